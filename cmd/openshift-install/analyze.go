@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/analyze"
+)
+
+var (
+	analyzeOpts struct {
+		logBundle string
+	}
+)
+
+func newAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Find the probable root causes of a failed install",
+		Long: `Analyze the installer log of the assets directory and the log bundle
+gathered from the bootstrap host, and print the probable root causes of
+the failed install with remediation hints.
+
+When --log-bundle is not set, the latest log bundle of the assets
+directory is used.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runAnalyzeCmd(rootOpts.dir, analyzeOpts.logBundle); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&analyzeOpts.logBundle, "log-bundle", "", "log bundle gathered from the bootstrap host (defaults to the latest log-bundle-*.tar.gz of the assets directory)")
+	return cmd
+}
+
+func runAnalyzeCmd(directory string, logBundle string) error {
+	findings, err := analyzeFailure(directory, logBundle)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		logrus.Info("No known cause of failure was found")
+		return nil
+	}
+	for i, finding := range findings {
+		fmt.Printf("Probable cause %d: %s\n", i+1, finding)
+	}
+	return nil
+}
+
+// analyzeFailure returns the probable root causes of the failed install found in the installer
+// log of the directory and the log bundle, the latest of the directory when empty.
+func analyzeFailure(directory string, logBundle string) ([]analyze.Finding, error) {
+	analyzer := analyze.NewAnalyzer()
+	if err := analyzer.AddDir(directory); err != nil {
+		return nil, errors.Wrap(err, "failed to read the installer log")
+	}
+
+	if logBundle == "" {
+		var err error
+		if logBundle, err = latestLogBundle(directory); err != nil {
+			return nil, err
+		}
+	}
+	if logBundle != "" {
+		logrus.Infof("Analyzing the log bundle %s", logBundle)
+		if err := analyzer.AddLogBundle(logBundle); err != nil {
+			return nil, err
+		}
+	}
+	return analyzer.Analyze(), nil
+}
+
+// latestLogBundle returns the most recent log bundle of the directory, empty if there is none.
+func latestLogBundle(directory string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(directory, "log-bundle-*.tar.gz"))
+	if err != nil {
+		return "", err
+	}
+	latest := ""
+	var latestInfo os.FileInfo
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return "", err
+		}
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest, latestInfo = match, info
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+
+	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+)
+
+var (
+	bootstrapRecoveryOpts struct {
+		autoGather bool
+	}
+)
+
+const (
+	// maxSuspectedErrors is the number of probable causes printed when bootstrapping fails.
+	maxSuspectedErrors = 3
+
+	retainBootstrap  = "Keep the bootstrap resources to troubleshoot the bootstrap host"
+	destroyBootstrap = "Destroy the bootstrap resources"
+)
+
+func addBootstrapRecoveryFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&bootstrapRecoveryOpts.autoGather, "auto-gather", false, "when bootstrapping fails, gather the logs of the bootstrap host and print the probable causes of the failure without prompting, keeping the bootstrap resources")
+}
+
+// recoverBootstrapFailure helps recovering from a failed bootstrap. With --auto-gather, the logs
+// of the bootstrap host are gathered and the probable causes of the failure printed. From a
+// terminal, the user is prompted to do so, then whether to keep or destroy the bootstrap
+// resources. Otherwise, only the commands gathering the logs are printed.
+func recoverBootstrapFailure(directory string) {
+	interactive := !bootstrapRecoveryOpts.autoGather && terminal.IsTerminal(int(os.Stdin.Fd()))
+	recoverBootstrap(directory, bootstrapRecoveryOpts.autoGather, interactive, runGatherBootstrapCmd)
+}
+
+// recoverBootstrap helps recovering from a failed bootstrap, gathering the logs of the directory
+// with gather, prompting the user when interactive.
+func recoverBootstrap(directory string, autoGather bool, interactive bool, gather func(directory string) error) {
+	if !autoGather && !interactive {
+		logrus.Info("Use the following commands to gather logs from the cluster")
+		logrus.Info("openshift-install gather bootstrap --help")
+		return
+	}
+
+	gatherLogs := true
+	if interactive {
+		if err := survey.AskOne(&survey.Confirm{
+			Message: "Gather the logs of the bootstrap host and analyze them?",
+			Help:    "The logs of the bootstrap host and of the control plane hosts are pulled over SSH into a log bundle of the assets directory, then searched for the known causes of bootstrap failures.",
+			Default: true,
+		}, &gatherLogs, nil); err != nil {
+			logrus.Error("Failed to prompt for the log gathering: ", err)
+			return
+		}
+	}
+	if gatherLogs {
+		if err := gather(directory); err != nil {
+			logrus.Error("Attempted to gather debug logs after bootstrap failure: ", err)
+		} else {
+			printSuspectedErrors(directory)
+		}
+	}
+	if !interactive {
+		return
+	}
+
+	var choice string
+	if err := survey.AskOne(&survey.Select{
+		Message: "Bootstrap resources",
+		Help:    "The bootstrap resources are needed to troubleshoot the bootstrap host, e.g. with SSH. They can be destroyed later with 'openshift-install destroy bootstrap'.",
+		Options: []string{retainBootstrap, destroyBootstrap},
+		Default: retainBootstrap,
+	}, &choice, nil); err != nil {
+		logrus.Error("Failed to prompt for the bootstrap resources: ", err)
+		return
+	}
+	if choice == destroyBootstrap {
+		logrus.Info("Destroying the bootstrap resources...")
+		if err := destroybootstrap.Destroy(directory); err != nil {
+			logrus.Error(errors.Wrap(err, "failed to destroy the bootstrap resources"))
+		}
+		return
+	}
+	logrus.Info("Keeping the bootstrap resources, destroy them with 'openshift-install destroy bootstrap'")
+}
+
+// printSuspectedErrors prints the first probable causes of the failure found in the logs of the
+// directory.
+func printSuspectedErrors(directory string) {
+	findings, err := analyzeFailure(directory, "")
+	if err != nil {
+		logrus.Error("Failed to analyze the logs: ", err)
+		return
+	}
+	if len(findings) == 0 {
+		logrus.Info("No known cause of failure was found")
+		return
+	}
+	if len(findings) > maxSuspectedErrors {
+		logrus.Infof("Showing %d of the %d probable causes found, see 'openshift-install analyze' for all of them", maxSuspectedErrors, len(findings))
+		findings = findings[:maxSuspectedErrors]
+	}
+	for i, finding := range findings {
+		fmt.Printf("Probable cause %d: %s\n", i+1, finding)
+	}
+}
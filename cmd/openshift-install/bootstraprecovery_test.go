@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverBootstrapNonInteractive(t *testing.T) {
+	cases := []struct {
+		name            string
+		autoGather      bool
+		gatherErr       error
+		expectedGathers int
+		expectedLog     string
+		analyzed        bool
+	}{
+		{
+			name:        "without auto-gather",
+			expectedLog: "openshift-install gather bootstrap --help",
+		},
+		{
+			name:            "auto-gather",
+			autoGather:      true,
+			expectedGathers: 1,
+			expectedLog:     "No known cause of failure was found",
+			analyzed:        true,
+		},
+		{
+			name:            "auto-gather failing",
+			autoGather:      true,
+			gatherErr:       fmt.Errorf("failed to connect to the bootstrap host"),
+			expectedGathers: 1,
+			expectedLog:     "Attempted to gather debug logs after bootstrap failure: failed to connect to the bootstrap host",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "bootstraprecovery")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer os.RemoveAll(dir)
+			var log bytes.Buffer
+			logrus.SetOutput(&log)
+			defer logrus.SetOutput(os.Stderr)
+
+			var gathered []string
+			// Without a terminal, the user is never prompted
+			recoverBootstrap(dir, tc.autoGather, false, func(directory string) error {
+				gathered = append(gathered, directory)
+				return tc.gatherErr
+			})
+			assert.Len(t, gathered, tc.expectedGathers)
+			for _, directory := range gathered {
+				assert.Equal(t, dir, directory)
+			}
+			assert.Contains(t, log.String(), tc.expectedLog)
+			// The logs are only analyzed once they were gathered
+			assert.Equal(t, tc.analyzed, bytes.Contains(log.Bytes(), []byte("No known cause of failure was found")))
+			// The bootstrap resources are kept
+			assert.NotContains(t, log.String(), "Destroying the bootstrap resources")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	"github.com/openshift/installer/pkg/asset/password"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/terraform"
+)
+
+var (
+	cleanOpts struct {
+		keepCredentials bool
+	}
+)
+
+func newCleanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove the generated assets from the assets directory",
+		Long: `Remove the manifests, the Ignition configs, the live ISOs and the Terraform
+variables generated in the assets directory, keeping the install-config and
+metadata.json, so that they are generated again by the next create command.
+
+With --keep-credentials, the admin kubeconfig and the kubeadmin password are
+kept too, along with the certificates and the keys they are issued from.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			lockAssetDir(rootOpts.dir)
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runCleanCmd(rootOpts.dir, cleanOpts.keepCredentials); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&cleanOpts.keepCredentials, "keep-credentials", false, "keep the admin kubeconfig and the kubeadmin password")
+	return cmd
+}
+
+func runCleanCmd(directory string, keepCredentials bool) error {
+	if _, err := os.Stat(filepath.Join(directory, terraform.StateFileName)); err == nil {
+		return errors.Errorf("%q exists, the cluster has been created: destroy it first", terraform.StateFileName)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// Metadata depends on the bootstrap Ignition config only to be generated after it, it is
+	// kept alone with the cluster ID it records. The credentials are kept with the certificates
+	// and the keys they are issued from, for the kubeconfig to stay valid.
+	kept := []asset.Asset{&installconfig.InstallConfig{}, &installconfig.ClusterID{}}
+	if keepCredentials {
+		kept = append(kept, &kubeconfig.AdminClient{}, &password.KubeadminPassword{})
+	}
+	preserved := map[reflect.Type]bool{reflect.TypeOf(&cluster.Metadata{}): true}
+	for _, a := range kept {
+		walkDependencies(a, preserved)
+	}
+
+	generated := map[reflect.Type]bool{}
+	for _, t := range targets {
+		for _, a := range t.assets {
+			walkDependencies(a, generated)
+		}
+	}
+
+	store, err := assetstore.NewStore(directory, assetStoreOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	removed := 0
+	for t := range generated {
+		if preserved[t] {
+			continue
+		}
+		a := reflect.New(t.Elem()).Interface().(asset.Asset)
+		if err := store.Destroy(a); err != nil {
+			return errors.Wrapf(err, "failed to remove asset %q", a.Name())
+		}
+		removed++
+	}
+
+	// The live ISOs embed the Ignition configs, see create iso
+	for _, role := range []string{"bootstrap", "master", "worker"} {
+		if err := os.Remove(filepath.Join(directory, role+".iso")); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove the %s live ISO", role)
+		}
+	}
+
+	logrus.Infof("Removed %d generated assets, kept %d", removed, len(preserved))
+	return nil
+}
+
+// walkDependencies adds the type of the asset and of all the assets it depends on, directly or
+// not, to seen.
+func walkDependencies(a asset.Asset, seen map[reflect.Type]bool) {
+	t := reflect.TypeOf(a)
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	for _, dep := range a.Dependencies() {
+		walkDependencies(dep, seen)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCleanCmd(t *testing.T) {
+	// The assets of the state file, with the files they wrote
+	state := map[string]string{
+		"*installconfig.ClusterID":                 "",
+		"*cluster.Metadata":                        "metadata.json",
+		"*kubeconfig.AdminClient":                  "auth/kubeconfig",
+		"*password.KubeadminPassword":              "auth/kubeadmin-password",
+		"*tls.AdminKubeConfigClientCertKey":        "",
+		"*bootstrap.Bootstrap":                     "bootstrap.ign",
+		"*machine.Master":                          "master.ign",
+		"*machine.Worker":                          "worker.ign",
+		"*kubeconfig.AdminInternalClient":          "",
+		"*tls.KubeAPIServerLocalhostServerCertKey": "",
+	}
+	cases := []struct {
+		name            string
+		keepCredentials bool
+		expectedState   []string
+		expectedFiles   []string
+	}{
+		{
+			name:          "without the credentials",
+			expectedState: []string{"*cluster.Metadata", "*installconfig.ClusterID"},
+			expectedFiles: []string{"metadata.json"},
+		},
+		{
+			name:            "with the credentials",
+			keepCredentials: true,
+			expectedState: []string{
+				"*cluster.Metadata",
+				"*installconfig.ClusterID",
+				"*kubeconfig.AdminClient",
+				"*password.KubeadminPassword",
+				"*tls.AdminKubeConfigClientCertKey",
+			},
+			expectedFiles: []string{"auth/kubeadmin-password", "auth/kubeconfig", "metadata.json"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "TestRunCleanCmd")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			stateFile := map[string]interface{}{}
+			for key, filename := range state {
+				if filename == "" {
+					stateFile[key] = map[string]interface{}{}
+					continue
+				}
+				stateFile[key] = map[string]interface{}{"File": map[string]interface{}{"Filename": filename, "Data": []byte(key)}}
+				path := filepath.Join(dir, filename)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(path, []byte(key), 0640); err != nil {
+					t.Fatal(err)
+				}
+			}
+			data, err := json.Marshal(stateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, ".openshift_install_state.json"), data, 0640); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, "master.iso"), nil, 0640); err != nil {
+				t.Fatal(err)
+			}
+
+			if !assert.NoError(t, runCleanCmd(dir, tc.keepCredentials)) {
+				return
+			}
+
+			data, err = ioutil.ReadFile(filepath.Join(dir, ".openshift_install_state.json"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			var cleaned map[string]json.RawMessage
+			if !assert.NoError(t, json.Unmarshal(data, &cleaned)) {
+				return
+			}
+			var keys []string
+			for key := range cleaned {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			assert.Equal(t, tc.expectedState, keys)
+
+			var files []string
+			err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() || info.Name() == ".openshift_install_state.json" {
+					return err
+				}
+				rel, err := filepath.Rel(dir, path)
+				files = append(files, filepath.ToSlash(rel))
+				return err
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFiles, files)
+		})
+	}
+}
+
+func TestRunCleanCmdCreatedCluster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestRunCleanCmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "terraform.tfstate"), nil, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.EqualError(t, runCleanCmd(dir, false), `"terraform.tfstate" exists, the cluster has been created: destroy it first`)
+}
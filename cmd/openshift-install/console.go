@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+)
+
+// consoleEscape is the byte disconnecting from the serial console, ^] as in virtctl console.
+const consoleEscape = 0x1d
+
+func newConsoleCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "console MACHINE",
+		Short: "Open the serial console of a machine of the cluster",
+		Long: `Connect the terminal to the serial console of a machine of the cluster, as
+'virtctl console' does, using the infra cluster of the metadata of the
+assets directory. The machine is the name of its VM, which is also the name
+of its node, e.g. the bootstrap machine or a control plane machine failing
+to join the cluster before it can be reached with SSH.
+
+Press ^] to disconnect from the console. Only the KubeVirt platform is
+supported.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runConsoleCmd(rootOpts.dir, args[0]); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+}
+
+func runConsoleCmd(directory string, machine string) error {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	if metadata.Kubevirt == nil {
+		return errors.New("the serial console is only supported on the KubeVirt platform")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	namespace := metadata.Kubevirt.Namespace
+	client, err := findVirtualMachineInstance(ctx, namespace, machine, func() (ickubevirt.Client, error) {
+		return ickubevirt.NewClientForMetadata(metadata.Kubevirt)
+	})
+	for _, infraCluster := range metadata.Kubevirt.InfraClusters {
+		if err != nil || client != nil {
+			break
+		}
+		infraCluster := infraCluster
+		namespace = infraCluster.Namespace
+		client, err = findVirtualMachineInstance(ctx, namespace, machine, func() (ickubevirt.Client, error) {
+			return ickubevirt.NewClientForInfraCluster(infraCluster)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return errors.Errorf("no running VM %s in the namespaces of the cluster", machine)
+	}
+
+	console, err := client.SerialConsole(context.Background(), namespace, machine)
+	if err != nil {
+		return err
+	}
+	defer console.Close()
+	return attachConsole(console, machine)
+}
+
+// findVirtualMachineInstance looks the VMI of the machine up in the namespace of an infra
+// cluster, returning the client of the infra cluster, or a nil client when it is not there. It
+// fails when the VMI is found but not running.
+func findVirtualMachineInstance(ctx context.Context, namespace string, machine string, newClient func() (ickubevirt.Client, error)) (ickubevirt.Client, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the infra cluster client")
+	}
+	vmis, err := client.ListVirtualMachineInstances(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the VMIs in namespace %s", namespace)
+	}
+	names := make([]string, 0, len(vmis))
+	for _, vmi := range vmis {
+		names = append(names, vmi.GetName())
+		if vmi.GetName() != machine {
+			continue
+		}
+		phase, _, _ := unstructured.NestedString(vmi.Object, "status", "phase")
+		if phase != "Running" {
+			return nil, errors.Errorf("VMI %s in namespace %s is %s, its serial console is only available while it is running", machine, namespace, phase)
+		}
+		return client, nil
+	}
+	sort.Strings(names)
+	logrus.Debugf("The VMIs in namespace %s are: %s", namespace, strings.Join(names, ", "))
+	return nil, nil
+}
+
+// attachConsole copies the serial console to the terminal, in raw mode when it is one, until
+// the console is closed or the escape byte is typed.
+func attachConsole(console io.ReadWriter, machine string) error {
+	stdin := int(os.Stdin.Fd())
+	if terminal.IsTerminal(stdin) {
+		state, err := terminal.MakeRaw(stdin)
+		if err != nil {
+			return errors.Wrap(err, "failed to set the terminal in raw mode")
+		}
+		defer terminal.Restore(stdin, state)
+	}
+	fmt.Fprintf(os.Stderr, "Connected to the serial console of %s, press ^] to disconnect\r\n", machine)
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(os.Stdout, console)
+		done <- err
+	}()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				input := buf[:n]
+				escaped := false
+				if i := bytes.IndexByte(input, consoleEscape); i >= 0 {
+					input, escaped = input[:i], true
+				}
+				if len(input) > 0 {
+					if _, err := console.Write(input); err != nil {
+						done <- err
+						return
+					}
+				}
+				if escaped {
+					done <- nil
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				done <- err
+				return
+			}
+		}
+	}()
+
+	err := <-done
+	fmt.Fprint(os.Stderr, "\r\n")
+	if err != nil {
+		return errors.Wrap(err, "the serial console was disconnected")
+	}
+	return nil
+}
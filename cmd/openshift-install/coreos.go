@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/types"
+)
+
+var (
+	coreosStreamOpts struct {
+		platform string
+		arch     string
+	}
+)
+
+func newCoreOSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coreos",
+		Short: "Commands for operating on CoreOS boot images",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCoreOSPrintStreamJSONCmd())
+	cmd.AddCommand(newCoreOSDownloadCmd())
+	return cmd
+}
+
+func newCoreOSPrintStreamJSONCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print-stream-json",
+		Short: "Outputs the CoreOS stream metadata for the bootimages",
+		Long: `Outputs the RHCOS boot images embedded in the installer in the CoreOS stream
+metadata format. Use --platform and --arch to only print the images the
+installer would use for that platform and architecture.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stream, err := rhcos.FilteredStream(context.TODO(), coreosStreamOpts.platform, types.Architecture(coreosStreamOpts.arch))
+			if err != nil {
+				return err
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "    ")
+			return encoder.Encode(stream)
+		},
+	}
+	cmd.Flags().StringVar(&coreosStreamOpts.platform, "platform", "", fmt.Sprintf("only print the images used for this platform (e.g. %q)", strings.Join(rhcos.StreamPlatforms(), " | ")))
+	cmd.Flags().StringVar(&coreosStreamOpts.arch, "arch", "", fmt.Sprintf("only print the images for this architecture (e.g. %q)", strings.Join(rhcos.StreamArchitectures(), " | ")))
+	return cmd
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/rhcos/cache"
+	"github.com/openshift/installer/pkg/rhcos/containerdisk"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	diskFormatAuto  = "auto"
+	diskFormatRaw   = "raw"
+	diskFormatQCOW2 = "qcow2"
+)
+
+// blockProvisioners are the provisioners of storage classes backed by block devices, to which
+// CDI imports raw images without converting them.
+var blockProvisioners = map[string]bool{
+	"kubernetes.io/rbd":                  true,
+	"rbd.csi.ceph.com":                   true,
+	"openshift-storage.rbd.csi.ceph.com": true,
+	"kubernetes.io/aws-ebs":              true,
+	"ebs.csi.aws.com":                    true,
+	"topolvm.cybozu.com":                 true,
+}
+
+var (
+	coreosDownloadOpts struct {
+		platform       string
+		arch           string
+		format         string
+		storageClass   string
+		kubeconfigPath string
+		context        string
+		caBundle       string
+		outputDir      string
+		containerDisk  bool
+	}
+)
+
+func newCoreOSDownloadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Downloads the CoreOS boot image of a platform, ready to be imported",
+		Long: `Downloads the RHCOS boot image the installer uses for --platform, verifies
+its checksum and writes it to --output-dir, with a .sha256 file holding the
+checksum of the written image.
+
+For the kubevirt platform, the image is written in the format CDI imports
+best to the storage class of the cluster: raw for the storage classes
+backed by block devices, qcow2 otherwise. Use --storage-class to pick the
+format from the provisioner of a storage class of the infra cluster, or
+--format to set it. Writing a raw image needs qemu-img.
+
+With --containerdisk, the image is also packaged as a containerDisk, for
+the clusters which cannot download it, written as an OCI image layout
+directory next to the image. Push it to a registry with skopeo or podman,
+e.g.:
+
+  skopeo copy oci:<layout directory>:<release> docker://quay.io/example/rhcos:<release>`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runCoreOSDownloadCmd(); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&coreosDownloadOpts.platform, "platform", kubevirt.Name, fmt.Sprintf("platform of the image (%q)", kubevirt.Name))
+	cmd.Flags().StringVar(&coreosDownloadOpts.arch, "arch", string(types.ArchitectureAMD64), "architecture of the image")
+	cmd.Flags().StringVar(&coreosDownloadOpts.format, "format", diskFormatAuto, fmt.Sprintf("format of the written image (%q, %q or %q)", diskFormatAuto, diskFormatRaw, diskFormatQCOW2))
+	cmd.Flags().StringVar(&coreosDownloadOpts.storageClass, "storage-class", "", "storage class of the infra cluster the image is imported to, picking the format with --format=auto")
+	cmd.Flags().StringVar(&coreosDownloadOpts.kubeconfigPath, "kubeconfig", "", "kubeconfig of the infra cluster (defaults to KUBECONFIG, or ~/.kube/config)")
+	cmd.Flags().StringVar(&coreosDownloadOpts.context, "context", "", "context of the kubeconfig (defaults to its current-context)")
+	cmd.Flags().StringVar(&coreosDownloadOpts.caBundle, "ca-bundle", "", "file of an additional PEM CA bundle trusted when connecting to the infra cluster")
+	cmd.Flags().StringVar(&coreosDownloadOpts.outputDir, "output-dir", ".", "directory the image is written to")
+	cmd.Flags().BoolVar(&coreosDownloadOpts.containerDisk, "containerdisk", false, "also write the image as a containerDisk, in an OCI image layout directory")
+	return cmd
+}
+
+func runCoreOSDownloadCmd() error {
+	if coreosDownloadOpts.platform != kubevirt.Name {
+		return errors.Errorf("unsupported platform %q, only %q images can be downloaded", coreosDownloadOpts.platform, kubevirt.Name)
+	}
+	arch := types.Architecture(coreosDownloadOpts.arch)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Minute)
+	defer cancel()
+	clientBuilder := ickubevirt.ClientBuilder(coreosDownloadOpts.kubeconfigPath, coreosDownloadOpts.context, coreosDownloadOpts.caBundle)
+	format, err := coreosDownloadFormat(ctx, coreosDownloadOpts.format, coreosDownloadOpts.storageClass, clientBuilder)
+	if err != nil {
+		return err
+	}
+
+	stream, err := rhcos.FilteredStream(ctx, coreosDownloadOpts.platform, arch)
+	if err != nil {
+		return err
+	}
+	release, artifact := coreosDownloadArtifact(stream)
+	if artifact == nil {
+		return errors.Errorf("no %s image was found for %s", coreosDownloadOpts.platform, arch)
+	}
+
+	// The cache decompresses the image and verifies its checksum
+	cached, err := cache.DownloadImageFile(fmt.Sprintf("%s?sha256=%s", artifact.Location, artifact.UncompressedSHA256))
+	if err != nil {
+		return errors.Wrap(err, "failed to download the image")
+	}
+
+	if err := os.MkdirAll(coreosDownloadOpts.outputDir, 0755); err != nil {
+		return err
+	}
+	output := filepath.Join(coreosDownloadOpts.outputDir, fmt.Sprintf("rhcos-%s-%s-%s.%s", release, coreosDownloadOpts.platform, arch, format))
+	if err := writeDiskImage(context.TODO(), cached, output, format); err != nil {
+		return errors.Wrapf(err, "failed to write %s", output)
+	}
+	checksum, err := writeChecksumFile(output)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the checksum of the image")
+	}
+	logrus.Infof("Wrote %s image %s (sha256 %s)", format, output, checksum)
+
+	if !coreosDownloadOpts.containerDisk {
+		return nil
+	}
+	layoutDir := output + ".oci"
+	if err := os.RemoveAll(layoutDir); err != nil {
+		return err
+	}
+	if _, err := containerdisk.Build(output, layoutDir, string(arch), release); err != nil {
+		return errors.Wrap(err, "failed to build the containerDisk")
+	}
+	logrus.Infof("Wrote the containerDisk %s, push it with: skopeo copy oci:%s:%s docker://<registry>/<repository>:%s", layoutDir, layoutDir, release, release)
+	return nil
+}
+
+// coreosDownloadArtifact returns the release and the compressed QCOW2 disk of the stream filtered
+// for the kubevirt platform and an architecture, which is the OpenStack image, or nil when the
+// stream has none.
+func coreosDownloadArtifact(stream *rhcos.Stream) (string, *rhcos.StreamArtifact) {
+	for _, streamArch := range stream.Architectures {
+		artifacts, ok := streamArch.Artifacts["openstack"]
+		if !ok {
+			continue
+		}
+		if artifact := artifacts.Formats["qcow2.gz"]["disk"]; artifact != nil {
+			return artifacts.Release, artifact
+		}
+	}
+	return "", nil
+}
+
+// coreosDownloadFormat returns the format the image is written in, picked with the auto format
+// from the provisioner of the storage class, in the infra cluster of the client builder.
+func coreosDownloadFormat(ctx context.Context, format string, storageClassName string, clientBuilder ickubevirt.ClientBuilderFuncType) (string, error) {
+	switch format {
+	case diskFormatRaw, diskFormatQCOW2:
+		return format, nil
+	case diskFormatAuto:
+	default:
+		return "", errors.Errorf("unsupported format %q, must be one of %s, %s or %s", format, diskFormatAuto, diskFormatRaw, diskFormatQCOW2)
+	}
+	if storageClassName == "" {
+		return diskFormatQCOW2, nil
+	}
+
+	client, err := clientBuilder()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the infra cluster client")
+	}
+	storageClass, err := client.GetStorageClass(ctx, storageClassName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the storage class %s", storageClassName)
+	}
+	if blockProvisioners[storageClass.Provisioner] {
+		logrus.Infof("The storage class %s is backed by block devices, writing a raw image", storageClass.Name)
+		return diskFormatRaw, nil
+	}
+	return diskFormatQCOW2, nil
+}
+
+// writeDiskImage writes the QCOW2 image src to dst in the format, converting it with qemu-img
+// to write a raw image.
+func writeDiskImage(ctx context.Context, src string, dst string, format string) error {
+	if format == diskFormatRaw {
+		out, err := exec.CommandContext(ctx, "qemu-img", "convert", "-f", diskFormatQCOW2, "-O", diskFormatRaw, src, dst).CombinedOutput()
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert the image with qemu-img: %s", out)
+		}
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// writeChecksumFile writes the sha256 checksum of the file to a .sha256 file next to it, in the
+// format of sha256sum, and returns it.
+func writeChecksumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	checksum := fmt.Sprintf("%x", hash.Sum(nil))
+	line := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(path))
+	return checksum, ioutil.WriteFile(path+".sha256", []byte(line), 0644)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/rhcos"
+)
+
+func TestCoreOSDownloadArtifact(t *testing.T) {
+	disk := &rhcos.StreamArtifact{
+		Location:           "https://example.com/rhcos-openstack.x86_64.qcow2.gz",
+		SHA256:             "sha256",
+		UncompressedSHA256: "uncompressed-sha256",
+	}
+	cases := []struct {
+		name     string
+		stream   *rhcos.Stream
+		release  string
+		artifact *rhcos.StreamArtifact
+	}{
+		{
+			name: "openstack qcow2",
+			stream: &rhcos.Stream{Architectures: map[string]*rhcos.StreamArchitecture{
+				"x86_64": {Artifacts: map[string]*rhcos.StreamPlatformArtifacts{
+					"openstack": {
+						Release: "47.82.202010211043-0",
+						Formats: map[string]map[string]*rhcos.StreamArtifact{"qcow2.gz": {"disk": disk}},
+					},
+				}},
+			}},
+			release:  "47.82.202010211043-0",
+			artifact: disk,
+		},
+		{
+			name: "no openstack artifacts",
+			stream: &rhcos.Stream{Architectures: map[string]*rhcos.StreamArchitecture{
+				"x86_64": {Artifacts: map[string]*rhcos.StreamPlatformArtifacts{
+					"qemu": {
+						Release: "47.82.202010211043-0",
+						Formats: map[string]map[string]*rhcos.StreamArtifact{"qcow2.gz": {"disk": disk}},
+					},
+				}},
+			}},
+		},
+		{
+			name: "no qcow2 disk",
+			stream: &rhcos.Stream{Architectures: map[string]*rhcos.StreamArchitecture{
+				"x86_64": {Artifacts: map[string]*rhcos.StreamPlatformArtifacts{
+					"openstack": {
+						Release: "47.82.202010211043-0",
+						Formats: map[string]map[string]*rhcos.StreamArtifact{"raw.gz": {"disk": disk}},
+					},
+				}},
+			}},
+		},
+		{
+			name:   "no architectures",
+			stream: &rhcos.Stream{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			release, artifact := coreosDownloadArtifact(tc.stream)
+			assert.Equal(t, tc.release, release)
+			assert.Equal(t, tc.artifact, artifact)
+		})
+	}
+}
+
+func TestCoreOSDownloadFormat(t *testing.T) {
+	cases := []struct {
+		name          string
+		format        string
+		storageClass  string
+		provisioner   string
+		getErr        error
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "raw",
+			format:   "raw",
+			expected: "raw",
+		},
+		{
+			name:         "qcow2 with a block storage class",
+			format:       "qcow2",
+			storageClass: "ceph-rbd",
+			expected:     "qcow2",
+		},
+		{
+			name:     "auto without storage class",
+			format:   "auto",
+			expected: "qcow2",
+		},
+		{
+			name:         "auto with a block storage class",
+			format:       "auto",
+			storageClass: "ceph-rbd",
+			provisioner:  "openshift-storage.rbd.csi.ceph.com",
+			expected:     "raw",
+		},
+		{
+			name:         "auto with a filesystem storage class",
+			format:       "auto",
+			storageClass: "nfs",
+			provisioner:  "nfs.csi.k8s.io",
+			expected:     "qcow2",
+		},
+		{
+			name:          "auto with a missing storage class",
+			format:        "auto",
+			storageClass:  "ceph-rbd",
+			getErr:        fmt.Errorf("not found"),
+			expectedError: "failed to get the storage class ceph-rbd: not found",
+		},
+		{
+			name:          "unsupported format",
+			format:        "vmdk",
+			expectedError: `unsupported format "vmdk", must be one of auto, raw or qcow2`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			// The infra cluster is only queried for the auto format with a storage class
+			client := mock.NewMockClient(mockCtrl)
+			if tc.provisioner != "" || tc.getErr != nil {
+				storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: tc.storageClass}, Provisioner: tc.provisioner}
+				if tc.getErr != nil {
+					storageClass = nil
+				}
+				client.EXPECT().GetStorageClass(gomock.Any(), tc.storageClass).Return(storageClass, tc.getErr)
+			}
+			clientBuilder := func() (ickubevirt.Client, error) { return client, nil }
+
+			format, err := coreosDownloadFormat(context.TODO(), tc.format, tc.storageClass, clientBuilder)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+}
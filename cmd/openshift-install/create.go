@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"crypto/x509"
-	"io/ioutil"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -26,13 +30,23 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/installer/pkg/apicheck"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	"github.com/openshift/installer/pkg/asset/logging"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	targetassets "github.com/openshift/installer/pkg/asset/targets"
 	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/diskcheck"
+	"github.com/openshift/installer/pkg/hooks"
+	"github.com/openshift/installer/pkg/metrics/progress"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/metrics/tracing"
+	"github.com/openshift/installer/pkg/postinstall"
+	"github.com/openshift/installer/pkg/secretstore"
+	"github.com/openshift/installer/pkg/timeouts"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"github.com/openshift/library-go/pkg/route/routeapihelpers"
@@ -42,8 +56,16 @@ type target struct {
 	name    string
 	command *cobra.Command
 	assets  []asset.WritableAsset
+	// files are the files written by the command besides the files of its assets.
+	files []string
 }
 
+var (
+	dnsCheckOpts struct {
+		skip bool
+	}
+)
+
 // each target is a variable to preserve the order when creating subcommands and still
 // allow other functions to directly access each target individually.
 var (
@@ -80,6 +102,30 @@ var (
 		assets: targetassets.IgnitionConfigs,
 	}
 
+	isoTarget = target{
+		name: "ISO",
+		command: &cobra.Command{
+			Use:   "iso",
+			Short: "Generates the Ignition configs and the live ISOs installing the machines with them",
+			Long: `Generates the Ignition configs, and the bootstrap.iso, master.iso and worker.iso
+RHCOS live ISOs installing RHCOS with them to the boot disk of the machines.
+
+The ISOs are for installs where the installer cannot reach the infra cluster: they
+are uploaded to DataVolumes attached as cdroms to the VMs, which boot from them
+until RHCOS is installed to their disks. Only the kubevirt platform is supported.`,
+			PostRun: func(_ *cobra.Command, _ []string) {
+				cleanup := setupFileHook(rootOpts.dir)
+				defer cleanup()
+
+				if err := createISOs(rootOpts.dir); err != nil {
+					logrus.Fatal(err)
+				}
+			},
+		},
+		assets: targetassets.IgnitionConfigs,
+		files:  []string{"bootstrap.iso", "master.iso", "worker.iso"},
+	}
+
 	clusterTarget = target{
 		name: "Cluster",
 		command: &cobra.Command{
@@ -88,20 +134,28 @@ var (
 			// FIXME: add longer descriptions for our commands with examples for better UX.
 			// Long:  "",
 			PostRun: func(_ *cobra.Command, _ []string) {
+				if dryRunOpts.enabled {
+					return
+				}
 				ctx := context.Background()
 
 				cleanup := setupFileHook(rootOpts.dir)
 				defer cleanup()
 
+				stopHeartbeat := startHeartbeat(rootOpts.dir)
+				defer stopHeartbeat()
+
 				// FIXME: pulling the kubeconfig and metadata out of the root
 				// directory is a bit cludgy when we already have them in memory.
-				config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+				config, err := loadKubeconfig(rootOpts.dir)
 				if err != nil {
 					logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 				}
 
 				timer.StartTimer("Bootstrap Complete")
-				err = waitForBootstrapComplete(ctx, config)
+				err = runStage(ctx, rootOpts.dir, hooks.StageBootstrap, func() error {
+					return waitForBootstrapComplete(ctx, config)
+				})
 				if err != nil {
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
@@ -112,6 +166,7 @@ var (
 					logrus.Fatal("Bootstrap failed to complete: ", err)
 				}
 				timer.StopTimer("Bootstrap Complete")
+				recordMilestone(rootOpts.dir, ickubevirt.MilestoneBootstrapComplete, "The bootstrap control plane handed over to the masters")
 				timer.StartTimer("Bootstrap Destroy")
 
 				if oi, ok := os.LookupEnv("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP"); ok && oi != "" {
@@ -126,7 +181,9 @@ var (
 				}
 				timer.StopTimer("Bootstrap Destroy")
 
-				err = waitForInstallComplete(ctx, config, rootOpts.dir)
+				err = runStage(ctx, rootOpts.dir, hooks.StageInstall, func() error {
+					return waitForInstallComplete(ctx, config, rootOpts.dir)
+				})
 				if err != nil {
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
@@ -134,6 +191,11 @@ var (
 					logTroubleshootingLink()
 					logrus.Fatal(err)
 				}
+				recordMilestone(rootOpts.dir, ickubevirt.MilestoneInstallComplete, "The cluster is installed")
+				// The compute machines are created during the installation
+				if err := recordMachinePlacements(rootOpts.dir); err != nil {
+					logrus.Warn("Failed to record the placements of the machines: ", err)
+				}
 				timer.StopTimer(timer.TotalTimeElapsed)
 				timer.LogSummary()
 			},
@@ -141,7 +203,7 @@ var (
 		assets: targetassets.Cluster,
 	}
 
-	targets = []target{installConfigTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget}
+	targets = []target{installConfigTarget, manifestsTarget, ignitionConfigsTarget, isoTarget, clusterTarget}
 )
 
 func newCreateCmd() *cobra.Command {
@@ -158,19 +220,64 @@ func newCreateCmd() *cobra.Command {
 		t.command.Run = runTargetCmd(t.assets...)
 		cmd.AddCommand(t.command)
 	}
+	installConfigTarget.command.Flags().BoolVar(&platformChecksOpts.only, "platform-checks-only", false, "only run the live checks against the platform infrastructure of the existing install-config, without creating any asset")
+	installConfigTarget.command.Flags().StringVar(&platformChecksOpts.junitOutput, "junit-output", "", "path of the JUnit XML report of --platform-checks-only (defaults to junit_platform_checks.xml in the assets directory)")
+	installConfigTarget.command.Flags().BoolVar(&printDefaultedOpts.enabled, "print-defaulted", false, "print the install-config with all the defaults applied by the installer and the secrets redacted, once validated")
+	manifestsTarget.command.Flags().BoolVar(&manifestsDiffOpts.diff, "diff", false, "print the unified diff of the changes to the manifests already in the assets directory, without writing them")
+	addPostInstallChecksFlags(clusterTarget.command)
+	clusterTarget.command.Flags().BoolVar(&protectOpts.onCreate, "protect", false, "protect the cluster against deletion once its infrastructure is created, see the protect command")
+	clusterTarget.command.Flags().BoolVar(&dryRunOpts.enabled, "dry-run", false, "validate the install-config, generate the assets and print the resources which would be created in the infra cluster, without creating anything or changing the assets directory")
+	clusterTarget.command.Flags().StringVar(&dryRunOpts.output, "output", "yaml", "format of the provisioning plan printed by --dry-run, yaml or json")
+	clusterTarget.command.Flags().BoolVar(&dnsCheckOpts.skip, "skip-dns-check", false, "skip the check of the NS delegation of the base domain and of the DNS records of the cluster")
+	manifestsTarget.command.Flags().BoolVar(&manifestsDiffOpts.overwrite, "overwrite", false, "with --diff, also write the changes to the manifests")
+	isoTarget.command.Flags().StringVar(&isoOpts.baseISO, "base-iso", "", "path of the RHCOS live ISO to write the ISOs from (defaults to the live ISO of the RHCOS release, downloaded to the image cache)")
+	addReleaseImageFlags(cmd)
 
 	return cmd
 }
 
+var (
+	// assetDirSpace is the free space needed in the assets directory for the
+	// manifests, the ignition configs and the logs.
+	assetDirSpace = diskcheck.Space{Bytes: 64 << 20, Inodes: 1000}
+	// clusterAssetDirSpace is the free space needed in the assets directory to
+	// create the cluster, adding the Terraform state and variables.
+	clusterAssetDirSpace = diskcheck.Space{Bytes: 512 << 20, Inodes: 1000}
+)
+
+// targetsAssetDirSpace returns the free space needed in the assets directory
+// for the targets.
+func targetsAssetDirSpace(targets []asset.WritableAsset) diskcheck.Space {
+	for _, a := range targets {
+		if _, ok := a.(*cluster.Cluster); ok {
+			return clusterAssetDirSpace
+		}
+	}
+	return assetDirSpace
+}
+
+// targetStages are the stages of the installation run by the create subcommands, which hooks
+// can run around.
+var targetStages = map[string]hooks.Stage{
+	"manifests": hooks.StageManifests,
+	"cluster":   hooks.StageInfrastructure,
+}
+
 func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
-	runner := func(directory string) error {
-		assetStore, err := assetstore.NewStore(directory)
+	runner := func(directory string, targets []asset.WritableAsset) error {
+		if err := diskcheck.Check(directory, targetsAssetDirSpace(targets)); err != nil {
+			return err
+		}
+
+		assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...)
 		if err != nil {
 			return errors.Wrap(err, "failed to create asset store")
 		}
 
 		for _, a := range targets {
+			tracing.StartSpan(fmt.Sprintf("Fetch %s", a.Name()))
 			err := assetStore.Fetch(a, targets...)
+			tracing.EndSpan(fmt.Sprintf("Fetch %s", a.Name()), err)
 			if err != nil {
 				err = errors.Wrapf(err, "failed to fetch %s", a.Name())
 			}
@@ -188,22 +295,86 @@ func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args
 				return err
 			}
 		}
+		if err := storeCredentials(directory); err != nil {
+			return errors.Wrap(err, "failed to store credentials")
+		}
 		return nil
 	}
 
 	return func(cmd *cobra.Command, args []string) {
 		timer.StartTimer(timer.TotalTimeElapsed)
 
+		lockAssetDir(rootOpts.dir)
 		cleanup := setupFileHook(rootOpts.dir)
 		defer cleanup()
 
-		err := runner(rootOpts.dir)
+		if err := setReleaseImage(); err != nil {
+			logrus.Fatal(err)
+		}
+		installconfig.SetSkipDNSCheck(dnsCheckOpts.skip)
+
+		if platformChecksOpts.only {
+			if err := runPlatformChecks(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		}
+
+		if dryRunOpts.enabled {
+			if err := runDryRun(rootOpts.dir, targets); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		}
+
+		if manifestsDiffOpts.diff {
+			if err := printManifestsDiff(rootOpts.dir, targets); err != nil {
+				logrus.Fatal(err)
+			}
+			if !manifestsDiffOpts.overwrite {
+				return
+			}
+		}
+
+		if cmd.Name() == "cluster" && hooks.Configured(hooks.StageManifests) {
+			// The manifests are written for the manifests hooks to act on, and the
+			// cluster is then created from them
+			err := runStage(context.Background(), rootOpts.dir, hooks.StageManifests, func() error {
+				return runner(rootOpts.dir, targetassets.Manifests)
+			})
+			if err != nil {
+				logrus.Fatal(err)
+			}
+		}
+
+		run := func() error { return runner(rootOpts.dir, targets) }
+		var err error
+		if stage, ok := targetStages[cmd.Name()]; ok {
+			err = runStage(context.Background(), rootOpts.dir, stage, run)
+		} else {
+			err = run()
+		}
 		if err != nil {
 			logrus.Fatal(err)
 		}
+		if cmd.Name() == "cluster" {
+			if err := recordMachinePlacements(rootOpts.dir); err != nil {
+				logrus.Warn("Failed to record the placements of the machines: ", err)
+			}
+		}
+		if cmd.Name() == "cluster" && protectOpts.onCreate {
+			if err := setClusterProtection(rootOpts.dir, true); err != nil {
+				logrus.Fatal(err)
+			}
+		}
 		if cmd.Name() != "cluster" {
 			logrus.Infof(logging.LogCreatedFiles(cmd.Name(), rootOpts.dir, targets))
 		}
+		if printDefaultedOpts.enabled {
+			if err := printDefaultedInstallConfig(targets); err != nil {
+				logrus.Fatal(err)
+			}
+		}
 
 	}
 }
@@ -222,8 +393,15 @@ func addRouterCAToClusterCA(ctx context.Context, config *rest.Config, directory
 	}
 
 	routerCrtBytes := []byte(caConfigMap.Data["ca-bundle.crt"])
-	kubeconfig := filepath.Join(directory, "auth", "kubeconfig")
-	kconfig, err := clientcmd.LoadFromFile(kubeconfig)
+	store, err := newSecretStore(directory)
+	if err != nil {
+		return err
+	}
+	kubeconfigData, err := store.Read(secretstore.KubeconfigName)
+	if err != nil {
+		return errors.Wrap(err, "reading kubeconfig")
+	}
+	kconfig, err := clientcmd.Load(kubeconfigData)
 	if err != nil {
 		return errors.Wrap(err, "loading kubeconfig")
 	}
@@ -248,7 +426,11 @@ func addRouterCAToClusterCA(ctx context.Context, config *rest.Config, directory
 		newCA := append(routerCrtBytes, clusterCABytes...)
 		c.CertificateAuthorityData = newCA
 	}
-	if err := clientcmd.WriteToFile(*kconfig, kubeconfig); err != nil {
+	kubeconfigData, err = clientcmd.Write(*kconfig)
+	if err != nil {
+		return errors.Wrap(err, "serializing kubeconfig")
+	}
+	if err := store.Write(secretstore.KubeconfigName, kubeconfigData); err != nil {
 		return errors.Wrap(err, "writing kubeconfig")
 	}
 	return nil
@@ -283,6 +465,8 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config) (err err
 			cancel()
 		} else {
 			lastErr = err
+			progress.APIError("API")
+			progress.Retry("API")
 			silenceRemaining--
 			chunks := strings.Split(err.Error(), ":")
 			errorSuffix := chunks[len(chunks)-1]
@@ -311,7 +495,7 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config) (err err
 // and waits for the bootstrap configmap to report that bootstrapping has
 // completed.
 func waitForBootstrapConfigMap(ctx context.Context, client *kubernetes.Clientset) error {
-	timeout := 30 * time.Minute
+	timeout := timeouts.Get(timeouts.Bootstrap, stateInstallConfig(rootOpts.dir), 30*time.Minute)
 	logrus.Infof("Waiting up to %v for bootstrapping to complete...", timeout)
 
 	waitCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -353,13 +537,11 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	timeout := 40 * time.Minute
 
 	// Wait longer for baremetal, due to length of time it takes to boot
-	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
-		if installConfig, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && installConfig != nil {
-			if installConfig.(*installconfig.InstallConfig).Config.Platform.Name() == baremetal.Name {
-				timeout = 60 * time.Minute
-			}
-		}
+	installConfig := stateInstallConfig(rootOpts.dir)
+	if installConfig != nil && installConfig.Platform.Name() == baremetal.Name {
+		timeout = 60 * time.Minute
 	}
+	timeout = timeouts.Get(timeouts.InstallComplete, installConfig, timeout)
 
 	logrus.Infof("Waiting up to %v for the cluster at %s to initialize...", timeout, config.Host)
 	cc, err := configclient.NewForConfig(config)
@@ -441,6 +623,9 @@ func waitForConsole(ctx context.Context, config *rest.Config) (string, error) {
 	timer.StartTimer("Console")
 	wait.Until(func() {
 		route, err := rc.RouteV1().Routes(consoleNamespace).Get(ctx, consoleRouteName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			progress.APIError("Console")
+		}
 		if err == nil {
 			logrus.Debugf("Route found in openshift-console namespace: %s", consoleRouteName)
 			if uri, _, err2 := routeapihelpers.IngressURI(route, ""); err2 == nil {
@@ -452,6 +637,7 @@ func waitForConsole(ctx context.Context, config *rest.Config) (string, error) {
 			}
 		}
 		if err != nil {
+			progress.Retry("Console")
 			silenceRemaining--
 			if silenceRemaining == 0 {
 				logrus.Debugf("Still waiting for the console route: %v", err)
@@ -470,22 +656,37 @@ func waitForConsole(ctx context.Context, config *rest.Config) (string, error) {
 	return url, nil
 }
 
-// logComplete prints info upon completion
-func logComplete(directory, consoleURL string) error {
+// exportKubeconfigCommand returns the shell command setting KUBECONFIG to the path.
+func exportKubeconfigCommand(path string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("$env:KUBECONFIG=\"%s\"", path)
+	}
+	return fmt.Sprintf("export KUBECONFIG=%s", path)
+}
+
+// logComplete prints info upon completion, with the results of the post-install checks
+func logComplete(directory, consoleURL string, checks []postinstall.Result) error {
 	absDir, err := filepath.Abs(directory)
 	if err != nil {
 		return err
 	}
-	kubeconfig := filepath.Join(absDir, "auth", "kubeconfig")
-	pwFile := filepath.Join(absDir, "auth", "kubeadmin-password")
-	pw, err := ioutil.ReadFile(pwFile)
+	store, err := newSecretStore(absDir)
+	if err != nil {
+		return err
+	}
+	pw, err := store.Read(secretstore.KubeadminPasswordName)
 	if err != nil {
 		return err
 	}
 	logrus.Info("Install complete!")
-	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
+	if secretstore.IsFile(store) {
+		logrus.Infof("To access the cluster as the system:admin user when using 'oc', run '%s'", exportKubeconfigCommand(store.Location(secretstore.KubeconfigName)))
+	} else {
+		logrus.Infof("The kubeconfig of the system:admin user is stored in %s", store.Location(secretstore.KubeconfigName))
+	}
 	logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
 	logrus.Infof("Login to the console with user: %q, and password: %q", "kubeadmin", pw)
+	logPostInstallChecks(checks)
 	return nil
 }
 
@@ -503,7 +704,49 @@ func waitForInstallComplete(ctx context.Context, config *rest.Config, directory
 		return err
 	}
 
-	return logComplete(rootOpts.dir, consoleURL)
+	if err = verifyAPI(ctx, config, directory); err != nil {
+		return err
+	}
+
+	checks, err := runPostInstallChecks(ctx, config, directory)
+	if err != nil {
+		return err
+	}
+
+	return logComplete(rootOpts.dir, consoleURL, checks)
+}
+
+// verifyAPI verifies the certificate chain served by the API server against the CA generated
+// by the installer, and warns about the misconfigurations of the DNS of the API found from the
+// installer host, such as the split-horizon DNS of nested KubeVirt clusters.
+func verifyAPI(ctx context.Context, config *rest.Config, directory string) error {
+	apiURL, err := url.Parse(config.Host)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the API URL")
+	}
+	host, port := apiURL.Hostname(), apiURL.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := apicheck.VerifyCertificate(checkCtx, net.JoinHostPort(host, port), host, config.CAData); err != nil {
+		return errors.Wrap(err, "failed to verify the API server certificate")
+	}
+
+	var vip string
+	if assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...); err == nil {
+		if asset, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && asset != nil {
+			if platform := asset.(*installconfig.InstallConfig).Config.Platform.Kubevirt; platform != nil {
+				vip = platform.APIVIP
+			}
+		}
+	}
+	for _, warning := range apicheck.CheckDNS(checkCtx, net.DefaultResolver, host, vip, 3) {
+		logrus.Warn(warning)
+	}
+	return nil
 }
 
 func logTroubleshootingLink() {
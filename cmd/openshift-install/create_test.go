@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	targetassets "github.com/openshift/installer/pkg/asset/targets"
+	"github.com/openshift/installer/pkg/diskcheck"
+)
+
+func TestTargetsAssetDirSpace(t *testing.T) {
+	cases := []struct {
+		name     string
+		targets  []asset.WritableAsset
+		expected diskcheck.Space
+	}{
+		{name: "install-config", targets: targetassets.InstallConfig, expected: assetDirSpace},
+		{name: "manifests", targets: targetassets.Manifests, expected: assetDirSpace},
+		{name: "ignition-configs", targets: targetassets.IgnitionConfigs, expected: assetDirSpace},
+		{name: "cluster", targets: targetassets.Cluster, expected: clusterAssetDirSpace},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, targetsAssetDirSpace(tc.targets))
+		})
+	}
+}
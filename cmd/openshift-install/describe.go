@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/describe"
+)
+
+var (
+	describeConfigOpts struct {
+		kubeconfigPath string
+		output         string
+	}
+)
+
+func newDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe an existing cluster",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newDescribeConfigCmd())
+	return cmd
+}
+
+func newDescribeConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Generate a best-effort install-config of an existing cluster",
+		Long: `Generate the install-config of an existing cluster, e.g. to reproduce it or
+to use it as the template of other clusters.
+
+The install-config stored in the cluster by the installer is used when
+present, otherwise it is reconstructed from the configuration of the cluster,
+including the KubeVirt platform section. The replicas of the machine pools,
+and their sizes on KubeVirt, are updated from the machines of the cluster.
+The secrets are redacted and the pull secret, which the cluster does not
+store, must be set before installing.`,
+		Example: `  openshift-install describe config --kubeconfig=auth/kubeconfig --output=install-config.yaml`,
+		Args:    cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runDescribeConfigCmd(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&describeConfigOpts.kubeconfigPath, "kubeconfig", "", "kubeconfig of the cluster (defaults to the kubeconfig of the assets directory)")
+	cmd.Flags().StringVar(&describeConfigOpts.output, "output", "", "file the install-config is written to (defaults to the standard output)")
+	return cmd
+}
+
+func runDescribeConfigCmd(directory string) error {
+	var restConfig *rest.Config
+	var err error
+	if describeConfigOpts.kubeconfigPath != "" {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", describeConfigOpts.kubeconfigPath)
+	} else {
+		restConfig, err = loadKubeconfig(directory)
+	}
+	if err != nil {
+		return errors.Wrap(err, "loading kubeconfig")
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cluster client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	cluster, err := describe.Fetch(ctx, client)
+	if err != nil {
+		return err
+	}
+	config, warnings, err := describe.Config(cluster)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		logrus.Warn(warning)
+	}
+	data, err := installconfig.RedactedYAML(*config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the install-config")
+	}
+
+	if describeConfigOpts.output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := ioutil.WriteFile(describeConfigOpts.output, data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the install-config")
+	}
+	logrus.Infof("The install-config was written to %s", describeConfigOpts.output)
+	return nil
+}
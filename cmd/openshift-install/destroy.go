@@ -3,11 +3,14 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/destroy"
 	_ "github.com/openshift/installer/pkg/destroy/aws"
@@ -19,9 +22,11 @@ import (
 	_ "github.com/openshift/installer/pkg/destroy/libvirt"
 	_ "github.com/openshift/installer/pkg/destroy/openstack"
 	_ "github.com/openshift/installer/pkg/destroy/ovirt"
+	"github.com/openshift/installer/pkg/destroy/providers"
 	_ "github.com/openshift/installer/pkg/destroy/vsphere"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/timeouts"
 )
 
 func newDestroyCmd() *cobra.Command {
@@ -35,37 +40,99 @@ func newDestroyCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newDestroyBootstrapCmd())
 	cmd.AddCommand(newDestroyClusterCmd())
+	cmd.AddCommand(newDestroyMachinesCmd())
 	return cmd
 }
 
+var (
+	destroyClusterOpts struct {
+		force              bool
+		gracePeriod        time.Duration
+		overrideProtection bool
+		mode               string
+	}
+)
+
+const (
+	// destroyModeDelete deletes the resources of the cluster.
+	destroyModeDelete = "delete"
+	// destroyModeStop stops the machines of the cluster, keeping them for resume.
+	destroyModeStop = "stop"
+)
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
 		Args:  cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
+			lockAssetDir(rootOpts.dir)
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			err := runDestroyCmd(rootOpts.dir)
+			var err error
+			switch destroyClusterOpts.mode {
+			case destroyModeDelete:
+				err = runDestroyCmd(rootOpts.dir, destroyClusterOpts.force, destroyClusterOpts.gracePeriod, destroyClusterOpts.overrideProtection)
+			case destroyModeStop:
+				err = runStopCmd(rootOpts.dir, destroyClusterOpts.force, destroyClusterOpts.gracePeriod)
+			default:
+				err = errors.Errorf("invalid --mode %q, must be %s or %s", destroyClusterOpts.mode, destroyModeDelete, destroyModeStop)
+			}
 			if err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
+	cmd.PersistentFlags().BoolVar(&destroyClusterOpts.force, "force", false, "Continue past resources which cannot be deleted due to missing permissions, and report them at the end, keeping metadata.json for another run to delete them")
+	cmd.PersistentFlags().DurationVar(&destroyClusterOpts.gracePeriod, "grace-period", 0, "Stop the machines and give them this long to shut down gracefully before deleting them, instead of deleting them right away")
+	cmd.PersistentFlags().BoolVar(&destroyClusterOpts.overrideProtection, "override-protection", false, "Destroy the cluster even if it is protected against deletion")
+	cmd.PersistentFlags().StringVar(&destroyClusterOpts.mode, "mode", destroyModeDelete, "Delete the cluster, or stop its machines and keep them and their disks to resume it later with 'openshift-install resume'")
+	return cmd
 }
 
-func runDestroyCmd(directory string) error {
+func runDestroyCmd(directory string, force bool, gracePeriod time.Duration, overrideProtection bool) error {
 	timer.StartTimer(timer.TotalTimeElapsed)
 	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
 	if err != nil {
 		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
+	if err := checkClusterProtection(directory, destroyer, overrideProtection); err != nil {
+		return err
+	}
+	if force {
+		forceDestroyer, ok := destroyer.(providers.ForceDestroyer)
+		if !ok {
+			return errors.New("--force is not supported for the platform of this cluster")
+		}
+		forceDestroyer.SetForce(true)
+	}
+	if gracePeriod > 0 {
+		gracePeriodDestroyer, ok := destroyer.(providers.GracePeriodDestroyer)
+		if !ok {
+			return errors.New("--grace-period is not supported for the platform of this cluster")
+		}
+		gracePeriodDestroyer.SetGracePeriod(gracePeriod)
+	}
+	if timeout := timeouts.Get(timeouts.Destroy, stateInstallConfig(directory), 0); timeout > 0 {
+		if timeoutDestroyer, ok := destroyer.(providers.TimeoutDestroyer); ok {
+			logrus.Infof("Destroying the cluster for up to %v", timeout)
+			timeoutDestroyer.SetTimeout(timeout)
+		} else {
+			logrus.Warnf("Ignoring the destroy timeout %v, not supported for the platform of this cluster", timeout)
+		}
+	}
+	recordMilestone(directory, ickubevirt.MilestoneDestroyStarted, "Deleting the resources of the cluster")
 	if err := destroyer.Run(); err != nil {
+		// The metadata of the cluster is kept for another run to delete the resources left
+		var skipped *providers.SkippedResourcesError
+		if errors.As(err, &skipped) {
+			logrus.Warn("Keeping metadata.json to delete the resources left, run destroy cluster again once they can be deleted")
+		}
 		return errors.Wrap(err, "Failed to destroy cluster")
 	}
 
-	store, err := assetstore.NewStore(directory)
+	store, err := assetstore.NewStore(directory, assetStoreOptions()...)
 	if err != nil {
 		return errors.Wrap(err, "failed to create asset store")
 	}
@@ -97,6 +164,7 @@ func newDestroyBootstrapCmd() *cobra.Command {
 		Short: "Destroy the bootstrap resources",
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
+			lockAssetDir(rootOpts.dir)
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
@@ -110,3 +178,86 @@ func newDestroyBootstrapCmd() *cobra.Command {
 		},
 	}
 }
+
+var (
+	destroyMachinesOpts struct {
+		pool        string
+		namePrefix  string
+		dryRun      bool
+		force       bool
+		gracePeriod time.Duration
+	}
+)
+
+func newDestroyMachinesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "machines",
+		Short: "Destroy the machines of a compute machine pool",
+		Long: `Destroy the infra resources of the machines of a compute machine pool,
+e.g. an experimental worker pool, keeping the control plane and the other
+machine pools of the cluster.
+
+The MachineSet of the machine pool must be scaled down to 0 or deleted
+first, or the machine-api creates its machines again.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runDestroyMachinesCmd(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&destroyMachinesOpts.pool, "pool", "", "The name of the compute machine pool whose machines are destroyed")
+	cmd.Flags().StringVar(&destroyMachinesOpts.namePrefix, "name-prefix", "", "The name prefix of the machine pool, when set in install-config.yaml (default: the infra ID of the cluster)")
+	cmd.Flags().BoolVar(&destroyMachinesOpts.dryRun, "dry-run", false, "List the resources of the machine pool without deleting them")
+	cmd.Flags().BoolVar(&destroyMachinesOpts.force, "force", false, "Continue past resources which cannot be deleted due to missing permissions, and report them at the end")
+	cmd.Flags().DurationVar(&destroyMachinesOpts.gracePeriod, "grace-period", 0, "Stop the machines and give them this long to shut down gracefully before deleting them, instead of deleting them right away")
+	return cmd
+}
+
+func runDestroyMachinesCmd(directory string) error {
+	pool := destroyMachinesOpts.pool
+	switch pool {
+	case "":
+		return errors.New("--pool is required")
+	case "master":
+		return errors.New("the machines of the control plane cannot be destroyed, use destroy cluster instead")
+	}
+	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	poolDestroyer, ok := destroyer.(providers.PoolDestroyer)
+	if !ok {
+		return errors.New("destroying the machines of a machine pool is not supported for the platform of this cluster")
+	}
+	if destroyMachinesOpts.force {
+		forceDestroyer, ok := destroyer.(providers.ForceDestroyer)
+		if !ok {
+			return errors.New("--force is not supported for the platform of this cluster")
+		}
+		forceDestroyer.SetForce(true)
+	}
+	if destroyMachinesOpts.gracePeriod > 0 {
+		gracePeriodDestroyer, ok := destroyer.(providers.GracePeriodDestroyer)
+		if !ok {
+			return errors.New("--grace-period is not supported for the platform of this cluster")
+		}
+		gracePeriodDestroyer.SetGracePeriod(destroyMachinesOpts.gracePeriod)
+	}
+	deleted, err := poolDestroyer.DestroyPool(pool, destroyMachinesOpts.namePrefix, destroyMachinesOpts.dryRun)
+	if err != nil {
+		return errors.Wrapf(err, "failed to destroy the machines of machine pool %s", pool)
+	}
+	switch {
+	case len(deleted) == 0:
+		logrus.Infof("No resources found for machine pool %s", pool)
+	case destroyMachinesOpts.dryRun:
+		logrus.Infof("The following resources belong to machine pool %s:\n%s", pool, strings.Join(deleted, "\n"))
+	default:
+		logrus.Infof("Destroyed %d resources of machine pool %s", len(deleted), pool)
+	}
+	return nil
+}
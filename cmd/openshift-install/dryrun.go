@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+var (
+	dryRunOpts struct {
+		enabled bool
+		output  string
+	}
+)
+
+// runDryRun validates the install-config and generates the assets of the cluster, short of
+// the cluster itself, and prints the provisioning plan of the cluster. The assets directory is
+// left as it was and the infra cluster is only read.
+func runDryRun(directory string, targets []asset.WritableAsset) error {
+	if dryRunOpts.output != "yaml" && dryRunOpts.output != "json" {
+		return errors.Errorf("invalid --output %q, must be yaml or json", dryRunOpts.output)
+	}
+
+	ickubevirt.SetReadOnly(true)
+	defer ickubevirt.SetReadOnly(false)
+
+	assetStore, err := assetstore.NewDryRunStore(directory, assetStoreOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	for _, a := range targets {
+		// Creating the cluster is what the dry run leaves out
+		if _, ok := a.(*cluster.Cluster); ok {
+			continue
+		}
+		if err := assetStore.Fetch(a, targets...); err != nil {
+			return errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+	}
+
+	installConfig := &installconfig.InstallConfig{}
+	terraformVariables := &cluster.TerraformVariables{}
+	for _, a := range []asset.Asset{installConfig, terraformVariables} {
+		if err := assetStore.Fetch(a); err != nil {
+			return errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+	}
+	plan, err := cluster.NewPlan(installConfig.Config.Platform.Name(), terraformVariables)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if dryRunOpts.output == "json" {
+		data, err = json.MarshalIndent(plan, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(plan)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the provisioning plan")
+	}
+	fmt.Print(string(data))
+	logrus.Infof("The cluster would be created with %d resources in the infra cluster, nothing was created", len(plan.Resources))
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+)
+
+// recordMilestone records the milestone of the cluster in the infra cluster, for the infra
+// cluster admins to follow it. It is a no-op for the platforms other than kubevirt, and only warns
+// when the milestone cannot be recorded, which doesn't affect the cluster.
+func recordMilestone(directory string, milestone ickubevirt.Milestone, message string) {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil || metadata.Kubevirt == nil {
+		return
+	}
+	if err := ickubevirt.RecordMilestone(metadata.InfraID, metadata.Kubevirt, milestone, message); err != nil {
+		logrus.Warnf("Failed to record the %s milestone in the infra cluster: %v", milestone, err)
+	}
+}
+
+// startHeartbeat updates the heartbeat of the cluster in the infra cluster until the returned
+// function is called, for the infra cluster admins to tell the running installs from the
+// abandoned ones. It is a no-op for the platforms other than kubevirt.
+func startHeartbeat(directory string) (stop func()) {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil || metadata.Kubevirt == nil {
+		return func() {}
+	}
+	return ickubevirt.StartHeartbeat(metadata.InfraID, metadata.Kubevirt)
+}
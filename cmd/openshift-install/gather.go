@@ -1,8 +1,11 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -21,6 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/gather/ssh"
@@ -92,7 +96,7 @@ func newGatherBootstrapCmd() *cobra.Command {
 }
 
 func runGatherBootstrapCmd(directory string) error {
-	assetStore, err := assetstore.NewStore(directory)
+	assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...)
 	if err != nil {
 		return errors.Wrap(err, "failed to create asset store")
 	}
@@ -162,6 +166,12 @@ func logGatherBootstrap(bootstrap string, port int, masters []string, directory
 	if err := ssh.PullFileTo(client, fmt.Sprintf("/home/core/log-bundle-%s.tar.gz", gatherID), file); err != nil {
 		return errors.Wrap(err, "failed to pull log file from remote")
 	}
+	auditLog := filepath.Join(directory, ickubevirt.AuditLogName)
+	if _, err := os.Stat(auditLog); err == nil {
+		if err := addFileToLogBundle(file, auditLog, fmt.Sprintf("log-bundle-%s/installer/kubevirt-audit.log", gatherID)); err != nil {
+			logrus.Warnf("Failed to add the infra cluster audit log to the log bundle: %v", err)
+		}
+	}
 	path, err := filepath.Abs(file)
 	if err != nil {
 		return errors.Wrap(err, "failed to stat log file")
@@ -170,6 +180,73 @@ func logGatherBootstrap(bootstrap string, port int, masters []string, directory
 	return nil
 }
 
+// addFileToLogBundle rewrites the gzipped tarball at bundle with the file at path added as name.
+func addFileToLogBundle(bundle string, path string, name string) error {
+	in, err := os.Open(bundle)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	gzipReader, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	out, err := ioutil.TempFile(filepath.Dir(bundle), filepath.Base(bundle))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	gzipWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return err
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Rename(out.Name(), bundle)
+}
+
 func extractHostAddresses(config *types.InstallConfig, tfstate *terraform.State) (bootstrap string, port int, masters []string, err error) {
 	port = 22
 	switch config.Platform.Name() {
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/hooks"
+	"github.com/openshift/installer/pkg/secretstore"
+)
+
+// runStage runs the stage between the pre and post hooks of the stage.
+func runStage(ctx context.Context, directory string, stage hooks.Stage, run func() error) error {
+	if err := hooks.Run(ctx, hookContext(directory, hooks.PhasePre, stage)); err != nil {
+		return err
+	}
+	if err := run(); err != nil {
+		return err
+	}
+	return hooks.Run(ctx, hookContext(directory, hooks.PhasePost, stage))
+}
+
+// hookContext returns the context of the hooks, with the cluster metadata and the admin
+// kubeconfig when they have been written to the directory.
+func hookContext(directory string, phase hooks.Phase, stage hooks.Stage) hooks.Context {
+	hookContext := hooks.Context{
+		Phase:     phase,
+		Stage:     stage,
+		Directory: directory,
+	}
+	if dir, err := filepath.Abs(directory); err == nil {
+		hookContext.Directory = dir
+	}
+	if metadata, err := cluster.LoadMetadata(directory); err == nil {
+		hookContext.ClusterName = metadata.ClusterName
+		hookContext.InfraID = metadata.InfraID
+		hookContext.Platform = metadata.Platform()
+	}
+	if store, err := secretstore.New("file", hookContext.Directory, secretstore.Kubeconfig{}); err == nil {
+		if kubeconfig := store.Location(secretstore.KubeconfigName); fileExists(kubeconfig) {
+			hookContext.Kubeconfig = kubeconfig
+		}
+	}
+	return hookContext
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/imagecache"
+)
+
+var (
+	imageCachePruneOpts struct {
+		namespaces     []string
+		kubeconfigPath string
+		context        string
+		caBundle       string
+		olderThan      time.Duration
+		dryRun         bool
+	}
+)
+
+func newImageCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image-cache",
+		Short: "Manage the RHCOS images cached in the infra cluster",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newImageCachePruneCmd())
+	return cmd
+}
+
+func newImageCachePruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the RHCOS source DataVolumes no cluster uses anymore",
+		Long: `List the RHCOS source DataVolumes created by the installer in the
+namespaces of the KubeVirt infra cluster, with the clusters whose VMs
+are cloned from them, and delete the ones which no VM is cloned from
+and which are older than --older-than.
+
+Such DataVolumes are usually left behind by failed installs or destroys.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runImageCachePruneCmd(); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringSliceVar(&imageCachePruneOpts.namespaces, "namespace", nil, "namespace of the infra cluster to prune, may be repeated")
+	cmd.Flags().StringVar(&imageCachePruneOpts.kubeconfigPath, "kubeconfig", "", "kubeconfig of the infra cluster (defaults to KUBECONFIG, or ~/.kube/config)")
+	cmd.Flags().StringVar(&imageCachePruneOpts.context, "context", "", "context of the kubeconfig (defaults to its current-context)")
+	cmd.Flags().StringVar(&imageCachePruneOpts.caBundle, "ca-bundle", "", "file of an additional PEM CA bundle trusted when connecting to the infra cluster")
+	cmd.Flags().DurationVar(&imageCachePruneOpts.olderThan, "older-than", 24*time.Hour, "only delete the DataVolumes created before this long ago")
+	cmd.Flags().BoolVar(&imageCachePruneOpts.dryRun, "dry-run", false, "only print the DataVolumes which would be deleted")
+	return cmd
+}
+
+func runImageCachePruneCmd() error {
+	if len(imageCachePruneOpts.namespaces) == 0 {
+		return errors.New("at least one --namespace is required")
+	}
+	client, err := ickubevirt.NewClientFor(imageCachePruneOpts.kubeconfigPath, imageCachePruneOpts.context, imageCachePruneOpts.caBundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Minute)
+	defer cancel()
+	images, err := imagecache.List(ctx, client, imageCachePruneOpts.namespaces)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		logrus.Info("No source DataVolume was found")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tAGE\tCLUSTERS")
+	for _, image := range images {
+		clusters := strings.Join(image.Clusters, ",")
+		if clusters == "" {
+			clusters = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", image.Namespace, image.Name, duration.HumanDuration(now.Sub(image.Created)), clusters)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	pruned, err := imagecache.Prune(client, images, imageCachePruneOpts.olderThan, now, imageCachePruneOpts.dryRun, logrus.StandardLogger())
+	if err != nil {
+		return err
+	}
+	if imageCachePruneOpts.dryRun {
+		logrus.Infof("%d DataVolumes would be deleted", len(pruned))
+	} else {
+		logrus.Infof("%d DataVolumes were deleted", len(pruned))
+	}
+	return nil
+}
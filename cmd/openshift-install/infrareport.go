@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	infraReportOpts struct {
+		platform string
+	}
+)
+
+func newInfraCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "infra",
+		Short: "Inspect the infrastructure the cluster is installed on",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newInfraReportCmd())
+	return cmd
+}
+
+func newInfraReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report the capacity of the infrastructure for the cluster of the install-config",
+		Long: `Report the allocatable resources of the infra cluster nodes, the VMs
+already running on them, and the capacities of the storage classes published
+by their CSI drivers, and whether the VMs and disks of the cluster of the
+install-config fit.`,
+		Example: `  openshift-install infra report --platform=kubevirt`,
+		Args:    cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			fits, err := runInfraReportCmd(rootOpts.dir, infraReportOpts.platform)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if !fits {
+				logrus.Fatal("The cluster does not fit the infrastructure")
+			}
+			logrus.Info("The cluster fits the infrastructure")
+		},
+	}
+	cmd.Flags().StringVar(&infraReportOpts.platform, "platform", kubevirt.Name, "platform of the infrastructure, only kubevirt is supported")
+	return cmd
+}
+
+func runInfraReportCmd(directory string, platform string) (bool, error) {
+	if platform != kubevirt.Name {
+		return false, errors.Errorf("infra reports are not supported for platform %q", platform)
+	}
+	config, err := readInstallConfig(directory)
+	if err != nil {
+		return false, err
+	}
+	if config.Platform.Name() != platform {
+		return false, errors.Errorf("the install-config is for platform %q, not %q", config.Platform.Name(), platform)
+	}
+	client, err := ickubevirt.NewClientFor(config.Kubevirt.InfraKubeconfigPath, config.Kubevirt.InfraContext, config.Kubevirt.InfraCABundle)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Minute)
+	defer cancel()
+	report, err := ickubevirt.ReportCapacity(ctx, config, client)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to report the infra cluster capacity")
+	}
+	if err := printInfraReport(report); err != nil {
+		return false, err
+	}
+	return report.Fits(), nil
+}
+
+func printInfraReport(report *ickubevirt.CapacityReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSCHEDULABLE\tCPU\tMEMORY\tVMS\tREQUESTED CPU\tREQUESTED MEMORY")
+	for _, node := range report.Nodes {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%d\t%s\t%s\n", node.Name, node.Schedulable, node.AllocatableCPU.String(), node.AllocatableMemory.String(), node.VMs, node.RequestedCPU.String(), node.RequestedMemory.String())
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "STORAGE CLASS\tREQUIRED\tCAPACITY")
+	for _, storageClass := range report.StorageClasses {
+		capacity := "<unknown>"
+		if storageClass.Capacity != nil {
+			capacity = storageClass.Capacity.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", storageClass.Name, storageClass.Required.String(), capacity)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, problem := range report.Problems {
+		logrus.Warn(problem)
+	}
+	return nil
+}
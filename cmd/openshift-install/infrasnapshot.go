@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/validation"
+)
+
+// defaultInfraSnapshotFileName is the file of the snapshot in the assets directory.
+const defaultInfraSnapshotFileName = "infra-snapshot.json"
+
+var (
+	infraSnapshotOpts struct {
+		file string
+	}
+)
+
+func newInfraSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "infra-snapshot",
+		Short: "Validate the install-config against a snapshot of the infra cluster",
+		Long: `Export a snapshot of the KubeVirt infra cluster objects the install-config
+is validated against (namespaces, storage classes, network-attachment-definitions,
+resource quotas and VM names), and later validate the install-config against
+the snapshot, without access to the infra cluster.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.PersistentFlags().StringVar(&infraSnapshotOpts.file, "snapshot", "", "file of the snapshot (defaults to "+defaultInfraSnapshotFileName+" in the assets directory)")
+	cmd.AddCommand(newInfraSnapshotExportCmd())
+	cmd.AddCommand(newInfraSnapshotValidateCmd())
+	return cmd
+}
+
+func newInfraSnapshotExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export the snapshot of the infra cluster of the install-config",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runInfraSnapshotExportCmd(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+}
+
+func newInfraSnapshotValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the install-config against the snapshot of the infra cluster",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runInfraSnapshotValidateCmd(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+			logrus.Info("The install-config is valid")
+		},
+	}
+}
+
+// infraSnapshotFile returns the file of the snapshot.
+func infraSnapshotFile(directory string) string {
+	if infraSnapshotOpts.file != "" {
+		return infraSnapshotOpts.file
+	}
+	return filepath.Join(directory, defaultInfraSnapshotFileName)
+}
+
+// readKubevirtInstallConfig reads the install-config in the directory, failing unless it is
+// for the kubevirt platform.
+func readKubevirtInstallConfig(directory string) (*types.InstallConfig, error) {
+	config, err := readInstallConfig(directory)
+	if err != nil {
+		return nil, err
+	}
+	if platform := config.Platform.Name(); platform != kubevirt.Name {
+		return nil, errors.Errorf("infra cluster snapshots are not supported for platform %q", platform)
+	}
+	return config, nil
+}
+
+func runInfraSnapshotExportCmd(directory string) error {
+	config, err := readKubevirtInstallConfig(directory)
+	if err != nil {
+		return err
+	}
+	client, err := ickubevirt.NewClientFor(config.Kubevirt.InfraKubeconfigPath, config.Kubevirt.InfraContext, config.Kubevirt.InfraCABundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Minute)
+	defer cancel()
+	snapshot, err := ickubevirt.TakeSnapshot(ctx, client, []string{config.Kubevirt.Namespace})
+	if err != nil {
+		return errors.Wrap(err, "failed to take the infra cluster snapshot")
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the infra cluster snapshot")
+	}
+	file := infraSnapshotFile(directory)
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write the infra cluster snapshot")
+	}
+	logrus.Infof("Infra cluster snapshot written to %s", file)
+	return nil
+}
+
+func runInfraSnapshotValidateCmd(directory string) error {
+	config, err := readKubevirtInstallConfig(directory)
+	if err != nil {
+		return err
+	}
+	file := infraSnapshotFile(directory)
+	snapshot, err := ickubevirt.LoadSnapshot(file)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the infra cluster snapshot")
+	}
+	logrus.Infof("Validating against the infra cluster snapshot %s taken at %s", file, snapshot.Time.Format(time.RFC3339))
+
+	if err := validation.ValidateInstallConfig(config).ToAggregate(); err != nil {
+		return errors.Wrap(err, "invalid install-config")
+	}
+	if err := ickubevirt.ValidateWithSnapshot(config, snapshot); err != nil {
+		return errors.Wrap(err, "invalid install-config")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/diskcheck"
+	"github.com/openshift/installer/pkg/liveiso"
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/rhcos/cache"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	isoOpts struct {
+		baseISO string
+	}
+)
+
+// isoMachine is a machine booted from a live ISO, installing RHCOS with its Ignition config.
+type isoMachine struct {
+	role string
+	bus  kubevirt.DiskBus
+}
+
+// createISOs writes the live ISOs installing the bootstrap, master and worker machines with
+// the Ignition configs of the assets directory, for the VMs of the infra cluster to boot from
+// when the installer cannot reach the infra cluster.
+func createISOs(directory string) error {
+	assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	asset, err := assetStore.Load(&installconfig.InstallConfig{})
+	if err != nil {
+		return errors.Wrap(err, "failed to load the install-config")
+	}
+	if asset == nil {
+		return errors.New("no install-config in the assets directory")
+	}
+	config := asset.(*installconfig.InstallConfig).Config
+	if platform := config.Platform.Name(); platform != kubevirt.Name {
+		return errors.Errorf("live ISOs are not supported for platform %q", platform)
+	}
+
+	base, err := baseISO(config)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(base)
+	if err != nil {
+		return err
+	}
+
+	// The bootstrap VM and the workers boot from virtio disks, see data/data/kubevirt
+	machines := []isoMachine{{role: "bootstrap", bus: kubevirt.DiskBusVirtio}, {role: "master", bus: kubevirt.DiskBusVirtio}, {role: "worker", bus: kubevirt.DiskBusVirtio}}
+	if pool := config.ControlPlane.Platform.Kubevirt; pool != nil && pool.DiskBus != "" {
+		machines[1].bus = pool.DiskBus
+	}
+	if err := diskcheck.Check(directory, diskcheck.Space{Bytes: uint64(info.Size()) * uint64(len(machines)), Inodes: uint64(len(machines))}); err != nil {
+		return errors.Wrap(err, "the assets directory is too small for the live ISOs")
+	}
+
+	for _, machine := range machines {
+		ignition, err := ioutil.ReadFile(filepath.Join(directory, machine.role+".ign"))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the %s Ignition config", machine.role)
+		}
+		liveIgnition, err := liveiso.Installer{
+			Device:                diskDevice(machine.bus),
+			Ignition:              ignition,
+			ImageContentSources:   config.ImageContentSources,
+			AdditionalTrustBundle: config.AdditionalTrustBundle,
+		}.Config()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the %s live Ignition config", machine.role)
+		}
+		path := filepath.Join(directory, machine.role+".iso")
+		if err := liveiso.Write(base, path, liveIgnition); err != nil {
+			return errors.Wrapf(err, "failed to write the %s live ISO", machine.role)
+		}
+		logrus.Infof("Live ISO of the %s machines created in %s", machine.role, path)
+	}
+	return nil
+}
+
+// baseISO returns the path of the RHCOS live ISO the ISOs are written from, downloaded to the
+// image cache unless set with --base-iso.
+func baseISO(config *types.InstallConfig) (string, error) {
+	if isoOpts.baseISO != "" {
+		return isoOpts.baseISO, nil
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+	url, err := rhcos.LiveISO(ctx, config.ControlPlane.Architecture)
+	if err != nil {
+		return "", err
+	}
+	path, err := cache.DownloadImageFile(url)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to download the RHCOS live ISO")
+	}
+	return path, nil
+}
+
+// diskDevice returns the device of the boot disk of the VMs emulating the bus.
+func diskDevice(bus kubevirt.DiskBus) string {
+	switch bus {
+	case kubevirt.DiskBusSATA, kubevirt.DiskBusSCSI:
+		return "/dev/sda"
+	default:
+		return "/dev/vda"
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	targetassets "github.com/openshift/installer/pkg/asset/targets"
+)
+
+var (
+	listTargetsOpts struct {
+		output string
+	}
+)
+
+const (
+	// listTargetsOutputText prints one line per target with its files.
+	listTargetsOutputText = "text"
+	// listTargetsOutputJSON prints the targets as JSON.
+	listTargetsOutputJSON = "json"
+)
+
+// targetDescription is the JSON output of a create target.
+type targetDescription struct {
+	// Name is the create subcommand of the target.
+	Name string `json:"name"`
+	// Description is the short description of the subcommand.
+	Description string `json:"description"`
+	// Hidden is set for the subcommands hidden from the help.
+	Hidden bool `json:"hidden,omitempty"`
+	// Assets are the assets written by the target.
+	Assets []assetDescription `json:"assets"`
+	// Files are the files written by the target besides the files of its assets.
+	Files []string `json:"files,omitempty"`
+}
+
+// assetDescription is the JSON output of an asset written by a target.
+type assetDescription struct {
+	Name string `json:"name"`
+	// Files are the files written in the assets directory, or their globs when their names
+	// depend on the install-config.
+	Files []string `json:"files,omitempty"`
+}
+
+func newListTargetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-targets",
+		Short: "List the create targets, including the hidden ones, and the files they write",
+		Long: `List the targets of the create command, including the ones hidden from the
+help, with the assets they generate and the files, relative to the assets
+directory, they write, so that wrappers don't hardcode the file lists. The
+files whose names depend on the install-config are listed as globs.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			descriptions := describeTargets()
+			switch listTargetsOpts.output {
+			case listTargetsOutputText:
+				for _, description := range descriptions {
+					files := sets.NewString()
+					for _, a := range description.Assets {
+						files.Insert(a.Files...)
+					}
+					files.Insert(description.Files...)
+					fmt.Printf("%s\t%s\n", description.Name, strings.Join(files.List(), " "))
+				}
+			case listTargetsOutputJSON:
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(descriptions); err != nil {
+					logrus.Fatal(err)
+				}
+			default:
+				logrus.Fatalf("invalid --output %q, must be %s or %s", listTargetsOpts.output, listTargetsOutputText, listTargetsOutputJSON)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&listTargetsOpts.output, "output", listTargetsOutputText, "format of the list (e.g. \"text | json\")")
+	return cmd
+}
+
+// describeTargets returns the descriptions of the create targets, in their order.
+func describeTargets() []targetDescription {
+	var descriptions []targetDescription
+	for _, t := range targets {
+		description := targetDescription{
+			Name:        t.command.Name(),
+			Description: t.command.Short,
+			Hidden:      t.command.Hidden,
+			Files:       t.files,
+		}
+		for _, a := range t.assets {
+			description.Assets = append(description.Assets, assetDescription{
+				Name:  a.Name(),
+				Files: targetassets.FileNames(a),
+			})
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/dirlock"
+)
+
+// lockAssetDir locks the assets directory until the installer exits, for the commands writing
+// the assets or the Terraform state not to run concurrently on the same directory. The
+// commands only reading the directory, e.g. wait-for and gather, don't lock it and can run
+// while it is locked.
+func lockAssetDir(directory string) {
+	if err := dirlock.Acquire(directory); err != nil {
+		logrus.Fatal(err)
+	}
+}
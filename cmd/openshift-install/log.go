@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	"github.com/openshift/installer/pkg/version"
 )
 
@@ -94,6 +95,8 @@ func setupFileHook(baseDir string) func() {
 		DisableLevelTruncation: false,
 	}))
 
+	ickubevirt.SetAuditLogPath(filepath.Join(baseDir, ickubevirt.AuditLogName))
+
 	versionString, err := version.String()
 	if err != nil {
 		logrus.Fatal(err)
@@ -104,6 +107,7 @@ func setupFileHook(baseDir string) func() {
 	}
 
 	return func() {
+		ickubevirt.SetAuditLogPath("")
 		logfile.Close()
 		logrus.StandardLogger().ReplaceHooks(originalHooks)
 	}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -14,13 +17,28 @@ import (
 	"k8s.io/klog"
 	klogv2 "k8s.io/klog/v2"
 
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/featuregates"
+	"github.com/openshift/installer/pkg/hooks"
+	"github.com/openshift/installer/pkg/metrics/progress"
+	"github.com/openshift/installer/pkg/metrics/tracing"
+	"github.com/openshift/installer/pkg/statecrypt"
 	"github.com/openshift/installer/pkg/terraform/exec/plugins"
+	"github.com/openshift/installer/pkg/timeouts"
 )
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir          string
+		logLevel     string
+		secretStore  string
+		encryptState bool
+		stateKeyFile string
+		serveMetrics string
+		hooks        []string
+		lenient      bool
+		// timeouts are the --*-timeout flags of the stages, by stage.
+		timeouts map[timeouts.Stage]*time.Duration
 	}
 )
 
@@ -63,13 +81,31 @@ func installerMain() {
 		newCompletionCmd(),
 		newMigrateCmd(),
 		newExplainCmd(),
+		newCoreOSCmd(),
+		newAnalyzeCmd(),
+		newImageCacheCmd(),
+		newStateCmd(),
+		newInfraSnapshotCmd(),
+		newInfraCmd(),
+		newProtectCmd(),
+		newResumeCmd(),
+		newRelabelCmd(),
+		newScaleCmd(),
+		newCleanCmd(),
+		newValidateCmd(),
+		newListTargetsCmd(),
+		newDescribeCmd(),
+		newConsoleCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
 	}
 
+	// Export the traces also when the command exits through logrus.Fatal
+	logrus.RegisterExitHandler(tracing.Flush)
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Fatalf("Error executing openshift-install: %v", err)
 	}
+	tracing.Flush()
 }
 
 func newRootCmd() *cobra.Command {
@@ -83,6 +119,16 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
 	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	cmd.PersistentFlags().BoolVar(&rootOpts.encryptState, "encrypt-state", false, "encrypt the state file and the Terraform state with the passphrase from "+statecrypt.PassphraseEnvVar+" or --state-key-file")
+	cmd.PersistentFlags().StringVar(&rootOpts.stateKeyFile, "state-key-file", "", "file holding the passphrase used to encrypt and decrypt the state")
+	cmd.PersistentFlags().StringVar(&rootOpts.serveMetrics, "serve-metrics", "", "address to serve the Prometheus metrics of the install progress on, at /metrics (e.g. \":9100\")")
+	cmd.PersistentFlags().StringArrayVar(&rootOpts.hooks, "hook", nil, "executable run before or after a stage with the JSON stage context on stdin, as PHASE-STAGE=PATH with the phase pre or post and the stage manifests, infrastructure, bootstrap or install (can be repeated)")
+	cmd.PersistentFlags().BoolVar(&rootOpts.lenient, "lenient", false, "ignore the unknown fields of the install-config instead of rejecting them")
+	cmd.PersistentFlags().StringVar(&rootOpts.secretStore, "secret-store", "file", "where the kubeadmin password and the admin kubeconfig are stored (e.g. \"file | secure-file | kubernetes-secret:<namespace>/<name> | vault:<path>\")")
+	rootOpts.timeouts = map[timeouts.Stage]*time.Duration{}
+	for _, stage := range timeouts.Stages {
+		rootOpts.timeouts[stage] = cmd.PersistentFlags().Duration(string(stage)+"-timeout", 0, fmt.Sprintf("timeout of the %s stage, overriding the timeouts of the install-config and the default", stage))
+	}
 	return cmd
 }
 
@@ -110,4 +156,47 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		logrus.Fatal(errors.Wrap(err, "invalid log-level"))
 	}
+
+	stateKey := []byte(os.Getenv(statecrypt.PassphraseEnvVar))
+	if rootOpts.stateKeyFile != "" {
+		if stateKey, err = ioutil.ReadFile(rootOpts.stateKeyFile); err != nil {
+			logrus.Fatal(errors.Wrap(err, "failed to read the state key file"))
+		}
+		stateKey = bytes.TrimSpace(stateKey)
+	}
+	if err := statecrypt.Configure(stateKey, rootOpts.encryptState); err != nil {
+		logrus.Fatal(err)
+	}
+
+	if err := hooks.Configure(rootOpts.hooks); err != nil {
+		logrus.Fatal(err)
+	}
+
+	for stage, timeout := range rootOpts.timeouts {
+		if *timeout < 0 {
+			logrus.Fatalf("invalid --%s-timeout %v, must be positive", stage, *timeout)
+		}
+		timeouts.Override(stage, *timeout)
+	}
+
+	if err := featuregates.Configure(os.Getenv(featuregates.EnvVar), os.Getenv(featuregates.FileEnvVar)); err != nil {
+		logrus.Fatal(err)
+	}
+	if active := featuregates.Active(); len(active) > 0 {
+		logrus.Warnf("Experimental feature gates enabled:\n  %s", strings.Join(active, "\n  "))
+	}
+
+	if rootOpts.serveMetrics != "" {
+		if err := progress.Serve(rootOpts.serveMetrics); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
+// assetStoreOptions returns the options of the asset stores set by the root flags.
+func assetStoreOptions() []assetstore.Options {
+	if rootOpts.lenient {
+		return []assetstore.Options{assetstore.WithLenient()}
+	}
+	return nil
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+var (
+	manifestsDiffOpts struct {
+		diff      bool
+		overwrite bool
+	}
+)
+
+// printManifestsDiff prints the unified diff of the changes writing the targets would make to
+// the files in the directory. The targets are fetched without changing the directory, so that
+// it is left as it was when the changes are not applied.
+func printManifestsDiff(directory string, targets []asset.WritableAsset) error {
+	assetStore, err := assetstore.NewDryRunStore(directory, assetStoreOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+
+	changed := false
+	for _, a := range targets {
+		if err := assetStore.Fetch(a, targets...); err != nil {
+			return errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+		diff, err := asset.DiffWithFiles(a, directory)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compare %s with the files on disk", a.Name())
+		}
+		if diff != "" {
+			changed = true
+			fmt.Print(diff)
+		}
+	}
+	if !changed {
+		logrus.Info("The manifests on disk are up to date")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// saveClusterMetadata writes the cluster metadata to metadata.json and to the Metadata asset
+// of the state file, so that the asset store doesn't write the former metadata back.
+func saveClusterMetadata(directory string, metadata *types.ClusterMetadata) error {
+	if err := cluster.SaveMetadata(directory, metadata); err != nil {
+		return err
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to Marshal ClusterMetadata")
+	}
+	return errors.Wrap(assetstore.UpdateState(directory, func(a asset.Asset) bool {
+		m := a.(*cluster.Metadata)
+		m.File = &asset.File{Filename: m.FileNames()[0], Data: data}
+		return true
+	}, &cluster.Metadata{}), "failed to update the metadata in the state file")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestSaveClusterMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSaveClusterMetadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	statePath := filepath.Join(dir, ".openshift_install_state.json")
+	state := `{"*cluster.Metadata": {"File": {"Filename": "metadata.json", "Data": "eyJpbmZyYUlEIjoiaW5mcmEtaWQifQ=="}}}`
+	if err := ioutil.WriteFile(statePath, []byte(state), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := &types.ClusterMetadata{InfraID: "infra-id", Protected: true}
+	if !assert.NoError(t, saveClusterMetadata(dir, metadata)) {
+		return
+	}
+
+	loaded, err := cluster.LoadMetadata(dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, metadata, loaded)
+	}
+
+	// The Metadata asset of the state file holds the new metadata as well
+	raw, err := ioutil.ReadFile(statePath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var stored map[string]cluster.Metadata
+	if !assert.NoError(t, json.Unmarshal(raw, &stored)) {
+		return
+	}
+	var storedMetadata types.ClusterMetadata
+	if assert.NoError(t, json.Unmarshal(stored["*cluster.Metadata"].File.Data, &storedMetadata)) {
+		assert.Equal(t, *metadata, storedMetadata)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// recordMachinePlacements records in the metadata the infra cluster nodes and zones the VMs of
+// the cluster are scheduled to, waiting a bit for the VMs which are not scheduled yet. It is a
+// no-op for the platforms other than kubevirt.
+func recordMachinePlacements(directory string) error {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	if metadata.Kubevirt == nil {
+		return nil
+	}
+	client, err := ickubevirt.NewClientForMetadata(metadata.Kubevirt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	var placements []kubevirt.MachinePlacement
+	err = wait.PollImmediate(5*time.Second, 2*time.Minute, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+		defer cancel()
+		placements, err = ickubevirt.MachinePlacements(ctx, client, metadata.Kubevirt.Namespace, metadata.Kubevirt.Labels)
+		if err != nil {
+			return false, err
+		}
+		for _, placement := range placements {
+			if placement.Node == "" {
+				logrus.Debugf("Waiting for VM %s to be scheduled", placement.Name)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		logrus.Warn("Some VMs are not scheduled yet, recording their placements without a node")
+	} else if err != nil {
+		return errors.Wrap(err, "failed to look up the placements of the VMs")
+	}
+
+	metadata.Kubevirt.Machines = placements
+	return errors.Wrap(saveClusterMetadata(directory, metadata), "failed to save the cluster metadata")
+}
+
+// showMachinePlacements prints the placements of the VMs of the cluster recorded in its metadata.
+func showMachinePlacements(directory string) error {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	if metadata.Kubevirt == nil || len(metadata.Kubevirt.Machines) == 0 {
+		return errors.New("no machine placements are recorded in the cluster metadata")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tNODE\tZONE")
+	for _, placement := range metadata.Kubevirt.Machines {
+		node, zone := placement.Node, placement.Zone
+		if node == "" {
+			node = "<pending>"
+		}
+		if zone == "" {
+			zone = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", placement.Namespace, placement.Name, node, zone)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jstemmer/go-junit-report/formatter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/defaults"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	platformChecksOpts struct {
+		only        bool
+		junitOutput string
+	}
+)
+
+// readInstallConfig reads the install-config in the directory, with its defaults set, without
+// validating it.
+func readInstallConfig(directory string) (*types.InstallConfig, error) {
+	return readInstallConfigFile(filepath.Join(directory, "install-config.yaml"))
+}
+
+// readInstallConfigFile reads the install-config file, with its defaults set, without
+// validating it.
+func readInstallConfigFile(path string) (*types.InstallConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the install-config")
+	}
+	config := &types.InstallConfig{}
+	if err := installconfig.Unmarshal(data, config, rootOpts.lenient); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the install-config")
+	}
+	defaults.SetInstallConfigDefaults(config)
+	return config, nil
+}
+
+// runPlatformChecks runs only the live checks against the infrastructure of the
+// install-config in the directory and writes the results as JUnit XML.
+func runPlatformChecks(directory string) error {
+	config, err := readInstallConfig(directory)
+	if err != nil {
+		return err
+	}
+
+	if platform := config.Platform.Name(); platform != kubevirt.Name {
+		return errors.Errorf("platform checks are not supported for platform %q", platform)
+	}
+	checks, err := ickubevirt.RunPlatformChecks(config, ickubevirt.ClientBuilder(config.Platform.Kubevirt.InfraKubeconfigPath, config.Platform.Kubevirt.InfraContext, config.Platform.Kubevirt.InfraCABundle))
+	if err != nil {
+		return err
+	}
+
+	suite := formatter.JUnitTestSuite{
+		Name:  "platform-checks",
+		Tests: len(checks),
+		Time:  "0",
+	}
+	for _, check := range checks {
+		testCase := formatter.JUnitTestCase{
+			Classname: config.Platform.Name(),
+			Name:      check.Name,
+			Time:      "0",
+		}
+		switch {
+		case check.Skipped:
+			logrus.Warnf("Platform check %s skipped", check.Name)
+			testCase.SkipMessage = &formatter.JUnitSkipMessage{Message: "skipped because the infra cluster is not reachable"}
+		case len(check.Errors) > 0:
+			messages := make([]string, 0, len(check.Errors))
+			for _, e := range check.Errors {
+				messages = append(messages, e.Error())
+			}
+			logrus.Errorf("Platform check %s failed:\n%s", check.Name, strings.Join(messages, "\n"))
+			testCase.Failure = &formatter.JUnitFailure{
+				Message:  fmt.Sprintf("%s failed", check.Name),
+				Type:     "PlatformCheck",
+				Contents: strings.Join(messages, "\n"),
+			}
+			suite.Failures++
+		default:
+			logrus.Infof("Platform check %s passed", check.Name)
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	output := platformChecksOpts.junitOutput
+	if output == "" {
+		output = filepath.Join(directory, "junit_platform_checks.xml")
+	}
+	report, err := xml.MarshalIndent(formatter.JUnitTestSuites{Suites: []formatter.JUnitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the platform checks report")
+	}
+	if err := ioutil.WriteFile(output, append([]byte(xml.Header), report...), 0644); err != nil {
+		return errors.Wrap(err, "failed to write the platform checks report")
+	}
+	logrus.Infof("Platform checks report written to %s", output)
+
+	if suite.Failures > 0 {
+		return errors.Errorf("%d of %d platform checks failed", suite.Failures, suite.Tests)
+	}
+	return nil
+}
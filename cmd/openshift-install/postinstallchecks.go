@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/postinstall"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	postInstallChecksOpts struct {
+		enabled bool
+		image   string
+	}
+)
+
+// addPostInstallChecksFlags adds the flags enabling the post-install checks to the command
+// waiting for the install to complete.
+func addPostInstallChecksFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&postInstallChecksOpts.enabled, "post-install-checks", false, "once the install completed, verify that the cluster runs a deployment, a route, a persistent volume claim and, on kubevirt, a LoadBalancer service, in a temporary namespace")
+	cmd.Flags().StringVar(&postInstallChecksOpts.image, "post-install-checks-image", postinstall.DefaultImage, "image of the workloads of the post-install checks, serving HTTP on port 8080")
+}
+
+// runPostInstallChecks runs the post-install checks against the cluster, when enabled.
+func runPostInstallChecks(ctx context.Context, config *rest.Config, directory string) ([]postinstall.Result, error) {
+	if !postInstallChecksOpts.enabled {
+		return nil, nil
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a Kubernetes client")
+	}
+	routes, err := routeclient.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a route client")
+	}
+
+	// The KubeVirt cloud controller manager serves the LoadBalancer services of the tenant
+	// clusters
+	options := postinstall.Options{Image: postInstallChecksOpts.image}
+	if assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...); err == nil {
+		if asset, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && asset != nil {
+			options.LoadBalancer = asset.(*installconfig.InstallConfig).Config.Platform.Name() == kubevirt.Name
+		}
+	}
+	return postinstall.Run(ctx, client, routes.RouteV1(), options), nil
+}
+
+// logPostInstallChecks logs the results of the post-install checks.
+func logPostInstallChecks(results []postinstall.Result) {
+	if len(results) == 0 {
+		return
+	}
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			logrus.Warnf("Post-install check %s skipped: %s", result.Name, result.Message)
+		case !result.Passed:
+			logrus.Errorf("Post-install check %s failed: %s", result.Name, result.Message)
+		default:
+			logrus.Infof("Post-install check %s passed in %s", result.Name, result.Duration.Round(time.Second))
+		}
+	}
+	if failed := postinstall.Failed(results); failed > 0 {
+		logrus.Errorf("%d of %d post-install checks failed", failed, len(results))
+	} else {
+		logrus.Info("The post-install checks passed")
+	}
+}
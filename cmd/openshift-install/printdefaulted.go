@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+var (
+	printDefaultedOpts struct {
+		enabled bool
+	}
+)
+
+// printDefaultedInstallConfig prints the install-config among the fetched targets, with all
+// the defaults applied by the installer and the secrets redacted.
+func printDefaultedInstallConfig(targets []asset.WritableAsset) error {
+	for _, a := range targets {
+		installConfig, ok := a.(*installconfig.InstallConfig)
+		if !ok || installConfig.Config == nil {
+			continue
+		}
+		data, err := installconfig.RedactedYAML(*installConfig.Config)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the defaulted install-config")
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+	return errors.New("no install-config was fetched")
+}
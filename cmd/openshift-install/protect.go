@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+var (
+	protectOpts struct {
+		disable  bool
+		onCreate bool
+	}
+)
+
+func newProtectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "protect",
+		Short: "Protect the cluster against deletion",
+		Long: `Protect the cluster against deletion, so that destroy cluster refuses to
+delete it unless --override-protection is given.
+
+The protection is recorded in metadata.json and, on the platforms supporting
+it, on a marker resource of the cluster, so that it also holds for the other
+copies of metadata.json.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := setClusterProtection(rootOpts.dir, !protectOpts.disable); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&protectOpts.disable, "disable", false, "remove the protection of the cluster instead")
+	return cmd
+}
+
+// setClusterProtection records the deletion protection of the cluster on its marker resource,
+// when the platform supports it, and in its metadata.
+func setClusterProtection(directory string, protected bool) error {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	if err != nil {
+		return err
+	}
+	if marker, ok := destroyer.(providers.ProtectionMarker); ok {
+		if err := marker.SetProtected(protected); err != nil {
+			return errors.Wrap(err, "failed to mark the protection of the cluster")
+		}
+	} else {
+		logrus.Warnf("Platform %s has no marker resource, the protection is only recorded in metadata.json", metadata.Platform())
+	}
+
+	metadata.Protected = protected
+	if err := saveClusterMetadata(directory, metadata); err != nil {
+		return errors.Wrap(err, "failed to save the cluster metadata")
+	}
+	if protected {
+		logrus.Info("The cluster is protected against deletion")
+	} else {
+		logrus.Info("The cluster is no longer protected against deletion")
+	}
+	return nil
+}
+
+// checkClusterProtection fails if the cluster is protected against deletion, either in its
+// metadata or on its marker resource, unless the protection is overridden.
+func checkClusterProtection(directory string, destroyer providers.Destroyer, override bool) error {
+	if override {
+		logrus.Warn("Overriding the deletion protection of the cluster")
+		return nil
+	}
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	protected := metadata.Protected
+	if marker, ok := destroyer.(providers.ProtectionMarker); ok && !protected {
+		if protected, err = marker.Protected(); err != nil {
+			return errors.Wrap(err, "failed to check the protection of the cluster")
+		}
+	}
+	if protected {
+		return errors.New("the cluster is protected against deletion, remove the protection with 'openshift-install protect --disable' or override it with --override-protection")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// fakeDestroyer is a destroyer without a protection marker.
+type fakeDestroyer struct{}
+
+func (d *fakeDestroyer) Run() error {
+	return nil
+}
+
+// fakeMarkerDestroyer is a destroyer whose marker resource records the protection.
+type fakeMarkerDestroyer struct {
+	fakeDestroyer
+	protected bool
+	err       error
+}
+
+func (d *fakeMarkerDestroyer) SetProtected(protected bool) error {
+	d.protected = protected
+	return d.err
+}
+
+func (d *fakeMarkerDestroyer) Protected() (bool, error) {
+	return d.protected, d.err
+}
+
+func TestCheckClusterProtection(t *testing.T) {
+	const protectedError = "the cluster is protected against deletion, remove the protection with 'openshift-install protect --disable' or override it with --override-protection"
+	cases := []struct {
+		name          string
+		protected     bool
+		destroyer     providers.Destroyer
+		override      bool
+		expectedError string
+	}{
+		{
+			name:      "not protected",
+			destroyer: &fakeDestroyer{},
+		},
+		{
+			name:          "protected in the metadata",
+			protected:     true,
+			destroyer:     &fakeDestroyer{},
+			expectedError: protectedError,
+		},
+		{
+			name:          "protected in the metadata without marker",
+			protected:     true,
+			destroyer:     &fakeMarkerDestroyer{},
+			expectedError: protectedError,
+		},
+		{
+			name:          "protected by the marker",
+			destroyer:     &fakeMarkerDestroyer{protected: true},
+			expectedError: protectedError,
+		},
+		{
+			name:          "marker unreadable",
+			destroyer:     &fakeMarkerDestroyer{err: errors.New("forbidden")},
+			expectedError: "failed to check the protection of the cluster: forbidden",
+		},
+		{
+			name:      "protection in the metadata overridden",
+			protected: true,
+			destroyer: &fakeDestroyer{},
+			override:  true,
+		},
+		{
+			name:      "protection by the marker overridden",
+			destroyer: &fakeMarkerDestroyer{protected: true, err: errors.New("forbidden")},
+			override:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "TestCheckClusterProtection")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			if err := saveClusterMetadata(dir, &types.ClusterMetadata{InfraID: "infra-id", Protected: tc.protected}); err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkClusterProtection(dir, tc.destroyer, tc.override)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+var (
+	relabelOpts struct {
+		dryRun bool
+		force  bool
+	}
+)
+
+func newRelabelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relabel",
+		Short: "Restore the labels of metadata.json on the resources of the cluster",
+		Long: `Restore the labels of metadata.json on the resources of the cluster which
+lost them, e.g. after their labels were edited manually, so that destroy
+cluster finds and deletes them again.
+
+The resources of the cluster are those named after the infra ID of the
+cluster, or carrying any of the label keys of metadata.json.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runRelabelCmd(rootOpts.dir, relabelOpts.dryRun, relabelOpts.force); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&relabelOpts.dryRun, "dry-run", false, "List the resources missing labels without relabeling them")
+	cmd.Flags().BoolVar(&relabelOpts.force, "force", false, "Continue past resources which cannot be relabeled due to missing permissions, and report them at the end")
+	return cmd
+}
+
+func runRelabelCmd(directory string, dryRun bool, force bool) error {
+	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	relabeler, ok := destroyer.(providers.Relabeler)
+	if !ok {
+		return errors.New("relabeling is not supported for the platform of this cluster")
+	}
+	if force {
+		forceDestroyer, ok := destroyer.(providers.ForceDestroyer)
+		if !ok {
+			return errors.New("--force is not supported for the platform of this cluster")
+		}
+		forceDestroyer.SetForce(true)
+	}
+	relabeled, err := relabeler.Relabel(dryRun)
+	if err != nil {
+		return errors.Wrap(err, "failed to relabel the cluster")
+	}
+	switch {
+	case len(relabeled) == 0:
+		logrus.Info("All the resources of the cluster have the labels of metadata.json")
+	case dryRun:
+		logrus.Infof("The following resources are missing the labels of metadata.json:\n%s", strings.Join(relabeled, "\n"))
+	default:
+		logrus.Infof("Relabeled %d resources of the cluster", len(relabeled))
+	}
+	return nil
+}
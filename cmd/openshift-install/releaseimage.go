@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+)
+
+var (
+	releaseImageOpts struct {
+		pullSpec           string
+		signatureStores    []string
+		keyring            string
+		insecureSkipVerify bool
+	}
+)
+
+func addReleaseImageFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&releaseImageOpts.pullSpec, "release-image", "", "release image to install, referenced by digest, instead of the one embedded in the installer (takes precedence over OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE)")
+	cmd.PersistentFlags().StringArrayVar(&releaseImageOpts.signatureStores, "release-image-signature-store", []string{releaseimage.DefaultSignatureStore}, "URL of a store serving the signatures of --release-image (can be repeated)")
+	cmd.PersistentFlags().StringVar(&releaseImageOpts.keyring, "release-image-keyring", "", "file of the OpenPGP keyring, armored or binary, trusted to sign --release-image")
+	cmd.PersistentFlags().BoolVar(&releaseImageOpts.insecureSkipVerify, "insecure-skip-release-image-verification", false, "install --release-image without verifying its signature")
+}
+
+// setReleaseImage verifies the signature of the --release-image, and overrides the release image
+// with it. It is a no-op without --release-image.
+func setReleaseImage() error {
+	pullSpec := releaseImageOpts.pullSpec
+	if pullSpec == "" {
+		return nil
+	}
+	if _, err := releaseimage.DigestPullSpec(pullSpec); err != nil {
+		return err
+	}
+	switch {
+	case releaseImageOpts.insecureSkipVerify:
+		logrus.Warnf("Not verifying the signature of the release image %s", pullSpec)
+	case releaseImageOpts.keyring == "":
+		return errors.New("--release-image-keyring is required to verify the signature of --release-image, or set --insecure-skip-release-image-verification")
+	default:
+		keyring, err := releaseimage.LoadKeyring(releaseImageOpts.keyring)
+		if err != nil {
+			return err
+		}
+		if err := releaseimage.VerifySignature(context.Background(), pullSpec, releaseImageOpts.signatureStores, keyring); err != nil {
+			return err
+		}
+		logrus.Infof("Verified the signature of the release image %s", pullSpec)
+	}
+	releaseimage.SetOverride(pullSpec)
+	return nil
+}
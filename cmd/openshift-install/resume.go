@@ -0,0 +1,87 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+var (
+	resumeOpts struct {
+		force bool
+	}
+)
+
+func newResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a cluster stopped with destroy cluster --mode=stop",
+		Long: `Resume a cluster stopped with 'openshift-install destroy cluster --mode=stop',
+starting its machines again.
+
+Once the machines are started, 'openshift-install wait-for install-complete'
+waits for the cluster to be available again.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			hibernator, err := newHibernator(rootOpts.dir, resumeOpts.force, 0)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := hibernator.Resume(); err != nil {
+				logrus.Fatal(errors.Wrap(err, "failed to resume the cluster"))
+			}
+			logrus.Info("The machines of the cluster are started")
+		},
+	}
+	cmd.Flags().BoolVar(&resumeOpts.force, "force", false, "Continue past machines which cannot be started due to missing permissions, and report them at the end")
+	return cmd
+}
+
+// runStopCmd stops the machines of the cluster, keeping them and the assets of the cluster for
+// it to be resumed.
+func runStopCmd(directory string, force bool, gracePeriod time.Duration) error {
+	hibernator, err := newHibernator(directory, force, gracePeriod)
+	if err != nil {
+		return err
+	}
+	if err := hibernator.Stop(); err != nil {
+		return errors.Wrap(err, "failed to stop the cluster")
+	}
+	logrus.Info("The machines of the cluster are stopped, resume it with 'openshift-install resume'")
+	return nil
+}
+
+// newHibernator returns the destroyer of the cluster, if it can stop and resume the cluster.
+func newHibernator(directory string, force bool, gracePeriod time.Duration) (providers.Hibernator, error) {
+	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	hibernator, ok := destroyer.(providers.Hibernator)
+	if !ok {
+		return nil, errors.New("stopping and resuming is not supported for the platform of this cluster")
+	}
+	if force {
+		forceDestroyer, ok := destroyer.(providers.ForceDestroyer)
+		if !ok {
+			return nil, errors.New("--force is not supported for the platform of this cluster")
+		}
+		forceDestroyer.SetForce(true)
+	}
+	if gracePeriod > 0 {
+		gracePeriodDestroyer, ok := destroyer.(providers.GracePeriodDestroyer)
+		if !ok {
+			return nil, errors.New("--grace-period is not supported for the platform of this cluster")
+		}
+		gracePeriodDestroyer.SetGracePeriod(gracePeriod)
+	}
+	return hibernator, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/scale"
+)
+
+var (
+	scaleOpts struct {
+		installConfig string
+		dryRun        bool
+	}
+)
+
+func newScaleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Apply the changes of the compute machine pools to the running cluster",
+		Long: `Compare the compute machine pools of a modified install-config with the
+MachineSets of the running cluster, using the kubeconfig of the assets
+directory, and update the MachineSets to match the replicas, CPU, memory
+and storage of the pools.
+
+The pools are validated as in the install-config validation. Adding
+machine pools is not supported. The size changes only apply to the
+machines created afterwards, the existing ones keep their size until
+they are replaced. Only the KubeVirt platform is supported.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runScaleCmd(rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&scaleOpts.installConfig, "install-config", "", "modified install-config file (defaults to install-config.yaml in the assets directory)")
+	cmd.Flags().BoolVar(&scaleOpts.dryRun, "dry-run", false, "only print the changes which would be applied")
+	return cmd
+}
+
+func runScaleCmd(directory string) error {
+	path := scaleOpts.installConfig
+	if path == "" {
+		path = filepath.Join(directory, "install-config.yaml")
+	}
+	config, err := readInstallConfigFile(path)
+	if err != nil {
+		return err
+	}
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the cluster metadata")
+	}
+	restConfig, err := loadKubeconfig(directory)
+	if err != nil {
+		return errors.Wrap(err, "loading kubeconfig")
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cluster client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	machineSets, err := scale.ListMachineSets(ctx, client, metadata.InfraID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the MachineSets")
+	}
+	changes, err := scale.Plan(config, metadata.InfraID, machineSets)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		logrus.Info("The MachineSets match the compute machine pools")
+		return nil
+	}
+	for _, change := range changes {
+		logrus.Infof("MachineSet %s of machine pool %s: %s", change.MachineSet.Name, change.Pool, strings.Join(change.Diffs, ", "))
+	}
+	if scaleOpts.dryRun {
+		return nil
+	}
+	if err := scale.Apply(ctx, client, changes); err != nil {
+		return err
+	}
+	logrus.Infof("%d MachineSets were updated", len(changes))
+	return nil
+}
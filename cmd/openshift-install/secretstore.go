@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	"github.com/openshift/installer/pkg/asset/password"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/secretstore"
+)
+
+// newSecretStore returns the secret store selected with --secret-store. The kubernetes-secret
+// store keeps its Secret in the infra cluster of a KubeVirt cluster, with the kubeconfig and
+// context of the infra cluster of the metadata of the directory.
+func newSecretStore(directory string) (secretstore.Store, error) {
+	var kubeconfig secretstore.Kubeconfig
+	if metadata, err := cluster.LoadMetadata(directory); err == nil && metadata.Kubevirt != nil {
+		kubeconfig.Path = metadata.Kubevirt.InfraKubeconfigPath
+		kubeconfig.Context = metadata.Kubevirt.InfraContext
+	}
+	return secretstore.New(rootOpts.secretStore, directory, kubeconfig)
+}
+
+// storeCredentials moves the credentials written to the auth directory into the
+// secret store selected with --secret-store. When the secret store is not the auth
+// directory, the credentials are removed from the state file too.
+func storeCredentials(directory string) error {
+	store, err := newSecretStore(directory)
+	if err != nil {
+		return err
+	}
+	fileStore, err := secretstore.New("file", directory, secretstore.Kubeconfig{})
+	if err != nil {
+		return err
+	}
+	for _, name := range secretstore.Names {
+		path := fileStore.Location(name)
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := store.Write(name, data); err != nil {
+			return errors.Wrapf(err, "failed to store %s", name)
+		}
+		if !secretstore.IsFile(store) {
+			if err := os.Remove(path); err != nil {
+				return errors.Wrapf(err, "failed to remove %s", path)
+			}
+		}
+	}
+	if secretstore.IsFile(store) {
+		return nil
+	}
+	return errors.Wrap(redactCredentials(directory), "failed to remove the credentials from the state file")
+}
+
+// redactCredentials removes the credentials from the state file of the directory. The hash of
+// the kubeadmin password is kept, the cluster is configured with it, and the admin kubeconfig
+// is removed, it is generated again from the certificates if it is needed.
+func redactCredentials(directory string) error {
+	return assetstore.UpdateState(directory, func(a asset.Asset) bool {
+		if kubeadminPassword, ok := a.(*password.KubeadminPassword); ok {
+			kubeadminPassword.Password = ""
+			kubeadminPassword.File = nil
+			return true
+		}
+		return false
+	}, &password.KubeadminPassword{}, &kubeconfig.AdminClient{})
+}
+
+// loadKubeconfig returns the client configuration of the admin kubeconfig from the secret store.
+func loadKubeconfig(directory string) (*rest.Config, error) {
+	store, err := newSecretStore(directory)
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Read(secretstore.KubeconfigName)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.RESTConfigFromKubeConfig(data)
+}
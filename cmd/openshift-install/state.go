@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+var (
+	stateShowOpts struct {
+		includeSecrets bool
+	}
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect the assets stored in the state file",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newStateListCmd())
+	cmd.AddCommand(newStateShowCmd())
+	return cmd
+}
+
+func newStateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the assets stored in the state file",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			names, err := assetstore.StateAssetNames(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			for _, name := range names {
+				fmt.Println(name)
+			}
+		},
+	}
+}
+
+func newStateShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show ASSET",
+		Short: "Print an asset stored in the state file",
+		Long: `Print the JSON of an asset stored in the state file, with the contents
+of its text files decoded.
+
+The asset is named as in the state file, with or without its package and
+its leading "*", case-insensitively: for example *installconfig.InstallConfig,
+installconfig.installconfig and InstallConfig all name the install-config.
+Use "state list" to list the stored assets.
+
+"state show machines" prints instead the infra nodes and zones the machines
+of the cluster were scheduled to, as recorded in metadata.json.
+
+The passwords, keys, tokens, pull secrets and the files holding them, also
+in the data URLs of the files, are redacted unless --include-secrets is set.
+The Terraform variables and state, the Ignition configs and the kubeconfigs
+are redacted as a whole.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if strings.EqualFold(args[0], "machines") {
+				if err := showMachinePlacements(rootOpts.dir); err != nil {
+					logrus.Fatal(err)
+				}
+				return
+			}
+			data, err := assetstore.ShowStateAsset(rootOpts.dir, args[0], stateShowOpts.includeSecrets)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if _, err := os.Stdout.Write(data); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&stateShowOpts.includeSecrets, "include-secrets", false, "print the secrets of the asset instead of redacting them")
+	return cmd
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// stateInstallConfig returns the install-config recorded in the state file of the assets
+// directory, e.g. for its timeouts once the install-config file is consumed, or nil when
+// there is none.
+func stateInstallConfig(directory string) *types.InstallConfig {
+	assetStore, err := assetstore.NewStore(directory, assetStoreOptions()...)
+	if err != nil {
+		return nil
+	}
+	installConfig, err := assetStore.Load(&installconfig.InstallConfig{})
+	if err != nil || installConfig == nil {
+		return nil
+	}
+	return installConfig.(*installconfig.InstallConfig).Config
+}
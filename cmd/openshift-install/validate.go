@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types/validation"
+)
+
+var (
+	validateOpts struct {
+		watch    bool
+		interval time.Duration
+		output   string
+	}
+)
+
+const (
+	// validateOutputText logs the validation errors.
+	validateOutputText = "text"
+	// validateOutputJSON prints the validation result on stdout as JSON, with the codes of the
+	// errors.
+	validateOutputJSON = "json"
+)
+
+// validationResult is the JSON output of a validation.
+type validationResult struct {
+	Valid  bool                    `json:"valid"`
+	Errors []validation.CodedError `json:"errors,omitempty"`
+	// Warnings are the non-fatal issues of the valid install-config.
+	Warnings []validation.Warning `json:"warnings,omitempty"`
+}
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the install-config of the assets directory",
+		Long: `Validate the install-config of the assets directory, running the same static
+and platform validations as the create commands, e.g. against the infra cluster
+on KubeVirt, without generating any asset. The issues of a valid
+install-config deviating from the best practices, e.g. a single control plane
+replica, are logged as warnings, and listed apart from the errors with
+--output json.
+
+With --watch, the install-config and the files it includes are watched, and
+validated again each time one of them is saved, until interrupted.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if validateOpts.output != validateOutputText && validateOpts.output != validateOutputJSON {
+				logrus.Fatalf("invalid --output %q, must be %s or %s", validateOpts.output, validateOutputText, validateOutputJSON)
+			}
+			if !validateOpts.watch {
+				if _, err := validateInstallConfig(rootOpts.dir, validateOpts.output); err != nil {
+					if validateOpts.output == validateOutputJSON {
+						logrus.Exit(1)
+					}
+					logrus.Fatal(err)
+				}
+				return
+			}
+			watchInstallConfig(rootOpts.dir, validateOpts.interval, validateOpts.output)
+		},
+	}
+	cmd.Flags().BoolVar(&validateOpts.watch, "watch", false, "validate the install-config again each time it changes")
+	cmd.Flags().DurationVar(&validateOpts.interval, "interval", time.Second, "how often the files are checked for changes with --watch")
+	cmd.Flags().StringVar(&validateOpts.output, "output", validateOutputText, "format of the result (e.g. \"text | json\"), json printing it on stdout with the codes of the errors, one line per validation")
+	return cmd
+}
+
+// validateInstallConfig loads and validates the install-config in the directory, logging the
+// result or printing it in the given output format. It returns the files read, the
+// install-config and the files it includes, to watch.
+func validateInstallConfig(directory string, output string) ([]string, error) {
+	fetcher := &recordingFetcher{directory: directory}
+	installConfig := &installconfig.InstallConfig{}
+	found, err := installConfig.Load(fetcher)
+	if err == nil && !found {
+		err = errors.Errorf("no install-config.yaml in %s", directory)
+	}
+	if output == validateOutputJSON {
+		result := validationResult{Valid: err == nil, Errors: validation.CodedErrors(err)}
+		if err == nil {
+			result.Warnings = validation.Lint(installConfig.Config)
+		}
+		if encodeErr := json.NewEncoder(os.Stdout).Encode(result); encodeErr != nil {
+			return fetcher.names, encodeErr
+		}
+		return fetcher.names, err
+	}
+	if err != nil {
+		if agg, ok := errors.Cause(err).(utilerrors.Aggregate); ok && len(agg.Errors()) > 1 {
+			logrus.Errorf("The install-config is invalid, %d errors found:", len(agg.Errors()))
+			for _, e := range agg.Errors() {
+				logrus.Errorf("  %v", e)
+			}
+		}
+		return fetcher.names, err
+	}
+	logrus.Info("The install-config is valid")
+	return fetcher.names, nil
+}
+
+// watchInstallConfig validates the install-config in the directory each time the files it is
+// read from change, checking them at the interval.
+func watchInstallConfig(directory string, interval time.Duration, output string) {
+	validations := 0
+	validate := func() []string {
+		if validations > 0 && output == validateOutputText {
+			fmt.Println()
+		}
+		validations++
+		names, err := validateInstallConfig(directory, output)
+		if err != nil && output == validateOutputText {
+			logrus.Error(err)
+		}
+		if len(names) == 0 {
+			names = []string{"install-config.yaml"}
+		}
+		return names
+	}
+	watchLoop(context.Background(), interval, validate, func(names []string) map[string]string {
+		return fingerprints(directory, names)
+	})
+}
+
+// watchLoop calls validate, then calls it again each time the fingerprints of the files it
+// returns change, until the context is done. The fingerprints are checked at the interval, and
+// a change is only validated once they are the same for an interval, so that a file saved in
+// several writes, or several files saved together, are validated once.
+func watchLoop(ctx context.Context, interval time.Duration, validate func() []string, fingerprint func(names []string) map[string]string) {
+	for {
+		names := validate()
+		previous := fingerprint(names)
+		logrus.Infof("Watching %d files for changes...", len(previous))
+
+		changed := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			current := fingerprint(names)
+			if !equalFingerprints(previous, current) {
+				changed = true
+				previous = current
+				continue
+			}
+			if changed {
+				break
+			}
+		}
+	}
+}
+
+// fingerprints returns the modification time and the size of the files, or that they are
+// missing, by name.
+func fingerprints(directory string, names []string) map[string]string {
+	prints := make(map[string]string, len(names))
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(directory, name))
+		if err != nil {
+			prints[name] = "missing"
+			continue
+		}
+		prints[name] = fmt.Sprintf("%d %d", info.ModTime().UnixNano(), info.Size())
+	}
+	return prints
+}
+
+func equalFingerprints(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, print := range a {
+		if b[name] != print {
+			return false
+		}
+	}
+	return true
+}
+
+// recordingFetcher fetches the files from the directory, recording the names of the files
+// requested, found or not.
+type recordingFetcher struct {
+	directory string
+	names     []string
+}
+
+// FetchByName returns the file with the given name.
+func (f *recordingFetcher) FetchByName(name string) (*asset.File, error) {
+	f.names = append(f.names, name)
+	data, err := ioutil.ReadFile(filepath.Join(f.directory, name))
+	if err != nil {
+		return nil, err
+	}
+	return &asset.File{Filename: name, Data: data}, nil
+}
+
+// FetchByPattern is not used to load the install-config.
+func (f *recordingFetcher) FetchByPattern(pattern string) ([]*asset.File, error) {
+	return nil, errors.New("not supported")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchLoop(t *testing.T) {
+	cases := []struct {
+		name string
+		// fingerprints are the fingerprints of the files returned by the successive checks,
+		// the first one after each validation, the last one being returned from then on.
+		fingerprints        []string
+		expectedValidations int
+	}{
+		{
+			name:                "no change",
+			fingerprints:        []string{"a"},
+			expectedValidations: 1,
+		},
+		{
+			name:                "single change",
+			fingerprints:        []string{"a", "a", "b"},
+			expectedValidations: 2,
+		},
+		{
+			name:                "changes debounced",
+			fingerprints:        []string{"a", "b", "c", "d", "d"},
+			expectedValidations: 2,
+		},
+		{
+			name:                "changes apart",
+			fingerprints:        []string{"a", "b", "b", "b", "c"},
+			expectedValidations: 3,
+		},
+		{
+			name:                "change reverted",
+			fingerprints:        []string{"a", "b", "a"},
+			expectedValidations: 2,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			checks := 0
+			fingerprint := func(names []string) map[string]string {
+				assert.Equal(t, []string{"install-config.yaml"}, names)
+				i := checks
+				if i >= len(tc.fingerprints) {
+					i = len(tc.fingerprints) - 1
+					// The fingerprints don't change anymore, stop once the last one is validated
+					if checks >= len(tc.fingerprints)+2 {
+						cancel()
+					}
+				}
+				checks++
+				return map[string]string{"install-config.yaml": tc.fingerprints[i]}
+			}
+			validations := 0
+			validate := func() []string {
+				validations++
+				return []string{"install-config.yaml"}
+			}
+
+			done := make(chan struct{})
+			go func() {
+				watchLoop(ctx, time.Millisecond, validate, fingerprint)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("the watch loop did not stop")
+			}
+			assert.Equal(t, tc.expectedValidations, validations)
+		})
+	}
+}
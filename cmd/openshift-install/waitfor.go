@@ -2,13 +2,12 @@ package main
 
 import (
 	"context"
-	"path/filepath"
 
+	"github.com/openshift/installer/pkg/hooks"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 func newWaitForCmd() *cobra.Command {
@@ -26,14 +25,21 @@ them directly.`,
 	}
 	cmd.AddCommand(newWaitForBootstrapCompleteCmd())
 	cmd.AddCommand(newWaitForInstallCompleteCmd())
+	cmd.AddCommand(newWaitForOperatorsCmd())
 	return cmd
 }
 
 func newWaitForBootstrapCompleteCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "bootstrap-complete",
 		Short: "Wait until cluster bootstrapping has completed",
-		Args:  cobra.ExactArgs(0),
+		Long: `Wait until cluster bootstrapping has completed.
+
+When bootstrapping fails from a terminal, the logs of the bootstrap host can be
+gathered and analyzed, printing the probable causes of the failure, and the
+bootstrap resources kept for troubleshooting or destroyed. Use --auto-gather to
+gather and analyze the logs without prompting.`,
+		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
 			timer.StartTimer(timer.TotalTimeElapsed)
 			ctx := context.Background()
@@ -41,19 +47,20 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			config, err := loadKubeconfig(rootOpts.dir)
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
 			timer.StartTimer("Bootstrap Complete")
-			err = waitForBootstrapComplete(ctx, config)
+			err = runStage(ctx, rootOpts.dir, hooks.StageBootstrap, func() error {
+				return waitForBootstrapComplete(ctx, config)
+			})
 			if err != nil {
 				if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 					logrus.Error("Attempted to gather ClusterOperator status after wait failure: ", err2)
 				}
 
-				logrus.Info("Use the following commands to gather logs from the cluster")
-				logrus.Info("openshift-install gather bootstrap --help")
+				recoverBootstrapFailure(rootOpts.dir)
 				logrus.Fatal(err)
 			}
 
@@ -63,10 +70,12 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 			timer.LogSummary()
 		},
 	}
+	addBootstrapRecoveryFlags(cmd)
+	return cmd
 }
 
 func newWaitForInstallCompleteCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "install-complete",
 		Short: "Wait until the cluster is ready",
 		Args:  cobra.ExactArgs(0),
@@ -77,12 +86,14 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			config, err := loadKubeconfig(rootOpts.dir)
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
 
-			err = waitForInstallComplete(ctx, config, rootOpts.dir)
+			err = runStage(ctx, rootOpts.dir, hooks.StageInstall, func() error {
+				return waitForInstallComplete(ctx, config, rootOpts.dir)
+			})
 			if err != nil {
 				if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 					logrus.Error("Attempted to gather ClusterOperator status after wait failure: ", err2)
@@ -94,4 +105,6 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 			timer.LogSummary()
 		},
 	}
+	addPostInstallChecksFlags(cmd)
+	return cmd
 }
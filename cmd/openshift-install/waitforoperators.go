@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/openshift/installer/pkg/featuregates"
+	timer "github.com/openshift/installer/pkg/metrics/timer"
+)
+
+var (
+	waitForOperatorsOpts struct {
+		operators []string
+		selector  string
+		timeout   time.Duration
+	}
+)
+
+func newWaitForOperatorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operators",
+		Short: "Wait until a subset of the ClusterOperators is available",
+		Long: `Wait until a subset of the ClusterOperators is available.
+
+The ClusterOperators are selected by name with --operator, which may set
+the timeout of the operator, and by label with --selector. They are ready
+once Available and neither Progressing nor Degraded.`,
+		Example: `  openshift-install wait-for operators --operator ingress=20m --operator authentication
+  openshift-install wait-for operators --selector 'lab.example.com/tier=network'`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			timer.StartTimer(timer.TotalTimeElapsed)
+			ctx := context.Background()
+
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			timeouts, err := parseOperatorTimeouts(waitForOperatorsOpts.operators, waitForOperatorsOpts.timeout)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			selector, err := labels.Parse(waitForOperatorsOpts.selector)
+			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "invalid --selector"))
+			}
+			if len(timeouts) == 0 && selector.Empty() {
+				logrus.Fatal("no ClusterOperators to wait for, set --operator or --selector")
+			}
+
+			config, err := loadKubeconfig(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
+			}
+			client, err := configclient.NewForConfig(config)
+			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "creating a config client"))
+			}
+			if err := waitForOperators(ctx, client.ConfigV1(), timeouts, selector, waitForOperatorsOpts.timeout); err != nil {
+				if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
+					logrus.Error("Attempted to gather ClusterOperator status after wait failure: ", err2)
+				}
+				logrus.Fatal(err)
+			}
+			timer.StopTimer(timer.TotalTimeElapsed)
+			timer.LogSummary()
+		},
+	}
+	cmd.Flags().StringArrayVar(&waitForOperatorsOpts.operators, "operator", nil, "name of a ClusterOperator to wait for, optionally with its timeout as NAME=TIMEOUT, e.g. ingress=20m (can be repeated)")
+	cmd.Flags().StringVar(&waitForOperatorsOpts.selector, "selector", "", "label selector of the ClusterOperators to wait for")
+	cmd.Flags().DurationVar(&waitForOperatorsOpts.timeout, "timeout", 30*time.Minute, "timeout of the ClusterOperators without their own timeout")
+	return cmd
+}
+
+// parseOperatorTimeouts returns the timeouts of the ClusterOperators by name, from arguments
+// of the form NAME or NAME=TIMEOUT.
+func parseOperatorTimeouts(operators []string, defaultTimeout time.Duration) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(operators))
+	for _, operator := range operators {
+		name, timeout := operator, defaultTimeout
+		if i := strings.Index(operator, "="); i >= 0 {
+			var err error
+			name = operator[:i]
+			timeout, err = time.ParseDuration(operator[i+1:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid timeout of ClusterOperator %s", name)
+			}
+		}
+		if name == "" {
+			return nil, errors.Errorf("invalid --operator %q, the name is empty", operator)
+		}
+		timeouts[name] = timeout
+	}
+	return timeouts, nil
+}
+
+// operatorReady returns whether the ClusterOperator is Available and neither Progressing nor
+// Degraded, with the message of the first condition it is waiting for otherwise.
+func operatorReady(operator *configv1.ClusterOperator) (bool, string) {
+	conditions := operator.Status.Conditions
+	if !cov1helpers.IsStatusConditionTrue(conditions, configv1.OperatorAvailable) {
+		if c := cov1helpers.FindStatusCondition(conditions, configv1.OperatorAvailable); c != nil {
+			return false, "not available: " + c.Message
+		}
+		return false, "not available"
+	}
+	if c := cov1helpers.FindStatusCondition(conditions, configv1.OperatorDegraded); c != nil && c.Status == configv1.ConditionTrue {
+		return false, "degraded: " + c.Message
+	}
+	if c := cov1helpers.FindStatusCondition(conditions, configv1.OperatorProgressing); c != nil && c.Status == configv1.ConditionTrue {
+		return false, "progressing: " + c.Message
+	}
+	return true, ""
+}
+
+// waitForOperators waits for the named ClusterOperators and the ones matching the selector to
+// be ready, each until its own timeout. The operators matching the selector, and the named
+// operators which don't exist yet, are waited for until the default timeout.
+func waitForOperators(ctx context.Context, client configv1client.ClusterOperatorsGetter, timeouts map[string]time.Duration, selector labels.Selector, defaultTimeout time.Duration) error {
+	maxTimeout := defaultTimeout
+	for name, timeout := range timeouts {
+		logrus.Infof("Waiting up to %v for ClusterOperator %s...", timeout, name)
+		if timeout > maxTimeout {
+			maxTimeout = timeout
+		}
+	}
+	if !selector.Empty() {
+		logrus.Infof("Waiting up to %v for the ClusterOperators matching %q...", defaultTimeout, selector)
+	}
+
+	start := time.Now()
+	timer.StartTimer("Cluster Operators")
+	ready := map[string]bool{}
+	condition := func() (bool, error) {
+		operators, err := client.ClusterOperators().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			err = errors.Wrap(err, "listing ClusterOperator objects")
+			if time.Since(start) > maxTimeout {
+				return false, err
+			}
+			logrus.Debug(err)
+			return false, nil
+		}
+
+		waiting := map[string]string{}
+		for name := range timeouts {
+			waiting[name] = "not found"
+		}
+		matched := false
+		for i := range operators.Items {
+			operator := &operators.Items[i]
+			_, named := timeouts[operator.Name]
+			selected := !selector.Empty() && selector.Matches(labels.Set(operator.Labels))
+			if !named && !selected {
+				continue
+			}
+			matched = matched || selected
+			delete(waiting, operator.Name)
+			if ok, message := operatorReady(operator); !ok {
+				waiting[operator.Name] = message
+			} else if !ready[operator.Name] {
+				ready[operator.Name] = true
+				logrus.Infof("ClusterOperator %s is ready after %v", operator.Name, time.Since(start).Round(time.Second))
+			}
+		}
+		if !selector.Empty() && !matched {
+			waiting[selector.String()] = "no ClusterOperator matches the selector"
+		}
+		if len(waiting) == 0 {
+			return true, nil
+		}
+
+		var timedOut []string
+		for name, message := range waiting {
+			logrus.Debugf("Still waiting for ClusterOperator %s: %s", name, message)
+			timeout, ok := timeouts[name]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if time.Since(start) > timeout {
+				timedOut = append(timedOut, name+" ("+message+")")
+			}
+		}
+		if len(timedOut) > 0 {
+			sort.Strings(timedOut)
+			return false, errors.Errorf("timed out waiting for the ClusterOperators: %s", strings.Join(timedOut, ", "))
+		}
+		return false, nil
+	}
+	var err error
+	if featuregates.Enabled(featuregates.WatchWaits) {
+		err = pollOnWatch(ctx, client.ClusterOperators().Watch, 10*time.Second, condition)
+	} else {
+		err = wait.PollImmediateUntil(10*time.Second, condition, ctx.Done())
+	}
+	if err != nil {
+		return err
+	}
+	timer.StopTimer("Cluster Operators")
+	return nil
+}
+
+// pollOnWatch checks the condition right away, then each time the watch reports a change and
+// at least at the interval, until it is done or fails or the context is done. The watch is
+// restarted when it closes, falling back to the interval while it fails.
+func pollOnWatch(ctx context.Context, watchFunc func(context.Context, metav1.ListOptions) (watch.Interface, error), interval time.Duration, condition wait.ConditionFunc) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var watcher watch.Interface
+	defer func() {
+		if watcher != nil {
+			watcher.Stop()
+		}
+	}()
+	for {
+		if done, err := condition(); err != nil || done {
+			return err
+		}
+		if watcher == nil {
+			var err error
+			if watcher, err = watchFunc(ctx, metav1.ListOptions{}); err != nil {
+				logrus.Debugf("Failed to watch, polling instead: %v", err)
+				watcher = nil
+			}
+		}
+		var events <-chan watch.Event
+		if watcher != nil {
+			events = watcher.ResultChan()
+		}
+		select {
+		case <-ctx.Done():
+			return wait.ErrWaitTimeout
+		case <-ticker.C:
+		case _, ok := <-events:
+			if !ok {
+				watcher.Stop()
+				watcher = nil
+			}
+		}
+	}
+}
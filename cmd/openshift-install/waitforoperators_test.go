@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeClusterOperators is a config client listing the ClusterOperators it holds; its other
+// methods are not implemented.
+type fakeClusterOperators struct {
+	configv1client.ClusterOperatorInterface
+	operators []configv1.ClusterOperator
+}
+
+func (f *fakeClusterOperators) ClusterOperators() configv1client.ClusterOperatorInterface {
+	return f
+}
+
+func (f *fakeClusterOperators) List(ctx context.Context, opts metav1.ListOptions) (*configv1.ClusterOperatorList, error) {
+	return &configv1.ClusterOperatorList{Items: f.operators}, nil
+}
+
+func clusterOperator(name string, operatorLabels map[string]string, conditions ...configv1.ClusterOperatorStatusCondition) configv1.ClusterOperator {
+	return configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: operatorLabels},
+		Status:     configv1.ClusterOperatorStatus{Conditions: conditions},
+	}
+}
+
+var (
+	operatorAvailable = configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}
+	operatorDegraded  = configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Message: "pods crashing"}
+	networkTier       = map[string]string{"lab.example.com/tier": "network"}
+)
+
+func TestWaitForOperators(t *testing.T) {
+	operators := []configv1.ClusterOperator{
+		clusterOperator("ingress", nil, operatorAvailable),
+		clusterOperator("dns", networkTier, operatorAvailable),
+		clusterOperator("network", networkTier, operatorAvailable),
+		// Not ready, but neither named nor selected by the cases which succeed
+		clusterOperator("authentication", nil, operatorAvailable, operatorDegraded),
+		clusterOperator("console", nil),
+	}
+	cases := []struct {
+		name          string
+		operators     []string
+		selector      string
+		expectedError string
+	}{
+		{
+			name:      "named operators",
+			operators: []string{"ingress", "dns=1h"},
+		},
+		{
+			name:     "selected operators",
+			selector: "lab.example.com/tier=network",
+		},
+		{
+			name:      "named and selected operators",
+			operators: []string{"ingress"},
+			selector:  "lab.example.com/tier=network",
+		},
+		{
+			name:          "named operator not ready",
+			operators:     []string{"ingress", "authentication"},
+			expectedError: "timed out waiting for the ClusterOperators: authentication (degraded: pods crashing)",
+		},
+		{
+			name:          "named operators missing or not available",
+			operators:     []string{"console", "storage"},
+			expectedError: "timed out waiting for the ClusterOperators: console (not available), storage (not found)",
+		},
+		{
+			name:          "selector matching no operator",
+			selector:      "lab.example.com/tier=storage",
+			expectedError: "timed out waiting for the ClusterOperators: lab.example.com/tier=storage (no ClusterOperator matches the selector)",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The operators not ready time out at once, as their timeout is zero
+			timeouts, err := parseOperatorTimeouts(tc.operators, 0)
+			if !assert.NoError(t, err) {
+				return
+			}
+			selector, err := labels.Parse(tc.selector)
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = waitForOperators(context.Background(), &fakeClusterOperators{operators: operators}, timeouts, selector, 0)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestParseOperatorTimeouts(t *testing.T) {
+	timeouts, err := parseOperatorTimeouts([]string{"ingress=20m", "authentication"}, 30*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"ingress": 20 * time.Minute, "authentication": 30 * time.Minute}, timeouts)
+
+	_, err = parseOperatorTimeouts([]string{"=20m"}, 30*time.Minute)
+	assert.EqualError(t, err, `invalid --operator "=20m", the name is empty`)
+}
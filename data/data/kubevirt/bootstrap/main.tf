@@ -12,7 +12,7 @@ EOF
 data "ignition_config" "bootstrap_ignition_config" {
 
   merge {
-    source = "data:text/plain;charset=utf-8;base64,${base64encode(var.ignition_data)}"
+    source = var.ignition_url != "" ? var.ignition_url : "data:text/plain;charset=utf-8;base64,${base64encode(var.ignition_data)}"
   }
 
   files = [
@@ -20,14 +20,33 @@ data "ignition_config" "bootstrap_ignition_config" {
   ]
 }
 
+locals {
+  # The ignition_config data source doesn't set the compression of the merged config, the
+  # user data embedding the compressed config is rendered here
+  compressed_ignition_config = jsonencode({
+    ignition = {
+      version = "3.1.0"
+      config = {
+        merge = [{
+          source      = "data:;base64,${base64gzip(var.ignition_data)}"
+          compression = "gzip"
+        }]
+      }
+    }
+    storage = {
+      files = [jsondecode(element(data.ignition_file.hostname.*.rendered, 0))]
+    }
+  })
+}
+
 resource "kubernetes_secret" "bootstrap_ignition" {
   metadata {
     name = "${var.cluster_id}-bootstrap-ignition"
     namespace = var.namespace
-    labels = var.labels
+    labels = merge(var.labels, var.run_labels)
   }
   data = {
-    "userdata" = element(
+    "userdata" = var.ignition_gzip ? local.compressed_ignition_config : element(
       data.ignition_config.bootstrap_ignition_config.*.rendered,
       0,
     )
@@ -36,17 +55,24 @@ resource "kubernetes_secret" "bootstrap_ignition" {
 
 resource "kubevirt_virtual_machine" "bootstrap_vm" {
 
+  # The run strategy is switched after the creation by the installer for the Manual
+  # strategy, and by the hibernation of the cluster.
+  lifecycle {
+    ignore_changes = [spec[0].run_strategy]
+  }
+
   metadata {
     name = "${var.cluster_id}-bootstrap"
     namespace = var.namespace
-    labels = var.labels
+    labels = merge(var.labels, var.run_labels)
   }
   spec {
-    run_strategy = "Always"
+    run_strategy = var.run_strategy
     data_volume_templates {
       metadata {
         name = "${var.cluster_id}-bootstrap-bootvolume"
         namespace = var.namespace
+        annotations = var.data_volume_annotations
       }
       spec {
         source {
@@ -68,9 +94,9 @@ resource "kubevirt_virtual_machine" "bootstrap_vm" {
     }
     template {
       metadata {
-        labels = {
+        labels = merge(var.labels, {
           "kubevirt.io/vm" = "${var.cluster_id}-bootstrap"
-        }
+        })
       }
       spec {
         volume {
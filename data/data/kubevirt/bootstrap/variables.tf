@@ -27,6 +27,18 @@ variable "ignition_data" {
   description = "Ignition config file contents of the bootstrap VM"
 }
 
+variable "ignition_url" {
+  type        = string
+  default     = ""
+  description = "The URL the bootstrap VM fetches its Ignition config from, instead of embedding ignition_data"
+}
+
+variable "ignition_gzip" {
+  type        = bool
+  default     = false
+  description = "Whether ignition_data is embedded compressed with gzip"
+}
+
 variable "storage_class" {
   type        = string
   description = "The \"class\" of the storage located in the infracluster"
@@ -58,3 +70,21 @@ EOF
 
   default = {}
 }
+
+variable "run_labels" {
+  type        = map(string)
+  default     = {}
+  description = "Labels identifying the run of the installer, set on the metadata of the resources only"
+}
+
+variable "run_strategy" {
+  type        = string
+  default     = "Always"
+  description = "The run strategy the bootstrap VM is created with [Always,RerunOnFailure]"
+}
+
+variable "data_volume_annotations" {
+  type        = map(string)
+  default     = {}
+  description = "The annotations tagging the data volumes of the VM"
+}
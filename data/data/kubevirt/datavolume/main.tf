@@ -1,8 +1,9 @@
 resource "kubevirt_data_volume" "data_volume" {
   metadata {
-    name      = var.pvc_name
-    namespace = var.namespace
-    labels    = var.labels
+    name        = var.pvc_name
+    namespace   = var.namespace
+    labels      = merge(var.labels, var.run_labels)
+    annotations = var.annotations
   }
   spec {
     source {
@@ -20,5 +21,9 @@ resource "kubevirt_data_volume" "data_volume" {
       storage_class_name = var.storage_class
     }
   }
+
+  timeouts {
+    create = var.import_timeout
+  }
 }
 
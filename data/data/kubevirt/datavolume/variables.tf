@@ -39,3 +39,21 @@ EOF
 
   default = {}
 }
+
+variable "run_labels" {
+  type        = map(string)
+  default     = {}
+  description = "Labels identifying the run of the installer, set on the metadata of the resources only"
+}
+
+variable "annotations" {
+  type        = map(string)
+  default     = {}
+  description = "The annotations of the data volume, tuning its CDI import"
+}
+
+variable "import_timeout" {
+  type        = string
+  default     = "20m"
+  description = "How long the import of the image into the data volume is waited for, e.g. 40m"
+}
@@ -1,7 +1,11 @@
 provider "kubernetes" {
+  config_path    = var.kubevirt_kubeconfig_path
+  config_context = var.kubevirt_kubeconfig_context
 }
 
 provider "kubevirt" {
+  config_path    = var.kubevirt_kubeconfig_path
+  config_context = var.kubevirt_kubeconfig_context
 }
 
 module "datavolume" {
@@ -9,16 +13,20 @@ module "datavolume" {
   pvc_name       = var.kubevirt_source_pvc_name
   namespace      = var.kubevirt_namespace
   labels         = var.kubevirt_labels
+  run_labels     = var.kubevirt_run_labels
   storage        = "20Gi"
   pv_access_mode = var.kubevirt_pv_access_mode
   storage_class  = var.kubevirt_storage_class
   image_url      = var.kubevirt_image_url
+  annotations    = merge(var.kubevirt_import_annotations, var.kubevirt_data_volume_annotations)
+  import_timeout = var.kubevirt_image_import_timeout
 }
 
 module "masters" {
   source         = "./masters"
   master_count   = var.master_count
   cluster_id     = var.cluster_id
+  name_prefix    = var.kubevirt_master_name_prefix
   ignition_data  = var.ignition_master
   namespace      = var.kubevirt_namespace
   storage        = var.kubevirt_master_storage
@@ -28,20 +36,42 @@ module "masters" {
   network_name   = var.kubevirt_network_name
   pv_access_mode = var.kubevirt_pv_access_mode
   labels         = var.kubevirt_labels
+  run_labels     = var.kubevirt_run_labels
   pvc_name       = module.datavolume.pvc_name
+
+  memory_limit                     = var.kubevirt_master_memory_limit
+  overcommit_guest_overhead        = var.kubevirt_master_overcommit_guest_overhead
+  spread_policy                    = var.kubevirt_master_spread_policy
+  disk_bus                         = var.kubevirt_master_disk_bus
+  etcd_disk_size                   = var.kubevirt_master_etcd_disk_size
+  etcd_disk_storage_class          = var.kubevirt_master_etcd_disk_storage_class
+  cpu_model                        = var.kubevirt_master_cpu_model
+  cpu_features                     = var.kubevirt_master_cpu_features
+  hugepages_page_size              = var.kubevirt_master_hugepages_page_size
+  run_strategy                     = var.kubevirt_master_run_strategy
+  termination_grace_period_seconds = var.kubevirt_master_termination_grace_period_seconds
+  priority_class_name              = var.kubevirt_master_priority_class_name
+  min_available                    = var.kubevirt_master_min_available
+  data_volume_annotations          = var.kubevirt_data_volume_annotations
 }
 
 module "bootstrap" {
   source         = "./bootstrap"
   cluster_id     = var.cluster_id
   ignition_data  = var.ignition_bootstrap
+  ignition_url   = var.kubevirt_bootstrap_ignition_url
+  ignition_gzip  = var.kubevirt_bootstrap_ignition_gzip
   namespace      = var.kubevirt_namespace
-  storage        = "35Gi"
-  memory         = "8G"
-  cpu            = "4"
+  storage        = var.kubevirt_bootstrap_storage
+  memory         = var.kubevirt_bootstrap_memory
+  cpu            = var.kubevirt_bootstrap_cpu
   storage_class  = var.kubevirt_storage_class
   network_name   = var.kubevirt_network_name
   pv_access_mode = var.kubevirt_pv_access_mode
   labels         = var.kubevirt_labels
+  run_labels     = var.kubevirt_run_labels
   pvc_name       = module.datavolume.pvc_name
+  run_strategy   = var.kubevirt_bootstrap_run_strategy
+
+  data_volume_annotations = var.kubevirt_data_volume_annotations
 }
@@ -5,7 +5,7 @@ data "ignition_file" "hostname" {
 
   content {
     content = <<EOF
-${var.cluster_id}-master-${count.index}
+${var.name_prefix}-master-${count.index}
 EOF
   }
 }
@@ -26,9 +26,9 @@ resource "kubernetes_secret" "master_ignition" {
   count = var.master_count
 
   metadata {
-    name = "${var.cluster_id}-master-${count.index}-ignition"
+    name = "${var.name_prefix}-master-${count.index}-ignition"
     namespace = var.namespace
-    labels = var.labels
+    labels = merge(var.labels, var.run_labels)
   }
   data = {
     "userdata" = element(
@@ -42,22 +42,76 @@ locals {
   anti_affinity_label = {
     "anti-affinity-tag-${var.cluster_id}" = "master"
   }
+  # The virt-launcher pods of the masters, selected by their disruption budget
+  role_label = {
+    "tenantcluster-${var.cluster_id}-role" = "master"
+  }
+  etcd_disk = var.etcd_disk_size == "" ? [] : [1]
+  cpu       = var.cpu_model == "" && length(var.cpu_features) == 0 ? [] : [1]
+  hugepages = var.hugepages_page_size == "" ? [] : [1]
+}
+
+# The provider has no blank DataVolume source, the etcd disks of the masters are cloned
+# from this empty PVC instead, and their disk image is created by KubeVirt on first boot.
+resource "kubernetes_persistent_volume_claim" "etcd_blank" {
+  count = length(local.etcd_disk)
+
+  metadata {
+    name      = "${var.name_prefix}-master-etcd-blank"
+    namespace = var.namespace
+    labels    = merge(var.labels, var.run_labels)
+  }
+  spec {
+    access_modes = [var.pv_access_mode]
+    resources {
+      requests = {
+        storage = var.etcd_disk_size
+      }
+    }
+    storage_class_name = var.etcd_disk_storage_class
+  }
+  wait_until_bound = false
+}
+
+# Keeps the drains of the infra cluster nodes from evicting more masters than etcd can
+# lose and keep its quorum, see mastersMinAvailable of pkg/tfvars/kubevirt.
+resource "kubernetes_pod_disruption_budget" "masters" {
+  count = var.min_available > 0 ? 1 : 0
+
+  metadata {
+    name      = "${var.name_prefix}-masters"
+    namespace = var.namespace
+    labels    = merge(var.labels, var.run_labels)
+  }
+  spec {
+    min_available = var.min_available
+    selector {
+      match_labels = merge(var.labels, local.role_label)
+    }
+  }
 }
 
 resource "kubevirt_virtual_machine" "master_vm" {
   count = var.master_count
 
+  # The run strategy is switched after the creation by the installer for the Manual
+  # strategy, and by the hibernation of the cluster.
+  lifecycle {
+    ignore_changes = [spec[0].run_strategy]
+  }
+
   metadata {
-    name = "${var.cluster_id}-master-${count.index}"
+    name = "${var.name_prefix}-master-${count.index}"
     namespace = var.namespace
-    labels = merge(var.labels, local.anti_affinity_label)
+    labels = merge(var.labels, var.run_labels, local.anti_affinity_label)
   }
   spec {
-    run_strategy = "Always"
+    run_strategy = var.run_strategy
     data_volume_templates {
       metadata {
-        name = "${var.cluster_id}-master-${count.index}-bootvolume"
+        name = "${var.name_prefix}-master-${count.index}-bootvolume"
         namespace = var.namespace
+        annotations = var.data_volume_annotations
       }
       spec {
         source {
@@ -77,23 +131,61 @@ resource "kubevirt_virtual_machine" "master_vm" {
         }
       }
     }
+    dynamic "data_volume_templates" {
+      for_each = local.etcd_disk
+      content {
+        metadata {
+          name        = "${var.name_prefix}-master-${count.index}-etcdvolume"
+          namespace   = var.namespace
+          annotations = var.data_volume_annotations
+        }
+        spec {
+          source {
+            pvc {
+              name      = kubernetes_persistent_volume_claim.etcd_blank[0].metadata[0].name
+              namespace = var.namespace
+            }
+          }
+          pvc {
+            access_modes = [var.pv_access_mode]
+            resources {
+              requests = {
+                storage = var.etcd_disk_size
+              }
+            }
+            storage_class_name = var.etcd_disk_storage_class
+          }
+        }
+      }
+    }
     template {
       metadata {
-        labels = {
-          "kubevirt.io/vm" = "${var.cluster_id}-master-${count.index}"
-        }
+        labels = merge(var.labels, local.role_label, {
+          "kubevirt.io/vm" = "${var.name_prefix}-master-${count.index}"
+        })
       }
       spec {
         volume {
-          name = "${var.cluster_id}-master-${count.index}-datavolumedisk1"
+          name = "${var.name_prefix}-master-${count.index}-datavolumedisk1"
           volume_source {
             data_volume {
-              name = "${var.cluster_id}-master-${count.index}-bootvolume"
+              name = "${var.name_prefix}-master-${count.index}-bootvolume"
+            }
+          }
+        }
+        dynamic "volume" {
+          for_each = local.etcd_disk
+          content {
+            name = "${var.name_prefix}-master-${count.index}-etcddisk"
+            volume_source {
+              data_volume {
+                name = "${var.name_prefix}-master-${count.index}-etcdvolume"
+              }
             }
           }
         }
         volume {
-          name = "${var.cluster_id}-master-${count.index}-cloudinitdisk"
+          name = "${var.name_prefix}-master-${count.index}-cloudinitdisk"
           volume_source {
             cloud_init_config_drive {
               user_data_secret_ref {
@@ -108,18 +200,56 @@ resource "kubevirt_virtual_machine" "master_vm" {
               memory = var.memory
               cpu = var.cpu
             }
+            limits = var.memory_limit == "" ? {} : {
+              memory = var.memory_limit
+            }
+            over_commit_guest_overhead = var.overcommit_guest_overhead
+          }
+          dynamic "cpu" {
+            for_each = local.cpu
+            content {
+              model = var.cpu_model
+              dynamic "feature" {
+                for_each = var.cpu_features
+                content {
+                  name   = feature.value.name
+                  policy = feature.value.policy
+                }
+              }
+            }
+          }
+          dynamic "memory" {
+            for_each = local.hugepages
+            content {
+              hugepages {
+                page_size = var.hugepages_page_size
+              }
+            }
           }
           devices {
             disk {
-              name = "${var.cluster_id}-master-${count.index}-datavolumedisk1"
+              name = "${var.name_prefix}-master-${count.index}-datavolumedisk1"
               disk_device {
                 disk {
-                  bus = "virtio"
+                  bus = var.disk_bus
+                }
+              }
+            }
+            # The etcd disk must stay the first virtio disk after the boot disk, the
+            # MachineConfig mounting it at /var/lib/etcd formats it by its device name
+            dynamic "disk" {
+              for_each = local.etcd_disk
+              content {
+                name = "${var.name_prefix}-master-${count.index}-etcddisk"
+                disk_device {
+                  disk {
+                    bus = "virtio"
+                  }
                 }
               }
             }
             disk {
-              name = "${var.cluster_id}-master-${count.index}-cloudinitdisk"
+              name = "${var.name_prefix}-master-${count.index}-cloudinitdisk"
               disk_device {
                 disk {
                   bus = "virtio"
@@ -140,15 +270,49 @@ resource "kubevirt_virtual_machine" "master_vm" {
             }
           }
         }
-        affinity {
-          pod_anti_affinity {
-            preferred_during_scheduling_ignored_during_execution {
-              weight = 100
-              pod_affinity_term {
-                label_selector {
-                  match_labels = local.anti_affinity_label
+        termination_grace_period_seconds = var.termination_grace_period_seconds
+        priority_class_name              = var.priority_class_name
+        dynamic "affinity" {
+          for_each = var.spread_policy == "None" ? [] : [var.spread_policy]
+          content {
+            dynamic "pod_anti_affinity" {
+              for_each = affinity.value == "Pack" ? [] : [affinity.value]
+              content {
+                dynamic "required_during_scheduling_ignored_during_execution" {
+                  for_each = pod_anti_affinity.value == "Spread" ? [1] : []
+                  content {
+                    label_selector {
+                      match_labels = local.anti_affinity_label
+                    }
+                    topology_key = "kubernetes.io/hostname"
+                  }
+                }
+                dynamic "preferred_during_scheduling_ignored_during_execution" {
+                  for_each = pod_anti_affinity.value == "Spread" ? [] : [1]
+                  content {
+                    weight = 100
+                    pod_affinity_term {
+                      label_selector {
+                        match_labels = local.anti_affinity_label
+                      }
+                      topology_key = "kubernetes.io/hostname"
+                    }
+                  }
+                }
+              }
+            }
+            dynamic "pod_affinity" {
+              for_each = affinity.value == "Pack" ? [1] : []
+              content {
+                preferred_during_scheduling_ignored_during_execution {
+                  weight = 100
+                  pod_affinity_term {
+                    label_selector {
+                      match_labels = local.anti_affinity_label
+                    }
+                    topology_key = "kubernetes.io/hostname"
+                  }
                 }
-                topology_key = "kubernetes.io/hostname"
               }
             }
           }
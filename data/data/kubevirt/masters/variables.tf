@@ -2,6 +2,11 @@ variable "cluster_id" {
   description = "The ID of Openshift cluster"
 }
 
+variable "name_prefix" {
+  type        = string
+  description = "The prefix of the master VM names"
+}
+
 variable "master_count" {
   description = "The number of master vm instances"
 }
@@ -62,3 +67,96 @@ EOF
 
   default = {}
 }
+
+variable "memory_limit" {
+  type        = string
+  default     = ""
+  description = "master VM memory limit, of type Quantity, empty for no limit"
+}
+
+variable "overcommit_guest_overhead" {
+  type        = bool
+  default     = false
+  description = "Whether the scheduler should not take the guest-management overhead of the master VMs into account"
+}
+
+variable "spread_policy" {
+  type        = string
+  default     = ""
+  description = "The scheduling policy of the master VMs across the infracluster nodes [Spread,Pack,None], empty to prefer different nodes"
+}
+
+variable "disk_bus" {
+  type        = string
+  default     = "virtio"
+  description = "The type of disk device emulated for the boot disk of the master VMs [virtio,sata,scsi]"
+}
+
+variable "etcd_disk_size" {
+  type        = string
+  default     = ""
+  description = "The size of the dedicated etcd disk of the master VMs, of type Quantity, empty for no etcd disk"
+}
+
+variable "etcd_disk_storage_class" {
+  type        = string
+  default     = ""
+  description = "The storage class of the dedicated etcd disk of the master VMs"
+}
+
+variable "cpu_model" {
+  type        = string
+  default     = ""
+  description = "The CPU model of the master VMs, host-passthrough, host-model or a named model, empty for the default of the infracluster"
+}
+
+variable "cpu_features" {
+  type = list(object({
+    name   = string
+    policy = string
+  }))
+  default     = []
+  description = "The CPU features enabled or disabled in the master VMs, with their policy [force,require,optional,disable,forbid]"
+}
+
+variable "hugepages_page_size" {
+  type        = string
+  default     = ""
+  description = "The page size of the hugepages backing the memory of the master VMs [2Mi,1Gi], empty for regular memory"
+}
+
+variable "run_labels" {
+  type        = map(string)
+  default     = {}
+  description = "Labels identifying the run of the installer, set on the metadata of the resources only"
+}
+
+variable "run_strategy" {
+  type        = string
+  default     = "Always"
+  description = "The run strategy the master VMs are created with [Always,RerunOnFailure]"
+}
+
+variable "termination_grace_period_seconds" {
+  type        = number
+  default     = -1
+  description = "How long the guests of the master VMs are given to shut down before they are forcibly terminated, negative for the default of the infracluster"
+}
+
+variable "priority_class_name" {
+  type        = string
+  default     = ""
+  description = "The priority class of the infracluster the master VMs are scheduled with, empty for the default priority"
+}
+
+variable "min_available" {
+  type        = number
+  default     = 0
+  description = "How many master VMs the disruption budget of the masters keeps available, 0 for no disruption budget"
+}
+
+variable "data_volume_annotations" {
+  type        = map(string)
+  default     = {}
+  description = "The annotations tagging the data volumes of the VMs"
+}
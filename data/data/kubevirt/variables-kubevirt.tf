@@ -13,6 +13,11 @@ variable "kubevirt_master_storage" {
   description = "master VM disk size, of type Quantity (see: https://github.com/kubernetes/apimachinery/blob/master/pkg/api/resource/quantity.go)"
 }
 
+variable "kubevirt_master_name_prefix" {
+  type        = string
+  description = "The prefix of the master VM names (the VMs are named <prefix>-master-<index>)"
+}
+
 variable "kubevirt_image_url" {
   type        = string
   description = "The source image URL to be used to create the source persistant data volume (all the VMs are cloned from)"
@@ -28,6 +33,93 @@ variable "kubevirt_master_cpu" {
   description = "master VM number of cores"
 }
 
+variable "kubevirt_master_memory_limit" {
+  type        = string
+  default     = ""
+  description = "master VM memory limit, the memory plus the memory overhead of the control plane pool, empty for no limit"
+}
+
+variable "kubevirt_master_overcommit_guest_overhead" {
+  type        = bool
+  default     = false
+  description = "Whether the scheduler should not take the guest-management overhead of the master VMs into account"
+}
+
+variable "kubevirt_master_spread_policy" {
+  type        = string
+  default     = ""
+  description = "The scheduling policy of the master VMs across the infracluster nodes [Spread,Pack,None], empty to prefer different nodes"
+}
+
+variable "kubevirt_master_disk_bus" {
+  type        = string
+  default     = "virtio"
+  description = "The type of disk device emulated for the boot disk of the master VMs [virtio,sata,scsi]"
+}
+
+variable "kubevirt_master_etcd_disk_size" {
+  type        = string
+  default     = ""
+  description = "The size of the dedicated etcd disk of the master VMs, of type Quantity, empty for no etcd disk"
+}
+
+variable "kubevirt_master_etcd_disk_storage_class" {
+  type        = string
+  default     = ""
+  description = "The storage class of the dedicated etcd disk of the master VMs"
+}
+
+variable "kubevirt_master_cpu_model" {
+  type        = string
+  default     = ""
+  description = "The CPU model of the master VMs, host-passthrough, host-model or a named model, empty for the default of the infracluster"
+}
+
+variable "kubevirt_master_cpu_features" {
+  type = list(object({
+    name   = string
+    policy = string
+  }))
+  default     = []
+  description = "The CPU features enabled or disabled in the master VMs, with their policy [force,require,optional,disable,forbid]"
+}
+
+variable "kubevirt_master_hugepages_page_size" {
+  type        = string
+  default     = ""
+  description = "The page size of the hugepages backing the memory of the master VMs [2Mi,1Gi], empty for regular memory"
+}
+
+variable "kubevirt_bootstrap_ignition_url" {
+  type        = string
+  default     = ""
+  description = "The URL the bootstrap VM fetches its Ignition config from, empty to embed the config in its user data"
+}
+
+variable "kubevirt_bootstrap_ignition_gzip" {
+  type        = bool
+  default     = false
+  description = "Whether the Ignition config embedded in the user data of the bootstrap VM is compressed with gzip, for the user data to fit its Secret"
+}
+
+variable "kubevirt_bootstrap_cpu" {
+  type        = string
+  default     = "4"
+  description = "bootstrap VM number of cores"
+}
+
+variable "kubevirt_bootstrap_memory" {
+  type        = string
+  default     = "8G"
+  description = "bootstrap VM memory size, of type Quantity (see: https://github.com/kubernetes/apimachinery/blob/master/pkg/api/resource/quantity.go)"
+}
+
+variable "kubevirt_bootstrap_storage" {
+  type        = string
+  default     = "35Gi"
+  description = "bootstrap VM disk size, of type Quantity (see: https://github.com/kubernetes/apimachinery/blob/master/pkg/api/resource/quantity.go)"
+}
+
 variable "kubevirt_storage_class" {
   type        = string
   description = "The \"class\" of the storage located in the infracluster"
@@ -54,3 +146,68 @@ EOF
 
   default = {}
 }
+
+variable "kubevirt_run_labels" {
+  type        = map(string)
+  default     = {}
+  description = "Labels identifying the run of the installer which created the resources, set on their metadata only"
+}
+
+variable "kubevirt_import_annotations" {
+  type        = map(string)
+  default     = {}
+  description = "The CDI annotations tuning the resources of the importer pod of the source data volume"
+}
+
+variable "kubevirt_data_volume_annotations" {
+  type        = map(string)
+  default     = {}
+  description = "The annotations tagging the data volumes of the cluster for the storage operators of the infra cluster"
+}
+
+variable "kubevirt_kubeconfig_path" {
+  type        = string
+  description = "The kubeconfig file used to access the infracluster"
+}
+
+variable "kubevirt_kubeconfig_context" {
+  type        = string
+  default     = ""
+  description = "The context of the kubeconfig used to access the infracluster, empty for the current-context"
+}
+
+variable "kubevirt_image_import_timeout" {
+  type        = string
+  default     = "20m"
+  description = "How long the import of the RHCOS image into the infra cluster is waited for"
+}
+
+variable "kubevirt_master_run_strategy" {
+  type        = string
+  default     = "Always"
+  description = "The run strategy the master VMs are created with [Always,RerunOnFailure]"
+}
+
+variable "kubevirt_master_termination_grace_period_seconds" {
+  type        = number
+  default     = -1
+  description = "How long the guests of the master VMs are given to shut down before they are forcibly terminated, negative for the default of the infracluster"
+}
+
+variable "kubevirt_master_priority_class_name" {
+  type        = string
+  default     = ""
+  description = "The priority class of the infracluster the master VMs are scheduled with, empty for the default priority"
+}
+
+variable "kubevirt_master_min_available" {
+  type        = number
+  default     = 0
+  description = "How many master VMs the disruption budget of the masters keeps available, 0 for no disruption budget"
+}
+
+variable "kubevirt_bootstrap_run_strategy" {
+  type        = string
+  default     = "Always"
+  description = "The run strategy the bootstrap VM is created with [Always,RerunOnFailure]"
+}
@@ -0,0 +1,164 @@
+// Package analyze finds the probable root causes of failed installs in the installer
+// log of the assets directory and in the log bundle gathered from the bootstrap host.
+package analyze
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// InstallerLogName is the name of the installer log in the assets directory.
+const InstallerLogName = ".openshift_install.log"
+
+// maxEvidence is the number of matching lines kept as evidence of each finding.
+const maxEvidence = 3
+
+// Finding is a probable root cause of a failed install.
+type Finding struct {
+	// Cause describes the probable root cause.
+	Cause string
+	// Remediation hints at how to fix the cause.
+	Remediation string
+	// Evidence are the log lines pointing at the cause, prefixed by their file.
+	Evidence []string
+	// MoreEvidence is the number of matching lines not kept in Evidence.
+	MoreEvidence int
+}
+
+// String returns the finding formatted for users.
+func (f Finding) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", f.Cause)
+	if len(f.Evidence) > 0 {
+		fmt.Fprintf(&b, "  Evidence:\n")
+		for _, e := range f.Evidence {
+			fmt.Fprintf(&b, "    %s\n", e)
+		}
+		if f.MoreEvidence > 0 {
+			fmt.Fprintf(&b, "    (and %d more)\n", f.MoreEvidence)
+		}
+	}
+	fmt.Fprintf(&b, "  Remediation: %s\n", f.Remediation)
+	return b.String()
+}
+
+// addEvidence records the line of the file as evidence of the finding.
+func (f *Finding) addEvidence(file string, line string) {
+	if len(f.Evidence) >= maxEvidence {
+		f.MoreEvidence++
+		return
+	}
+	f.Evidence = append(f.Evidence, fmt.Sprintf("%s: %s", file, strings.TrimSpace(line)))
+}
+
+// Analyzer runs the heuristics over the files added to it.
+type Analyzer struct {
+	// files are the contents of the added files by name; the names of the files of the
+	// log bundle are relative to its root directory.
+	files map[string][]byte
+}
+
+// NewAnalyzer returns an analyzer without any file.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{files: map[string][]byte{}}
+}
+
+// AddFile adds the content of a file with the name.
+func (a *Analyzer) AddFile(name string, data []byte) {
+	a.files[filepath.ToSlash(name)] = data
+}
+
+// AddDir adds the installer log of the assets directory.
+func (a *Analyzer) AddDir(directory string) error {
+	data, err := ioutil.ReadFile(filepath.Join(directory, InstallerLogName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	a.AddFile(InstallerLogName, data)
+	return nil
+}
+
+// AddLogBundle adds the files of a log bundle gathered from the bootstrap host.
+func (a *Analyzer) AddLogBundle(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the log bundle %s", path)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the log bundle %s", path)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s from the log bundle %s", hdr.Name, path)
+		}
+		// Strip the log-bundle-<gather ID> root directory
+		name := strings.TrimPrefix(hdr.Name, "./")
+		if i := strings.Index(name, "/"); i >= 0 && strings.HasPrefix(name, "log-bundle-") {
+			name = name[i+1:]
+		}
+		a.AddFile(name, data)
+	}
+}
+
+// Analyze runs all the heuristics and returns their findings.
+func (a *Analyzer) Analyze() []Finding {
+	var findings []Finding
+	for _, r := range rules {
+		findings = append(findings, r(a)...)
+	}
+	return findings
+}
+
+// fileNames returns the names of the files sorted, so that the evidence is stable.
+func (a *Analyzer) fileNames() []string {
+	names := make([]string, 0, len(a.files))
+	for name := range a.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// eachLine calls fn for each line of the files accepted by filter.
+func (a *Analyzer) eachLine(filter func(name string) bool, fn func(name string, line string)) {
+	for _, name := range a.fileNames() {
+		if !filter(name) {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(a.files[name]))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			fn(name, scanner.Text())
+		}
+	}
+}
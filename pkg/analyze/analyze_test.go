@@ -0,0 +1,117 @@
+package analyze
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const clusterOperators = `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {
+      "metadata": {"name": "authentication"},
+      "status": {"conditions": [
+        {"type": "Degraded", "status": "True", "reason": "OAuthRouteCheckEndpointAccessibleController_SyncError", "message": "route not reachable"},
+        {"type": "Available", "status": "False", "reason": "OAuthRouteCheckEndpointAccessibleController_EndpointUnavailable", "message": "route not available"}
+      ]}
+    },
+    {
+      "metadata": {"name": "etcd"},
+      "status": {"conditions": [
+        {"type": "Degraded", "status": "False"},
+        {"type": "Available", "status": "True"}
+      ]}
+    }
+  ]
+}`
+
+func writeLogBundle(t *testing.T, path string, files map[string]string) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analyze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	installerLog := `time="2020-11-10T10:00:00Z" level=debug msg="module.datavolume.kubevirt_data_volume.data_volume: Creating..."
+time="2020-11-10T10:30:00Z" level=error msg="Error: DataVolume rhcos-source import failed: Unable to connect to http data source"
+time="2020-11-10T11:00:00Z" level=error msg="Cluster operator console Degraded is True with RouteHealth_FailedGet: failed to GET route"
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, InstallerLogName), []byte(installerLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(dir, "log-bundle-20201110.tar.gz")
+	writeLogBundle(t, bundle, map[string]string{
+		"log-bundle-20201110/resources/clusteroperators.json": clusterOperators,
+		"log-bundle-20201110/control-plane/10.0.0.5/journals/ignition.log": `ignition[712]: GET https://api-int.test.example.com:22623/config/master: attempt #1
+ignition[712]: GET error: Get "https://api-int.test.example.com:22623/config/master": dial tcp: lookup api-int.test.example.com: no such host
+ignition[712]: GET https://api-int.test.example.com:22623/config/master: attempt #2
+ignition[712]: GET https://api-int.test.example.com:22623/config/master: attempt #3
+`,
+		"log-bundle-20201110/bootstrap/journals/release-image.log": `podman[1234]: Error: error pulling image "quay.io/openshift-release-dev/ocp-release@sha256:1234": unauthorized: authentication required
+`,
+	})
+
+	analyzer := NewAnalyzer()
+	if err := analyzer.AddDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.AddLogBundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+	findings := analyzer.Analyze()
+	if !assert.Len(t, findings, 5) {
+		return
+	}
+
+	assert.Contains(t, findings[0].Cause, "could not fetch their Ignition config")
+	assert.Len(t, findings[0].Evidence, maxEvidence)
+	assert.Equal(t, 1, findings[0].MoreEvidence)
+	assert.Equal(t, "control-plane/10.0.0.5/journals/ignition.log: ignition[712]: GET https://api-int.test.example.com:22623/config/master: attempt #1", findings[0].Evidence[0])
+
+	assert.Equal(t, "Images of the release could not be pulled.", findings[1].Cause)
+	assert.Len(t, findings[1].Evidence, 1)
+
+	assert.Equal(t, "The authentication cluster operator is degraded or unavailable.", findings[2].Cause)
+	assert.Equal(t, []string{
+		"resources/clusteroperators.json: authentication Degraded is True with OAuthRouteCheckEndpointAccessibleController_SyncError: route not reachable",
+		"resources/clusteroperators.json: authentication Available is False with OAuthRouteCheckEndpointAccessibleController_EndpointUnavailable: route not available",
+	}, findings[2].Evidence)
+	assert.Equal(t, "The console cluster operator is degraded or unavailable.", findings[3].Cause)
+	assert.Equal(t, []string{".openshift_install.log: console Degraded is True with RouteHealth_FailedGet: failed to GET route"}, findings[3].Evidence)
+
+	assert.Contains(t, findings[4].Cause, "DataVolume")
+	assert.Len(t, findings[4].Evidence, 1)
+}
+
+func TestAnalyzeNoFindings(t *testing.T) {
+	analyzer := NewAnalyzer()
+	analyzer.AddFile(InstallerLogName, []byte(`time="2020-11-10T10:00:00Z" level=info msg="Install complete!"`))
+	assert.Empty(t, analyzer.Analyze())
+}
@@ -0,0 +1,133 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// rule is a heuristic returning the findings of a root cause in the files of the analyzer.
+type rule func(a *Analyzer) []Finding
+
+var rules = []rule{
+	ignitionFetchRule,
+	imagePullRule,
+	clusterOperatorsRule,
+	dataVolumeImportRule,
+}
+
+// isLog accepts the log files, skipping the JSON dumps of the cluster resources and the rendered assets.
+func isLog(name string) bool {
+	return !strings.HasPrefix(name, "resources/") && !strings.HasPrefix(name, "rendered-assets/")
+}
+
+// matchRule returns a single finding when the pattern matches lines of the files accepted by filter.
+func matchRule(a *Analyzer, filter func(string) bool, pattern *regexp.Regexp, cause string, remediation string) []Finding {
+	finding := Finding{Cause: cause, Remediation: remediation}
+	a.eachLine(filter, func(name string, line string) {
+		if pattern.MatchString(line) {
+			finding.addEvidence(name, line)
+		}
+	})
+	if len(finding.Evidence) == 0 {
+		return nil
+	}
+	return []Finding{finding}
+}
+
+// ignitionFetchPattern matches the failed attempts of Ignition to fetch the config from the machine config server.
+var ignitionFetchPattern = regexp.MustCompile(`:22623/config/\S+.*(attempt #\d+|[Ee]rror)|GET error: .*:22623/config/`)
+
+func ignitionFetchRule(a *Analyzer) []Finding {
+	return matchRule(a, isLog, ignitionFetchPattern,
+		"The machines could not fetch their Ignition config from the machine config server on the bootstrap host (port 22623), so the bootstrap is stuck.",
+		"Check that api-int.<cluster domain> resolves to the API VIP or load balancer from the machine network, that port 22623 is reachable from the machines, and that the machine-config-server is running on the bootstrap host (bootstrap/journals/bootkube.log). On KubeVirt, check that the apiVIP belongs to the network of the network-attachment-definition.")
+}
+
+// imagePullPattern matches the image pull failures of the kubelet, cri-o and podman.
+var imagePullPattern = regexp.MustCompile(`ErrImagePull|ImagePullBackOff|[Ee]rror pulling image|manifest unknown|unauthorized: authentication required|toomanyrequests`)
+
+func imagePullRule(a *Analyzer) []Finding {
+	return matchRule(a, isLog, imagePullPattern,
+		"Images of the release could not be pulled.",
+		"Check that the pull secret holds valid credentials for the registries of the release image, that the registries are reachable from the bootstrap and control plane hosts (proxy settings), and that imageContentSources points at complete mirrors when installing from a mirror registry.")
+}
+
+// degradedLogPattern matches the degraded operators logged by the installer when the install fails.
+var degradedLogPattern = regexp.MustCompile(`Cluster operator (\S+) Degraded is True with (\S*): (.*?)"?$`)
+
+// operatorRemediations are the remediation hints of the operators whose failures have well known causes.
+var operatorRemediations = map[string]string{
+	"authentication": "The OAuth server is usually unreachable through the default ingress: check that *.apps.<cluster domain> resolves to the ingress VIP or load balancer and that the router pods are running (openshift-ingress namespace).",
+	"console":        "The console route is usually unreachable through the default ingress: check that *.apps.<cluster domain> resolves to the ingress VIP or load balancer and that the router pods are running (openshift-ingress namespace).",
+	"ingress":        "Check that enough worker nodes are ready to run the router replicas and that the ingress VIP or load balancer is configured.",
+	"image-registry": "Check that the image registry has storage configured (oc edit configs.imageregistry.operator.openshift.io cluster).",
+	"machine-config": "Check the machine config pools (oc get machineconfigpools) and the machine-config-daemon logs of the degraded nodes.",
+	"monitoring":     "Check that enough worker nodes are ready and that the persistent volumes requested by the monitoring stack can be provisioned.",
+}
+
+func operatorRemediation(operator string) string {
+	if remediation, ok := operatorRemediations[operator]; ok {
+		return remediation
+	}
+	return fmt.Sprintf("Check the pods and the events of the namespaces of the %s operator (oc get clusteroperator %s -o yaml lists them in relatedObjects).", operator, operator)
+}
+
+func clusterOperatorsRule(a *Analyzer) []Finding {
+	findings := map[string]*Finding{}
+	finding := func(operator string, cause string) *Finding {
+		if f, ok := findings[operator]; ok {
+			return f
+		}
+		f := &Finding{Cause: cause, Remediation: operatorRemediation(operator)}
+		findings[operator] = f
+		return f
+	}
+
+	if data, ok := a.files["resources/clusteroperators.json"]; ok {
+		operators := &configv1.ClusterOperatorList{}
+		if err := json.Unmarshal(data, operators); err == nil {
+			for _, operator := range operators.Items {
+				for _, condition := range operator.Status.Conditions {
+					if (condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue) ||
+						(condition.Type == configv1.OperatorAvailable && condition.Status == configv1.ConditionFalse) {
+						f := finding(operator.Name, fmt.Sprintf("The %s cluster operator is degraded or unavailable.", operator.Name))
+						f.addEvidence("resources/clusteroperators.json", fmt.Sprintf("%s %s is %s with %s: %s", operator.Name, condition.Type, condition.Status, condition.Reason, condition.Message))
+					}
+				}
+			}
+		}
+	}
+
+	a.eachLine(func(name string) bool { return path.Base(name) == InstallerLogName }, func(name string, line string) {
+		if match := degradedLogPattern.FindStringSubmatch(line); match != nil {
+			f := finding(match[1], fmt.Sprintf("The %s cluster operator is degraded or unavailable.", match[1]))
+			f.addEvidence(name, fmt.Sprintf("%s Degraded is True with %s: %s", match[1], match[2], match[3]))
+		}
+	})
+
+	operators := make([]string, 0, len(findings))
+	for operator := range findings {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+	result := make([]Finding, 0, len(operators))
+	for _, operator := range operators {
+		result = append(result, *findings[operator])
+	}
+	return result
+}
+
+// dataVolumeImportPattern matches the import failures of the KubeVirt DataVolume of the RHCOS image.
+var dataVolumeImportPattern = regexp.MustCompile(`(?i)(datavolume|importer|cdi).*(import ?failed|failed to import|unable to (connect|process)|error)`)
+
+func dataVolumeImportRule(a *Analyzer) []Finding {
+	return matchRule(a, func(name string) bool { return path.Base(name) == InstallerLogName }, dataVolumeImportPattern,
+		"The KubeVirt DataVolume of the RHCOS image could not be imported in the infra cluster.",
+		"Check the logs of the importer pod in the platform namespace of the infra cluster (oc logs importer-<datavolume name>), that the RHCOS image URL is reachable from the infra cluster (OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE can point at a mirror), and that the storage class can provision the requested size with the persistentVolumeAccessMode.")
+}
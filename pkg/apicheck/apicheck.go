@@ -0,0 +1,120 @@
+// Package apicheck verifies the API of an installed cluster from the installer host: the
+// certificate chain it serves and the resolution of its DNS name.
+package apicheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VerifyCertificate connects to the API server at address (host:port) and verifies that the
+// certificate chain it serves is issued by the CA bundle generated by the installer, for the
+// server name.
+func VerifyCertificate(ctx context.Context, address string, serverName string, caBundle []byte) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		return errors.New("the CA bundle of the cluster is not valid PEM")
+	}
+
+	rawConn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to the API server at %s", address)
+	}
+	// The chain is verified below, for the errors to point at the cause
+	conn := tls.Client(rawConn, &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if err := conn.Handshake(); err != nil {
+		return errors.Wrapf(err, "failed the TLS handshake with the API server at %s", address)
+	}
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return errors.Errorf("the API server at %s served no certificate", address)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case x509.UnknownAuthorityError:
+		return errors.Errorf("the certificate of the API server at %s, issued by %q, is not issued by the CA generated by the installer: a proxy or a load balancer may be terminating TLS in front of it", address, certs[0].Issuer.String())
+	case x509.HostnameError:
+		return errors.Errorf("the certificate of the API server at %s is not valid for %s, only for %s", address, serverName, strings.Join(certs[0].DNSNames, ", "))
+	default:
+		return errors.Wrapf(err, "failed to verify the certificate of the API server at %s", address)
+	}
+}
+
+// Resolver resolves host names, e.g. net.DefaultResolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// CheckDNS resolves the host the number of attempts and returns a warning for each of the
+// misconfigurations found: the host not resolving, resolving to different addresses across
+// attempts, or not resolving to the API VIP when set. These are common with split-horizon DNS,
+// when the installer host does not use the DNS servers of the network of the cluster.
+func CheckDNS(ctx context.Context, resolver Resolver, host string, vip string, attempts int) []string {
+	var (
+		warnings []string
+		answers  []string
+		seen     = map[string]bool{}
+		failed   bool
+	)
+	for i := 0; i < attempts; i++ {
+		addresses, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			if !failed {
+				warnings = append(warnings, fmt.Sprintf("%s does not resolve from the installer host: %v", host, err))
+				failed = true
+			}
+			continue
+		}
+		sort.Strings(addresses)
+		answer := strings.Join(addresses, ", ")
+		if !seen[answer] {
+			seen[answer] = true
+			answers = append(answers, answer)
+		}
+	}
+	if len(answers) > 1 {
+		warnings = append(warnings, fmt.Sprintf("%s resolves inconsistently from the installer host, to %s", host, strings.Join(answers, " or ")))
+	}
+
+	if vip == "" {
+		return warnings
+	}
+	for _, answer := range answers {
+		if !containsAddress(strings.Split(answer, ", "), vip) {
+			warnings = append(warnings, fmt.Sprintf("%s resolves to %s from the installer host, not to the API VIP %s: the installer host may be using a different DNS view than the cluster (split horizon)", host, answer, vip))
+		}
+	}
+	return warnings
+}
+
+func containsAddress(addresses []string, address string) bool {
+	ip := net.ParseIP(address)
+	for _, a := range addresses {
+		if a == address || (ip != nil && ip.Equal(net.ParseIP(a))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package apicheck
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+func TestVerifyCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	// The certificate of the test server is self-signed
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	_, otherCA, err := tls.GenerateSelfSignedCertificate(&tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "other-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageCertSign,
+		Validity:  time.Hour,
+		IsCA:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCABundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCA.Raw})
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	cases := []struct {
+		name          string
+		serverName    string
+		caBundle      []byte
+		expectedError string
+	}{
+		{
+			name:       "valid",
+			serverName: "example.com",
+			caBundle:   caBundle,
+		},
+		{
+			name:          "other CA",
+			serverName:    "example.com",
+			caBundle:      otherCABundle,
+			expectedError: `the certificate of the API server at ` + address + `, issued by "O=Acme Co", is not issued by the CA generated by the installer: a proxy or a load balancer may be terminating TLS in front of it`,
+		},
+		{
+			name:          "invalid CA bundle",
+			serverName:    "example.com",
+			caBundle:      []byte("invalid"),
+			expectedError: `the CA bundle of the cluster is not valid PEM`,
+		},
+		{
+			name:          "other server name",
+			serverName:    "api.test-cluster.test",
+			caBundle:      caBundle,
+			expectedError: `the certificate of the API server at ` + address + ` is not valid for api.test-cluster.test, only for example.com, *.example.com`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyCertificate(context.Background(), address, tc.serverName, tc.caBundle)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+// fakeResolver returns the answers in turn, an empty answer failing the lookup.
+type fakeResolver struct {
+	answers [][]string
+	calls   int
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	answer := r.answers[r.calls%len(r.answers)]
+	r.calls++
+	if len(answer) == 0 {
+		return nil, errors.New("no such host")
+	}
+	return answer, nil
+}
+
+func TestCheckDNS(t *testing.T) {
+	cases := []struct {
+		name     string
+		answers  [][]string
+		vip      string
+		expected []string
+	}{
+		{
+			name:    "resolves to the VIP",
+			answers: [][]string{{"192.168.0.10"}},
+			vip:     "192.168.0.10",
+		},
+		{
+			name:    "no VIP",
+			answers: [][]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.2", "10.0.0.1"}},
+		},
+		{
+			name:    "split horizon",
+			answers: [][]string{{"203.0.113.10"}},
+			vip:     "192.168.0.10",
+			expected: []string{
+				"api.test-cluster.test resolves to 203.0.113.10 from the installer host, not to the API VIP 192.168.0.10: the installer host may be using a different DNS view than the cluster (split horizon)",
+			},
+		},
+		{
+			name:    "inconsistent",
+			answers: [][]string{{"192.168.0.10"}, {"203.0.113.10"}},
+			vip:     "192.168.0.10",
+			expected: []string{
+				"api.test-cluster.test resolves inconsistently from the installer host, to 192.168.0.10 or 203.0.113.10",
+				"api.test-cluster.test resolves to 203.0.113.10 from the installer host, not to the API VIP 192.168.0.10: the installer host may be using a different DNS view than the cluster (split horizon)",
+			},
+		},
+		{
+			name:    "not resolving",
+			answers: [][]string{{}},
+			vip:     "192.168.0.10",
+			expected: []string{
+				"api.test-cluster.test does not resolve from the installer host: no such host",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := CheckDNS(context.Background(), &fakeResolver{answers: tc.answers}, "api.test-cluster.test", tc.vip, 2)
+			assert.Equal(t, tc.expected, warnings)
+		})
+	}
+}
@@ -36,6 +36,24 @@ type WritableAsset interface {
 	Load(FileFetcher) (found bool, err error)
 }
 
+// Interactive is an Asset that may prompt the user when generated. The store
+// never generates it concurrently with other assets.
+type Interactive interface {
+	Asset
+
+	// Interactive marks the asset as interactive.
+	Interactive()
+}
+
+// FileDescriber is a WritableAsset which is not loaded from disk and describes the names of
+// the files it writes, which can't be discovered from its Load.
+type FileDescriber interface {
+	WritableAsset
+
+	// FileNames returns the names of the files written, relative to the assets directory.
+	FileNames() []string
+}
+
 // File is a file for an Asset.
 type File struct {
 	// Filename is the name of the file.
@@ -109,3 +109,51 @@ func verifyFilesCreated(t *testing.T, dir string, expectedFiles map[string][]byt
 		t.Errorf("Expected file %q not created", f)
 	}
 }
+
+func TestDiffWithFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestDiffWithFiles")
+	if err != nil {
+		t.Skipf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "manifests"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"manifests/changed.yaml":   "kind: ConfigMap\ndata:\n  key: old\n",
+		"manifests/unchanged.yaml": "kind: Secret\n",
+		"manifests/user.yaml":      "kind: Namespace\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := &writablePersistAsset{
+		FileList: []*File{
+			{Filename: "manifests/unchanged.yaml", Data: []byte("kind: Secret\n")},
+			{Filename: "manifests/new.yaml", Data: []byte("kind: Service\n")},
+			{Filename: "manifests/changed.yaml", Data: []byte("kind: ConfigMap\ndata:\n  key: new\n")},
+		},
+	}
+	diff, err := DiffWithFiles(a, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, `--- a/manifests/changed.yaml
++++ b/manifests/changed.yaml
+@@ -1,3 +1,3 @@
+ kind: ConfigMap
+ data:
+-  key: old
++  key: new
+--- /dev/null
++++ b/manifests/new.yaml
+@@ -0,0 +1 @@
++kind: Service
+`, diff)
+
+	a.FileList = a.FileList[:1]
+	diff, err = DiffWithFiles(a, dir)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
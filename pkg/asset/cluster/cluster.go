@@ -13,13 +13,20 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/cluster/aws"
 	"github.com/openshift/installer/pkg/asset/cluster/azure"
+	"github.com/openshift/installer/pkg/asset/cluster/kubevirt"
+	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/quota"
+	"github.com/openshift/installer/pkg/asset/rhcos"
 	"github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/statecrypt"
 	"github.com/openshift/installer/pkg/terraform"
+	kubevirttfvars "github.com/openshift/installer/pkg/tfvars/kubevirt"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
+	typeskubevirt "github.com/openshift/installer/pkg/types/kubevirt"
 )
 
 // Cluster uses the terraform executable to launch a cluster
@@ -50,7 +57,9 @@ func (c *Cluster) Dependencies() []asset.Asset {
 		&installconfig.PlatformProvisionCheck{},
 		&quota.PlatformQuotaCheck{},
 		&TerraformVariables{},
+		new(rhcos.Image),
 		&password.KubeadminPassword{},
+		&machine.Worker{},
 	}
 }
 
@@ -59,7 +68,9 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	terraformVariables := &TerraformVariables{}
-	parents.Get(clusterID, installConfig, terraformVariables)
+	rhcosImage := new(rhcos.Image)
+	workerIgnition := &machine.Worker{}
+	parents.Get(clusterID, installConfig, terraformVariables, rhcosImage, workerIgnition)
 
 	if installConfig.Config.Platform.None != nil {
 		return errors.New("cluster cannot be created with platform set to 'none'")
@@ -80,6 +91,26 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 		extraArgs = append(extraArgs, fmt.Sprintf("-var-file=%s", filepath.Join(tmpDir, file.Filename)))
 	}
 
+	// runID labels the KubeVirt resources created by this run, to roll them back on failure.
+	var runID string
+	if installConfig.Config.Platform.Name() == typeskubevirt.Name {
+		runID = kubevirt.NewRunID()
+		data, err := kubevirttfvars.RunTFVars(kubevirt.RunLabels(runID))
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubevirt run Terraform variables")
+		}
+		runVarsFile := filepath.Join(tmpDir, "terraform.kubevirt.run.tfvars.json")
+		if err := ioutil.WriteFile(runVarsFile, data, 0600); err != nil {
+			return err
+		}
+		extraArgs = append(extraArgs, fmt.Sprintf("-var-file=%s", runVarsFile))
+		// Capping the concurrent operations of terraform caps the DataVolumes imported and
+		// cloned concurrently.
+		if tuning := installConfig.Config.Kubevirt.ImportTuning; tuning != nil && tuning.MaxConcurrentImports > 0 {
+			extraArgs = append(extraArgs, fmt.Sprintf("-parallelism=%d", tuning.MaxConcurrentImports))
+		}
+	}
+
 	logrus.Infof("Creating infrastructure resources...")
 	switch installConfig.Config.Platform.Name() {
 	case typesaws.Name:
@@ -90,6 +121,21 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 		if err := azure.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
 			return err
 		}
+	case typeskubevirt.Name:
+		metadata := kubevirt.Metadata(clusterID.InfraID, installConfig.Config)
+		if err := ickubevirt.RecordMilestone(clusterID.InfraID, metadata, ickubevirt.MilestoneProvisioningStarted, "Creating the infrastructure of the cluster"); err != nil {
+			logrus.Warnf("Failed to record the %s milestone in the infra cluster: %v", ickubevirt.MilestoneProvisioningStarted, err)
+		}
+		stopHeartbeat := ickubevirt.StartHeartbeat(clusterID.InfraID, metadata)
+		defer stopHeartbeat()
+		if err := kubevirt.PreTerraform(context.TODO(), clusterID.InfraID, runID, string(*rhcosImage), installConfig); err != nil {
+			// The resources created by the run before it failed are rolled back as
+			// when terraform fails.
+			if rollbackErr := kubevirt.Rollback(clusterID.InfraID, runID, installConfig); rollbackErr != nil {
+				logrus.Errorf("Failed to roll back the infrastructure, run destroy cluster to delete it: %v", rollbackErr)
+			}
+			return err
+		}
 	}
 
 	timer.StartTimer("Infrastructure")
@@ -97,6 +143,16 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	stateFile, err := terraform.Apply(tmpDir, installConfig.Config.Platform.Name(), extraArgs...)
 	if err != nil {
 		err = errors.Wrap(err, "failed to create cluster")
+		if runID != "" {
+			rollbackErr := kubevirt.Rollback(clusterID.InfraID, runID, installConfig)
+			if rollbackErr == nil {
+				// Nothing created by the run is left, so the state file is not kept
+				// and the cluster can be created again.
+				timer.StopTimer("Infrastructure")
+				return err
+			}
+			logrus.Errorf("Failed to roll back the infrastructure, run destroy cluster to delete it: %v", rollbackErr)
+		}
 		if stateFile == "" {
 			return err
 		}
@@ -104,8 +160,21 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 		// generation so that the Terraform state file is recovered from
 		// the temporary directory.
 	}
+	if err == nil && installConfig.Config.Platform.Name() == typeskubevirt.Name {
+		// The state file is kept on failure, the VMs being created.
+		err = kubevirt.PostTerraform(clusterID.InfraID, installConfig)
+		if err == nil {
+			err = kubevirt.WaitForDataVolumes(context.TODO(), clusterID.InfraID, installConfig)
+		}
+		if err == nil {
+			err = kubevirt.ServeWorkerIgnition(context.TODO(), clusterID.InfraID, runID, installConfig, workerIgnition.File.Data)
+		}
+	}
 
 	data, err2 := ioutil.ReadFile(stateFile)
+	if err2 == nil && statecrypt.Enabled() {
+		data, err2 = statecrypt.Encrypt(data)
+	}
 	if err2 == nil {
 		c.FileList = append(c.FileList, &asset.File{
 			Filename: terraform.StateFileName,
@@ -0,0 +1,199 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/timeouts"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// The phases of the DataVolumes which end the wait for them.
+const (
+	dataVolumeSucceeded = "Succeeded"
+	dataVolumeFailed    = "Failed"
+	// dataVolumeWaitForFirstConsumer is the phase of the DataVolumes of storage classes binding
+	// their volumes once their VM is scheduled, which the VMs left stopped never are.
+	dataVolumeWaitForFirstConsumer = "WaitForFirstConsumer"
+)
+
+// dataVolumeReportInterval is how often the DataVolumes not ready yet are reported while
+// waiting for them.
+var dataVolumeReportInterval = 30 * time.Second
+
+// dataVolumeStatus is the phase of a DataVolume with its progress and the message of its
+// conditions.
+type dataVolumeStatus struct {
+	phase    string
+	progress string
+	message  string
+}
+
+func (s *dataVolumeStatus) String() string {
+	if s == nil {
+		return "not created yet"
+	}
+	status := s.phase
+	if status == "" {
+		status = "Pending"
+	}
+	if s.progress != "" && s.progress != "N/A" {
+		status += " " + s.progress
+	}
+	if s.message != "" {
+		status += ": " + s.message
+	}
+	return status
+}
+
+// newDataVolumeStatus returns the status of the DataVolume, with the message of its Running
+// condition or, when it has none, of its other conditions.
+func newDataVolumeStatus(dv *unstructured.Unstructured) *dataVolumeStatus {
+	status := &dataVolumeStatus{}
+	status.phase, _, _ = unstructured.NestedString(dv.Object, "status", "phase")
+	status.progress, _, _ = unstructured.NestedString(dv.Object, "status", "progress")
+	conditions, _, _ := unstructured.NestedSlice(dv.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		if message == "" {
+			continue
+		}
+		if condition["type"] == "Running" || status.message == "" {
+			status.message = message
+		}
+	}
+	return status
+}
+
+// createdDataVolumes returns the names of the DataVolumes created by terraform in the platform
+// namespace: the RHCOS image and the disks of the bootstrap and control plane VMs.
+func createdDataVolumes(infraID string, installConfig *installconfig.InstallConfig) []string {
+	dataVolumes := []string{
+		fmt.Sprintf("%s-source-pvc", infraID),
+		fmt.Sprintf("%s-bootstrap-bootvolume", infraID),
+	}
+
+	pool := installConfig.Config.ControlPlane
+	prefix := pool.Platform.Kubevirt.ResourceNamePrefix(infraID)
+	replicas := int64(1)
+	if pool.Replicas != nil {
+		replicas = *pool.Replicas
+	}
+	for i := int64(0); i < replicas; i++ {
+		dataVolumes = append(dataVolumes, kubevirt.ResourceName(prefix, "master", int(i))+"-bootvolume")
+		if pool.Platform.Kubevirt != nil && pool.Platform.Kubevirt.EtcdDisk != nil {
+			dataVolumes = append(dataVolumes, kubevirt.ResourceName(prefix, "master", int(i))+"-etcdvolume")
+		}
+	}
+	return dataVolumes
+}
+
+// WaitForDataVolumes waits for the DataVolumes created by terraform to be Succeeded, before the
+// infrastructure of the cluster is declared ready, so that a failed import or clone is reported
+// with its message rather than by the VMs failing to boot later. The DataVolumes are watched and
+// those not ready yet are reported periodically. The wait is bounded by the image-import timeout.
+func WaitForDataVolumes(ctx context.Context, infraID string, installConfig *installconfig.InstallConfig) error {
+	platform := installConfig.Config.Platform.Kubevirt
+	client, err := ickubevirt.NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	timeout := timeouts.Get(timeouts.ImageImport, installConfig.Config, 20*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logrus.Infof("Waiting up to %v for the DataVolumes of the cluster in namespace %s to be ready", timeout, platform.Namespace)
+	return waitForDataVolumes(ctx, client, platform.Namespace, createdDataVolumes(infraID, installConfig))
+}
+
+// waitForDataVolumes watches the DataVolumes in the namespace until all of them are Succeeded,
+// or waiting for their first consumer. It fails as soon as one of them is Failed, and reports
+// those not ready yet at most every dataVolumeReportInterval.
+func waitForDataVolumes(ctx context.Context, client ickubevirt.Client, namespace string, names []string) error {
+	statuses := map[string]*dataVolumeStatus{}
+	for _, name := range names {
+		statuses[name] = nil
+	}
+	pending := func() []string {
+		var lines []string
+		for _, name := range names {
+			status := statuses[name]
+			if status == nil || (status.phase != dataVolumeSucceeded && status.phase != dataVolumeWaitForFirstConsumer) {
+				lines = append(lines, fmt.Sprintf("%s: %s", name, status))
+			}
+		}
+		sort.Strings(lines)
+		return lines
+	}
+
+	ticker := time.NewTicker(dataVolumeReportInterval)
+	defer ticker.Stop()
+	var watcher watch.Interface
+	defer func() {
+		if watcher != nil {
+			watcher.Stop()
+		}
+	}()
+	for {
+		if len(pending()) == 0 {
+			return nil
+		}
+		if watcher == nil {
+			// The watch is restarted whenever the infra cluster closes it, starting over with
+			// the current state of the DataVolumes
+			var err error
+			watcher, err = client.WatchDataVolumes(ctx, namespace)
+			if err != nil {
+				return errors.Wrapf(err, "failed to watch the DataVolumes in namespace %s", namespace)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Errorf("the DataVolumes in namespace %s were not ready in time:\n%s", namespace, strings.Join(pending(), "\n"))
+		case <-ticker.C:
+			logrus.Infof("Waiting for the DataVolumes in namespace %s:\n%s", namespace, strings.Join(pending(), "\n"))
+		case event, ok := <-watcher.ResultChan():
+			if !ok || event.Type == watch.Error {
+				logrus.Debugf("Restarting the watch of the DataVolumes in namespace %s", namespace)
+				watcher.Stop()
+				watcher = nil
+				continue
+			}
+			dv, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			if _, created := statuses[dv.GetName()]; !created {
+				continue
+			}
+			if event.Type == watch.Deleted {
+				statuses[dv.GetName()] = nil
+				continue
+			}
+			status := newDataVolumeStatus(dv)
+			if previous := statuses[dv.GetName()]; previous == nil || previous.phase != status.phase {
+				logrus.Debugf("DataVolume %s is %s", dv.GetName(), status)
+			}
+			statuses[dv.GetName()] = status
+			if status.phase == dataVolumeFailed {
+				return errors.Errorf("DataVolume %s in namespace %s failed: %s", dv.GetName(), namespace, status.message)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+func dataVolume(name string, phase string, message string) *unstructured.Unstructured {
+	dv := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": phase,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Running", "message": message},
+			},
+		},
+	}}
+	dv.SetName(name)
+	return dv
+}
+
+func TestWaitForDataVolumes(t *testing.T) {
+	cases := []struct {
+		name          string
+		events        []*unstructured.Unstructured
+		expectedError string
+	}{
+		{
+			name: "succeeded",
+			events: []*unstructured.Unstructured{
+				dataVolume("source-pvc", "ImportInProgress", ""),
+				dataVolume("other", "Failed", "not of the cluster"),
+				dataVolume("source-pvc", "Succeeded", ""),
+				dataVolume("bootvolume", "WaitForFirstConsumer", ""),
+			},
+		},
+		{
+			name: "failed",
+			events: []*unstructured.Unstructured{
+				dataVolume("source-pvc", "ImportInProgress", ""),
+				dataVolume("source-pvc", "Failed", "Unable to connect to http data source"),
+			},
+			expectedError: "DataVolume source-pvc in namespace tenant failed: Unable to connect to http data source",
+		},
+		{
+			name: "timed out",
+			events: []*unstructured.Unstructured{
+				dataVolume("source-pvc", "Succeeded", ""),
+				dataVolume("bootvolume", "CloneInProgress", "Clone in progress"),
+			},
+			expectedError: "the DataVolumes in namespace tenant were not ready in time:\nbootvolume: CloneInProgress: Clone in progress",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			watcher := watch.NewFakeWithChanSize(len(tc.events), false)
+			for _, dv := range tc.events {
+				watcher.Modify(dv)
+			}
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().WatchDataVolumes(gomock.Any(), "tenant").Return(watcher, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := waitForDataVolumes(ctx, client, "tenant", []string{"source-pvc", "bootvolume"})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// workerIgnitionFile is the file of the worker pointer ignition config served by the server.
+	workerIgnitionFile = "worker.ign"
+
+	// workerIgnitionDir is the directory of the server pod the ignition config is mounted in.
+	workerIgnitionDir = "/srv/ignition"
+
+	// workerIgnitionPort is the port the server pod listens on.
+	workerIgnitionPort = 8080
+)
+
+// WorkerIgnitionServerName returns the name of the Secret, Deployment and Service serving the
+// worker pointer ignition config of the cluster.
+func WorkerIgnitionServerName(infraID string) string {
+	return fmt.Sprintf("%s-worker-ignition", infraID)
+}
+
+// WorkerIgnitionURL returns the URL of the worker pointer ignition config of the cluster in the
+// infra cluster.
+func WorkerIgnitionURL(infraID string, namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc/%s", WorkerIgnitionServerName(infraID), namespace, workerIgnitionFile)
+}
+
+// ServeWorkerIgnition creates, in the platform namespace, the Secret holding the worker pointer
+// ignition config and the Deployment and Service serving it, when the platform has a worker
+// ignition server. They carry the owner labels of the cluster, to be deleted with it, while the
+// server pod doesn't, as it is not a VM of the cluster.
+func ServeWorkerIgnition(ctx context.Context, infraID string, runID string, installConfig *installconfig.InstallConfig, ignition []byte) error {
+	platform := installConfig.Config.Platform.Kubevirt
+	if platform.WorkerIgnitionServer == nil {
+		return nil
+	}
+	client, err := ickubevirt.NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+
+	labels := kubevirt.OwnerLabels(infraID)
+	for k, v := range RunLabels(runID) {
+		labels[k] = v
+	}
+	objects, err := workerIgnitionServer(infraID, platform, labels, ignition)
+	if err != nil {
+		return err
+	}
+	name := WorkerIgnitionServerName(infraID)
+	logrus.Infof("Serving the worker ignition config from service %s in namespace %s", name, platform.Namespace)
+	for _, resource := range []string{"secrets", "deployments", "services"} {
+		if err := client.ApplyResource(ctx, resource, objects[resource]); err != nil {
+			return errors.Wrapf(err, "failed to create %s %s", resource, name)
+		}
+	}
+	logrus.Infof("The worker ignition config is served at %s", WorkerIgnitionURL(infraID, platform.Namespace))
+	return nil
+}
+
+// workerIgnitionServer returns the Secret, Deployment and Service serving the worker pointer
+// ignition config, by resource name.
+func workerIgnitionServer(infraID string, platform *kubevirt.Platform, labels map[string]string, ignition []byte) (map[string]*unstructured.Unstructured, error) {
+	name := WorkerIgnitionServerName(infraID)
+	selector := map[string]string{"app": name}
+	meta := metav1.ObjectMeta{Name: name, Namespace: platform.Namespace, Labels: labels}
+	replicas := int32(1)
+
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: meta,
+		Data:       map[string][]byte{workerIgnitionFile: ignition},
+	}
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: selector},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "server",
+						Image:   platform.WorkerIgnitionServer.Image,
+						Command: []string{"httpd", "-f", "-p", fmt.Sprint(workerIgnitionPort), "-h", workerIgnitionDir},
+						Ports:   []corev1.ContainerPort{{ContainerPort: workerIgnitionPort, Protocol: corev1.ProtocolTCP}},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "ignition",
+							MountPath: workerIgnitionDir,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         "ignition",
+						VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+					}},
+				},
+			},
+		},
+	}
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(workerIgnitionPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+
+	objects := map[string]*unstructured.Unstructured{}
+	for resource, obj := range map[string]runtime.Object{"secrets": secret, "deployments": deployment, "services": service} {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(content, "spec", "template", "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(content, "status")
+		objects[resource] = &unstructured.Unstructured{Object: content}
+	}
+	return objects, nil
+}
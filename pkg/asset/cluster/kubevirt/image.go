@@ -0,0 +1,71 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	cdiv1alpha1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	kubevirttfvars "github.com/openshift/installer/pkg/tfvars/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// sourceImageStorage is the size of the DataVolume of the RHCOS image, as the one imported by
+// terraform in the platform infra cluster.
+const sourceImageStorage = "20Gi"
+
+// sourceDataVolume returns the DataVolume importing the RHCOS image into the namespace of an
+// infra cluster, which the machine-api provider clones the boot disks of the VMs from.
+func sourceDataVolume(infraID string, namespace string, imageURL string, platform *kubevirt.Platform, labels map[string]string) *unstructured.Unstructured {
+	dv := &unstructured.Unstructured{}
+	dv.SetAPIVersion(cdiv1alpha1.SchemeGroupVersion.String())
+	dv.SetKind("DataVolume")
+	dv.SetName(fmt.Sprintf("%s-source-pvc", infraID))
+	dv.SetNamespace(namespace)
+	dv.SetLabels(labels)
+	annotations := map[string]string{}
+	for k, v := range kubevirttfvars.ImportAnnotations(platform.ImportTuning) {
+		annotations[k] = v
+	}
+	for k, v := range platform.DataVolumeTags.Annotations(infraID) {
+		annotations[k] = v
+	}
+	if len(annotations) > 0 {
+		dv.SetAnnotations(annotations)
+	}
+	pvc := map[string]interface{}{
+		"accessModes": []interface{}{kubevirttfvars.AccessMode(platform.PersistentVolumeAccessMode)},
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"storage": sourceImageStorage},
+		},
+	}
+	if platform.StorageClass != "" {
+		pvc["storageClassName"] = platform.StorageClass
+	}
+	dv.Object["spec"] = map[string]interface{}{
+		"source": map[string]interface{}{
+			"http": map[string]interface{}{"url": imageURL},
+		},
+		"pvc": pvc,
+	}
+	return dv
+}
+
+// importImage imports the RHCOS image into the namespace of an infra cluster of machine pools,
+// labeled for the cluster to delete it on destroy and for the run to roll it back on failure.
+// The import is not waited for, the machine-api provider cloning the image once it completed.
+func importImage(ctx context.Context, client ickubevirt.Client, infraID string, runID string, infraCluster kubevirt.InfraCluster, imageURL string, platform *kubevirt.Platform) error {
+	labels := kubevirt.OwnerLabels(infraID)
+	for k, v := range RunLabels(runID) {
+		labels[k] = v
+	}
+	dv := sourceDataVolume(infraID, infraCluster.Namespace, imageURL, platform, labels)
+	logrus.Infof("Importing the RHCOS image into namespace %s of the infra cluster of the compute pools", infraCluster.Namespace)
+	// An existing DataVolume, e.g. imported by a previous run, is reused with the labels of the run
+	err := client.CreateDataVolume(ctx, dv)
+	return errors.Wrapf(err, "failed to create DataVolume %s", dv.GetName())
+}
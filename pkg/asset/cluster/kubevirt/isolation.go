@@ -0,0 +1,89 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// policyForAnnotation is the annotation of a MultiNetworkPolicy naming the
+// network-attachment-definition it applies to.
+const policyForAnnotation = "k8s.v1.cni.cncf.io/policy-for"
+
+// publicPorts are the TCP ports of the control plane VMs reached from outside the machine network:
+// the API and the default ingress controller, which may run on the control plane.
+var publicPorts = []int64{6443, 80, 443}
+
+// networkIsolationName returns the name of the MultiNetworkPolicy isolating the network of the
+// cluster.
+func networkIsolationName(infraID string) string {
+	return fmt.Sprintf("%s-tenant-isolation", infraID)
+}
+
+// isolateNetwork creates the MultiNetworkPolicy isolating the VMs of the cluster on the network of
+// the platform, labeled for the cluster to delete it on destroy and for the run to roll it back
+// on failure.
+func isolateNetwork(ctx context.Context, client ickubevirt.Client, infraID string, runID string, platform *kubevirt.Platform, machineNetworks []types.MachineNetworkEntry) error {
+	labels := kubevirt.OwnerLabels(infraID)
+	for k, v := range RunLabels(runID) {
+		labels[k] = v
+	}
+	policy := networkIsolationPolicy(infraID, platform, machineNetworks, labels)
+	logrus.Infof("Isolating the VMs of the cluster on network %s with multi-network policy %s", platform.NetworkName, policy.GetName())
+	if err := client.ApplyResource(ctx, "multi-networkpolicies", policy); err != nil {
+		return errors.Wrapf(err, "failed to create multi-network policy %s", policy.GetName())
+	}
+	return nil
+}
+
+// networkIsolationPolicy returns the MultiNetworkPolicy of the network of the platform selecting
+// the VM pods carrying the owner labels of the cluster, which are the bootstrap and control plane
+// VMs, as the VMs of the machine-api don't carry them. Their ingress traffic is only allowed from
+// the machine networks of the cluster, and from anywhere to the public ports.
+func networkIsolationPolicy(infraID string, platform *kubevirt.Platform, machineNetworks []types.MachineNetworkEntry, labels map[string]string) *unstructured.Unstructured {
+	network := platform.NetworkName
+	if !strings.Contains(network, "/") {
+		network = fmt.Sprintf("%s/%s", platform.Namespace, network)
+	}
+
+	from := make([]interface{}, 0, len(machineNetworks))
+	for _, machineNetwork := range machineNetworks {
+		from = append(from, map[string]interface{}{
+			"ipBlock": map[string]interface{}{"cidr": machineNetwork.CIDR.String()},
+		})
+	}
+	ports := make([]interface{}, 0, len(publicPorts))
+	for _, port := range publicPorts {
+		ports = append(ports, map[string]interface{}{"protocol": "TCP", "port": port})
+	}
+	podLabels := map[string]interface{}{}
+	for k, v := range kubevirt.OwnerLabels(infraID) {
+		podLabels[k] = v
+	}
+
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "k8s.cni.cncf.io/v1beta1",
+		"kind":       "MultiNetworkPolicy",
+		"spec": map[string]interface{}{
+			"podSelector": map[string]interface{}{"matchLabels": podLabels},
+			"policyTypes": []interface{}{"Ingress"},
+			"ingress": []interface{}{
+				map[string]interface{}{"from": from},
+				map[string]interface{}{"ports": ports},
+			},
+		},
+	}}
+	policy.SetNamespace(platform.Namespace)
+	policy.SetName(networkIsolationName(infraID))
+	policy.SetLabels(labels)
+	policy.SetAnnotations(map[string]string{policyForAnnotation: network})
+	return policy
+}
@@ -0,0 +1,75 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestIsolateNetwork(t *testing.T) {
+	cases := []struct {
+		name        string
+		networkName string
+		policyFor   string
+	}{
+		{name: "network of the namespace", networkName: "tenant-net", policyFor: "tenant/tenant-net"},
+		{name: "network of another namespace", networkName: "default/shared-net", policyFor: "default/shared-net"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			client := mock.NewMockClient(mockCtrl)
+
+			var policy *unstructured.Unstructured
+			client.EXPECT().ApplyResource(gomock.Any(), "multi-networkpolicies", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, obj *unstructured.Unstructured) error {
+					policy = obj
+					return nil
+				})
+
+			platform := &kubevirt.Platform{Namespace: "tenant", NetworkName: tc.networkName}
+			machineNetworks := []types.MachineNetworkEntry{
+				{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")},
+				{CIDR: *ipnet.MustParseCIDR("fd00::/64")},
+			}
+			err := isolateNetwork(context.TODO(), client, "infra-id", "run-id", platform, machineNetworks)
+			if !assert.NoError(t, err) || !assert.NotNil(t, policy) {
+				return
+			}
+
+			assert.Equal(t, "MultiNetworkPolicy", policy.GetKind())
+			assert.Equal(t, "tenant", policy.GetNamespace())
+			assert.Equal(t, "infra-id-tenant-isolation", policy.GetName())
+			assert.Equal(t, map[string]string{
+				"tenantcluster-infra-id-machine.openshift.io": "owned",
+				RunIDLabel: "run-id",
+			}, policy.GetLabels())
+			assert.Equal(t, map[string]string{policyForAnnotation: tc.policyFor}, policy.GetAnnotations())
+
+			// The pods are only selected with the owner labels, the run label is left out
+			selector, _, _ := unstructured.NestedMap(policy.Object, "spec", "podSelector", "matchLabels")
+			assert.Equal(t, map[string]interface{}{"tenantcluster-infra-id-machine.openshift.io": "owned"}, selector)
+			ingress, _, _ := unstructured.NestedSlice(policy.Object, "spec", "ingress")
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{"from": []interface{}{
+					map[string]interface{}{"ipBlock": map[string]interface{}{"cidr": "10.0.0.0/16"}},
+					map[string]interface{}{"ipBlock": map[string]interface{}{"cidr": "fd00::/64"}},
+				}},
+				map[string]interface{}{"ports": []interface{}{
+					map[string]interface{}{"protocol": "TCP", "port": int64(6443)},
+					map[string]interface{}{"protocol": "TCP", "port": int64(80)},
+					map[string]interface{}{"protocol": "TCP", "port": int64(443)},
+				}},
+			}, ingress)
+		})
+	}
+}
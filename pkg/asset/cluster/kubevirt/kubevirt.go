@@ -2,16 +2,63 @@
 package kubevirt
 
 import (
-	kubevirtutils "github.com/openshift/cluster-api-provider-kubevirt/pkg/utils"
+	"path/filepath"
+
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/kubevirt"
 )
 
 // Metadata converts an install configuration to kubevirt metadata.
 func Metadata(infraID string, config *types.InstallConfig) *kubevirt.Metadata {
-	labels := kubevirtutils.BuildLabels(infraID)
+	labels := kubevirt.OwnerLabels(infraID)
 	return &kubevirt.Metadata{
-		Namespace: config.Kubevirt.Namespace,
-		Labels:    labels,
+		Namespace:           config.Kubevirt.Namespace,
+		Labels:              labels,
+		InfraKubeconfigPath: absPath(config.Kubevirt.InfraKubeconfigPath),
+		InfraContext:        config.Kubevirt.InfraContext,
+		InfraCABundle:       absCABundle(config.Kubevirt.InfraCABundle),
+		InfraClusters:       InfraClusters(config),
+		DataVolumeTags:      config.Kubevirt.DataVolumeTags,
+	}
+}
+
+// absPath returns the absolute path of the file, so that the cluster can be destroyed from
+// another working directory.
+func absPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// absCABundle returns the CA bundle, with the absolute path of its file when it is not inline,
+// so that the cluster can be destroyed from another working directory.
+func absCABundle(caBundle string) string {
+	if caBundle == "" || kubevirt.IsInlinePEM(caBundle) {
+		return caBundle
+	}
+	return absPath(caBundle)
+}
+
+// InfraClusters returns the infra clusters of the compute pools with their own infra cluster,
+// once each and with the absolute paths of their files.
+func InfraClusters(config *types.InstallConfig) []kubevirt.InfraCluster {
+	var infraClusters []kubevirt.InfraCluster
+	seen := map[kubevirt.InfraCluster]bool{}
+	for _, pool := range config.Compute {
+		if !pool.Platform.Kubevirt.HasInfraCluster() {
+			continue
+		}
+		infraCluster := config.Kubevirt.MachinePoolInfraCluster(pool.Platform.Kubevirt)
+		infraCluster.KubeconfigPath = absPath(infraCluster.KubeconfigPath)
+		infraCluster.CABundle = absCABundle(infraCluster.CABundle)
+		if !seen[infraCluster] {
+			seen[infraCluster] = true
+			infraClusters = append(infraClusters, infraCluster)
+		}
 	}
+	return infraClusters
 }
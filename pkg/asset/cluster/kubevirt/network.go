@@ -0,0 +1,122 @@
+package kubevirt
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// PreTerraform creates the network-attachment-definition of the cluster when the platform has
+// a network template, then probes the network when the platform has a network probe and
+// isolates the VMs of the cluster on it when the platform has the network isolation, before
+// the VMs of the cluster are created. The infra clusters of the compute pools with their own
+// infra cluster are then prepared for the machine-api provider.
+func PreTerraform(ctx context.Context, infraID string, runID string, imageURL string, installConfig *installconfig.InstallConfig) error {
+	platform := installConfig.Config.Platform.Kubevirt
+	if platform.CreateNetwork != nil || platform.NetworkProbe != nil || platform.NetworkIsolation {
+		client, err := ickubevirt.NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the infra cluster client")
+		}
+		if platform.CreateNetwork != nil {
+			if err := createNetwork(ctx, client, infraID, runID, platform); err != nil {
+				return err
+			}
+		}
+		if platform.NetworkProbe != nil {
+			if err := probeNetwork(ctx, client, platform); err != nil {
+				return err
+			}
+		}
+		if platform.NetworkIsolation {
+			if err := isolateNetwork(ctx, client, infraID, runID, platform, installConfig.Config.MachineNetwork); err != nil {
+				return err
+			}
+		}
+	}
+	return prepareMachinePoolInfraClusters(ctx, infraID, runID, imageURL, installConfig)
+}
+
+// prepareMachinePoolInfraClusters creates, in each infra cluster of the compute pools with their
+// own infra cluster, the network-attachment-definition of the cluster when the platform has a
+// network template and the RHCOS image the machine-api provider clones the VMs from, as
+// terraform only creates them in the platform infra cluster.
+func prepareMachinePoolInfraClusters(ctx context.Context, infraID string, runID string, imageURL string, installConfig *installconfig.InstallConfig) error {
+	platform := installConfig.Config.Platform.Kubevirt
+	registry := ickubevirt.NewClientRegistry(installConfig.Config, ickubevirt.ClientBuilder(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle))
+	seen := map[kubevirt.InfraCluster]bool{}
+	for _, pool := range installConfig.Config.Compute {
+		if !pool.Platform.Kubevirt.HasInfraCluster() {
+			continue
+		}
+		infraCluster := registry.InfraCluster(pool.Name)
+		if seen[infraCluster] {
+			continue
+		}
+		seen[infraCluster] = true
+
+		client, err := registry.ForPool(pool.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the client of the infra cluster of machine pool %s", pool.Name)
+		}
+		if platform.CreateNetwork != nil {
+			if err := createNetwork(ctx, client, infraID, runID, platform); err != nil {
+				return errors.Wrapf(err, "failed to prepare the infra cluster of machine pool %s", pool.Name)
+			}
+		}
+		if err := importImage(ctx, client, infraID, runID, infraCluster, imageURL, platform); err != nil {
+			return errors.Wrapf(err, "failed to prepare the infra cluster of machine pool %s", pool.Name)
+		}
+	}
+	return nil
+}
+
+// probeNetwork runs the network probe of the platform, failing when any of its checks failed.
+func probeNetwork(ctx context.Context, client ickubevirt.Client, platform *kubevirt.Platform) error {
+	checks, err := ickubevirt.ProbeNetwork(ctx, client, platform)
+	if err != nil {
+		return errors.Wrapf(err, "failed to probe network %s", platform.NetworkName)
+	}
+	for _, check := range checks {
+		logrus.Debugf("Network probe %s check passed: %t", check.Name, check.Passed)
+	}
+	if failed := ickubevirt.FailedNetworkProbeChecks(checks); len(failed) > 0 {
+		return errors.Errorf("the %s checks of the probe of network %s failed", strings.Join(failed, ", "), platform.NetworkName)
+	}
+	return nil
+}
+
+// createNetwork creates the network-attachment-definition of the cluster from the network
+// template of the platform when it doesn't exist, labeled for the cluster to delete it on
+// destroy and for the run to roll it back on failure.
+func createNetwork(ctx context.Context, client ickubevirt.Client, infraID string, runID string, platform *kubevirt.Platform) error {
+	_, err := client.GetNetworkAttachmentDefinition(ctx, platform.NetworkName, platform.Namespace)
+	if err == nil {
+		logrus.Debugf("Using the existing network-attachment-definition %s", platform.NetworkName)
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get network-attachment-definition %s", platform.NetworkName)
+	}
+
+	labels := kubevirt.OwnerLabels(infraID)
+	for k, v := range RunLabels(runID) {
+		labels[k] = v
+	}
+	nad, err := ickubevirt.NetworkAttachmentDefinition(platform, labels)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Creating the %s network-attachment-definition %s in namespace %s", platform.CreateNetwork.Type, platform.NetworkName, platform.Namespace)
+	if err := client.CreateNetworkAttachmentDefinition(ctx, nad); err != nil {
+		return errors.Wrapf(err, "failed to create network-attachment-definition %s", platform.NetworkName)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package kubevirt
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	kubevirtdestroy "github.com/openshift/installer/pkg/destroy/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// RunIDLabel is the label set on the infra cluster resources created by a run of the
+// infrastructure stage, to roll them back when the run fails.
+const RunIDLabel = "installer.openshift.io/run-id"
+
+// NewRunID returns a random ID for a run of the infrastructure stage.
+func NewRunID() string {
+	return utilrand.String(10)
+}
+
+// RunLabels returns the labels of the resources created by the run.
+func RunLabels(runID string) map[string]string {
+	return map[string]string{RunIDLabel: runID}
+}
+
+// Rollback deletes the infra cluster resources created by the failed run of the infrastructure
+// stage, selected by its run ID label, leaving the resources which existed before the run untouched.
+func Rollback(infraID string, runID string, installConfig *installconfig.InstallConfig) error {
+	uninstaller := &kubevirtdestroy.ClusterUninstaller{
+		Metadata: types.ClusterMetadata{
+			InfraID:                 infraID,
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{Kubevirt: rollbackMetadata(infraID, runID, installConfig.Config)},
+		},
+		Logger: logrus.StandardLogger(),
+	}
+	logrus.Infof("Rolling back the infrastructure resources created by run %s", runID)
+	if err := uninstaller.Run(); err != nil {
+		return errors.Wrapf(err, "failed to roll back the infrastructure resources created by run %s", runID)
+	}
+	return nil
+}
+
+// rollbackMetadata returns the metadata of the cluster selecting only the resources created by
+// the run.
+func rollbackMetadata(infraID string, runID string, config *types.InstallConfig) *kubevirt.Metadata {
+	metadata := Metadata(infraID, config)
+	metadata.Labels = RunLabels(runID)
+	// The tagged DataVolumes which existed before the run are kept
+	metadata.DataVolumeTags = nil
+	return metadata
+}
@@ -0,0 +1,52 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestRollbackMetadata(t *testing.T) {
+	config := &types.InstallConfig{
+		Platform: types.Platform{
+			Kubevirt: &kubevirt.Platform{
+				Namespace:      "tenant",
+				DataVolumeTags: &kubevirt.DataVolumeTags{PriorityClass: "tenant-low"},
+			},
+		},
+	}
+
+	metadata := rollbackMetadata("infra-id", "run-id", config)
+	// Only the resources created by the run are selected, not all the ones of the cluster
+	assert.Equal(t, map[string]string{RunIDLabel: "run-id"}, metadata.Labels)
+	assert.Nil(t, metadata.DataVolumeTags)
+	assert.Equal(t, "tenant", metadata.Namespace)
+}
+
+func TestCreateNetworkExisting(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// The network-attachment-definition converged from a previous run keeps the run ID of
+	// that run, so that rolling back this run doesn't delete it
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	existing.SetName("tenant-net")
+	existing.SetLabels(map[string]string{RunIDLabel: "old-run-id"})
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), "tenant-net", "tenant").Return(existing, nil)
+
+	platform := &kubevirt.Platform{
+		Namespace:     "tenant",
+		NetworkName:   "tenant-net",
+		CreateNetwork: &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge},
+	}
+	assert.NoError(t, createNetwork(context.TODO(), client, "infra-id", "run-id", platform))
+	assert.Equal(t, map[string]string{RunIDLabel: "old-run-id"}, existing.GetLabels())
+}
@@ -0,0 +1,63 @@
+package kubevirt
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// PostTerraform switches the VMs created by terraform with the Manual run strategy to it, as they
+// are created with the Always run strategy for KubeVirt to start them.
+func PostTerraform(infraID string, installConfig *installconfig.InstallConfig) error {
+	platform := installConfig.Config.Platform.Kubevirt
+	vms := manualVMs(infraID, installConfig)
+	if len(vms) == 0 {
+		return nil
+	}
+
+	client, err := ickubevirt.NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the infra cluster client")
+	}
+	for _, vm := range vms {
+		logrus.Debugf("Setting the run strategy of VM %s to %s", vm.name, kubevirt.RunStrategyManual)
+		if err := client.SetVirtualMachineRunStrategy(vm.namespace, vm.name, string(kubevirt.RunStrategyManual)); err != nil {
+			return errors.Wrapf(err, "failed to set the run strategy of VM %s", vm.name)
+		}
+	}
+	return nil
+}
+
+type namespacedVM struct {
+	namespace string
+	name      string
+}
+
+// manualVMs returns the VMs created by terraform whose run strategy is Manual.
+func manualVMs(infraID string, installConfig *installconfig.InstallConfig) []namespacedVM {
+	platform := installConfig.Config.Platform.Kubevirt
+	var vms []namespacedVM
+	if platform.MachinePoolRunStrategy(nil) == kubevirt.RunStrategyManual {
+		vms = append(vms, namespacedVM{namespace: platform.Namespace, name: fmt.Sprintf("%s-bootstrap", infraID)})
+	}
+
+	pool := installConfig.Config.ControlPlane
+	if platform.MachinePoolRunStrategy(pool.Platform.Kubevirt) != kubevirt.RunStrategyManual {
+		return vms
+	}
+	prefix := pool.Platform.Kubevirt.ResourceNamePrefix(infraID)
+	replicas := int64(1)
+	if pool.Replicas != nil {
+		replicas = *pool.Replicas
+	}
+	namespace := platform.Namespace
+	for i := int64(0); i < replicas; i++ {
+		vms = append(vms, namespacedVM{namespace: namespace, name: kubevirt.ResourceName(prefix, "master", int(i))})
+	}
+	return vms
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/cluster/vsphere"
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
 	azuretypes "github.com/openshift/installer/pkg/types/azure"
@@ -41,7 +42,7 @@ type Metadata struct {
 	File *asset.File
 }
 
-var _ asset.WritableAsset = (*Metadata)(nil)
+var _ asset.FileDescriber = (*Metadata)(nil)
 
 // Name returns the human-friendly name of the asset.
 func (m *Metadata) Name() string {
@@ -55,6 +56,7 @@ func (m *Metadata) Dependencies() []asset.Asset {
 		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
 		&bootstrap.Bootstrap{},
+		&releaseimage.Image{},
 	}
 }
 
@@ -62,13 +64,17 @@ func (m *Metadata) Dependencies() []asset.Asset {
 func (m *Metadata) Generate(parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
-	parents.Get(clusterID, installConfig)
+	releaseImage := &releaseimage.Image{}
+	parents.Get(clusterID, installConfig, releaseImage)
 
 	metadata := &types.ClusterMetadata{
 		ClusterName: installConfig.Config.ObjectMeta.Name,
 		ClusterID:   clusterID.UUID,
 		InfraID:     clusterID.InfraID,
 	}
+	if releaseImage.Overridden {
+		metadata.ReleaseImageOverride = releaseImage.PullSpec
+	}
 
 	switch installConfig.Config.Platform.Name() {
 	case awstypes.Name:
@@ -115,6 +121,11 @@ func (m *Metadata) Files() []*asset.File {
 	return []*asset.File{}
 }
 
+// FileNames returns the name of the metadata file.
+func (m *Metadata) FileNames() []string {
+	return []string{metadataFileName}
+}
+
 // Load is a no-op, because we never want to load broken metadata from
 // the disk.
 func (m *Metadata) Load(f asset.FileFetcher) (found bool, err error) {
@@ -136,3 +147,13 @@ func LoadMetadata(dir string) (*types.ClusterMetadata, error) {
 
 	return metadata, err
 }
+
+// SaveMetadata writes the cluster metadata to an asset directory. It doesn't update the
+// Metadata asset of the state file of the directory.
+func SaveMetadata(dir string, metadata *types.ClusterMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to Marshal ClusterMetadata")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, metadataFileName), data, 0640)
+}
@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/tfvars"
+	kubevirttfvars "github.com/openshift/installer/pkg/tfvars/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// Plan is the provisioning plan of the cluster, the infrastructure resources which creating
+// it creates, as listed by create cluster --dry-run.
+type Plan struct {
+	Platform  string                   `json:"platform"`
+	InfraID   string                   `json:"infraID"`
+	Resources []tfvars.PlannedResource `json:"resources"`
+}
+
+// NewPlan returns the provisioning plan of the cluster from its Terraform variables. Only the
+// kubevirt platform is supported.
+func NewPlan(platform string, terraformVariables *TerraformVariables) (*Plan, error) {
+	files := map[string][]byte{}
+	for _, file := range terraformVariables.Files() {
+		files[file.Filename] = file.Data
+	}
+	common, err := tfvars.ParseCommon(files[TfVarsFileName])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", TfVarsFileName)
+	}
+
+	plan := &Plan{Platform: platform, InfraID: common.ClusterID}
+	platformVarsFileName := fmt.Sprintf(TfPlatformVarsFileName, platform)
+	switch platform {
+	case kubevirt.Name:
+		plan.Resources, err = kubevirttfvars.Plan(common, files[platformVarsFileName])
+	default:
+		return nil, errors.Errorf("the provisioning plan is not supported on the %s platform", platform)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", platformVarsFileName)
+	}
+	return plan, nil
+}
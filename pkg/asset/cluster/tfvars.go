@@ -7,11 +7,11 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
 	gcpprovider "github.com/openshift/cluster-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
 	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
-	kubevirtutils "github.com/openshift/cluster-api-provider-kubevirt/pkg/utils"
 	libvirtprovider "github.com/openshift/cluster-api-provider-libvirt/pkg/apis/libvirtproviderconfig/v1beta1"
 	ovirtprovider "github.com/openshift/cluster-api-provider-ovirt/pkg/apis/ovirtprovider/v1beta1"
 	vsphereprovider "github.com/openshift/machine-api-operator/pkg/apis/vsphereprovider/v1beta1"
@@ -29,6 +29,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	openstackconfig "github.com/openshift/installer/pkg/asset/installconfig/openstack"
 	ovirtconfig "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	"github.com/openshift/installer/pkg/asset/machines"
@@ -45,6 +46,7 @@ import (
 	openstacktfvars "github.com/openshift/installer/pkg/tfvars/openstack"
 	ovirttfvars "github.com/openshift/installer/pkg/tfvars/ovirt"
 	vspheretfvars "github.com/openshift/installer/pkg/tfvars/vsphere"
+	"github.com/openshift/installer/pkg/timeouts"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/azure"
@@ -539,13 +541,67 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 			masterSpecs[i] = m.Spec.ProviderSpec.Value.Object.(*kubevirtprovider.KubevirtMachineProviderSpec)
 		}
 
-		labels := kubevirtutils.BuildLabels(clusterID.InfraID)
+		masterNamePrefix := installConfig.Config.ControlPlane.Platform.Kubevirt.ResourceNamePrefix(clusterID.InfraID)
+
+		kubeconfigPath, err := ickubevirt.InfraKubeconfigPath(installConfig.Config.Kubevirt.InfraKubeconfigPath)
+		if err != nil {
+			return err
+		}
+
+		var memoryOverhead string
+		var overcommitGuestOverhead bool
+		var spreadPolicy kubevirt.SpreadPolicy
+		var diskBus kubevirt.DiskBus
+		var etcdDisk *kubevirt.EtcdDisk
+		var cpuModel string
+		var cpuFeatures []kubevirt.CPUFeature
+		var hugepagesPageSize string
+		var terminationGracePeriod *int64
+		var priorityClassName string
+		if mpool := installConfig.Config.ControlPlane.Platform.Kubevirt; mpool != nil {
+			memoryOverhead = mpool.MemoryOverhead
+			overcommitGuestOverhead = mpool.OvercommitGuestOverhead
+			spreadPolicy = mpool.SpreadPolicy
+			diskBus = mpool.DiskBus
+			etcdDisk = mpool.EtcdDisk
+			cpuModel = mpool.CPUModel
+			cpuFeatures = mpool.CPUFeatures
+			if mpool.Hugepages != nil {
+				hugepagesPageSize = mpool.Hugepages.PageSize
+			}
+			terminationGracePeriod = mpool.TerminationGracePeriodSeconds
+			priorityClassName = mpool.PriorityClassName
+		}
+
+		labels := kubevirt.OwnerLabels(clusterID.InfraID)
 		data, err := kubevirttfvars.TFVars(
 			kubevirttfvars.TFVarsSources{
-				MasterSpecs:     masterSpecs,
-				ImageURL:        string(*rhcosImage),
-				Namespace:       installConfig.Config.Kubevirt.Namespace,
-				ResourcesLabels: labels,
+				MasterSpecs:                   masterSpecs,
+				MasterNamePrefix:              masterNamePrefix,
+				ImageURL:                      string(*rhcosImage),
+				Namespace:                     installConfig.Config.Kubevirt.Namespace,
+				ResourcesLabels:               labels,
+				KubeconfigPath:                kubeconfigPath,
+				KubeconfigContext:             installConfig.Config.Kubevirt.InfraContext,
+				MasterMemoryOverhead:          memoryOverhead,
+				MasterOvercommitGuestOverhead: overcommitGuestOverhead,
+				MasterSpreadPolicy:            spreadPolicy,
+				MasterDiskBus:                 diskBus,
+				MasterEtcdDisk:                etcdDisk,
+				MasterCPUModel:                cpuModel,
+				MasterCPUFeatures:             cpuFeatures,
+				MasterHugepagesPageSize:       hugepagesPageSize,
+				MasterRunStrategy:             installConfig.Config.Kubevirt.MachinePoolRunStrategy(installConfig.Config.ControlPlane.Platform.Kubevirt),
+				BootstrapRunStrategy:          installConfig.Config.Kubevirt.MachinePoolRunStrategy(nil),
+				MasterGracePeriodSeconds:      terminationGracePeriod,
+				MasterPriorityClassName:       priorityClassName,
+				BootstrapIgnitionURL:          installConfig.Config.Kubevirt.BootstrapIgnitionURL,
+				BootstrapIgnition:             bootstrapIgn,
+				SkipBootstrapIgnitionUpload:   ickubevirt.ReadOnly(),
+				BootstrapVM:                   installConfig.Config.Kubevirt.BootstrapVM(),
+				ImportTuning:                  installConfig.Config.Kubevirt.ImportTuning,
+				ImageImportTimeout:            timeouts.Get(timeouts.ImageImport, installConfig.Config, 20*time.Minute),
+				DataVolumeAnnotations:         installConfig.Config.Kubevirt.DataVolumeTags.Annotations(clusterID.InfraID),
 			},
 		)
 		if err != nil {
@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DiffWithFiles returns the unified diff of the files of the asset against the files in the
+// directory, as writing the asset with PersistToFile would change them. It is empty when
+// writing the asset would not change any file. The files in the directory which are not
+// files of the asset are left out, as PersistToFile does not remove them.
+func DiffWithFiles(asset WritableAsset, directory string) (string, error) {
+	files := append([]*File(nil), asset.Files()...)
+	SortFiles(files)
+
+	var diff bytes.Buffer
+	for _, f := range files {
+		fromFile := filepath.ToSlash(filepath.Join("a", f.Filename))
+		existing, err := ioutil.ReadFile(filepath.Join(directory, f.Filename))
+		if os.IsNotExist(err) {
+			fromFile = "/dev/null"
+		} else if err != nil {
+			return "", errors.Wrapf(err, "failed to read %s", f.Filename)
+		}
+		if bytes.Equal(existing, f.Data) {
+			continue
+		}
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(existing),
+			B:        splitLines(f.Data),
+			FromFile: fromFile,
+			ToFile:   filepath.ToSlash(filepath.Join("b", f.Filename)),
+			Context:  3,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to diff %s", f.Filename)
+		}
+		diff.WriteString(text)
+	}
+	return diff.String(), nil
+}
+
+// splitLines splits the data in lines, keeping their line endings.
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
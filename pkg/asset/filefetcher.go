@@ -9,3 +9,11 @@ type FileFetcher interface {
 	// FetchByPattern returns the files whose name match the given glob.
 	FetchByPattern(pattern string) ([]*File, error)
 }
+
+// LenientFileFetcher is implemented by the file fetchers whose files are loaded leniently,
+// ignoring the fields the installer doesn't know instead of rejecting them.
+type LenientFileFetcher interface {
+	FileFetcher
+	// Lenient returns whether the files are loaded leniently.
+	Lenient() bool
+}
@@ -231,7 +231,7 @@ func (a *Bootstrap) getTemplateData(installConfig *types.InstallConfig, releaseI
 	}
 
 	registries := []sysregistriesv2.Registry{}
-	for _, group := range mergedMirrorSets(imageSources) {
+	for _, group := range ignition.MergedMirrorSets(imageSources) {
 		if len(group.Mirrors) == 0 {
 			continue
 		}
@@ -255,10 +255,15 @@ func (a *Bootstrap) getTemplateData(installConfig *types.InstallConfig, releaseI
 		platformData.VSphere = vsphere.GetTemplateData(installConfig.Platform.VSphere)
 	}
 
+	pullSecret, err := installconfig.ScopedPullSecret(installConfig, releaseImage)
+	if err != nil {
+		return nil, err
+	}
+
 	return &bootstrapTemplateData{
 		AdditionalTrustBundle: installConfig.AdditionalTrustBundle,
 		FIPS:                  installConfig.FIPS,
-		PullSecret:            installConfig.PullSecret,
+		PullSecret:            pullSecret,
 		ReleaseImage:          releaseImage,
 		EtcdCluster:           strings.Join(etcdEndpoints, ","),
 		Proxy:                 &proxy.Status,
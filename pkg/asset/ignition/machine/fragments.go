@@ -0,0 +1,33 @@
+package machine
+
+import (
+	"encoding/json"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// appendIgnitionFragments appends the files, directories, links, systemd units and users of
+// the Ignition fragments of the machine pools to the pointer Ignition config.
+func appendIgnitionFragments(config *igntypes.Config, pools ...*types.MachinePool) error {
+	for _, pool := range pools {
+		if pool == nil {
+			continue
+		}
+		for i, data := range pool.IgnitionFragments {
+			fragment := &igntypes.Config{}
+			if err := json.Unmarshal([]byte(data), fragment); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal Ignition fragment %d of the %s machine pool", i, pool.Name)
+			}
+			config.Storage.Directories = append(config.Storage.Directories, fragment.Storage.Directories...)
+			config.Storage.Files = append(config.Storage.Files, fragment.Storage.Files...)
+			config.Storage.Links = append(config.Storage.Links, fragment.Storage.Links...)
+			config.Systemd.Units = append(config.Systemd.Units, fragment.Systemd.Units...)
+			config.Passwd.Users = append(config.Passwd.Users, fragment.Passwd.Users...)
+			config.Passwd.Groups = append(config.Passwd.Groups, fragment.Passwd.Groups...)
+		}
+	}
+	return nil
+}
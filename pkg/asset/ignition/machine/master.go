@@ -40,6 +40,9 @@ func (a *Master) Generate(dependencies asset.Parents) error {
 	dependencies.Get(installConfig, rootCA)
 
 	a.Config = pointerIgnitionConfig(installConfig.Config, rootCA.Cert(), "master")
+	if err := appendIgnitionFragments(a.Config, installConfig.Config.ControlPlane); err != nil {
+		return err
+	}
 
 	data, err := ignition.Marshal(a.Config)
 	if err != nil {
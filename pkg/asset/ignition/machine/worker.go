@@ -40,6 +40,11 @@ func (a *Worker) Generate(dependencies asset.Parents) error {
 	dependencies.Get(installConfig, rootCA)
 
 	a.Config = pointerIgnitionConfig(installConfig.Config, rootCA.Cert(), "worker")
+	for i := range installConfig.Config.Compute {
+		if err := appendIgnitionFragments(a.Config, &installConfig.Config.Compute[i]); err != nil {
+			return err
+		}
+	}
 
 	data, err := ignition.Marshal(a.Config)
 	if err != nil {
@@ -43,3 +43,46 @@ func TestWorkerGenerate(t *testing.T) {
 	assert.Equal(t, 1, len(actualFiles), "unexpected number of files in worker state")
 	assert.Equal(t, "worker.ign", actualFiles[0].Filename, "unexpected name for worker ignition config")
 }
+
+// TestWorkerGenerateIgnitionFragments tests appending the Ignition fragments of the compute pools.
+func TestWorkerGenerateIgnitionFragments(t *testing.T) {
+	installConfig := &installconfig.InstallConfig{
+		Config: &types.InstallConfig{
+			Networking: &types.Networking{
+				ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("10.0.1.0/24")},
+			},
+			Platform: types.Platform{
+				AWS: &aws.Platform{
+					Region: "us-east",
+				},
+			},
+			Compute: []types.MachinePool{{
+				Name: "worker",
+				IgnitionFragments: []string{
+					`{"ignition":{"version":"3.0.0"},"storage":{"files":[{"path":"/etc/custom.conf","contents":{"source":"data:,custom"}}]}}`,
+					`{"ignition":{"version":"3.1.0"},"systemd":{"units":[{"name":"custom.service","enabled":true}]}}`,
+				},
+			}},
+		},
+	}
+
+	rootCA := &tls.RootCA{}
+	err := rootCA.Generate(nil)
+	assert.NoError(t, err, "unexpected error generating root CA")
+
+	parents := asset.Parents{}
+	parents.Add(installConfig, rootCA)
+
+	worker := &Worker{}
+	err = worker.Generate(parents)
+	assert.NoError(t, err, "unexpected error generating worker asset")
+
+	assert.Equal(t, "3.1.0", worker.Config.Ignition.Version, "unexpected Ignition spec version")
+	assert.Len(t, worker.Config.Ignition.Config.Merge, 1, "unexpected merged configs")
+	if assert.Len(t, worker.Config.Storage.Files, 1, "unexpected files") {
+		assert.Equal(t, "/etc/custom.conf", worker.Config.Storage.Files[0].Path)
+	}
+	if assert.Len(t, worker.Config.Systemd.Units, 1, "unexpected systemd units") {
+		assert.Equal(t, "custom.service", worker.Config.Systemd.Units[0].Name)
+	}
+}
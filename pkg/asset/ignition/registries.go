@@ -1,4 +1,4 @@
-package bootstrap
+package ignition
 
 import (
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -6,7 +6,9 @@ import (
 	"github.com/openshift/installer/pkg/types"
 )
 
-func mergedMirrorSets(sources []types.ImageContentSource) []types.ImageContentSource {
+// MergedMirrorSets merges the groups of the image content sources with the same source, in
+// the order of their first occurrence, dropping the duplicate mirrors.
+func MergedMirrorSets(sources []types.ImageContentSource) []types.ImageContentSource {
 	sourceSet := make(map[string][]string)
 	mirrorSet := make(map[string]sets.String)
 	orderedSources := []string{}
@@ -1,4 +1,4 @@
-package bootstrap
+package ignition
 
 import (
 	"testing"
@@ -120,7 +120,7 @@ func TestMergedMirrorSets(t *testing.T) {
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			assert.Equal(t, test.expected, mergedMirrorSets(test.input))
+			assert.Equal(t, test.expected, MergedMirrorSets(test.input))
 		})
 	}
 }
@@ -0,0 +1,82 @@
+package installconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// APIServerCertificate is the content of the files of the named serving certificate of the API
+// server.
+type APIServerCertificate struct {
+	// Cert is the PEM-encoded certificate followed by its intermediate CA certificates.
+	Cert []byte
+	// Key is the PEM-encoded private key of the certificate.
+	Key []byte
+	// CA is the PEM-encoded certificate of the root CA of the certificate.
+	CA []byte
+}
+
+// ReadAPIServerCertificate reads the files of the named serving certificate of the API server,
+// checking that the key matches the certificate and that the certificate chains up to the CA
+// for all its names. It returns nil when the install-config has no such certificate.
+func ReadAPIServerCertificate(config *types.InstallConfig) (*APIServerCertificate, error) {
+	if config.APIServerCertificate == nil {
+		return nil, nil
+	}
+	files := config.APIServerCertificate
+
+	cert, err := ioutil.ReadFile(files.CertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the API server certificate")
+	}
+	key, err := ioutil.ReadFile(files.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the API server certificate key")
+	}
+	ca, err := ioutil.ReadFile(files.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the API server certificate CA")
+	}
+
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid API server certificate %s", files.CertFile)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, errors.Errorf("invalid API server certificate CA %s: no PEM-encoded certificates", files.CAFile)
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range pair.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid intermediate certificate in %s", files.CertFile)
+		}
+		intermediates.AddCert(intermediate)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid API server certificate %s", files.CertFile)
+	}
+	for _, name := range config.APIServerCertificateNames() {
+		// The wildcard names are checked with a name they match, as the certificates are only
+		// verified for host names
+		_, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       strings.Replace(name, "*", "wildcard", 1),
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "the API server certificate %s is not valid for %s", files.CertFile, name)
+		}
+	}
+
+	return &APIServerCertificate{Cert: cert, Key: key, CA: ca}, nil
+}
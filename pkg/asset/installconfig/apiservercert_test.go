@@ -0,0 +1,136 @@
+package installconfig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// testCertificate signs a certificate for the names with the parent, or self-signs a CA when the
+// parent is nil.
+func testCertificate(t *testing.T, names []string, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     names,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestReadAPIServerCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apiservercert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey := testCertificate(t, nil, nil, nil)
+	otherCA, _ := testCertificate(t, nil, nil, nil)
+	cert, key := testCertificate(t, []string{"api.test-cluster.example.com", "*.apps.example.com"}, ca, caKey)
+	_, otherKey := testCertificate(t, nil, nil, nil)
+	write := func(name string, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	certFile := write("api.crt", "CERTIFICATE", cert.Raw)
+	keyFile := write("api.key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	otherKeyFile := write("other.key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(otherKey))
+	caFile := write("ca.crt", "CERTIFICATE", ca.Raw)
+	otherCAFile := write("other-ca.crt", "CERTIFICATE", otherCA.Raw)
+
+	cases := []struct {
+		name          string
+		certificate   *types.APIServerCertificate
+		expectedError string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:        "default name",
+			certificate: &types.APIServerCertificate{CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		},
+		{
+			name:        "wildcard name",
+			certificate: &types.APIServerCertificate{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, Names: []string{"*.apps.example.com"}},
+		},
+		{
+			name:          "name not in the certificate",
+			certificate:   &types.APIServerCertificate{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, Names: []string{"api.other.example.com"}},
+			expectedError: "the API server certificate .*/api.crt is not valid for api.other.example.com: .*",
+		},
+		{
+			name:          "other key",
+			certificate:   &types.APIServerCertificate{CertFile: certFile, KeyFile: otherKeyFile, CAFile: caFile},
+			expectedError: "invalid API server certificate .*/api.crt: tls: private key does not match public key",
+		},
+		{
+			name:          "other CA",
+			certificate:   &types.APIServerCertificate{CertFile: certFile, KeyFile: keyFile, CAFile: otherCAFile},
+			expectedError: "the API server certificate .*/api.crt is not valid for api.test-cluster.example.com: .*",
+		},
+		{
+			name:          "missing file",
+			certificate:   &types.APIServerCertificate{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile, CAFile: caFile},
+			expectedError: "failed to read the API server certificate: .*",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				ObjectMeta:           metav1.ObjectMeta{Name: "test-cluster"},
+				BaseDomain:           "example.com",
+				APIServerCertificate: tc.certificate,
+			}
+			certificate, err := ReadAPIServerCertificate(config)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.certificate == nil {
+				assert.Nil(t, certificate)
+			} else if assert.NotNil(t, certificate) {
+				assert.Equal(t, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), certificate.CA)
+			}
+		})
+	}
+}
@@ -19,7 +19,10 @@ type baseDomain struct {
 	BaseDomain string
 }
 
-var _ asset.Asset = (*baseDomain)(nil)
+var _ asset.Interactive = (*baseDomain)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *baseDomain) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *baseDomain) Dependencies() []asset.Asset {
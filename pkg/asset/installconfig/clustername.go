@@ -12,7 +12,10 @@ type clusterName struct {
 	ClusterName string
 }
 
-var _ asset.Asset = (*clusterName)(nil)
+var _ asset.Interactive = (*clusterName)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *clusterName) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *clusterName) Dependencies() []asset.Asset {
@@ -0,0 +1,111 @@
+package installconfig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// documentSeparator matches the lines separating the documents of a YAML stream.
+var documentSeparator = regexp.MustCompile(`^(---|\.\.\.)(\s.*)?$`)
+
+// Unmarshal unmarshals the install-config, rejecting its unknown fields, e.g. a misspelled
+// storgeSize, unless lenient, which ignores them as older installers did.
+func Unmarshal(data []byte, config *types.InstallConfig, lenient bool) error {
+	if lenient {
+		return yaml.Unmarshal(data, config)
+	}
+	err := yaml.Unmarshal(data, config, func(d *json.Decoder) *json.Decoder {
+		d.DisallowUnknownFields()
+		return d
+	})
+	if err != nil && strings.Contains(err.Error(), "unknown field") {
+		return errors.Wrap(err, "use --lenient to ignore the unknown fields")
+	}
+	return err
+}
+
+// splitDocuments splits the documents of the YAML stream, returning the first one, the
+// install-config, and the others, the custom manifests of the cluster. The documents holding
+// only comments are dropped.
+func splitDocuments(data []byte) ([]byte, [][]byte) {
+	var documents [][]byte
+	var current bytes.Buffer
+	flush := func() {
+		if !isEmptyDocument(current.Bytes()) {
+			documents = append(documents, append([]byte(nil), current.Bytes()...))
+		}
+		current.Reset()
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if documentSeparator.MatchString(line) {
+			flush()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	flush()
+
+	if len(documents) <= 1 {
+		// The install-config is kept as it is
+		return data, nil
+	}
+	return documents[0], documents[1:]
+}
+
+func isEmptyDocument(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// customManifests returns the files of the custom manifests of the install-config, named
+// after their kind, namespace and name.
+func customManifests(documents [][]byte) ([]*asset.File, error) {
+	var files []*asset.File
+	seen := map[string]int{}
+	for i, data := range documents {
+		var manifest struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest in document %d", i+2)
+		}
+		if manifest.APIVersion == "" || manifest.Kind == "" || manifest.Metadata.Name == "" {
+			return nil, errors.Errorf("invalid manifest in document %d: apiVersion, kind and metadata.name are required", i+2)
+		}
+		name := manifest.Metadata.Name
+		if manifest.Metadata.Namespace != "" {
+			name = manifest.Metadata.Namespace + "-" + name
+		}
+		filename := fmt.Sprintf("99_install-config-%s-%s.yaml", strings.ToLower(manifest.Kind), name)
+		if previous, ok := seen[filename]; ok {
+			return nil, errors.Errorf("the manifests in documents %d and %d are both %s %s", previous, i+2, manifest.Kind, name)
+		}
+		seen[filename] = i + 2
+		files = append(files, &asset.File{Filename: filename, Data: data})
+	}
+	return files, nil
+}
@@ -0,0 +1,83 @@
+package installconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	cases := []struct {
+		name              string
+		data              string
+		expectedConfig    string
+		expectedDocuments []string
+	}{
+		{
+			name:           "single document",
+			data:           "apiVersion: v1\nbaseDomain: example.com\n",
+			expectedConfig: "apiVersion: v1\nbaseDomain: example.com\n",
+		},
+		{
+			name:           "single document with separators",
+			data:           "---\napiVersion: v1\nbaseDomain: example.com\n...\n",
+			expectedConfig: "---\napiVersion: v1\nbaseDomain: example.com\n...\n",
+		},
+		{
+			name:              "manifests",
+			data:              "---\napiVersion: v1\nbaseDomain: example.com\n--- # extra\napiVersion: v1\nkind: ConfigMap\n---\n# nothing\n---\nkind: Secret\n",
+			expectedConfig:    "apiVersion: v1\nbaseDomain: example.com\n",
+			expectedDocuments: []string{"apiVersion: v1\nkind: ConfigMap\n", "kind: Secret\n"},
+		},
+		{
+			name:           "block scalar",
+			data:           "apiVersion: v1\nadditionalTrustBundle: |\n  ---\n  data\n",
+			expectedConfig: "apiVersion: v1\nadditionalTrustBundle: |\n  ---\n  data\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, documents := splitDocuments([]byte(tc.data))
+			assert.Equal(t, tc.expectedConfig, string(config))
+			var actual []string
+			for _, document := range documents {
+				actual = append(actual, string(document))
+			}
+			assert.Equal(t, tc.expectedDocuments, actual)
+		})
+	}
+}
+
+func TestCustomManifests(t *testing.T) {
+	files, err := customManifests([][]byte{
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: extra\n  namespace: openshift-config\n"),
+		[]byte("apiVersion: machineconfiguration.openshift.io/v1\nkind: MachineConfig\nmetadata:\n  name: 99-worker-chrony\n"),
+	})
+	if assert.NoError(t, err) && assert.Len(t, files, 2) {
+		assert.Equal(t, "99_install-config-configmap-openshift-config-extra.yaml", files[0].Filename)
+		assert.Equal(t, "99_install-config-machineconfig-99-worker-chrony.yaml", files[1].Filename)
+	}
+
+	_, err = customManifests([][]byte{[]byte("apiVersion: v1\nmetadata:\n  name: extra\n")})
+	assert.EqualError(t, err, "invalid manifest in document 2: apiVersion, kind and metadata.name are required")
+
+	_, err = customManifests([][]byte{
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: extra\n"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: extra\n"),
+	})
+	assert.EqualError(t, err, "the manifests in documents 2 and 3 are both ConfigMap extra")
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := []byte("apiVersion: v1\nbaseDomain: example.com\ncompute:\n- name: worker\n  platform:\n    kubevirt:\n      storgeSize: 100Gi\n")
+
+	err := Unmarshal(data, &types.InstallConfig{}, false)
+	assert.Regexp(t, `unknown field "storgeSize"`, err)
+
+	config := &types.InstallConfig{}
+	if assert.NoError(t, Unmarshal(data, config, true)) {
+		assert.Equal(t, "example.com", config.BaseDomain)
+	}
+}
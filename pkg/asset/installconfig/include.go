@@ -0,0 +1,103 @@
+package installconfig
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// includeTag is the tag of the YAML values replaced by the content of the file they name,
+// letting install-configs share common sections, for instance the platform of the clusters
+// of a fleet:
+//
+//   platform: !include common/platform.yaml
+//
+// The paths are relative to the including file, and must stay within the installation directory.
+const includeTag = "!include"
+
+// resolveIncludes returns the content of the file, with its included files and theirs
+// substituted for the values tagged with includeTag.
+func resolveIncludes(f asset.FileFetcher, filename string, data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(includeTag)) {
+		return data, nil
+	}
+	document := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(data, document); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", filename)
+	}
+	if err := expandIncludes(f, document, []string{filename}); err != nil {
+		return nil, err
+	}
+	return yamlv3.Marshal(document)
+}
+
+// expandIncludes replaces the included nodes of the tree with the content of their files. The
+// stack holds the files being included, the last one holding the node.
+func expandIncludes(f asset.FileFetcher, node *yamlv3.Node, stack []string) error {
+	if node.Tag != includeTag {
+		for _, child := range node.Content {
+			if err := expandIncludes(f, child, stack); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	including := stack[len(stack)-1]
+	if node.Kind != yamlv3.ScalarNode {
+		return errors.Errorf("%s: line %d: %s must be followed by a path", including, node.Line, includeTag)
+	}
+	filename, err := includePath(including, node.Value)
+	if err != nil {
+		return errors.Wrapf(err, "%s: line %d", including, node.Line)
+	}
+	for i, name := range stack {
+		if name == filename {
+			return errors.Errorf("include cycle: %s", strings.Join(append(stack[i:], filename), " -> "))
+		}
+	}
+
+	file, err := f.FetchByName(filename)
+	if err != nil {
+		return errors.Wrapf(err, "%s: line %d: failed to include %s", including, node.Line, filename)
+	}
+	document := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(file.Data, document); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", filename)
+	}
+	if len(document.Content) == 0 {
+		return errors.Errorf("%s: line %d: included file %s is empty", including, node.Line, filename)
+	}
+	included := document.Content[0]
+	if err := expandIncludes(f, included, append(stack[:len(stack):len(stack)], filename)); err != nil {
+		return err
+	}
+	// Aliases of an anchored include refer to the node, keep its anchor
+	anchor := node.Anchor
+	*node = *included
+	if anchor != "" {
+		node.Anchor = anchor
+	}
+	return nil
+}
+
+// includePath returns the path, relative to the installation directory, of the file included
+// by the including file, rejecting the paths outside of the installation directory.
+func includePath(including string, path string) (string, error) {
+	if path == "" {
+		return "", errors.Errorf("%s must be followed by a path", includeTag)
+	}
+	if filepath.IsAbs(path) {
+		return "", errors.Errorf("included path %s must be relative", path)
+	}
+	filename := filepath.Join(filepath.Dir(including), path)
+	if filename == ".." || strings.HasPrefix(filename, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("included path %s is outside of the installation directory", path)
+	}
+	return filename, nil
+}
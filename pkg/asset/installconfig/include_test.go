@@ -0,0 +1,113 @@
+package installconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// mapFetcher fetches the files from a map of their names to their content.
+type mapFetcher map[string]string
+
+func (m mapFetcher) FetchByName(name string) (*asset.File, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &asset.File{Filename: name, Data: []byte(data)}, nil
+}
+
+func (m mapFetcher) FetchByPattern(pattern string) ([]*asset.File, error) {
+	return nil, nil
+}
+
+func TestResolveIncludes(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         mapFetcher
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "no include",
+			files: mapFetcher{
+				"install-config.yaml": "metadata:\n  name: test-cluster\n",
+			},
+			expected: `{"metadata":{"name":"test-cluster"}}`,
+		},
+		{
+			name: "include",
+			files: mapFetcher{
+				"install-config.yaml":  "metadata:\n  name: test-cluster\nplatform: !include common/platform.yaml\n",
+				"common/platform.yaml": "kubevirt:\n  namespace: fleet\n  networkName: !include network.yaml\n",
+				"common/network.yaml":  "fleet-network\n",
+			},
+			expected: `{"metadata":{"name":"test-cluster"},"platform":{"kubevirt":{"namespace":"fleet","networkName":"fleet-network"}}}`,
+		},
+		{
+			name: "include merged",
+			files: mapFetcher{
+				"install-config.yaml":  "platform:\n  kubevirt:\n    <<: !include common/kubevirt.yaml\n    namespace: cluster\n",
+				"common/kubevirt.yaml": "namespace: fleet\nstorageClass: fast\n",
+			},
+			expected: `{"platform":{"kubevirt":{"namespace":"cluster","storageClass":"fast"}}}`,
+		},
+		{
+			name: "include cycle",
+			files: mapFetcher{
+				"install-config.yaml": "platform: !include a.yaml\n",
+				"a.yaml":              "kubevirt: !include b.yaml\n",
+				"b.yaml":              "namespace: !include a.yaml\n",
+			},
+			expectedError: `include cycle: a.yaml -> b.yaml -> a.yaml`,
+		},
+		{
+			name: "include outside of the installation directory",
+			files: mapFetcher{
+				"install-config.yaml": "platform: !include common/../../platform.yaml\n",
+			},
+			expectedError: `install-config.yaml: line 1: included path common/../../platform.yaml is outside of the installation directory`,
+		},
+		{
+			name: "absolute include",
+			files: mapFetcher{
+				"install-config.yaml": "platform: !include /etc/platform.yaml\n",
+			},
+			expectedError: `install-config.yaml: line 1: included path /etc/platform.yaml must be relative`,
+		},
+		{
+			name: "include of a mapping",
+			files: mapFetcher{
+				"install-config.yaml": "platform: !include\n  path: platform.yaml\n",
+			},
+			expectedError: `install-config.yaml: line 1: !include must be followed by a path`,
+		},
+		{
+			name: "missing include",
+			files: mapFetcher{
+				"install-config.yaml": "platform: !include platform.yaml\n",
+			},
+			expectedError: `install-config.yaml: line 1: failed to include platform.yaml: open platform.yaml: file does not exist`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := resolveIncludes(tc.files, installConfigFilename, []byte(tc.files[installConfigFilename]))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			actual, err := yaml.YAMLToJSON(data)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tc.expected, string(actual))
+			}
+		})
+	}
+}
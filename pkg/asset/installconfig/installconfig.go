@@ -6,6 +6,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -17,6 +18,7 @@ import (
 	icopenstack "github.com/openshift/installer/pkg/asset/installconfig/openstack"
 	icovirt "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	icvsphere "github.com/openshift/installer/pkg/asset/installconfig/vsphere"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/conversion"
 	"github.com/openshift/installer/pkg/types/defaults"
@@ -33,6 +35,9 @@ type InstallConfig struct {
 	File   *asset.File          `json:"file"`
 	AWS    *aws.Metadata        `json:"aws,omitempty"`
 	Azure  *icazure.Metadata    `json:"azure,omitempty"`
+	// Manifests are the custom manifests of the cluster, from the documents following the
+	// install-config in its file.
+	Manifests []*asset.File `json:"manifests,omitempty"`
 }
 
 var _ asset.WritableAsset = (*InstallConfig)(nil)
@@ -111,7 +116,8 @@ func (a *InstallConfig) Files() []*asset.File {
 	return []*asset.File{}
 }
 
-// Load returns the installconfig from disk.
+// Load returns the installconfig from disk, ignoring its unknown fields when the file fetcher
+// loads the files leniently.
 func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 	file, err := f.FetchByName(installConfigFilename)
 	if err != nil {
@@ -121,11 +127,28 @@ func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, err
 	}
 
+	data, documents := splitDocuments(file.Data)
+	manifests, err := customManifests(documents)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to load the custom manifests of %s", installConfigFilename)
+	}
+
+	data, err = resolveIncludes(f, installConfigFilename, data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to resolve the includes of %s", installConfigFilename)
+	}
+
+	// The unknown fields are ignored when the files are loaded leniently
+	lenient := false
+	if lenientFetcher, ok := f.(asset.LenientFileFetcher); ok {
+		lenient = lenientFetcher.Lenient()
+	}
 	config := &types.InstallConfig{}
-	if err := yaml.Unmarshal(file.Data, config); err != nil {
+	if err := Unmarshal(data, config, lenient); err != nil {
 		return false, errors.Wrapf(err, "failed to unmarshal %s", installConfigFilename)
 	}
 	a.Config = config
+	a.Manifests = manifests
 
 	// Upconvert any deprecated fields
 	if err := conversion.ConvertInstallConfig(a.Config); err != nil {
@@ -155,14 +178,51 @@ func (a *InstallConfig) finish(filename string) error {
 		return errors.Wrapf(err, "invalid %q file", filename)
 	}
 
+	if _, err := ReadAPIServerCertificate(a.Config); err != nil {
+		if filename == "" {
+			return errors.Wrap(err, "invalid install config")
+		}
+		return errors.Wrapf(err, "invalid %q file", filename)
+	}
+
+	pullSpec, _, err := releaseimage.PullSpec()
+	if err != nil {
+		return err
+	}
+	if len(a.Config.ImageContentSources) > 0 {
+		if err := validation.ValidateReleaseImageMirrors(pullSpec, a.Config.ImageContentSources, field.NewPath("imageContentSources")).ToAggregate(); err != nil {
+			if filename == "" {
+				return errors.Wrap(err, "invalid install config")
+			}
+			return errors.Wrapf(err, "invalid %q file", filename)
+		}
+	}
+	// The registries may allow anonymous pulls, the missing credentials are only an error when
+	// the pull secret is scoped, its credentials being selected for the cluster
+	if err := validation.ValidateReleaseImagePullSecret(a.Config.PullSecret, pullSpec, a.Config.ImageContentSources, field.NewPath("pullSecret")).ToAggregate(); err != nil {
+		if a.Config.PullSecretScope == nil {
+			logrus.Warn(err)
+		} else if filename == "" {
+			return errors.Wrap(err, "invalid install config")
+		} else {
+			return errors.Wrapf(err, "invalid %q file", filename)
+		}
+	}
+
 	if err := a.platformValidation(); err != nil {
 		return err
 	}
+	for _, warning := range validation.Lint(a.Config) {
+		logrus.Warn(warning)
+	}
 
 	data, err := yaml.Marshal(a.Config)
 	if err != nil {
 		return errors.Wrap(err, "failed to Marshal InstallConfig")
 	}
+	for _, manifest := range a.Manifests {
+		data = append(append(data, "---\n"...), manifest.Data...)
+	}
 	a.File = &asset.File{
 		Filename: installConfigFilename,
 		Data:     data,
@@ -198,7 +258,7 @@ func (a *InstallConfig) platformValidation() error {
 		return icopenstack.Validate(a.Config)
 	}
 	if a.Config.Platform.Kubevirt != nil {
-		clientBuilderFunc := ickubevirt.NewClient
+		clientBuilderFunc := ickubevirt.ClientBuilder(a.Config.Platform.Kubevirt.InfraKubeconfigPath, a.Config.Platform.Kubevirt.InfraContext, a.Config.Platform.Kubevirt.InfraCABundle)
 		return ickubevirt.Validate(a.Config, clientBuilderFunc)
 	}
 	return field.ErrorList{}.ToAggregate()
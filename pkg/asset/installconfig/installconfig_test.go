@@ -22,7 +22,7 @@ func TestInstallConfigGenerate_FillsInDefaults(t *testing.T) {
 	sshPublicKey := &sshPublicKey{}
 	baseDomain := &baseDomain{"test-domain"}
 	clusterName := &clusterName{"test-cluster"}
-	pullSecret := &pullSecret{`{"auths":{"example.com":{"auth":"authorization value"}}}`}
+	pullSecret := &pullSecret{`{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`}
 	platform := &platform{
 		Platform: types.Platform{None: &none.Platform{}},
 	}
@@ -78,17 +78,71 @@ func TestInstallConfigGenerate_FillsInDefaults(t *testing.T) {
 		Platform: types.Platform{
 			None: &none.Platform{},
 		},
-		PullSecret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+		PullSecret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
 		Publish:    types.ExternalPublishingStrategy,
 	}
 	assert.Equal(t, expected, installConfig.Config, "unexpected config generated")
 }
 
+// lenientFileFetcher loads the files of its file fetcher leniently.
+type lenientFileFetcher struct {
+	asset.FileFetcher
+}
+
+func (lenientFileFetcher) Lenient() bool {
+	return true
+}
+
 func TestInstallConfigLoad(t *testing.T) {
+	oldConfig := &types.InstallConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: types.InstallConfigVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-cluster",
+		},
+		BaseDomain: "test-domain",
+		Networking: &types.Networking{
+			MachineNetwork: []types.MachineNetworkEntry{
+				{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")},
+			},
+			NetworkType:    "OpenShiftSDN",
+			ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+			ClusterNetwork: []types.ClusterNetworkEntry{
+				{
+					CIDR:       *ipnet.MustParseCIDR("10.128.0.0/14"),
+					HostPrefix: 23,
+				},
+			},
+		},
+		ControlPlane: &types.MachinePool{
+			Name:           "master",
+			Replicas:       pointer.Int64Ptr(3),
+			Hyperthreading: types.HyperthreadingEnabled,
+			Architecture:   types.ArchitectureAMD64,
+		},
+		Compute: []types.MachinePool{
+			{
+				Name:           "worker",
+				Replicas:       pointer.Int64Ptr(3),
+				Hyperthreading: types.HyperthreadingEnabled,
+				Architecture:   types.ArchitectureAMD64,
+			},
+		},
+		Platform: types.Platform{
+			AWS: &aws.Platform{
+				Region: "us-east-1",
+			},
+		},
+		PullSecret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
+		Publish:    types.ExternalPublishingStrategy,
+	}
+
 	cases := []struct {
 		name           string
 		data           string
 		fetchError     error
+		lenient        bool
 		expectedFound  bool
 		expectedError  bool
 		expectedConfig *types.InstallConfig
@@ -103,7 +157,7 @@ baseDomain: test-domain
 platform:
   aws:
     region: us-east-1
-pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"dXNlcjpwYXNzd29yZA==\"}}}"
 `,
 			expectedFound: true,
 			expectedConfig: &types.InstallConfig{
@@ -146,7 +200,7 @@ pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
 						Region: "us-east-1",
 					},
 				},
-				PullSecret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+				PullSecret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
 				Publish:    types.ExternalPublishingStrategy,
 			},
 		},
@@ -158,6 +212,21 @@ metadata:
 `,
 			expectedError: true,
 		},
+		{
+			name: "unknown field",
+			data: `
+apiVersion: v1
+metadata:
+  name: test-cluster
+baseDomian: test-domain
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"dXNlcjpwYXNzd29yZA==\"}}}"
+`,
+			expectedFound: false,
+			expectedError: true,
+		},
 		{
 			name:          "empty",
 			data:          "",
@@ -187,54 +256,45 @@ baseDomain: test-domain
 platform:
   aws:
     region: us-east-1
-pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"dXNlcjpwYXNzd29yZA==\"}}}"
+`,
+			expectedFound:  true,
+			expectedConfig: oldConfig,
+		},
+		{
+			name: "old InstallConfig with a removed field",
+			data: `
+apiVersion: v1beta3
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"dXNlcjpwYXNzd29yZA==\"}}}"
 network:
   type: OpenShiftSDN
 `,
-			expectedFound: true,
-			expectedConfig: &types.InstallConfig{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "test-cluster",
-				},
-				BaseDomain: "test-domain",
-				Networking: &types.Networking{
-					MachineNetwork: []types.MachineNetworkEntry{
-						{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")},
-					},
-					NetworkType:    "OpenShiftSDN",
-					ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
-					ClusterNetwork: []types.ClusterNetworkEntry{
-						{
-							CIDR:       *ipnet.MustParseCIDR("10.128.0.0/14"),
-							HostPrefix: 23,
-						},
-					},
-				},
-				ControlPlane: &types.MachinePool{
-					Name:           "master",
-					Replicas:       pointer.Int64Ptr(3),
-					Hyperthreading: types.HyperthreadingEnabled,
-					Architecture:   types.ArchitectureAMD64,
-				},
-				Compute: []types.MachinePool{
-					{
-						Name:           "worker",
-						Replicas:       pointer.Int64Ptr(3),
-						Hyperthreading: types.HyperthreadingEnabled,
-						Architecture:   types.ArchitectureAMD64,
-					},
-				},
-				Platform: types.Platform{
-					AWS: &aws.Platform{
-						Region: "us-east-1",
-					},
-				},
-				PullSecret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
-				Publish:    types.ExternalPublishingStrategy,
-			},
+			expectedFound: false,
+			expectedError: true,
+		},
+		{
+			name: "old InstallConfig with a removed field, lenient",
+			data: `
+apiVersion: v1beta3
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"dXNlcjpwYXNzd29yZA==\"}}}"
+network:
+  type: OpenShiftSDN
+`,
+			lenient:        true,
+			expectedFound:  true,
+			expectedConfig: oldConfig,
 		},
 	}
 	for _, tc := range cases {
@@ -251,8 +311,12 @@ network:
 					tc.fetchError,
 				)
 
+			var fetcher asset.FileFetcher = fileFetcher
+			if tc.lenient {
+				fetcher = lenientFileFetcher{fileFetcher}
+			}
 			ic := &InstallConfig{}
-			found, err := ic.Load(fileFetcher)
+			found, err := ic.Load(fetcher)
 			assert.Equal(t, tc.expectedFound, found, "unexpected found value returned from Load")
 			if tc.expectedError {
 				assert.Error(t, err, "expected error from Load")
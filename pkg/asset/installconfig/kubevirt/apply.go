@@ -0,0 +1,115 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// FieldManager is the field manager of the fields of the infra cluster resources the installer
+// creates and converges.
+const FieldManager = "openshift-installer"
+
+// createOrConverge creates the resource or, when it already exists (e.g. created by a previous
+// run which failed afterwards), converges it to the desired one with a server-side apply, so that
+// creating the cluster again is idempotent. The existing resource is left untouched when its spec,
+// or data, already holds the desired one and it has all the desired labels and annotations; the
+// values of the existing labels and annotations are kept. Only the labels and annotations of the
+// existing DataVolumes are converged, the DataVolumes being reused as they are. Conflicts are
+// retried.
+func (c *client) createOrConverge(ctx context.Context, resource schema.GroupVersionResource, desired *unstructured.Unstructured) error {
+	resources := c.dynamicClient.Resource(resource).Namespace(desired.GetNamespace())
+	_, err := resources.Create(ctx, desired, metav1.CreateOptions{FieldManager: FieldManager})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := resources.Get(ctx, desired.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		converged := convergedObject(existing, desired, convergedFields(resource))
+		if converged == nil {
+			return nil
+		}
+		data, err := json.Marshal(converged.Object)
+		if err != nil {
+			return err
+		}
+		force := true
+		_, err = resources.Patch(ctx, desired.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: FieldManager, Force: &force})
+		if err != nil && !apierrors.IsConflict(err) {
+			return fmt.Errorf("%s %s already exists and could not be converged: %v", resource.Resource, desired.GetName(), err)
+		}
+		return err
+	})
+}
+
+// immutableSpecResources are the resources whose spec can't be updated once they are created,
+// CDI rejecting the updates of the spec of the DataVolumes.
+var immutableSpecResources = map[string]bool{
+	"datavolumes": true,
+}
+
+// convergedFields returns the fields of the resources converged to the desired ones: the spec of
+// most resources and the data of the secrets, none for the resources with an immutable spec.
+func convergedFields(resource schema.GroupVersionResource) []string {
+	if immutableSpecResources[resource.Resource] {
+		return nil
+	}
+	return []string{"spec", "data"}
+}
+
+// convergedObject returns the object to apply to converge the existing resource to the desired
+// one: its fields, and the labels and annotations the existing resource is missing. It returns
+// nil when the existing resource already holds the desired one.
+func convergedObject(existing *unstructured.Unstructured, desired *unstructured.Unstructured, fields []string) *unstructured.Unstructured {
+	labels := missing(existing.GetLabels(), desired.GetLabels())
+	annotations := missing(existing.GetAnnotations(), desired.GetAnnotations())
+	converges := len(labels) > 0 || len(annotations) > 0
+	for _, field := range fields {
+		if value, ok := desired.Object[field]; ok && !equality.Semantic.DeepDerivative(value, existing.Object[field]) {
+			converges = true
+		}
+	}
+	if !converges {
+		return nil
+	}
+
+	converged := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	converged.SetAPIVersion(desired.GetAPIVersion())
+	converged.SetKind(desired.GetKind())
+	converged.SetNamespace(desired.GetNamespace())
+	converged.SetName(desired.GetName())
+	for _, field := range fields {
+		if value, ok := desired.Object[field]; ok {
+			converged.Object[field] = value
+		}
+	}
+	if len(labels) > 0 {
+		converged.SetLabels(labels)
+	}
+	if len(annotations) > 0 {
+		converged.SetAnnotations(annotations)
+	}
+	return converged
+}
+
+// missing returns the entries of desired whose keys are not in existing.
+func missing(existing map[string]string, desired map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range desired {
+		if _, ok := existing[key]; !ok {
+			result[key] = value
+		}
+	}
+	return result
+}
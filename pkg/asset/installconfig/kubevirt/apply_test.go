@@ -0,0 +1,205 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestConvergedObject(t *testing.T) {
+	object := func(labels map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetAPIVersion("cdi.kubevirt.io/v1alpha1")
+		u.SetKind("DataVolume")
+		u.SetNamespace("ns")
+		u.SetName("infra-id-source")
+		u.SetLabels(labels)
+		if spec != nil {
+			u.Object["spec"] = spec
+		}
+		return u
+	}
+	spec := map[string]interface{}{"source": map[string]interface{}{"http": map[string]interface{}{"url": "https://example.com/rhcos.img"}}}
+	otherSpec := map[string]interface{}{"source": map[string]interface{}{"http": map[string]interface{}{"url": "https://example.com/other.img"}}}
+	defaulted := map[string]interface{}{
+		"source":            map[string]interface{}{"http": map[string]interface{}{"url": "https://example.com/rhcos.img"}},
+		"preallocation":     false,
+		"contentType":       "kubevirt",
+		"priorityClassName": "",
+	}
+
+	cases := []struct {
+		name           string
+		existing       *unstructured.Unstructured
+		desired        *unstructured.Unstructured
+		expectedLabels map[string]string
+		expectedSpec   interface{}
+	}{
+		{
+			name:     "same",
+			existing: object(map[string]string{"owner": "owned"}, spec),
+			desired:  object(map[string]string{"owner": "owned"}, spec),
+		},
+		{
+			name:     "defaulted by the server",
+			existing: object(map[string]string{"owner": "owned", "other": "value"}, defaulted),
+			desired:  object(map[string]string{"owner": "owned"}, spec),
+		},
+		{
+			name:     "label with another value kept",
+			existing: object(map[string]string{"owner": "owned", "run": "previous"}, spec),
+			desired:  object(map[string]string{"owner": "owned", "run": "current"}, spec),
+		},
+		{
+			name:           "missing label",
+			existing:       object(nil, spec),
+			desired:        object(map[string]string{"owner": "owned"}, spec),
+			expectedLabels: map[string]string{"owner": "owned"},
+			expectedSpec:   spec,
+		},
+		{
+			name:         "other spec",
+			existing:     object(map[string]string{"owner": "owned"}, otherSpec),
+			desired:      object(map[string]string{"owner": "owned"}, spec),
+			expectedSpec: spec,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			converged := convergedObject(tc.existing, tc.desired, []string{"spec", "data"})
+			if tc.expectedLabels == nil && tc.expectedSpec == nil {
+				assert.Nil(t, converged)
+				return
+			}
+			if assert.NotNil(t, converged) {
+				assert.Equal(t, "DataVolume", converged.GetKind())
+				assert.Equal(t, "infra-id-source", converged.GetName())
+				assert.Equal(t, tc.expectedLabels, converged.GetLabels())
+				assert.Equal(t, tc.expectedSpec, converged.Object["spec"])
+			}
+		})
+	}
+}
+
+func TestConvergedObjectData(t *testing.T) {
+	secret := func(data map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetAPIVersion("v1")
+		u.SetKind("Secret")
+		u.SetNamespace("ns")
+		u.SetName("infra-id-worker-ignition")
+		u.Object["data"] = data
+		return u
+	}
+	data := map[string]interface{}{"worker.ign": "e30="}
+
+	fields := convergedFields(schema.GroupVersionResource{Version: "v1", Resource: "secrets"})
+	assert.Nil(t, convergedObject(secret(data), secret(data), fields))
+	converged := convergedObject(secret(map[string]interface{}{"worker.ign": "e30K"}), secret(data), fields)
+	if assert.NotNil(t, converged) {
+		assert.Equal(t, data, converged.Object["data"])
+		assert.Nil(t, converged.Object["spec"])
+	}
+}
+
+func TestCreateOrConvergeExisting(t *testing.T) {
+	labels := map[string]string{"tenantcluster-infra-id-machine.openshift.io": "owned", "installer.openshift.io/run-id": "current"}
+	object := func(apiVersion string, kind string, name string, labels map[string]string, field string, value map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{field: value}}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace("tenant")
+		u.SetName(name)
+		u.SetLabels(labels)
+		return u
+	}
+	dataVolume := func(url string, labels map[string]string) *unstructured.Unstructured {
+		return object("cdi.kubevirt.io/v1alpha1", "DataVolume", "infra-id-source-pvc", labels, "spec", map[string]interface{}{
+			"source": map[string]interface{}{"http": map[string]interface{}{"url": url}},
+		})
+	}
+	secret := func(ignition string, labels map[string]string) *unstructured.Unstructured {
+		return object("v1", "Secret", "infra-id-worker-ignition", labels, "data", map[string]interface{}{"worker.ign": ignition})
+	}
+
+	cases := []struct {
+		name          string
+		path          string
+		existing      *unstructured.Unstructured
+		create        func(c *client, ctx context.Context) error
+		expectedPatch string
+	}{
+		{
+			// CDI rejects the updates of the spec of the DataVolumes, the existing one is reused
+			name:     "DataVolume",
+			path:     "/apis/cdi.kubevirt.io/v1alpha1/namespaces/tenant/datavolumes",
+			existing: dataVolume("https://example.com/previous.img", map[string]string{"installer.openshift.io/run-id": "previous"}),
+			create: func(c *client, ctx context.Context) error {
+				return c.CreateDataVolume(ctx, dataVolume("https://example.com/rhcos.img", labels))
+			},
+			expectedPatch: `{"apiVersion":"cdi.kubevirt.io/v1alpha1","kind":"DataVolume","metadata":{"labels":{"tenantcluster-infra-id-machine.openshift.io":"owned"},"name":"infra-id-source-pvc","namespace":"tenant"}}`,
+		},
+		{
+			name:     "DataVolume with the labels",
+			path:     "/apis/cdi.kubevirt.io/v1alpha1/namespaces/tenant/datavolumes",
+			existing: dataVolume("https://example.com/previous.img", labels),
+			create: func(c *client, ctx context.Context) error {
+				return c.CreateDataVolume(ctx, dataVolume("https://example.com/rhcos.img", labels))
+			},
+		},
+		{
+			name:     "Secret",
+			path:     "/api/v1/namespaces/tenant/secrets",
+			existing: secret("e30K", labels),
+			create: func(c *client, ctx context.Context) error {
+				return c.ApplyResource(ctx, "secrets", secret("e30=", labels))
+			},
+			expectedPatch: `{"apiVersion":"v1","data":{"worker.ign":"e30="},"kind":"Secret","metadata":{"name":"infra-id-worker-ignition","namespace":"tenant"}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var patches []string
+			c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == tc.path:
+					w.WriteHeader(http.StatusConflict)
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "Status",
+						"status":     "Failure",
+						"reason":     "AlreadyExists",
+						"code":       http.StatusConflict,
+					})
+				case r.Method == http.MethodGet && r.URL.Path == tc.path+"/"+tc.existing.GetName():
+					json.NewEncoder(w).Encode(tc.existing.Object)
+				case r.Method == http.MethodPatch && r.URL.Path == tc.path+"/"+tc.existing.GetName():
+					assert.Equal(t, "application/apply-patch+yaml", r.Header.Get("Content-Type"))
+					assert.Equal(t, FieldManager, r.URL.Query().Get("fieldManager"))
+					body, err := ioutil.ReadAll(r.Body)
+					assert.NoError(t, err)
+					patches = append(patches, string(body))
+					json.NewEncoder(w).Encode(tc.existing.Object)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			defer stop()
+
+			assert.NoError(t, tc.create(c, context.Background()))
+			if tc.expectedPatch == "" {
+				assert.Empty(t, patches)
+				return
+			}
+			assert.Equal(t, []string{tc.expectedPatch}, patches)
+		})
+	}
+}
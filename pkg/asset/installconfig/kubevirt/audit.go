@@ -0,0 +1,247 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AuditLogName is the name of the file, in the assets directory, recording the
+// mutations done by the installer in the infra cluster.
+const AuditLogName = ".openshift_install_kubevirt_audit.log"
+
+var (
+	auditLogPath string
+	auditLogLock sync.Mutex
+)
+
+// SetAuditLogPath makes the clients returned by NewClient record every mutation of
+// the infra cluster into the file at path. An empty path disables the audit log.
+func SetAuditLogPath(path string) {
+	auditLogLock.Lock()
+	defer auditLogLock.Unlock()
+	auditLogPath = path
+}
+
+// AuditRecord is a single mutation of the infra cluster, written as a JSON line to the audit log.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Verb      string    `json:"verb"`
+	Resource  string    `json:"resource"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	Latency   string    `json:"latency"`
+}
+
+// auditingClient records the mutations done through the wrapped Client.
+type auditingClient struct {
+	Client
+	path string
+}
+
+// newAuditingClient returns a Client recording every mutation done through client into the audit log at path.
+func newAuditingClient(client Client, path string) Client {
+	return &auditingClient{Client: client, path: path}
+}
+
+func (c *auditingClient) DeleteVirtualMachine(namespace string, name string, wait bool) error {
+	return c.audit("delete", "virtualmachines", namespace, name, func() error {
+		return c.Client.DeleteVirtualMachine(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error {
+	return c.audit("create", "datavolumes", dv.GetNamespace(), dv.GetName(), func() error {
+		return c.Client.CreateDataVolume(ctx, dv)
+	})
+}
+
+func (c *auditingClient) DeleteDataVolume(namespace string, name string, wait bool) error {
+	return c.audit("delete", "datavolumes", namespace, name, func() error {
+		return c.Client.DeleteDataVolume(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) DeleteSecret(namespace string, name string, wait bool) error {
+	return c.audit("delete", "secrets", namespace, name, func() error {
+		return c.Client.DeleteSecret(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error {
+	return c.audit("create", "network-attachment-definitions", nad.GetNamespace(), nad.GetName(), func() error {
+		return c.Client.CreateNetworkAttachmentDefinition(ctx, nad)
+	})
+}
+
+func (c *auditingClient) DeleteNetworkAttachmentDefinition(namespace string, name string, wait bool) error {
+	return c.audit("delete", "network-attachment-definitions", namespace, name, func() error {
+		return c.Client.DeleteNetworkAttachmentDefinition(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) CreateOrUpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	return c.audit("apply", "configmaps", configMap.Namespace, configMap.Name, func() error {
+		return c.Client.CreateOrUpdateConfigMap(ctx, configMap)
+	})
+}
+
+func (c *auditingClient) AnnotateConfigMap(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return c.audit("patch", "configmaps", namespace, name, func() error {
+		return c.Client.AnnotateConfigMap(ctx, namespace, name, annotations)
+	})
+}
+
+func (c *auditingClient) DeleteConfigMap(namespace string, name string, wait bool) error {
+	return c.audit("delete", "configmaps", namespace, name, func() error {
+		return c.Client.DeleteConfigMap(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) CreateEvent(ctx context.Context, event *corev1.Event) error {
+	return c.audit("create", "events", event.Namespace, event.Name, func() error {
+		return c.Client.CreateEvent(ctx, event)
+	})
+}
+
+func (c *auditingClient) CreatePod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	var created *corev1.Pod
+	err := c.audit("create", "pods", pod.Namespace, pod.Name, func() error {
+		var err error
+		created, err = c.Client.CreatePod(ctx, pod)
+		return err
+	})
+	return created, err
+}
+
+func (c *auditingClient) DeletePod(namespace string, name string, wait bool) error {
+	return c.audit("delete", "pods", namespace, name, func() error {
+		return c.Client.DeletePod(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) CreateServiceAccountToken(ctx context.Context, namespace string, name string, expiration time.Duration) (string, error) {
+	var token string
+	err := c.audit("create", "serviceaccounts/token", namespace, name, func() error {
+		var err error
+		token, err = c.Client.CreateServiceAccountToken(ctx, namespace, name, expiration)
+		return err
+	})
+	return token, err
+}
+
+func (c *auditingClient) StopVirtualMachine(namespace string, name string, gracePeriod time.Duration) error {
+	return c.audit("stop", "virtualmachines", namespace, name, func() error {
+		return c.Client.StopVirtualMachine(namespace, name, gracePeriod)
+	})
+}
+
+func (c *auditingClient) StartVirtualMachine(namespace string, name string) error {
+	return c.audit("start", "virtualmachines", namespace, name, func() error {
+		return c.Client.StartVirtualMachine(namespace, name)
+	})
+}
+
+func (c *auditingClient) AddResourceLabels(ctx context.Context, namespace string, resource string, name string, labels map[string]string) error {
+	return c.audit("patch", resource, namespace, name, func() error {
+		return c.Client.AddResourceLabels(ctx, namespace, resource, name, labels)
+	})
+}
+
+func (c *auditingClient) SetVirtualMachineRunStrategy(namespace string, name string, runStrategy string) error {
+	return c.audit("patch", "virtualmachines", namespace, name, func() error {
+		return c.Client.SetVirtualMachineRunStrategy(namespace, name, runStrategy)
+	})
+}
+
+func (c *auditingClient) AnnotateVirtualMachine(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return c.audit("patch", "virtualmachines", namespace, name, func() error {
+		return c.Client.AnnotateVirtualMachine(ctx, namespace, name, annotations)
+	})
+}
+
+func (c *auditingClient) DeleteMultiNetworkPolicy(namespace string, name string, wait bool) error {
+	return c.audit("delete", "multi-networkpolicies", namespace, name, func() error {
+		return c.Client.DeleteMultiNetworkPolicy(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) DeletePodDisruptionBudget(namespace string, name string, wait bool) error {
+	return c.audit("delete", "poddisruptionbudgets", namespace, name, func() error {
+		return c.Client.DeletePodDisruptionBudget(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error {
+	return c.audit("apply", resource, obj.GetNamespace(), obj.GetName(), func() error {
+		return c.Client.ApplyResource(ctx, resource, obj)
+	})
+}
+
+func (c *auditingClient) DeleteDeployment(namespace string, name string, wait bool) error {
+	return c.audit("delete", "deployments", namespace, name, func() error {
+		return c.Client.DeleteDeployment(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) DeleteService(namespace string, name string, wait bool) error {
+	return c.audit("delete", "services", namespace, name, func() error {
+		return c.Client.DeleteService(namespace, name, wait)
+	})
+}
+
+func (c *auditingClient) DeleteEndpoints(namespace string, name string, wait bool) error {
+	return c.audit("delete", "endpoints", namespace, name, func() error {
+		return c.Client.DeleteEndpoints(namespace, name, wait)
+	})
+}
+
+// audit runs the mutation and records its result and latency.
+func (c *auditingClient) audit(verb string, resource string, namespace string, name string, mutation func() error) error {
+	start := time.Now()
+	err := mutation()
+	record := AuditRecord{
+		Time:      start.UTC(),
+		Verb:      verb,
+		Resource:  resource,
+		Namespace: namespace,
+		Name:      name,
+		Result:    "success",
+		Latency:   time.Since(start).String(),
+	}
+	if err != nil {
+		record.Result = "failure"
+		record.Error = err.Error()
+	}
+	if writeErr := c.write(record); writeErr != nil {
+		logrus.Warnf("Failed to write the infra cluster audit log: %v", writeErr)
+	}
+	return err
+}
+
+func (c *auditingClient) write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	auditLogLock.Lock()
+	defer auditLogLock.Unlock()
+	file, err := os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
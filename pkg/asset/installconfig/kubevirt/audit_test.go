@@ -0,0 +1,64 @@
+package kubevirt
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+func TestAuditingClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, AuditLogName)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	kubevirtClient := mock.NewMockClient(mockCtrl)
+	kubevirtClient.EXPECT().DeleteVirtualMachine("ns", "vm", true).Return(nil)
+	kubevirtClient.EXPECT().DeleteDataVolume("ns", "dv", true).Return(errors.New("forbidden"))
+	kubevirtClient.EXPECT().DeleteSecret("ns", "secret", false).Return(nil)
+	kubevirtClient.EXPECT().ListSecretNames("ns", nil).Return(nil, nil)
+
+	client := newAuditingClient(kubevirtClient, path)
+	assert.NoError(t, client.DeleteVirtualMachine("ns", "vm", true))
+	assert.EqualError(t, client.DeleteDataVolume("ns", "dv", true), "forbidden")
+	assert.NoError(t, client.DeleteSecret("ns", "secret", false))
+	// Reads are not recorded
+	_, err = client.ListSecretNames("ns", nil)
+	assert.NoError(t, err)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotEmpty(t, record.Latency)
+		record.Time, record.Latency = record.Time.UTC().Truncate(0), ""
+		records = append(records, record)
+	}
+
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, AuditRecord{Time: records[0].Time, Verb: "delete", Resource: "virtualmachines", Namespace: "ns", Name: "vm", Result: "success"}, records[0])
+		assert.Equal(t, AuditRecord{Time: records[1].Time, Verb: "delete", Resource: "datavolumes", Namespace: "ns", Name: "dv", Result: "failure", Error: "forbidden"}, records[1])
+		assert.Equal(t, AuditRecord{Time: records[2].Time, Verb: "delete", Resource: "secrets", Namespace: "ns", Name: "secret", Result: "success"}, records[2])
+	}
+}
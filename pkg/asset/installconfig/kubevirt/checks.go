@@ -0,0 +1,269 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver"
+	nadv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubevirtapiv1 "kubevirt.io/client-go/api/v1"
+	cdiapiv1alpa1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	// minimumKubernetesVersion is the oldest infra cluster Kubernetes version supported.
+	minimumKubernetesVersion = semver.MustParse("1.18.0")
+	// minimumKubeVirtVersion is the oldest KubeVirt version supported on the infra cluster.
+	minimumKubeVirtVersion = semver.MustParse("0.34.0")
+)
+
+// requiredPermission is an action the installer performs in the infra cluster namespaces.
+type requiredPermission struct {
+	group    string
+	resource string
+	verbs    []string
+}
+
+var requiredPermissions = []requiredPermission{
+	{kubevirtapiv1.GroupVersion.Group, "virtualmachines", []string{"get", "list", "create", "delete"}},
+	{cdiapiv1alpa1.SchemeGroupVersion.Group, "datavolumes", []string{"get", "list", "create", "delete"}},
+	{"", "secrets", []string{"get", "list", "create", "delete"}},
+	{policyv1beta1.SchemeGroupVersion.Group, "poddisruptionbudgets", []string{"get", "list", "create", "delete"}},
+	{nadv1.SchemeGroupVersion.Group, "network-attachment-definitions", []string{"get"}},
+}
+
+// networkIsolationPermission is required in addition when the platform network isolation is enabled.
+var networkIsolationPermission = requiredPermission{nadv1.SchemeGroupVersion.Group, "multi-networkpolicies", []string{"get", "list", "create", "patch", "delete"}}
+
+// networkCreationPermission is required in addition when the platform has a network template, to
+// create the network-attachment-definition when it doesn't exist and to delete it on destroy.
+var networkCreationPermission = requiredPermission{nadv1.SchemeGroupVersion.Group, "network-attachment-definitions", []string{"list", "create", "delete"}}
+
+// networkProbePermissions are required in addition when the platform has a network probe or the
+// VIPs in use check, to run the probe pod and read its logs.
+var networkProbePermissions = []requiredPermission{
+	{"", "pods", []string{"get", "create", "delete"}},
+	{"", "pods/log", []string{"get"}},
+}
+
+// PlatformCheck is the result of a live check against the infra cluster.
+type PlatformCheck struct {
+	// Name is the name of the check.
+	Name string
+	// Skipped is set when the check could not run because an earlier check failed, or
+	// because the namespace-scoped infra cluster credentials are not allowed to run it.
+	Skipped bool
+	// Errors are the failures found by the check; empty when the check passed.
+	Errors field.ErrorList
+}
+
+// RunPlatformChecks executes only the live checks against the infra cluster (reachability, RBAC,
+// namespace, storage class, network-attachment-definition and versions) and returns the result of each.
+func RunPlatformChecks(ic *types.InstallConfig, clientBuilderFunc ClientBuilderFuncType) ([]PlatformCheck, error) {
+	fldPath := field.NewPath("platform", "kubevirt")
+	if ic.Platform.Kubevirt == nil {
+		return nil, fmt.Errorf("platform checks require a kubevirt platform configuration")
+	}
+	platform := ic.Platform.Kubevirt
+	ctx := context.Background()
+
+	client, errs := validateInfraClusterReachable(ctx, clientBuilderFunc, fldPath)
+	if client != nil {
+		if _, err := client.GetServerVersion(); err != nil {
+			detailedErr := fmt.Errorf("failed to access to InfraCluster with error: %v", err)
+			errs = append(errs, field.Invalid(fldPath.Child("InfraClusterReachable"), "InfraCluster", detailedErr.Error()))
+		}
+	}
+	checks := []PlatformCheck{{Name: "InfraClusterReachable", Errors: errs}}
+
+	namespaces := []string{platform.Namespace}
+
+	permissions := requiredPermissions
+	if platform.NetworkIsolation {
+		permissions = append(permissions[:len(permissions):len(permissions)], networkIsolationPermission)
+	}
+	if platform.CreateNetwork != nil {
+		permissions = append(permissions[:len(permissions):len(permissions)], networkCreationPermission)
+	}
+	if platform.NetworkProbe != nil || platform.VIPsInUseCheck {
+		permissions = append(permissions[:len(permissions):len(permissions)], networkProbePermissions...)
+	}
+
+	namespacesAllowed := func() bool { return clusterScopedAllowed(ctx, platform, client, "", "namespaces", "get") }
+	storageClassesAllowed := func() bool {
+		return clusterScopedAllowed(ctx, platform, client, "storage.k8s.io", "storageclasses", "get")
+	}
+
+	for _, check := range []struct {
+		name string
+		// allowed returns whether the infra cluster credentials are allowed to run the check, nil when always allowed.
+		allowed func() bool
+		run     func() field.ErrorList
+	}{
+		{"Namespace", namespacesAllowed, func() field.ErrorList { return checkNamespaces(ctx, namespaces, client, fldPath) }},
+		{"RBAC", nil, func() field.ErrorList { return checkPermissions(ctx, namespaces, permissions, client, fldPath) }},
+		{"StorageClass", storageClassesAllowed, func() field.ErrorList {
+			return validateStorageClassExistsInInfraCluster(ctx, platform.StorageClass, client, fldPath)
+		}},
+		{"NetworkAttachmentDefinition", nil, func() field.ErrorList { return checkNetworkAttachmentDefinition(ctx, platform, client, fldPath) }},
+		{"Versions", nil, func() field.ErrorList {
+			kubeVirtVersion := clusterScopedAllowed(ctx, platform, client, kubevirtapiv1.GroupVersion.Group, "kubevirts", "list")
+			return checkVersions(ctx, client, kubeVirtVersion, fldPath)
+		}},
+	} {
+		if len(errs) > 0 || (check.allowed != nil && !check.allowed()) {
+			checks = append(checks, PlatformCheck{Name: check.name, Skipped: true})
+			continue
+		}
+		checks = append(checks, PlatformCheck{Name: check.name, Errors: check.run()})
+	}
+
+	if platform.NetworkIsolation {
+		isolation := PlatformCheck{Name: "NetworkIsolation", Skipped: len(errs) > 0}
+		if !isolation.Skipped {
+			isolation.Errors = checkMultiNetworkPolicies(platform, client, fldPath)
+		}
+		checks = append(checks, isolation)
+	}
+
+	// The probe attaches a pod to the network, so it only runs once all the other checks passed
+	if platform.NetworkProbe != nil {
+		probe := PlatformCheck{Name: "NetworkProbe"}
+		for _, check := range checks {
+			probe.Skipped = probe.Skipped || check.Skipped || len(check.Errors) > 0
+		}
+		if !probe.Skipped {
+			probe.Errors = checkNetworkProbe(ctx, platform, client, fldPath)
+		}
+		checks = append(checks, probe)
+	}
+
+	return checks, nil
+}
+
+func checkNetworkProbe(ctx context.Context, platform *kubevirt.Platform, client Client, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	checks, err := ProbeNetwork(ctx, client, platform)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkProbe"), platform.NetworkName, err.Error()))
+		return allErrs
+	}
+	for _, failed := range FailedNetworkProbeChecks(checks) {
+		detailedErr := fmt.Errorf("the %s check of the network probe failed on network %s", failed, platform.NetworkName)
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkProbe", failed), platform.NetworkName, detailedErr.Error()))
+	}
+
+	return allErrs
+}
+
+func checkNamespaces(ctx context.Context, namespaces []string, client Client, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, namespace := range namespaces {
+		if _, err := client.GetNamespace(ctx, namespace); err != nil {
+			detailedErr := fmt.Errorf("failed to get namespace %s from InfraCluster, with error: %v", namespace, err)
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), namespace, detailedErr.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func checkPermissions(ctx context.Context, namespaces []string, permissions []requiredPermission, client Client, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, namespace := range namespaces {
+		for _, permission := range permissions {
+			for _, verb := range permission.verbs {
+				allowed, err := client.CheckAccess(ctx, namespace, permission.group, permission.resource, verb)
+				if err != nil {
+					detailedErr := fmt.Errorf("failed to check the permission to %s %s in namespace %s, with error: %v", verb, permission.resource, namespace, err)
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), namespace, detailedErr.Error()))
+					continue
+				}
+				if !allowed {
+					detailedErr := fmt.Errorf("not allowed to %s %s in namespace %s", verb, permission.resource, namespace)
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("namespace"), detailedErr.Error()))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func checkNetworkAttachmentDefinition(ctx context.Context, platform *kubevirt.Platform, client Client, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if _, err := platformNetworkAttachmentDefinition(ctx, platform, client); err != nil {
+		detailedErr := fmt.Errorf("failed to get network-attachment-definition %s from InfraCluster, with error: %v", platform.NetworkName, err)
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("NetworkAttachmentDefinitionExistsInInfraCluster"), platform.NetworkName, detailedErr.Error()))
+		return allErrs
+	}
+	allErrs = append(allErrs, validateIPsInNetworkAttachmentDefinitionSubnet(ctx, platform, client, fldPath)...)
+
+	return allErrs
+}
+
+// checkMultiNetworkPolicies checks that the infra cluster serves the MultiNetworkPolicies isolating
+// the network of the cluster, which the VMs are only attached to.
+func checkMultiNetworkPolicies(platform *kubevirt.Platform, client Client, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	_, err := client.ListMultiNetworkPolicyNames(platform.Namespace, nil)
+	if apierrors.IsNotFound(err) {
+		detailedErr := fmt.Errorf("the InfraCluster does not serve MultiNetworkPolicies, which are required to isolate network %s", platform.NetworkName)
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkIsolation"), platform.NetworkIsolation, detailedErr.Error()))
+	} else if err != nil {
+		detailedErr := fmt.Errorf("failed to list the MultiNetworkPolicies of namespace %s from InfraCluster, with error: %v", platform.Namespace, err)
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkIsolation"), platform.NetworkIsolation, detailedErr.Error()))
+	}
+
+	return allErrs
+}
+
+// checkVersions checks the Kubernetes version of the infra cluster, and its KubeVirt version
+// when kubeVirtVersion is set.
+func checkVersions(ctx context.Context, client Client, kubeVirtVersion bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, component := range []struct {
+		name    string
+		get     func() (string, error)
+		minimum semver.Version
+	}{
+		{"Kubernetes", client.GetServerVersion, minimumKubernetesVersion},
+		{"KubeVirt", func() (string, error) { return client.GetKubeVirtVersion(ctx) }, minimumKubeVirtVersion},
+	} {
+		if component.name == "KubeVirt" && !kubeVirtVersion {
+			continue
+		}
+		version, err := component.get()
+		if err != nil {
+			detailedErr := fmt.Errorf("failed to get the %s version of InfraCluster, with error: %v", component.name, err)
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("InfraClusterVersion"), component.name, detailedErr.Error()))
+			continue
+		}
+		parsed, err := semver.ParseTolerant(version)
+		if err != nil {
+			detailedErr := fmt.Errorf("failed to parse the %s version %s of InfraCluster, with error: %v", component.name, version, err)
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("InfraClusterVersion"), version, detailedErr.Error()))
+			continue
+		}
+		// Ignore pre-release and build metadata, e.g. v1.19.0+d59ce34
+		parsed.Pre, parsed.Build = nil, nil
+		if parsed.LT(component.minimum) {
+			detailedErr := fmt.Errorf("%s version %s is older than the minimum supported version %s", component.name, version, component.minimum)
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("InfraClusterVersion"), version, detailedErr.Error()))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,175 @@
+package kubevirt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestRunPlatformChecks(t *testing.T) {
+	validClient := func(kubevirtClient *mock.MockClient) {
+		kubevirtClient.EXPECT().GetServerVersion().Return("v1.19.0+d59ce34", nil).AnyTimes()
+		kubevirtClient.EXPECT().GetKubeVirtVersion(gomock.Any()).Return("v0.34.2", nil).AnyTimes()
+		kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+		kubevirtClient.EXPECT().CheckAccess(gomock.Any(), validNamespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+		kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+		kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(validNADConfig), nil).AnyTimes()
+	}
+
+	cases := []struct {
+		name             string
+		clientBuilderErr error
+		networkIsolation bool
+		namespaceScoped  bool
+		createNetwork    *kubevirt.NetworkTemplate
+		expectClient     func(kubevirtClient *mock.MockClient)
+		expectedFailed   map[string]string
+		expectedSkipped  bool
+		skippedChecks    []string
+		// checks is the number of checks run, 6 when unset
+		checks int
+	}{
+		{
+			name:         "valid",
+			expectClient: validClient,
+		},
+		{
+			name:             "unreachable",
+			clientBuilderErr: errors.New("test"),
+			expectedFailed:   map[string]string{"InfraClusterReachable": "failed to create InfraCluster client with error: test"},
+			expectedSkipped:  true,
+		},
+		{
+			name: "forbidden",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), validNamespace, "kubevirt.io", "virtualmachines", "create").Return(false, nil)
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"RBAC": "not allowed to create virtualmachines in namespace valid-namespace"},
+		},
+		{
+			name:             "network isolation forbidden",
+			networkIsolation: true,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), validNamespace, "k8s.cni.cncf.io", "multi-networkpolicies", "create").Return(false, nil)
+				kubevirtClient.EXPECT().ListMultiNetworkPolicyNames(validNamespace, nil).Return(nil, nil)
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"RBAC": "not allowed to create multi-networkpolicies in namespace valid-namespace"},
+			checks:         7,
+		},
+		{
+			name:             "network isolation without multi-network policies",
+			networkIsolation: true,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListMultiNetworkPolicyNames(validNamespace, nil).Return(nil, apierrors.NewNotFound(schema.GroupResource{Group: "k8s.cni.cncf.io", Resource: "multi-networkpolicies"}, ""))
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"NetworkIsolation": "the InfraCluster does not serve MultiNetworkPolicies, which are required to isolate network valid-network-name"},
+			checks:         7,
+		},
+		{
+			name: "old kubevirt",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().GetKubeVirtVersion(gomock.Any()).Return("v0.30.0", nil)
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"Versions": "KubeVirt version v0.30.0 is older than the minimum supported version 0.34.0"},
+		},
+		{
+			name:            "namespace-scoped credentials",
+			namespaceScoped: true,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "", "namespaces", "get").Return(false, nil)
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "storage.k8s.io", "storageclasses", "get").Return(false, nil)
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "kubevirt.io", "kubevirts", "list").Return(false, nil)
+				kubevirtClient.EXPECT().GetServerVersion().Return("v1.19.0+d59ce34", nil).AnyTimes()
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), validNamespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(validNADConfig), nil).AnyTimes()
+			},
+			skippedChecks: []string{"Namespace", "StorageClass"},
+		},
+		{
+			name:            "namespace-scoped credentials allowed to read storage classes",
+			namespaceScoped: true,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "", "namespaces", "get").Return(false, nil)
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "storage.k8s.io", "storageclasses", "get").Return(true, nil)
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "kubevirt.io", "kubevirts", "list").Return(true, nil)
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, errors.New("test"))
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"StorageClass": "failed to get storageClass valid-storage-class from InfraCluster, with error: test"},
+			skippedChecks:  []string{"Namespace"},
+		},
+		{
+			name: "missing network-attachment-definition",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, errors.New("test"))
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"NetworkAttachmentDefinition": "failed to get network-attachment-definition valid-network-name from InfraCluster, with error: test"},
+		},
+		{
+			name:          "missing network-attachment-definition created",
+			createNetwork: &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: validMachineCIDR},
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nadNotFound(validNetworkName)).AnyTimes()
+				validClient(kubevirtClient)
+			},
+		},
+		{
+			name:          "network creation forbidden",
+			createNetwork: &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1"},
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), validNamespace, "k8s.cni.cncf.io", "network-attachment-definitions", "create").Return(false, nil)
+				validClient(kubevirtClient)
+			},
+			expectedFailed: map[string]string{"RBAC": "not allowed to create network-attachment-definitions in namespace valid-namespace"},
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubevirtClient := mock.NewMockClient(mockCtrl)
+			if tc.expectClient != nil {
+				tc.expectClient(kubevirtClient)
+			}
+
+			ic := validInstallConfig()
+			ic.Platform.Kubevirt.NetworkIsolation = tc.networkIsolation
+			ic.Platform.Kubevirt.NamespaceScopedCredentials = tc.namespaceScoped
+			ic.Platform.Kubevirt.CreateNetwork = tc.createNetwork
+			checks, err := RunPlatformChecks(ic, func() (Client, error) { return kubevirtClient, tc.clientBuilderErr })
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tc.checks == 0 {
+				tc.checks = 6
+			}
+			assert.Len(t, checks, tc.checks)
+			for _, check := range checks {
+				if msg, ok := tc.expectedFailed[check.Name]; ok {
+					assert.Regexp(t, msg, check.Errors.ToAggregate())
+					continue
+				}
+				skipped := tc.expectedSkipped
+				for _, name := range tc.skippedChecks {
+					skipped = skipped || name == check.Name
+				}
+				assert.Equal(t, skipped, check.Skipped, check.Name)
+				assert.Empty(t, check.Errors, check.Name)
+			}
+		})
+	}
+}
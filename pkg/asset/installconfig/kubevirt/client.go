@@ -17,39 +17,115 @@ limitations under the License.
 package kubevirt
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/ghodss/yaml"
 	nadv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	storagev1alpha1 "k8s.io/api/storage/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"k8s.io/client-go/util/retry"
 	kubevirtapiv1 "kubevirt.io/client-go/api/v1"
 	cdiapiv1alpa1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
-)
 
-var (
-	kubeConfigEnvName         = "KUBECONFIG"
-	kubeConfigDefaultFilename = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
 )
 
-func LoadKubeConfigContent() ([]byte, error) {
-	kubeConfigFilename := os.Getenv(kubeConfigEnvName)
-	// Fallback to default kubeconfig file location if no env variable set
-	if kubeConfigFilename == "" {
-		kubeConfigFilename = kubeConfigDefaultFilename
+// kubeConfigPath returns the kubeconfig file used to access the infra cluster: the explicit
+// path when set. Otherwise KUBECONFIG may hold a list of files separated by the OS path list
+// separator (";" on Windows), of which the first existing one is used. Unlike kubectl, the
+// files are not merged, the infra cluster is accessed with a single kubeconfig file which is
+// recorded in the metadata of the cluster. Without KUBECONFIG, the kubeconfig in the user's
+// home directory (%USERPROFILE% on Windows) is used.
+func kubeConfigPath(explicitPath string) string {
+	if explicitPath != "" {
+		return explicitPath
+	}
+	paths := filepath.SplitList(os.Getenv(clientcmd.RecommendedConfigPathEnvVar))
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	if len(paths) > 0 {
+		// Report the missing file the user asked for rather than the default one
+		return paths[0]
 	}
+	return clientcmd.RecommendedHomeFile
+}
 
-	return ioutil.ReadFile(kubeConfigFilename)
+// InfraKubeconfigPath returns the absolute path of the kubeconfig used to access the infra
+// cluster, given the optional explicit path of the platform.
+func InfraKubeconfigPath(explicitPath string) (string, error) {
+	return filepath.Abs(kubeConfigPath(explicitPath))
+}
+
+// LoadKubeConfigContent returns the content of the kubeconfig used to access the infra cluster.
+// When kubeconfigContext is set, the returned kubeconfig only holds that context, as its
+// current-context, with the credentials inlined.
+func LoadKubeConfigContent(kubeconfigPath string, kubeconfigContext string) ([]byte, error) {
+	path := kubeConfigPath(kubeconfigPath)
+	if kubeconfigContext == "" {
+		return ioutil.ReadFile(path)
+	}
+
+	config, err := loadKubeConfigContext(path, kubeconfigContext)
+	if err != nil {
+		return nil, err
+	}
+	if err := clientcmdapi.MinifyConfig(config); err != nil {
+		return nil, err
+	}
+	if err := clientcmdapi.FlattenConfig(config); err != nil {
+		return nil, err
+	}
+	v1Config := &clientcmdapiv1.Config{}
+	if err := clientcmdapiv1.Convert_api_Config_To_v1_Config(config, v1Config, nil); err != nil {
+		return nil, err
+	}
+	v1Config.APIVersion, v1Config.Kind = "v1", "Config"
+	return yaml.Marshal(v1Config)
+}
+
+// loadKubeConfigContext loads the kubeconfig at path with kubeconfigContext as its current-context.
+func loadKubeConfigContext(path string, kubeconfigContext string) (*clientcmdapi.Config, error) {
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if kubeconfigContext != "" {
+		if _, ok := config.Contexts[kubeconfigContext]; !ok {
+			return nil, fmt.Errorf("context %s not found in kubeconfig %s", kubeconfigContext, path)
+		}
+		config.CurrentContext = kubeconfigContext
+	}
+	return config, nil
 }
 
 //go:generate mockgen -source=./client.go -destination=./mock/client_generated.go -package=mock
@@ -62,35 +138,104 @@ type Client interface {
 	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
 	ListNamespace(ctx context.Context) (*corev1.NamespaceList, error)
 	GetStorageClass(ctx context.Context, name string) (*storagev1.StorageClass, error)
+	GetPriorityClass(ctx context.Context, name string) (*schedulingv1.PriorityClass, error)
+	ListPriorityClasses(ctx context.Context) ([]schedulingv1.PriorityClass, error)
+	ListStorageClasses(ctx context.Context) ([]storagev1.StorageClass, error)
 	GetNetworkAttachmentDefinition(ctx context.Context, name string, namespace string) (*unstructured.Unstructured, error)
+	ListNetworkAttachmentDefinitions(ctx context.Context, namespace string) ([]unstructured.Unstructured, error)
+	CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error
+	DeleteNetworkAttachmentDefinition(namespace string, name string, wait bool) error
+	ListNetworkAttachmentDefinitionNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	ListResourceQuotas(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error)
+	ListNodes(ctx context.Context) ([]corev1.Node, error)
+	ListCSIStorageCapacities(ctx context.Context) ([]storagev1alpha1.CSIStorageCapacity, error)
 	DeleteVirtualMachine(namespace string, name string, wait bool) error
+	StopVirtualMachine(namespace string, name string, gracePeriod time.Duration) error
+	StartVirtualMachine(namespace string, name string) error
+	SetVirtualMachineRunStrategy(namespace string, name string, runStrategy string) error
+	AnnotateVirtualMachine(ctx context.Context, namespace string, name string, annotations map[string]string) error
+	WaitForVirtualMachineStopped(namespace string, name string, timeout time.Duration) error
 	ListVirtualMachineNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	ListAllVirtualMachineNames(ctx context.Context, namespace string) ([]string, error)
+	ListVirtualMachines(ctx context.Context, namespace string) ([]unstructured.Unstructured, error)
+	ListVirtualMachineInstances(ctx context.Context, namespace string) ([]unstructured.Unstructured, error)
+	SerialConsole(ctx context.Context, namespace string, name string) (io.ReadWriteCloser, error)
+	ListDataVolumes(ctx context.Context, namespace string) ([]unstructured.Unstructured, error)
+	WatchDataVolumes(ctx context.Context, namespace string) (watch.Interface, error)
+	CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error
 	DeleteDataVolume(namespace string, name string, wait bool) error
 	ListDataVolumeNames(namespace string, requiredLabels map[string]string) ([]string, error)
 	DeleteSecret(namespace string, name string, wait bool) error
 	ListSecretNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	ListResourceLabels(ctx context.Context, namespace string, resource string) (map[string]map[string]string, error)
+	AddResourceLabels(ctx context.Context, namespace string, resource string, name string, labels map[string]string) error
+	ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error
+	GetConfigMap(ctx context.Context, namespace string, name string) (*corev1.ConfigMap, error)
+	CreateOrUpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error
+	AnnotateConfigMap(ctx context.Context, namespace string, name string, annotations map[string]string) error
+	DeleteConfigMap(namespace string, name string, wait bool) error
+	ListConfigMapNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	CreateEvent(ctx context.Context, event *corev1.Event) error
+	CreatePod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error)
+	GetPod(ctx context.Context, namespace string, name string) (*corev1.Pod, error)
+	GetPodLogs(ctx context.Context, namespace string, name string) (string, error)
+	DeletePod(namespace string, name string, wait bool) error
+	DeleteMultiNetworkPolicy(namespace string, name string, wait bool) error
+	ListMultiNetworkPolicyNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	DeletePodDisruptionBudget(namespace string, name string, wait bool) error
+	ListPodDisruptionBudgetNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	DeleteDeployment(namespace string, name string, wait bool) error
+	ListDeploymentNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	DeleteService(namespace string, name string, wait bool) error
+	ListServiceNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	DeleteEndpoints(namespace string, name string, wait bool) error
+	ListEndpointsNames(namespace string, requiredLabels map[string]string) ([]string, error)
+	CheckAccess(ctx context.Context, namespace string, group string, resource string, verb string) (bool, error)
+	CreateServiceAccountToken(ctx context.Context, namespace string, name string, expiration time.Duration) (string, error)
+	GetServerVersion() (string, error)
+	GetKubeVirtVersion(ctx context.Context) (string, error)
 }
 
 type client struct {
+	restConfig       *rest.Config
 	kubernetesClient *kubernetes.Clientset
 	dynamicClient    dynamic.Interface
 }
 
 // New creates our client wrapper object for the actual kubeVirt and kubernetes clients we use.
 func NewClient() (Client, error) {
+	return NewClientFor("", "", "")
+}
+
+// ClientBuilder returns a ClientBuilderFuncType creating clients for the kubeconfig, context
+// and additional CA bundle of the infra cluster; empty values fall back to KUBECONFIG and its
+// current-context.
+func ClientBuilder(kubeconfigPath string, kubeconfigContext string, caBundle string) ClientBuilderFuncType {
+	return func() (Client, error) {
+		return NewClientFor(kubeconfigPath, kubeconfigContext, caBundle)
+	}
+}
+
+// NewClientFor creates the client wrapper for the kubeconfig and context of the infra cluster;
+// empty values fall back to KUBECONFIG and its current-context. The optional caBundle, either
+// an inline PEM or the path of a file holding it, is trusted besides the certificate authority
+// of the kubeconfig.
+func NewClientFor(kubeconfigPath string, kubeconfigContext string, caBundle string) (Client, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	// if you want to change the loading rules (which files in which order), you can do so here
+	loadingRules.ExplicitPath = kubeconfigPath
 
-	configOverrides := &clientcmd.ConfigOverrides{}
-	// if you want to change override values or bind them to flags, there are methods to help you
+	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: kubeconfigContext}
 
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 	restClientConfig, err := kubeConfig.ClientConfig()
 	if err != nil {
 		return nil, err
 	}
+	if err := addCABundle(restClientConfig, caBundle); err != nil {
+		return nil, err
+	}
 
-	result := &client{}
+	result := &client{restConfig: restClientConfig}
 
 	if result.kubernetesClient, err = kubernetes.NewForConfig(restClientConfig); err != nil {
 		return nil, err
@@ -98,9 +243,63 @@ func NewClient() (Client, error) {
 	if result.dynamicClient, err = dynamic.NewForConfig(restClientConfig); err != nil {
 		return nil, err
 	}
+
+	if ReadOnly() {
+		return newReadOnlyClient(result), nil
+	}
+	auditLogLock.Lock()
+	path := auditLogPath
+	auditLogLock.Unlock()
+	if path != "" {
+		return newAuditingClient(result, path), nil
+	}
 	return result, nil
 }
 
+// NewClientForMetadata creates the client wrapper for the infra cluster of the cluster described
+// by the metadata.
+func NewClientForMetadata(metadata *kubevirttypes.Metadata) (Client, error) {
+	return NewClientFor(metadata.InfraKubeconfigPath, metadata.InfraContext, metadata.InfraCABundle)
+}
+
+// NewClientForInfraCluster creates the client wrapper for the infra cluster.
+func NewClientForInfraCluster(infraCluster kubevirttypes.InfraCluster) (Client, error) {
+	return NewClientFor(infraCluster.KubeconfigPath, infraCluster.Context, infraCluster.CABundle)
+}
+
+// addCABundle merges the CA bundle, either an inline PEM or the path of a file holding it, into
+// the certificate authority of the rest config. When the kubeconfig has no certificate authority,
+// the bundle replaces the system roots.
+func addCABundle(config *rest.Config, caBundle string) error {
+	if caBundle == "" {
+		return nil
+	}
+	bundle := []byte(caBundle)
+	if !kubevirttypes.IsInlinePEM(caBundle) {
+		var err error
+		if bundle, err = ioutil.ReadFile(caBundle); err != nil {
+			return fmt.Errorf("failed to read the infra cluster CA bundle: %v", err)
+		}
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+		return fmt.Errorf("the infra cluster CA bundle holds no PEM-encoded certificates")
+	}
+
+	caData := config.TLSClientConfig.CAData
+	if len(caData) == 0 && config.TLSClientConfig.CAFile != "" {
+		var err error
+		if caData, err = ioutil.ReadFile(config.TLSClientConfig.CAFile); err != nil {
+			return fmt.Errorf("failed to read the certificate authority of the kubeconfig: %v", err)
+		}
+	}
+	if len(caData) > 0 && !bytes.HasSuffix(caData, []byte("\n")) {
+		caData = append(caData, '\n')
+	}
+	config.TLSClientConfig.CAData = append(caData, bundle...)
+	config.TLSClientConfig.CAFile = ""
+	return nil
+}
+
 func (c *client) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
 	return c.kubernetesClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 }
@@ -113,11 +312,149 @@ func (c *client) GetStorageClass(ctx context.Context, name string) (*storagev1.S
 	return c.kubernetesClient.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
 }
 
+func (c *client) GetPriorityClass(ctx context.Context, name string) (*schedulingv1.PriorityClass, error) {
+	return c.kubernetesClient.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListPriorityClasses returns all the priority classes
+func (c *client) ListPriorityClasses(ctx context.Context) ([]schedulingv1.PriorityClass, error) {
+	list, err := c.kubernetesClient.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListStorageClasses returns all the storage classes
+func (c *client) ListStorageClasses(ctx context.Context) ([]storagev1.StorageClass, error) {
+	list, err := c.kubernetesClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
 func (c *client) GetNetworkAttachmentDefinition(ctx context.Context, name string, namespace string) (*unstructured.Unstructured, error) {
 	nadRes := schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: nadv1.SchemeGroupVersion.Version, Resource: "network-attachment-definitions"}
 	return c.getResource(namespace, name, nadRes)
 }
 
+// ListNetworkAttachmentDefinitions returns all the network-attachment-definitions in the namespace
+func (c *client) ListNetworkAttachmentDefinitions(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	nadRes := schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: nadv1.SchemeGroupVersion.Version, Resource: "network-attachment-definitions"}
+	list, err := c.dynamicClient.Resource(nadRes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// CreateNetworkAttachmentDefinition creates the network-attachment-definition in its namespace,
+// or converges the existing one to it
+func (c *client) CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error {
+	nadRes := schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: nadv1.SchemeGroupVersion.Version, Resource: "network-attachment-definitions"}
+	return c.createOrConverge(ctx, nadRes, nad)
+}
+
+func (c *client) DeleteNetworkAttachmentDefinition(namespace string, name string, wait bool) error {
+	nadRes := schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: nadv1.SchemeGroupVersion.Version, Resource: "network-attachment-definitions"}
+	return c.deleteResource(namespace, name, nadRes, wait)
+}
+
+func (c *client) ListNetworkAttachmentDefinitionNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	nadRes := schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: nadv1.SchemeGroupVersion.Version, Resource: "network-attachment-definitions"}
+	return c.listResource(namespace, requiredLabels, nadRes)
+}
+
+// ListResourceQuotas returns all the resource quotas in the namespace
+func (c *client) ListResourceQuotas(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error) {
+	list, err := c.kubernetesClient.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListNodes returns all the nodes of the infra cluster
+func (c *client) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	list, err := c.kubernetesClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListCSIStorageCapacities returns the capacities published by the CSI drivers in all the namespaces
+func (c *client) ListCSIStorageCapacities(ctx context.Context) ([]storagev1alpha1.CSIStorageCapacity, error) {
+	list, err := c.kubernetesClient.StorageV1alpha1().CSIStorageCapacities(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// CheckAccess returns whether the current user is allowed to perform the verb on the resource in the namespace
+func (c *client) CheckAccess(ctx context.Context, namespace string, group string, resource string, verb string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Group:     group,
+				Resource:  resource,
+				Verb:      verb,
+			},
+		},
+	}
+	result, err := c.kubernetesClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// CreateServiceAccountToken requests a token of the service account, bound to it and expiring after expiration
+func (c *client) CreateServiceAccountToken(ctx context.Context, namespace string, name string, expiration time.Duration) (string, error) {
+	expirationSeconds := int64(expiration.Seconds())
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	result, err := c.kubernetesClient.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, request, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return result.Status.Token, nil
+}
+
+// GetServerVersion returns the Kubernetes version of the infra cluster
+func (c *client) GetServerVersion() (string, error) {
+	info, err := c.kubernetesClient.Discovery().ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return info.GitVersion, nil
+}
+
+// GetKubeVirtVersion returns the version of KubeVirt deployed in the infra cluster
+func (c *client) GetKubeVirtVersion(ctx context.Context) (string, error) {
+	kvRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "kubevirts"}
+	list, err := c.dynamicClient.Resource(kvRes).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, kv := range list.Items {
+		version, found, err := unstructured.NestedString(kv.Object, "status", "observedKubeVirtVersion")
+		if err != nil {
+			return "", err
+		}
+		if found && version != "" {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("no deployed KubeVirt found")
+}
+
 // The functions bellow are used for the destroy command
 // Use Dynamic cluster for those actions (list and delete)
 
@@ -126,11 +463,141 @@ func (c *client) DeleteVirtualMachine(namespace string, name string, wait bool)
 	return c.deleteResource(namespace, name, vmRes, wait)
 }
 
+// StopVirtualMachine stops the VM through the stop subresource of the virt API, which shuts
+// its guest down, and waits up to gracePeriod for its VMI to be deleted.
+func (c *client) StopVirtualMachine(namespace string, name string, gracePeriod time.Duration) error {
+	subresourceVersion := kubevirtapiv1.SubresourceGroupVersions[0]
+	if err := c.kubernetesClient.CoreV1().RESTClient().Put().
+		AbsPath("/apis", subresourceVersion.Group, subresourceVersion.Version, "namespaces", namespace, "virtualmachines", name, "stop").
+		Do(context.Background()).Error(); err != nil {
+		return err
+	}
+	return c.WaitForVirtualMachineStopped(namespace, name, gracePeriod)
+}
+
+// StartVirtualMachine starts the VM through the start subresource of the virt API, as the VMs
+// with the Manual run strategy are only started on request.
+func (c *client) StartVirtualMachine(namespace string, name string) error {
+	subresourceVersion := kubevirtapiv1.SubresourceGroupVersions[0]
+	return c.kubernetesClient.CoreV1().RESTClient().Put().
+		AbsPath("/apis", subresourceVersion.Group, subresourceVersion.Version, "namespaces", namespace, "virtualmachines", name, "start").
+		Do(context.Background()).Error()
+}
+
+// SetVirtualMachineRunStrategy sets the run strategy of the VM, replacing its running field
+// which is mutually exclusive with it; KubeVirt then starts or stops the VM accordingly.
+func (c *client) SetVirtualMachineRunStrategy(namespace string, name string, runStrategy string) error {
+	vmRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"running":     nil,
+			"runStrategy": runStrategy,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClient.Resource(vmRes).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// AnnotateVirtualMachine adds the annotations to the VM, overwriting the values of the existing
+// ones and keeping the other ones.
+func (c *client) AnnotateVirtualMachine(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	vmRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClient.Resource(vmRes).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// WaitForVirtualMachineStopped waits up to timeout for the VMI of the VM to be deleted.
+func (c *client) WaitForVirtualMachineStopped(namespace string, name string, timeout time.Duration) error {
+	vmiRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachineinstances"}
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := c.getResource(namespace, name, vmiRes)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the VM %s did not stop within %s", name, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
 func (c *client) ListVirtualMachineNames(namespace string, requiredLabels map[string]string) ([]string, error) {
 	vmRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"}
 	return c.listResource(namespace, requiredLabels, vmRes)
 }
 
+// ListAllVirtualMachineNames returns the names of all the VMs in the namespace, regardless of their labels
+func (c *client) ListAllVirtualMachineNames(ctx context.Context, namespace string) ([]string, error) {
+	var result []string
+	vmRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"}
+	list, err := c.dynamicClient.Resource(vmRes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range list.Items {
+		result = append(result, d.GetName())
+	}
+	return result, nil
+}
+
+// ListVirtualMachines returns all the VMs in the namespace
+func (c *client) ListVirtualMachines(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	vmRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"}
+	list, err := c.dynamicClient.Resource(vmRes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListVirtualMachineInstances returns all the VMIs in the namespace, or in all the namespaces when it is empty
+func (c *client) ListVirtualMachineInstances(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	vmiRes := schema.GroupVersionResource{Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachineinstances"}
+	list, err := c.dynamicClient.Resource(vmiRes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListDataVolumes returns all the DataVolumes in the namespace
+func (c *client) ListDataVolumes(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	dvRes := schema.GroupVersionResource{Group: cdiapiv1alpa1.SchemeGroupVersion.Group, Version: cdiapiv1alpa1.SchemeGroupVersion.Version, Resource: "datavolumes"}
+	list, err := c.dynamicClient.Resource(dvRes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// WatchDataVolumes watches the DataVolumes in the namespace, starting with an ADDED event for
+// each of the existing ones
+func (c *client) WatchDataVolumes(ctx context.Context, namespace string) (watch.Interface, error) {
+	dvRes := schema.GroupVersionResource{Group: cdiapiv1alpa1.SchemeGroupVersion.Group, Version: cdiapiv1alpa1.SchemeGroupVersion.Version, Resource: "datavolumes"}
+	return c.dynamicClient.Resource(dvRes).Namespace(namespace).Watch(ctx, metav1.ListOptions{})
+}
+
+// CreateDataVolume creates the DataVolume in its namespace, or converges the existing one to it
+func (c *client) CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error {
+	dvRes := schema.GroupVersionResource{Group: cdiapiv1alpa1.SchemeGroupVersion.Group, Version: cdiapiv1alpa1.SchemeGroupVersion.Version, Resource: "datavolumes"}
+	return c.createOrConverge(ctx, dvRes, dv)
+}
+
 func (c *client) DeleteDataVolume(namespace string, name string, wait bool) error {
 	dvRes := schema.GroupVersionResource{Group: cdiapiv1alpa1.SchemeGroupVersion.Group, Version: cdiapiv1alpa1.SchemeGroupVersion.Version, Resource: "datavolumes"}
 	return c.deleteResource(namespace, name, dvRes, wait)
@@ -151,6 +618,204 @@ func (c *client) ListSecretNames(namespace string, requiredLabels map[string]str
 	return c.listResource(namespace, requiredLabels, secretRes)
 }
 
+func (c *client) GetConfigMap(ctx context.Context, namespace string, name string) (*corev1.ConfigMap, error) {
+	return c.kubernetesClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// CreateOrUpdateConfigMap creates the ConfigMap, or replaces its labels, annotations and data if it already exists,
+// retrying on conflicts
+func (c *client) CreateOrUpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	configMaps := c.kubernetesClient.CoreV1().ConfigMaps(configMap.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := configMaps.Get(ctx, configMap.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{FieldManager: FieldManager})
+			if apierrors.IsAlreadyExists(err) {
+				// Created in the meantime, updated on the next try
+				return apierrors.NewConflict(corev1.Resource("configmaps"), configMap.Name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		existing.Labels = configMap.Labels
+		existing.Annotations = configMap.Annotations
+		existing.Data = configMap.Data
+		_, err = configMaps.Update(ctx, existing, metav1.UpdateOptions{FieldManager: FieldManager})
+		return err
+	})
+}
+
+// AnnotateConfigMap adds the annotations to the ConfigMap, overwriting the values of the existing
+// ones and keeping the other ones.
+func (c *client) AnnotateConfigMap(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubernetesClient.CoreV1().ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (c *client) DeleteConfigMap(namespace string, name string, wait bool) error {
+	configMapRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "configmaps"}
+	return c.deleteResource(namespace, name, configMapRes, wait)
+}
+
+func (c *client) ListConfigMapNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	configMapRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "configmaps"}
+	return c.listResource(namespace, requiredLabels, configMapRes)
+}
+
+func (c *client) CreateEvent(ctx context.Context, event *corev1.Event) error {
+	_, err := c.kubernetesClient.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+func (c *client) CreatePod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	return c.kubernetesClient.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+}
+
+func (c *client) GetPod(ctx context.Context, namespace string, name string) (*corev1.Pod, error) {
+	return c.kubernetesClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetPodLogs returns the logs of the single container of the pod
+func (c *client) GetPodLogs(ctx context.Context, namespace string, name string) (string, error) {
+	logs, err := c.kubernetesClient.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	return string(logs), err
+}
+
+func (c *client) DeletePod(namespace string, name string, wait bool) error {
+	podRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "pods"}
+	return c.deleteResource(namespace, name, podRes, wait)
+}
+
+func (c *client) DeleteMultiNetworkPolicy(namespace string, name string, wait bool) error {
+	return c.deleteResource(namespace, name, multiNetworkPolicyRes, wait)
+}
+
+func (c *client) ListMultiNetworkPolicyNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return c.listResource(namespace, requiredLabels, multiNetworkPolicyRes)
+}
+
+func (c *client) DeletePodDisruptionBudget(namespace string, name string, wait bool) error {
+	podDisruptionBudgetRes := schema.GroupVersionResource{Group: policyv1beta1.SchemeGroupVersion.Group, Version: policyv1beta1.SchemeGroupVersion.Version, Resource: "poddisruptionbudgets"}
+	return c.deleteResource(namespace, name, podDisruptionBudgetRes, wait)
+}
+
+func (c *client) ListPodDisruptionBudgetNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	podDisruptionBudgetRes := schema.GroupVersionResource{Group: policyv1beta1.SchemeGroupVersion.Group, Version: policyv1beta1.SchemeGroupVersion.Version, Resource: "poddisruptionbudgets"}
+	return c.listResource(namespace, requiredLabels, podDisruptionBudgetRes)
+}
+
+func (c *client) DeleteDeployment(namespace string, name string, wait bool) error {
+	deploymentRes := schema.GroupVersionResource{Group: appsv1.SchemeGroupVersion.Group, Version: appsv1.SchemeGroupVersion.Version, Resource: "deployments"}
+	return c.deleteResource(namespace, name, deploymentRes, wait)
+}
+
+func (c *client) ListDeploymentNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	deploymentRes := schema.GroupVersionResource{Group: appsv1.SchemeGroupVersion.Group, Version: appsv1.SchemeGroupVersion.Version, Resource: "deployments"}
+	return c.listResource(namespace, requiredLabels, deploymentRes)
+}
+
+func (c *client) DeleteService(namespace string, name string, wait bool) error {
+	serviceRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "services"}
+	return c.deleteResource(namespace, name, serviceRes, wait)
+}
+
+func (c *client) ListServiceNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	serviceRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "services"}
+	return c.listResource(namespace, requiredLabels, serviceRes)
+}
+
+func (c *client) DeleteEndpoints(namespace string, name string, wait bool) error {
+	endpointsRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "endpoints"}
+	return c.deleteResource(namespace, name, endpointsRes, wait)
+}
+
+func (c *client) ListEndpointsNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	endpointsRes := schema.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "endpoints"}
+	return c.listResource(namespace, requiredLabels, endpointsRes)
+}
+
+// multiNetworkPolicyRes is the resource of the MultiNetworkPolicies, the network policies of the
+// secondary networks such as the network-attachment-definitions the VMs are attached to. It is
+// only served by the infra clusters enforcing them.
+var multiNetworkPolicyRes = schema.GroupVersionResource{Group: nadv1.SchemeGroupVersion.Group, Version: "v1beta1", Resource: "multi-networkpolicies"}
+
+// labeledResources are the resources of the cluster which ListResourceLabels,
+// AddResourceLabels and ApplyResource apply to, by resource name.
+var labeledResources = map[string]schema.GroupVersionResource{
+	"virtualmachines":       {Group: kubevirtapiv1.GroupVersion.Group, Version: kubevirtapiv1.GroupVersion.Version, Resource: "virtualmachines"},
+	"datavolumes":           {Group: cdiapiv1alpa1.SchemeGroupVersion.Group, Version: cdiapiv1alpa1.SchemeGroupVersion.Version, Resource: "datavolumes"},
+	"secrets":               {Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "secrets"},
+	"services":              {Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "services"},
+	"deployments":           {Group: appsv1.SchemeGroupVersion.Group, Version: appsv1.SchemeGroupVersion.Version, Resource: "deployments"},
+	"multi-networkpolicies": multiNetworkPolicyRes,
+}
+
+// labeledResource returns the resource of the cluster of the given name.
+func labeledResource(resource string) (schema.GroupVersionResource, error) {
+	gvr, ok := labeledResources[resource]
+	if !ok {
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported resource %s", resource)
+	}
+	return gvr, nil
+}
+
+// ListResourceLabels returns the labels of all the resources of the namespace, by name,
+// regardless of their labels. The resource is one of the labeledResources.
+func (c *client) ListResourceLabels(ctx context.Context, namespace string, resource string) (map[string]map[string]string, error) {
+	gvr, err := labeledResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]map[string]string, len(list.Items))
+	for _, item := range list.Items {
+		result[item.GetName()] = item.GetLabels()
+	}
+	return result, nil
+}
+
+// AddResourceLabels adds the labels to the resource, overwriting the values of the existing
+// ones and keeping the other ones.
+func (c *client) AddResourceLabels(ctx context.Context, namespace string, resource string, name string, labels map[string]string) error {
+	gvr, err := labeledResource(resource)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// ApplyResource creates the resource of the namespace of the object, or converges the existing
+// one to the object. The resource is one of the labeledResources.
+func (c *client) ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error {
+	gvr, err := labeledResource(resource)
+	if err != nil {
+		return err
+	}
+	return c.createOrConverge(ctx, gvr, obj)
+}
+
 func (c *client) deleteResource(namespace string, name string, resource schema.GroupVersionResource, wait bool) error {
 	if err := c.dynamicClient.Resource(resource).Namespace(namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
 		return err
@@ -0,0 +1,312 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestKubeConfigPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	existing := filepath.Join(dir, "existing")
+	if err := ioutil.WriteFile(existing, []byte("kubeconfig"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	cases := []struct {
+		name       string
+		kubeconfig []string
+		expected   string
+	}{
+		{
+			name:     "unset",
+			expected: clientcmd.RecommendedHomeFile,
+		},
+		{
+			name:       "single file",
+			kubeconfig: []string{existing},
+			expected:   existing,
+		},
+		{
+			name:       "list with missing first file",
+			kubeconfig: []string{missing, existing},
+			expected:   existing,
+		},
+		{
+			name:       "only missing files",
+			kubeconfig: []string{missing},
+			expected:   missing,
+		},
+	}
+
+	defer os.Setenv(clientcmd.RecommendedConfigPathEnvVar, os.Getenv(clientcmd.RecommendedConfigPathEnvVar))
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(clientcmd.RecommendedConfigPathEnvVar, strings.Join(tc.kubeconfig, string(filepath.ListSeparator)))
+			assert.Equal(t, tc.expected, kubeConfigPath(""))
+		})
+	}
+
+	os.Setenv(clientcmd.RecommendedConfigPathEnvVar, existing)
+	content, err := LoadKubeConfigContent("", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "kubeconfig", string(content))
+	}
+}
+
+const multiContextKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: infra-a
+  cluster:
+    server: https://a.example.com:6443
+- name: infra-b
+  cluster:
+    server: https://b.example.com:6443
+contexts:
+- name: a
+  context:
+    cluster: infra-a
+    user: admin-a
+- name: b
+  context:
+    cluster: infra-b
+    user: admin-b
+current-context: a
+users:
+- name: admin-a
+  user:
+    token: token-a
+- name: admin-b
+  user:
+    token: token-b
+`
+
+func TestLoadKubeConfigContentWithContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kubeconfig")
+	if err := ioutil.WriteFile(path, []byte(multiContextKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := LoadKubeConfigContent(path, "b")
+	if !assert.NoError(t, err) {
+		return
+	}
+	config, err := clientcmd.Load(content)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "b", config.CurrentContext)
+	assert.Len(t, config.Contexts, 1)
+	assert.Len(t, config.Clusters, 1)
+	assert.Equal(t, "https://b.example.com:6443", config.Clusters["infra-b"].Server)
+	assert.Equal(t, "token-b", config.AuthInfos["admin-b"].Token)
+
+	_, err = LoadKubeConfigContent(path, "c")
+	assert.EqualError(t, err, "context c not found in kubeconfig "+path)
+}
+
+func TestValidateInfraKubeconfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kubeconfig")
+	if err := ioutil.WriteFile(path, []byte(multiContextKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		platform *kubevirt.Platform
+		expected string
+	}{
+		{
+			name:     "defaults",
+			platform: &kubevirt.Platform{},
+		},
+		{
+			name:     "valid context",
+			platform: &kubevirt.Platform{InfraKubeconfigPath: path, InfraContext: "b"},
+		},
+		{
+			name:     "missing context",
+			platform: &kubevirt.Platform{InfraKubeconfigPath: path, InfraContext: "c"},
+			expected: `^platform\.kubevirt\.infraContext: Invalid value: "c": context c not found in kubeconfig .*, the available contexts are: a, b$`,
+		},
+		{
+			name:     "missing kubeconfig",
+			platform: &kubevirt.Platform{InfraKubeconfigPath: filepath.Join(dir, "missing")},
+			expected: `^platform\.kubevirt\.infraKubeconfigPath: Invalid value: ".*missing": failed to load kubeconfig .*missing, with error: .*$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateInfraKubeconfig(tc.platform, field.NewPath("platform", "kubevirt")).ToAggregate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
+
+const testCACert = `-----BEGIN CERTIFICATE-----
+MIICYTCCAcqgAwIBAgIJAI2kA+uXAbhOMA0GCSqGSIb3DQEBCwUAMEgxCzAJBgNV
+BAYTAlVTMQswCQYDVQQIDAJDQTEWMBQGA1UEBwwNU2FuIEZyYW5jaXNjbzEUMBIG
+A1UECgwLUmVkIEhhdCBJbmMwHhcNMTkwMjEyMTkzMjUzWhcNMTkwMjEzMTkzMjUz
+WjBIMQswCQYDVQQGEwJVUzELMAkGA1UECAwCQ0ExFjAUBgNVBAcMDVNhbiBGcmFu
+Y2lzY28xFDASBgNVBAoMC1JlZCBIYXQgSW5jMIGfMA0GCSqGSIb3DQEBAQUAA4GN
+ADCBiQKBgQC+HOC0mKig/oINAKPo88LqxDJ4l7lozdLtp5oGeqWrLUXSfkvXAkQY
+2QYdvPAjpRfH7Ii7G0Asx+HTKdvula7B5fXDjc6NYKuEpTJZRV1ugntI97bozF/E
+C2BBmxxEnJN3+Xe8RYXMjz5Q4aqPw9vZhlWN+0hrREl1Ea/zHuWFIQIDAQABo1Mw
+UTAdBgNVHQ4EFgQUvTS1XjlvOdsufSyWxukyQu3LriEwHwYDVR0jBBgwFoAUvTS1
+XjlvOdsufSyWxukyQu3LriEwDwYDVR0TAQH/BAUwAwEB/zANBgkqhkiG9w0BAQsF
+AAOBgQB9gFcOXnzJrM65QqxeCB9Z5l5JMjp45UFC9Bj2cgwDHP80Zvi4omlaacC6
+aavmnLd67zm9PbYDWRaOIWAMeB916Iwaw/v6I0jwhAk/VxX5Fl6cGlZu9jZ3zbFE
+2sDqkwzIuSjCG2A23s6d4M1S3IXCCydoCSLMu+WhLkbboK6jEg==
+-----END CERTIFICATE-----
+`
+
+func TestAddCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cabundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundleFile := filepath.Join(dir, "bundle.pem")
+	if err := ioutil.WriteFile(bundleFile, []byte(testCACert), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfigCAFile := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(kubeconfigCAFile, []byte("kubeconfig CA"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name           string
+		tlsConfig      rest.TLSClientConfig
+		caBundle       string
+		expectedCAData string
+		expectedError  string
+	}{
+		{
+			name:      "unset",
+			tlsConfig: rest.TLSClientConfig{CAFile: kubeconfigCAFile},
+		},
+		{
+			name:           "inline without kubeconfig CA",
+			caBundle:       testCACert,
+			expectedCAData: testCACert,
+		},
+		{
+			name:           "inline with kubeconfig CA data",
+			tlsConfig:      rest.TLSClientConfig{CAData: []byte("kubeconfig CA")},
+			caBundle:       testCACert,
+			expectedCAData: "kubeconfig CA\n" + testCACert,
+		},
+		{
+			name:           "file with kubeconfig CA file",
+			tlsConfig:      rest.TLSClientConfig{CAFile: kubeconfigCAFile},
+			caBundle:       bundleFile,
+			expectedCAData: "kubeconfig CA\n" + testCACert,
+		},
+		{
+			name:          "missing file",
+			caBundle:      filepath.Join(dir, "missing.pem"),
+			expectedError: "failed to read the infra cluster CA bundle",
+		},
+		{
+			name:          "no certificates",
+			caBundle:      "-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----\n",
+			expectedError: "the infra cluster CA bundle holds no PEM-encoded certificates",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &rest.Config{TLSClientConfig: tc.tlsConfig}
+			err := addCABundle(config, tc.caBundle)
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.caBundle == "" {
+				assert.Equal(t, tc.tlsConfig, config.TLSClientConfig)
+				return
+			}
+			assert.Equal(t, tc.expectedCAData, string(config.TLSClientConfig.CAData))
+			assert.Empty(t, config.TLSClientConfig.CAFile)
+		})
+	}
+}
+
+// newTestClient returns a client of the API server served by the handler, and the function
+// stopping the server.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*client, func()) {
+	server := httptest.NewServer(handler)
+	config := &rest.Config{Host: server.URL}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return &client{restConfig: config, dynamicClient: dynamicClient}, server.Close
+}
+
+func TestAddResourceLabels(t *testing.T) {
+	labels := map[string]string{"tenantcluster-infra-id-machine.openshift.io": "owned"}
+	var patches []string
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		patches = append(patches, r.URL.Path+" "+string(body))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "infra-id-master-0-ignition", "namespace": "tenant", "labels": labels},
+		})
+	})
+	defer stop()
+
+	assert.NoError(t, c.AddResourceLabels(context.Background(), "tenant", "virtualmachines", "infra-id-master-0", labels))
+	assert.NoError(t, c.AddResourceLabels(context.Background(), "tenant", "secrets", "infra-id-master-0-ignition", labels))
+	assert.EqualError(t, c.AddResourceLabels(context.Background(), "tenant", "configmaps", "infra-id-master-0", labels), "unsupported resource configmaps")
+
+	// The merge patches only add the labels, keeping the other labels of the resources
+	assert.Equal(t, []string{
+		`/apis/kubevirt.io/v1alpha3/namespaces/tenant/virtualmachines/infra-id-master-0 {"metadata":{"labels":{"tenantcluster-infra-id-machine.openshift.io":"owned"}}}`,
+		`/api/v1/namespaces/tenant/secrets/infra-id-master-0-ignition {"metadata":{"labels":{"tenantcluster-infra-id-machine.openshift.io":"owned"}}}`,
+	}, patches)
+}
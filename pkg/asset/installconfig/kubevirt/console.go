@@ -0,0 +1,383 @@
+package kubevirt
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	kubevirtapiv1 "kubevirt.io/client-go/api/v1"
+)
+
+const (
+	// consoleSubprotocol is the websocket subprotocol of the console subresource, carrying the
+	// raw bytes of the serial console in binary messages.
+	consoleSubprotocol = "plain.kubevirt.io"
+	// websocketGUID is the GUID the accept key of the websocket handshake is derived with.
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// The websocket opcodes.
+const (
+	websocketContinuation = 0x0
+	websocketText         = 0x1
+	websocketBinary       = 0x2
+	websocketClose        = 0x8
+	websocketPing         = 0x9
+	websocketPong         = 0xa
+)
+
+// SerialConsole connects to the serial console of the VMI through the console subresource of
+// the KubeVirt API, as virtctl console does. The returned connection carries the raw bytes of
+// the console, closing it disconnects from the console.
+func (c *client) SerialConsole(ctx context.Context, namespace string, name string) (io.ReadWriteCloser, error) {
+	host := c.restConfig.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	consoleURL, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid infra cluster host")
+	}
+	gv := kubevirtapiv1.SubresourceStorageGroupVersion
+	consoleURL.Path = strings.TrimSuffix(consoleURL.Path, "/") + fmt.Sprintf("/apis/%s/%s/namespaces/%s/virtualmachineinstances/%s/console", gv.Group, gv.Version, namespace, name)
+
+	tlsConfig, err := rest.TLSConfigFor(c.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	upgrader := &websocketUpgrader{ctx: ctx, tlsConfig: tlsConfig, proxy: c.restConfig.Proxy}
+	// The authentication of the kubeconfig is added by the wrappers of the rest config
+	roundTripper, err := rest.HTTPWrappersForConfig(c.restConfig, upgrader)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := upgrader.connect(roundTripper, consoleURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the serial console of VMI %s/%s", namespace, name)
+	}
+	return conn, nil
+}
+
+// websocketUpgrader is the round tripper of the websocket handshake, keeping the connection
+// of the response to be used by the websocket.
+type websocketUpgrader struct {
+	ctx       context.Context
+	tlsConfig *tls.Config
+	// proxy returns the proxy of the requests, as the proxy of the rest config does. The proxy
+	// of the environment is used when it is nil.
+	proxy func(*http.Request) (*url.URL, error)
+
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (u *websocketUpgrader) RoundTrip(req *http.Request) (*http.Response, error) {
+	address := hostPort(req.URL)
+	proxy := u.proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	proxyURL, err := proxy(req)
+	if err != nil {
+		return nil, err
+	}
+	var conn net.Conn
+	if proxyURL != nil {
+		conn, err = u.dialProxy(proxyURL, address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(u.ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "http" {
+		tlsConfig := &tls.Config{}
+		if u.tlsConfig != nil {
+			tlsConfig = u.tlsConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = req.URL.Hostname()
+		}
+		// The connection is upgraded from HTTP/1.1, HTTP/2 has no upgrade
+		tlsConfig.NextProtos = []string{"http/1.1"}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	u.conn = conn
+	u.reader = reader
+	return resp, nil
+}
+
+// hostPort returns the address of the host of the URL, with the default port of its scheme when
+// it has none.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "443"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// dialProxy connects to the address through the HTTP proxy, with a CONNECT tunnel the
+// websocket is then established in as through a direct connection.
+func (u *websocketUpgrader) dialProxy(proxyURL *url.URL, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(u.ctx, "tcp", hostPort(proxyURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the proxy")
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to connect to the proxy")
+		}
+		conn = tlsConn
+	}
+
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		connectReq.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := connectReq.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// The proxy sends nothing past its response before the client speaks, the reader buffers
+	// no byte of the tunnel
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, errors.Errorf("proxy refused to connect to %s: %s", address, resp.Status)
+	}
+	return conn, nil
+}
+
+// connect does the websocket handshake with the URL through the round tripper wrapping the
+// upgrader, and returns the websocket once the server switched to it.
+func (u *websocketUpgrader) connect(roundTripper http.RoundTripper, target *url.URL) (*websocketConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(u.ctx)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Protocol", consoleSubprotocol)
+
+	resp, err := roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer u.conn.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		if message := strings.TrimSpace(string(body)); message != "" {
+			return nil, errors.Errorf("%s: %s", resp.Status, message)
+		}
+		return nil, errors.New(resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		u.conn.Close()
+		return nil, errors.New("invalid websocket handshake response")
+	}
+	return &websocketConn{conn: u.conn, reader: u.reader}, nil
+}
+
+// websocketAccept returns the accept key of the handshake response for the key of the request.
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// websocketConn is the client side of a websocket, reading and writing the payloads of its
+// data messages as a stream. The control messages are handled as they are read.
+type websocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// writeLock serializes the frames written by Write and by the replies to the control
+	// messages.
+	writeLock sync.Mutex
+	// remaining is the length of the payload of the current data frame not read yet, which is
+	// unmasked with mask from maskPos when the frame is masked.
+	remaining uint64
+	masked    bool
+	mask      [4]byte
+	maskPos   int
+}
+
+func (w *websocketConn) Read(p []byte) (int, error) {
+	for w.remaining == 0 {
+		opcode, length, err := w.readHeader()
+		if err != nil {
+			return 0, err
+		}
+		switch opcode {
+		case websocketContinuation, websocketText, websocketBinary:
+			w.remaining = length
+		case websocketClose, websocketPing, websocketPong:
+			payload := make([]byte, length)
+			if _, err := io.ReadFull(w.reader, payload); err != nil {
+				return 0, err
+			}
+			w.unmask(payload)
+			switch opcode {
+			case websocketClose:
+				w.writeFrame(websocketClose, payload)
+				return 0, io.EOF
+			case websocketPing:
+				if err := w.writeFrame(websocketPong, payload); err != nil {
+					return 0, err
+				}
+			}
+		default:
+			return 0, errors.Errorf("unexpected websocket opcode %d", opcode)
+		}
+	}
+
+	if uint64(len(p)) > w.remaining {
+		p = p[:w.remaining]
+	}
+	n, err := w.reader.Read(p)
+	w.unmask(p[:n])
+	w.remaining -= uint64(n)
+	return n, err
+}
+
+// readHeader reads the header of the next frame, returning its opcode and payload length.
+func (w *websocketConn) readHeader() (byte, uint64, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(w.reader, header[:]); err != nil {
+		return 0, 0, err
+	}
+	opcode := header[0] & 0x0f
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(w.reader, extended[:]); err != nil {
+			return 0, 0, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(w.reader, extended[:]); err != nil {
+			return 0, 0, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	w.masked = header[1]&0x80 != 0
+	w.maskPos = 0
+	if w.masked {
+		if _, err := io.ReadFull(w.reader, w.mask[:]); err != nil {
+			return 0, 0, err
+		}
+	}
+	return opcode, length, nil
+}
+
+// unmask unmasks the next bytes of the payload of the current frame, when it is masked.
+func (w *websocketConn) unmask(p []byte) {
+	if !w.masked {
+		return
+	}
+	for i := range p {
+		p[i] ^= w.mask[w.maskPos%4]
+		w.maskPos++
+	}
+}
+
+func (w *websocketConn) Write(p []byte) (int, error) {
+	if err := w.writeFrame(websocketBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeFrame writes a final frame with the payload, masked as the frames of the clients are.
+func (w *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+	_, err := w.conn.Write(frame)
+	return err
+}
+
+// Close sends a normal closure to the server and closes the connection.
+func (w *websocketConn) Close() error {
+	w.writeFrame(websocketClose, []byte{0x03, 0xe8})
+	return w.conn.Close()
+}
@@ -0,0 +1,179 @@
+package kubevirt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+// consoleServer echoes what is written to the serial console of the VMI tenant/master-0, and
+// refuses the other VMIs.
+func consoleServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/subresources.kubevirt.io/v1alpha3/namespaces/tenant/virtualmachineinstances/master-0/console" {
+			http.Error(w, "virtualmachineinstance not found", http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, consoleSubprotocol, r.Header.Get("Sec-WebSocket-Protocol"))
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		buf.WriteString("Sec-WebSocket-Accept: " + websocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		buf.Flush()
+
+		// The frames of the server are masked as well, which the client accepts
+		ws := &websocketConn{conn: conn, reader: bufio.NewReader(buf)}
+		io.Copy(ws, ws)
+	}))
+}
+
+func TestSerialConsole(t *testing.T) {
+	server := consoleServer(t)
+	defer server.Close()
+	c := &client{restConfig: &rest.Config{Host: server.URL, BearerToken: "token"}}
+
+	t.Run("connected", func(t *testing.T) {
+		console, err := c.SerialConsole(context.Background(), "tenant", "master-0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer console.Close()
+
+		for _, message := range []string{"root\r", string(make([]byte, 300))} {
+			_, err = console.Write([]byte(message))
+			assert.NoError(t, err)
+			echo := make([]byte, len(message))
+			_, err = io.ReadFull(console, echo)
+			assert.NoError(t, err)
+			assert.Equal(t, message, string(echo))
+		}
+	})
+
+	t.Run("proxied", func(t *testing.T) {
+		var tunnels []string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNzd29yZA==" {
+				http.Error(w, "", http.StatusProxyAuthRequired)
+				return
+			}
+			tunnels = append(tunnels, r.Host)
+			target, err := net.Dial("tcp", r.Host)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer target.Close()
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			buf.WriteString("HTTP/1.1 200 Connection established\r\n\r\n")
+			buf.Flush()
+			go io.Copy(target, buf)
+			io.Copy(conn, target)
+		}))
+		defer proxy.Close()
+		proxyURL, err := url.Parse(proxy.URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		proxyURL.User = url.UserPassword("user", "password")
+		c := &client{restConfig: &rest.Config{Host: server.URL, BearerToken: "token", Proxy: http.ProxyURL(proxyURL)}}
+		console, err := c.SerialConsole(context.Background(), "tenant", "master-0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer console.Close()
+		_, err = console.Write([]byte("root\r"))
+		assert.NoError(t, err)
+		echo := make([]byte, 5)
+		_, err = io.ReadFull(console, echo)
+		assert.NoError(t, err)
+		assert.Equal(t, "root\r", string(echo))
+		assert.Equal(t, []string{server.Listener.Addr().String()}, tunnels)
+
+		proxyURL.User = nil
+		c = &client{restConfig: &rest.Config{Host: server.URL, BearerToken: "token", Proxy: http.ProxyURL(proxyURL)}}
+		_, err = c.SerialConsole(context.Background(), "tenant", "master-0")
+		assert.EqualError(t, err, "failed to connect to the serial console of VMI tenant/master-0: proxy refused to connect to "+server.Listener.Addr().String()+": 407 Proxy Authentication Required")
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		_, err := c.SerialConsole(context.Background(), "tenant", "master-1")
+		assert.EqualError(t, err, "failed to connect to the serial console of VMI tenant/master-1: 404 Not Found: virtualmachineinstance not found")
+	})
+}
+
+func TestWebsocketFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	serverConn, err := listener.Accept()
+	if !assert.NoError(t, err) {
+		return
+	}
+	client := &websocketConn{conn: clientConn, reader: bufio.NewReader(clientConn)}
+	server := &websocketConn{conn: serverConn, reader: bufio.NewReader(serverConn)}
+	defer serverConn.Close()
+
+	// The lengths around the limits of the 7 bits, 16 bits and 64 bits payload lengths
+	for _, length := range []int{1, 125, 126, 0xffff, 0x10000} {
+		payload := bytes.Repeat([]byte{byte(length)}, length)
+		_, err := client.Write(payload)
+		assert.NoError(t, err)
+		received := make([]byte, length)
+		_, err = io.ReadFull(server, received)
+		assert.NoError(t, err)
+		assert.Equal(t, payload, received, "payload of %d bytes", length)
+	}
+
+	// The pings are answered with a pong carrying their payload while reading the data
+	assert.NoError(t, server.writeFrame(websocketPing, []byte("heartbeat")))
+	_, err = server.Write([]byte("login:"))
+	assert.NoError(t, err)
+	received := make([]byte, 6)
+	_, err = io.ReadFull(client, received)
+	assert.NoError(t, err)
+	assert.Equal(t, "login:", string(received))
+	assert.Equal(t, []byte("heartbeat"), readControlFrame(t, server, websocketPong))
+
+	// Closing sends a normal closure
+	assert.NoError(t, client.Close())
+	assert.Equal(t, []byte{0x03, 0xe8}, readControlFrame(t, server, websocketClose))
+}
+
+// readControlFrame reads the next frame of the websocket, which must have the opcode, and returns
+// its unmasked payload.
+func readControlFrame(t *testing.T, ws *websocketConn, opcode byte) []byte {
+	readOpcode, length, err := ws.readHeader()
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	assert.Equal(t, opcode, readOpcode)
+	payload := make([]byte, length)
+	_, err = io.ReadFull(ws.reader, payload)
+	assert.NoError(t, err)
+	ws.unmask(payload)
+	return payload
+}
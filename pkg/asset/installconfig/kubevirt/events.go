@@ -0,0 +1,96 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// Milestone is a step of the lifecycle of the cluster recorded in the infra cluster.
+type Milestone string
+
+const (
+	// MilestoneProvisioningStarted is recorded before the infrastructure of the cluster is created.
+	MilestoneProvisioningStarted Milestone = "ProvisioningStarted"
+	// MilestoneBootstrapComplete is recorded once the bootstrap control plane handed over to
+	// the masters.
+	MilestoneBootstrapComplete Milestone = "BootstrapComplete"
+	// MilestoneInstallComplete is recorded once the cluster is installed.
+	MilestoneInstallComplete Milestone = "InstallComplete"
+	// MilestoneDestroyStarted is recorded before the resources of the cluster are deleted.
+	MilestoneDestroyStarted Milestone = "DestroyStarted"
+)
+
+// eventSource is the component of the events recorded by the installer.
+const eventSource = "openshift-installer"
+
+// StatusConfigMapName returns the name of the ConfigMap recording the last milestone of the
+// cluster, in the namespace of the cluster, which the events of the milestones are about.
+func StatusConfigMapName(infraID string) string {
+	return infraID + "-install-status"
+}
+
+// RecordMilestone records the milestone of the cluster in its namespace of the infra cluster, for
+// the infra cluster admins to follow the cluster with kubectl: the status ConfigMap of the cluster
+// is updated to the milestone and an event about it is created.
+func RecordMilestone(infraID string, metadata *kubevirttypes.Metadata, milestone Milestone, message string) error {
+	client, err := NewClientForMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	return recordMilestone(context.TODO(), client, infraID, metadata, milestone, message, time.Now())
+}
+
+func recordMilestone(ctx context.Context, client Client, infraID string, metadata *kubevirttypes.Metadata, milestone Milestone, message string, now time.Time) error {
+	name := StatusConfigMapName(infraID)
+	// The ConfigMap carries the labels of the cluster for destroy to delete it, and a fresh
+	// heartbeat as its annotations are replaced
+	err := client.CreateOrUpdateConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   metadata.Namespace,
+			Labels:      metadata.Labels,
+			Annotations: map[string]string{HeartbeatAnnotation: now.UTC().Format(time.RFC3339)},
+		},
+		Data: map[string]string{
+			"milestone": string(milestone),
+			"message":   message,
+			"time":      now.UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the status ConfigMap %s: %v", name, err)
+	}
+
+	timestamp := metav1.NewTime(now)
+	err = client.CreateEvent(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// The events recorded by client-go are named the same way
+			Name:      fmt.Sprintf("%s.%x", name, now.UnixNano()),
+			Namespace: metadata.Namespace,
+			Labels:    metadata.Labels,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       name,
+			Namespace:  metadata.Namespace,
+		},
+		Reason:         string(milestone),
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: eventSource},
+		FirstTimestamp: timestamp,
+		LastTimestamp:  timestamp,
+		Count:          1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the %s event: %v", milestone, err)
+	}
+	return nil
+}
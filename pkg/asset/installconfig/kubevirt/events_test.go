@@ -0,0 +1,55 @@
+package kubevirt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestRecordMilestone(t *testing.T) {
+	metadata := &kubevirt.Metadata{Namespace: validNamespace, Labels: kubevirt.OwnerLabels("infra-id")}
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().CreateOrUpdateConfigMap(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, configMap *corev1.ConfigMap) error {
+		assert.Equal(t, "infra-id-install-status", configMap.Name)
+		assert.Equal(t, validNamespace, configMap.Namespace)
+		assert.Equal(t, metadata.Labels, configMap.Labels)
+		assert.Equal(t, map[string]string{HeartbeatAnnotation: "2021-03-04T05:06:07Z"}, configMap.Annotations)
+		assert.Equal(t, map[string]string{"milestone": "BootstrapComplete", "message": "bootstrapped", "time": "2021-03-04T05:06:07Z"}, configMap.Data)
+		return nil
+	})
+	client.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *corev1.Event) error {
+		assert.Equal(t, validNamespace, event.Namespace)
+		assert.Equal(t, "infra-id-install-status", event.InvolvedObject.Name)
+		assert.Equal(t, "ConfigMap", event.InvolvedObject.Kind)
+		assert.Equal(t, "BootstrapComplete", event.Reason)
+		assert.Equal(t, "bootstrapped", event.Message)
+		assert.Equal(t, corev1.EventTypeNormal, event.Type)
+		return nil
+	})
+
+	assert.NoError(t, recordMilestone(context.Background(), client, "infra-id", metadata, MilestoneBootstrapComplete, "bootstrapped", now))
+}
+
+func TestRecordMilestoneConfigMapError(t *testing.T) {
+	metadata := &kubevirt.Metadata{Namespace: validNamespace}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().CreateOrUpdateConfigMap(gomock.Any(), gomock.Any()).Return(errors.New("forbidden"))
+
+	err := recordMilestone(context.Background(), client, "infra-id", metadata, MilestoneDestroyStarted, "destroying", time.Now())
+	assert.EqualError(t, err, "failed to update the status ConfigMap infra-id-install-status: forbidden")
+}
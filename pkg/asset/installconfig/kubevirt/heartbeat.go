@@ -0,0 +1,60 @@
+package kubevirt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// HeartbeatAnnotation is the annotation of the status ConfigMap of the cluster holding the
+	// last time, in RFC 3339 format, the installer creating the cluster was seen running. A status
+	// ConfigMap whose milestone is not InstallComplete and whose heartbeat is older than a few
+	// HeartbeatIntervals belongs to an abandoned install, which can be destroyed safely.
+	HeartbeatAnnotation = "installer.openshift.io/heartbeat"
+
+	// HeartbeatInterval is how often the heartbeat of the status ConfigMap is updated.
+	HeartbeatInterval = time.Minute
+)
+
+// StartHeartbeat updates the heartbeat of the status ConfigMap of the cluster every
+// HeartbeatInterval, until the returned function is called. The heartbeats which fail are only
+// logged, the status ConfigMap being created by the first milestone recorded.
+func StartHeartbeat(infraID string, metadata *kubevirttypes.Metadata) (stop func()) {
+	client, err := NewClientForMetadata(metadata)
+	if err != nil {
+		logrus.Debugf("Not updating the heartbeat of the cluster in the infra cluster: %v", err)
+		return func() {}
+	}
+	return startHeartbeat(client, metadata.Namespace, StatusConfigMapName(infraID), HeartbeatInterval)
+}
+
+func startHeartbeat(client Client, namespace string, name string, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				annotations := map[string]string{HeartbeatAnnotation: now.UTC().Format(time.RFC3339)}
+				if err := client.AnnotateConfigMap(ctx, namespace, name, annotations); err != nil && ctx.Err() == nil {
+					logrus.Debugf("Failed to update the heartbeat of the cluster in the infra cluster: %v", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,45 @@
+package kubevirt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+func TestHeartbeat(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	client := mock.NewMockClient(mockCtrl)
+	beats := make(chan string, 10)
+	client.EXPECT().AnnotateConfigMap(gomock.Any(), validNamespace, "infra-id-install-status", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ string, annotations map[string]string) error {
+		select {
+		case beats <- annotations[HeartbeatAnnotation]:
+		default:
+		}
+		return errors.New("not found")
+	}).MinTimes(2)
+
+	stop := startHeartbeat(client, validNamespace, "infra-id-install-status", 10*time.Millisecond)
+	// The failed heartbeats don't stop the next ones
+	for i := 0; i < 2; i++ {
+		select {
+		case beat := <-beats:
+			_, err := time.Parse(time.RFC3339, beat)
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no heartbeat")
+		}
+	}
+	stop()
+	for len(beats) > 0 {
+		<-beats
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, beats, "heartbeat after stop")
+}
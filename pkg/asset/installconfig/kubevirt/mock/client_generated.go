@@ -7,10 +7,15 @@ package mock
 import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
+	io "io"
 	v1 "k8s.io/api/core/v1"
+	v11 "k8s.io/api/scheduling/v1"
 	v10 "k8s.io/api/storage/v1"
+	v1alpha1 "k8s.io/api/storage/v1alpha1"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	watch "k8s.io/apimachinery/pkg/watch"
 	reflect "reflect"
+	time "time"
 )
 
 // MockClient is a mock of Client interface
@@ -81,6 +86,51 @@ func (mr *MockClientMockRecorder) GetStorageClass(ctx, name interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageClass", reflect.TypeOf((*MockClient)(nil).GetStorageClass), ctx, name)
 }
 
+// GetPriorityClass mocks base method
+func (m *MockClient) GetPriorityClass(ctx context.Context, name string) (*v11.PriorityClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriorityClass", ctx, name)
+	ret0, _ := ret[0].(*v11.PriorityClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriorityClass indicates an expected call of GetPriorityClass
+func (mr *MockClientMockRecorder) GetPriorityClass(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriorityClass", reflect.TypeOf((*MockClient)(nil).GetPriorityClass), ctx, name)
+}
+
+// ListPriorityClasses mocks base method
+func (m *MockClient) ListPriorityClasses(ctx context.Context) ([]v11.PriorityClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriorityClasses", ctx)
+	ret0, _ := ret[0].([]v11.PriorityClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriorityClasses indicates an expected call of ListPriorityClasses
+func (mr *MockClientMockRecorder) ListPriorityClasses(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriorityClasses", reflect.TypeOf((*MockClient)(nil).ListPriorityClasses), ctx)
+}
+
+// ListStorageClasses mocks base method
+func (m *MockClient) ListStorageClasses(ctx context.Context) ([]v10.StorageClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStorageClasses", ctx)
+	ret0, _ := ret[0].([]v10.StorageClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStorageClasses indicates an expected call of ListStorageClasses
+func (mr *MockClientMockRecorder) ListStorageClasses(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStorageClasses", reflect.TypeOf((*MockClient)(nil).ListStorageClasses), ctx)
+}
+
 // GetNetworkAttachmentDefinition mocks base method
 func (m *MockClient) GetNetworkAttachmentDefinition(ctx context.Context, name, namespace string) (*unstructured.Unstructured, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +146,109 @@ func (mr *MockClientMockRecorder) GetNetworkAttachmentDefinition(ctx, name, name
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkAttachmentDefinition", reflect.TypeOf((*MockClient)(nil).GetNetworkAttachmentDefinition), ctx, name, namespace)
 }
 
+// ListNetworkAttachmentDefinitions mocks base method
+func (m *MockClient) ListNetworkAttachmentDefinitions(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNetworkAttachmentDefinitions", ctx, namespace)
+	ret0, _ := ret[0].([]unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNetworkAttachmentDefinitions indicates an expected call of ListNetworkAttachmentDefinitions
+func (mr *MockClientMockRecorder) ListNetworkAttachmentDefinitions(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworkAttachmentDefinitions", reflect.TypeOf((*MockClient)(nil).ListNetworkAttachmentDefinitions), ctx, namespace)
+}
+
+// CreateNetworkAttachmentDefinition mocks base method
+func (m *MockClient) CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNetworkAttachmentDefinition", ctx, nad)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNetworkAttachmentDefinition indicates an expected call of CreateNetworkAttachmentDefinition
+func (mr *MockClientMockRecorder) CreateNetworkAttachmentDefinition(ctx, nad interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetworkAttachmentDefinition", reflect.TypeOf((*MockClient)(nil).CreateNetworkAttachmentDefinition), ctx, nad)
+}
+
+// DeleteNetworkAttachmentDefinition mocks base method
+func (m *MockClient) DeleteNetworkAttachmentDefinition(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNetworkAttachmentDefinition", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNetworkAttachmentDefinition indicates an expected call of DeleteNetworkAttachmentDefinition
+func (mr *MockClientMockRecorder) DeleteNetworkAttachmentDefinition(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkAttachmentDefinition", reflect.TypeOf((*MockClient)(nil).DeleteNetworkAttachmentDefinition), namespace, name, wait)
+}
+
+// ListNetworkAttachmentDefinitionNames mocks base method
+func (m *MockClient) ListNetworkAttachmentDefinitionNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNetworkAttachmentDefinitionNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNetworkAttachmentDefinitionNames indicates an expected call of ListNetworkAttachmentDefinitionNames
+func (mr *MockClientMockRecorder) ListNetworkAttachmentDefinitionNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworkAttachmentDefinitionNames", reflect.TypeOf((*MockClient)(nil).ListNetworkAttachmentDefinitionNames), namespace, requiredLabels)
+}
+
+// ListResourceQuotas mocks base method
+func (m *MockClient) ListResourceQuotas(ctx context.Context, namespace string) ([]v1.ResourceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceQuotas", ctx, namespace)
+	ret0, _ := ret[0].([]v1.ResourceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceQuotas indicates an expected call of ListResourceQuotas
+func (mr *MockClientMockRecorder) ListResourceQuotas(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceQuotas", reflect.TypeOf((*MockClient)(nil).ListResourceQuotas), ctx, namespace)
+}
+
+// ListNodes mocks base method
+func (m *MockClient) ListNodes(ctx context.Context) ([]v1.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes", ctx)
+	ret0, _ := ret[0].([]v1.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes
+func (mr *MockClientMockRecorder) ListNodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MockClient)(nil).ListNodes), ctx)
+}
+
+// ListCSIStorageCapacities mocks base method
+func (m *MockClient) ListCSIStorageCapacities(ctx context.Context) ([]v1alpha1.CSIStorageCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCSIStorageCapacities", ctx)
+	ret0, _ := ret[0].([]v1alpha1.CSIStorageCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCSIStorageCapacities indicates an expected call of ListCSIStorageCapacities
+func (mr *MockClientMockRecorder) ListCSIStorageCapacities(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCSIStorageCapacities", reflect.TypeOf((*MockClient)(nil).ListCSIStorageCapacities), ctx)
+}
+
 // DeleteVirtualMachine mocks base method
 func (m *MockClient) DeleteVirtualMachine(namespace, name string, wait bool) error {
 	m.ctrl.T.Helper()
@@ -110,6 +263,76 @@ func (mr *MockClientMockRecorder) DeleteVirtualMachine(namespace, name, wait int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualMachine", reflect.TypeOf((*MockClient)(nil).DeleteVirtualMachine), namespace, name, wait)
 }
 
+// StopVirtualMachine mocks base method
+func (m *MockClient) StopVirtualMachine(namespace, name string, gracePeriod time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopVirtualMachine", namespace, name, gracePeriod)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopVirtualMachine indicates an expected call of StopVirtualMachine
+func (mr *MockClientMockRecorder) StopVirtualMachine(namespace, name, gracePeriod interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopVirtualMachine", reflect.TypeOf((*MockClient)(nil).StopVirtualMachine), namespace, name, gracePeriod)
+}
+
+// StartVirtualMachine mocks base method
+func (m *MockClient) StartVirtualMachine(namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVirtualMachine", namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartVirtualMachine indicates an expected call of StartVirtualMachine
+func (mr *MockClientMockRecorder) StartVirtualMachine(namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVirtualMachine", reflect.TypeOf((*MockClient)(nil).StartVirtualMachine), namespace, name)
+}
+
+// SetVirtualMachineRunStrategy mocks base method
+func (m *MockClient) SetVirtualMachineRunStrategy(namespace, name, runStrategy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVirtualMachineRunStrategy", namespace, name, runStrategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVirtualMachineRunStrategy indicates an expected call of SetVirtualMachineRunStrategy
+func (mr *MockClientMockRecorder) SetVirtualMachineRunStrategy(namespace, name, runStrategy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVirtualMachineRunStrategy", reflect.TypeOf((*MockClient)(nil).SetVirtualMachineRunStrategy), namespace, name, runStrategy)
+}
+
+// AnnotateVirtualMachine mocks base method
+func (m *MockClient) AnnotateVirtualMachine(ctx context.Context, namespace, name string, annotations map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnnotateVirtualMachine", ctx, namespace, name, annotations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnnotateVirtualMachine indicates an expected call of AnnotateVirtualMachine
+func (mr *MockClientMockRecorder) AnnotateVirtualMachine(ctx, namespace, name, annotations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnotateVirtualMachine", reflect.TypeOf((*MockClient)(nil).AnnotateVirtualMachine), ctx, namespace, name, annotations)
+}
+
+// WaitForVirtualMachineStopped mocks base method
+func (m *MockClient) WaitForVirtualMachineStopped(namespace, name string, timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForVirtualMachineStopped", namespace, name, timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForVirtualMachineStopped indicates an expected call of WaitForVirtualMachineStopped
+func (mr *MockClientMockRecorder) WaitForVirtualMachineStopped(namespace, name, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForVirtualMachineStopped", reflect.TypeOf((*MockClient)(nil).WaitForVirtualMachineStopped), namespace, name, timeout)
+}
+
 // ListVirtualMachineNames mocks base method
 func (m *MockClient) ListVirtualMachineNames(namespace string, requiredLabels map[string]string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +348,110 @@ func (mr *MockClientMockRecorder) ListVirtualMachineNames(namespace, requiredLab
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVirtualMachineNames", reflect.TypeOf((*MockClient)(nil).ListVirtualMachineNames), namespace, requiredLabels)
 }
 
+// ListAllVirtualMachineNames mocks base method
+func (m *MockClient) ListAllVirtualMachineNames(ctx context.Context, namespace string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllVirtualMachineNames", ctx, namespace)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllVirtualMachineNames indicates an expected call of ListAllVirtualMachineNames
+func (mr *MockClientMockRecorder) ListAllVirtualMachineNames(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllVirtualMachineNames", reflect.TypeOf((*MockClient)(nil).ListAllVirtualMachineNames), ctx, namespace)
+}
+
+// ListVirtualMachines mocks base method
+func (m *MockClient) ListVirtualMachines(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVirtualMachines", ctx, namespace)
+	ret0, _ := ret[0].([]unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVirtualMachines indicates an expected call of ListVirtualMachines
+func (mr *MockClientMockRecorder) ListVirtualMachines(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVirtualMachines", reflect.TypeOf((*MockClient)(nil).ListVirtualMachines), ctx, namespace)
+}
+
+// ListVirtualMachineInstances mocks base method
+func (m *MockClient) ListVirtualMachineInstances(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVirtualMachineInstances", ctx, namespace)
+	ret0, _ := ret[0].([]unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVirtualMachineInstances indicates an expected call of ListVirtualMachineInstances
+func (mr *MockClientMockRecorder) ListVirtualMachineInstances(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVirtualMachineInstances", reflect.TypeOf((*MockClient)(nil).ListVirtualMachineInstances), ctx, namespace)
+}
+
+// SerialConsole mocks base method
+func (m *MockClient) SerialConsole(ctx context.Context, namespace, name string) (io.ReadWriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SerialConsole", ctx, namespace, name)
+	ret0, _ := ret[0].(io.ReadWriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SerialConsole indicates an expected call of SerialConsole
+func (mr *MockClientMockRecorder) SerialConsole(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SerialConsole", reflect.TypeOf((*MockClient)(nil).SerialConsole), ctx, namespace, name)
+}
+
+// ListDataVolumes mocks base method
+func (m *MockClient) ListDataVolumes(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDataVolumes", ctx, namespace)
+	ret0, _ := ret[0].([]unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataVolumes indicates an expected call of ListDataVolumes
+func (mr *MockClientMockRecorder) ListDataVolumes(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataVolumes", reflect.TypeOf((*MockClient)(nil).ListDataVolumes), ctx, namespace)
+}
+
+// WatchDataVolumes mocks base method
+func (m *MockClient) WatchDataVolumes(ctx context.Context, namespace string) (watch.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchDataVolumes", ctx, namespace)
+	ret0, _ := ret[0].(watch.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchDataVolumes indicates an expected call of WatchDataVolumes
+func (mr *MockClientMockRecorder) WatchDataVolumes(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchDataVolumes", reflect.TypeOf((*MockClient)(nil).WatchDataVolumes), ctx, namespace)
+}
+
+// CreateDataVolume mocks base method
+func (m *MockClient) CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDataVolume", ctx, dv)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDataVolume indicates an expected call of CreateDataVolume
+func (mr *MockClientMockRecorder) CreateDataVolume(ctx, dv interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataVolume", reflect.TypeOf((*MockClient)(nil).CreateDataVolume), ctx, dv)
+}
+
 // DeleteDataVolume mocks base method
 func (m *MockClient) DeleteDataVolume(namespace, name string, wait bool) error {
 	m.ctrl.T.Helper()
@@ -182,3 +509,396 @@ func (mr *MockClientMockRecorder) ListSecretNames(namespace, requiredLabels inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretNames", reflect.TypeOf((*MockClient)(nil).ListSecretNames), namespace, requiredLabels)
 }
+
+// ListResourceLabels mocks base method
+func (m *MockClient) ListResourceLabels(ctx context.Context, namespace, resource string) (map[string]map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceLabels", ctx, namespace, resource)
+	ret0, _ := ret[0].(map[string]map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceLabels indicates an expected call of ListResourceLabels
+func (mr *MockClientMockRecorder) ListResourceLabels(ctx, namespace, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceLabels", reflect.TypeOf((*MockClient)(nil).ListResourceLabels), ctx, namespace, resource)
+}
+
+// AddResourceLabels mocks base method
+func (m *MockClient) AddResourceLabels(ctx context.Context, namespace, resource, name string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddResourceLabels", ctx, namespace, resource, name, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddResourceLabels indicates an expected call of AddResourceLabels
+func (mr *MockClientMockRecorder) AddResourceLabels(ctx, namespace, resource, name, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddResourceLabels", reflect.TypeOf((*MockClient)(nil).AddResourceLabels), ctx, namespace, resource, name, labels)
+}
+
+// GetConfigMap mocks base method
+func (m *MockClient) GetConfigMap(ctx context.Context, namespace, name string) (*v1.ConfigMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigMap", ctx, namespace, name)
+	ret0, _ := ret[0].(*v1.ConfigMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigMap indicates an expected call of GetConfigMap
+func (mr *MockClientMockRecorder) GetConfigMap(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigMap", reflect.TypeOf((*MockClient)(nil).GetConfigMap), ctx, namespace, name)
+}
+
+// CreateOrUpdateConfigMap mocks base method
+func (m *MockClient) CreateOrUpdateConfigMap(ctx context.Context, configMap *v1.ConfigMap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateConfigMap", ctx, configMap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateConfigMap indicates an expected call of CreateOrUpdateConfigMap
+func (mr *MockClientMockRecorder) CreateOrUpdateConfigMap(ctx, configMap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateConfigMap", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateConfigMap), ctx, configMap)
+}
+
+// AnnotateConfigMap mocks base method
+func (m *MockClient) AnnotateConfigMap(ctx context.Context, namespace, name string, annotations map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnnotateConfigMap", ctx, namespace, name, annotations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnnotateConfigMap indicates an expected call of AnnotateConfigMap
+func (mr *MockClientMockRecorder) AnnotateConfigMap(ctx, namespace, name, annotations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnotateConfigMap", reflect.TypeOf((*MockClient)(nil).AnnotateConfigMap), ctx, namespace, name, annotations)
+}
+
+// DeleteConfigMap mocks base method
+func (m *MockClient) DeleteConfigMap(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigMap", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigMap indicates an expected call of DeleteConfigMap
+func (mr *MockClientMockRecorder) DeleteConfigMap(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigMap", reflect.TypeOf((*MockClient)(nil).DeleteConfigMap), namespace, name, wait)
+}
+
+// ListConfigMapNames mocks base method
+func (m *MockClient) ListConfigMapNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigMapNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigMapNames indicates an expected call of ListConfigMapNames
+func (mr *MockClientMockRecorder) ListConfigMapNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigMapNames", reflect.TypeOf((*MockClient)(nil).ListConfigMapNames), namespace, requiredLabels)
+}
+
+// CreateEvent mocks base method
+func (m *MockClient) CreateEvent(ctx context.Context, event *v1.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent
+func (mr *MockClientMockRecorder) CreateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockClient)(nil).CreateEvent), ctx, event)
+}
+
+// CreatePod mocks base method
+func (m *MockClient) CreatePod(ctx context.Context, pod *v1.Pod) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePod", ctx, pod)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePod indicates an expected call of CreatePod
+func (mr *MockClientMockRecorder) CreatePod(ctx, pod interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePod", reflect.TypeOf((*MockClient)(nil).CreatePod), ctx, pod)
+}
+
+// GetPod mocks base method
+func (m *MockClient) GetPod(ctx context.Context, namespace, name string) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPod", ctx, namespace, name)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPod indicates an expected call of GetPod
+func (mr *MockClientMockRecorder) GetPod(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPod", reflect.TypeOf((*MockClient)(nil).GetPod), ctx, namespace, name)
+}
+
+// GetPodLogs mocks base method
+func (m *MockClient) GetPodLogs(ctx context.Context, namespace, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodLogs", ctx, namespace, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPodLogs indicates an expected call of GetPodLogs
+func (mr *MockClientMockRecorder) GetPodLogs(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodLogs", reflect.TypeOf((*MockClient)(nil).GetPodLogs), ctx, namespace, name)
+}
+
+// DeletePod mocks base method
+func (m *MockClient) DeletePod(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePod", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePod indicates an expected call of DeletePod
+func (mr *MockClientMockRecorder) DeletePod(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePod", reflect.TypeOf((*MockClient)(nil).DeletePod), namespace, name, wait)
+}
+
+// DeleteMultiNetworkPolicy mocks base method
+func (m *MockClient) DeleteMultiNetworkPolicy(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMultiNetworkPolicy", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMultiNetworkPolicy indicates an expected call of DeleteMultiNetworkPolicy
+func (mr *MockClientMockRecorder) DeleteMultiNetworkPolicy(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMultiNetworkPolicy", reflect.TypeOf((*MockClient)(nil).DeleteMultiNetworkPolicy), namespace, name, wait)
+}
+
+// ListMultiNetworkPolicyNames mocks base method
+func (m *MockClient) ListMultiNetworkPolicyNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMultiNetworkPolicyNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMultiNetworkPolicyNames indicates an expected call of ListMultiNetworkPolicyNames
+func (mr *MockClientMockRecorder) ListMultiNetworkPolicyNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultiNetworkPolicyNames", reflect.TypeOf((*MockClient)(nil).ListMultiNetworkPolicyNames), namespace, requiredLabels)
+}
+
+// DeletePodDisruptionBudget mocks base method
+func (m *MockClient) DeletePodDisruptionBudget(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePodDisruptionBudget", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePodDisruptionBudget indicates an expected call of DeletePodDisruptionBudget
+func (mr *MockClientMockRecorder) DeletePodDisruptionBudget(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePodDisruptionBudget", reflect.TypeOf((*MockClient)(nil).DeletePodDisruptionBudget), namespace, name, wait)
+}
+
+// ListPodDisruptionBudgetNames mocks base method
+func (m *MockClient) ListPodDisruptionBudgetNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPodDisruptionBudgetNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPodDisruptionBudgetNames indicates an expected call of ListPodDisruptionBudgetNames
+func (mr *MockClientMockRecorder) ListPodDisruptionBudgetNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPodDisruptionBudgetNames", reflect.TypeOf((*MockClient)(nil).ListPodDisruptionBudgetNames), namespace, requiredLabels)
+}
+
+// ApplyResource mocks base method
+func (m *MockClient) ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyResource", ctx, resource, obj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyResource indicates an expected call of ApplyResource
+func (mr *MockClientMockRecorder) ApplyResource(ctx, resource, obj interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyResource", reflect.TypeOf((*MockClient)(nil).ApplyResource), ctx, resource, obj)
+}
+
+// DeleteDeployment mocks base method
+func (m *MockClient) DeleteDeployment(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeployment", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeployment indicates an expected call of DeleteDeployment
+func (mr *MockClientMockRecorder) DeleteDeployment(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeployment", reflect.TypeOf((*MockClient)(nil).DeleteDeployment), namespace, name, wait)
+}
+
+// ListDeploymentNames mocks base method
+func (m *MockClient) ListDeploymentNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeploymentNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeploymentNames indicates an expected call of ListDeploymentNames
+func (mr *MockClientMockRecorder) ListDeploymentNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploymentNames", reflect.TypeOf((*MockClient)(nil).ListDeploymentNames), namespace, requiredLabels)
+}
+
+// DeleteService mocks base method
+func (m *MockClient) DeleteService(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService
+func (mr *MockClientMockRecorder) DeleteService(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockClient)(nil).DeleteService), namespace, name, wait)
+}
+
+// ListServiceNames mocks base method
+func (m *MockClient) ListServiceNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceNames indicates an expected call of ListServiceNames
+func (mr *MockClientMockRecorder) ListServiceNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceNames", reflect.TypeOf((*MockClient)(nil).ListServiceNames), namespace, requiredLabels)
+}
+
+// DeleteEndpoints mocks base method
+func (m *MockClient) DeleteEndpoints(namespace, name string, wait bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEndpoints", namespace, name, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEndpoints indicates an expected call of DeleteEndpoints
+func (mr *MockClientMockRecorder) DeleteEndpoints(namespace, name, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEndpoints", reflect.TypeOf((*MockClient)(nil).DeleteEndpoints), namespace, name, wait)
+}
+
+// ListEndpointsNames mocks base method
+func (m *MockClient) ListEndpointsNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpointsNames", namespace, requiredLabels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEndpointsNames indicates an expected call of ListEndpointsNames
+func (mr *MockClientMockRecorder) ListEndpointsNames(namespace, requiredLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpointsNames", reflect.TypeOf((*MockClient)(nil).ListEndpointsNames), namespace, requiredLabels)
+}
+
+// CheckAccess mocks base method
+func (m *MockClient) CheckAccess(ctx context.Context, namespace, group, resource, verb string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAccess", ctx, namespace, group, resource, verb)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAccess indicates an expected call of CheckAccess
+func (mr *MockClientMockRecorder) CheckAccess(ctx, namespace, group, resource, verb interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAccess", reflect.TypeOf((*MockClient)(nil).CheckAccess), ctx, namespace, group, resource, verb)
+}
+
+// CreateServiceAccountToken mocks base method
+func (m *MockClient) CreateServiceAccountToken(ctx context.Context, namespace, name string, expiration time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccountToken", ctx, namespace, name, expiration)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceAccountToken indicates an expected call of CreateServiceAccountToken
+func (mr *MockClientMockRecorder) CreateServiceAccountToken(ctx, namespace, name, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccountToken", reflect.TypeOf((*MockClient)(nil).CreateServiceAccountToken), ctx, namespace, name, expiration)
+}
+
+// GetServerVersion mocks base method
+func (m *MockClient) GetServerVersion() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerVersion")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerVersion indicates an expected call of GetServerVersion
+func (mr *MockClientMockRecorder) GetServerVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerVersion", reflect.TypeOf((*MockClient)(nil).GetServerVersion))
+}
+
+// GetKubeVirtVersion mocks base method
+func (m *MockClient) GetKubeVirtVersion(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKubeVirtVersion", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKubeVirtVersion indicates an expected call of GetKubeVirtVersion
+func (mr *MockClientMockRecorder) GetKubeVirtVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeVirtVersion", reflect.TypeOf((*MockClient)(nil).GetKubeVirtVersion), ctx)
+}
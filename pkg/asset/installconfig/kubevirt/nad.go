@@ -0,0 +1,178 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	nadv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// cniIPAM holds the parts of the CNI IPAM configuration used to find the subnets of
+// a NetworkAttachmentDefinition; host-local uses subnet/ranges and whereabouts uses range.
+type cniIPAM struct {
+	Type   string `json:"type,omitempty"`
+	Subnet string `json:"subnet,omitempty"`
+	Range  string `json:"range,omitempty"`
+	Ranges [][]struct {
+		Subnet string `json:"subnet,omitempty"`
+	} `json:"ranges,omitempty"`
+}
+
+// cniConfig is either a single CNI plugin configuration or a configuration list. OVN-Kubernetes
+// secondary networks hold their comma-separated subnets in subnets.
+type cniConfig struct {
+	Type    string       `json:"type,omitempty"`
+	IPAM    *cniIPAM     `json:"ipam,omitempty"`
+	Subnets string       `json:"subnets,omitempty"`
+	Plugins []*cniConfig `json:"plugins,omitempty"`
+}
+
+// networkAttachmentDefinitionConfig returns the parsed CNI configuration (spec.config) of the
+// NetworkAttachmentDefinition.
+func networkAttachmentDefinitionConfig(nad *unstructured.Unstructured) (*cniConfig, error) {
+	config, found, err := unstructured.NestedString(nad.Object, "spec", "config")
+	if err != nil {
+		return nil, err
+	}
+	if !found || config == "" {
+		return &cniConfig{}, nil
+	}
+	cfg := &cniConfig{}
+	if err := json.Unmarshal([]byte(config), cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse CNI config of network-attachment-definition %s: %v", nad.GetName(), err)
+	}
+	return cfg, nil
+}
+
+// NetworkAttachmentDefinition returns the network-attachment-definition named after the network
+// of the platform, in its namespace, created from its network template with the labels.
+func NetworkAttachmentDefinition(platform *kubevirt.Platform, labels map[string]string) (*unstructured.Unstructured, error) {
+	template := platform.CreateNetwork
+	if template == nil {
+		return nil, fmt.Errorf("no network template to create network-attachment-definition %s from", platform.NetworkName)
+	}
+
+	config := map[string]interface{}{
+		"cniVersion": "0.3.1",
+		"name":       platform.NetworkName,
+	}
+	switch template.Type {
+	case kubevirt.NetworkTypeOVN:
+		config["type"] = "ovn-k8s-cni-overlay"
+		config["netAttachDefName"] = platform.Namespace + "/" + platform.NetworkName
+		config["topology"] = "layer2"
+		if template.VLAN != 0 {
+			config["topology"] = "localnet"
+			config["vlanID"] = template.VLAN
+		}
+		if template.CIDR != "" {
+			config["subnets"] = template.CIDR
+		}
+	default:
+		config["type"] = "bridge"
+		config["bridge"] = template.Bridge
+		if template.VLAN != 0 {
+			config["vlan"] = template.VLAN
+		}
+		// The addresses are unique across the infra cluster nodes with whereabouts
+		if template.CIDR != "" {
+			config["ipam"] = map[string]interface{}{"type": "whereabouts", "range": template.CIDR}
+		}
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	nad := &unstructured.Unstructured{}
+	nad.SetAPIVersion(nadv1.SchemeGroupVersion.String())
+	nad.SetKind("NetworkAttachmentDefinition")
+	nad.SetNamespace(platform.Namespace)
+	nad.SetName(platform.NetworkName)
+	nad.SetLabels(labels)
+	if err := unstructured.SetNestedField(nad.Object, string(data), "spec", "config"); err != nil {
+		return nil, err
+	}
+	return nad, nil
+}
+
+// subnets returns all the subnets defined by the IPAM of the CNI configuration (and its plugins).
+func (c *cniConfig) subnets() ([]*net.IPNet, error) {
+	var cidrs []string
+	for _, subnet := range strings.Split(c.Subnets, ",") {
+		if subnet = strings.TrimSpace(subnet); subnet != "" {
+			cidrs = append(cidrs, subnet)
+		}
+	}
+	if c.IPAM != nil {
+		if c.IPAM.Subnet != "" {
+			cidrs = append(cidrs, c.IPAM.Subnet)
+		}
+		if c.IPAM.Range != "" {
+			cidrs = append(cidrs, c.IPAM.Range)
+		}
+		for _, rangeSet := range c.IPAM.Ranges {
+			for _, r := range rangeSet {
+				if r.Subnet != "" {
+					cidrs = append(cidrs, r.Subnet)
+				}
+			}
+		}
+	}
+
+	var result []*net.IPNet
+	for _, cidr := range cidrs {
+		_, subnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IPAM subnet %s: %v", cidr, err)
+		}
+		result = append(result, subnet)
+	}
+	for _, plugin := range c.Plugins {
+		pluginSubnets, err := plugin.subnets()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pluginSubnets...)
+	}
+	return result, nil
+}
+
+// unsupportedCNITypes are the CNI plugins known not to carry the cluster traffic of the VMs,
+// with the reason.
+var unsupportedCNITypes = map[string]string{
+	"ptp":      "its point-to-point links to the infra cluster node don't connect the VMs with each other",
+	"loopback": "it only sets up the loopback interface",
+	"ipvlan":   "its interfaces share the MAC address of the infra cluster node interface, which the VM bridge binding doesn't support",
+}
+
+// mainPlugin returns the configuration of the plugin creating the interface, which is the
+// first plugin of a configuration list, the others being chained to it.
+func (c *cniConfig) mainPlugin() *cniConfig {
+	if c.Type == "" && len(c.Plugins) > 0 {
+		return c.Plugins[0]
+	}
+	return c
+}
+
+// validateType returns an error when the CNI plugin creating the interface is known not to
+// work for the cluster traffic of the VMs. The unknown types are not rejected.
+func (c *cniConfig) validateType() error {
+	plugin := c.mainPlugin()
+	if reason, ok := unsupportedCNITypes[plugin.Type]; ok {
+		return fmt.Errorf("CNI type %s is not supported, %s", plugin.Type, reason)
+	}
+	if plugin.Type == "macvlan" && (plugin.IPAM == nil || plugin.IPAM.Type != "dhcp") {
+		ipamType := ""
+		if plugin.IPAM != nil {
+			ipamType = plugin.IPAM.Type
+		}
+		return fmt.Errorf("CNI type macvlan is only supported with the dhcp IPAM, not %q, as the VMs get their addresses with DHCP", ipamType)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// MachinePlacements returns the infra cluster nodes and zones which the VMIs with the labels in
+// the namespace are scheduled to, sorted by name. The VMIs not scheduled yet are returned
+// without a node.
+func MachinePlacements(ctx context.Context, client Client, namespace string, labels map[string]string) ([]kubevirt.MachinePlacement, error) {
+	vmis, err := client.ListVirtualMachineInstances(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the VMIs of namespace %s: %v", namespace, err)
+	}
+	var placements []kubevirt.MachinePlacement
+	for _, vmi := range vmis {
+		if !hasLabels(&vmi, labels) {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(vmi.Object, "status", "nodeName")
+		placements = append(placements, kubevirt.MachinePlacement{
+			Name:      vmi.GetName(),
+			Namespace: vmi.GetNamespace(),
+			Node:      node,
+		})
+	}
+	if len(placements) == 0 {
+		return nil, nil
+	}
+
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the nodes: %v", err)
+	}
+	zones := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		zone, ok := node.Labels[corev1.LabelZoneFailureDomainStable]
+		if !ok {
+			zone = node.Labels[corev1.LabelZoneFailureDomain]
+		}
+		zones[node.Name] = zone
+	}
+	for i := range placements {
+		placements[i].Zone = zones[placements[i].Node]
+	}
+
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].Name < placements[j].Name
+	})
+	return placements, nil
+}
+
+// hasLabels returns true if the object has all the labels.
+func hasLabels(object *unstructured.Unstructured, labels map[string]string) bool {
+	existing := object.GetLabels()
+	for k, v := range labels {
+		if existing[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func placementVMI(name string, namespace string, labels map[string]string, node string) unstructured.Unstructured {
+	vmi := unstructured.Unstructured{Object: map[string]interface{}{}}
+	vmi.SetName(name)
+	vmi.SetNamespace(namespace)
+	vmi.SetLabels(labels)
+	if node != "" {
+		unstructured.SetNestedField(vmi.Object, node, "status", "nodeName")
+	}
+	return vmi
+}
+
+func TestMachinePlacements(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	labels := map[string]string{"tenantcluster-test-machine.openshift.io": "owned"}
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListVirtualMachineInstances(gomock.Any(), validNamespace).Return([]unstructured.Unstructured{
+		placementVMI("test-master-1", validNamespace, labels, "node-1"),
+		placementVMI("test-master-0", validNamespace, labels, "node-0"),
+		placementVMI("other-master-0", validNamespace, map[string]string{"other": "owned"}, "node-0"),
+		placementVMI("test-master-2", validNamespace, labels, ""),
+	}, nil)
+	client.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{corev1.LabelZoneFailureDomainStable: "zone-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{corev1.LabelZoneFailureDomain: "zone-b"}}},
+	}, nil)
+
+	placements, err := MachinePlacements(context.TODO(), client, validNamespace, labels)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []kubevirt.MachinePlacement{
+			{Name: "test-master-0", Namespace: validNamespace, Node: "node-0", Zone: "zone-a"},
+			{Name: "test-master-1", Namespace: validNamespace, Node: "node-1", Zone: "zone-b"},
+			{Name: "test-master-2", Namespace: validNamespace},
+		}, placements)
+	}
+}
@@ -0,0 +1,195 @@
+package kubevirt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// networkProbeTimeout is how long the probe pod is given to run its checks.
+	networkProbeTimeout = 2 * time.Minute
+	// networkProbeInterface is the interface of the probe pod attached to the network.
+	networkProbeInterface = "net1"
+	// networkProbePrefix prefixes the lines of the probe output reporting the checks.
+	networkProbePrefix = "probe"
+)
+
+// The checks of the network probe.
+const (
+	NetworkProbeDHCP    = "dhcp"
+	NetworkProbeGateway = "gateway"
+	NetworkProbeDNS     = "dns"
+)
+
+// The checks of the VIPs probe.
+const (
+	NetworkProbeAPIVIP     = "api-vip"
+	NetworkProbeIngressVIP = "ingress-vip"
+)
+
+// NetworkProbeCheck is the result of a check of the network probe.
+type NetworkProbeCheck struct {
+	// Name is the name of the check, one of dhcp, gateway, dns, api-vip and ingress-vip.
+	Name   string
+	Passed bool
+}
+
+// reportNetworkProbeCheck writes to the script of the probe pod the command of the check,
+// printing a "probe <check> passed|failed" line depending on its exit status.
+func reportNetworkProbeCheck(script *strings.Builder, check string, command string) {
+	fmt.Fprintf(script, "if %s >/dev/null 2>&1; then echo %s %s passed; else echo %s %s failed; fi\n", command, networkProbePrefix, check, networkProbePrefix, check)
+}
+
+// networkProbeScript returns the shell script of the probe pod, printing a "probe <check>
+// passed|failed" line per check.
+func networkProbeScript(probe *kubevirt.NetworkProbe) string {
+	var script strings.Builder
+	report := func(check string, command string) { reportNetworkProbeCheck(&script, check, command) }
+	if probe.DHCP {
+		// The address leased is configured on the interface, for the next checks
+		script.WriteString(`printf '#!/bin/sh\n[ "$1" = bound ] && ip addr add "$ip/$mask" dev "$interface"\nexit 0\n' > /tmp/udhcpc.sh
+chmod +x /tmp/udhcpc.sh
+`)
+		report(NetworkProbeDHCP, fmt.Sprintf("udhcpc -i %s -n -q -t 5 -T 3 -s /tmp/udhcpc.sh", networkProbeInterface))
+	}
+	if probe.Gateway != "" {
+		report(NetworkProbeGateway, fmt.Sprintf("arping -c 3 -w 5 -I %s %s", networkProbeInterface, probe.Gateway))
+	}
+	if probe.DNSServer != "" {
+		report(NetworkProbeDNS, fmt.Sprintf("nslookup %s %s", probe.DNSName, probe.DNSServer))
+	}
+	return script.String()
+}
+
+// vipsProbeScript returns the shell script of the probe pod checking that no host of the
+// network answers ARP for the API and ingress VIPs, with the duplicate address detection of
+// arping, which exits successfully when no reply was received.
+func vipsProbeScript(platform *kubevirt.Platform) string {
+	var script strings.Builder
+	reportNetworkProbeCheck(&script, NetworkProbeAPIVIP, fmt.Sprintf("arping -D -c 3 -w 5 -I %s %s", networkProbeInterface, platform.APIVIP))
+	reportNetworkProbeCheck(&script, NetworkProbeIngressVIP, fmt.Sprintf("arping -D -c 3 -w 5 -I %s %s", networkProbeInterface, platform.IngressVIP))
+	return script.String()
+}
+
+// networkProbePod returns the probe pod attached to the network of the platform, running the
+// script. The image is the one of the network probe of the platform, when set.
+func networkProbePod(platform *kubevirt.Platform, script string) *corev1.Pod {
+	image := kubevirt.DefaultNetworkProbeImage
+	if platform.NetworkProbe != nil && platform.NetworkProbe.Image != "" {
+		image = platform.NetworkProbe.Image
+	}
+	deadline := int64(networkProbeTimeout.Seconds())
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("openshift-install-network-probe-%s", utilrand.String(5)),
+			Namespace: platform.Namespace,
+			Labels:    map[string]string{"app": "openshift-install-network-probe"},
+			Annotations: map[string]string{
+				"k8s.v1.cni.cncf.io/networks": fmt.Sprintf("%s@%s", platform.NetworkName, networkProbeInterface),
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &deadline,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   image,
+				Command: []string{"/bin/sh", "-c", script},
+				SecurityContext: &corev1.SecurityContext{
+					Capabilities: &corev1.Capabilities{
+						Add: []corev1.Capability{"NET_ADMIN", "NET_RAW"},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// parseNetworkProbeOutput returns the checks reported by the output of the probe pod.
+func parseNetworkProbeOutput(output string) []NetworkProbeCheck {
+	var checks []NetworkProbeCheck
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != networkProbePrefix {
+			continue
+		}
+		checks = append(checks, NetworkProbeCheck{Name: fields[1], Passed: fields[2] == "passed"})
+	}
+	return checks
+}
+
+// ProbeNetwork runs the network probe of the platform in a short-lived pod attached to its
+// network, and returns the result of its checks. The pod is deleted once it completed.
+func ProbeNetwork(ctx context.Context, client Client, platform *kubevirt.Platform) ([]NetworkProbeCheck, error) {
+	return runNetworkProbe(ctx, client, platform, networkProbeScript(platform.NetworkProbe))
+}
+
+// ProbeVIPs checks from a short-lived pod attached to the network of the platform whether a
+// host of the network already answers ARP for the API or the ingress VIP, and returns the
+// result of the api-vip and ingress-vip checks, which fail when the VIP is in use. The pod is
+// deleted once it completed.
+func ProbeVIPs(ctx context.Context, client Client, platform *kubevirt.Platform) ([]NetworkProbeCheck, error) {
+	return runNetworkProbe(ctx, client, platform, vipsProbeScript(platform))
+}
+
+// runNetworkProbe runs the script in a probe pod attached to the network of the platform, and
+// returns the checks it reported.
+func runNetworkProbe(ctx context.Context, client Client, platform *kubevirt.Platform, script string) ([]NetworkProbeCheck, error) {
+	pod, err := client.CreatePod(ctx, networkProbePod(platform, script))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the network probe pod")
+	}
+	defer func() {
+		if err := client.DeletePod(pod.Namespace, pod.Name, false); err != nil {
+			logrus.Warnf("Failed to delete the network probe pod %s: %v", pod.Name, err)
+		}
+	}()
+
+	logrus.Infof("Probing network %s with pod %s", platform.NetworkName, pod.Name)
+	var phase corev1.PodPhase
+	err = wait.PollImmediate(2*time.Second, networkProbeTimeout, func() (bool, error) {
+		current, err := client.GetPod(ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			return false, err
+		}
+		phase = current.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "the network probe pod %s did not complete, in phase %q", pod.Name, phase)
+	}
+
+	logs, err := client.GetPodLogs(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the logs of the network probe pod")
+	}
+	checks := parseNetworkProbeOutput(logs)
+	if len(checks) == 0 {
+		return nil, errors.Errorf("the network probe pod %s reported no checks, in phase %q", pod.Name, phase)
+	}
+	return checks, nil
+}
+
+// FailedNetworkProbeChecks returns the names of the checks which failed.
+func FailedNetworkProbeChecks(checks []NetworkProbeCheck) []string {
+	var failed []string
+	for _, check := range checks {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
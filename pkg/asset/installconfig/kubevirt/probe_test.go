@@ -0,0 +1,124 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestNetworkProbePod(t *testing.T) {
+	platform := &kubevirt.Platform{
+		Namespace:    validNamespace,
+		NetworkName:  validNetworkName,
+		NetworkProbe: &kubevirt.NetworkProbe{Image: "busybox", Gateway: "10.0.0.1"},
+	}
+	pod := networkProbePod(platform, networkProbeScript(platform.NetworkProbe))
+	assert.Equal(t, validNamespace, pod.Namespace)
+	assert.Equal(t, validNetworkName+"@net1", pod.Annotations["k8s.v1.cni.cncf.io/networks"])
+	if assert.Len(t, pod.Spec.Containers, 1) {
+		assert.Equal(t, "busybox", pod.Spec.Containers[0].Image)
+		script := pod.Spec.Containers[0].Command[2]
+		assert.Contains(t, script, "arping -c 3 -w 5 -I net1 10.0.0.1")
+		assert.NotContains(t, script, "udhcpc")
+		assert.NotContains(t, script, "nslookup")
+	}
+}
+
+func TestVIPsProbePod(t *testing.T) {
+	platform := &kubevirt.Platform{
+		Namespace:   validNamespace,
+		NetworkName: validNetworkName,
+		APIVIP:      "10.0.0.5",
+		IngressVIP:  "10.0.0.6",
+	}
+	pod := networkProbePod(platform, vipsProbeScript(platform))
+	assert.Equal(t, validNetworkName+"@net1", pod.Annotations["k8s.v1.cni.cncf.io/networks"])
+	if assert.Len(t, pod.Spec.Containers, 1) {
+		assert.Equal(t, kubevirt.DefaultNetworkProbeImage, pod.Spec.Containers[0].Image)
+		script := pod.Spec.Containers[0].Command[2]
+		assert.Contains(t, script, "arping -D -c 3 -w 5 -I net1 10.0.0.5 >/dev/null 2>&1; then echo probe api-vip passed")
+		assert.Contains(t, script, "arping -D -c 3 -w 5 -I net1 10.0.0.6 >/dev/null 2>&1; then echo probe ingress-vip passed")
+	}
+}
+
+func TestParseNetworkProbeOutput(t *testing.T) {
+	output := "udhcpc: started\nprobe dhcp passed\nprobe gateway failed\nprobe dns passed\n"
+	checks := parseNetworkProbeOutput(output)
+	assert.Equal(t, []NetworkProbeCheck{
+		{Name: NetworkProbeDHCP, Passed: true},
+		{Name: NetworkProbeGateway},
+		{Name: NetworkProbeDNS, Passed: true},
+	}, checks)
+	assert.Equal(t, []string{NetworkProbeGateway}, FailedNetworkProbeChecks(checks))
+}
+
+func TestProbeNetwork(t *testing.T) {
+	platform := &kubevirt.Platform{
+		Namespace:    validNamespace,
+		NetworkName:  validNetworkName,
+		NetworkProbe: &kubevirt.NetworkProbe{Image: "busybox", DHCP: true},
+	}
+	cases := []struct {
+		name          string
+		phase         corev1.PodPhase
+		logs          string
+		expected      []NetworkProbeCheck
+		expectedError string
+	}{
+		{
+			name:     "passed",
+			phase:    corev1.PodSucceeded,
+			logs:     "probe dhcp passed\n",
+			expected: []NetworkProbeCheck{{Name: NetworkProbeDHCP, Passed: true}},
+		},
+		{
+			name:     "failed",
+			phase:    corev1.PodSucceeded,
+			logs:     "probe dhcp failed\n",
+			expected: []NetworkProbeCheck{{Name: NetworkProbeDHCP}},
+		},
+		{
+			name:          "no checks reported",
+			phase:         corev1.PodFailed,
+			logs:          "exec format error\n",
+			expectedError: `reported no checks, in phase "Failed"$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			kubevirtClient := mock.NewMockClient(mockCtrl)
+			kubevirtClient.EXPECT().CreatePod(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+				return pod, nil
+			})
+			kubevirtClient.EXPECT().GetPod(gomock.Any(), validNamespace, gomock.Any()).Return(&corev1.Pod{Status: corev1.PodStatus{Phase: tc.phase}}, nil)
+			kubevirtClient.EXPECT().GetPodLogs(gomock.Any(), validNamespace, gomock.Any()).Return(tc.logs, nil)
+			kubevirtClient.EXPECT().DeletePod(validNamespace, gomock.Any(), false).Return(nil)
+
+			checks, err := ProbeNetwork(context.Background(), kubevirtClient, platform)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, checks)
+		})
+	}
+}
+
+// expectNetworkProbe expects a probe pod to be created, to complete and to print the logs.
+func expectNetworkProbe(kubevirtClient *mock.MockClient, logs string) {
+	kubevirtClient.EXPECT().CreatePod(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+		return pod, nil
+	})
+	kubevirtClient.EXPECT().GetPod(gomock.Any(), validNamespace, gomock.Any()).Return(&corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}, nil)
+	kubevirtClient.EXPECT().GetPodLogs(gomock.Any(), validNamespace, gomock.Any()).Return(logs, nil)
+	kubevirtClient.EXPECT().DeletePod(validNamespace, gomock.Any(), false).Return(nil)
+}
@@ -0,0 +1,187 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// resourceVirtualMachines is the object count quota of the KubeVirt VMs.
+const resourceVirtualMachines corev1.ResourceName = "count/virtualmachines.kubevirt.io"
+
+// vmQuotaUsage returns the resources the resource quotas charge for a VM of the machine pool: its
+// virt-launcher pod, requesting the CPU and memory of the VM, and its persistent volume claims.
+func vmQuotaUsage(pool *kubevirt.MachinePool) (corev1.ResourceList, error) {
+	memory, err := resource.ParseQuantity(pool.VMMemory())
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory %q: %v", pool.VMMemory(), err)
+	}
+	storage, err := resource.ParseQuantity(pool.StorageSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage size %q: %v", pool.StorageSize, err)
+	}
+	claims := int64(1)
+	if pool.EtcdDisk != nil {
+		etcd, err := resource.ParseQuantity(pool.EtcdDisk.Size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid etcd disk size %q: %v", pool.EtcdDisk.Size, err)
+		}
+		storage.Add(etcd)
+		claims++
+	}
+	cpu := *resource.NewQuantity(int64(pool.CPU), resource.DecimalSI)
+	return corev1.ResourceList{
+		corev1.ResourceCPU:                    cpu,
+		corev1.ResourceRequestsCPU:            cpu,
+		corev1.ResourceMemory:                 memory,
+		corev1.ResourceRequestsMemory:         memory,
+		corev1.ResourceRequestsStorage:        storage,
+		corev1.ResourcePersistentVolumeClaims: *resource.NewQuantity(claims, resource.DecimalSI),
+		corev1.ResourcePods:                   *resource.NewQuantity(1, resource.DecimalSI),
+		resourceVirtualMachines:               *resource.NewQuantity(1, resource.DecimalSI),
+	}, nil
+}
+
+// quotaRemaining returns the resources left by the resource quotas of a namespace, the lowest of
+// the quotas limiting a resource. The quotas with scopes, which may not apply to the VMs, are
+// ignored.
+func quotaRemaining(quotas []corev1.ResourceQuota) corev1.ResourceList {
+	remaining := corev1.ResourceList{}
+	for _, quota := range quotas {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		hard := quota.Status.Hard
+		if hard == nil {
+			hard = quota.Spec.Hard
+		}
+		for name, limit := range hard {
+			left := limit.DeepCopy()
+			if used, ok := quota.Status.Used[name]; ok {
+				left.Sub(used)
+			}
+			if current, ok := remaining[name]; !ok || left.Cmp(current) < 0 {
+				remaining[name] = left
+			}
+		}
+	}
+	return remaining
+}
+
+// maxQuotaReplicas returns the most VMs using the resources the remaining quotas allow, and the
+// resource limiting them. It returns -1 when no quota limits the VMs.
+func maxQuotaReplicas(remaining corev1.ResourceList, usage corev1.ResourceList) (int64, corev1.ResourceName) {
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	max := int64(-1)
+	var limitedBy corev1.ResourceName
+	for _, name := range names {
+		need := usage[corev1.ResourceName(name)]
+		left, ok := remaining[corev1.ResourceName(name)]
+		if !ok || need.IsZero() {
+			continue
+		}
+		replicas := int64(0)
+		if left.Sign() > 0 {
+			replicas = left.MilliValue() / need.MilliValue()
+		}
+		if max < 0 || replicas < max {
+			max = replicas
+			limitedBy = corev1.ResourceName(name)
+		}
+	}
+	return max, limitedBy
+}
+
+// chargeQuota subtracts the resources of the VMs from the remaining quotas.
+func chargeQuota(remaining corev1.ResourceList, usage corev1.ResourceList, replicas int64) {
+	for name, need := range usage {
+		left, ok := remaining[name]
+		if !ok {
+			continue
+		}
+		for i := int64(0); i < replicas; i++ {
+			left.Sub(need)
+		}
+		remaining[name] = left
+	}
+}
+
+// validateMachinePoolQuotas checks the VMs of the machine pools against the resource quotas of
+// their infra cluster namespaces. The bootstrap and control plane VMs must fit the quotas. The
+// compute pools requesting more replicas than the quotas left after them allow only get a
+// warning, recommending the most replicas the quotas allow, as they can be scaled up once the
+// quotas are raised.
+func validateMachinePoolQuotas(ctx context.Context, ic *types.InstallConfig, pools []machinePoolWithPath, client Client) (field.ErrorList, []string) {
+	allErrs := field.ErrorList{}
+	var warnings []string
+
+	remainingByNamespace := map[string]corev1.ResourceList{}
+	remainingIn := func(namespace string) corev1.ResourceList {
+		if remaining, ok := remainingByNamespace[namespace]; ok {
+			return remaining
+		}
+		quotas, err := client.ListResourceQuotas(ctx, namespace)
+		if err != nil {
+			logrus.Debugf("Skipping the resource quotas of namespace %s: %v", namespace, err)
+		}
+		remaining := quotaRemaining(quotas)
+		remainingByNamespace[namespace] = remaining
+		return remaining
+	}
+
+	bootstrapVM := ic.Platform.Kubevirt.BootstrapVM()
+	if usage, err := vmQuotaUsage(&bootstrapVM); err == nil {
+		remaining := remainingIn(ic.Platform.Kubevirt.Namespace)
+		if max, limitedBy := maxQuotaReplicas(remaining, usage); max == 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("platform", "kubevirt", "namespace"), ic.Platform.Kubevirt.Namespace, fmt.Sprintf("the resource quotas of the namespace leave no room for the bootstrap VM, limited by %s", limitedBy)))
+		}
+		chargeQuota(remaining, usage, 1)
+	}
+
+	for _, p := range pools {
+		mpool := p.pool.Platform.Kubevirt
+		if mpool.HasInfraCluster() {
+			continue
+		}
+		usage, err := vmQuotaUsage(mpool)
+		if err != nil {
+			// The machine pool is validated separately
+			continue
+		}
+		namespace := ic.Platform.Kubevirt.Namespace
+		remaining := remainingIn(namespace)
+		requested := replicas(p.pool)
+		max, limitedBy := maxQuotaReplicas(remaining, usage)
+		if max < 0 || requested <= max {
+			chargeQuota(remaining, usage, requested)
+			continue
+		}
+		chargeQuota(remaining, usage, max)
+
+		if p.pool == ic.ControlPlane {
+			detailedErr := fmt.Errorf("the resource quotas of namespace %s allow %d of the %d control plane VMs, limited by %s", namespace, max, requested, limitedBy)
+			allErrs = append(allErrs, field.Invalid(field.NewPath("controlPlane", "replicas"), requested, detailedErr.Error()))
+			continue
+		}
+		if max == 0 {
+			warnings = append(warnings, fmt.Sprintf("Compute pool %s requests %d replicas but the resource quotas of namespace %s leave no room for its VMs, limited by %s; set its replicas to 0 or raise the quotas", p.pool.Name, requested, namespace, limitedBy))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Compute pool %s requests %d replicas but the resource quotas of namespace %s allow at most %d, limited by %s; set its replicas to %d or raise the quotas", p.pool.Name, requested, namespace, max, limitedBy, max))
+	}
+
+	return allErrs, warnings
+}
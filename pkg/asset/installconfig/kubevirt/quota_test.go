@@ -0,0 +1,131 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func resourceQuota(hard corev1.ResourceList, used corev1.ResourceList) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		Spec:   corev1.ResourceQuotaSpec{Hard: hard},
+		Status: corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestValidateMachinePoolQuotas(t *testing.T) {
+	cases := []struct {
+		name             string
+		quotas           []corev1.ResourceQuota
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		{
+			name: "no quotas",
+		},
+		{
+			name: "enough quota",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("40")}, nil),
+			},
+		},
+		{
+			name: "compute replicas limited by cpu",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("32")}, nil),
+			},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace allow at most 1, limited by requests.cpu; set its replicas to 1 or raise the quotas"},
+		},
+		{
+			name: "compute replicas limited by memory",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("60Gi")}, nil),
+			},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace leave no room for its VMs, limited by requests.memory; set its replicas to 0 or raise the quotas"},
+		},
+		{
+			name: "compute replicas limited by used pods",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}),
+			},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace allow at most 1, limited by pods; set its replicas to 1 or raise the quotas"},
+		},
+		{
+			name: "lowest quota",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("10Ti")}, nil),
+				resourceQuota(corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("600Gi")}, nil),
+			},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace allow at most 1, limited by requests.storage; set its replicas to 1 or raise the quotas"},
+		},
+		{
+			name: "scoped quota ignored",
+			quotas: []corev1.ResourceQuota{{
+				Spec:   corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}, Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+				Status: corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}},
+			}},
+		},
+		{
+			name: "control plane replicas limited by cpu",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20")}, nil),
+			},
+			expectedErrors:   []string{"controlPlane.replicas: Invalid value: 3: the resource quotas of namespace valid-namespace allow 2 of the 3 control plane VMs, limited by cpu"},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace leave no room for its VMs, limited by cpu; set its replicas to 0 or raise the quotas"},
+		},
+		{
+			name: "no room for the bootstrap VM",
+			quotas: []corev1.ResourceQuota{
+				resourceQuota(corev1.ResourceList{resourceVirtualMachines: resource.MustParse("2")}, corev1.ResourceList{resourceVirtualMachines: resource.MustParse("2")}),
+			},
+			expectedErrors: []string{
+				"platform.kubevirt.namespace: Invalid value: \"valid-namespace\": the resource quotas of the namespace leave no room for the bootstrap VM, limited by count/virtualmachines.kubevirt.io",
+				"controlPlane.replicas: Invalid value: 3: the resource quotas of namespace valid-namespace allow 0 of the 3 control plane VMs, limited by count/virtualmachines.kubevirt.io",
+			},
+			expectedWarnings: []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace leave no room for its VMs, limited by count/virtualmachines.kubevirt.io; set its replicas to 0 or raise the quotas"},
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := reportInstallConfig()
+			ic.Platform.Kubevirt.Namespace = validNamespace
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().ListResourceQuotas(gomock.Any(), validNamespace).Return(tc.quotas, nil)
+
+			errs, warnings := validateMachinePoolQuotas(context.TODO(), ic, kubevirtMachinePools(ic), client)
+			var errMsgs []string
+			for _, err := range errs {
+				errMsgs = append(errMsgs, err.Error())
+			}
+			assert.Equal(t, tc.expectedErrors, errMsgs)
+			assert.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestValidateMachinePoolQuotasBootstrapSize(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ic := reportInstallConfig()
+	ic.Platform.Kubevirt.Namespace = validNamespace
+	ic.Platform.Kubevirt.Bootstrap = &kubevirt.Bootstrap{CPU: 2}
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListResourceQuotas(gomock.Any(), validNamespace).Return([]corev1.ResourceQuota{
+		resourceQuota(corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("26")}, nil),
+	}, nil)
+
+	errs, warnings := validateMachinePoolQuotas(context.TODO(), ic, kubevirtMachinePools(ic), client)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"Compute pool worker requests 2 replicas but the resource quotas of namespace valid-namespace leave no room for its VMs, limited by requests.cpu; set its replicas to 0 or raise the quotas"}, warnings)
+}
@@ -0,0 +1,142 @@
+package kubevirt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ErrReadOnly is the cause of the errors returned by the mutations of the read-only clients.
+var ErrReadOnly = errors.New("the infra cluster is read-only in a dry run")
+
+var (
+	readOnly     bool
+	readOnlyLock sync.Mutex
+)
+
+// SetReadOnly makes the clients returned by NewClient refuse every mutation of the infra
+// cluster, e.g. for create cluster --dry-run. The service account tokens are still
+// requested, they are not stored in the infra cluster.
+func SetReadOnly(ro bool) {
+	readOnlyLock.Lock()
+	defer readOnlyLock.Unlock()
+	readOnly = ro
+}
+
+// ReadOnly returns whether the infra cluster is read-only, see SetReadOnly.
+func ReadOnly() bool {
+	readOnlyLock.Lock()
+	defer readOnlyLock.Unlock()
+	return readOnly
+}
+
+// readOnlyClient refuses the mutations done through the wrapped Client.
+type readOnlyClient struct {
+	Client
+}
+
+// newReadOnlyClient returns a Client refusing every mutation done through client.
+func newReadOnlyClient(client Client) Client {
+	return &readOnlyClient{Client: client}
+}
+
+func (c *readOnlyClient) DeleteVirtualMachine(namespace string, name string, wait bool) error {
+	return refuse("delete", "virtualmachines", namespace, name)
+}
+
+func (c *readOnlyClient) CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error {
+	return refuse("create", "datavolumes", dv.GetNamespace(), dv.GetName())
+}
+
+func (c *readOnlyClient) DeleteDataVolume(namespace string, name string, wait bool) error {
+	return refuse("delete", "datavolumes", namespace, name)
+}
+
+func (c *readOnlyClient) DeleteSecret(namespace string, name string, wait bool) error {
+	return refuse("delete", "secrets", namespace, name)
+}
+
+func (c *readOnlyClient) CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error {
+	return refuse("create", "network-attachment-definitions", nad.GetNamespace(), nad.GetName())
+}
+
+func (c *readOnlyClient) DeleteNetworkAttachmentDefinition(namespace string, name string, wait bool) error {
+	return refuse("delete", "network-attachment-definitions", namespace, name)
+}
+
+func (c *readOnlyClient) CreateOrUpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	return refuse("apply", "configmaps", configMap.Namespace, configMap.Name)
+}
+
+func (c *readOnlyClient) AnnotateConfigMap(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return refuse("patch", "configmaps", namespace, name)
+}
+
+func (c *readOnlyClient) DeleteConfigMap(namespace string, name string, wait bool) error {
+	return refuse("delete", "configmaps", namespace, name)
+}
+
+func (c *readOnlyClient) CreateEvent(ctx context.Context, event *corev1.Event) error {
+	return refuse("create", "events", event.Namespace, event.Name)
+}
+
+func (c *readOnlyClient) CreatePod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	return nil, refuse("create", "pods", pod.Namespace, pod.Name)
+}
+
+func (c *readOnlyClient) DeletePod(namespace string, name string, wait bool) error {
+	return refuse("delete", "pods", namespace, name)
+}
+
+func (c *readOnlyClient) StopVirtualMachine(namespace string, name string, gracePeriod time.Duration) error {
+	return refuse("stop", "virtualmachines", namespace, name)
+}
+
+func (c *readOnlyClient) StartVirtualMachine(namespace string, name string) error {
+	return refuse("start", "virtualmachines", namespace, name)
+}
+
+func (c *readOnlyClient) AddResourceLabels(ctx context.Context, namespace string, resource string, name string, labels map[string]string) error {
+	return refuse("patch", resource, namespace, name)
+}
+
+func (c *readOnlyClient) SetVirtualMachineRunStrategy(namespace string, name string, runStrategy string) error {
+	return refuse("patch", "virtualmachines", namespace, name)
+}
+
+func (c *readOnlyClient) AnnotateVirtualMachine(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return refuse("patch", "virtualmachines", namespace, name)
+}
+
+func (c *readOnlyClient) DeleteMultiNetworkPolicy(namespace string, name string, wait bool) error {
+	return refuse("delete", "multi-networkpolicies", namespace, name)
+}
+
+func (c *readOnlyClient) DeletePodDisruptionBudget(namespace string, name string, wait bool) error {
+	return refuse("delete", "poddisruptionbudgets", namespace, name)
+}
+
+func (c *readOnlyClient) ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error {
+	return refuse("apply", resource, obj.GetNamespace(), obj.GetName())
+}
+
+func (c *readOnlyClient) DeleteDeployment(namespace string, name string, wait bool) error {
+	return refuse("delete", "deployments", namespace, name)
+}
+
+func (c *readOnlyClient) DeleteService(namespace string, name string, wait bool) error {
+	return refuse("delete", "services", namespace, name)
+}
+
+func (c *readOnlyClient) DeleteEndpoints(namespace string, name string, wait bool) error {
+	return refuse("delete", "endpoints", namespace, name)
+}
+
+// refuse returns the error of the mutation refused by the read-only client.
+func refuse(verb string, resource string, namespace string, name string) error {
+	return errors.Wrapf(ErrReadOnly, "refusing to %s %s %s/%s", verb, resource, namespace, name)
+}
@@ -0,0 +1,39 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	kubevirtClient := mock.NewMockClient(mockCtrl)
+	kubevirtClient.EXPECT().ListSecretNames("ns", nil).Return([]string{"secret"}, nil)
+	kubevirtClient.EXPECT().CreateServiceAccountToken(gomock.Any(), "ns", "sa", time.Minute).Return("token", nil)
+
+	client := newReadOnlyClient(kubevirtClient)
+	err := client.DeleteVirtualMachine("ns", "vm", true)
+	assert.EqualError(t, err, "refusing to delete virtualmachines ns/vm: the infra cluster is read-only in a dry run")
+	assert.Equal(t, ErrReadOnly, errors.Cause(err))
+	pod, err := client.CreatePod(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "probe"}})
+	assert.Nil(t, pod)
+	assert.EqualError(t, err, "refusing to create pods ns/probe: the infra cluster is read-only in a dry run")
+
+	// Reads and token requests go through
+	names, err := client.ListSecretNames("ns", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"secret"}, names)
+	token, err := client.CreateServiceAccountToken(context.Background(), "ns", "sa", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
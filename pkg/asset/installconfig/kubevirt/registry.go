@@ -0,0 +1,72 @@
+package kubevirt
+
+import (
+	"sync"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// newInfraClusterClient creates the clients of the infra clusters of the machine pools with their
+// own infra cluster.
+var newInfraClusterClient = NewClientForInfraCluster
+
+// ClientRegistry holds the clients of the infra clusters of the machine pools, keyed by the name
+// of the pool. The pools placed in the same infra cluster share its client, which is created on
+// first use.
+type ClientRegistry struct {
+	platform          *kubevirt.Platform
+	pools             map[string]*kubevirt.MachinePool
+	clientBuilderFunc ClientBuilderFuncType
+
+	mutex   sync.Mutex
+	clients map[kubevirt.InfraCluster]Client
+}
+
+// NewClientRegistry returns the registry of the clients of the machine pools of the install config.
+// The pools placed in the platform infra cluster use the client of clientBuilderFunc.
+func NewClientRegistry(ic *types.InstallConfig, clientBuilderFunc ClientBuilderFuncType) *ClientRegistry {
+	registry := &ClientRegistry{
+		platform:          ic.Platform.Kubevirt,
+		pools:             map[string]*kubevirt.MachinePool{},
+		clientBuilderFunc: clientBuilderFunc,
+		clients:           map[kubevirt.InfraCluster]Client{},
+	}
+	if ic.ControlPlane != nil {
+		registry.pools[ic.ControlPlane.Name] = ic.ControlPlane.Platform.Kubevirt
+	}
+	for i := range ic.Compute {
+		registry.pools[ic.Compute[i].Name] = ic.Compute[i].Platform.Kubevirt
+	}
+	return registry
+}
+
+// InfraCluster returns the infra cluster of the machine pool with the given name, the platform one
+// for an unknown pool.
+func (r *ClientRegistry) InfraCluster(pool string) kubevirt.InfraCluster {
+	return r.platform.MachinePoolInfraCluster(r.pools[pool])
+}
+
+// ForPool returns the client of the infra cluster of the machine pool with the given name, the
+// platform one for an unknown pool.
+func (r *ClientRegistry) ForPool(pool string) (Client, error) {
+	endpoint := r.InfraCluster(pool).Endpoint()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if client, ok := r.clients[endpoint]; ok {
+		return client, nil
+	}
+	var client Client
+	var err error
+	if r.pools[pool].HasInfraCluster() {
+		client, err = newInfraClusterClient(endpoint)
+	} else {
+		client, err = r.clientBuilderFunc()
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.clients[endpoint] = client
+	return client, nil
+}
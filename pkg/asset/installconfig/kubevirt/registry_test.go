@@ -0,0 +1,69 @@
+package kubevirt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestClientRegistry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	platformClient := mock.NewMockClient(mockCtrl)
+	poolClient := mock.NewMockClient(mockCtrl)
+
+	var created []kubevirt.InfraCluster
+	defer func(original func(kubevirt.InfraCluster) (Client, error)) { newInfraClusterClient = original }(newInfraClusterClient)
+	newInfraClusterClient = func(infraCluster kubevirt.InfraCluster) (Client, error) {
+		created = append(created, infraCluster)
+		if infraCluster.Context == "unreachable" {
+			return nil, errors.New("unreachable")
+		}
+		return poolClient, nil
+	}
+	platformBuilds := 0
+	clientBuilderFunc := func() (Client, error) {
+		platformBuilds++
+		return platformClient, nil
+	}
+
+	ic := &types.InstallConfig{
+		Platform: types.Platform{Kubevirt: &kubevirt.Platform{Namespace: validNamespace}},
+		ControlPlane: &types.MachinePool{
+			Name:     "master",
+			Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{}},
+		},
+		Compute: []types.MachinePool{
+			{Name: "worker", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{}}},
+			{Name: "worker-b", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{InfraContext: "infra-b"}}},
+			{Name: "worker-b2", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{InfraContext: "infra-b"}}},
+			{Name: "worker-c", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{InfraContext: "unreachable"}}},
+		},
+	}
+	registry := NewClientRegistry(ic, clientBuilderFunc)
+
+	for _, pool := range []string{"master", "worker", "unknown"} {
+		client, err := registry.ForPool(pool)
+		assert.NoError(t, err)
+		assert.Equal(t, platformClient, client, pool)
+	}
+	assert.Equal(t, 1, platformBuilds)
+	assert.Equal(t, kubevirt.InfraCluster{Namespace: validNamespace}, registry.InfraCluster("master"))
+
+	for _, pool := range []string{"worker-b", "worker-b2"} {
+		client, err := registry.ForPool(pool)
+		assert.NoError(t, err)
+		assert.Equal(t, poolClient, client, pool)
+	}
+	assert.Equal(t, kubevirt.InfraCluster{Context: "infra-b", Namespace: validNamespace}, registry.InfraCluster("worker-b"))
+
+	_, err := registry.ForPool("worker-c")
+	assert.EqualError(t, err, "unreachable")
+	assert.Equal(t, []kubevirt.InfraCluster{{Context: "infra-b"}, {Context: "unreachable"}}, created)
+}
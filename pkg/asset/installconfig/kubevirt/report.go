@@ -0,0 +1,288 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// NodeCapacity is the capacity of an infra cluster node and the share of it used by the VMs
+// already running on the node.
+type NodeCapacity struct {
+	Name string
+	// Schedulable is false for the nodes which are cordoned or tainted with NoSchedule or
+	// NoExecute, which the VMs of the cluster are not placed on.
+	Schedulable       bool
+	AllocatableCPU    resource.Quantity
+	AllocatableMemory resource.Quantity
+	// VMs is the number of VMs running on the node.
+	VMs             int
+	RequestedCPU    resource.Quantity
+	RequestedMemory resource.Quantity
+}
+
+// StorageClassCapacity is the storage the cluster requires from a storage class of the infra
+// cluster, and the capacity the CSI driver of the storage class publishes.
+type StorageClassCapacity struct {
+	Name     string
+	Required resource.Quantity
+	// Capacity is nil when the CSI driver publishes no capacity for the storage class.
+	Capacity *resource.Quantity
+}
+
+// CapacityReport is the capacity of the infra cluster for the cluster of an install-config.
+type CapacityReport struct {
+	Nodes          []NodeCapacity
+	StorageClasses []StorageClassCapacity
+	// Problems are the reasons the cluster doesn't fit the infra cluster.
+	Problems []string
+}
+
+// Fits returns true if the cluster fits the infra cluster.
+func (r *CapacityReport) Fits() bool {
+	return len(r.Problems) == 0
+}
+
+// plannedVM is a VM of the cluster to place on the infra cluster nodes.
+type plannedVM struct {
+	name   string
+	cpu    resource.Quantity
+	memory resource.Quantity
+	// spread is set for the VMs which must run on different nodes.
+	spread bool
+}
+
+// ReportCapacity reports the capacity of the infra cluster nodes and storage classes, and
+// whether the bootstrap, control plane and compute VMs of the install-config fit them.
+// The VMs are placed on the nodes with the most free memory first, the free resources of a
+// node being its allocatable resources minus the requests of the VMs running on it.
+func ReportCapacity(ctx context.Context, ic *types.InstallConfig, client Client) (*CapacityReport, error) {
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the nodes: %v", err)
+	}
+	vmis, err := client.ListVirtualMachineInstances(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the VMIs: %v", err)
+	}
+	capacities, err := client.ListCSIStorageCapacities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the CSI storage capacities: %v", err)
+	}
+
+	report := &CapacityReport{}
+	byName := map[string]*NodeCapacity{}
+	for _, node := range nodes {
+		report.Nodes = append(report.Nodes, NodeCapacity{
+			Name:              node.Name,
+			Schedulable:       nodeSchedulable(&node),
+			AllocatableCPU:    node.Status.Allocatable[corev1.ResourceCPU],
+			AllocatableMemory: node.Status.Allocatable[corev1.ResourceMemory],
+		})
+	}
+	for i := range report.Nodes {
+		byName[report.Nodes[i].Name] = &report.Nodes[i]
+	}
+	for _, vmi := range vmis {
+		nodeName, _, _ := unstructured.NestedString(vmi.Object, "status", "nodeName")
+		node, ok := byName[nodeName]
+		if !ok {
+			continue
+		}
+		cpu, memory := vmiRequests(&vmi)
+		node.VMs++
+		node.RequestedCPU.Add(cpu)
+		node.RequestedMemory.Add(memory)
+	}
+
+	vms, storage, err := plannedResources(ic)
+	if err != nil {
+		return nil, err
+	}
+	report.Problems = append(report.Problems, placeVMs(report.Nodes, vms)...)
+
+	storageClasses := make([]string, 0, len(storage))
+	for name := range storage {
+		storageClasses = append(storageClasses, name)
+	}
+	sort.Strings(storageClasses)
+	for _, name := range storageClasses {
+		storageClass := StorageClassCapacity{Name: name, Required: storage[name]}
+		for _, capacity := range capacities {
+			if capacity.StorageClassName != name || capacity.Capacity == nil {
+				continue
+			}
+			if storageClass.Capacity == nil {
+				storageClass.Capacity = &resource.Quantity{}
+			}
+			storageClass.Capacity.Add(*capacity.Capacity)
+		}
+		if storageClass.Capacity != nil && storageClass.Capacity.Cmp(storageClass.Required) < 0 {
+			report.Problems = append(report.Problems, fmt.Sprintf("storage class %s has a capacity of %s, less than the required %s", name, storageClass.Capacity.String(), storageClass.Required.String()))
+		}
+		report.StorageClasses = append(report.StorageClasses, storageClass)
+	}
+	return report, nil
+}
+
+// nodeSchedulable returns true unless the node is cordoned or tainted against scheduling.
+func nodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	return true
+}
+
+// vmiRequests returns the CPU and memory requested by the VMI, falling back to its cores and
+// guest memory when it sets no requests.
+func vmiRequests(vmi *unstructured.Unstructured) (resource.Quantity, resource.Quantity) {
+	quantity := func(fields ...string) resource.Quantity {
+		value, found, err := unstructured.NestedFieldNoCopy(vmi.Object, fields...)
+		if err != nil || !found {
+			return resource.Quantity{}
+		}
+		q, err := resource.ParseQuantity(fmt.Sprint(value))
+		if err != nil {
+			return resource.Quantity{}
+		}
+		return q
+	}
+	cpu := quantity("spec", "domain", "resources", "requests", "cpu")
+	if cpu.IsZero() {
+		cpu = quantity("spec", "domain", "cpu", "cores")
+	}
+	memory := quantity("spec", "domain", "resources", "requests", "memory")
+	if memory.IsZero() {
+		memory = quantity("spec", "domain", "memory", "guest")
+	}
+	return cpu, memory
+}
+
+// plannedResources returns the VMs of the cluster and the storage they require by storage class.
+func plannedResources(ic *types.InstallConfig) ([]plannedVM, map[string]resource.Quantity, error) {
+	var vms []plannedVM
+	storage := map[string]resource.Quantity{}
+	addStorage := func(storageClass string, size string, count int64) error {
+		q, err := resource.ParseQuantity(size)
+		if err != nil {
+			return fmt.Errorf("invalid storage size %q: %v", size, err)
+		}
+		total := storage[storageClass]
+		for i := int64(0); i < count; i++ {
+			total.Add(q)
+		}
+		storage[storageClass] = total
+		return nil
+	}
+	addPool := func(name string, pool *kubevirt.MachinePool, replicas int64, spread bool) error {
+		memory, err := resource.ParseQuantity(pool.Memory)
+		if err != nil {
+			return fmt.Errorf("invalid memory %q of the %s machine pool: %v", pool.Memory, name, err)
+		}
+		for i := int64(0); i < replicas; i++ {
+			vms = append(vms, plannedVM{
+				name:   fmt.Sprintf("%s-%d", name, i),
+				cpu:    *resource.NewQuantity(int64(pool.CPU), resource.DecimalSI),
+				memory: memory,
+				spread: spread,
+			})
+		}
+		if err := addStorage(ic.Kubevirt.StorageClass, pool.StorageSize, replicas); err != nil {
+			return fmt.Errorf("invalid %s machine pool: %v", name, err)
+		}
+		if pool.EtcdDisk != nil {
+			storageClass := pool.EtcdDisk.StorageClass
+			if storageClass == "" {
+				storageClass = ic.Kubevirt.StorageClass
+			}
+			if err := addStorage(storageClass, pool.EtcdDisk.Size, replicas); err != nil {
+				return fmt.Errorf("invalid etcd disk of the %s machine pool: %v", name, err)
+			}
+		}
+		return nil
+	}
+
+	bootstrapVM := ic.Kubevirt.BootstrapVM()
+	if err := addPool("bootstrap", &bootstrapVM, 1, false); err != nil {
+		return nil, nil, err
+	}
+	if pool := ic.ControlPlane; pool != nil && pool.Platform.Kubevirt != nil {
+		if err := addPool(pool.Name, pool.Platform.Kubevirt, replicas(pool), pool.Platform.Kubevirt.SpreadPolicy == kubevirt.SpreadPolicySpread); err != nil {
+			return nil, nil, err
+		}
+	}
+	for i := range ic.Compute {
+		pool := &ic.Compute[i]
+		if pool.Platform.Kubevirt == nil {
+			continue
+		}
+		if err := addPool(pool.Name, pool.Platform.Kubevirt, replicas(pool), false); err != nil {
+			return nil, nil, err
+		}
+	}
+	return vms, storage, nil
+}
+
+func replicas(pool *types.MachinePool) int64 {
+	if pool.Replicas == nil {
+		return 0
+	}
+	return *pool.Replicas
+}
+
+// placeVMs places the VMs on the schedulable nodes with the most free memory, and returns the
+// VMs which fit no node.
+func placeVMs(nodes []NodeCapacity, vms []plannedVM) []string {
+	type free struct {
+		name      string
+		cpu       resource.Quantity
+		memory    resource.Quantity
+		spreadVMs int
+	}
+	var frees []*free
+	for _, node := range nodes {
+		if !node.Schedulable {
+			continue
+		}
+		f := &free{name: node.Name, cpu: node.AllocatableCPU.DeepCopy(), memory: node.AllocatableMemory.DeepCopy()}
+		f.cpu.Sub(node.RequestedCPU)
+		f.memory.Sub(node.RequestedMemory)
+		frees = append(frees, f)
+	}
+
+	var problems []string
+	for _, vm := range vms {
+		sort.SliceStable(frees, func(i, j int) bool {
+			return frees[i].memory.Cmp(frees[j].memory) > 0
+		})
+		placed := false
+		for _, f := range frees {
+			if f.cpu.Cmp(vm.cpu) < 0 || f.memory.Cmp(vm.memory) < 0 || (vm.spread && f.spreadVMs > 0) {
+				continue
+			}
+			f.cpu.Sub(vm.cpu)
+			f.memory.Sub(vm.memory)
+			if vm.spread {
+				f.spreadVMs++
+			}
+			placed = true
+			break
+		}
+		if !placed {
+			problems = append(problems, fmt.Sprintf("no schedulable node has %s CPUs and %s of memory free for VM %s", vm.cpu.String(), vm.memory.String(), vm.name))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,140 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1alpha1 "k8s.io/api/storage/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func reportInstallConfig() *types.InstallConfig {
+	masters, workers := int64(3), int64(2)
+	return &types.InstallConfig{
+		Platform: types.Platform{Kubevirt: &kubevirt.Platform{StorageClass: validStorageClass}},
+		ControlPlane: &types.MachinePool{
+			Name:     "master",
+			Replicas: &masters,
+			Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{CPU: 8, Memory: "16Gi", StorageSize: "120Gi", SpreadPolicy: kubevirt.SpreadPolicySpread}},
+		},
+		Compute: []types.MachinePool{{
+			Name:     "worker",
+			Replicas: &workers,
+			Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{CPU: 4, Memory: "8Gi", StorageSize: "120Gi"}},
+		}},
+	}
+}
+
+func reportNode(name string, cpu string, memory string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}},
+	}
+}
+
+func reportVMI(node string, cpu string, memory string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"domain": map[string]interface{}{
+				"cpu":       map[string]interface{}{"cores": int64(4)},
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": cpu, "memory": memory}},
+			},
+		},
+		"status": map[string]interface{}{"nodeName": node},
+	}}
+}
+
+func reportCapacity(storageClass string, capacity string) storagev1alpha1.CSIStorageCapacity {
+	q := resource.MustParse(capacity)
+	return storagev1alpha1.CSIStorageCapacity{StorageClassName: storageClass, Capacity: &q}
+}
+
+func TestReportCapacity(t *testing.T) {
+	cordoned := reportNode("node-3", "64", "256Gi")
+	cordoned.Spec.Unschedulable = true
+
+	cases := []struct {
+		name             string
+		nodes            []corev1.Node
+		vmis             []unstructured.Unstructured
+		capacities       []storagev1alpha1.CSIStorageCapacity
+		expectedCapacity string
+		expectedProblems []string
+	}{
+		{
+			name:       "fits",
+			nodes:      []corev1.Node{reportNode("node-0", "32", "64Gi"), reportNode("node-1", "32", "64Gi"), reportNode("node-2", "32", "64Gi")},
+			vmis:       []unstructured.Unstructured{reportVMI("node-0", "16", "32Gi"), reportVMI("other-node", "16", "32Gi")},
+			capacities: []storagev1alpha1.CSIStorageCapacity{reportCapacity(validStorageClass, "500Gi"), reportCapacity(validStorageClass, "500Gi"), reportCapacity("other", "1Ti")},
+
+			expectedCapacity: "1000Gi",
+		},
+		{
+			name:  "no capacity published",
+			nodes: []corev1.Node{reportNode("node-0", "32", "64Gi"), reportNode("node-1", "32", "64Gi"), reportNode("node-2", "32", "64Gi")},
+		},
+		{
+			name:       "masters not spread",
+			nodes:      []corev1.Node{reportNode("node-0", "32", "64Gi"), reportNode("node-1", "32", "64Gi"), cordoned},
+			capacities: []storagev1alpha1.CSIStorageCapacity{reportCapacity(validStorageClass, "1Ti")},
+
+			expectedCapacity: "1Ti",
+			expectedProblems: []string{"no schedulable node has 8 CPUs and 16Gi of memory free for VM master-2"},
+		},
+		{
+			name:       "not enough resources",
+			nodes:      []corev1.Node{reportNode("node-0", "16", "32Gi"), reportNode("node-1", "16", "32Gi"), reportNode("node-2", "16", "32Gi")},
+			vmis:       []unstructured.Unstructured{reportVMI("node-2", "12", "24Gi")},
+			capacities: []storagev1alpha1.CSIStorageCapacity{reportCapacity(validStorageClass, "500Gi")},
+
+			expectedCapacity: "500Gi",
+			expectedProblems: []string{
+				"no schedulable node has 8 CPUs and 16Gi of memory free for VM master-2",
+				"storage class valid-storage-class has a capacity of 500Gi, less than the required 635Gi",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().ListNodes(gomock.Any()).Return(tc.nodes, nil)
+			client.EXPECT().ListVirtualMachineInstances(gomock.Any(), "").Return(tc.vmis, nil)
+			client.EXPECT().ListCSIStorageCapacities(gomock.Any()).Return(tc.capacities, nil)
+
+			report, err := ReportCapacity(context.TODO(), reportInstallConfig(), client)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedProblems, report.Problems)
+			assert.Equal(t, len(tc.expectedProblems) == 0, report.Fits())
+			if assert.Len(t, report.StorageClasses, 1) {
+				assert.Equal(t, validStorageClass, report.StorageClasses[0].Name)
+				assert.Equal(t, "635Gi", report.StorageClasses[0].Required.String())
+				if tc.expectedCapacity == "" {
+					assert.Nil(t, report.StorageClasses[0].Capacity)
+				} else if assert.NotNil(t, report.StorageClasses[0].Capacity) {
+					assert.Equal(t, tc.expectedCapacity, report.StorageClasses[0].Capacity.String())
+				}
+			}
+			if tc.name == "fits" {
+				assert.Equal(t, 1, report.Nodes[0].VMs)
+				assert.Equal(t, "32Gi", report.Nodes[0].RequestedMemory.String())
+			}
+		})
+	}
+}
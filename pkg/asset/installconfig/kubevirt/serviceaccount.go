@@ -0,0 +1,65 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// ServiceAccountTokenExpiration is how long the service account tokens the cluster uses to
+	// access the infra cluster are valid.
+	ServiceAccountTokenExpiration = 24 * time.Hour
+
+	// ServiceAccountKubeConfigUser is the user of the service account in its kubeconfig.
+	ServiceAccountKubeConfigUser = "serviceaccount"
+)
+
+// ServiceAccountKubeConfigContent requests a token of the service account of the platform and
+// returns the kubeconfig of the infra cluster authenticating with it, instead of with the
+// credentials of the infra kubeconfig.
+func ServiceAccountKubeConfigContent(ctx context.Context, platform *kubevirt.Platform) ([]byte, error) {
+	client, err := NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+	if err != nil {
+		return nil, err
+	}
+	serviceAccount := platform.ServiceAccount
+	token, err := client.CreateServiceAccountToken(ctx, serviceAccount.Namespace, serviceAccount.Name, ServiceAccountTokenExpiration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request a token of service account %s/%s: %v", serviceAccount.Namespace, serviceAccount.Name, err)
+	}
+	return serviceAccountKubeConfig(kubeConfigPath(platform.InfraKubeconfigPath), platform.InfraContext, token)
+}
+
+// serviceAccountKubeConfig returns the kubeconfig at path, reduced to the context and with its
+// credentials replaced by the token.
+func serviceAccountKubeConfig(path string, kubeconfigContext string, token string) ([]byte, error) {
+	config, err := loadKubeConfigContext(path, kubeconfigContext)
+	if err != nil {
+		return nil, err
+	}
+	if err := clientcmdapi.MinifyConfig(config); err != nil {
+		return nil, err
+	}
+	if err := clientcmdapi.FlattenConfig(config); err != nil {
+		return nil, err
+	}
+	config.AuthInfos = map[string]*clientcmdapi.AuthInfo{
+		ServiceAccountKubeConfigUser: {Token: token},
+	}
+	for _, c := range config.Contexts {
+		c.AuthInfo = ServiceAccountKubeConfigUser
+	}
+	v1Config := &clientcmdapiv1.Config{}
+	if err := clientcmdapiv1.Convert_api_Config_To_v1_Config(config, v1Config, nil); err != nil {
+		return nil, err
+	}
+	v1Config.APIVersion, v1Config.Kind = "v1", "Config"
+	return yaml.Marshal(v1Config)
+}
@@ -0,0 +1,39 @@
+package kubevirt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestServiceAccountKubeConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kubeconfig")
+	if err := ioutil.WriteFile(path, []byte(multiContextKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := serviceAccountKubeConfig(path, "b", "service-account-token")
+	if !assert.NoError(t, err) {
+		return
+	}
+	config, err := clientcmd.Load(content)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "b", config.CurrentContext)
+	assert.Len(t, config.Contexts, 1)
+	assert.Equal(t, ServiceAccountKubeConfigUser, config.Contexts["b"].AuthInfo)
+	assert.Equal(t, "https://b.example.com:6443", config.Clusters["infra-b"].Server)
+	if assert.Len(t, config.AuthInfos, 1) {
+		assert.Equal(t, "service-account-token", config.AuthInfos[ServiceAccountKubeConfigUser].Token)
+	}
+}
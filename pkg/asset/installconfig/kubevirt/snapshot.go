@@ -0,0 +1,425 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	storagev1alpha1 "k8s.io/api/storage/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Snapshot holds the infra cluster objects the install-config is validated against, so that
+// the validation can run later without access to the infra cluster.
+type Snapshot struct {
+	// Time is when the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Namespaces are the namespaces of the cluster which exist in the infra cluster.
+	Namespaces []corev1.Namespace `json:"namespaces"`
+	// StorageClasses are the storage classes of the infra cluster, nil when the credentials
+	// are not allowed to list them.
+	StorageClasses []storagev1.StorageClass `json:"storageClasses"`
+	// PriorityClasses are the priority classes of the infra cluster, nil when the credentials
+	// are not allowed to list them.
+	PriorityClasses []schedulingv1.PriorityClass `json:"priorityClasses,omitempty"`
+	// NetworkAttachmentDefinitions are the network-attachment-definitions of the namespaces.
+	NetworkAttachmentDefinitions []unstructured.Unstructured `json:"networkAttachmentDefinitions"`
+	// ResourceQuotas are the resource quotas of the namespaces.
+	ResourceQuotas []corev1.ResourceQuota `json:"resourceQuotas"`
+	// VirtualMachineNames are the names of the VMs, by namespace.
+	VirtualMachineNames map[string][]string `json:"virtualMachineNames"`
+}
+
+// TakeSnapshot returns the snapshot of the infra cluster objects in the namespaces of the cluster.
+func TakeSnapshot(ctx context.Context, client Client, namespaces []string) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Time:                time.Now().UTC(),
+		VirtualMachineNames: map[string][]string{},
+	}
+
+	storageClasses, err := client.ListStorageClasses(ctx)
+	switch {
+	case apierrors.IsForbidden(err):
+		logrus.Warnf("The storage classes are not included in the snapshot: %v", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to list the storage classes: %v", err)
+	default:
+		snapshot.StorageClasses = storageClasses
+	}
+
+	priorityClasses, err := client.ListPriorityClasses(ctx)
+	switch {
+	case apierrors.IsForbidden(err):
+		logrus.Warnf("The priority classes are not included in the snapshot: %v", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to list the priority classes: %v", err)
+	default:
+		snapshot.PriorityClasses = priorityClasses
+	}
+
+	for _, namespace := range namespaces {
+		ns, err := client.GetNamespace(ctx, namespace)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %v", namespace, err)
+		}
+		snapshot.Namespaces = append(snapshot.Namespaces, *ns)
+
+		nads, err := client.ListNetworkAttachmentDefinitions(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the network-attachment-definitions of namespace %s: %v", namespace, err)
+		}
+		snapshot.NetworkAttachmentDefinitions = append(snapshot.NetworkAttachmentDefinitions, nads...)
+
+		quotas, err := client.ListResourceQuotas(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the resource quotas of namespace %s: %v", namespace, err)
+		}
+		snapshot.ResourceQuotas = append(snapshot.ResourceQuotas, quotas...)
+
+		vmNames, err := client.ListAllVirtualMachineNames(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the VMs of namespace %s: %v", namespace, err)
+		}
+		snapshot.VirtualMachineNames[namespace] = vmNames
+	}
+	return snapshot, nil
+}
+
+// LoadSnapshot reads the snapshot written to the file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the infra cluster snapshot %s: %v", path, err)
+	}
+	return snapshot, nil
+}
+
+// ValidateWithSnapshot validates the install-config against the snapshot of the infra cluster
+// instead of the infra cluster itself. The kubeconfig of the platform is not validated, as it
+// may not be available where the snapshot is reviewed.
+func ValidateWithSnapshot(ic *types.InstallConfig, snapshot *Snapshot) error {
+	kubevirtPlatformPath := field.NewPath("platform", "kubevirt")
+
+	if ic.Platform.Kubevirt == nil {
+		return errors.New(field.Required(
+			kubevirtPlatformPath,
+			"validation requires a Engine platform configuration").Error())
+	}
+
+	clientBuilderFunc := func() (Client, error) {
+		return &snapshotClient{snapshot: snapshot}, nil
+	}
+	allErrs := ValidatePlatform(ic.Platform.Kubevirt, ic.MachineNetwork, clientBuilderFunc, kubevirtPlatformPath)
+	allErrs = append(allErrs, validateMachinePools(ic, clientBuilderFunc)...)
+
+	return allErrs.ToAggregate()
+}
+
+// errSnapshot is returned by the snapshot client for the requests a snapshot cannot answer.
+var errSnapshot = errors.New("not supported with an infra cluster snapshot")
+
+// snapshotClient is a read-only Client answering from a snapshot of the infra cluster.
+type snapshotClient struct {
+	snapshot *Snapshot
+}
+
+func (c *snapshotClient) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	for i := range c.snapshot.Namespaces {
+		if c.snapshot.Namespaces[i].Name == name {
+			return &c.snapshot.Namespaces[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, name)
+}
+
+func (c *snapshotClient) ListNamespace(ctx context.Context) (*corev1.NamespaceList, error) {
+	return &corev1.NamespaceList{Items: c.snapshot.Namespaces}, nil
+}
+
+func (c *snapshotClient) GetStorageClass(ctx context.Context, name string) (*storagev1.StorageClass, error) {
+	for i := range c.snapshot.StorageClasses {
+		if c.snapshot.StorageClasses[i].Name == name {
+			return &c.snapshot.StorageClasses[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: storagev1.GroupName, Resource: "storageclasses"}, name)
+}
+
+func (c *snapshotClient) ListStorageClasses(ctx context.Context) ([]storagev1.StorageClass, error) {
+	return c.snapshot.StorageClasses, nil
+}
+
+func (c *snapshotClient) GetPriorityClass(ctx context.Context, name string) (*schedulingv1.PriorityClass, error) {
+	for i := range c.snapshot.PriorityClasses {
+		if c.snapshot.PriorityClasses[i].Name == name {
+			return &c.snapshot.PriorityClasses[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: schedulingv1.GroupName, Resource: "priorityclasses"}, name)
+}
+
+func (c *snapshotClient) ListPriorityClasses(ctx context.Context) ([]schedulingv1.PriorityClass, error) {
+	return c.snapshot.PriorityClasses, nil
+}
+
+func (c *snapshotClient) GetNetworkAttachmentDefinition(ctx context.Context, name string, namespace string) (*unstructured.Unstructured, error) {
+	for i := range c.snapshot.NetworkAttachmentDefinitions {
+		nad := &c.snapshot.NetworkAttachmentDefinitions[i]
+		if nad.GetNamespace() == namespace && nad.GetName() == name {
+			return nad, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: "k8s.cni.cncf.io", Resource: "network-attachment-definitions"}, name)
+}
+
+func (c *snapshotClient) ListNetworkAttachmentDefinitions(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	var result []unstructured.Unstructured
+	for _, nad := range c.snapshot.NetworkAttachmentDefinitions {
+		if nad.GetNamespace() == namespace {
+			result = append(result, nad)
+		}
+	}
+	return result, nil
+}
+
+func (c *snapshotClient) ListResourceQuotas(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error) {
+	var result []corev1.ResourceQuota
+	for _, quota := range c.snapshot.ResourceQuotas {
+		if quota.Namespace == namespace {
+			result = append(result, quota)
+		}
+	}
+	return result, nil
+}
+
+func (c *snapshotClient) ListAllVirtualMachineNames(ctx context.Context, namespace string) ([]string, error) {
+	if _, err := c.GetNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+	return c.snapshot.VirtualMachineNames[namespace], nil
+}
+
+// CheckAccess answers whether the resource is in the snapshot: the storage classes are left out
+// when the credentials are not allowed to list them, the other resources are always included.
+func (c *snapshotClient) CheckAccess(ctx context.Context, namespace string, group string, resource string, verb string) (bool, error) {
+	if resource == "storageclasses" {
+		return c.snapshot.StorageClasses != nil, nil
+	}
+	if resource == "priorityclasses" {
+		return c.snapshot.PriorityClasses != nil, nil
+	}
+	return true, nil
+}
+
+func (c *snapshotClient) CreateServiceAccountToken(ctx context.Context, namespace string, name string, expiration time.Duration) (string, error) {
+	return "", errSnapshot
+}
+
+func (c *snapshotClient) DeleteVirtualMachine(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) StopVirtualMachine(namespace string, name string, gracePeriod time.Duration) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) StartVirtualMachine(namespace string, name string) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListResourceLabels(ctx context.Context, namespace string, resource string) (map[string]map[string]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) AddResourceLabels(ctx context.Context, namespace string, resource string, name string, labels map[string]string) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) SetVirtualMachineRunStrategy(namespace string, name string, runStrategy string) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) AnnotateVirtualMachine(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) WaitForVirtualMachineStopped(namespace string, name string, timeout time.Duration) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListVirtualMachineNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ListVirtualMachines(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ListVirtualMachineInstances(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ListCSIStorageCapacities(ctx context.Context) ([]storagev1alpha1.CSIStorageCapacity, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ListDataVolumes(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) WatchDataVolumes(ctx context.Context, namespace string) (watch.Interface, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) CreateDataVolume(ctx context.Context, dv *unstructured.Unstructured) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) DeleteDataVolume(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListDataVolumeNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) DeleteSecret(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListSecretNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) CreateNetworkAttachmentDefinition(ctx context.Context, nad *unstructured.Unstructured) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) DeleteNetworkAttachmentDefinition(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListNetworkAttachmentDefinitionNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) GetConfigMap(ctx context.Context, namespace string, name string) (*corev1.ConfigMap, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) CreateOrUpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) AnnotateConfigMap(ctx context.Context, namespace string, name string, annotations map[string]string) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) DeleteConfigMap(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListConfigMapNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) CreateEvent(ctx context.Context, event *corev1.Event) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) CreatePod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) GetPod(ctx context.Context, namespace string, name string) (*corev1.Pod, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) GetPodLogs(ctx context.Context, namespace string, name string) (string, error) {
+	return "", errSnapshot
+}
+
+func (c *snapshotClient) DeletePod(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) DeleteMultiNetworkPolicy(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListMultiNetworkPolicyNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) DeletePodDisruptionBudget(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListPodDisruptionBudgetNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) ApplyResource(ctx context.Context, resource string, obj *unstructured.Unstructured) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) DeleteDeployment(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListDeploymentNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) DeleteService(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListServiceNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) DeleteEndpoints(namespace string, name string, wait bool) error {
+	return errSnapshot
+}
+
+func (c *snapshotClient) ListEndpointsNames(namespace string, requiredLabels map[string]string) ([]string, error) {
+	return nil, errSnapshot
+}
+
+func (c *snapshotClient) GetServerVersion() (string, error) {
+	return "", errSnapshot
+}
+
+func (c *snapshotClient) GetKubeVirtVersion(ctx context.Context) (string, error) {
+	return "", errSnapshot
+}
+
+func (c *snapshotClient) SerialConsole(ctx context.Context, namespace string, name string) (io.ReadWriteCloser, error) {
+	return nil, errSnapshot
+}
@@ -0,0 +1,126 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestSnapshot(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	nad := networkAttachmentDefinition(validNADConfig)
+	nad.SetAPIVersion("k8s.cni.cncf.io/v1")
+	nad.SetKind("NetworkAttachmentDefinition")
+
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListStorageClasses(gomock.Any()).Return([]storagev1.StorageClass{{ObjectMeta: metav1.ObjectMeta{Name: validStorageClass}}}, nil)
+	client.EXPECT().ListPriorityClasses(gomock.Any()).Return([]schedulingv1.PriorityClass{{ObjectMeta: metav1.ObjectMeta{Name: "tenant-control-plane"}}}, nil)
+	client.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: validNamespace}}, nil)
+	client.EXPECT().GetNamespace(gomock.Any(), "missing-namespace").Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "missing-namespace"))
+	client.EXPECT().ListNetworkAttachmentDefinitions(gomock.Any(), validNamespace).Return([]unstructured.Unstructured{*nad}, nil)
+	client.EXPECT().ListResourceQuotas(gomock.Any(), validNamespace).Return([]corev1.ResourceQuota{{ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: validNamespace}}}, nil)
+	client.EXPECT().ListAllVirtualMachineNames(gomock.Any(), validNamespace).Return([]string{"other-master-0"}, nil)
+
+	snapshot, err := TakeSnapshot(context.TODO(), client, []string{validNamespace, "missing-namespace"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "infra-snapshot.json")
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = LoadSnapshot(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, snapshot.Namespaces, 1)
+	assert.Len(t, snapshot.ResourceQuotas, 1)
+
+	cases := []struct {
+		name           string
+		edit           func(ic *types.InstallConfig)
+		editSnapshot   func(s *Snapshot)
+		expectedErrMsg string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:           "invalid storage class",
+			edit:           func(ic *types.InstallConfig) { ic.Platform.Kubevirt.StorageClass = invalidStorageClass },
+			expectedErrMsg: `platform.kubevirt.StorageClassExistsInInfraCluster: Invalid value: "invalid-storage-class": failed to get storageClass invalid-storage-class from InfraCluster`,
+		},
+		{
+			name:         "storage classes not in the snapshot",
+			edit:         func(ic *types.InstallConfig) { ic.Platform.Kubevirt.StorageClass = invalidStorageClass },
+			editSnapshot: func(s *Snapshot) { s.StorageClasses = nil },
+		},
+		{
+			name: "valid priority class",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{PriorityClassName: "tenant-control-plane"}}}
+			},
+		},
+		{
+			name: "invalid priority class",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{PriorityClassName: "missing"}}}
+			},
+			expectedErrMsg: `controlPlane.platform.kubevirt.priorityClassName: Invalid value: "missing": failed to get priorityClass missing from InfraCluster`,
+		},
+		{
+			name:           "VIP outside of the network-attachment-definition subnet",
+			edit:           func(ic *types.InstallConfig) { ic.Platform.Kubevirt.APIVIP = "192.168.124.15" },
+			expectedErrMsg: "APIVIP 192.168.124.15 is not in the subnet",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := validInstallConfig()
+			if tc.edit != nil {
+				tc.edit(ic)
+			}
+			s := *snapshot
+			if tc.editSnapshot != nil {
+				tc.editSnapshot(&s)
+			}
+			ic.Platform.Kubevirt.NamespaceScopedCredentials = true
+
+			err := ValidateWithSnapshot(ic, &s)
+			if tc.expectedErrMsg == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
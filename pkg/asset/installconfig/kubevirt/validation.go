@@ -5,10 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/kubevirt"
 	"github.com/openshift/installer/pkg/types/kubevirt/validation"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -22,7 +30,11 @@ func Validate(ic *types.InstallConfig, clientBuilderFunc ClientBuilderFuncType)
 			"validation requires a Engine platform configuration").Error())
 	}
 
-	return ValidatePlatform(ic.Platform.Kubevirt, ic.MachineNetwork, clientBuilderFunc, kubevirtPlatformPath).ToAggregate()
+	allErrs := validateInfraKubeconfig(ic.Platform.Kubevirt, kubevirtPlatformPath)
+	allErrs = append(allErrs, ValidatePlatform(ic.Platform.Kubevirt, ic.MachineNetwork, clientBuilderFunc, kubevirtPlatformPath)...)
+	allErrs = append(allErrs, validateMachinePools(ic, clientBuilderFunc)...)
+
+	return allErrs.ToAggregate()
 }
 
 func ValidatePlatform(kubevirtPlatform *kubevirt.Platform, machineNetworkEntryList []types.MachineNetworkEntry, clientBuilderFunc ClientBuilderFuncType, fldPath *field.Path) field.ErrorList {
@@ -34,9 +46,25 @@ func ValidatePlatform(kubevirtPlatform *kubevirt.Platform, machineNetworkEntryLi
 	if client != nil {
 		nsErr := validateNamespaceExistsInInfraCluster(ctx, kubevirtPlatform.Namespace, client, fldPath)
 		allErrs = append(allErrs, nsErr...)
-		allErrs = append(allErrs, validateStorageClassExistsInInfraCluster(ctx, kubevirtPlatform.StorageClass, client, fldPath)...)
+		if kubevirtPlatform.ServiceAccount != nil {
+			allErrs = append(allErrs, validateServiceAccountToken(ctx, kubevirtPlatform.ServiceAccount, client, fldPath)...)
+		}
+		if clusterScopedAllowed(ctx, kubevirtPlatform, client, "storage.k8s.io", "storageclasses", "get") {
+			allErrs = append(allErrs, validateStorageClassExistsInInfraCluster(ctx, kubevirtPlatform.StorageClass, client, fldPath)...)
+		}
+		if len(kubevirtPlatform.NodeLabels) > 0 && clusterScopedAllowed(ctx, kubevirtPlatform, client, "", "nodes", "list") {
+			allErrs = append(allErrs, validateNodeLabelsOnInfraNodes(ctx, kubevirtPlatform, client, fldPath)...)
+		}
 		if len(nsErr) == 0 {
-			allErrs = append(allErrs, validateNetworkAttachmentDefinitionExistsInInfraCluster(ctx, kubevirtPlatform.NetworkName, kubevirtPlatform.Namespace, client, fldPath)...)
+			nadErr := validateNetworkAttachmentDefinitionExistsInInfraCluster(ctx, kubevirtPlatform.NetworkName, kubevirtPlatform.Namespace, client, fldPath)
+			allErrs = append(allErrs, nadErr...)
+			if len(nadErr) == 0 {
+				allErrs = append(allErrs, validateNetworkAttachmentDefinitionCNIType(ctx, kubevirtPlatform, client, fldPath)...)
+				allErrs = append(allErrs, validateIPsInNetworkAttachmentDefinitionSubnet(ctx, kubevirtPlatform, client, fldPath)...)
+				if kubevirtPlatform.VIPsInUseCheck {
+					allErrs = append(allErrs, validateIPsNotInUse(ctx, kubevirtPlatform, client, fldPath)...)
+				}
+			}
 		}
 	}
 	allErrs = append(allErrs, validateIPsInMachineNetworkEntryList(machineNetworkEntryList, kubevirtPlatform.APIVIP, kubevirtPlatform.IngressVIP, fldPath)...)
@@ -44,6 +72,60 @@ func ValidatePlatform(kubevirtPlatform *kubevirt.Platform, machineNetworkEntryLi
 	return allErrs
 }
 
+// clusterScopedAllowed returns whether a validation of the cluster-scoped resource can run: always
+// with cluster-scoped infra cluster credentials, and with namespace-scoped ones only when a
+// SelfSubjectAccessReview shows that they are allowed to perform the verb on the resource.
+func clusterScopedAllowed(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client, group string, resource string, verb string) bool {
+	if !kubevirtPlatform.NamespaceScopedCredentials {
+		return true
+	}
+	allowed, err := client.CheckAccess(ctx, "", group, resource, verb)
+	if err != nil {
+		logrus.Warnf("Skipping the validation of the %s, failed to check the permission to %s them: %v", resource, verb, err)
+		return false
+	}
+	if !allowed {
+		logrus.Infof("Skipping the validation of the %s, the namespace-scoped infra cluster credentials are not allowed to %s them", resource, verb)
+	}
+	return allowed
+}
+
+// validateInfraKubeconfig checks that the explicit kubeconfig of the infra cluster can be
+// loaded and holds the explicit context.
+func validateInfraKubeconfig(kubevirtPlatform *kubevirt.Platform, fieldPath *field.Path) field.ErrorList {
+	return validateKubeconfig(kubevirtPlatform.InfraKubeconfigPath, kubevirtPlatform.InfraContext, fieldPath)
+}
+
+// validateKubeconfig checks that the explicit kubeconfig can be loaded and holds the explicit
+// context, reported as the infraKubeconfigPath and infraContext fields.
+func validateKubeconfig(kubeconfigPath string, kubeconfigContext string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if kubeconfigPath == "" && kubeconfigContext == "" {
+		return allErrs
+	}
+
+	path := kubeConfigPath(kubeconfigPath)
+	config, err := loadKubeConfigContext(path, "")
+	if err != nil {
+		detailedErr := fmt.Errorf("failed to load kubeconfig %s, with error: %v", path, err)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("infraKubeconfigPath"), kubeconfigPath, detailedErr.Error()))
+		return allErrs
+	}
+	if kubeconfigContext != "" {
+		if _, ok := config.Contexts[kubeconfigContext]; !ok {
+			contexts := make([]string, 0, len(config.Contexts))
+			for name := range config.Contexts {
+				contexts = append(contexts, name)
+			}
+			sort.Strings(contexts)
+			detailedErr := fmt.Errorf("context %s not found in kubeconfig %s, the available contexts are: %s", kubeconfigContext, path, strings.Join(contexts, ", "))
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("infraContext"), kubeconfigContext, detailedErr.Error()))
+		}
+	}
+
+	return allErrs
+}
+
 func validateInfraClusterReachable(ctx context.Context, clientBuilderFunc ClientBuilderFuncType, fieldPath *field.Path) (Client, field.ErrorList) {
 	allErrs := field.ErrorList{}
 	client, err := clientBuilderFunc()
@@ -75,6 +157,24 @@ func validateNamespaceExistsInInfraCluster(ctx context.Context, name string, cli
 	return allErrs
 }
 
+// validateServiceAccountToken checks that a token of the service account can be requested, as
+// the installer requests the token of the cluster when generating its manifests.
+func validateServiceAccountToken(ctx context.Context, serviceAccount *kubevirt.ServiceAccountReference, client Client, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// The shortest expiration the API server accepts
+	if _, err := client.CreateServiceAccountToken(ctx, serviceAccount.Namespace, serviceAccount.Name, 10*time.Minute); err != nil {
+		if err == errSnapshot {
+			logrus.Info("Skipping the validation of the service account, tokens cannot be requested with an infra cluster snapshot")
+			return allErrs
+		}
+		detailedErr := fmt.Errorf("failed to request a token of service account %s/%s from InfraCluster, with error: %v", serviceAccount.Namespace, serviceAccount.Name, err)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("serviceAccount"), serviceAccount.Namespace+"/"+serviceAccount.Name, detailedErr.Error()))
+	}
+
+	return allErrs
+}
+
 func validateStorageClassExistsInInfraCluster(ctx context.Context, name string, client Client, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -86,6 +186,30 @@ func validateStorageClassExistsInInfraCluster(ctx context.Context, name string,
 	return allErrs
 }
 
+// validateNodeLabelsOnInfraNodes checks that at least one infra cluster node has each of the
+// infra labels mapped to the tenant cluster nodes.
+func validateNodeLabelsOnInfraNodes(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client, fieldPath *field.Path) field.ErrorList {
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		detailedErr := fmt.Errorf("failed to list the nodes of the InfraCluster, with error: %v", err)
+		return field.ErrorList{field.Invalid(fieldPath.Child("nodeLabels"), len(kubevirtPlatform.NodeLabels), detailedErr.Error())}
+	}
+	allErrs := field.ErrorList{}
+	for i, mapping := range kubevirtPlatform.NodeLabels {
+		found := false
+		for _, node := range nodes {
+			if _, ok := node.Labels[mapping.InfraLabel]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("nodeLabels").Index(i).Child("infraLabel"), mapping.InfraLabel, "no infra cluster node has the label"))
+		}
+	}
+	return allErrs
+}
+
 func validateNetworkAttachmentDefinitionExistsInInfraCluster(ctx context.Context, name string, namespace string, client Client, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -97,6 +221,332 @@ func validateNetworkAttachmentDefinitionExistsInInfraCluster(ctx context.Context
 	return allErrs
 }
 
+// platformNetworkAttachmentDefinition returns the network-attachment-definition of the platform
+// or, when it doesn't exist and the platform has a network template, the one to be created from
+// it, for the validations to run against the network the cluster will use.
+func platformNetworkAttachmentDefinition(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client) (*unstructured.Unstructured, error) {
+	nad, err := client.GetNetworkAttachmentDefinition(ctx, kubevirtPlatform.NetworkName, kubevirtPlatform.Namespace)
+	if kubevirtPlatform.CreateNetwork != nil && apierrors.IsNotFound(err) {
+		return NetworkAttachmentDefinition(kubevirtPlatform, nil)
+	}
+	return nad, err
+}
+
+// validateNetworkAttachmentDefinitionCNIType checks that the CNI plugin of the
+// network-attachment-definition can carry the cluster traffic, rather than failing at node boot.
+func validateNetworkAttachmentDefinitionCNIType(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	nad, err := platformNetworkAttachmentDefinition(ctx, kubevirtPlatform, client)
+	if err != nil || nad == nil {
+		// The existence of the network-attachment-definition is validated separately
+		return allErrs
+	}
+	cfg, err := networkAttachmentDefinitionConfig(nad)
+	if err != nil {
+		// The parsing errors are reported by the subnet validation
+		return allErrs
+	}
+	if err := cfg.validateType(); err != nil {
+		detailedErr := fmt.Errorf("network-attachment-definition %s can't be used for the cluster traffic: %v", kubevirtPlatform.NetworkName, err)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("NetworkName"), kubevirtPlatform.NetworkName, detailedErr.Error()))
+	}
+
+	return allErrs
+}
+
+func validateIPsInNetworkAttachmentDefinitionSubnet(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	nad, err := platformNetworkAttachmentDefinition(ctx, kubevirtPlatform, client)
+	if err != nil || nad == nil {
+		// The existence of the network-attachment-definition is validated separately
+		return allErrs
+	}
+	cfg, err := networkAttachmentDefinitionConfig(nad)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("NetworkName"), kubevirtPlatform.NetworkName, err.Error()))
+		return allErrs
+	}
+	subnets, err := cfg.subnets()
+	if err != nil {
+		detailedErr := fmt.Errorf("failed to get the subnet of network-attachment-definition %s, with error: %v", kubevirtPlatform.NetworkName, err)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("NetworkName"), kubevirtPlatform.NetworkName, detailedErr.Error()))
+		return allErrs
+	}
+	// Without a static IPAM subnet (e.g. DHCP) there is nothing to validate against
+	if len(subnets) == 0 {
+		return allErrs
+	}
+
+	for _, vip := range []struct {
+		name string
+		ip   string
+	}{{"APIVIP", kubevirtPlatform.APIVIP}, {"IngressVIP", kubevirtPlatform.IngressVIP}} {
+		ipAddr := net.ParseIP(vip.ip)
+		if ipAddr == nil {
+			// Invalid IPs are reported by the platform validation
+			continue
+		}
+		if !subnetsContain(subnets, ipAddr) {
+			detailedErr := fmt.Errorf("%s %s is not in the subnet %s of network-attachment-definition %s", vip.name, vip.ip, subnets, kubevirtPlatform.NetworkName)
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child(vip.name), vip.ip, detailedErr.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func subnetsContain(subnets []*net.IPNet, ip net.IP) bool {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIPsNotInUse checks, from a probe pod attached to the network-attachment-definition,
+// that no host of the network already answers for the API and ingress VIPs.
+func validateIPsNotInUse(ctx context.Context, kubevirtPlatform *kubevirt.Platform, client Client, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	checks, err := ProbeVIPs(ctx, client, kubevirtPlatform)
+	if err != nil {
+		detailedErr := fmt.Errorf("failed to check if the VIPs are in use on network %s, with error: %v", kubevirtPlatform.NetworkName, err)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("vipsInUseCheck"), kubevirtPlatform.VIPsInUseCheck, detailedErr.Error()))
+		return allErrs
+	}
+	vips := map[string]struct {
+		name string
+		ip   string
+	}{
+		NetworkProbeAPIVIP:     {"APIVIP", kubevirtPlatform.APIVIP},
+		NetworkProbeIngressVIP: {"IngressVIP", kubevirtPlatform.IngressVIP},
+	}
+	for _, failed := range FailedNetworkProbeChecks(checks) {
+		vip, ok := vips[failed]
+		if !ok {
+			continue
+		}
+		detailedErr := fmt.Errorf("IP is already in use on network %s", kubevirtPlatform.NetworkName)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child(vip.name), vip.ip, detailedErr.Error()))
+	}
+
+	return allErrs
+}
+
+type machinePoolWithPath struct {
+	pool    *types.MachinePool
+	fldPath *field.Path
+}
+
+// validateMachinePoolInfraClusters checks that the infra clusters of the compute pools with their
+// own infra cluster are reachable and hold the platform namespace and network, which the
+// machine-api provider creates the VMs of the pools with.
+func validateMachinePoolInfraClusters(ctx context.Context, ic *types.InstallConfig, pools []machinePoolWithPath, registry *ClientRegistry) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, p := range pools {
+		if p.pool == ic.ControlPlane || !p.pool.Platform.Kubevirt.HasInfraCluster() {
+			continue
+		}
+		kubeconfigErrs := validateKubeconfig(p.pool.Platform.Kubevirt.InfraKubeconfigPath, p.pool.Platform.Kubevirt.InfraContext, p.fldPath)
+		allErrs = append(allErrs, kubeconfigErrs...)
+		if len(kubeconfigErrs) > 0 {
+			continue
+		}
+		client, err := registry.ForPool(p.pool.Name)
+		if err != nil {
+			detailedErr := fmt.Errorf("failed to create the client of the infra cluster of the pool, with error: %v", err)
+			allErrs = append(allErrs, field.Invalid(p.fldPath.Child("infraKubeconfigPath"), p.pool.Platform.Kubevirt.InfraKubeconfigPath, detailedErr.Error()))
+			continue
+		}
+		nsErr := validateNamespaceExistsInInfraCluster(ctx, ic.Platform.Kubevirt.Namespace, client, p.fldPath)
+		allErrs = append(allErrs, nsErr...)
+		if len(nsErr) == 0 && ic.Platform.Kubevirt.CreateNetwork == nil {
+			allErrs = append(allErrs, validateNetworkAttachmentDefinitionExistsInInfraCluster(ctx, ic.Platform.Kubevirt.NetworkName, ic.Platform.Kubevirt.Namespace, client, p.fldPath)...)
+		}
+	}
+	return allErrs
+}
+
+// kubevirtMachinePools returns the machine pools which have a kubevirt configuration, with their field paths.
+func kubevirtMachinePools(ic *types.InstallConfig) []machinePoolWithPath {
+	var pools []machinePoolWithPath
+	if ic.ControlPlane != nil && ic.ControlPlane.Platform.Kubevirt != nil {
+		pools = append(pools, machinePoolWithPath{ic.ControlPlane, field.NewPath("controlPlane", "platform", "kubevirt")})
+	}
+	for i := range ic.Compute {
+		if ic.Compute[i].Platform.Kubevirt != nil {
+			pools = append(pools, machinePoolWithPath{&ic.Compute[i], field.NewPath("compute").Index(i).Child("platform", "kubevirt")})
+		}
+	}
+	return pools
+}
+
+func validateMachinePools(ic *types.InstallConfig, clientBuilderFunc ClientBuilderFuncType) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ctx := context.Background()
+
+	pools := kubevirtMachinePools(ic)
+	needsClient := false
+	for _, p := range pools {
+		if p.pool == ic.ControlPlane && p.pool.Platform.Kubevirt.HasInfraCluster() {
+			allErrs = append(allErrs, field.Invalid(p.fldPath.Child("infraKubeconfigPath"), p.pool.Platform.Kubevirt.InfraKubeconfigPath, "the control plane machine pool does not support its own infra cluster, its VMs are created in the platform infra cluster"))
+		}
+		if p.pool.Platform.Kubevirt.NamePrefix != "" {
+			needsClient = true
+		}
+		if p.pool == ic.ControlPlane && p.pool.Platform.Kubevirt.Hugepages != nil {
+			needsClient = true
+		}
+		if p.pool == ic.ControlPlane && p.pool.Platform.Kubevirt.EtcdDisk != nil && p.pool.Platform.Kubevirt.EtcdDisk.StorageClass != "" {
+			needsClient = true
+		}
+		if p.pool == ic.ControlPlane && p.pool.Platform.Kubevirt.PriorityClassName != "" {
+			needsClient = true
+		}
+	}
+	allErrs = append(allErrs, validateMachinePoolInfraClusters(ctx, ic, pools, NewClientRegistry(ic, clientBuilderFunc))...)
+	if len(pools) == 0 {
+		return allErrs
+	}
+
+	client, err := clientBuilderFunc()
+	if err != nil {
+		// The infra cluster reachability is validated with the platform
+		return allErrs
+	}
+	quotaErrs, quotaWarnings := validateMachinePoolQuotas(ctx, ic, pools, client)
+	allErrs = append(allErrs, quotaErrs...)
+	for _, warning := range quotaWarnings {
+		logrus.Warn(warning)
+	}
+	if !needsClient {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateMachinePoolNamePrefixes(ctx, ic, pools, client)...)
+	allErrs = append(allErrs, validateEtcdDiskStorageClass(ctx, ic, client)...)
+	allErrs = append(allErrs, validatePriorityClass(ctx, ic, client)...)
+	allErrs = append(allErrs, validateHugepagesOnInfraNodes(ctx, ic, client)...)
+
+	return allErrs
+}
+
+// validateMachinePoolNamePrefixes checks that the VM names generated from the custom
+// name prefixes of the machine pools don't collide with existing VMs in their namespace.
+func validateMachinePoolNamePrefixes(ctx context.Context, ic *types.InstallConfig, pools []machinePoolWithPath, client Client) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, p := range pools {
+		namePrefix := p.pool.Platform.Kubevirt.NamePrefix
+		if namePrefix == "" {
+			continue
+		}
+		namespace := ic.Platform.Kubevirt.Namespace
+		vmNames, err := client.ListAllVirtualMachineNames(ctx, namespace)
+		if err != nil {
+			detailedErr := fmt.Errorf("failed to list the VMs in namespace %s from InfraCluster, with error: %v", namespace, err)
+			allErrs = append(allErrs, field.Invalid(p.fldPath.Child("namePrefix"), namePrefix, detailedErr.Error()))
+			continue
+		}
+		prefix := fmt.Sprintf("%s-%s-", namePrefix, p.pool.Name)
+		for _, vmName := range vmNames {
+			if strings.HasPrefix(vmName, prefix) {
+				detailedErr := fmt.Errorf("the VM names of machine pool %s collide with the existing VM %s in namespace %s", p.pool.Name, vmName, namespace)
+				allErrs = append(allErrs, field.Invalid(p.fldPath.Child("namePrefix"), namePrefix, detailedErr.Error()))
+				break
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateEtcdDiskStorageClass checks that the storage class of the etcd disk of the control
+// plane exists in the infra cluster.
+func validateEtcdDiskStorageClass(ctx context.Context, ic *types.InstallConfig, client Client) field.ErrorList {
+	if ic.ControlPlane == nil {
+		return nil
+	}
+	mpool := ic.ControlPlane.Platform.Kubevirt
+	if mpool == nil || mpool.EtcdDisk == nil || mpool.EtcdDisk.StorageClass == "" {
+		return nil
+	}
+	if !clusterScopedAllowed(ctx, ic.Platform.Kubevirt, client, "storage.k8s.io", "storageclasses", "get") {
+		return nil
+	}
+	fldPath := field.NewPath("controlPlane", "platform", "kubevirt", "etcdDisk", "storageClass")
+	if _, err := client.GetStorageClass(ctx, mpool.EtcdDisk.StorageClass); err != nil {
+		detailedErr := fmt.Errorf("failed to get storageClass %s from InfraCluster, with error: %v", mpool.EtcdDisk.StorageClass, err)
+		return field.ErrorList{field.Invalid(fldPath, mpool.EtcdDisk.StorageClass, detailedErr.Error())}
+	}
+	return nil
+}
+
+// validatePriorityClass checks that the priority class of the control plane exists in the infra
+// cluster.
+func validatePriorityClass(ctx context.Context, ic *types.InstallConfig, client Client) field.ErrorList {
+	if ic.ControlPlane == nil {
+		return nil
+	}
+	mpool := ic.ControlPlane.Platform.Kubevirt
+	if mpool == nil || mpool.PriorityClassName == "" {
+		return nil
+	}
+	if !clusterScopedAllowed(ctx, ic.Platform.Kubevirt, client, "scheduling.k8s.io", "priorityclasses", "get") {
+		return nil
+	}
+	fldPath := field.NewPath("controlPlane", "platform", "kubevirt", "priorityClassName")
+	if _, err := client.GetPriorityClass(ctx, mpool.PriorityClassName); err != nil {
+		detailedErr := fmt.Errorf("failed to get priorityClass %s from InfraCluster, with error: %v", mpool.PriorityClassName, err)
+		return field.ErrorList{field.Invalid(fldPath, mpool.PriorityClassName, detailedErr.Error())}
+	}
+	return nil
+}
+
+// validateHugepagesOnInfraNodes checks that an infra cluster node advertises enough hugepages of
+// the page size of the control plane pool as allocatable resources to run one of its VMs.
+func validateHugepagesOnInfraNodes(ctx context.Context, ic *types.InstallConfig, client Client) field.ErrorList {
+	if ic.ControlPlane == nil {
+		return nil
+	}
+	mpool := ic.ControlPlane.Platform.Kubevirt
+	if mpool == nil || mpool.Hugepages == nil {
+		return nil
+	}
+	memory, err := resource.ParseQuantity(mpool.VMMemory())
+	if err != nil {
+		// The memory is validated separately
+		return nil
+	}
+	if !clusterScopedAllowed(ctx, ic.Platform.Kubevirt, client, "", "nodes", "list") {
+		return nil
+	}
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return nil
+	}
+
+	fldPath := field.NewPath("controlPlane", "platform", "kubevirt", "hugepages", "pageSize")
+	hugepages := corev1.ResourceName(corev1.ResourceHugePagesPrefix + mpool.Hugepages.PageSize)
+	advertised := false
+	for _, node := range nodes {
+		allocatable, ok := node.Status.Allocatable[hugepages]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		advertised = true
+		if allocatable.Cmp(memory) >= 0 {
+			return nil
+		}
+	}
+	if !advertised {
+		return field.ErrorList{field.Invalid(fldPath, mpool.Hugepages.PageSize, fmt.Sprintf("no infra cluster node advertises %s hugepages as an allocatable resource", hugepages))}
+	}
+	return field.ErrorList{field.Invalid(fldPath, mpool.Hugepages.PageSize, fmt.Sprintf("no infra cluster node has %s of allocatable %s hugepages for the memory of a control plane VM", memory.String(), hugepages))}
+}
+
 func validateIPsInMachineNetworkEntryList(machineNetworkEntryList []types.MachineNetworkEntry, apiVIP string, ingressVIP string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -7,6 +7,12 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
 	"github.com/openshift/installer/pkg/ipnet"
@@ -32,6 +38,37 @@ var (
 	invalidMachineCIDR    = "10.0.0.0/16"
 )
 
+var (
+	validNADConfig   = `{"cniVersion":"0.3.1","plugins":[{"type":"bridge","bridge":"br1","ipam":{"type":"host-local","subnet":"192.168.123.0/24"}}]}`
+	invalidNADConfig = `{"cniVersion":"0.3.1","type":"bridge","bridge":"br1","ipam":{"type":"whereabouts","range":"10.0.0.0/16"}}`
+)
+
+func networkAttachmentDefinition(config string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      validNetworkName,
+				"namespace": validNamespace,
+			},
+			"spec": map[string]interface{}{
+				"config": config,
+			},
+		},
+	}
+}
+
+func nadNotFound(name string) error {
+	return apierrors.NewNotFound(schema.GroupResource{Group: "k8s.cni.cncf.io", Resource: "network-attachment-definitions"}, name)
+}
+
+func hugepagesNode(hugepages string, allocatable string) corev1.Node {
+	return corev1.Node{Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceName(hugepages): resource.MustParse(allocatable)}}}
+}
+
+func labeledNode(labels map[string]string) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+}
+
 func validInstallConfig() *types.InstallConfig {
 	return &types.InstallConfig{
 		Networking: &types.Networking{
@@ -117,6 +154,20 @@ func TestKubevirtInstallConfigValidation(t *testing.T) {
 				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), invalidStorageClass).Return(nil, fmt.Errorf("test")).AnyTimes()
 			},
 		},
+		{
+			name: "storage class skipped with namespace-scoped credentials",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt.StorageClass = invalidStorageClass
+				ic.Platform.Kubevirt.NamespaceScopedCredentials = true
+			},
+			expectedError: false,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().CheckAccess(gomock.Any(), "", "storage.k8s.io", "storageclasses", "get").Return(false, nil)
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(validNADConfig), nil).AnyTimes()
+			},
+		},
 		{
 			name:           "invalid network name",
 			edit:           func(ic *types.InstallConfig) { ic.Platform.Kubevirt.NetworkName = invalidNetworkName },
@@ -167,6 +218,277 @@ func TestKubevirtInstallConfigValidation(t *testing.T) {
 				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
 			},
 		},
+		{
+			name:           "valid VIPs in network-attachment-definition subnet",
+			edit:           nil,
+			expectedError:  false,
+			expectedErrMsg: "",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(validNADConfig), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "invalid VIPs not in network-attachment-definition subnet",
+			edit:           nil,
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.APIVIP: Invalid value: \"192.168.123.15\": APIVIP 192.168.123.15 is not in the subnet \\[10.0.0.0/16\\] of network-attachment-definition valid-network-name",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(invalidNADConfig), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "valid macvlan network-attachment-definition with DHCP",
+			edit:           nil,
+			expectedError:  false,
+			expectedErrMsg: "",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(`{"cniVersion":"0.3.1","type":"macvlan","master":"eth1","ipam":{"type":"dhcp"}}`), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "invalid macvlan network-attachment-definition without DHCP",
+			edit:           nil,
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.NetworkName: Invalid value: \"valid-network-name\": network-attachment-definition valid-network-name can't be used for the cluster traffic: CNI type macvlan is only supported with the dhcp IPAM, not \"host-local\"",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(`{"cniVersion":"0.3.1","plugins":[{"type":"macvlan","master":"eth1","ipam":{"type":"host-local","subnet":"192.168.123.0/24"}},{"type":"tuning"}]}`), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "invalid ptp network-attachment-definition",
+			edit:           nil,
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.NetworkName: Invalid value: \"valid-network-name\": network-attachment-definition valid-network-name can't be used for the cluster traffic: CNI type ptp is not supported",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition(`{"cniVersion":"0.3.1","type":"ptp","ipam":{"type":"host-local","subnet":"192.168.123.0/24"}}`), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "invalid network-attachment-definition config",
+			edit:           nil,
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.NetworkName: Invalid value: \"valid-network-name\": failed to parse CNI config",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(networkAttachmentDefinition("{"), nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name: "valid network-attachment-definition created",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: validMachineCIDR}
+			},
+			expectedError:  false,
+			expectedErrMsg: "",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nadNotFound(validNetworkName)).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid VIPs not in the subnet of the network-attachment-definition created",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeOVN, CIDR: invalidMachineCIDR}
+			},
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.APIVIP: Invalid value: \"192.168.123.15\": APIVIP 192.168.123.15 is not in the subnet \\[10.0.0.0/16\\] of network-attachment-definition valid-network-name",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nadNotFound(validNetworkName)).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:           "invalid VIPs in use",
+			edit:           func(ic *types.InstallConfig) { ic.Platform.Kubevirt.VIPsInUseCheck = true },
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.APIVIP: Invalid value: \"192.168.123.15\": IP is already in use on network valid-network-name",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				expectNetworkProbe(kubevirtClient, "probe api-vip failed\nprobe ingress-vip passed\n")
+			},
+		},
+		{
+			name:           "valid VIPs not in use",
+			edit:           func(ic *types.InstallConfig) { ic.Platform.Kubevirt.VIPsInUseCheck = true },
+			expectedError:  false,
+			expectedErrMsg: "",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				expectNetworkProbe(kubevirtClient, "probe api-vip passed\nprobe ingress-vip passed\n")
+			},
+		},
+		{
+			name: "valid name prefix",
+			edit: func(ic *types.InstallConfig) {
+				ic.Compute = []types.MachinePool{{Name: "worker", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{NamePrefix: "site"}}}}
+			},
+			expectedError:  false,
+			expectedErrMsg: "",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListAllVirtualMachineNames(gomock.Any(), validNamespace).Return([]string{"other-worker-0"}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid name prefix collides with existing VM",
+			edit: func(ic *types.InstallConfig) {
+				ic.Compute = []types.MachinePool{{Name: "worker", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{NamePrefix: "site"}}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "compute\\[0\\].platform.kubevirt.namePrefix: Invalid value: \"site\": the VM names of machine pool worker collide with the existing VM site-worker-0-abcde in namespace valid-namespace",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListAllVirtualMachineNames(gomock.Any(), validNamespace).Return([]string{"site-worker-0-abcde"}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid control plane infra cluster",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{InfraContext: "infra-b"}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "controlPlane.platform.kubevirt.infraKubeconfigPath: Invalid value: \"\": the control plane machine pool does not support its own infra cluster",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid compute infra kubeconfig",
+			edit: func(ic *types.InstallConfig) {
+				ic.Compute = []types.MachinePool{{Name: "worker", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{InfraKubeconfigPath: "/nonexistent/kubeconfig"}}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "compute\\[0\\].platform.kubevirt.infraKubeconfigPath: Invalid value: \"/nonexistent/kubeconfig\": failed to load kubeconfig /nonexistent/kubeconfig",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid etcd disk storage class",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{EtcdDisk: &kubevirt.EtcdDisk{Size: "10Gi", StorageClass: "fast-ssd"}}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "controlPlane.platform.kubevirt.etcdDisk.storageClass: Invalid value: \"fast-ssd\": failed to get storageClass fast-ssd from InfraCluster, with error: test",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), "fast-ssd").Return(nil, fmt.Errorf("test")).AnyTimes()
+			},
+		},
+		{
+			name: "valid node labels",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt.NodeLabels = []kubevirt.NodeLabelMapping{{InfraLabel: "example.com/rack"}}
+			},
+			expectedError: false,
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{labeledNode(map[string]string{"example.com/rack": "r1"})}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid node label missing on the infra nodes",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt.NodeLabels = []kubevirt.NodeLabelMapping{{InfraLabel: "example.com/rack"}, {InfraLabel: "example.com/room", TenantLabel: "example.com/site"}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "platform.kubevirt.nodeLabels\\[1\\].infraLabel: Invalid value: \"example.com/room\": no infra cluster node has the label",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{labeledNode(map[string]string{"example.com/rack": "r1"}), labeledNode(nil)}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid hugepages not advertised",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{Memory: "16Gi", Hugepages: &kubevirt.Hugepages{PageSize: "1Gi"}}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "controlPlane.platform.kubevirt.hugepages.pageSize: Invalid value: \"1Gi\": no infra cluster node advertises hugepages-1Gi hugepages",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{hugepagesNode("hugepages-2Mi", "32Gi")}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "invalid hugepages not enough",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{Memory: "16Gi", Hugepages: &kubevirt.Hugepages{PageSize: "1Gi"}}}}
+			},
+			expectedError:  true,
+			expectedErrMsg: "controlPlane.platform.kubevirt.hugepages.pageSize: Invalid value: \"1Gi\": no infra cluster node has 16Gi of allocatable hugepages-1Gi hugepages",
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{hugepagesNode("hugepages-1Gi", "8Gi")}, nil).AnyTimes()
+			},
+		},
+		{
+			name: "valid hugepages",
+			edit: func(ic *types.InstallConfig) {
+				ic.ControlPlane = &types.MachinePool{Name: "master", Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{Hugepages: &kubevirt.Hugepages{PageSize: "1Gi", Count: 16}}}}
+			},
+			expectClient: func(kubevirtClient *mock.MockClient) {
+				kubevirtClient.EXPECT().ListNamespace(gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetNetworkAttachmentDefinition(gomock.Any(), validNetworkName, validNamespace).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().GetStorageClass(gomock.Any(), validStorageClass).Return(nil, nil).AnyTimes()
+				kubevirtClient.EXPECT().ListNodes(gomock.Any()).Return([]corev1.Node{hugepagesNode("hugepages-1Gi", "8Gi"), hugepagesNode("hugepages-1Gi", "64Gi")}, nil).AnyTimes()
+			},
+		},
 	}
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -182,6 +504,7 @@ func TestKubevirtInstallConfigValidation(t *testing.T) {
 			if tc.expectClient != nil {
 				tc.expectClient(kubevirtClient)
 			}
+			kubevirtClient.EXPECT().ListResourceQuotas(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 			errs := Validate(installConfig, func() (Client, error) { return kubevirtClient, tc.clientBuilderErr })
 			if tc.expectedError {
@@ -192,3 +515,49 @@ func TestKubevirtInstallConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkAttachmentDefinition(t *testing.T) {
+	cases := []struct {
+		name     string
+		template kubevirt.NetworkTemplate
+		expected string
+	}{
+		{
+			name:     "bridge",
+			template: kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1"},
+			expected: `{"bridge":"br1","cniVersion":"0.3.1","name":"valid-network-name","type":"bridge"}`,
+		},
+		{
+			name:     "bridge with CIDR and VLAN",
+			template: kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: validMachineCIDR, VLAN: 100},
+			expected: `{"bridge":"br1","cniVersion":"0.3.1","ipam":{"range":"192.168.123.0/24","type":"whereabouts"},"name":"valid-network-name","type":"bridge","vlan":100}`,
+		},
+		{
+			name:     "ovn layer2",
+			template: kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeOVN, CIDR: validMachineCIDR},
+			expected: `{"cniVersion":"0.3.1","name":"valid-network-name","netAttachDefName":"valid-namespace/valid-network-name","subnets":"192.168.123.0/24","topology":"layer2","type":"ovn-k8s-cni-overlay"}`,
+		},
+		{
+			name:     "ovn localnet",
+			template: kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeOVN, VLAN: 100},
+			expected: `{"cniVersion":"0.3.1","name":"valid-network-name","netAttachDefName":"valid-namespace/valid-network-name","topology":"localnet","type":"ovn-k8s-cni-overlay","vlanID":100}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			platform := validInstallConfig().Platform.Kubevirt
+			platform.CreateNetwork = &tc.template
+			labels := map[string]string{"tenantcluster-test-infra-id-machine.openshift.io": "owned"}
+			nad, err := NetworkAttachmentDefinition(platform, labels)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "k8s.cni.cncf.io/v1", nad.GetAPIVersion())
+			assert.Equal(t, validNamespace, nad.GetNamespace())
+			assert.Equal(t, validNetworkName, nad.GetName())
+			assert.Equal(t, labels, nad.GetLabels())
+			config, _, _ := unstructured.NestedString(nad.Object, "spec", "config")
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
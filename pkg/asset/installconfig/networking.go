@@ -13,7 +13,10 @@ type networking struct {
 	machineNetwork []types.MachineNetworkEntry
 }
 
-var _ asset.Asset = (*networking)(nil)
+var _ asset.Interactive = (*networking)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *networking) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *networking) Dependencies() []asset.Asset {
@@ -36,7 +36,10 @@ type platform struct {
 	types.Platform
 }
 
-var _ asset.Asset = (*platform)(nil)
+var _ asset.Interactive = (*platform)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *platform) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *platform) Dependencies() []asset.Asset {
@@ -27,7 +27,10 @@ import (
 type PlatformCredsCheck struct {
 }
 
-var _ asset.Asset = (*PlatformCredsCheck)(nil)
+var _ asset.Interactive = (*PlatformCredsCheck)(nil)
+
+// Interactive marks the asset as interactive, it asks for the platform credentials when missing.
+func (a *PlatformCredsCheck) Interactive() {}
 
 // Dependencies returns the dependencies for PlatformCredsCheck
 func (a *PlatformCredsCheck) Dependencies() []asset.Asset {
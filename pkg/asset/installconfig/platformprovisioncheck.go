@@ -3,12 +3,17 @@ package installconfig
 import (
 	"context"
 	"fmt"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
 	azconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	bmconfig "github.com/openshift/installer/pkg/asset/installconfig/baremetal"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
 	vsconfig "github.com/openshift/installer/pkg/asset/installconfig/vsphere"
+	"github.com/openshift/installer/pkg/dnscheck"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/baremetal"
@@ -21,6 +26,14 @@ import (
 	"github.com/openshift/installer/pkg/types/vsphere"
 )
 
+// skipDNSCheck is set with SetSkipDNSCheck.
+var skipDNSCheck bool
+
+// SetSkipDNSCheck skips the check of the DNS of the base domain before provisioning.
+func SetSkipDNSCheck(skip bool) {
+	skipDNSCheck = skip
+}
+
 // PlatformProvisionCheck is an asset that validates the install-config platform for
 // any requirements specific for provisioning infrastructure.
 type PlatformProvisionCheck struct {
@@ -40,6 +53,10 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 	ic := &InstallConfig{}
 	dependencies.Get(ic)
 
+	if err := checkDNS(ic); err != nil {
+		return err
+	}
+
 	var err error
 	platform := ic.Config.Platform.Name()
 	switch platform {
@@ -80,13 +97,31 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 		// no special provisioning requirements to check
 	case kubevirt.Name:
 		// no special provisioning requirements to check
-		// TODO <nargaman> need to validate public DNS?
 	default:
 		err = fmt.Errorf("unknown platform type %q", platform)
 	}
 	return err
 }
 
+// checkDNS checks the delegation of the base domain and the records of the cluster, unless
+// skipped.
+func checkDNS(ic *InstallConfig) error {
+	if skipDNSCheck {
+		logrus.Warn("Skipping the DNS check of the base domain")
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+	warnings, err := dnscheck.Check(ctx, net.DefaultResolver, ic.Config)
+	for _, warning := range warnings {
+		logrus.Warn(warning)
+	}
+	if err != nil {
+		return fmt.Errorf("%v (skip this check with --skip-dns-check)", err)
+	}
+	return nil
+}
+
 // Name returns the human-friendly name of the asset.
 func (a *PlatformProvisionCheck) Name() string {
 	return "Platform Provisioning Check"
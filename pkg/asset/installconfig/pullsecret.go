@@ -1,9 +1,14 @@
 package installconfig
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
 	survey "gopkg.in/AlecAivazis/survey.v1"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/validation"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -11,7 +16,10 @@ type pullSecret struct {
 	PullSecret string
 }
 
-var _ asset.Asset = (*pullSecret)(nil)
+var _ asset.Interactive = (*pullSecret)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *pullSecret) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *pullSecret) Dependencies() []asset.Asset {
@@ -37,3 +45,50 @@ func (a *pullSecret) Generate(asset.Parents) error {
 func (a *pullSecret) Name() string {
 	return "Pull Secret"
 }
+
+// ScopedPullSecret returns the pull secret to embed into the cluster. When the install-config
+// scopes it, only the credentials of the registries of the release image, of its mirrors and of
+// the additional registries of the scope are kept; otherwise it is the pull secret as is.
+func ScopedPullSecret(config *types.InstallConfig, releaseImage string) (string, error) {
+	if config.PullSecretScope == nil {
+		return config.PullSecret, nil
+	}
+	repositories := validation.ReleaseImageRepositories(releaseImage, config.ImageContentSources)
+	repositories = append(repositories, config.PullSecretScope.AdditionalRegistries...)
+
+	// The other fields of the pull secret and of its entries are kept as they are
+	var secret map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config.PullSecret), &secret); err != nil {
+		return "", errors.Wrap(err, "failed to parse the pull secret")
+	}
+	var auths map[string]json.RawMessage
+	if err := json.Unmarshal(secret["auths"], &auths); err != nil {
+		return "", errors.Wrap(err, "failed to parse the auths of the pull secret")
+	}
+	for key := range auths {
+		if !coversAny(key, repositories) {
+			delete(auths, key)
+		}
+	}
+	data, err := json.Marshal(auths)
+	if err != nil {
+		return "", err
+	}
+	secret["auths"] = data
+	data, err = json.Marshal(secret)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// coversAny returns whether the credentials of the pull secret entry with the given key apply to
+// any of the repositories.
+func coversAny(key string, repositories []string) bool {
+	for _, repository := range repositories {
+		if validate.ImagePullSecretCovers(key, repository) {
+			return true
+		}
+	}
+	return false
+}
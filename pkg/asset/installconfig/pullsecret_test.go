@@ -0,0 +1,50 @@
+package installconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestScopedPullSecret(t *testing.T) {
+	pullSecret := `{"auths":{"cloud.openshift.com":{"auth":"dXNlcjpwYXNzd29yZA==","email":"user@example.com"},"mirror.example.com":{"auth":"dXNlcjpwYXNzd29yZA=="},"quay.io":{"auth":"dXNlcjpwYXNzd29yZA=="},"registry.redhat.io":{"auth":"dXNlcjpwYXNzd29yZA=="}},"credHelpers":{}}`
+	releaseImage := "quay.io/openshift-release-dev/ocp-release:4.7"
+
+	cases := []struct {
+		name     string
+		scope    *types.PullSecretScope
+		sources  []types.ImageContentSource
+		expected string
+	}{
+		{
+			name:     "not scoped",
+			expected: pullSecret,
+		},
+		{
+			name:     "release image",
+			scope:    &types.PullSecretScope{},
+			expected: `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNzd29yZA=="}},"credHelpers":{}}`,
+		},
+		{
+			name:     "mirrors and additional registries",
+			scope:    &types.PullSecretScope{AdditionalRegistries: []string{"cloud.openshift.com"}},
+			sources:  []types.ImageContentSource{{Source: "quay.io/openshift-release-dev", Mirrors: []string{"mirror.example.com/ocp"}}},
+			expected: `{"auths":{"cloud.openshift.com":{"auth":"dXNlcjpwYXNzd29yZA==","email":"user@example.com"},"mirror.example.com":{"auth":"dXNlcjpwYXNzd29yZA=="},"quay.io":{"auth":"dXNlcjpwYXNzd29yZA=="}},"credHelpers":{}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				PullSecret:          pullSecret,
+				PullSecretScope:     tc.scope,
+				ImageContentSources: tc.sources,
+			}
+			scoped, err := ScopedPullSecret(config, releaseImage)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, scoped)
+			}
+		})
+	}
+}
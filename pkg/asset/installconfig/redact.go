@@ -0,0 +1,64 @@
+package installconfig
+
+import (
+	"net/url"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+// redactedValue replaces the secrets of the install-config shown to users.
+const redactedValue = "REDACTED"
+
+// RedactedYAML returns the YAML of the install-config with the pull secret, the passwords of
+// the proxies and the platform credentials redacted, so that it can be shown to users.
+func RedactedYAML(config types.InstallConfig) ([]byte, error) {
+	config.PullSecret = redact(config.PullSecret)
+	if config.Proxy != nil {
+		p := *config.Proxy
+		p.HTTPProxy = redactURL(p.HTTPProxy)
+		p.HTTPSProxy = redactURL(p.HTTPSProxy)
+		config.Proxy = &p
+	}
+	if config.Platform.VSphere != nil {
+		p := *config.Platform.VSphere
+		p.Username = redact(p.Username)
+		p.Password = redact(p.Password)
+		config.Platform.VSphere = &p
+	}
+	if config.Platform.BareMetal != nil {
+		p := *config.Platform.BareMetal
+		p.Hosts = make([]*baremetal.Host, len(config.Platform.BareMetal.Hosts))
+		for i, host := range config.Platform.BareMetal.Hosts {
+			h := *host
+			h.BMC.Username = redact(h.BMC.Username)
+			h.BMC.Password = redact(h.BMC.Password)
+			p.Hosts[i] = &h
+		}
+		config.Platform.BareMetal = &p
+	}
+	return yaml.Marshal(config)
+}
+
+// redact returns redactedValue for set values, keeping unset ones empty.
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// redactURL returns the URL with the password of its user info redacted.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); !ok {
+		return value
+	}
+	u.User = url.UserPassword(u.User.Username(), redactedValue)
+	return u.String()
+}
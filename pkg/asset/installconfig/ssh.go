@@ -22,7 +22,10 @@ type sshPublicKey struct {
 	Key string
 }
 
-var _ asset.Asset = (*sshPublicKey)(nil)
+var _ asset.Interactive = (*sshPublicKey)(nil)
+
+// Interactive marks the asset as interactive, it prompts the user.
+func (a *sshPublicKey) Interactive() {}
 
 // Dependencies returns no dependencies.
 func (a *sshPublicKey) Dependencies() []asset.Asset {
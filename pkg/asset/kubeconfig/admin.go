@@ -35,8 +35,20 @@ func (k *AdminClient) Generate(parents asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	parents.Get(ca, clientCertKey, installConfig)
 
+	// The CA of the named serving certificate of the API server is trusted as well, the
+	// certificate being served for the external names of the API once the cluster is up
+	var caBundle tls.CertInterface = ca
+	certificate, err := installconfig.ReadAPIServerCertificate(installConfig.Config)
+	if err != nil {
+		return err
+	}
+	if certificate != nil {
+		bundle := append(append([]byte{}, ca.Cert()...), certificate.CA...)
+		caBundle = &tls.CertBundle{BundleRaw: bundle}
+	}
+
 	return k.kubeconfig.generate(
-		ca,
+		caBundle,
 		clientCertKey,
 		getExtAPIServerURL(installConfig.Config),
 		installConfig.Config.GetName(),
@@ -2,9 +2,11 @@
 package kubevirt
 
 import (
+	"encoding/json"
 	"fmt"
 
 	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -28,7 +30,11 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	if pool.Replicas != nil {
 		total = *pool.Replicas
 	}
-	provider := provider(clusterID, platform, pool, userDataSecret, osImage)
+	providerSpec, err := providerSpecValue(provider(clusterID, platform, pool, userDataSecret, osImage), pool.Platform.Kubevirt.ProviderSpecOverrides)
+	if err != nil {
+		return nil, err
+	}
+	prefix := pool.Platform.Kubevirt.ResourceNamePrefix(clusterID)
 	var machines []machineapi.Machine
 	for idx := int64(0); idx < total; idx++ {
 		machine := machineapi.Machine{
@@ -38,7 +44,7 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 			},
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "openshift-machine-api",
-				Name:      fmt.Sprintf("%s-%s-%d", clusterID, pool.Name, idx),
+				Name:      kubevirt.ResourceName(prefix, pool.Name, int(idx)),
 				Labels: map[string]string{
 					"machine.openshift.io/cluster-api-cluster":      clusterID,
 					"machine.openshift.io/cluster-api-machine-role": role,
@@ -47,7 +53,7 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 			},
 			Spec: machineapi.MachineSpec{
 				ProviderSpec: machineapi.ProviderSpec{
-					Value: &runtime.RawExtension{Object: provider},
+					Value: providerSpec,
 				},
 				// we don't need to set Versions, because we control those via cluster operators.
 			},
@@ -65,7 +71,7 @@ func provider(clusterID string, platform *kubevirt.Platform, pool *types.Machine
 			Kind:       "KubevirtMachineProviderSpec",
 		},
 		SourcePvcName:              fmt.Sprintf("%s-source-pvc", clusterID),
-		RequestedMemory:            pool.Platform.Kubevirt.Memory,
+		RequestedMemory:            pool.Platform.Kubevirt.VMMemory(),
 		RequestedCPU:               pool.Platform.Kubevirt.CPU,
 		RequestedStorage:           pool.Platform.Kubevirt.StorageSize,
 		StorageClassName:           platform.StorageClass,
@@ -73,5 +79,66 @@ func provider(clusterID string, platform *kubevirt.Platform, pool *types.Machine
 		NetworkName:                platform.NetworkName,
 		PersistentVolumeAccessMode: platform.PersistentVolumeAccessMode,
 	}
+	if pool.Platform.Kubevirt.HasInfraCluster() {
+		spec.CredentialsSecretName = kubevirt.CredentialsSecretName(pool.Name)
+	}
 	return &spec
 }
+
+// providerSpecValue returns the provider spec with the overrides deep-merged into it. The
+// merged spec is kept raw, so that the fields unknown to the installer reach the provider, and
+// decoded, so that the overrides of the known fields apply to the installer as well.
+func providerSpecValue(spec *kubevirtprovider.KubevirtMachineProviderSpec, overrides map[string]interface{}) (*runtime.RawExtension, error) {
+	if len(overrides) == 0 {
+		return &runtime.RawExtension{Object: spec}, nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(mergeObjects(object, overrides))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge the provider spec overrides")
+	}
+	merged := &kubevirtprovider.KubevirtMachineProviderSpec{}
+	if err := json.Unmarshal(raw, merged); err != nil {
+		return nil, errors.Wrap(err, "invalid provider spec overrides")
+	}
+	return &runtime.RawExtension{Raw: raw, Object: merged}, nil
+}
+
+// mergeObjects deep-merges the overrides into the object, as a JSON merge patch: objects are
+// merged key by key, any other value replaces the existing one and null removes it.
+func mergeObjects(object map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
+	for key, value := range overrides {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		existing, existingIsObject := object[key].(map[string]interface{})
+		override, overrideIsObject := value.(map[string]interface{})
+		if existingIsObject && overrideIsObject {
+			object[key] = mergeObjects(existing, override)
+			continue
+		}
+		if overrideIsObject {
+			value = mergeObjects(map[string]interface{}{}, override)
+		}
+		object[key] = value
+	}
+	return object
+}
+
+// EtcdDiskDevice returns the device of the dedicated etcd disk in the VMs of the pool. The etcd
+// disk is the virtio disk attached right after the boot disk, so it is the second virtio disk
+// when the boot disk is a virtio one as well, and the first one otherwise.
+func EtcdDiskDevice(pool *kubevirt.MachinePool) string {
+	if pool.DiskBus == "" || pool.DiskBus == kubevirt.DiskBusVirtio {
+		return "/dev/vdb"
+	}
+	return "/dev/vda"
+}
@@ -0,0 +1,88 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+)
+
+func TestProviderSpecValue(t *testing.T) {
+	spec := func() *kubevirtprovider.KubevirtMachineProviderSpec {
+		return &kubevirtprovider.KubevirtMachineProviderSpec{
+			SourcePvcName:      "infra-id-source-pvc",
+			RequestedMemory:    "8G",
+			RequestedCPU:       4,
+			RequestedStorage:   "120Gi",
+			StorageClassName:   "fast-ssd",
+			IgnitionSecretName: "worker-user-data-managed",
+			NetworkName:        "tenant-network",
+		}
+	}
+
+	cases := []struct {
+		name         string
+		overrides    map[string]interface{}
+		expectedRaw  string
+		expectedSpec *kubevirtprovider.KubevirtMachineProviderSpec
+		expectedErr  string
+	}{
+		{
+			name:         "no overrides",
+			expectedSpec: spec(),
+		},
+		{
+			name: "overrides",
+			overrides: map[string]interface{}{
+				"requestedStorage": "200Gi",
+				"storageClassName": nil,
+				"dedicatedCPUs":    true,
+				"nodeSelector":     map[string]interface{}{"kubernetes.io/arch": "amd64"},
+			},
+			expectedRaw: `{"dedicatedCPUs":true,"ignitionSecretName":"worker-user-data-managed","networkName":"tenant-network","nodeSelector":{"kubernetes.io/arch":"amd64"},"requestedCPU":4,"requestedMemory":"8G","requestedStorage":"200Gi","sourcePvcName":"infra-id-source-pvc"}`,
+			expectedSpec: func() *kubevirtprovider.KubevirtMachineProviderSpec {
+				s := spec()
+				s.RequestedStorage = "200Gi"
+				s.StorageClassName = ""
+				return s
+			}(),
+		},
+		{
+			name:        "invalid override of a known field",
+			overrides:   map[string]interface{}{"requestedCPU": "four"},
+			expectedErr: "invalid provider spec overrides: json: cannot unmarshal string into Go struct field KubevirtMachineProviderSpec.requestedCPU of type uint32",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := providerSpecValue(spec(), tc.overrides)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedRaw, string(value.Raw))
+			assert.Equal(t, tc.expectedSpec, value.Object)
+		})
+	}
+}
+
+func TestMergeObjects(t *testing.T) {
+	object := map[string]interface{}{
+		"a": map[string]interface{}{"b": "c", "d": "e"},
+		"f": []interface{}{"g"},
+	}
+	overrides := map[string]interface{}{
+		"a": map[string]interface{}{"d": nil, "h": "i"},
+		"f": []interface{}{"j"},
+		"k": map[string]interface{}{"l": nil, "m": "n"},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"b": "c", "h": "i"},
+		"f": []interface{}{"j"},
+		"k": map[string]interface{}{"m": "n"},
+	}, mergeObjects(object, overrides))
+}
@@ -6,7 +6,6 @@ import (
 
 	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
 	"github.com/openshift/installer/pkg/types"
@@ -30,8 +29,11 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 		total = *pool.Replicas
 	}
 
-	provider := provider(clusterID, platform, pool, userDataSecret, osImage)
-	name := fmt.Sprintf("%s-%s-%d", clusterID, pool.Name, 0)
+	providerSpec, err := providerSpecValue(provider(clusterID, platform, pool, userDataSecret, osImage), pool.Platform.Kubevirt.ProviderSpecOverrides)
+	if err != nil {
+		return nil, err
+	}
+	name := kubevirt.ResourceName(pool.Platform.Kubevirt.ResourceNamePrefix(clusterID), pool.Name, 0)
 	mset := &machineapi.MachineSet{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "machine.openshift.io/v1beta1",
@@ -65,7 +67,7 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 				},
 				Spec: machineapi.MachineSpec{
 					ProviderSpec: machineapi.ProviderSpec{
-						Value: &runtime.RawExtension{Object: provider},
+						Value: providerSpec,
 					},
 					// we don't need to set Versions, because we control those via cluster operators.
 				},
@@ -0,0 +1,263 @@
+package machineconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	ignvalidate "github.com/coreos/ignition/v2/config/validate"
+	"github.com/ghodss/yaml"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/vincent-petithory/dataurl"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+const (
+	// ButaneVariant is the Butane variant of the configs transpiled to MachineConfigs.
+	ButaneVariant = "openshift"
+
+	roleLabel = "machineconfiguration.openshift.io/role"
+)
+
+// ButaneVersions are the spec versions of the Butane openshift variant which can be
+// transpiled. Their files, directories, links, systemd units, users and openshift sections
+// are rendered to Ignition 3.1 configs.
+var ButaneVersions = []string{"4.8.0"}
+
+// butaneConfig is the subset of the Butane openshift variant supported by the installer.
+type butaneConfig struct {
+	Variant  string `json:"variant"`
+	Version  string `json:"version"`
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Storage struct {
+		Directories []butaneDirectory `json:"directories,omitempty"`
+		Files       []butaneFile      `json:"files,omitempty"`
+		Links       []butaneLink      `json:"links,omitempty"`
+	} `json:"storage,omitempty"`
+	Systemd struct {
+		Units []butaneUnit `json:"units,omitempty"`
+	} `json:"systemd,omitempty"`
+	Passwd struct {
+		Users []butaneUser `json:"users,omitempty"`
+	} `json:"passwd,omitempty"`
+	OpenShift struct {
+		KernelArguments []string `json:"kernel_arguments,omitempty"`
+		Extensions      []string `json:"extensions,omitempty"`
+		FIPS            *bool    `json:"fips,omitempty"`
+		KernelType      string   `json:"kernel_type,omitempty"`
+	} `json:"openshift,omitempty"`
+}
+
+type butaneNodeOwner struct {
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type butaneNode struct {
+	Path      string          `json:"path"`
+	Overwrite *bool           `json:"overwrite,omitempty"`
+	User      butaneNodeOwner `json:"user,omitempty"`
+	Group     butaneNodeOwner `json:"group,omitempty"`
+}
+
+type butaneDirectory struct {
+	butaneNode
+	Mode *int `json:"mode,omitempty"`
+}
+
+type butaneResource struct {
+	Inline       *string `json:"inline,omitempty"`
+	Source       *string `json:"source,omitempty"`
+	Compression  *string `json:"compression,omitempty"`
+	Verification struct {
+		Hash *string `json:"hash,omitempty"`
+	} `json:"verification,omitempty"`
+}
+
+type butaneFile struct {
+	butaneNode
+	Contents butaneResource   `json:"contents,omitempty"`
+	Append   []butaneResource `json:"append,omitempty"`
+	Mode     *int             `json:"mode,omitempty"`
+}
+
+type butaneLink struct {
+	butaneNode
+	Target string `json:"target"`
+	Hard   *bool  `json:"hard,omitempty"`
+}
+
+type butaneDropin struct {
+	Name     string  `json:"name"`
+	Contents *string `json:"contents,omitempty"`
+}
+
+type butaneUnit struct {
+	Name     string         `json:"name"`
+	Enabled  *bool          `json:"enabled,omitempty"`
+	Mask     *bool          `json:"mask,omitempty"`
+	Contents *string        `json:"contents,omitempty"`
+	Dropins  []butaneDropin `json:"dropins,omitempty"`
+}
+
+type butaneUser struct {
+	Name              string   `json:"name"`
+	SSHAuthorizedKeys []string `json:"ssh_authorized_keys,omitempty"`
+}
+
+// FromButane transpiles the Butane config of the openshift variant to a MachineConfig.
+// The fields not supported by the installer are rejected rather than ignored.
+func FromButane(data []byte) (*mcfgv1.MachineConfig, error) {
+	var config butaneConfig
+	err := yaml.Unmarshal(data, &config, func(d *json.Decoder) *json.Decoder {
+		d.DisallowUnknownFields()
+		return d
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Butane config: %v", err)
+	}
+
+	if config.Variant != ButaneVariant {
+		return nil, fmt.Errorf("unsupported Butane variant %q, must be %s", config.Variant, ButaneVariant)
+	}
+	if !supportedButaneVersion(config.Version) {
+		return nil, fmt.Errorf("unsupported Butane spec version %q, must be one of %v", config.Version, ButaneVersions)
+	}
+	if config.Metadata.Name == "" {
+		return nil, fmt.Errorf("metadata.name is required")
+	}
+	if config.Metadata.Labels[roleLabel] == "" {
+		return nil, fmt.Errorf("the %s label is required", roleLabel)
+	}
+	switch config.OpenShift.KernelType {
+	case "", "default", "realtime":
+	default:
+		return nil, fmt.Errorf("unsupported kernel_type %q, must be default or realtime", config.OpenShift.KernelType)
+	}
+
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+	}
+	for _, d := range config.Storage.Directories {
+		ignConfig.Storage.Directories = append(ignConfig.Storage.Directories, igntypes.Directory{
+			Node:               d.node(),
+			DirectoryEmbedded1: igntypes.DirectoryEmbedded1{Mode: d.Mode},
+		})
+	}
+	for _, f := range config.Storage.Files {
+		file := igntypes.File{
+			Node:          f.node(),
+			FileEmbedded1: igntypes.FileEmbedded1{Mode: f.Mode},
+		}
+		if file.Contents, err = f.Contents.resource(); err != nil {
+			return nil, fmt.Errorf("file %s: %v", f.Path, err)
+		}
+		for _, a := range f.Append {
+			resource, err := a.resource()
+			if err != nil {
+				return nil, fmt.Errorf("file %s: %v", f.Path, err)
+			}
+			file.Append = append(file.Append, resource)
+		}
+		ignConfig.Storage.Files = append(ignConfig.Storage.Files, file)
+	}
+	for _, l := range config.Storage.Links {
+		ignConfig.Storage.Links = append(ignConfig.Storage.Links, igntypes.Link{
+			Node:          l.node(),
+			LinkEmbedded1: igntypes.LinkEmbedded1{Target: l.Target, Hard: l.Hard},
+		})
+	}
+	for _, u := range config.Systemd.Units {
+		unit := igntypes.Unit{
+			Name:     u.Name,
+			Enabled:  u.Enabled,
+			Mask:     u.Mask,
+			Contents: u.Contents,
+		}
+		for _, d := range u.Dropins {
+			unit.Dropins = append(unit.Dropins, igntypes.Dropin{Name: d.Name, Contents: d.Contents})
+		}
+		ignConfig.Systemd.Units = append(ignConfig.Systemd.Units, unit)
+	}
+	for _, u := range config.Passwd.Users {
+		if u.Name != "core" {
+			return nil, fmt.Errorf("user %s: only the core user can be configured", u.Name)
+		}
+		user := igntypes.PasswdUser{Name: u.Name}
+		for _, key := range u.SSHAuthorizedKeys {
+			user.SSHAuthorizedKeys = append(user.SSHAuthorizedKeys, igntypes.SSHAuthorizedKey(key))
+		}
+		ignConfig.Passwd.Users = append(ignConfig.Passwd.Users, user)
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+	if report := ignvalidate.ValidateWithContext(ignConfig, rawExt.Raw); report.IsFatal() {
+		return nil, fmt.Errorf("invalid Ignition config: %s", report.String())
+	}
+
+	machineConfig := &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   config.Metadata.Name,
+			Labels: config.Metadata.Labels,
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config:          rawExt,
+			KernelArguments: config.OpenShift.KernelArguments,
+			Extensions:      config.OpenShift.Extensions,
+			KernelType:      config.OpenShift.KernelType,
+		},
+	}
+	if config.OpenShift.FIPS != nil {
+		machineConfig.Spec.FIPS = *config.OpenShift.FIPS
+	}
+	return machineConfig, nil
+}
+
+func supportedButaneVersion(version string) bool {
+	for _, v := range ButaneVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (n butaneNode) node() igntypes.Node {
+	return igntypes.Node{
+		Path:      n.Path,
+		Overwrite: n.Overwrite,
+		User:      igntypes.NodeUser{ID: n.User.ID, Name: n.User.Name},
+		Group:     igntypes.NodeGroup{ID: n.Group.ID, Name: n.Group.Name},
+	}
+}
+
+// resource converts the Butane resource, encoding its inline contents in a data URL.
+func (r butaneResource) resource() (igntypes.Resource, error) {
+	resource := igntypes.Resource{
+		Source:       r.Source,
+		Compression:  r.Compression,
+		Verification: igntypes.Verification{Hash: r.Verification.Hash},
+	}
+	if r.Inline != nil {
+		if r.Source != nil {
+			return resource, fmt.Errorf("inline and source cannot both be set")
+		}
+		source := dataurl.EncodeBytes([]byte(*r.Inline))
+		resource.Source = &source
+	}
+	return resource, nil
+}
@@ -0,0 +1,142 @@
+package machineconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromButane(t *testing.T) {
+	cases := []struct {
+		name        string
+		butane      string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			butane: `variant: openshift
+version: 4.8.0
+metadata:
+  name: 99-worker-chrony
+  labels:
+    machineconfiguration.openshift.io/role: worker
+storage:
+  files:
+  - path: /etc/chrony.conf
+    mode: 0644
+    overwrite: true
+    contents:
+      inline: |
+        pool 0.rhel.pool.ntp.org iburst
+systemd:
+  units:
+  - name: chronyd.service
+    enabled: true
+openshift:
+  kernel_arguments:
+  - loglevel=7
+  fips: true
+`,
+		},
+		{
+			name: "unsupported variant",
+			butane: `variant: fcos
+version: 1.1.0
+`,
+			expectedErr: `unsupported Butane variant "fcos", must be openshift`,
+		},
+		{
+			name: "unsupported version",
+			butane: `variant: openshift
+version: 4.9.0-experimental
+`,
+			expectedErr: `unsupported Butane spec version "4.9.0-experimental", must be one of [4.8.0]`,
+		},
+		{
+			name: "missing role",
+			butane: `variant: openshift
+version: 4.8.0
+metadata:
+  name: 99-worker-chrony
+`,
+			expectedErr: "the machineconfiguration.openshift.io/role label is required",
+		},
+		{
+			name: "unsupported field",
+			butane: `variant: openshift
+version: 4.8.0
+metadata:
+  name: 99-worker-disks
+  labels:
+    machineconfiguration.openshift.io/role: worker
+storage:
+  disks:
+  - device: /dev/vdb
+`,
+			expectedErr: `failed to parse the Butane config: error unmarshaling JSON: while decoding JSON: json: unknown field "disks"`,
+		},
+		{
+			name: "user other than core",
+			butane: `variant: openshift
+version: 4.8.0
+metadata:
+  name: 99-worker-users
+  labels:
+    machineconfiguration.openshift.io/role: worker
+passwd:
+  users:
+  - name: admin
+`,
+			expectedErr: "user admin: only the core user can be configured",
+		},
+		{
+			name: "relative path",
+			butane: `variant: openshift
+version: 4.8.0
+metadata:
+  name: 99-worker-relative
+  labels:
+    machineconfiguration.openshift.io/role: worker
+storage:
+  directories:
+  - path: etc/custom
+    mode: 0755
+`,
+			expectedErr: "invalid Ignition config: error at $.storage.directories.0.path, line 1 col 67: path not absolute\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machineConfig, err := FromButane([]byte(tc.butane))
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "99-worker-chrony", machineConfig.Name)
+			assert.Equal(t, "worker", machineConfig.Labels[roleLabel])
+			assert.Equal(t, []string{"loglevel=7"}, machineConfig.Spec.KernelArguments)
+			assert.True(t, machineConfig.Spec.FIPS)
+
+			var config igntypes.Config
+			if !assert.NoError(t, json.Unmarshal(machineConfig.Spec.Config.Raw, &config)) {
+				return
+			}
+			assert.Equal(t, igntypes.MaxVersion.String(), config.Ignition.Version)
+			if assert.Len(t, config.Storage.Files, 1) {
+				file := config.Storage.Files[0]
+				assert.Equal(t, "/etc/chrony.conf", file.Path)
+				assert.Equal(t, 0644, *file.Mode)
+				assert.Equal(t, "data:text/plain;charset=utf-8;base64,cG9vbCAwLnJoZWwucG9vbC5udHAub3JnIGlidXJzdAo=", *file.Contents.Source)
+			}
+			if assert.Len(t, config.Systemd.Units, 1) {
+				assert.Equal(t, "chronyd.service", config.Systemd.Units[0].Name)
+				assert.True(t, *config.Systemd.Units[0].Enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package machineconfig
+
+import (
+	"fmt"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+const etcdDiskLabel = "etcd"
+
+// etcdDiskMountUnit mounts the etcd disk by its label, so that the mount does not depend on
+// the name of its device once the disk is formatted.
+var etcdDiskMountUnit = fmt.Sprintf(`[Unit]
+Description=Mount the dedicated etcd disk at /var/lib/etcd
+Before=local-fs.target
+
+[Mount]
+What=/dev/disk/by-label/%s
+Where=/var/lib/etcd
+Type=xfs
+Options=defaults,prjquota
+
+[Install]
+WantedBy=local-fs.target
+`, etcdDiskLabel)
+
+// etcdDiskRelabelUnit restores the SELinux context of /var/lib/etcd, which is lost when the
+// disk is mounted over it.
+const etcdDiskRelabelUnit = `[Unit]
+Description=Restore the SELinux context of /var/lib/etcd
+Requires=var-lib-etcd.mount
+After=var-lib-etcd.mount
+Before=kubelet.service
+
+[Service]
+Type=oneshot
+ExecStart=/sbin/restorecon -R /var/lib/etcd
+RemainAfterExit=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ForEtcdDisk creates the MachineConfig to format the disk of the device as an XFS filesystem
+// and to mount it at /var/lib/etcd.
+func ForEtcdDisk(role string, device string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Filesystems: []igntypes.Filesystem{{
+				Device: device,
+				Format: ignutil.StrToPtr("xfs"),
+				Label:  ignutil.StrToPtr(etcdDiskLabel),
+			}},
+		},
+		Systemd: igntypes.Systemd{
+			Units: []igntypes.Unit{
+				{
+					Name:     "var-lib-etcd.mount",
+					Enabled:  ignutil.BoolToPtr(true),
+					Contents: ignutil.StrToPtr(etcdDiskMountUnit),
+				},
+				{
+					Name:     "etcd-disk-relabel.service",
+					Enabled:  ignutil.BoolToPtr(true),
+					Contents: ignutil.StrToPtr(etcdDiskRelabelUnit),
+				},
+			},
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-etcd-disk", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
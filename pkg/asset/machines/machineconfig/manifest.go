@@ -7,6 +7,7 @@ import (
 	"github.com/ghodss/yaml"
 
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/installer/pkg/asset"
 )
@@ -43,6 +44,24 @@ func Manifests(configs []*mcfgv1.MachineConfig, role, directory string) ([]*asse
 	return ret, nil
 }
 
+// PoolConfigManifests creates manifest files containing the KubeletConfigs and
+// ContainerRuntimeConfigs of the machine config pools. They are named like the MachineConfig
+// manifests, to be loaded with them.
+func PoolConfigManifests(configs []metav1.Object, directory string) ([]*asset.File, error) {
+	var ret []*asset.File
+	for _, c := range configs {
+		configData, err := yaml.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &asset.File{
+			Filename: filepath.Join(directory, fmt.Sprintf(machineConfigFileName, c.GetName())),
+			Data:     configData,
+		})
+	}
+	return ret, nil
+}
+
 // IsManifest tests whether the specified filename is a MachineConfig manifest.
 func IsManifest(filename string) (bool, error) {
 	matched, err := filepath.Match(machineConfigFileNamePattern, filename)
@@ -0,0 +1,90 @@
+package machineconfig
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// registriesDropIn returns the containers registries.conf drop-in pulling the images of the
+// sources by digest from their mirrors, as the registries.conf of the bootstrap machine does.
+func registriesDropIn(sources []types.ImageContentSource) string {
+	var config strings.Builder
+	for _, group := range ignition.MergedMirrorSets(sources) {
+		if len(group.Mirrors) == 0 {
+			continue
+		}
+		fmt.Fprintf(&config, "[[registry]]\nlocation = %q\nmirror-by-digest-only = true\n", group.Source)
+		for _, mirror := range group.Mirrors {
+			fmt.Fprintf(&config, "\n[[registry.mirror]]\nlocation = %q\n", mirror)
+		}
+		config.WriteString("\n")
+	}
+	return config.String()
+}
+
+// mirrorHosts returns the registry hosts of the mirrors of the sources, without duplicates.
+func mirrorHosts(sources []types.ImageContentSource) []string {
+	var hosts []string
+	seen := map[string]bool{}
+	for _, group := range sources {
+		for _, mirror := range group.Mirrors {
+			host := strings.SplitN(mirror, "/", 2)[0]
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts
+}
+
+// ForMirrorRegistries creates the MachineConfig configuring the container runtime of the
+// machines for the mirror registries of the image content sources, with a registries.conf
+// drop-in and, with a CA bundle, the CA of each of the mirror registries.
+func ForMirrorRegistries(role string, sources []types.ImageContentSource, caBundle string) (*mcfgv1.MachineConfig, error) {
+	files := []igntypes.File{
+		ignition.FileFromString("/etc/containers/registries.conf.d/99-mirror-registries.conf", "root", 0644, registriesDropIn(sources)),
+	}
+	if caBundle != "" {
+		for _, host := range mirrorHosts(sources) {
+			files = append(files, ignition.FileFromString(path.Join("/etc/containers/certs.d", host, "ca.crt"), "root", 0644, caBundle))
+		}
+	}
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Files: files,
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-mirror-registries", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package machineconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vincent-petithory/dataurl"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestForMirrorRegistries(t *testing.T) {
+	sources := []types.ImageContentSource{
+		{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com:5000/ocp/release"}},
+		{Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev", Mirrors: []string{"mirror.example.com:5000/ocp/release", "backup.example.com/ocp/release"}},
+		{Source: "registry.example.com/unmirrored"},
+	}
+
+	cases := []struct {
+		name          string
+		caBundle      string
+		expectedFiles []string
+	}{
+		{
+			name: "without CA bundle",
+			expectedFiles: []string{
+				"/etc/containers/registries.conf.d/99-mirror-registries.conf",
+			},
+		},
+		{
+			name:     "with CA bundle",
+			caBundle: "-----BEGIN CERTIFICATE-----\n",
+			expectedFiles: []string{
+				"/etc/containers/registries.conf.d/99-mirror-registries.conf",
+				"/etc/containers/certs.d/mirror.example.com:5000/ca.crt",
+				"/etc/containers/certs.d/backup.example.com/ca.crt",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machineConfig, err := ForMirrorRegistries("worker", sources, tc.caBundle)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "99-worker-mirror-registries", machineConfig.Name)
+			assert.Equal(t, "worker", machineConfig.Labels[roleLabel])
+
+			var config igntypes.Config
+			if !assert.NoError(t, json.Unmarshal(machineConfig.Spec.Config.Raw, &config)) {
+				return
+			}
+			var paths []string
+			for _, file := range config.Storage.Files {
+				paths = append(paths, file.Path)
+			}
+			assert.Equal(t, tc.expectedFiles, paths)
+
+			contents, err := dataurl.DecodeString(*config.Storage.Files[0].Contents.Source)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, `[[registry]]
+location = "quay.io/openshift-release-dev/ocp-release"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com:5000/ocp/release"
+
+[[registry]]
+location = "quay.io/openshift-release-dev/ocp-v4.0-art-dev"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com:5000/ocp/release"
+
+[[registry.mirror]]
+location = "backup.example.com/ocp/release"
+
+`, string(contents.Data))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package machineconfig
+
+import (
+	"fmt"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+// chronyConfig returns the chrony configuration synchronizing the clock with the servers, the
+// other directives being the defaults of RHCOS.
+func chronyConfig(servers []string) string {
+	var config strings.Builder
+	for _, server := range servers {
+		fmt.Fprintf(&config, "server %s iburst\n", server)
+	}
+	config.WriteString(`driftfile /var/lib/chrony/drift
+makestep 1.0 3
+rtcsync
+logdir /var/log/chrony
+`)
+	return config.String()
+}
+
+// ForNTPServers creates the MachineConfig to synchronize the clock of the machines with the
+// NTP servers, replacing the chrony configuration of RHCOS.
+func ForNTPServers(role string, servers []string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Files: []igntypes.File{
+				ignition.FileFromString("/etc/chrony.conf", "root", 0644, chronyConfig(servers)),
+			},
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-chrony", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
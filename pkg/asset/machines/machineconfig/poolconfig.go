@@ -0,0 +1,103 @@
+package machineconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// poolSelector selects the machine config pool of the role.
+func poolSelector(role string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			fmt.Sprintf("pools.operator.machineconfiguration.openshift.io/%s", role): "",
+		},
+	}
+}
+
+// ForKubeletConfig creates the KubeletConfig of the machine config pool of the role.
+func ForKubeletConfig(role string, config *types.KubeletConfig) (*mcfgv1.KubeletConfig, error) {
+	// The fields of the kubelet configuration, in the kubelet.config.k8s.io/v1beta1 format
+	kubelet := struct {
+		MaxPods        *int32            `json:"maxPods,omitempty"`
+		SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	}{
+		MaxPods:        config.MaxPods,
+		SystemReserved: config.SystemReserved,
+	}
+	raw, err := json.Marshal(kubelet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.KubeletConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "KubeletConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-kubelet-config", role),
+		},
+		Spec: mcfgv1.KubeletConfigSpec{
+			MachineConfigPoolSelector: poolSelector(role),
+			KubeletConfig:             &runtime.RawExtension{Raw: raw},
+		},
+	}, nil
+}
+
+// ForContainerRuntimeConfig creates the ContainerRuntimeConfig of the machine config pool of the
+// role.
+func ForContainerRuntimeConfig(role string, config *types.ContainerRuntimeConfig) (*mcfgv1.ContainerRuntimeConfig, error) {
+	runtimeConfig := &mcfgv1.ContainerRuntimeConfiguration{}
+	if config.PidsLimit != nil {
+		runtimeConfig.PidsLimit = *config.PidsLimit
+	}
+	if config.LogSizeMax != "" {
+		logSizeMax, err := resource.ParseQuantity(config.LogSizeMax)
+		if err != nil {
+			return nil, err
+		}
+		runtimeConfig.LogSizeMax = logSizeMax
+	}
+
+	return &mcfgv1.ContainerRuntimeConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "ContainerRuntimeConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-container-runtime-config", role),
+		},
+		Spec: mcfgv1.ContainerRuntimeConfigSpec{
+			MachineConfigPoolSelector: poolSelector(role),
+			ContainerRuntimeConfig:    runtimeConfig,
+		},
+	}, nil
+}
+
+// ForPoolConfigs creates the KubeletConfig and ContainerRuntimeConfig of the machine config pool
+// of the role set by the machine pool, if any.
+func ForPoolConfigs(role string, pool *types.MachinePool) ([]metav1.Object, error) {
+	var configs []metav1.Object
+	if pool.KubeletConfig != nil {
+		kubeletConfig, err := ForKubeletConfig(role, pool.KubeletConfig)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, kubeletConfig)
+	}
+	if pool.ContainerRuntimeConfig != nil {
+		runtimeConfig, err := ForContainerRuntimeConfig(role, pool.ContainerRuntimeConfig)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, runtimeConfig)
+	}
+	return configs, nil
+}
@@ -401,11 +401,49 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		}
 		machineConfigs = append(machineConfigs, ignFIPS)
 	}
+	if len(pool.KernelArguments) > 0 {
+		ignKargs, err := machineconfig.ForKernelArguments("master", pool.KernelArguments)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for kernel arguments of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignKargs)
+	}
+	if len(ic.NTPServers) > 0 {
+		ignNTP, err := machineconfig.ForNTPServers("master", ic.NTPServers)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for the NTP servers of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignNTP)
+	}
+	if ic.Platform.Kubevirt != nil && ic.Platform.Kubevirt.MirrorRegistry != nil {
+		ignMirrors, err := machineconfig.ForMirrorRegistries("master", ic.ImageContentSources, ic.Platform.Kubevirt.MirrorRegistry.CABundle)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for the mirror registries of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignMirrors)
+	}
+	if pool.Platform.Kubevirt != nil && pool.Platform.Kubevirt.EtcdDisk != nil {
+		ignEtcdDisk, err := machineconfig.ForEtcdDisk("master", kubevirt.EtcdDiskDevice(pool.Platform.Kubevirt))
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for the etcd disk of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignEtcdDisk)
+	}
+
+	poolConfigs, err := machineconfig.ForPoolConfigs("master", pool)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kubelet and container runtime configs of master machines")
+	}
 
 	m.MachineConfigFiles, err = machineconfig.Manifests(machineConfigs, "master", directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create MachineConfig manifests for master machines")
 	}
+	poolConfigFiles, err := machineconfig.PoolConfigManifests(poolConfigs, directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kubelet and container runtime config manifests for master machines")
+	}
+	m.MachineConfigFiles = append(m.MachineConfigFiles, poolConfigFiles...)
 
 	m.MachineFiles = make([]*asset.File, len(machines))
 	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(machines))))
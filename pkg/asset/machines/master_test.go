@@ -20,6 +20,10 @@ func TestMasterGenerateMachineConfigs(t *testing.T) {
 		name                  string
 		key                   string
 		hyperthreading        types.HyperthreadingMode
+		kernelArguments       []string
+		ntpServers            []string
+		kubeletConfig         *types.KubeletConfig
+		runtimeConfig         *types.ContainerRuntimeConfig
 		expectedMachineConfig []string
 	}{
 		{
@@ -133,6 +137,102 @@ spec:
   kernelArguments: null
   kernelType: ""
   osImageURL: ""
+`},
+		},
+		{
+			name:            "kernel arguments",
+			hyperthreading:  types.HyperthreadingEnabled,
+			kernelArguments: []string{"intel_iommu=on", "hugepagesz=1G"},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-kernel-arguments
+spec:
+  config:
+    ignition:
+      version: 3.1.0
+  extensions: null
+  fips: false
+  kernelArguments:
+  - intel_iommu=on
+  - hugepagesz=1G
+  kernelType: ""
+  osImageURL: ""
+`},
+		},
+		{
+			name:           "kubelet and container runtime configs",
+			hyperthreading: types.HyperthreadingEnabled,
+			kubeletConfig: &types.KubeletConfig{
+				MaxPods:        pointer.Int32Ptr(500),
+				SystemReserved: map[string]string{"cpu": "500m", "memory": "1Gi"},
+			},
+			runtimeConfig: &types.ContainerRuntimeConfig{PidsLimit: pointer.Int64Ptr(4096), LogSizeMax: "50Mi"},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  creationTimestamp: null
+  name: 99-master-kubelet-config
+spec:
+  kubeletConfig:
+    maxPods: 500
+    systemReserved:
+      cpu: 500m
+      memory: 1Gi
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.openshift.io/master: ""
+status:
+  conditions: null
+`, `apiVersion: machineconfiguration.openshift.io/v1
+kind: ContainerRuntimeConfig
+metadata:
+  creationTimestamp: null
+  name: 99-master-container-runtime-config
+spec:
+  containerRuntimeConfig:
+    logSizeMax: 50Mi
+    overlaySize: "0"
+    pidsLimit: 4096
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.openshift.io/master: ""
+status:
+  conditions: null
+`},
+		},
+		{
+			name:           "ntp servers",
+			hyperthreading: types.HyperthreadingEnabled,
+			ntpServers:     []string{"10.0.0.1", "ntp.example.com"},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-chrony
+spec:
+  config:
+    ignition:
+      version: 3.1.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,c2VydmVyIDEwLjAuMC4xIGlidXJzdApzZXJ2ZXIgbnRwLmV4YW1wbGUuY29tIGlidXJzdApkcmlmdGZpbGUgL3Zhci9saWIvY2hyb255L2RyaWZ0Cm1ha2VzdGVwIDEuMCAzCnJ0Y3N5bmMKbG9nZGlyIC92YXIvbG9nL2Nocm9ueQo=
+        mode: 420
+        overwrite: true
+        path: /etc/chrony.conf
+        user:
+          name: root
+  extensions: null
+  fips: false
+  kernelArguments: null
+  kernelType: ""
+  osImageURL: ""
 `},
 		},
 	}
@@ -151,14 +251,18 @@ spec:
 						},
 						SSHKey:     tc.key,
 						BaseDomain: "test-domain",
+						NTPServers: tc.ntpServers,
 						Platform: types.Platform{
 							AWS: &awstypes.Platform{
 								Region: "us-east-1",
 							},
 						},
 						ControlPlane: &types.MachinePool{
-							Hyperthreading: tc.hyperthreading,
-							Replicas:       pointer.Int64Ptr(1),
+							Hyperthreading:         tc.hyperthreading,
+							KernelArguments:        tc.kernelArguments,
+							KubeletConfig:          tc.kubeletConfig,
+							ContainerRuntimeConfig: tc.runtimeConfig,
+							Replicas:               pointer.Int64Ptr(1),
 							Platform: types.MachinePoolPlatform{
 								AWS: &awstypes.MachinePool{
 									Zones:        []string{"us-east-1a"},
@@ -25,6 +25,7 @@ import (
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	awsapi "sigs.k8s.io/cluster-api-provider-aws/pkg/apis"
@@ -192,6 +193,7 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 	dependencies.Get(clusterID, installConfig, rhcosImage, wign)
 
 	machineConfigs := []*mcfgv1.MachineConfig{}
+	poolConfigs := []metav1.Object{}
 	machineSets := []runtime.Object{}
 	var err error
 	ic := installConfig.Config
@@ -217,6 +219,32 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			}
 			machineConfigs = append(machineConfigs, ignFIPS)
 		}
+		if len(pool.KernelArguments) > 0 {
+			ignKargs, err := machineconfig.ForKernelArguments("worker", pool.KernelArguments)
+			if err != nil {
+				return errors.Wrap(err, "failed to create ignition for kernel arguments of worker machines")
+			}
+			machineConfigs = append(machineConfigs, ignKargs)
+		}
+		if len(ic.NTPServers) > 0 {
+			ignNTP, err := machineconfig.ForNTPServers("worker", ic.NTPServers)
+			if err != nil {
+				return errors.Wrap(err, "failed to create ignition for the NTP servers of worker machines")
+			}
+			machineConfigs = append(machineConfigs, ignNTP)
+		}
+		if ic.Platform.Kubevirt != nil && ic.Platform.Kubevirt.MirrorRegistry != nil {
+			ignMirrors, err := machineconfig.ForMirrorRegistries("worker", ic.ImageContentSources, ic.Platform.Kubevirt.MirrorRegistry.CABundle)
+			if err != nil {
+				return errors.Wrap(err, "failed to create ignition for the mirror registries of worker machines")
+			}
+			machineConfigs = append(machineConfigs, ignMirrors)
+		}
+		configs, err := machineconfig.ForPoolConfigs("worker", &pool)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the kubelet and container runtime configs of worker machines")
+		}
+		poolConfigs = append(poolConfigs, configs...)
 		switch ic.Platform.Name() {
 		case awstypes.Name:
 			subnets := map[string]string{}
@@ -426,6 +454,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to create MachineConfig manifests for worker machines")
 	}
+	poolConfigFiles, err := machineconfig.PoolConfigManifests(poolConfigs, directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kubelet and container runtime config manifests for worker machines")
+	}
+	w.MachineConfigFiles = append(w.MachineConfigFiles, poolConfigFiles...)
 
 	w.MachineSetFiles = make([]*asset.File, len(machineSets))
 	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(machineSets))))
@@ -0,0 +1,133 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	apiServerCfgFilename               = filepath.Join(manifestDir, "cluster-apiserver-02-config.yml")
+	apiServerCertificateSecretFilename = filepath.Join(manifestDir, "cluster-apiserver-03-named-certificate-secret.yml")
+)
+
+// apiServerCertificateSecretName is the name of the secret, in the openshift-config namespace,
+// holding the named serving certificate of the API server.
+const apiServerCertificateSecretName = "api-named-certificate"
+
+// APIServer generates the cluster-apiserver-*.yml files.
+type APIServer struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*APIServer)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*APIServer) Name() string {
+	return "APIServer Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*APIServer) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the APIServer config, when the install-config sets the audit profile or
+// the named serving certificate of the API server, along with the secret of the certificate.
+// Otherwise, the APIServer config is left to its defaults.
+func (a *APIServer) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	a.FileList = nil
+	if installConfig.Config.AuditProfile == "" && installConfig.Config.APIServerCertificate == nil {
+		return nil
+	}
+
+	config := &configv1.APIServer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "APIServer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: configv1.APIServerSpec{
+			Audit: configv1.Audit{
+				Profile: configv1.AuditProfileType(installConfig.Config.AuditProfile),
+			},
+		},
+	}
+
+	certificate, err := installconfig.ReadAPIServerCertificate(installConfig.Config)
+	if err != nil {
+		return err
+	}
+	if certificate != nil {
+		config.Spec.ServingCerts.NamedCertificates = []configv1.APIServerNamedServingCert{{
+			Names:              installConfig.Config.APIServerCertificateNames(),
+			ServingCertificate: configv1.SecretNameReference{Name: apiServerCertificateSecretName},
+		}}
+	}
+
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", a.Name())
+	}
+
+	a.FileList = []*asset.File{
+		{
+			Filename: apiServerCfgFilename,
+			Data:     configData,
+		},
+	}
+
+	if certificate != nil {
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      apiServerCertificateSecretName,
+				Namespace: "openshift-config",
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certificate.Cert,
+				corev1.TLSPrivateKeyKey: certificate.Key,
+			},
+		}
+		secretData, err := yaml.Marshal(secret)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", a.Name())
+		}
+		a.FileList = append(a.FileList, &asset.File{
+			Filename: apiServerCertificateSecretFilename,
+			Data:     secretData,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (a *APIServer) Files() []*asset.File {
+	return a.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (a *APIServer) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
@@ -164,8 +164,10 @@ func (cpc *CloudProviderConfig) Generate(dependencies asset.Parents) error {
 		cm.Data[cloudProviderConfigDataKey] = vsphereConfig
 	case kubevirttypes.Name:
 		kubevirtConfig, err := kubevirt.CloudProviderConfig{
-			Namespace: installConfig.Config.Platform.Kubevirt.Namespace,
-			InfraID:   clusterID.InfraID,
+			Namespace:   installConfig.Config.Platform.Kubevirt.Namespace,
+			InfraID:     clusterID.InfraID,
+			ZoneLabel:   installConfig.Config.Platform.Kubevirt.InfraNodeLabel(corev1.LabelZoneFailureDomainStable),
+			RegionLabel: installConfig.Config.Platform.Kubevirt.InfraNodeLabel(corev1.LabelZoneRegionStable),
 		}.JSON()
 		if err != nil {
 			return errors.Wrap(err, "could not create cloud provider config")
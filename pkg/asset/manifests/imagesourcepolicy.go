@@ -11,9 +11,30 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
 )
 
-var imageContentSourcePolicyFilenameFormat = "image-content-source-policy-%s.yaml"
+var (
+	imageContentSourcePolicyFilenameFormat = "image-content-source-policy-%s.yaml"
+	imageDigestMirrorSetFilenameFormat     = "image-digest-mirror-set-%s.yaml"
+)
+
+// imageDigestMirrorSet is the config.openshift.io/v1 ImageDigestMirrorSet, which the vendored
+// openshift/api does not have yet.
+type imageDigestMirrorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              imageDigestMirrorSetSpec `json:"spec"`
+}
+
+type imageDigestMirrorSetSpec struct {
+	ImageDigestMirrors []imageDigestMirrors `json:"imageDigestMirrors"`
+}
+
+type imageDigestMirrors struct {
+	Source  string   `json:"source"`
+	Mirrors []string `json:"mirrors,omitempty"`
+}
 
 // ImageContentSourcePolicy generates the image-content-source-policy.yaml files.
 type ImageContentSourcePolicy struct {
@@ -35,15 +56,38 @@ func (*ImageContentSourcePolicy) Dependencies() []asset.Asset {
 	}
 }
 
-// Generate generates the ImageContentSourcePolicy config and its CRD.
+// Generate generates the ImageContentSourcePolicy config and its CRD, or the
+// ImageDigestMirrorSet config when the install-config sets its mirror policy kind.
 func (p *ImageContentSourcePolicy) Generate(dependencies asset.Parents) error {
 	installconfig := &installconfig.InstallConfig{}
 	dependencies.Get(installconfig)
 
 	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(installconfig.Config.ImageContentSources))))
 
-	var policies []*operatorv1alpha1.ImageContentSourcePolicy
+	digestMirrorSets := installconfig.Config.MirrorPolicyKind == types.ImageDigestMirrorSetMirrorPolicyKind
+	kind, filenameFormat := "ImageContentSourcePolicy", imageContentSourcePolicyFilenameFormat
+	if digestMirrorSets {
+		kind, filenameFormat = "ImageDigestMirrorSet", imageDigestMirrorSetFilenameFormat
+	}
+
+	var policies []interface{}
 	for gidx, group := range installconfig.Config.ImageContentSources {
+		if digestMirrorSets {
+			policies = append(policies, &imageDigestMirrorSet{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "config.openshift.io/v1",
+					Kind:       "ImageDigestMirrorSet",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("image-digest-mirror-%s", fmt.Sprintf(padFormat, gidx)),
+					// not namespaced
+				},
+				Spec: imageDigestMirrorSetSpec{
+					ImageDigestMirrors: []imageDigestMirrors{{Source: group.Source, Mirrors: group.Mirrors}},
+				},
+			})
+			continue
+		}
 		policies = append(policies, &operatorv1alpha1.ImageContentSourcePolicy{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
@@ -63,11 +107,11 @@ func (p *ImageContentSourcePolicy) Generate(dependencies asset.Parents) error {
 	for i, policy := range policies {
 		policyData, err := yaml.Marshal(policy)
 		if err != nil {
-			return errors.Wrapf(err, "failed to marshal ImageContentSourcePolicy")
+			return errors.Wrapf(err, "failed to marshal %s", kind)
 		}
 		padded := fmt.Sprintf(padFormat, i)
 		p.FileList[i] = &asset.File{
-			Filename: filepath.Join(manifestDir, fmt.Sprintf(imageContentSourcePolicyFilenameFormat, padded)),
+			Filename: filepath.Join(manifestDir, fmt.Sprintf(filenameFormat, padded)),
 			Data:     policyData,
 		}
 	}
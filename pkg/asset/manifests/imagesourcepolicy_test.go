@@ -0,0 +1,75 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestImageContentSourcePolicyGenerate(t *testing.T) {
+	sources := []types.ImageContentSource{
+		{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp/release"}},
+		{Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev", Mirrors: []string{"mirror.example.com/ocp/release"}},
+	}
+	cases := []struct {
+		name              string
+		kind              types.MirrorPolicyKind
+		expectedFilenames []string
+		expectedFirst     string
+	}{
+		{
+			name:              "image content source policies",
+			expectedFilenames: []string{"manifests/image-content-source-policy-0.yaml", "manifests/image-content-source-policy-1.yaml"},
+			expectedFirst: `apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  creationTimestamp: null
+  name: image-policy-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - mirror.example.com/ocp/release
+    source: quay.io/openshift-release-dev/ocp-release
+`,
+		},
+		{
+			name:              "image digest mirror sets",
+			kind:              types.ImageDigestMirrorSetMirrorPolicyKind,
+			expectedFilenames: []string{"manifests/image-digest-mirror-set-0.yaml", "manifests/image-digest-mirror-set-1.yaml"},
+			expectedFirst: `apiVersion: config.openshift.io/v1
+kind: ImageDigestMirrorSet
+metadata:
+  creationTimestamp: null
+  name: image-digest-mirror-0
+spec:
+  imageDigestMirrors:
+  - mirrors:
+    - mirror.example.com/ocp/release
+    source: quay.io/openshift-release-dev/ocp-release
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: &types.InstallConfig{
+				ImageContentSources: sources,
+				MirrorPolicyKind:    tc.kind,
+			}})
+			policy := &ImageContentSourcePolicy{}
+			if !assert.NoError(t, policy.Generate(parents)) {
+				return
+			}
+			var filenames []string
+			for _, file := range policy.Files() {
+				filenames = append(filenames, file.Filename)
+			}
+			assert.Equal(t, tc.expectedFilenames, filenames)
+			assert.Equal(t, tc.expectedFirst, string(policy.Files()[0].Data))
+		})
+	}
+}
@@ -3,6 +3,21 @@ package kubevirt
 import (
 	"bytes"
 	"encoding/json"
+
+	kubevirtutils "github.com/openshift/cluster-api-provider-kubevirt/pkg/utils"
+)
+
+const (
+	// secretName is the name of the secret holding the kubeconfig of the infra cluster,
+	// created from the cloud-creds-secret manifest.
+	secretName = "kubevirt-credentials"
+	// secretNamespace is the namespace of the secret holding the kubeconfig of the infra cluster.
+	secretNamespace = "kube-system"
+
+	// zoneLabel and regionLabel are the labels of the infra cluster nodes which the zone and
+	// the region of the tenant cluster nodes running on them are mapped from.
+	zoneLabel   = "topology.kubernetes.io/zone"
+	regionLabel = "topology.kubernetes.io/region"
 )
 
 // CloudProviderConfig is the kubevirt cloud provider config
@@ -10,19 +25,57 @@ type CloudProviderConfig struct {
 	// The namespace in the infra cluster that the cluster resources are created in
 	Namespace string
 	InfraID   string
+	// ZoneLabel and RegionLabel are the labels of the infra cluster nodes the zone and the
+	// region of the tenant cluster nodes are read from, the topology labels when empty.
+	ZoneLabel   string
+	RegionLabel string
 }
 
 type config struct {
 	// The namespace in the infra cluster that the cluster resources are created in
 	Namespace string `json:"namespace" yaml:"namespace"`
 	InfraID   string `json:"infraID" yaml:"infraID"`
+	// The secret holding, in its kubeconfig key, the kubeconfig of the infra cluster
+	SecretName      string `json:"secretName" yaml:"secretName"`
+	SecretNamespace string `json:"secretNamespace" yaml:"secretNamespace"`
+	// The labels set on the resources created in the infra cluster, such as the
+	// LoadBalancer services
+	InfraLabels  map[string]string  `json:"infraLabels" yaml:"infraLabels"`
+	LoadBalancer loadBalancerConfig `json:"loadBalancer" yaml:"loadBalancer"`
+	InstancesV2  instancesV2Config  `json:"instancesV2" yaml:"instancesV2"`
+}
+
+type loadBalancerConfig struct {
+	// Enabled creates the LoadBalancer services of the tenant cluster in the infra cluster
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+type instancesV2Config struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// ZoneAndRegionEnabled sets the zone and the region of the nodes from the labels of the
+	// infra cluster nodes running their VMs
+	ZoneAndRegionEnabled bool   `json:"zoneAndRegionEnabled" yaml:"zoneAndRegionEnabled"`
+	ZoneLabel            string `json:"zoneLabel" yaml:"zoneLabel"`
+	RegionLabel          string `json:"regionLabel" yaml:"regionLabel"`
 }
 
 // JSON generates the cloud provider json config for the kubevirt platform.
 func (params CloudProviderConfig) JSON() (string, error) {
 	config := config{
-		Namespace: params.Namespace,
-		InfraID:   params.InfraID,
+		Namespace:       params.Namespace,
+		InfraID:         params.InfraID,
+		SecretName:      secretName,
+		SecretNamespace: secretNamespace,
+		InfraLabels:     kubevirtutils.BuildLabels(params.InfraID),
+		LoadBalancer: loadBalancerConfig{
+			Enabled: true,
+		},
+		InstancesV2: instancesV2Config{
+			Enabled:              true,
+			ZoneAndRegionEnabled: true,
+			ZoneLabel:            labelOrDefault(params.ZoneLabel, zoneLabel),
+			RegionLabel:          labelOrDefault(params.RegionLabel, regionLabel),
+		},
 	}
 	buff := &bytes.Buffer{}
 	encoder := json.NewEncoder(buff)
@@ -32,3 +85,10 @@ func (params CloudProviderConfig) JSON() (string, error) {
 	}
 	return buff.String(), nil
 }
+
+func labelOrDefault(label string, defaultLabel string) string {
+	if label != "" {
+		return label
+	}
+	return defaultLabel
+}
@@ -0,0 +1,47 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudProviderConfig(t *testing.T) {
+	expectedConfig := `{
+	"namespace": "test-namespace",
+	"infraID": "clusterID",
+	"secretName": "kubevirt-credentials",
+	"secretNamespace": "kube-system",
+	"infraLabels": {
+		"tenantcluster-clusterID-machine.openshift.io": "owned"
+	},
+	"loadBalancer": {
+		"enabled": true
+	},
+	"instancesV2": {
+		"enabled": true,
+		"zoneAndRegionEnabled": true,
+		"zoneLabel": "topology.kubernetes.io/zone",
+		"regionLabel": "topology.kubernetes.io/region"
+	}
+}
+`
+	actualConfig, err := CloudProviderConfig{
+		Namespace: "test-namespace",
+		InfraID:   "clusterID",
+	}.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigWithCustomLabels(t *testing.T) {
+	actualConfig, err := CloudProviderConfig{
+		Namespace:   "test-namespace",
+		InfraID:     "clusterID",
+		ZoneLabel:   "example.com/rack",
+		RegionLabel: "example.com/room",
+	}.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, actualConfig, `"zoneLabel": "example.com/rack"`)
+	assert.Contains(t, actualConfig, `"regionLabel": "example.com/room"`)
+}
@@ -0,0 +1,155 @@
+package kubevirt
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// rotationName is the name of the CronJob renewing the service account token of the
+	// kubeconfig of the infra cluster, and of its service account and RBAC.
+	rotationName = "kubevirt-credentials-rotation"
+
+	// rotationSchedule renews the token four times a day, so that a few failed renewals
+	// don't let it expire.
+	rotationSchedule = "0 */6 * * *"
+
+	// cliImageTrigger sets the image of the rotation container from the cli image stream of
+	// the cluster, which ships oc.
+	cliImageTrigger = `[{"from":{"kind":"ImageStreamTag","name":"cli:latest","namespace":"openshift"},"fieldPath":"spec.jobTemplate.spec.template.spec.containers[?(@.name==\"rotate\")].image"}]`
+)
+
+// rotationScript requests a new token of the service account with the current token of the
+// kubeconfig, and replaces the token of the kubeconfig in the secret with it.
+const rotationScript = `set -eu
+oc extract -n %[1]s secret/%[2]s --keys=kubeconfig --to=/tmp --confirm
+cat > /tmp/token-request.json <<EOF
+{"apiVersion":"authentication.k8s.io/v1","kind":"TokenRequest","spec":{"expirationSeconds":%[5]d}}
+EOF
+token=$(oc --kubeconfig=/tmp/kubeconfig create --raw /api/v1/namespaces/%[3]s/serviceaccounts/%[4]s/token -f /tmp/token-request.json | sed -n 's/.*"token":"\([^"]*\)".*/\1/p')
+test -n "$token"
+oc --kubeconfig=/tmp/kubeconfig config set-credentials %[6]s --token="$token" >/dev/null
+oc set data -n %[1]s secret/%[2]s --from-file=kubeconfig=/tmp/kubeconfig
+`
+
+// CredentialsRotation renews the service account token of the kubeconfig of the infra cluster,
+// held by the credentials secret of the cluster.
+type CredentialsRotation struct {
+	// Namespace and Name are the service account of the infra cluster.
+	Namespace string
+	Name      string
+	// User is the user of the service account in the kubeconfig.
+	User string
+	// Expiration is how long the renewed tokens are valid.
+	Expiration time.Duration
+}
+
+// Manifest generates the CronJob renewing the token in the cluster, with its service account
+// allowed to update the credentials secret.
+func (params CredentialsRotation) Manifest() ([]byte, error) {
+	serviceAccount := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rotationName,
+			Namespace: secretNamespace,
+		},
+	}
+	role := &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rotationName,
+			Namespace: secretNamespace,
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{secretName},
+			Verbs:         []string{"get", "update", "patch"},
+		}},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rotationName,
+			Namespace: secretNamespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     rotationName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      rotationName,
+			Namespace: secretNamespace,
+		}},
+	}
+
+	successfulJobsHistoryLimit, failedJobsHistoryLimit := int32(1), int32(3)
+	script := fmt.Sprintf(rotationScript, secretNamespace, secretName, params.Namespace, params.Name, int64(params.Expiration.Seconds()), params.User)
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1beta1.SchemeGroupVersion.String(),
+			Kind:       "CronJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rotationName,
+			Namespace: secretNamespace,
+			Annotations: map[string]string{
+				"image.openshift.io/triggers": cliImageTrigger,
+			},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   rotationSchedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: rotationName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name: "rotate",
+								// Set by the image trigger
+								Image:   " ",
+								Command: []string{"/bin/bash", "-c", script},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buff := &bytes.Buffer{}
+	for i, object := range []interface{}{serviceAccount, role, roleBinding, cronJob} {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buff.WriteString("---\n")
+		}
+		buff.Write(data)
+	}
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+package kubevirt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+)
+
+func TestCredentialsRotation(t *testing.T) {
+	manifest, err := CredentialsRotation{
+		Namespace:  "infra-namespace",
+		Name:       "tenant",
+		User:       "serviceaccount",
+		Expiration: 24 * time.Hour,
+	}.Manifest()
+	if !assert.NoError(t, err, "failed to create credentials rotation") {
+		return
+	}
+
+	documents := strings.Split(string(manifest), "---\n")
+	if !assert.Len(t, documents, 4, "unexpected number of objects") {
+		return
+	}
+	assert.Contains(t, documents[0], "kind: ServiceAccount")
+	assert.Contains(t, documents[1], "kind: Role\n")
+	assert.Contains(t, documents[2], "kind: RoleBinding")
+
+	cronJob := &batchv1beta1.CronJob{}
+	if !assert.NoError(t, yaml.Unmarshal([]byte(documents[3]), cronJob), "failed to parse the CronJob") {
+		return
+	}
+	assert.Equal(t, "kube-system", cronJob.Namespace)
+	assert.Equal(t, "0 */6 * * *", cronJob.Spec.Schedule)
+	assert.Equal(t, batchv1beta1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	containers := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+	if assert.Len(t, containers, 1) {
+		script := containers[0].Command[2]
+		assert.Contains(t, script, "/api/v1/namespaces/infra-namespace/serviceaccounts/tenant/token")
+		assert.Contains(t, script, `"expirationSeconds":86400`)
+		assert.Contains(t, script, "config set-credentials serviceaccount")
+		assert.Contains(t, script, "secret/kubevirt-credentials --from-file=kubeconfig=/tmp/kubeconfig")
+	}
+}
@@ -0,0 +1,134 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// failureDomainsName is the name of the config map mapping the failure domains of the
+	// tenant cluster to the topology of the infra cluster.
+	failureDomainsName = "kubevirt-failure-domains"
+	// failureDomainsNamespace is the namespace of the failure domains config map, with the
+	// other config maps of the cluster configuration.
+	failureDomainsNamespace = "openshift-config"
+	// failureDomainsKey is the key of the config map holding the failure domains as JSON.
+	failureDomainsKey = "failure-domains.json"
+)
+
+// FailureDomains maps the failure domains of the tenant cluster to the topology labels of the
+// infra cluster nodes, for the scheduler and the CSI drivers of the tenant cluster to spread
+// the workloads and the volumes across the infra cluster.
+type FailureDomains struct {
+	// Nodes are the nodes of the infra cluster.
+	Nodes []corev1.Node
+	// ZoneLabel and RegionLabel are the labels of the infra cluster nodes holding their zone
+	// and region, the topology labels when empty.
+	ZoneLabel   string
+	RegionLabel string
+}
+
+type failureDomains struct {
+	// ZoneLabel and RegionLabel are the labels of the tenant cluster nodes holding their
+	// failure domain, set by the cloud provider from the infra cluster nodes.
+	ZoneLabel   string          `json:"zoneLabel"`
+	RegionLabel string          `json:"regionLabel"`
+	Domains     []failureDomain `json:"failureDomains"`
+}
+
+type failureDomain struct {
+	// Name is the zone of the tenant cluster nodes in the failure domain.
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+	// TopologyLabels select the infra cluster nodes of the failure domain.
+	TopologyLabels map[string]string `json:"topologyLabels"`
+	// InfraNodes are the infra cluster nodes of the failure domain when installed.
+	InfraNodes []string `json:"infraNodes"`
+}
+
+// domains returns the failure domains of the infra cluster nodes with a zone label, sorted by
+// region and zone.
+func (params FailureDomains) domains() []failureDomain {
+	byZone := map[string]*failureDomain{}
+	for _, node := range params.Nodes {
+		zone, zoneKey := nodeLabel(&node, labelOrDefault(params.ZoneLabel, zoneLabel), corev1.LabelZoneFailureDomain)
+		if zone == "" {
+			continue
+		}
+		region, regionKey := nodeLabel(&node, labelOrDefault(params.RegionLabel, regionLabel), corev1.LabelZoneRegion)
+		key := region + "/" + zone
+		domain, ok := byZone[key]
+		if !ok {
+			domain = &failureDomain{
+				Name:           zone,
+				Region:         region,
+				TopologyLabels: map[string]string{zoneKey: zone},
+			}
+			if region != "" {
+				domain.TopologyLabels[regionKey] = region
+			}
+			byZone[key] = domain
+		}
+		domain.InfraNodes = append(domain.InfraNodes, node.Name)
+	}
+
+	domains := make([]failureDomain, 0, len(byZone))
+	for _, domain := range byZone {
+		sort.Strings(domain.InfraNodes)
+		domains = append(domains, *domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Region != domains[j].Region {
+			return domains[i].Region < domains[j].Region
+		}
+		return domains[i].Name < domains[j].Name
+	})
+	return domains
+}
+
+// Manifest generates the config map of the failure domains, or nothing when none of the
+// infra cluster nodes has a zone label.
+func (params FailureDomains) Manifest() ([]byte, error) {
+	domains := params.domains()
+	if len(domains) == 0 {
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(failureDomains{
+		ZoneLabel:   zoneLabel,
+		RegionLabel: regionLabel,
+		Domains:     domains,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      failureDomainsName,
+			Namespace: failureDomainsNamespace,
+		},
+		Data: map[string]string{
+			failureDomainsKey: string(data),
+		},
+	}
+	return yaml.Marshal(configMap)
+}
+
+// nodeLabel returns the value of the label of the node and its key, falling back to the
+// deprecated label.
+func nodeLabel(node *corev1.Node, label string, deprecated string) (string, string) {
+	if value, ok := node.Labels[label]; ok {
+		return value, label
+	}
+	if value, ok := node.Labels[deprecated]; ok {
+		return value, deprecated
+	}
+	return "", label
+}
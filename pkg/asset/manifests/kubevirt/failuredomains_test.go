@@ -0,0 +1,90 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func node(name string, labels map[string]string) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestFailureDomains(t *testing.T) {
+	manifest, err := FailureDomains{
+		Nodes: []corev1.Node{
+			node("node-3", map[string]string{"topology.kubernetes.io/zone": "zone-b", "topology.kubernetes.io/region": "region-1"}),
+			node("node-2", map[string]string{"topology.kubernetes.io/zone": "zone-a", "topology.kubernetes.io/region": "region-1"}),
+			node("node-1", map[string]string{"topology.kubernetes.io/zone": "zone-a", "topology.kubernetes.io/region": "region-1"}),
+			node("node-4", map[string]string{"failure-domain.beta.kubernetes.io/zone": "zone-c"}),
+			node("node-5", nil),
+		},
+	}.Manifest()
+	if !assert.NoError(t, err, "failed to create failure domains") {
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if !assert.NoError(t, yaml.Unmarshal(manifest, configMap), "failed to parse the config map") {
+		return
+	}
+	assert.Equal(t, "kubevirt-failure-domains", configMap.Name)
+	assert.Equal(t, "openshift-config", configMap.Namespace)
+
+	domains := failureDomains{}
+	if !assert.NoError(t, json.Unmarshal([]byte(configMap.Data["failure-domains.json"]), &domains), "failed to parse the failure domains") {
+		return
+	}
+	assert.Equal(t, failureDomains{
+		ZoneLabel:   "topology.kubernetes.io/zone",
+		RegionLabel: "topology.kubernetes.io/region",
+		Domains: []failureDomain{
+			{
+				Name:           "zone-c",
+				TopologyLabels: map[string]string{"failure-domain.beta.kubernetes.io/zone": "zone-c"},
+				InfraNodes:     []string{"node-4"},
+			},
+			{
+				Name:           "zone-a",
+				Region:         "region-1",
+				TopologyLabels: map[string]string{"topology.kubernetes.io/zone": "zone-a", "topology.kubernetes.io/region": "region-1"},
+				InfraNodes:     []string{"node-1", "node-2"},
+			},
+			{
+				Name:           "zone-b",
+				Region:         "region-1",
+				TopologyLabels: map[string]string{"topology.kubernetes.io/zone": "zone-b", "topology.kubernetes.io/region": "region-1"},
+				InfraNodes:     []string{"node-3"},
+			},
+		},
+	}, domains)
+}
+
+func TestFailureDomainsWithoutZones(t *testing.T) {
+	manifest, err := FailureDomains{
+		Nodes: []corev1.Node{node("node-1", nil)},
+	}.Manifest()
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestFailureDomainsWithCustomZoneLabel(t *testing.T) {
+	params := FailureDomains{
+		Nodes: []corev1.Node{
+			node("node-1", map[string]string{"example.com/rack": "rack-1", "topology.kubernetes.io/zone": "zone-a"}),
+			node("node-2", map[string]string{"topology.kubernetes.io/zone": "zone-a"}),
+		},
+		ZoneLabel: "example.com/rack",
+	}
+	assert.Equal(t, []failureDomain{
+		{
+			Name:           "rack-1",
+			TopologyLabels: map[string]string{"example.com/rack": "rack-1"},
+			InfraNodes:     []string{"node-1"},
+		},
+	}, params.domains())
+}
@@ -0,0 +1,69 @@
+package kubevirt
+
+import (
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// nodeLabelsName is the name of the config map of the labels the machine-api provider
+	// propagates from the infra cluster nodes to the tenant cluster nodes.
+	nodeLabelsName = "kubevirt-node-labels"
+	// nodeLabelsNamespace is the namespace of the node labels config map, the namespace of the
+	// machine-api provider.
+	nodeLabelsNamespace = "openshift-machine-api"
+	// nodeLabelsKey is the key of the config map holding the node labels as JSON.
+	nodeLabelsKey = "node-labels.json"
+)
+
+// NodeLabels are the labels of the infra cluster nodes the machine-api provider sets on the
+// tenant cluster nodes running on them.
+type NodeLabels struct {
+	Mappings []kubevirttypes.NodeLabelMapping
+}
+
+type nodeLabels struct {
+	NodeLabels []nodeLabelMapping `json:"nodeLabels"`
+}
+
+type nodeLabelMapping struct {
+	InfraLabel  string `json:"infraLabel"`
+	TenantLabel string `json:"tenantLabel"`
+}
+
+// Manifest generates the config map of the node labels, or nothing when no label is mapped.
+func (params NodeLabels) Manifest() ([]byte, error) {
+	if len(params.Mappings) == 0 {
+		return nil, nil
+	}
+	labels := nodeLabels{}
+	for _, mapping := range params.Mappings {
+		labels.NodeLabels = append(labels.NodeLabels, nodeLabelMapping{
+			InfraLabel:  mapping.InfraLabel,
+			TenantLabel: mapping.TenantNodeLabel(),
+		})
+	}
+	data, err := json.MarshalIndent(labels, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeLabelsName,
+			Namespace: nodeLabelsNamespace,
+		},
+		Data: map[string]string{
+			nodeLabelsKey: string(data),
+		},
+	}
+	return yaml.Marshal(configMap)
+}
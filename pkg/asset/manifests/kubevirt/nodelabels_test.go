@@ -0,0 +1,48 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestNodeLabels(t *testing.T) {
+	manifest, err := NodeLabels{
+		Mappings: []kubevirttypes.NodeLabelMapping{
+			{InfraLabel: "example.com/rack"},
+			{InfraLabel: "example.com/room", TenantLabel: "topology.kubernetes.io/zone"},
+		},
+	}.Manifest()
+	if !assert.NoError(t, err, "failed to create node labels") {
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if !assert.NoError(t, yaml.Unmarshal(manifest, configMap), "failed to parse the config map") {
+		return
+	}
+	assert.Equal(t, "kubevirt-node-labels", configMap.Name)
+	assert.Equal(t, "openshift-machine-api", configMap.Namespace)
+
+	labels := nodeLabels{}
+	if !assert.NoError(t, json.Unmarshal([]byte(configMap.Data["node-labels.json"]), &labels), "failed to parse the node labels") {
+		return
+	}
+	assert.Equal(t, nodeLabels{
+		NodeLabels: []nodeLabelMapping{
+			{InfraLabel: "example.com/rack", TenantLabel: "example.com/rack"},
+			{InfraLabel: "example.com/room", TenantLabel: "topology.kubernetes.io/zone"},
+		},
+	}, labels)
+}
+
+func TestNodeLabelsWithoutMappings(t *testing.T) {
+	manifest, err := NodeLabels{}.Manifest()
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
@@ -0,0 +1,40 @@
+package kubevirt
+
+import (
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// poolCredentialsNamespace is the namespace of the secrets of the machine pools with their own
+// infra cluster, which the machine-api provider reads the credentials secrets of the machines from.
+const poolCredentialsNamespace = "openshift-machine-api"
+
+// PoolCredentials is the secret holding the kubeconfig of the infra cluster of a compute pool with
+// its own infra cluster, referenced by the provider spec of its machines.
+type PoolCredentials struct {
+	// PoolName is the name of the machine pool.
+	PoolName string
+	// Kubeconfig is the kubeconfig of the infra cluster of the machine pool.
+	Kubeconfig []byte
+}
+
+// Manifest generates the secret of the machine pool.
+func (params PoolCredentials) Manifest() ([]byte, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubevirttypes.CredentialsSecretName(params.PoolName),
+			Namespace: poolCredentialsNamespace,
+		},
+		Data: map[string][]byte{
+			"kubeconfig": params.Kubeconfig,
+		},
+	}
+	return yaml.Marshal(secret)
+}
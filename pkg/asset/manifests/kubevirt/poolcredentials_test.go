@@ -0,0 +1,25 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolCredentials(t *testing.T) {
+	expected := `apiVersion: v1
+data:
+  kubeconfig: a3ViZWNvbmZpZy1i
+kind: Secret
+metadata:
+  creationTimestamp: null
+  name: kubevirt-credentials-worker-b
+  namespace: openshift-machine-api
+`
+	manifest, err := PoolCredentials{
+		PoolName:   "worker-b",
+		Kubeconfig: []byte("kubeconfig-b"),
+	}.Manifest()
+	assert.NoError(t, err, "failed to create the pool credentials")
+	assert.Equal(t, expected, string(manifest))
+}
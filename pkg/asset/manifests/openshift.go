@@ -3,13 +3,18 @@ package manifests
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/ghodss/yaml"
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
@@ -17,7 +22,9 @@ import (
 	kubeconfig "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	"github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	"github.com/openshift/installer/pkg/asset/machines"
+	"github.com/openshift/installer/pkg/asset/machines/machineconfig"
 	osmachine "github.com/openshift/installer/pkg/asset/machines/openstack"
+	kubevirtmanifests "github.com/openshift/installer/pkg/asset/manifests/kubevirt"
 	openstackmanifests "github.com/openshift/installer/pkg/asset/manifests/openstack"
 	"github.com/openshift/installer/pkg/asset/openshiftinstall"
 	"github.com/openshift/installer/pkg/asset/password"
@@ -185,7 +192,13 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 			},
 		}
 	case kubevirttypes.Name:
-		kubeconfigContent, err := kubeconfig.LoadKubeConfigContent()
+		var kubeconfigContent []byte
+		var err error
+		if installConfig.Config.Kubevirt.ServiceAccount != nil {
+			kubeconfigContent, err = kubeconfig.ServiceAccountKubeConfigContent(context.TODO(), installConfig.Config.Kubevirt)
+		} else {
+			kubeconfigContent, err = kubeconfig.LoadKubeConfigContent(installConfig.Config.Kubevirt.InfraKubeconfigPath, installConfig.Config.Kubevirt.InfraContext)
+		}
 		if err != nil {
 			return err
 		}
@@ -238,6 +251,52 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		assetData["99_private-cluster-outbound-service.yaml"] = applyTemplateData(privateClusterOutbound.Files()[0].Data, templateData)
 	}
 
+	if platform == kubevirttypes.Name && installConfig.Config.Kubevirt.ServiceAccount != nil {
+		serviceAccount := installConfig.Config.Kubevirt.ServiceAccount
+		rotation, err := kubevirtmanifests.CredentialsRotation{
+			Namespace:  serviceAccount.Namespace,
+			Name:       serviceAccount.Name,
+			User:       kubeconfig.ServiceAccountKubeConfigUser,
+			Expiration: kubeconfig.ServiceAccountTokenExpiration,
+		}.Manifest()
+		if err != nil {
+			return errors.Wrap(err, "could not create the kubevirt credentials rotation")
+		}
+		assetData["99_kubevirt-credentials-rotation.yaml"] = rotation
+	}
+
+	if platform == kubevirttypes.Name {
+		for _, pool := range installConfig.Config.Compute {
+			if !pool.Platform.Kubevirt.HasInfraCluster() {
+				continue
+			}
+			kubeconfigContent, err := kubeconfig.LoadKubeConfigContent(pool.Platform.Kubevirt.InfraKubeconfigPath, pool.Platform.Kubevirt.InfraContext)
+			if err != nil {
+				return errors.Wrapf(err, "could not load the infra kubeconfig of machine pool %s", pool.Name)
+			}
+			credentials, err := kubevirtmanifests.PoolCredentials{
+				PoolName:   pool.Name,
+				Kubeconfig: kubeconfigContent,
+			}.Manifest()
+			if err != nil {
+				return errors.Wrapf(err, "could not create the kubevirt credentials of machine pool %s", pool.Name)
+			}
+			assetData[fmt.Sprintf("99_kubevirt-credentials-%s.yaml", pool.Name)] = credentials
+		}
+
+		failureDomains, err := kubevirtFailureDomains(installConfig.Config.Kubevirt)
+		if err != nil {
+			return errors.Wrap(err, "could not create the kubevirt failure domains")
+		}
+		assetData["99_kubevirt-failure-domains.yaml"] = failureDomains
+
+		nodeLabels, err := kubevirtmanifests.NodeLabels{Mappings: installConfig.Config.Kubevirt.NodeLabels}.Manifest()
+		if err != nil {
+			return errors.Wrap(err, "could not create the kubevirt node labels")
+		}
+		assetData["99_kubevirt-node-labels.yaml"] = nodeLabels
+	}
+
 	o.FileList = []*asset.File{}
 	for name, data := range assetData {
 		if len(data) == 0 {
@@ -251,11 +310,40 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 
 	o.FileList = append(o.FileList, openshiftInstall.Files()...)
 
+	for _, manifest := range installConfig.Manifests {
+		o.FileList = append(o.FileList, &asset.File{
+			Filename: filepath.Join(openshiftManifestDir, manifest.Filename),
+			Data:     manifest.Data,
+		})
+	}
+
 	asset.SortFiles(o.FileList)
 
 	return nil
 }
 
+// kubevirtFailureDomains generates the failure domains of the cluster from the topology labels
+// of the infra cluster nodes, or nothing when the credentials are not allowed to list them.
+func kubevirtFailureDomains(platform *kubevirttypes.Platform) ([]byte, error) {
+	client, err := kubeconfig.NewClientFor(platform.InfraKubeconfigPath, platform.InfraContext, platform.InfraCABundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the infra cluster client")
+	}
+	nodes, err := client.ListNodes(context.TODO())
+	if apierrors.IsForbidden(err) {
+		logrus.Info("Skipping the failure domains of the cluster, the infra cluster credentials are not allowed to list the nodes")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the infra cluster nodes")
+	}
+	return kubevirtmanifests.FailureDomains{
+		Nodes:       nodes,
+		ZoneLabel:   platform.InfraNodeLabel(corev1.LabelZoneFailureDomainStable),
+		RegionLabel: platform.InfraNodeLabel(corev1.LabelZoneRegionStable),
+	}.Manifest()
+}
+
 // Files returns the files generated by the asset.
 func (o *Openshift) Files() []*asset.File {
 	return o.FileList
@@ -278,14 +366,49 @@ func (o *Openshift) Load(f asset.FileFetcher) (bool, error) {
 	fileList := append(yamlFileList, ymlFileList...)
 	fileList = append(fileList, jsonFileList...)
 
+	butaneFileList, err := f.FetchByPattern(filepath.Join(openshiftManifestDir, "*.bu"))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load *.bu files")
+	}
+	butaneFiles, err := transpileButaneFiles(butaneFileList)
+	if err != nil {
+		return false, err
+	}
+
 	for _, file := range fileList {
 		if machines.IsMachineManifest(file) {
 			continue
 		}
+		// The manifests rendered from the Butane configs are rendered again
+		if _, ok := butaneFiles[file.Filename]; ok {
+			continue
+		}
 
 		o.FileList = append(o.FileList, file)
 	}
+	for _, file := range butaneFiles {
+		o.FileList = append(o.FileList, file)
+	}
 
 	asset.SortFiles(o.FileList)
 	return len(o.FileList) > 0, nil
 }
+
+// transpileButaneFiles transpiles the Butane configs to MachineConfig manifests, named after
+// the Butane files with a .yaml extension.
+func transpileButaneFiles(files []*asset.File) (map[string]*asset.File, error) {
+	manifests := make(map[string]*asset.File, len(files))
+	for _, file := range files {
+		machineConfig, err := machineconfig.FromButane(file.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to transpile %s", file.Filename)
+		}
+		data, err := yaml.Marshal(machineConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal the MachineConfig of %s", file.Filename)
+		}
+		filename := strings.TrimSuffix(file.Filename, filepath.Ext(file.Filename)) + ".yaml"
+		manifests[filename] = &asset.File{Filename: filename, Data: data}
+	}
+	return manifests, nil
+}
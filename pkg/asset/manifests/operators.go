@@ -13,6 +13,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/types"
@@ -54,12 +55,14 @@ func (m *Manifests) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
+		&releaseimage.Image{},
 		&Ingress{},
 		&DNS{},
 		&Infrastructure{},
 		&Networking{},
 		&Proxy{},
 		&Scheduler{},
+		&APIServer{},
 		&ImageContentSourcePolicy{},
 		&tls.RootCA{},
 		&tls.EtcdSignerCertKey{},
@@ -97,8 +100,10 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	proxy := &Proxy{}
 	scheduler := &Scheduler{}
+	apiServer := &APIServer{}
 	imageContentSourcePolicy := &ImageContentSourcePolicy{}
-	dependencies.Get(installConfig, ingress, dns, network, infra, proxy, scheduler, imageContentSourcePolicy)
+	releaseImage := &releaseimage.Image{}
+	dependencies.Get(installConfig, ingress, dns, network, infra, proxy, scheduler, apiServer, imageContentSourcePolicy, releaseImage)
 
 	redactedConfig, err := redactedInstallConfig(*installConfig.Config)
 	if err != nil {
@@ -119,7 +124,11 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 			Data:     kubeSysConfigData,
 		},
 	}
-	m.FileList = append(m.FileList, m.generateBootKubeManifests(dependencies)...)
+	pullSecret, err := installconfig.ScopedPullSecret(installConfig.Config, releaseImage.PullSpec)
+	if err != nil {
+		return err
+	}
+	m.FileList = append(m.FileList, m.generateBootKubeManifests(dependencies, pullSecret)...)
 
 	m.FileList = append(m.FileList, ingress.Files()...)
 	m.FileList = append(m.FileList, dns.Files()...)
@@ -127,6 +136,7 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	m.FileList = append(m.FileList, infra.Files()...)
 	m.FileList = append(m.FileList, proxy.Files()...)
 	m.FileList = append(m.FileList, scheduler.Files()...)
+	m.FileList = append(m.FileList, apiServer.Files()...)
 	m.FileList = append(m.FileList, imageContentSourcePolicy.Files()...)
 
 	asset.SortFiles(m.FileList)
@@ -139,7 +149,7 @@ func (m *Manifests) Files() []*asset.File {
 	return m.FileList
 }
 
-func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*asset.File {
+func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents, pullSecret string) []*asset.File {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	mcsCertKey := &tls.MCSCertKey{}
@@ -177,7 +187,7 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 		EtcdSignerKey:              base64.StdEncoding.EncodeToString(etcdSignerCertKey.Key()),
 		McsTLSCert:                 base64.StdEncoding.EncodeToString(mcsCertKey.Cert()),
 		McsTLSKey:                  base64.StdEncoding.EncodeToString(mcsCertKey.Key()),
-		PullSecretBase64:           base64.StdEncoding.EncodeToString([]byte(installConfig.Config.PullSecret)),
+		PullSecretBase64:           base64.StdEncoding.EncodeToString([]byte(pullSecret)),
 		RootCaCert:                 string(rootCA.Cert()),
 	}
 
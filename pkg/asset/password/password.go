@@ -21,7 +21,7 @@ type KubeadminPassword struct {
 	File         *asset.File
 }
 
-var _ asset.WritableAsset = (*KubeadminPassword)(nil)
+var _ asset.FileDescriber = (*KubeadminPassword)(nil)
 
 // Dependencies returns no dependencies.
 func (a *KubeadminPassword) Dependencies() []asset.Asset {
@@ -99,6 +99,11 @@ func (a *KubeadminPassword) Files() []*asset.File {
 	return []*asset.File{}
 }
 
+// FileNames returns the name of the password file.
+func (a *KubeadminPassword) FileNames() []string {
+	return []string{kubeadminPasswordPath}
+}
+
 // Load returns false as the password file is read-only.
 func (a *KubeadminPassword) Load(f asset.FileFetcher) (found bool, err error) {
 	return false, nil
@@ -14,6 +14,18 @@ import (
 type Image struct {
 	PullSpec   string
 	Repository string
+	// Overridden is set when the release image is overridden with --release-image or
+	// OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE instead of being the default one.
+	Overridden bool
+}
+
+// override is the release image set with SetOverride.
+var override string
+
+// SetOverride overrides the release image, taking precedence over
+// OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE.
+func SetOverride(pullSpec string) {
+	override = pullSpec
 }
 
 var _ asset.Asset = (*Image)(nil)
@@ -25,19 +37,17 @@ func (a *Image) Dependencies() []asset.Asset {
 
 // Generate creates the asset using the dependencies.
 func (a *Image) Generate(dependencies asset.Parents) error {
-	var pullSpec string
-	if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
+	pullSpec, overridden, err := PullSpec()
+	if err != nil {
+		return err
+	}
+	if overridden {
 		logrus.Warn("Found override for release image. Please be warned, this is not advised")
-		pullSpec = ri
 	} else {
-		var err error
-		pullSpec, err = Default()
-		if err != nil {
-			return errors.Wrap(err, "failed to load default release image")
-		}
 		logrus.Debugf("Using internal constant for release image %s", pullSpec)
 	}
 	a.PullSpec = pullSpec
+	a.Overridden = overridden
 
 	ref, err := dockerref.ParseNamed(pullSpec)
 	if err != nil {
@@ -48,6 +58,22 @@ func (a *Image) Generate(dependencies asset.Parents) error {
 	return nil
 }
 
+// PullSpec returns the pull spec of the release image, and whether it is overridden with
+// SetOverride or OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE instead of being the default one.
+func PullSpec() (string, bool, error) {
+	if override != "" {
+		return override, true, nil
+	}
+	if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
+		return ri, true, nil
+	}
+	pullSpec, err := Default()
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to load default release image")
+	}
+	return pullSpec, false, nil
+}
+
 // Name is the human friendly name for the asset.
 func (a *Image) Name() string {
 	return "Release Image Pull Spec"
@@ -0,0 +1,157 @@
+package releaseimage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const (
+	// DefaultSignatureStore is the store serving the signatures of the OpenShift releases.
+	DefaultSignatureStore = "https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release"
+
+	// signatureType is the type of the signatures of the release images, as the cluster-version
+	// operator verifies them.
+	signatureType = "atomic container signature"
+
+	// maxSignatures bounds the signatures of a release image fetched from a store.
+	maxSignatures = 16
+)
+
+// signature is the signed content of a release image signature.
+type signature struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// DigestPullSpec returns the digest of the release image, which must be referenced by digest to
+// be verified.
+func DigestPullSpec(pullSpec string) (string, error) {
+	ref, err := dockerref.ParseNamed(pullSpec)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid release image %s", pullSpec)
+	}
+	digested, ok := ref.(dockerref.Digested)
+	if !ok {
+		return "", errors.Errorf("the release image %s must be referenced by digest, e.g. quay.io/openshift-release-dev/ocp-release@sha256:...", pullSpec)
+	}
+	return digested.Digest().String(), nil
+}
+
+// LoadKeyring reads the OpenPGP keyring, armored or binary, trusted to sign the release images.
+func LoadKeyring(path string) (openpgp.EntityList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the release image keyring")
+	}
+	var keyring openpgp.EntityList
+	if block, err := armor.Decode(bytes.NewReader(data)); err == nil {
+		keyring, err = openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid release image keyring %s", path)
+		}
+	} else if keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+		return nil, errors.Wrapf(err, "invalid release image keyring %s", path)
+	}
+	if len(keyring) == 0 {
+		return nil, errors.Errorf("the release image keyring %s holds no keys", path)
+	}
+	return keyring, nil
+}
+
+// VerifySignature checks that one of the signature stores serves a signature of the release
+// image, referenced by digest, signed by a key of the keyring. The signatures of the image are
+// fetched from <store>/<algorithm>=<digest>/signature-<n>, as the cluster-version operator does.
+func VerifySignature(ctx context.Context, pullSpec string, stores []string, keyring openpgp.EntityList) error {
+	digest, err := DigestPullSpec(pullSpec)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	var errs []string
+	for _, store := range stores {
+		err := verifyFromStore(ctx, client, strings.TrimSuffix(store, "/"), digest, keyring)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", store, err))
+	}
+	return errors.Errorf("no valid signature of the release image %s found: %s", pullSpec, strings.Join(errs, "; "))
+}
+
+func verifyFromStore(ctx context.Context, client *http.Client, store string, digest string, keyring openpgp.EntityList) error {
+	base := fmt.Sprintf("%s/%s", store, strings.Replace(digest, ":", "=", 1))
+	for i := 1; i <= maxSignatures; i++ {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/signature-%d", base, i), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			if i == 1 {
+				return errors.New("no signatures")
+			}
+			return errors.New("no signature signed by the keyring")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("unexpected status %s fetching signature-%d", resp.Status, i)
+		}
+		if verifySignature(data, digest, keyring) == nil {
+			return nil
+		}
+	}
+	return errors.New("no signature signed by the keyring")
+}
+
+// verifySignature checks that the signature is signed by a key of the keyring, and signs the
+// digest.
+func verifySignature(data []byte, digest string, keyring openpgp.EntityList) error {
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), keyring, nil, nil)
+	if err != nil {
+		return err
+	}
+	if !md.IsSigned || md.SignedBy == nil {
+		return errors.New("not signed by the keyring")
+	}
+	content, err := ioutil.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return err
+	}
+	// The signature is only checked once the body is read
+	if md.SignatureError != nil {
+		return md.SignatureError
+	}
+	var s signature
+	if err := json.Unmarshal(content, &s); err != nil {
+		return errors.Wrap(err, "invalid signature content")
+	}
+	if s.Critical.Type != signatureType {
+		return errors.Errorf("unexpected signature type %q", s.Critical.Type)
+	}
+	if s.Critical.Image.DockerManifestDigest != digest {
+		return errors.Errorf("the signature is for %s", s.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package releaseimage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const (
+	testDigest      = "sha256:397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c"
+	testOtherDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// testSignature returns the signature of the digest signed by the entity.
+func testSignature(t *testing.T, signer *openpgp.Entity, digest string) []byte {
+	var buf bytes.Buffer
+	w, err := openpgp.Sign(&buf, signer, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(w, `{"critical":{"identity":{"docker-reference":"quay.io/openshift-release-dev/ocp-release:4.7.0-x86_64"},"image":{"docker-manifest-digest":%q},"type":"atomic container signature"},"optional":{"creator":"test"}}`, digest)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testEntity returns a new entity signing with SHA-256.
+func testEntity(t *testing.T, name string) *openpgp.Entity {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, identity := range entity.Identities {
+		identity.SelfSignature.PreferredHash = []uint8{8} // SHA-256
+		if err := identity.SelfSignature.SignUserId(identity.UserId.Id, entity.PrimaryKey, entity.PrivateKey, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return entity
+}
+
+func TestVerifySignature(t *testing.T) {
+	signer := testEntity(t, "release")
+	other := testEntity(t, "other")
+
+	dir, err := ioutil.TempDir("", "keyring")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var keyringData bytes.Buffer
+	w, err := armor.Encode(&keyringData, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	keyringFile := filepath.Join(dir, "keyring.asc")
+	if err := ioutil.WriteFile(keyringFile, keyringData.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := LoadKeyring(keyringFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signatures := map[string][]byte{
+		"/valid/sha256=397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c/signature-1":         testSignature(t, other, testDigest),
+		"/valid/sha256=397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c/signature-2":         testSignature(t, signer, testDigest),
+		"/other-key/sha256=397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c/signature-1":     testSignature(t, other, testDigest),
+		"/other-digest/sha256=397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c/signature-1":  testSignature(t, signer, testOtherDigest),
+		"/not-signature/sha256=397c867cc10bcc90cf05ae9b71dd3de6000535e27cb6c704d9f503879202582c/signature-1": []byte("not a signature"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature, ok := signatures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(signature)
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name          string
+		pullSpec      string
+		stores        []string
+		expectedError string
+	}{
+		{
+			name:     "valid",
+			pullSpec: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:   []string{server.URL + "/valid"},
+		},
+		{
+			name:     "valid in second store",
+			pullSpec: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:   []string{server.URL + "/missing", server.URL + "/valid/"},
+		},
+		{
+			name:          "by tag",
+			pullSpec:      "quay.io/openshift-release-dev/ocp-release:4.7.0-x86_64",
+			stores:        []string{server.URL + "/valid"},
+			expectedError: `^the release image quay.io/openshift-release-dev/ocp-release:4.7.0-x86_64 must be referenced by digest, .*$`,
+		},
+		{
+			name:          "no signatures",
+			pullSpec:      "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:        []string{server.URL + "/missing"},
+			expectedError: `^no valid signature of the release image .* found: .*/missing: no signatures$`,
+		},
+		{
+			name:          "other key",
+			pullSpec:      "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:        []string{server.URL + "/other-key"},
+			expectedError: `^no valid signature of the release image .* found: .*/other-key: no signature signed by the keyring$`,
+		},
+		{
+			name:          "other digest",
+			pullSpec:      "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:        []string{server.URL + "/other-digest"},
+			expectedError: `^no valid signature of the release image .* found: .*/other-digest: no signature signed by the keyring$`,
+		},
+		{
+			name:          "not a signature",
+			pullSpec:      "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			stores:        []string{server.URL + "/not-signature"},
+			expectedError: `^no valid signature of the release image .* found: .*/not-signature: no signature signed by the keyring$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifySignature(context.Background(), tc.pullSpec, tc.stores, keyring)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -99,6 +99,9 @@ func osImage(config *types.InstallConfig) (string, error) {
 	case ovirt.Name:
 		osimage, err = rhcos.OpenStack(ctx, arch)
 	case kubevirt.Name:
+		// The VMs boot the openstack qcow2 image, whose URL is read from the RHCOS build
+		// metadata embedded in the installer (data/data/rhcos-<arch>.json), unless the
+		// OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE environment variable replaces it in Generate
 		osimage, err = rhcos.OpenStack(ctx, arch)
 	case azure.Name:
 		osimage, err = rhcos.VHD(ctx, arch)
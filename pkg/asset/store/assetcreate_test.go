@@ -27,7 +27,7 @@ const userProvidedAssets = `{
     "none": {}
   },
   "*installconfig.pullSecret": {
-    "PullSecret": "{\"auths\": {\"example.com\": {\"auth\": \"test-auth\"}}}\n"
+    "PullSecret": "{\"auths\": {\"example.com\": {\"auth\": \"dXNlcjpwYXNzd29yZA==\"}}}\n"
   },
   "*installconfig.sshPublicKey": {}
 }`
@@ -9,6 +9,12 @@ import (
 
 type fileFetcher struct {
 	directory string
+	lenient   bool
+}
+
+// Lenient returns whether the files are loaded leniently.
+func (f *fileFetcher) Lenient() bool {
+	return f.lenient
 }
 
 // FetchByName returns the file with the given name.
@@ -174,3 +174,26 @@ func TestFetchByPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestNewStoreLenient(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "openshift-install-")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, lenient := range []bool{false, true} {
+		var options []Options
+		if lenient {
+			options = append(options, WithLenient())
+		}
+		store, err := newStore(tempDir, options...)
+		if !assert.NoError(t, err) {
+			return
+		}
+		fetcher, ok := store.fileFetcher.(asset.LenientFileFetcher)
+		if assert.True(t, ok) {
+			assert.Equal(t, lenient, fetcher.Lenient())
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package store
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/vincent-petithory/dataurl"
+)
+
+// redactedValue replaces the secrets of the assets shown to users.
+const redactedValue = "REDACTED"
+
+var (
+	// secretKeyPattern matches the JSON keys of the assets holding secrets.
+	secretKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|privkey|privatekey|^key$|^keyraw$)`)
+
+	// secretContentPattern matches the contents of files and strings holding secrets.
+	secretContentPattern = regexp.MustCompile(`PRIVATE KEY-----|"auths"|pullSecret|client-key-data|(?i)password|token:`)
+
+	// dataURLPattern matches the data URLs embedded in the contents of files and strings, e.g.
+	// the files of the Ignition configs held as JSON strings by the Terraform variables.
+	dataURLPattern = regexp.MustCompile(`data:[^,\s"\\]*,[^\s"\\]*`)
+
+	// secretAssets are the assets whose contents hold secrets throughout, which cannot all be
+	// told from their keys or contents: the Terraform variables and state, the Ignition configs
+	// and the kubeconfigs. All their strings but the names of their files are redacted.
+	secretAssets = map[string]bool{
+		"*bootstrap.Bootstrap":            true,
+		"*cluster.Cluster":                true,
+		"*cluster.TerraformVariables":     true,
+		"*kubeconfig.AdminClient":         true,
+		"*kubeconfig.AdminInternalClient": true,
+		"*kubeconfig.Kubelet":             true,
+		"*kubeconfig.LoopbackClient":      true,
+		"*machine.Master":                 true,
+		"*machine.Worker":                 true,
+	}
+)
+
+// StateAssetNames returns the names of the assets stored in the state file of the directory.
+func StateAssetNames(dir string) ([]string, error) {
+	s, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(s.stateFileAssets))
+	for name := range s.stateFileAssets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ShowStateAsset returns the indented JSON of the asset stored in the state file of the
+// directory, with the contents of its files as text. The name is the name of the asset in
+// the state file (e.g. *installconfig.InstallConfig), with or without its package, matched
+// case-insensitively. Unless includeSecrets is set, the secrets are redacted.
+func ShowStateAsset(dir string, name string, includeSecrets bool) ([]byte, error) {
+	names, err := StateAssetNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	key, err := matchAssetName(names, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(s.stateFileAssets[key], &value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal asset %s", key)
+	}
+	if !includeSecrets && secretAssets[key] {
+		value = redactAll("", value)
+	} else {
+		value = showValue("", value, includeSecrets)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// matchAssetName returns the state file name matching the name given by the user.
+func matchAssetName(names []string, name string) (string, error) {
+	normalize := func(n string) string {
+		return strings.ToLower(strings.TrimPrefix(n, "*"))
+	}
+	var matches []string
+	for _, n := range names {
+		full := normalize(n)
+		short := full[strings.LastIndex(full, ".")+1:]
+		if normalize(name) == full || strings.ToLower(name) == short {
+			matches = append(matches, n)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", errors.Errorf("asset %q is not found in the state file", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", errors.Errorf("asset %q is ambiguous, it matches %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// showValue returns the value for users: the base64-encoded Data of the files are replaced by
+// their Contents when they are text, and, unless includeSecrets is set, the secrets are redacted.
+func showValue(key string, value interface{}, includeSecrets bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if isFile(v) {
+			v = showFile(v)
+		}
+		for k, child := range v {
+			v[k] = showValue(k, child, includeSecrets)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = showValue(key, child, includeSecrets)
+		}
+		return v
+	case string:
+		if !includeSecrets && v != "" && (secretKeyPattern.MatchString(key) || isSecret(v)) {
+			return redactedValue
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactAll returns the value with all its strings redacted, except the names of the files.
+func redactAll(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if isFile(v) {
+			return map[string]interface{}{
+				"Filename": v["Filename"],
+				"Contents": redactedValue,
+			}
+		}
+		for k, child := range v {
+			v[k] = redactAll(k, child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactAll(key, child)
+		}
+		return v
+	case string:
+		if v == "" {
+			return v
+		}
+		return redactedValue
+	default:
+		return v
+	}
+}
+
+// isFile returns whether the object is an asset.File.
+func isFile(object map[string]interface{}) bool {
+	if len(object) != 2 {
+		return false
+	}
+	_, hasFilename := object["Filename"].(string)
+	_, hasData := object["Data"]
+	return hasFilename && hasData
+}
+
+// showFile replaces the base64-encoded Data of the file by its Contents when it is text.
+func showFile(file map[string]interface{}) map[string]interface{} {
+	encoded, ok := file["Data"].(string)
+	if !ok {
+		return file
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || !utf8.Valid(data) {
+		return file
+	}
+	return map[string]interface{}{
+		"Filename": file["Filename"],
+		"Contents": string(data),
+	}
+}
+
+// isSecret returns whether the string holds a secret, either as is, base64-encoded or in the
+// data URLs it holds, as the files of the Ignition configs.
+func isSecret(value string) bool {
+	if secretContentPattern.MatchString(value) {
+		return true
+	}
+	if data, err := base64.StdEncoding.DecodeString(value); err == nil && secretContentPattern.Match(data) {
+		return true
+	}
+	for _, match := range dataURLPattern.FindAllString(value, -1) {
+		if u, err := dataurl.DecodeString(match); err == nil && secretContentPattern.Match(u.Data) {
+			return true
+		}
+	}
+	return false
+}
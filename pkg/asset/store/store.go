@@ -2,15 +2,17 @@ package store
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/statecrypt"
 )
 
 const (
@@ -42,6 +44,11 @@ type assetState struct {
 	// presentOnDisk is true if the asset in on-disk. This is set whether the
 	// asset is sourced from on-disk or not. It is used in purging consumed assets.
 	presentOnDisk bool
+	// mutex serializes the fetches of the asset, which may be fetched concurrently as the
+	// dependency of several assets.
+	mutex sync.Mutex
+	// err is the error fetching the asset in the current Fetch, not to generate it again.
+	err error
 }
 
 // storeImpl is the implementation of Store.
@@ -50,19 +57,57 @@ type storeImpl struct {
 	assets          map[reflect.Type]*assetState
 	stateFileAssets map[string]json.RawMessage
 	fileFetcher     asset.FileFetcher
+	// dryRun leaves the state file and the consumed assets in the directory untouched on Fetch.
+	dryRun bool
+	// concurrency is the maximum number of assets generated concurrently. The dependencies of
+	// an asset are fetched one after the other when it is 1 or less.
+	concurrency int
+	// generating holds a token for each asset being generated, up to concurrency.
+	generating chan struct{}
+	// interactive is held exclusively by the interactive assets being generated, for the
+	// prompts not to interleave with the generation of the other assets.
+	interactive sync.RWMutex
+}
+
+// Options is a function that modifies the store being created.
+type Options func(store *storeImpl)
+
+// WithLenient makes the store load the assets leniently from the files of the directory,
+// ignoring the fields the installer doesn't know instead of rejecting them.
+func WithLenient() Options {
+	return func(store *storeImpl) {
+		store.fileFetcher = &fileFetcher{directory: store.directory, lenient: true}
+	}
 }
 
 // NewStore returns an asset store that implements the asset.Store interface.
-func NewStore(dir string) (asset.Store, error) {
-	return newStore(dir)
+func NewStore(dir string, options ...Options) (asset.Store, error) {
+	return newStore(dir, options...)
+}
+
+// NewDryRunStore returns an asset store fetching the assets as the store returned by NewStore
+// does, without saving the state file nor purging the consumed assets from the directory,
+// e.g. to compare the assets with the files in the directory before writing them.
+func NewDryRunStore(dir string, options ...Options) (asset.Store, error) {
+	store, err := newStore(dir, options...)
+	if err != nil {
+		return nil, err
+	}
+	store.dryRun = true
+	return store, nil
 }
 
-func newStore(dir string) (*storeImpl, error) {
+func newStore(dir string, options ...Options) (*storeImpl, error) {
 	store := &storeImpl{
 		directory:   dir,
 		fileFetcher: &fileFetcher{directory: dir},
 		assets:      map[reflect.Type]*assetState{},
+		concurrency: runtime.GOMAXPROCS(0),
 	}
+	for _, option := range options {
+		option(store)
+	}
+	store.generating = make(chan struct{}, store.concurrency)
 
 	if err := store.loadStateFile(); err != nil {
 		return nil, err
@@ -74,9 +119,15 @@ func newStore(dir string) (*storeImpl, error) {
 // dependencies if necessary. When purging consumed assets, none of the
 // assets in preserved will be purged.
 func (s *storeImpl) Fetch(a asset.Asset, preserved ...asset.WritableAsset) error {
+	for _, state := range s.assets {
+		state.err = nil
+	}
 	if err := s.fetch(a, ""); err != nil {
 		return err
 	}
+	if s.dryRun {
+		return nil
+	}
 	if err := s.saveStateFile(); err != nil {
 		return errors.Wrap(err, "failed to save state")
 	}
@@ -130,7 +181,7 @@ func (s *storeImpl) DestroyState() error {
 func (s *storeImpl) loadStateFile() error {
 	path := filepath.Join(s.directory, stateFileName)
 	assets := map[string]json.RawMessage{}
-	data, err := ioutil.ReadFile(path)
+	data, err := statecrypt.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -184,7 +235,7 @@ func (s *storeImpl) saveStateFile() error {
 	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
 		return err
 	}
-	if err := ioutil.WriteFile(path, data, 0640); err != nil {
+	if err := statecrypt.WriteFile(path, data, 0640); err != nil {
 		return err
 	}
 	return nil
@@ -192,10 +243,13 @@ func (s *storeImpl) saveStateFile() error {
 
 // fetch populates the given asset, generating it and its dependencies if
 // necessary, and returns whether or not the asset had to be regenerated and
-// any errors.
+// any errors. The independent dependencies are fetched concurrently, each
+// asset being generated once its own dependencies are.
 func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 	logrus.Debugf("%sFetching %s...", indent, a.Name())
 
+	// Loading the asset loads all of its ancestors, so that only the target
+	// of Fetch may be missing and the map is not written to concurrently.
 	assetState, ok := s.assets[reflect.TypeOf(a)]
 	if !ok {
 		if _, err := s.load(a, ""); err != nil {
@@ -204,6 +258,12 @@ func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 		assetState = s.assets[reflect.TypeOf(a)]
 	}
 
+	assetState.mutex.Lock()
+	defer assetState.mutex.Unlock()
+	if assetState.err != nil {
+		return assetState.err
+	}
+
 	// Return immediately if the asset has been fetched before,
 	// this is because we are doing a depth-first-search, it's guaranteed
 	// that we always fetch the parent before children, so we don't need
@@ -216,22 +276,70 @@ func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 
 	// Re-generate the asset
 	dependencies := a.Dependencies()
-	parents := make(asset.Parents, len(dependencies))
-	for _, d := range dependencies {
-		if err := s.fetch(d, increaseIndent(indent)); err != nil {
-			return errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
-		}
-		parents.Add(d)
+	if err := s.fetchDependencies(dependencies, increaseIndent(indent)); err != nil {
+		assetState.err = errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
+		return assetState.err
 	}
+	parents := make(asset.Parents, len(dependencies))
+	parents.Add(dependencies...)
 	logrus.Debugf("%sGenerating %s...", indent, a.Name())
-	if err := a.Generate(parents); err != nil {
-		return errors.Wrapf(err, "failed to generate asset %q", a.Name())
+	if err := s.generate(a, parents); err != nil {
+		assetState.err = errors.Wrapf(err, "failed to generate asset %q", a.Name())
+		return assetState.err
 	}
 	assetState.asset = a
 	assetState.source = generatedSource
 	return nil
 }
 
+// fetchDependencies fetches the dependencies, concurrently unless the concurrency of the store
+// is 1 or less, and returns the error of the first dependency failing to be fetched in order.
+func (s *storeImpl) fetchDependencies(dependencies []asset.Asset, indent string) error {
+	if s.concurrency <= 1 || len(dependencies) <= 1 {
+		for _, d := range dependencies {
+			if err := s.fetch(d, indent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(dependencies))
+	var wg sync.WaitGroup
+	for i, d := range dependencies {
+		wg.Add(1)
+		go func(i int, d asset.Asset) {
+			defer wg.Done()
+			errs[i] = s.fetch(d, indent)
+		}(i, d)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate generates the asset from its parents, with at most concurrency assets being
+// generated at once, and interactive assets alone.
+func (s *storeImpl) generate(a asset.Asset, parents asset.Parents) error {
+	if _, ok := a.(asset.Interactive); ok {
+		s.interactive.Lock()
+		defer s.interactive.Unlock()
+		return a.Generate(parents)
+	}
+
+	s.interactive.RLock()
+	defer s.interactive.RUnlock()
+	if s.generating != nil {
+		s.generating <- struct{}{}
+		defer func() { <-s.generating }()
+	}
+	return a.Generate(parents)
+}
+
 // load loads the asset and all of its ancestors from on-disk and the state file.
 func (s *storeImpl) load(a asset.Asset, indent string) (*assetState, error) {
 	logrus.Debugf("%sLoading %s...", indent, a.Name())
@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -19,12 +22,17 @@ var (
 	generationLog []string
 	dependencies  map[reflect.Type][]asset.Asset
 	onDiskAssets  map[reflect.Type]bool
+	// generationHook is called with the name of each asset generated, concurrently when the
+	// assets are generated concurrently.
+	generationHook func(name string)
+	generationMu   sync.Mutex
 )
 
 func clearAssetBehaviors() {
 	generationLog = []string{}
 	dependencies = map[reflect.Type][]asset.Asset{}
 	onDiskAssets = map[reflect.Type]bool{}
+	generationHook = nil
 }
 
 func dependenciesTestStoreAsset(a asset.Asset) []asset.Asset {
@@ -32,6 +40,11 @@ func dependenciesTestStoreAsset(a asset.Asset) []asset.Asset {
 }
 
 func generateTestStoreAsset(a asset.Asset) error {
+	if generationHook != nil {
+		generationHook(a.Name())
+	}
+	generationMu.Lock()
+	defer generationMu.Unlock()
 	generationLog = append(generationLog, a.Name())
 	return nil
 }
@@ -132,6 +145,22 @@ func (a *testStoreAssetD) Load(asset.FileFetcher) (bool, error) {
 	return loadTestStoreAsset(a)
 }
 
+type testStoreInteractiveAsset struct{}
+
+func (a *testStoreInteractiveAsset) Name() string {
+	return "interactive"
+}
+
+func (a *testStoreInteractiveAsset) Dependencies() []asset.Asset {
+	return dependenciesTestStoreAsset(a)
+}
+
+func (a *testStoreInteractiveAsset) Generate(asset.Parents) error {
+	return generateTestStoreAsset(a)
+}
+
+func (a *testStoreInteractiveAsset) Interactive() {}
+
 func newTestStoreAsset(name string) asset.Asset {
 	switch name {
 	case "a":
@@ -484,3 +513,106 @@ func TestStoreLoadOnDiskAssets(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRunStoreFetch(t *testing.T) {
+	clearAssetBehaviors()
+
+	tempDir, err := ioutil.TempDir("", "TestDryRunStoreFetch")
+	if err != nil {
+		t.Fatalf("could not create the temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := ioutil.WriteFile(filepath.Join(tempDir, "a"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	dependencies[reflect.TypeOf(&testStoreAssetB{})] = []asset.Asset{&testStoreAssetA{}}
+	onDiskAssets[reflect.TypeOf(&testStoreAssetA{})] = true
+
+	store, err := NewDryRunStore(tempDir)
+	if !assert.NoError(t, err, "unexpected error creating store") {
+		t.Fatal()
+	}
+	err = store.Fetch(&testStoreAssetB{})
+	if !assert.NoError(t, err, "unexpected error fetching asset") {
+		t.Fatal()
+	}
+	assert.Equal(t, []string{"b"}, generationLog, "unexpected assets generated")
+
+	// The consumed asset is not purged and the state file is not written
+	assert.FileExists(t, filepath.Join(tempDir, "a"))
+	_, err = os.Stat(filepath.Join(tempDir, stateFileName))
+	assert.True(t, os.IsNotExist(err), "unexpected state file")
+}
+
+func TestStoreFetchConcurrently(t *testing.T) {
+	clearAssetBehaviors()
+	dependencies[reflect.TypeOf(&testStoreAssetA{})] = []asset.Asset{&testStoreAssetB{}, &testStoreAssetC{}}
+	dependencies[reflect.TypeOf(&testStoreAssetB{})] = []asset.Asset{&testStoreAssetD{}}
+	dependencies[reflect.TypeOf(&testStoreAssetC{})] = []asset.Asset{&testStoreAssetD{}}
+
+	// b and c are generated only when both are being generated
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	timedOut := int32(0)
+	generationHook = func(name string) {
+		if name != "b" && name != "c" {
+			return
+		}
+		barrier.Done()
+		done := make(chan struct{})
+		go func() {
+			barrier.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			atomic.StoreInt32(&timedOut, 1)
+		}
+	}
+
+	store := &storeImpl{
+		assets:      map[reflect.Type]*assetState{},
+		concurrency: 2,
+		generating:  make(chan struct{}, 2),
+		dryRun:      true,
+	}
+	err := store.Fetch(&testStoreAssetA{})
+	assert.NoError(t, err, "error fetching asset")
+	assert.Zero(t, atomic.LoadInt32(&timedOut), "b and c were not generated concurrently")
+	if assert.Len(t, generationLog, 4) {
+		assert.Equal(t, "d", generationLog[0], "d generated once, before b and c")
+		assert.ElementsMatch(t, []string{"b", "c"}, generationLog[1:3])
+		assert.Equal(t, "a", generationLog[3], "a generated after b and c")
+	}
+}
+
+func TestStoreFetchInteractiveAlone(t *testing.T) {
+	clearAssetBehaviors()
+	dependencies[reflect.TypeOf(&testStoreAssetA{})] = []asset.Asset{&testStoreAssetB{}, &testStoreAssetC{}, &testStoreInteractiveAsset{}}
+
+	var running, overlapped int32
+	generationHook = func(name string) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		if name == "interactive" && n != 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if name == "interactive" && atomic.LoadInt32(&running) != 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+	}
+
+	store := &storeImpl{
+		assets:      map[reflect.Type]*assetState{},
+		concurrency: 3,
+		generating:  make(chan struct{}, 3),
+		dryRun:      true,
+	}
+	err := store.Fetch(&testStoreAssetA{})
+	assert.NoError(t, err, "error fetching asset")
+	assert.Len(t, generationLog, 4)
+	assert.Zero(t, atomic.LoadInt32(&overlapped), "the interactive asset was generated concurrently with other assets")
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// UpdateState rewrites the state file of the directory, passing each of the assets found in it
+// to update, which changes the asset as it is to be stored. The asset is removed from the state
+// file when update returns false. It is used to keep the state file in sync with the files of
+// the assets rewritten after the installer generated them, and to keep the credentials out of
+// the state file once they are kept in a secret store.
+func UpdateState(directory string, update func(asset.Asset) bool, assets ...asset.Asset) error {
+	s := &storeImpl{directory: directory}
+	if err := s.loadStateFile(); err != nil {
+		return err
+	}
+	updated := false
+	for _, a := range assets {
+		if !s.isAssetInState(a) {
+			continue
+		}
+		if err := s.loadAssetFromState(a); err != nil {
+			return err
+		}
+		updated = true
+		key := reflect.TypeOf(a).String()
+		if !update(a) {
+			delete(s.stateFileAssets, key)
+			continue
+		}
+		data, err := json.MarshalIndent(a, "", "    ")
+		if err != nil {
+			return err
+		}
+		s.stateFileAssets[key] = json.RawMessage(data)
+	}
+	if !updated {
+		return nil
+	}
+	return s.saveStateFile()
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+type testRedactedAsset struct {
+	Secret string
+	Hash   string
+}
+
+func (a *testRedactedAsset) Name() string {
+	return "redacted"
+}
+
+func (a *testRedactedAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testRedactedAsset) Generate(asset.Parents) error {
+	return nil
+}
+
+func TestUpdateState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestUpdateState")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	state := `{
+    "*store.testRedactedAsset": {"Secret": "secret", "Hash": "hash"},
+    "*store.testStoreAssetA": {},
+    "*store.testStoreAssetB": {}
+}`
+	if err := ioutil.WriteFile(filepath.Join(dir, stateFileName), []byte(state), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateState(dir, func(a asset.Asset) bool {
+		if r, ok := a.(*testRedactedAsset); ok {
+			r.Secret = ""
+			return true
+		}
+		return false
+	}, &testRedactedAsset{}, &testStoreAssetA{}, &testStoreAssetC{})
+	assert.NoError(t, err)
+
+	s := &storeImpl{directory: dir}
+	assert.NoError(t, s.loadStateFile())
+	assert.False(t, s.isAssetInState(&testStoreAssetA{}), "removed asset kept in the state file")
+	assert.True(t, s.isAssetInState(&testStoreAssetB{}), "other asset removed from the state file")
+
+	redacted := &testRedactedAsset{}
+	assert.NoError(t, s.loadAssetFromState(redacted))
+	assert.Equal(t, &testRedactedAsset{Hash: "hash"}, redacted)
+}
+
+func TestUpdateStateNoStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestUpdateState")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = UpdateState(dir, func(asset.Asset) bool { return false }, &testStoreAssetA{})
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, stateFileName))
+	assert.True(t, os.IsNotExist(err))
+}
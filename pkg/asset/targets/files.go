@@ -0,0 +1,38 @@
+package targets
+
+import (
+	"reflect"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// FileNames returns the names of the files, or the globs of the files whose names depend on
+// the install-config, written by the asset in the assets directory. They are the files the
+// asset is loaded from, or the files described by the asset when it is never loaded.
+func FileNames(a asset.WritableAsset) []string {
+	if describer, ok := a.(asset.FileDescriber); ok {
+		return describer.FileNames()
+	}
+	// A new asset of the same type is loaded, leaving the given one unchanged
+	loaded := reflect.New(reflect.TypeOf(a).Elem()).Interface().(asset.WritableAsset)
+	fetcher := &recordingFileFetcher{}
+	// The load fails on the empty files returned, once it fetched the files of the asset
+	loaded.Load(fetcher)
+	return fetcher.names
+}
+
+// recordingFileFetcher records the names and the patterns of the files fetched, returning
+// empty files for the names and no files for the patterns.
+type recordingFileFetcher struct {
+	names []string
+}
+
+func (f *recordingFileFetcher) FetchByName(name string) (*asset.File, error) {
+	f.names = append(f.names, name)
+	return &asset.File{Filename: name}, nil
+}
+
+func (f *recordingFileFetcher) FetchByPattern(pattern string) ([]*asset.File, error) {
+	f.names = append(f.names, pattern)
+	return nil, nil
+}
@@ -0,0 +1,64 @@
+package targets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/ignition/machine"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/manifests"
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+func TestFileNames(t *testing.T) {
+	cases := []struct {
+		name     string
+		asset    asset.WritableAsset
+		expected []string
+	}{
+		{
+			name:     "install-config",
+			asset:    &installconfig.InstallConfig{},
+			expected: []string{"install-config.yaml"},
+		},
+		{
+			name:     "manifests patterns",
+			asset:    &manifests.Manifests{},
+			expected: []string{"manifests/*.yaml", "manifests/*.yml", "manifests/*.json"},
+		},
+		{
+			name:     "ignition config",
+			asset:    &machine.Master{},
+			expected: []string{"master.ign"},
+		},
+		{
+			name:     "all the files fetched",
+			asset:    &cluster.TerraformVariables{},
+			expected: []string{"terraform.tfvars.json", "terraform.*.auto.tfvars.json"},
+		},
+		{
+			name:     "described files",
+			asset:    &cluster.Metadata{},
+			expected: []string{"metadata.json"},
+		},
+		{
+			name:     "described cert key",
+			asset:    &tls.JournalCertKey{},
+			expected: []string{"tls/journal-gatewayd.key", "tls/journal-gatewayd.crt"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FileNames(tc.asset))
+		})
+	}
+}
+
+func TestFileNamesLeavesAssetUnchanged(t *testing.T) {
+	tfvars := &cluster.TerraformVariables{}
+	FileNames(tfvars)
+	assert.Empty(t, tfvars.Files())
+}
@@ -13,7 +13,9 @@ type JournalCertKey struct {
 	SignedCertKey
 }
 
-var _ asset.WritableAsset = (*JournalCertKey)(nil)
+var _ asset.FileDescriber = (*JournalCertKey)(nil)
+
+const journalCertKeyFilenameBase = "journal-gatewayd"
 
 // Dependencies returns the dependency of the the cert/key pair, which includes
 // the parent CA, and install config if it depends on the install config for
@@ -36,7 +38,12 @@ func (a *JournalCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "journal-gatewayd", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, ca, journalCertKeyFilenameBase, DoNotAppendParent)
+}
+
+// FileNames returns the names of the key and the certificate files.
+func (a *JournalCertKey) FileNames() []string {
+	return []string{assetFilePath(journalCertKeyFilenameBase + ".key"), assetFilePath(journalCertKeyFilenameBase + ".crt")}
 }
 
 // Name returns the human-friendly name of the asset.
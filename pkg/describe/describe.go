@@ -0,0 +1,393 @@
+// Package describe reverse-engineers the install-config of a running cluster, e.g. to reproduce
+// it or to use it as the template of other clusters.
+package describe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	configv1 "github.com/openshift/api/config/v1"
+	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/none"
+)
+
+const (
+	machineNamespace = "openshift-machine-api"
+	clusterLabel     = "machine.openshift.io/cluster-api-cluster"
+	roleLabel        = "machine.openshift.io/cluster-api-machine-role"
+)
+
+var (
+	configMapResource      = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	infrastructureResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "infrastructures"}
+	dnsResource            = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "dnses"}
+	networkResource        = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "networks"}
+	proxyResource          = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "proxies"}
+	machineResource        = schema.GroupVersionResource{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machines"}
+	machineSetResource     = schema.GroupVersionResource{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machinesets"}
+
+	// machineSetIndex matches the index suffix of the names of the MachineSets of a machine pool.
+	machineSetIndex = regexp.MustCompile(`-[0-9]+$`)
+)
+
+// Cluster holds the resources of a running cluster its install-config is described from. The
+// resources missing from the cluster are nil or empty.
+type Cluster struct {
+	// InstallConfig is the install-config the installer stored in the cluster, without its
+	// pull secret.
+	InstallConfig string
+	// CloudProviderConfig is the configuration of the cloud provider of the cluster.
+	CloudProviderConfig string
+
+	Infrastructure *configv1.Infrastructure
+	DNS            *configv1.DNS
+	Network        *configv1.Network
+	Proxy          *configv1.Proxy
+
+	// Machines and MachineSets are those of the cluster, labeled with its infra ID.
+	Machines    []*machineapi.Machine
+	MachineSets []*machineapi.MachineSet
+}
+
+// Fetch reads the resources of the running cluster the install-config is described from.
+func Fetch(ctx context.Context, client dynamic.Interface) (*Cluster, error) {
+	cluster := &Cluster{}
+
+	var configMap struct {
+		Data map[string]string `json:"data"`
+	}
+	if found, err := get(ctx, client, configMapResource, "kube-system", "cluster-config-v1", &configMap); err != nil {
+		return nil, err
+	} else if found {
+		cluster.InstallConfig = configMap.Data["install-config"]
+	}
+	configMap.Data = nil
+	if found, err := get(ctx, client, configMapResource, "openshift-config", "cloud-provider-config", &configMap); err != nil {
+		return nil, err
+	} else if found {
+		cluster.CloudProviderConfig = configMap.Data["config"]
+	}
+
+	infrastructure, dns, network, proxy := &configv1.Infrastructure{}, &configv1.DNS{}, &configv1.Network{}, &configv1.Proxy{}
+	if found, err := get(ctx, client, infrastructureResource, "", "cluster", infrastructure); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, errors.New("the cluster has no infrastructure configuration, it is not an OpenShift cluster")
+	}
+	cluster.Infrastructure = infrastructure
+	if found, err := get(ctx, client, dnsResource, "", "cluster", dns); err != nil {
+		return nil, err
+	} else if found {
+		cluster.DNS = dns
+	}
+	if found, err := get(ctx, client, networkResource, "", "cluster", network); err != nil {
+		return nil, err
+	} else if found {
+		cluster.Network = network
+	}
+	if found, err := get(ctx, client, proxyResource, "", "cluster", proxy); err != nil {
+		return nil, err
+	} else if found {
+		cluster.Proxy = proxy
+	}
+
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", clusterLabel, cluster.Infrastructure.Status.InfrastructureName)}
+	machines, err := client.Resource(machineResource).Namespace(machineNamespace).List(ctx, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the Machines")
+	}
+	for _, item := range machines.Items {
+		machine := &machineapi.Machine{}
+		if err := convert(item.Object, machine); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal Machine %s", item.GetName())
+		}
+		cluster.Machines = append(cluster.Machines, machine)
+	}
+	machineSets, err := client.Resource(machineSetResource).Namespace(machineNamespace).List(ctx, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the MachineSets")
+	}
+	for _, item := range machineSets.Items {
+		machineSet := &machineapi.MachineSet{}
+		if err := convert(item.Object, machineSet); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal MachineSet %s", item.GetName())
+		}
+		cluster.MachineSets = append(cluster.MachineSets, machineSet)
+	}
+	return cluster, nil
+}
+
+// get reads the resource into the object, returning whether it was found.
+func get(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, namespace string, name string, into interface{}) (bool, error) {
+	obj, err := client.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get %s %s", resource.Resource, name)
+	}
+	if err := convert(obj.Object, into); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s %s", resource.Resource, name)
+	}
+	return true, nil
+}
+
+func convert(object map[string]interface{}, into interface{}) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// Config returns a best-effort install-config of the cluster, with the warnings about what
+// could not be determined. The install-config stored in the cluster by the installer is used
+// when present, otherwise it is reconstructed from the configuration of the cluster, only
+// the KubeVirt and none platforms being supported. Either way, the replicas of the machine
+// pools, and their sizes on KubeVirt, are updated from the machines of the cluster, and the
+// compute pools added since the installation are included. The pull secret, which the
+// cluster does not store, is left empty.
+func Config(cluster *Cluster) (*types.InstallConfig, []string, error) {
+	var warnings []string
+	var config *types.InstallConfig
+	if cluster.InstallConfig != "" {
+		config = &types.InstallConfig{}
+		if err := yaml.Unmarshal([]byte(cluster.InstallConfig), config); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to unmarshal the install-config stored in the cluster")
+		}
+	} else {
+		warnings = append(warnings, "the cluster does not store its install-config, it is reconstructed from the configuration of the cluster")
+		var err error
+		config, warnings, err = reconstruct(cluster, warnings)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	config.PullSecret = ""
+	warnings = append(warnings, "the pull secret is not stored in the cluster, pullSecret must be set")
+	if config.SSHKey == "" {
+		warnings = append(warnings, "the cluster has no SSH key in its install-config, sshKey may be set")
+	}
+
+	warnings = updatePools(config, cluster, warnings)
+	return config, warnings, nil
+}
+
+// reconstruct returns the install-config of the cluster from its configuration.
+func reconstruct(cluster *Cluster, warnings []string) (*types.InstallConfig, []string, error) {
+	config := &types.InstallConfig{
+		TypeMeta: metav1.TypeMeta{APIVersion: types.InstallConfigVersion},
+	}
+
+	if cluster.DNS == nil || cluster.DNS.Spec.BaseDomain == "" {
+		return nil, nil, errors.New("the cluster has no DNS configuration, its name and base domain are unknown")
+	}
+	// The base domain of the cluster DNS configuration is the cluster domain
+	parts := strings.SplitN(strings.TrimSuffix(cluster.DNS.Spec.BaseDomain, "."), ".", 2)
+	if len(parts) != 2 {
+		return nil, nil, errors.Errorf("invalid cluster domain %q", cluster.DNS.Spec.BaseDomain)
+	}
+	config.ObjectMeta.Name = parts[0]
+	config.BaseDomain = parts[1]
+
+	if network := cluster.Network; network != nil {
+		networking := &types.Networking{NetworkType: network.Spec.NetworkType}
+		for _, entry := range network.Spec.ClusterNetwork {
+			cidr, err := ipnet.ParseCIDR(entry.CIDR)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid cluster network %q", entry.CIDR)
+			}
+			networking.ClusterNetwork = append(networking.ClusterNetwork, types.ClusterNetworkEntry{CIDR: *cidr, HostPrefix: int32(entry.HostPrefix)})
+		}
+		for _, entry := range network.Spec.ServiceNetwork {
+			cidr, err := ipnet.ParseCIDR(entry)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid service network %q", entry)
+			}
+			networking.ServiceNetwork = append(networking.ServiceNetwork, *cidr)
+		}
+		config.Networking = networking
+		warnings = append(warnings, "the machine network is not stored in the cluster, the default one is assumed")
+	}
+
+	if proxy := cluster.Proxy; proxy != nil && (proxy.Spec.HTTPProxy != "" || proxy.Spec.HTTPSProxy != "") {
+		config.Proxy = &types.Proxy{
+			HTTPProxy:  proxy.Spec.HTTPProxy,
+			HTTPSProxy: proxy.Spec.HTTPSProxy,
+			NoProxy:    proxy.Spec.NoProxy,
+		}
+	}
+
+	platformType := cluster.Infrastructure.Status.Platform
+	if status := cluster.Infrastructure.Status.PlatformStatus; status != nil {
+		platformType = status.Type
+	}
+	switch platformType {
+	case configv1.KubevirtPlatformType:
+		platform, err := kubevirtPlatform(cluster)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Platform.Kubevirt = platform
+	case configv1.NonePlatformType:
+		config.Platform.None = &none.Platform{}
+	default:
+		warnings = append(warnings, fmt.Sprintf("the %s platform cannot be reconstructed, the platform section must be set", platformType))
+	}
+	return config, warnings, nil
+}
+
+// kubevirtPlatform returns the KubeVirt platform of the cluster, from its cloud provider
+// configuration, its VIPs and the provider spec of its control plane machines.
+func kubevirtPlatform(cluster *Cluster) (*kubevirt.Platform, error) {
+	platform := &kubevirt.Platform{}
+	if cluster.CloudProviderConfig != "" {
+		var cloudConfig struct {
+			Namespace string `json:"namespace"`
+		}
+		if err := yaml.Unmarshal([]byte(cluster.CloudProviderConfig), &cloudConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the cloud provider config")
+		}
+		platform.Namespace = cloudConfig.Namespace
+	}
+	if status := cluster.Infrastructure.Status.PlatformStatus; status != nil && status.Kubevirt != nil {
+		platform.APIVIP = status.Kubevirt.APIServerInternalIP
+		platform.IngressVIP = status.Kubevirt.IngressIP
+	}
+	for _, machine := range cluster.Machines {
+		if machine.Labels[roleLabel] != "master" {
+			continue
+		}
+		spec, err := kubevirtProviderSpec(machine.Spec.ProviderSpec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid provider spec of Machine %s", machine.Name)
+		}
+		platform.StorageClass = spec.StorageClassName
+		platform.NetworkName = spec.NetworkName
+		platform.PersistentVolumeAccessMode = spec.PersistentVolumeAccessMode
+		break
+	}
+	return platform, nil
+}
+
+func kubevirtProviderSpec(providerSpec machineapi.ProviderSpec) (*kubevirtprovider.KubevirtMachineProviderSpec, error) {
+	spec := &kubevirtprovider.KubevirtMachineProviderSpec{}
+	if providerSpec.Value == nil {
+		return spec, nil
+	}
+	if err := json.Unmarshal(providerSpec.Value.Raw, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// updatePools updates the replicas of the machine pools of the install-config from the
+// machines of the cluster, and their sizes on KubeVirt, adding the compute pools missing from
+// it.
+func updatePools(config *types.InstallConfig, cluster *Cluster, warnings []string) []string {
+	infraID := cluster.Infrastructure.Status.InfrastructureName
+	onKubevirt := config.Platform.Kubevirt != nil
+
+	var masters []*machineapi.Machine
+	for _, machine := range cluster.Machines {
+		if machine.Labels[roleLabel] == "master" {
+			masters = append(masters, machine)
+		}
+	}
+	if len(masters) > 0 {
+		if config.ControlPlane == nil {
+			config.ControlPlane = &types.MachinePool{Name: "master"}
+		}
+		config.ControlPlane.Replicas = pointer.Int64Ptr(int64(len(masters)))
+		if onKubevirt {
+			if err := updateKubevirtPool(config.ControlPlane, masters[0].Spec.ProviderSpec); err != nil {
+				warnings = append(warnings, fmt.Sprintf("the size of the control plane machines is unknown: invalid provider spec of Machine %s: %v", masters[0].Name, err))
+			}
+		}
+	}
+
+	// The replicas of the MachineSets of each compute pool
+	replicas := map[string]int64{}
+	machineSets := map[string]*machineapi.MachineSet{}
+	var names []string
+	for _, machineSet := range cluster.MachineSets {
+		name := poolName(infraID, machineSet)
+		if _, ok := machineSets[name]; !ok {
+			names = append(names, name)
+			machineSets[name] = machineSet
+		}
+		if machineSet.Spec.Replicas != nil {
+			replicas[name] += int64(*machineSet.Spec.Replicas)
+		}
+	}
+	sort.Strings(names)
+
+	byName := map[string]int{}
+	for i, pool := range config.Compute {
+		byName[pool.Name] = i
+	}
+	for _, name := range names {
+		i, ok := byName[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("compute machine pool %s was added after the installation", name))
+			config.Compute = append(config.Compute, types.MachinePool{Name: name})
+			i = len(config.Compute) - 1
+		}
+		pool := &config.Compute[i]
+		pool.Replicas = pointer.Int64Ptr(replicas[name])
+		if onKubevirt {
+			if err := updateKubevirtPool(pool, machineSets[name].Spec.Template.Spec.ProviderSpec); err != nil {
+				warnings = append(warnings, fmt.Sprintf("the size of the machines of compute machine pool %s is unknown: invalid provider spec of MachineSet %s: %v", name, machineSets[name].Name, err))
+			}
+		}
+	}
+	return warnings
+}
+
+// updateKubevirtPool updates the size of the VMs of the KubeVirt machine pool from the provider
+// spec of its machines.
+func updateKubevirtPool(pool *types.MachinePool, providerSpec machineapi.ProviderSpec) error {
+	spec, err := kubevirtProviderSpec(providerSpec)
+	if err != nil {
+		return err
+	}
+	if pool.Platform.Kubevirt == nil {
+		pool.Platform.Kubevirt = &kubevirt.MachinePool{}
+	}
+	pool.Platform.Kubevirt.Set(&kubevirt.MachinePool{
+		CPU:         spec.RequestedCPU,
+		Memory:      spec.RequestedMemory,
+		StorageSize: spec.RequestedStorage,
+	})
+	return nil
+}
+
+// poolName returns the name of the machine pool of the MachineSet, named
+// <infra ID or name prefix>-<pool>-<index> by the installer. The role of the machines is used
+// when the MachineSet has another name.
+func poolName(infraID string, machineSet *machineapi.MachineSet) string {
+	name := machineSetIndex.ReplaceAllString(machineSet.Name, "")
+	if infraID != "" && strings.HasPrefix(name, infraID+"-") {
+		return strings.TrimPrefix(name, infraID+"-")
+	}
+	if role := machineSet.Labels[roleLabel]; role != "" {
+		return role
+	}
+	return name
+}
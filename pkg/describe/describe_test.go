@@ -0,0 +1,209 @@
+package describe
+
+import (
+	"encoding/json"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func providerSpec(t *testing.T, cpu uint32, memory string, storage string) machineapi.ProviderSpec {
+	raw, err := json.Marshal(&kubevirtprovider.KubevirtMachineProviderSpec{
+		RequestedCPU:               cpu,
+		RequestedMemory:            memory,
+		RequestedStorage:           storage,
+		StorageClassName:           "fast-ssd",
+		NetworkName:                "tenant-network",
+		PersistentVolumeAccessMode: "ReadWriteMany",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return machineapi.ProviderSpec{Value: &runtime.RawExtension{Raw: raw}}
+}
+
+func testCluster(t *testing.T) *Cluster {
+	var machines []*machineapi.Machine
+	for _, name := range []string{"infra-id-master-0", "infra-id-master-1", "infra-id-master-2"} {
+		machines = append(machines, &machineapi.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{roleLabel: "master"}},
+			Spec:       machineapi.MachineSpec{ProviderSpec: providerSpec(t, 8, "16Gi", "120Gi")},
+		})
+	}
+	machineSet := func(name string, role string, replicas int32) *machineapi.MachineSet {
+		return &machineapi.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{roleLabel: role}},
+			Spec: machineapi.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+				Template: machineapi.MachineTemplateSpec{
+					Spec: machineapi.MachineSpec{ProviderSpec: providerSpec(t, 4, "8Gi", "100Gi")},
+				},
+			},
+		}
+	}
+	return &Cluster{
+		CloudProviderConfig: "namespace: tenant\ninfraID: infra-id\n",
+		Infrastructure: &configv1.Infrastructure{
+			Status: configv1.InfrastructureStatus{
+				InfrastructureName: "infra-id",
+				PlatformStatus: &configv1.PlatformStatus{
+					Type:     configv1.KubevirtPlatformType,
+					Kubevirt: &configv1.KubevirtPlatformStatus{APIServerInternalIP: "10.0.0.1", IngressIP: "10.0.0.2"},
+				},
+			},
+		},
+		DNS: &configv1.DNS{Spec: configv1.DNSSpec{BaseDomain: "test.example.com"}},
+		Network: &configv1.Network{Spec: configv1.NetworkSpec{
+			ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}},
+			ServiceNetwork: []string{"172.30.0.0/16"},
+			NetworkType:    "OVNKubernetes",
+		}},
+		Proxy:    &configv1.Proxy{},
+		Machines: machines,
+		MachineSets: []*machineapi.MachineSet{
+			machineSet("infra-id-worker-0", "worker", 2),
+			machineSet("infra-id-worker-1", "worker", 1),
+			machineSet("infra-id-infra-0", "infra", 3),
+		},
+	}
+}
+
+func TestConfigReconstructed(t *testing.T) {
+	config, warnings, err := Config(testCluster(t))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "test", config.ObjectMeta.Name)
+	assert.Equal(t, "example.com", config.BaseDomain)
+	assert.Equal(t, &types.Networking{
+		NetworkType:    "OVNKubernetes",
+		ClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.128.0.0/14"), HostPrefix: 23}},
+		ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+	}, config.Networking)
+	assert.Nil(t, config.Proxy)
+	assert.Equal(t, &kubevirt.Platform{
+		Namespace:                  "tenant",
+		StorageClass:               "fast-ssd",
+		NetworkName:                "tenant-network",
+		APIVIP:                     "10.0.0.1",
+		IngressVIP:                 "10.0.0.2",
+		PersistentVolumeAccessMode: "ReadWriteMany",
+	}, config.Platform.Kubevirt)
+	assert.Equal(t, &types.MachinePool{
+		Name:     "master",
+		Replicas: pointer.Int64Ptr(3),
+		Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{CPU: 8, Memory: "16Gi", StorageSize: "120Gi"}},
+	}, config.ControlPlane)
+	assert.Equal(t, []types.MachinePool{
+		{
+			Name:     "infra",
+			Replicas: pointer.Int64Ptr(3),
+			Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{CPU: 4, Memory: "8Gi", StorageSize: "100Gi"}},
+		},
+		{
+			Name:     "worker",
+			Replicas: pointer.Int64Ptr(3),
+			Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{CPU: 4, Memory: "8Gi", StorageSize: "100Gi"}},
+		},
+	}, config.Compute)
+	assert.Contains(t, warnings, "the cluster does not store its install-config, it is reconstructed from the configuration of the cluster")
+	assert.Contains(t, warnings, "the pull secret is not stored in the cluster, pullSecret must be set")
+}
+
+func TestConfigStored(t *testing.T) {
+	cluster := testCluster(t)
+	cluster.InstallConfig = `apiVersion: v1
+metadata:
+  name: stored
+baseDomain: example.org
+sshKey: ssh-ed25519 AAAA
+pullSecret: ""
+controlPlane:
+  name: master
+  replicas: 3
+  platform:
+    kubevirt:
+      cpu: 8
+      memory: 16Gi
+      storageSize: 120Gi
+      etcdDisk:
+        size: 20Gi
+compute:
+- name: worker
+  replicas: 2
+  hyperthreading: Enabled
+  platform:
+    kubevirt:
+      cpu: 2
+      memory: 8Gi
+      storageSize: 100Gi
+platform:
+  kubevirt:
+    namespace: stored-namespace
+    storageClass: standard
+    networkName: stored-network
+    apiVIP: 10.1.0.1
+    ingressVIP: 10.1.0.2
+`
+
+	config, warnings, err := Config(cluster)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// The stored install-config is kept, only the machine pools are updated
+	assert.Equal(t, "stored", config.ObjectMeta.Name)
+	assert.Equal(t, "stored-namespace", config.Platform.Kubevirt.Namespace)
+	assert.Equal(t, &kubevirt.EtcdDisk{Size: "20Gi"}, config.ControlPlane.Platform.Kubevirt.EtcdDisk)
+	assert.Equal(t, pointer.Int64Ptr(3), config.ControlPlane.Replicas)
+	if assert.Len(t, config.Compute, 2) {
+		assert.Equal(t, "worker", config.Compute[0].Name)
+		assert.Equal(t, types.HyperthreadingEnabled, config.Compute[0].Hyperthreading)
+		assert.Equal(t, pointer.Int64Ptr(3), config.Compute[0].Replicas)
+		assert.Equal(t, uint32(4), config.Compute[0].Platform.Kubevirt.CPU)
+		assert.Equal(t, "infra", config.Compute[1].Name)
+		assert.Equal(t, pointer.Int64Ptr(3), config.Compute[1].Replicas)
+	}
+	assert.Equal(t, []string{
+		"the pull secret is not stored in the cluster, pullSecret must be set",
+		"compute machine pool infra was added after the installation",
+	}, warnings)
+}
+
+func TestConfigNoDNS(t *testing.T) {
+	cluster := testCluster(t)
+	cluster.DNS = nil
+	_, _, err := Config(cluster)
+	assert.EqualError(t, err, "the cluster has no DNS configuration, its name and base domain are unknown")
+}
+
+func TestPoolName(t *testing.T) {
+	cases := []struct {
+		name     string
+		role     string
+		expected string
+	}{
+		{name: "infra-id-worker-0", role: "worker", expected: "worker"},
+		{name: "infra-id-gpu-workers-12", role: "worker", expected: "gpu-workers"},
+		{name: "site-prefix-worker-0", role: "worker", expected: "worker"},
+		{name: "custom", expected: "custom"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machineSet := &machineapi.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: tc.name, Labels: map[string]string{}}}
+			if tc.role != "" {
+				machineSet.Labels[roleLabel] = tc.role
+			}
+			assert.Equal(t, tc.expected, poolName("infra-id", machineSet))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset/cluster"
 	osp "github.com/openshift/installer/pkg/destroy/openstack"
+	"github.com/openshift/installer/pkg/statecrypt"
 	"github.com/openshift/installer/pkg/terraform"
 	"github.com/openshift/installer/pkg/types/gcp"
 	"github.com/openshift/installer/pkg/types/libvirt"
@@ -38,8 +39,21 @@ func Destroy(dir string) (err error) {
 	}
 	defer os.RemoveAll(tempDir)
 
+	stateData, err := ioutil.ReadFile(filepath.Join(dir, terraform.StateFileName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", terraform.StateFileName)
+	}
+	// Keep the state encrypted if it was, even without the encryption enabled
+	encryptState := statecrypt.Enabled() || statecrypt.IsEncrypted(stateData)
+	if stateData, err = statecrypt.Decrypt(stateData); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, terraform.StateFileName), stateData, 0600); err != nil {
+		return errors.Wrapf(err, "failed to copy %s to the temporary directory", terraform.StateFileName)
+	}
+
 	extraArgs := []string{}
-	for _, filename := range []string{terraform.StateFileName, cluster.TfVarsFileName, tfPlatformVarsFileName} {
+	for _, filename := range []string{cluster.TfVarsFileName, tfPlatformVarsFileName} {
 		sourcePath := filepath.Join(dir, filename)
 		targetPath := filepath.Join(tempDir, filename)
 		err = copy(sourcePath, targetPath)
@@ -92,7 +106,13 @@ func Destroy(dir string) (err error) {
 	}
 
 	tempStateFilePath := filepath.Join(dir, terraform.StateFileName+".new")
-	err = copy(filepath.Join(tempDir, terraform.StateFileName), tempStateFilePath)
+	stateData, err = ioutil.ReadFile(filepath.Join(tempDir, terraform.StateFileName))
+	if err == nil && encryptState {
+		stateData, err = statecrypt.Encrypt(stateData)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(tempStateFilePath, stateData, 0666)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "failed to copy %s from the temporary directory", terraform.StateFileName)
 	}
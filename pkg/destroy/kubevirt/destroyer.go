@@ -1,89 +1,482 @@
 package kubevirt
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
 	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/featuregates"
 	"github.com/openshift/installer/pkg/types"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
 )
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
 	Metadata types.ClusterMetadata
 	Logger   logrus.FieldLogger
+	// Force makes the uninstaller continue on Forbidden and NotFound errors,
+	// reporting the resources which could not be deleted at the end.
+	Force bool
+	// GracePeriod, when set, is how long the VMs are given to shut down gracefully
+	// before they are deleted.
+	GracePeriod time.Duration
+	// Timeout, when set, is how long the resources of the cluster are deleted for before the
+	// uninstaller gives up.
+	Timeout time.Duration
+
+	// client is the client of the infra cluster of the metadata, created from it when nil.
+	client ickubevirt.Client
+	// deadline is the end of the Timeout of the current Run, zero without a timeout.
+	deadline time.Time
+	// mutex guards skipped, when deleting the resources in parallel.
+	mutex   sync.Mutex
+	skipped []string
+}
+
+// SetForce sets the force mode of the uninstaller.
+func (uninstaller *ClusterUninstaller) SetForce(force bool) {
+	uninstaller.Force = force
+}
+
+// SetGracePeriod sets how long the VMs are given to shut down gracefully before they are deleted.
+func (uninstaller *ClusterUninstaller) SetGracePeriod(gracePeriod time.Duration) {
+	uninstaller.GracePeriod = gracePeriod
+}
+
+// SetTimeout sets how long the resources of the cluster are deleted for before the uninstaller
+// gives up.
+func (uninstaller *ClusterUninstaller) SetTimeout(timeout time.Duration) {
+	uninstaller.Timeout = timeout
 }
 
-// Run is the entrypoint to start the uninstall process.
+// infraClient returns the client of the infra cluster of the metadata.
+func (uninstaller *ClusterUninstaller) infraClient() (ickubevirt.Client, error) {
+	if uninstaller.client != nil {
+		return uninstaller.client, nil
+	}
+	return ickubevirt.NewClientForMetadata(uninstaller.Metadata.Kubevirt)
+}
+
+// Run is the entrypoint to start the uninstall process. The resources of the cluster are deleted
+// from the infra cluster of the metadata, then from the infra clusters of the machine pools with
+// their own infra cluster. The errors of the namespaces are reported together once all of them
+// were cleaned up. In force mode, a providers.SkippedResourcesError is returned when resources
+// were skipped and no other error occurred, for the metadata of the cluster to be kept to delete
+// them.
 func (uninstaller *ClusterUninstaller) Run() error {
-	namespace := uninstaller.Metadata.Kubevirt.Namespace
 	labels := uninstaller.Metadata.Kubevirt.Labels
+	uninstaller.deadline = time.Time{}
+	if uninstaller.Timeout > 0 {
+		uninstaller.deadline = time.Now().Add(uninstaller.Timeout)
+	}
 
-	kubevirtClient, err := ickubevirt.NewClient()
+	kubevirtClient, err := uninstaller.infraClient()
 	if err != nil {
 		return err
 	}
+	// A namespace failing to be cleaned up doesn't keep the resources of the other namespaces
+	// from being deleted, the errors of all the namespaces are returned together at the end
+	var errs []error
+	namespace := uninstaller.Metadata.Kubevirt.Namespace
+	if err := uninstaller.deleteNamespace(namespace, labels, kubevirtClient); err != nil {
+		uninstaller.Logger.Warnf("Failed to delete the resources of the cluster in namespace %s, going on with the other namespaces: %v", namespace, err)
+		errs = append(errs, errors.Wrapf(err, "namespace %s", namespace))
+	}
+	for _, infraCluster := range uninstaller.Metadata.Kubevirt.InfraClusters {
+		if err := uninstaller.checkDeadline(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		uninstaller.Logger.Infof("Deleting the resources of the cluster in namespace %s of infra cluster %s", infraCluster.Namespace, infraClusterName(infraCluster))
+		kubevirtClient, err := ickubevirt.NewClientForInfraCluster(infraCluster)
+		if err == nil {
+			err = uninstaller.deleteNamespace(infraCluster.Namespace, labels, kubevirtClient)
+		}
+		if err != nil {
+			uninstaller.Logger.Warnf("Failed to delete the resources of the cluster in namespace %s of infra cluster %s, going on with the other namespaces: %v", infraCluster.Namespace, infraClusterName(infraCluster), err)
+			errs = append(errs, errors.Wrapf(err, "namespace %s of infra cluster %s", infraCluster.Namespace, infraClusterName(infraCluster)))
+		}
+	}
+	uninstaller.report("deleted")
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return err
+	}
+	if len(uninstaller.skipped) > 0 {
+		return &providers.SkippedResourcesError{Resources: uninstaller.skipped}
+	}
+	return nil
+}
+
+// infraClusterName returns the name of the infra cluster in the logs, its context and kubeconfig.
+func infraClusterName(infraCluster kubevirttypes.InfraCluster) string {
+	switch {
+	case infraCluster.Context == "":
+		return infraCluster.KubeconfigPath
+	case infraCluster.KubeconfigPath == "":
+		return infraCluster.Context
+	}
+	return fmt.Sprintf("%s (%s)", infraCluster.Context, infraCluster.KubeconfigPath)
+}
+
+// deleteNamespace deletes the resources of the cluster in the namespace of an infra cluster.
+func (uninstaller *ClusterUninstaller) deleteNamespace(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
 	if err := uninstaller.deleteAllVMs(namespace, labels, kubevirtClient); err != nil {
 		return err
 	}
 	if err := uninstaller.deleteAllDVs(namespace, labels, kubevirtClient); err != nil {
 		return err
 	}
+	if err := uninstaller.deleteAllDeployments(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
 	if err := uninstaller.deleteAllSecrets(namespace, labels, kubevirtClient); err != nil {
 		return err
 	}
-	return nil
+	if err := uninstaller.deleteAllMultiNetworkPolicies(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
+	if err := uninstaller.deleteAllPodDisruptionBudgets(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
+	if err := uninstaller.deleteAllServices(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
+	if err := uninstaller.deleteAllEndpoints(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
+	if err := uninstaller.deleteAllConfigMaps(namespace, labels, kubevirtClient); err != nil {
+		return err
+	}
+	return uninstaller.deleteAllNetworkAttachmentDefinitions(namespace, labels, kubevirtClient)
 }
 
 func (uninstaller *ClusterUninstaller) deleteAllVMs(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
 	list, err := kubevirtClient.ListVirtualMachineNames(namespace, labels)
 	if err != nil {
-		return err
+		return uninstaller.tolerate(err, "VMs", namespace)
 	}
 	uninstaller.Logger.Infof("List tenant cluster's VMs (in namespace %s) return: %s", namespace, list)
-	for _, vmName := range list {
-		uninstaller.Logger.Infof("Delete VM %s", vmName)
-		if err := kubevirtClient.DeleteVirtualMachine(namespace, vmName, true); err != nil {
-			// TODO Do we want to continue to other resources?
+	return uninstaller.forEach(list, func(vmName string) error {
+		return uninstaller.deleteVM(namespace, vmName, kubevirtClient)
+	})
+}
+
+// deleteVM deletes a VM, after giving it the grace period of the uninstaller to shut down.
+func (uninstaller *ClusterUninstaller) deleteVM(namespace string, vmName string, kubevirtClient ickubevirt.Client) error {
+	if gracePeriod := uninstaller.stopGracePeriod(); gracePeriod > 0 {
+		uninstaller.Logger.Infof("Stop VM %s", vmName)
+		if err := kubevirtClient.StopVirtualMachine(namespace, vmName, gracePeriod); err != nil {
+			// The VM is deleted anyway, stopping it is best effort
+			uninstaller.Logger.Warnf("Failed to stop VM %s gracefully, deleting it: %v", vmName, err)
+		}
+	}
+	uninstaller.Logger.Infof("Delete VM %s", vmName)
+	if err := kubevirtClient.DeleteVirtualMachine(namespace, vmName, true); err != nil {
+		if err := uninstaller.tolerate(err, "VM", vmName); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// deleteAllDVs deletes the DVs of the cluster, carrying its labels or, when the DVs of the
+// cluster are tagged, its infra ID and tags.
 func (uninstaller *ClusterUninstaller) deleteAllDVs(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
 	list, err := kubevirtClient.ListDataVolumeNames(namespace, labels)
 	if err != nil {
-		return err
+		return uninstaller.tolerate(err, "DVs", namespace)
+	}
+	if annotations := uninstaller.Metadata.Kubevirt.DataVolumeTags.Annotations(uninstaller.Metadata.InfraID); annotations != nil {
+		dvs, err := kubevirtClient.ListDataVolumes(context.TODO(), namespace)
+		if err != nil {
+			return uninstaller.tolerate(err, "DVs", namespace)
+		}
+		list = appendTagged(list, dvs, annotations)
 	}
 	uninstaller.Logger.Infof("List tenant cluster's DVs (in namespace %s) return: %s", namespace, list)
-	for _, dvName := range list {
+	return uninstaller.forEach(list, func(dvName string) error {
 		uninstaller.Logger.Infof("Delete DV %s", dvName)
 		if err := kubevirtClient.DeleteDataVolume(namespace, dvName, true); err != nil {
-			// TODO Do we want to continue to other resources?
-			return err
+			if err := uninstaller.tolerate(err, "DV", dvName); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+}
+
+// appendTagged appends to names the names of the resources carrying all the annotations,
+// which names doesn't hold yet.
+func appendTagged(names []string, resources []unstructured.Unstructured, annotations map[string]string) []string {
+	seen := map[string]bool{}
+	for _, name := range names {
+		seen[name] = true
 	}
-	return nil
+	for _, resource := range resources {
+		if seen[resource.GetName()] || !hasAnnotations(resource.GetAnnotations(), annotations) {
+			continue
+		}
+		seen[resource.GetName()] = true
+		names = append(names, resource.GetName())
+	}
+	return names
+}
+
+// hasAnnotations returns whether existing holds all the annotations, with the same values.
+func hasAnnotations(existing map[string]string, annotations map[string]string) bool {
+	for key, value := range annotations {
+		if existing[key] != value {
+			return false
+		}
+	}
+	return true
 }
 
 func (uninstaller *ClusterUninstaller) deleteAllSecrets(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
 	list, err := kubevirtClient.ListSecretNames(namespace, labels)
 	if err != nil {
-		return err
+		return uninstaller.tolerate(err, "secrets", namespace)
 	}
 	uninstaller.Logger.Infof("List tenant cluster's secrets (in namespace %s) return: %s", namespace, list)
-	for _, secretName := range list {
+	return uninstaller.forEach(list, func(secretName string) error {
 		uninstaller.Logger.Infof("Delete secret %s", secretName)
 		if err := kubevirtClient.DeleteSecret(namespace, secretName, true); err != nil {
-			// TODO Do we want to continue to other resources?
+			if err := uninstaller.tolerate(err, "secret", secretName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllMultiNetworkPolicies deletes the MultiNetworkPolicies isolating the network of the
+// cluster. There are none when the infra cluster doesn't serve them.
+func (uninstaller *ClusterUninstaller) deleteAllMultiNetworkPolicies(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListMultiNetworkPolicyNames(namespace, labels)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return uninstaller.tolerate(err, "multi-network policies", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's multi-network policies (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(networkPolicyName string) error {
+		uninstaller.Logger.Infof("Delete multi-network policy %s", networkPolicyName)
+		if err := kubevirtClient.DeleteMultiNetworkPolicy(namespace, networkPolicyName, true); err != nil {
+			if err := uninstaller.tolerate(err, "multi-network policy", networkPolicyName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllPodDisruptionBudgets deletes the PodDisruptionBudgets protecting the control plane
+// VMs of the cluster from the drains of the infra cluster nodes.
+func (uninstaller *ClusterUninstaller) deleteAllPodDisruptionBudgets(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListPodDisruptionBudgetNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "PodDisruptionBudgets", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's PodDisruptionBudgets (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(podDisruptionBudgetName string) error {
+		uninstaller.Logger.Infof("Delete PodDisruptionBudget %s", podDisruptionBudgetName)
+		if err := kubevirtClient.DeletePodDisruptionBudget(namespace, podDisruptionBudgetName, true); err != nil {
+			if err := uninstaller.tolerate(err, "PodDisruptionBudget", podDisruptionBudgetName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllNetworkAttachmentDefinitions deletes the network-attachment-definition created from the
+// network template of the platform, after the VMs attached to it.
+func (uninstaller *ClusterUninstaller) deleteAllNetworkAttachmentDefinitions(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListNetworkAttachmentDefinitionNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "NetworkAttachmentDefinitions", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's NetworkAttachmentDefinitions (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(nadName string) error {
+		uninstaller.Logger.Infof("Delete NetworkAttachmentDefinition %s", nadName)
+		if err := kubevirtClient.DeleteNetworkAttachmentDefinition(namespace, nadName, true); err != nil {
+			if err := uninstaller.tolerate(err, "NetworkAttachmentDefinition", nadName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllDeployments deletes the deployments of the cluster, such as the server of the worker
+// ignition config.
+func (uninstaller *ClusterUninstaller) deleteAllDeployments(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListDeploymentNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "deployments", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's deployments (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(deploymentName string) error {
+		uninstaller.Logger.Infof("Delete deployment %s", deploymentName)
+		if err := kubevirtClient.DeleteDeployment(namespace, deploymentName, true); err != nil {
+			if err := uninstaller.tolerate(err, "deployment", deploymentName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllServices deletes the services of the cluster, such as the LoadBalancer services
+// created at runtime by the kubevirt cloud provider of the cluster, releasing their VIPs.
+func (uninstaller *ClusterUninstaller) deleteAllServices(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListServiceNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "services", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's services (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(serviceName string) error {
+		uninstaller.Logger.Infof("Delete service %s", serviceName)
+		if err := kubevirtClient.DeleteService(namespace, serviceName, true); err != nil {
+			if err := uninstaller.tolerate(err, "service", serviceName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllEndpoints deletes the endpoints of the cluster left behind by its services.
+func (uninstaller *ClusterUninstaller) deleteAllEndpoints(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListEndpointsNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "endpoints", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's endpoints (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(endpointsName string) error {
+		uninstaller.Logger.Infof("Delete endpoints %s", endpointsName)
+		if err := kubevirtClient.DeleteEndpoints(namespace, endpointsName, true); err != nil {
+			// The endpoints of a service may be deleted along with it in the meantime
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err := uninstaller.tolerate(err, "endpoints", endpointsName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAllConfigMaps deletes the ConfigMaps of the cluster, such as its protection marker.
+func (uninstaller *ClusterUninstaller) deleteAllConfigMaps(namespace string, labels map[string]string, kubevirtClient ickubevirt.Client) error {
+	list, err := kubevirtClient.ListConfigMapNames(namespace, labels)
+	if err != nil {
+		return uninstaller.tolerate(err, "ConfigMaps", namespace)
+	}
+	uninstaller.Logger.Infof("List tenant cluster's ConfigMaps (in namespace %s) return: %s", namespace, list)
+	return uninstaller.forEach(list, func(configMapName string) error {
+		uninstaller.Logger.Infof("Delete ConfigMap %s", configMapName)
+		if err := kubevirtClient.DeleteConfigMap(namespace, configMapName, true); err != nil {
+			if err := uninstaller.tolerate(err, "ConfigMap", configMapName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// tolerate returns nil for Forbidden and NotFound errors when running in force mode,
+// recording the resource for the final report; otherwise it returns the error.
+func (uninstaller *ClusterUninstaller) tolerate(err error, kind string, name string) error {
+	if !uninstaller.Force || !(apierrors.IsForbidden(err) || apierrors.IsNotFound(err)) {
+		return err
+	}
+	uninstaller.Logger.Warnf("Skipping %s %s: %v", kind, name, err)
+	uninstaller.mutex.Lock()
+	defer uninstaller.mutex.Unlock()
+	uninstaller.skipped = append(uninstaller.skipped, fmt.Sprintf("%s %s: %v", kind, name, err))
+	return nil
+}
+
+// forEach calls fn for each of the names, one after the other or, with the ParallelDestroy
+// feature gate, in parallel. It returns the first error, once all the calls returned.
+func (uninstaller *ClusterUninstaller) forEach(names []string, fn func(name string) error) error {
+	if len(names) > 0 {
+		if err := uninstaller.checkDeadline(); err != nil {
+			return err
+		}
+	}
+	if !featuregates.Enabled(featuregates.ParallelDestroy) {
+		for _, name := range names {
+			if err := uninstaller.checkDeadline(); err != nil {
+				return err
+			}
+			if err := fn(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = fn(name)
+		}(i, name)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// New returns oVirt Uninstaller from ClusterMetadata.
+// checkDeadline returns an error once the timeout of the uninstaller is over.
+func (uninstaller *ClusterUninstaller) checkDeadline() error {
+	if uninstaller.deadline.IsZero() || time.Now().Before(uninstaller.deadline) {
+		return nil
+	}
+	return fmt.Errorf("the resources of the cluster were not deleted within %v, run destroy cluster again to delete the ones left", uninstaller.Timeout)
+}
+
+// stopGracePeriod returns how long the VMs are given to shut down gracefully, at most the time
+// left before the deadline.
+func (uninstaller *ClusterUninstaller) stopGracePeriod() time.Duration {
+	gracePeriod := uninstaller.GracePeriod
+	if !uninstaller.deadline.IsZero() {
+		if left := time.Until(uninstaller.deadline); left < gracePeriod {
+			gracePeriod = left
+		}
+	}
+	return gracePeriod
+}
+
+// report warns of the resources skipped in force mode, which could not be deleted or, when
+// stopping or resuming the cluster, updated.
+func (uninstaller *ClusterUninstaller) report(action string) {
+	if len(uninstaller.skipped) == 0 {
+		return
+	}
+	uninstaller.Logger.Warnf("The following resources could not be %s:\n%s", action, strings.Join(uninstaller.skipped, "\n"))
+}
+
+// New returns kubevirt Uninstaller from ClusterMetadata.
 func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.Destroyer, error) {
 	return &ClusterUninstaller{
 		Metadata: *metadata,
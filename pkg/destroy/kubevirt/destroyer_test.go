@@ -0,0 +1,325 @@
+package kubevirt
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+var (
+	testLabels     = map[string]string{"tenantcluster-infra-id-machine.openshift.io": "owned"}
+	errVMForbidden = apierrors.NewForbidden(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachines"}, "infra-id-master-0", fmt.Errorf("access denied"))
+)
+
+// testUninstaller returns an uninstaller of the cluster infra-id, in the namespace tenant.
+func testUninstaller() *ClusterUninstaller {
+	return &ClusterUninstaller{
+		Metadata: types.ClusterMetadata{
+			InfraID: "infra-id",
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				Kubevirt: &kubevirttypes.Metadata{Namespace: "tenant", Labels: testLabels},
+			},
+		},
+		Logger: logrus.StandardLogger(),
+	}
+}
+
+// expectNoResources makes the listings of the resources of the cluster in the namespace, not
+// expected otherwise, return none.
+func expectNoResources(client *mock.MockClient, namespace string) {
+	client.EXPECT().ListVirtualMachineNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListDataVolumeNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListDeploymentNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListSecretNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListMultiNetworkPolicyNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListPodDisruptionBudgetNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListServiceNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListEndpointsNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListConfigMapNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+	client.EXPECT().ListNetworkAttachmentDefinitionNames(namespace, testLabels).Return(nil, nil).AnyTimes()
+}
+
+func TestAppendTagged(t *testing.T) {
+	dv := func(name string, annotations map[string]string) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetName(name)
+		u.SetAnnotations(annotations)
+		return u
+	}
+	tags := map[string]string{
+		"installer.openshift.io/infra-id":       "infra-id",
+		"installer.openshift.io/priority-class": "tenant-low",
+	}
+	dvs := []unstructured.Unstructured{
+		dv("infra-id-source-pvc", tags),
+		dv("infra-id-master-0-bootvolume", map[string]string{
+			"installer.openshift.io/infra-id":       "infra-id",
+			"installer.openshift.io/priority-class": "tenant-low",
+			"other":                                 "value",
+		}),
+		dv("other-infra-id-master-0-bootvolume", map[string]string{
+			"installer.openshift.io/infra-id":       "other-infra-id",
+			"installer.openshift.io/priority-class": "tenant-low",
+		}),
+		dv("untagged", nil),
+	}
+	assert.Equal(t, []string{"infra-id-source-pvc", "infra-id-master-0-bootvolume"}, appendTagged([]string{"infra-id-source-pvc"}, dvs, tags))
+}
+
+func TestDeleteNamespaceForce(t *testing.T) {
+	cases := []struct {
+		name          string
+		force         bool
+		expectedError string
+	}{
+		{
+			name:          "without force",
+			expectedError: errVMForbidden.Error(),
+		},
+		{
+			name:  "with force",
+			force: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().ListVirtualMachineNames("tenant", testLabels).Return([]string{"infra-id-master-0", "infra-id-master-1"}, nil)
+			client.EXPECT().DeleteVirtualMachine("tenant", "infra-id-master-0", true).Return(errVMForbidden)
+			if tc.force {
+				// The deletion goes on past the VM which could not be deleted
+				client.EXPECT().DeleteVirtualMachine("tenant", "infra-id-master-1", true).Return(nil)
+				client.EXPECT().ListSecretNames("tenant", testLabels).Return([]string{"infra-id-master-0-ignition"}, nil)
+				client.EXPECT().DeleteSecret("tenant", "infra-id-master-0-ignition", true).Return(nil)
+			}
+			expectNoResources(client, "tenant")
+
+			uninstaller := testUninstaller()
+			uninstaller.SetForce(tc.force)
+			err := uninstaller.deleteNamespace("tenant", testLabels, client)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Empty(t, uninstaller.skipped)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"VM infra-id-master-0: " + errVMForbidden.Error()}, uninstaller.skipped)
+
+			// The VMs left are reported at the end
+			var out bytes.Buffer
+			logger := logrus.New()
+			logger.Out = &out
+			uninstaller.Logger = logger
+			uninstaller.report("deleted")
+			assert.Contains(t, out.String(), "The following resources could not be deleted:")
+			assert.Contains(t, out.String(), "VM infra-id-master-0")
+		})
+	}
+}
+
+func TestRunForceSkipped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListVirtualMachineNames("tenant", testLabels).Return([]string{"infra-id-master-0"}, nil)
+	client.EXPECT().DeleteVirtualMachine("tenant", "infra-id-master-0", true).Return(errVMForbidden)
+	expectNoResources(client, "tenant")
+
+	uninstaller := testUninstaller()
+	uninstaller.client = client
+	uninstaller.SetForce(true)
+	// The skipped resources fail the run, for the metadata of the cluster to be kept to delete
+	// them
+	err := uninstaller.Run()
+	assert.Equal(t, &providers.SkippedResourcesError{Resources: []string{"VM infra-id-master-0: " + errVMForbidden.Error()}}, err)
+}
+
+func TestDeleteAllMultiNetworkPolicies(t *testing.T) {
+	cases := []struct {
+		name   string
+		expect func(client *mock.MockClient)
+	}{
+		{
+			name: "labeled policies",
+			expect: func(client *mock.MockClient) {
+				client.EXPECT().ListMultiNetworkPolicyNames("tenant", testLabels).Return([]string{"infra-id-tenant-isolation"}, nil)
+				client.EXPECT().DeleteMultiNetworkPolicy("tenant", "infra-id-tenant-isolation", true).Return(nil)
+			},
+		},
+		{
+			name: "multi-network policies not served",
+			expect: func(client *mock.MockClient) {
+				notFound := apierrors.NewNotFound(schema.GroupResource{Group: "k8s.cni.cncf.io", Resource: "multi-networkpolicies"}, "")
+				client.EXPECT().ListMultiNetworkPolicyNames("tenant", testLabels).Return(nil, notFound)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			tc.expect(client)
+
+			uninstaller := testUninstaller()
+			assert.NoError(t, uninstaller.deleteAllMultiNetworkPolicies("tenant", testLabels, client))
+			assert.Empty(t, uninstaller.skipped)
+		})
+	}
+}
+
+func TestDeleteAllVMsGracePeriod(t *testing.T) {
+	cases := []struct {
+		name        string
+		gracePeriod time.Duration
+		deadline    time.Duration
+		stopErr     error
+		expectStop  func(t *testing.T, gracePeriod time.Duration)
+	}{
+		{
+			name: "without grace period",
+		},
+		{
+			name:        "with grace period",
+			gracePeriod: 2 * time.Minute,
+			expectStop: func(t *testing.T, gracePeriod time.Duration) {
+				assert.Equal(t, 2*time.Minute, gracePeriod)
+			},
+		},
+		{
+			name:        "grace period capped by the timeout",
+			gracePeriod: 2 * time.Minute,
+			deadline:    30 * time.Second,
+			expectStop: func(t *testing.T, gracePeriod time.Duration) {
+				assert.True(t, gracePeriod > 0 && gracePeriod <= 30*time.Second, "grace period %v is not capped by the timeout", gracePeriod)
+			},
+		},
+		{
+			name:        "VM deleted when it fails to stop",
+			gracePeriod: 2 * time.Minute,
+			stopErr:     fmt.Errorf("the VM did not stop within 2m0s"),
+			expectStop:  func(t *testing.T, gracePeriod time.Duration) {},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().ListVirtualMachineNames("tenant", testLabels).Return([]string{"infra-id-master-0"}, nil)
+			deleted := client.EXPECT().DeleteVirtualMachine("tenant", "infra-id-master-0", true).Return(nil)
+			if tc.expectStop != nil {
+				// The VM is stopped before it is deleted
+				stopped := client.EXPECT().StopVirtualMachine("tenant", "infra-id-master-0", gomock.Any()).DoAndReturn(func(namespace string, name string, gracePeriod time.Duration) error {
+					tc.expectStop(t, gracePeriod)
+					return tc.stopErr
+				})
+				deleted.After(stopped)
+			}
+
+			uninstaller := testUninstaller()
+			uninstaller.SetGracePeriod(tc.gracePeriod)
+			if tc.deadline != 0 {
+				uninstaller.deadline = time.Now().Add(tc.deadline)
+			}
+			assert.NoError(t, uninstaller.deleteAllVMs("tenant", testLabels, client))
+			assert.Empty(t, uninstaller.skipped)
+		})
+	}
+}
+
+func TestDeleteServicesAndEndpoints(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListServiceNames("tenant", testLabels).Return([]string{"infra-id-ingress-lb", "infra-id-api-lb"}, nil)
+	client.EXPECT().DeleteService("tenant", "infra-id-ingress-lb", true).Return(nil)
+	client.EXPECT().DeleteService("tenant", "infra-id-api-lb", true).Return(nil)
+	client.EXPECT().ListEndpointsNames("tenant", testLabels).Return([]string{"infra-id-ingress-lb", "infra-id-api-lb"}, nil)
+	// The endpoints of the services are deleted along with them in the meantime
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "endpoints"}, "infra-id-ingress-lb")
+	client.EXPECT().DeleteEndpoints("tenant", "infra-id-ingress-lb", true).Return(notFound)
+	client.EXPECT().DeleteEndpoints("tenant", "infra-id-api-lb", true).Return(nil)
+	expectNoResources(client, "tenant")
+
+	uninstaller := testUninstaller()
+	assert.NoError(t, uninstaller.deleteNamespace("tenant", testLabels, client))
+	// The endpoints already gone are not reported as skipped, even without force
+	assert.Empty(t, uninstaller.skipped)
+}
+
+// listLabeled returns a listing of the names of the resources, by name, carrying the labels.
+func listLabeled(resources map[string]map[string]string) func(string, map[string]string) ([]string, error) {
+	return func(namespace string, labels map[string]string) ([]string, error) {
+		var names []string
+		for name, resourceLabels := range resources {
+			if hasAnnotations(resourceLabels, labels) {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	}
+}
+
+func TestRunRollback(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	owner := "tenantcluster-infra-id-machine.openshift.io"
+	run := map[string]string{owner: "owned", "installer.openshift.io/run-id": "new-run-id"}
+	previousRun := map[string]string{owner: "owned", "installer.openshift.io/run-id": "old-run-id"}
+	runLabels := map[string]string{"installer.openshift.io/run-id": "new-run-id"}
+
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListVirtualMachineNames("tenant", gomock.Any()).DoAndReturn(listLabeled(map[string]map[string]string{
+		"infra-id-master-0": run,
+	}))
+	client.EXPECT().ListDataVolumeNames("tenant", gomock.Any()).DoAndReturn(listLabeled(map[string]map[string]string{
+		"infra-id-master-0-bootvolume": run,
+		// A DataVolume kept from a previous run, which is reused by the run
+		"infra-id-source-pvc": previousRun,
+	}))
+	client.EXPECT().ListSecretNames("tenant", gomock.Any()).DoAndReturn(listLabeled(map[string]map[string]string{
+		"infra-id-master-0-ignition": run,
+	}))
+	// The network-attachment-definition converged by the run keeps the run ID of the run which
+	// created it
+	client.EXPECT().ListNetworkAttachmentDefinitionNames("tenant", gomock.Any()).DoAndReturn(listLabeled(map[string]map[string]string{
+		"tenant-net": previousRun,
+	}))
+	client.EXPECT().ListDeploymentNames("tenant", runLabels).Return(nil, nil)
+	client.EXPECT().ListMultiNetworkPolicyNames("tenant", runLabels).Return(nil, nil)
+	client.EXPECT().ListPodDisruptionBudgetNames("tenant", runLabels).Return(nil, nil)
+	client.EXPECT().ListServiceNames("tenant", runLabels).Return(nil, nil)
+	client.EXPECT().ListEndpointsNames("tenant", runLabels).Return(nil, nil)
+	client.EXPECT().ListConfigMapNames("tenant", runLabels).Return(nil, nil)
+	// Only the resources created by the run are deleted
+	client.EXPECT().DeleteVirtualMachine("tenant", "infra-id-master-0", true).Return(nil)
+	client.EXPECT().DeleteDataVolume("tenant", "infra-id-master-0-bootvolume", true).Return(nil)
+	client.EXPECT().DeleteSecret("tenant", "infra-id-master-0-ignition", true).Return(nil)
+
+	uninstaller := testUninstaller()
+	uninstaller.Metadata.Kubevirt.Labels = runLabels
+	uninstaller.client = client
+	assert.NoError(t, uninstaller.Run())
+}
@@ -0,0 +1,142 @@
+package kubevirt
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// runStrategyHalted keeps the VM stopped, even when its guest is restarted from within.
+	runStrategyHalted = "Halted"
+	// runStrategyAlways keeps the VM running, restarting it whenever it stops.
+	runStrategyAlways = "Always"
+
+	// previousRunStrategyAnnotation is the annotation recording on a VM stopped by Stop the run
+	// strategy it had, restored by Resume.
+	previousRunStrategyAnnotation = "installer.openshift.io/previous-run-strategy"
+
+	// defaultStopTimeout is how long the VMs are given to shut down when stopping the cluster
+	// without a grace period.
+	defaultStopTimeout = 10 * time.Minute
+)
+
+// Stop stops the VMs of the cluster, setting their run strategy to Halted after recording the
+// one they had, and waits for them to shut down. The VMs, their disks and the other resources
+// of the cluster are kept for the cluster to be resumed.
+func (uninstaller *ClusterUninstaller) Stop() error {
+	timeout := uninstaller.GracePeriod
+	if timeout == 0 {
+		timeout = defaultStopTimeout
+	}
+	return uninstaller.setRunStrategy("stopped", func(namespace string, vm *unstructured.Unstructured, kubevirtClient ickubevirt.Client) (string, error) {
+		// The VMs already halted, e.g. by a previous Stop, keep the run strategy recorded then
+		if previous := vmRunStrategy(vm); previous != runStrategyHalted {
+			uninstaller.Logger.Infof("Record run strategy %s of VM %s", previous, vm.GetName())
+			if err := kubevirtClient.AnnotateVirtualMachine(context.TODO(), namespace, vm.GetName(), map[string]string{previousRunStrategyAnnotation: previous}); err != nil {
+				return "", err
+			}
+		}
+		return runStrategyHalted, nil
+	}, func(namespace string, vmName string, kubevirtClient ickubevirt.Client) error {
+		uninstaller.Logger.Infof("Wait for VM %s to stop", vmName)
+		return kubevirtClient.WaitForVirtualMachineStopped(namespace, vmName, timeout)
+	})
+}
+
+// Resume starts the VMs of a cluster stopped by Stop, restoring the run strategy they had, or
+// Always for the VMs stopped without recording it. The VMs with the Manual run strategy are
+// started explicitly, as KubeVirt only starts them on request.
+func (uninstaller *ClusterUninstaller) Resume() error {
+	return uninstaller.setRunStrategy("started", func(namespace string, vm *unstructured.Unstructured, kubevirtClient ickubevirt.Client) (string, error) {
+		if previous := vm.GetAnnotations()[previousRunStrategyAnnotation]; previous != "" {
+			return previous, nil
+		}
+		return runStrategyAlways, nil
+	}, nil)
+}
+
+// vmRunStrategy returns the run strategy of the VM, derived from its running field when it has
+// none.
+func vmRunStrategy(vm *unstructured.Unstructured) string {
+	if strategy, _, _ := unstructured.NestedString(vm.Object, "spec", "runStrategy"); strategy != "" {
+		return strategy
+	}
+	if running, _, _ := unstructured.NestedBool(vm.Object, "spec", "running"); running {
+		return runStrategyAlways
+	}
+	return runStrategyHalted
+}
+
+// runStrategyFunc returns the run strategy a VM of the cluster is set to.
+type runStrategyFunc func(namespace string, vm *unstructured.Unstructured, kubevirtClient ickubevirt.Client) (string, error)
+
+// setRunStrategy sets the run strategy of all the VMs of the cluster to the one returned by
+// strategy, then calls wait, if any, for each of the VMs which were updated. The errors of the
+// VMs are returned together once all of them were processed.
+func (uninstaller *ClusterUninstaller) setRunStrategy(action string, strategy runStrategyFunc, wait func(namespace string, vmName string, kubevirtClient ickubevirt.Client) error) error {
+	namespace := uninstaller.Metadata.Kubevirt.Namespace
+	labels := uninstaller.Metadata.Kubevirt.Labels
+
+	kubevirtClient, err := uninstaller.infraClient()
+	if err != nil {
+		return err
+	}
+	list, err := kubevirtClient.ListVirtualMachines(context.TODO(), namespace)
+	if err != nil {
+		err = uninstaller.tolerate(err, "VMs", namespace)
+		uninstaller.report(action)
+		return errors.Wrapf(err, "namespace %s", namespace)
+	}
+	var vms []string
+	var errs []error
+	for i := range list {
+		vm := &list[i]
+		if !hasAnnotations(vm.GetLabels(), labels) {
+			continue
+		}
+		if err := uninstaller.setVMRunStrategy(namespace, vm, strategy, kubevirtClient); err != nil {
+			if err := uninstaller.tolerate(err, "VM", vm.GetName()); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		vms = append(vms, vm.GetName())
+	}
+	err = errors.Wrapf(utilerrors.NewAggregate(errs), "namespace %s", namespace)
+	// The VMs are all stopped or started before waiting for them, for them to shut down or boot
+	// in parallel
+	if wait != nil {
+		for _, vmName := range vms {
+			if waitErr := wait(namespace, vmName, kubevirtClient); waitErr != nil {
+				return utilerrors.NewAggregate([]error{err, waitErr})
+			}
+		}
+	}
+	uninstaller.report(action)
+	return err
+}
+
+// setVMRunStrategy sets the run strategy of the VM to the one returned by strategy, starting
+// it when it is the Manual run strategy.
+func (uninstaller *ClusterUninstaller) setVMRunStrategy(namespace string, vm *unstructured.Unstructured, strategy runStrategyFunc, kubevirtClient ickubevirt.Client) error {
+	runStrategy, err := strategy(namespace, vm, kubevirtClient)
+	if err != nil {
+		return err
+	}
+	uninstaller.Logger.Infof("Set run strategy of VM %s to %s", vm.GetName(), runStrategy)
+	if err := kubevirtClient.SetVirtualMachineRunStrategy(namespace, vm.GetName(), runStrategy); err != nil {
+		return err
+	}
+	if runStrategy == string(kubevirttypes.RunStrategyManual) {
+		uninstaller.Logger.Infof("Start VM %s", vm.GetName())
+		return kubevirtClient.StartVirtualMachine(namespace, vm.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package kubevirt
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+// testVM returns a VM of the cluster with the spec and the annotations.
+func testVM(name string, spec map[string]interface{}, annotations map[string]string) unstructured.Unstructured {
+	vm := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	vm.SetName(name)
+	vm.SetLabels(testLabels)
+	vm.SetAnnotations(annotations)
+	return vm
+}
+
+// recordRunStrategy returns the annotations recording the run strategy of a stopped VM.
+func recordRunStrategy(runStrategy string) map[string]string {
+	return map[string]string{previousRunStrategyAnnotation: runStrategy}
+}
+
+func TestStop(t *testing.T) {
+	errNotStopped := fmt.Errorf("VM infra-id-master-1 did not stop within 2m0s")
+	cases := []struct {
+		name          string
+		gracePeriod   time.Duration
+		force         bool
+		expect        func(client *mock.MockClient)
+		expectedError string
+	}{
+		{
+			name: "default timeout",
+			expect: func(client *mock.MockClient) {
+				halted := []*gomock.Call{
+					client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", runStrategyHalted).Return(nil),
+					client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", runStrategyHalted).Return(nil),
+				}
+				// The VMs are all halted before waiting for them, to shut down in parallel
+				for _, vmName := range []string{"infra-id-master-0", "infra-id-master-1"} {
+					client.EXPECT().WaitForVirtualMachineStopped("tenant", vmName, defaultStopTimeout).Return(nil).After(halted[0]).After(halted[1])
+				}
+			},
+		},
+		{
+			name:        "VM not stopping within the grace period",
+			gracePeriod: 2 * time.Minute,
+			expect: func(client *mock.MockClient) {
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", runStrategyHalted).Return(nil)
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", runStrategyHalted).Return(nil)
+				client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-0", 2*time.Minute).Return(nil)
+				client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-1", 2*time.Minute).Return(errNotStopped)
+			},
+			expectedError: errNotStopped.Error(),
+		},
+		{
+			name:  "VM forbidden with force",
+			force: true,
+			expect: func(client *mock.MockClient) {
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", runStrategyHalted).Return(errVMForbidden)
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", runStrategyHalted).Return(nil)
+				// The VM left running is not waited for
+				client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-1", defaultStopTimeout).Return(nil)
+			},
+		},
+		{
+			name: "VM forbidden",
+			expect: func(client *mock.MockClient) {
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", runStrategyHalted).Return(errVMForbidden)
+				client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", runStrategyHalted).Return(nil)
+				client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-1", defaultStopTimeout).Return(nil)
+			},
+			expectedError: "namespace tenant: " + errVMForbidden.Error(),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			other := testVM("other-master-0", map[string]interface{}{"running": true}, nil)
+			other.SetLabels(map[string]string{"tenantcluster-other-machine.openshift.io": "owned"})
+			client.EXPECT().ListVirtualMachines(gomock.Any(), "tenant").Return([]unstructured.Unstructured{
+				testVM("infra-id-master-0", map[string]interface{}{"running": true}, nil),
+				testVM("infra-id-master-1", map[string]interface{}{"runStrategy": "RerunOnFailure"}, nil),
+				// The VMs of the other clusters are left running
+				other,
+			}, nil)
+			client.EXPECT().AnnotateVirtualMachine(gomock.Any(), "tenant", "infra-id-master-0", recordRunStrategy(runStrategyAlways)).Return(nil).AnyTimes()
+			client.EXPECT().AnnotateVirtualMachine(gomock.Any(), "tenant", "infra-id-master-1", recordRunStrategy("RerunOnFailure")).Return(nil).AnyTimes()
+			tc.expect(client)
+
+			uninstaller := testUninstaller()
+			uninstaller.client = client
+			uninstaller.SetForce(tc.force)
+			uninstaller.SetGracePeriod(tc.gracePeriod)
+			err := uninstaller.Stop()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStopRecordsRunStrategy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListVirtualMachines(gomock.Any(), "tenant").Return([]unstructured.Unstructured{
+		testVM("infra-id-master-0", map[string]interface{}{"runStrategy": "Manual"}, nil),
+		// A VM already stopped keeps the run strategy recorded when it was stopped
+		testVM("infra-id-master-1", map[string]interface{}{"runStrategy": runStrategyHalted}, recordRunStrategy("Manual")),
+	}, nil)
+	recorded := client.EXPECT().AnnotateVirtualMachine(gomock.Any(), "tenant", "infra-id-master-0", recordRunStrategy("Manual")).Return(nil)
+	// The run strategy is recorded before the VM is halted
+	client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", runStrategyHalted).Return(nil).After(recorded)
+	client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", runStrategyHalted).Return(nil)
+	client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-0", defaultStopTimeout).Return(nil)
+	client.EXPECT().WaitForVirtualMachineStopped("tenant", "infra-id-master-1", defaultStopTimeout).Return(nil)
+
+	uninstaller := testUninstaller()
+	uninstaller.client = client
+	assert.NoError(t, uninstaller.Stop())
+}
+
+func TestResume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	halted := map[string]interface{}{"runStrategy": runStrategyHalted}
+	client := mock.NewMockClient(mockCtrl)
+	client.EXPECT().ListVirtualMachines(gomock.Any(), "tenant").Return([]unstructured.Unstructured{
+		testVM("infra-id-master-0", halted, recordRunStrategy("RerunOnFailure")),
+		testVM("infra-id-master-1", halted, recordRunStrategy("Manual")),
+		// A VM stopped without recording its run strategy is resumed with Always
+		testVM("infra-id-master-2", halted, nil),
+	}, nil)
+	client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-0", "RerunOnFailure").Return(nil)
+	manual := client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-1", "Manual").Return(nil)
+	// KubeVirt only starts the VMs with the Manual run strategy on request
+	client.EXPECT().StartVirtualMachine("tenant", "infra-id-master-1").Return(nil).After(manual)
+	client.EXPECT().SetVirtualMachineRunStrategy("tenant", "infra-id-master-2", runStrategyAlways).Return(nil)
+
+	uninstaller := testUninstaller()
+	uninstaller.client = client
+	// The VMs are not waited for to boot
+	assert.NoError(t, uninstaller.Resume())
+}
@@ -0,0 +1,128 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// machineSetLabel is the label the machine-api sets on the machines of a MachineSet, holding the
+// name of the MachineSet, which the VMs of the machines carry when the provider copies it.
+const machineSetLabel = "machine.openshift.io/cluster-api-machineset"
+
+// DestroyPool deletes the VMs of the compute machine pool of the given name, and their
+// DataVolumes, keeping the rest of the cluster. namePrefix is the name prefix of the machine
+// pool, the infra ID of the cluster when empty. It returns the deleted resources, without
+// deleting them when dryRun is set.
+//
+// The VMs of the machine pool are the VMs of the cluster carrying the label of its MachineSet
+// or, lacking it, named after its MachineSet. The MachineSet must be scaled down or deleted
+// first, or the machine-api creates the VMs again.
+func (uninstaller *ClusterUninstaller) DestroyPool(pool string, namePrefix string, dryRun bool) ([]string, error) {
+	if namePrefix == "" {
+		namePrefix = uninstaller.Metadata.InfraID
+	}
+	machineSet := kubevirttypes.ResourceName(namePrefix, pool, 0)
+	uninstaller.deadline = time.Time{}
+	if uninstaller.Timeout > 0 {
+		uninstaller.deadline = time.Now().Add(uninstaller.Timeout)
+	}
+
+	kubevirtClient, err := uninstaller.infraClient()
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	if err := uninstaller.deletePoolNamespace(uninstaller.Metadata.Kubevirt.Namespace, machineSet, dryRun, kubevirtClient, &deleted); err != nil {
+		return deleted, err
+	}
+	for _, infraCluster := range uninstaller.Metadata.Kubevirt.InfraClusters {
+		kubevirtClient, err := ickubevirt.NewClientForInfraCluster(infraCluster)
+		if err != nil {
+			return deleted, err
+		}
+		if err := uninstaller.deletePoolNamespace(infraCluster.Namespace, machineSet, dryRun, kubevirtClient, &deleted); err != nil {
+			return deleted, err
+		}
+	}
+	uninstaller.report("deleted")
+	return deleted, nil
+}
+
+// deletePoolNamespace deletes the VMs of the MachineSet in the namespace of an infra cluster,
+// and their DataVolumes, appending them to deleted.
+func (uninstaller *ClusterUninstaller) deletePoolNamespace(namespace string, machineSet string, dryRun bool, kubevirtClient ickubevirt.Client, deleted *[]string) error {
+	vmLabels, err := kubevirtClient.ListResourceLabels(context.TODO(), namespace, "virtualmachines")
+	if err != nil {
+		return uninstaller.tolerate(err, "VMs", namespace)
+	}
+	var vms []string
+	for name, labels := range vmLabels {
+		if uninstaller.Metadata.Kubevirt.Owns(labels) && inMachineSet(name, labels, machineSet) {
+			vms = append(vms, name)
+		}
+	}
+	if len(vms) == 0 {
+		return nil
+	}
+	sort.Strings(vms)
+
+	dvLabels, err := kubevirtClient.ListResourceLabels(context.TODO(), namespace, "datavolumes")
+	if err != nil {
+		return uninstaller.tolerate(err, "DVs", namespace)
+	}
+	var dvs []string
+	for name, labels := range dvLabels {
+		if uninstaller.Metadata.Kubevirt.Owns(labels) && ofVMs(name, vms) {
+			dvs = append(dvs, name)
+		}
+	}
+	sort.Strings(dvs)
+
+	for _, vmName := range vms {
+		*deleted = append(*deleted, fmt.Sprintf("virtualmachines %s/%s", namespace, vmName))
+	}
+	for _, dvName := range dvs {
+		*deleted = append(*deleted, fmt.Sprintf("datavolumes %s/%s", namespace, dvName))
+	}
+	if dryRun {
+		return nil
+	}
+	if err := uninstaller.forEach(vms, func(vmName string) error {
+		return uninstaller.deleteVM(namespace, vmName, kubevirtClient)
+	}); err != nil {
+		return err
+	}
+	return uninstaller.forEach(dvs, func(dvName string) error {
+		uninstaller.Logger.Infof("Delete DV %s", dvName)
+		if err := kubevirtClient.DeleteDataVolume(namespace, dvName, true); err != nil {
+			return uninstaller.tolerate(err, "DV", dvName)
+		}
+		return nil
+	})
+}
+
+// inMachineSet returns whether the VM of the given name and labels belongs to the MachineSet,
+// carrying its label or, lacking the label, named after a machine of the MachineSet.
+func inMachineSet(name string, labels map[string]string, machineSet string) bool {
+	if value, ok := labels[machineSetLabel]; ok {
+		return value == machineSet
+	}
+	return strings.HasPrefix(name, machineSet+"-")
+}
+
+// ofVMs returns whether the DataVolume of the given name belongs to any of the VMs, being named
+// after it.
+func ofVMs(name string, vms []string) bool {
+	for _, vmName := range vms {
+		if name == vmName || strings.HasPrefix(name, vmName+"-") {
+			return true
+		}
+	}
+	return false
+}
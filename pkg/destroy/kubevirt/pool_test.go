@@ -0,0 +1,57 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMachineSet(t *testing.T) {
+	cases := []struct {
+		name     string
+		vmName   string
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			name:     "machine set label",
+			vmName:   "other-name",
+			labels:   map[string]string{machineSetLabel: "infra-id-experimental-0"},
+			expected: true,
+		},
+		{
+			name:     "other machine set label",
+			vmName:   "infra-id-experimental-0-abcde",
+			labels:   map[string]string{machineSetLabel: "infra-id-worker-0"},
+			expected: false,
+		},
+		{
+			name:     "machine name",
+			vmName:   "infra-id-experimental-0-abcde",
+			expected: true,
+		},
+		{
+			name:     "other machine name",
+			vmName:   "infra-id-worker-0-abcde",
+			expected: false,
+		},
+		{
+			name:     "master",
+			vmName:   "infra-id-master-0",
+			expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, inMachineSet(tc.vmName, tc.labels, "infra-id-experimental-0"))
+		})
+	}
+}
+
+func TestOfVMs(t *testing.T) {
+	vms := []string{"infra-id-experimental-0-abcde"}
+	assert.True(t, ofVMs("infra-id-experimental-0-abcde", vms))
+	assert.True(t, ofVMs("infra-id-experimental-0-abcde-bootvolume", vms))
+	assert.False(t, ofVMs("infra-id-experimental-0-abcdef", vms))
+	assert.False(t, ofVMs("infra-id-master-0", vms))
+}
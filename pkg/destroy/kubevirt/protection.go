@@ -0,0 +1,54 @@
+package kubevirt
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// protectionAnnotation is the annotation of the marker ConfigMap recording whether the cluster
+// is protected against deletion.
+const protectionAnnotation = "installer.openshift.io/deletion-protection"
+
+// protectionMarkerName returns the name of the marker ConfigMap of the cluster, in the
+// namespace of the cluster.
+func (uninstaller *ClusterUninstaller) protectionMarkerName() string {
+	return uninstaller.Metadata.InfraID + "-deletion-protection"
+}
+
+// SetProtected records the deletion protection of the cluster on its marker ConfigMap, which
+// is labeled as the other resources of the cluster so that destroy deletes it.
+func (uninstaller *ClusterUninstaller) SetProtected(protected bool) error {
+	kubevirtClient, err := uninstaller.infraClient()
+	if err != nil {
+		return err
+	}
+	return kubevirtClient.CreateOrUpdateConfigMap(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        uninstaller.protectionMarkerName(),
+			Namespace:   uninstaller.Metadata.Kubevirt.Namespace,
+			Labels:      uninstaller.Metadata.Kubevirt.Labels,
+			Annotations: map[string]string{protectionAnnotation: strconv.FormatBool(protected)},
+		},
+	})
+}
+
+// Protected returns whether the marker ConfigMap of the cluster records it as protected. The
+// clusters without a marker are not protected.
+func (uninstaller *ClusterUninstaller) Protected() (bool, error) {
+	kubevirtClient, err := uninstaller.infraClient()
+	if err != nil {
+		return false, err
+	}
+	marker, err := kubevirtClient.GetConfigMap(context.TODO(), uninstaller.Metadata.Kubevirt.Namespace, uninstaller.protectionMarkerName())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return marker.Annotations[protectionAnnotation] == "true", nil
+}
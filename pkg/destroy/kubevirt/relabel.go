@@ -0,0 +1,77 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// relabeledResources are the resources of the cluster whose labels Relabel restores, named
+// after the infra ID of the cluster.
+var relabeledResources = []string{"virtualmachines", "datavolumes", "secrets"}
+
+// Relabel adds the labels of the metadata to the resources of the cluster missing any of them,
+// the resources of the cluster being those named after its infra ID or carrying any of its
+// labels. It returns the relabeled resources, without relabeling them when dryRun is set.
+func (uninstaller *ClusterUninstaller) Relabel(dryRun bool) ([]string, error) {
+	namespace := uninstaller.Metadata.Kubevirt.Namespace
+	labels := uninstaller.Metadata.Kubevirt.Labels
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("the metadata of the cluster has no labels")
+	}
+
+	kubevirtClient, err := uninstaller.infraClient()
+	if err != nil {
+		return nil, err
+	}
+	var relabeled []string
+	for _, resource := range relabeledResources {
+		resourceLabels, err := kubevirtClient.ListResourceLabels(context.TODO(), namespace, resource)
+		if err != nil {
+			if err := uninstaller.tolerate(err, resource, namespace); err != nil {
+				return relabeled, err
+			}
+			continue
+		}
+		names := make([]string, 0, len(resourceLabels))
+		for name := range resourceLabels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			existing := resourceLabels[name]
+			if !uninstaller.ownsResource(name, existing) || hasLabels(existing, labels) {
+				continue
+			}
+			relabeled = append(relabeled, fmt.Sprintf("%s %s/%s", resource, namespace, name))
+			if dryRun {
+				continue
+			}
+			uninstaller.Logger.Infof("Relabel %s %s", resource, name)
+			if err := kubevirtClient.AddResourceLabels(context.TODO(), namespace, resource, name, labels); err != nil {
+				if err := uninstaller.tolerate(err, resource, name); err != nil {
+					return relabeled, err
+				}
+			}
+		}
+	}
+	uninstaller.report("relabeled")
+	return relabeled, nil
+}
+
+// ownsResource returns whether the resource of the given name and labels belongs to the cluster,
+// being named after its infra ID or carrying any of its label keys.
+func (uninstaller *ClusterUninstaller) ownsResource(name string, existing map[string]string) bool {
+	return strings.HasPrefix(name, uninstaller.Metadata.InfraID+"-") || uninstaller.Metadata.Kubevirt.HasLabelKey(existing)
+}
+
+// hasLabels returns whether the existing labels hold all the given ones.
+func hasLabels(existing map[string]string, labels map[string]string) bool {
+	for key, value := range labels {
+		if existing[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+func TestRelabel(t *testing.T) {
+	vms := map[string]map[string]string{
+		// Named after the infra ID, without the labels
+		"infra-id-master-0": {"kubevirt.io/vm": "infra-id-master-0"},
+		// Already labeled
+		"infra-id-master-1": testLabels,
+		// Carrying a label key of the cluster, with another value
+		"site-master-2": {"tenantcluster-infra-id-machine.openshift.io": "shared"},
+		// Not of the cluster
+		"other-infra-id-master-0": nil,
+		"infra-idle":              nil,
+	}
+	dvs := map[string]map[string]string{
+		"infra-id-master-0-bootvolume": nil,
+		"infra-id-master-1-bootvolume": testLabels,
+	}
+	expected := []string{
+		"virtualmachines tenant/infra-id-master-0",
+		"virtualmachines tenant/site-master-2",
+		"datavolumes tenant/infra-id-master-0-bootvolume",
+	}
+	cases := []struct {
+		name          string
+		dryRun        bool
+		force         bool
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:     "relabel",
+			expected: expected,
+		},
+		{
+			name:     "dry run",
+			dryRun:   true,
+			expected: expected,
+		},
+		{
+			name:          "secrets forbidden",
+			expected:      expected,
+			expectedError: errVMForbidden.Error(),
+		},
+		{
+			name:     "secrets forbidden with force",
+			force:    true,
+			expected: expected,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockClient(mockCtrl)
+			client.EXPECT().ListResourceLabels(gomock.Any(), "tenant", "virtualmachines").Return(vms, nil)
+			client.EXPECT().ListResourceLabels(gomock.Any(), "tenant", "datavolumes").Return(dvs, nil)
+			if tc.expectedError != "" || tc.force {
+				client.EXPECT().ListResourceLabels(gomock.Any(), "tenant", "secrets").Return(nil, errVMForbidden)
+			} else {
+				client.EXPECT().ListResourceLabels(gomock.Any(), "tenant", "secrets").Return(nil, nil)
+			}
+			if !tc.dryRun {
+				// Only the resources of the cluster missing its labels are patched, with all of them
+				client.EXPECT().AddResourceLabels(gomock.Any(), "tenant", "virtualmachines", "infra-id-master-0", testLabels).Return(nil)
+				client.EXPECT().AddResourceLabels(gomock.Any(), "tenant", "virtualmachines", "site-master-2", testLabels).Return(nil)
+				client.EXPECT().AddResourceLabels(gomock.Any(), "tenant", "datavolumes", "infra-id-master-0-bootvolume", testLabels).Return(nil)
+			}
+
+			uninstaller := testUninstaller()
+			uninstaller.client = client
+			uninstaller.SetForce(tc.force)
+			relabeled, err := uninstaller.Relabel(tc.dryRun)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, relabeled)
+		})
+	}
+}
+
+func TestRelabelWithoutLabels(t *testing.T) {
+	uninstaller := testUninstaller()
+	uninstaller.Metadata.Kubevirt.Labels = nil
+	_, err := uninstaller.Relabel(false)
+	assert.EqualError(t, err, "the metadata of the cluster has no labels")
+}
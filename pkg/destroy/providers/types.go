@@ -1,6 +1,10 @@
 package providers
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/types"
@@ -12,5 +16,70 @@ type Destroyer interface {
 	Run() error
 }
 
+// ForceDestroyer is implemented by destroyers which can continue past resources
+// they are not allowed to delete, reporting them at the end instead of aborting.
+type ForceDestroyer interface {
+	Destroyer
+	SetForce(force bool)
+}
+
+// SkippedResourcesError is returned by the Run of a ForceDestroyer when it went on past
+// resources it could not delete. The metadata of the cluster must be kept for another run to
+// delete them.
+type SkippedResourcesError struct {
+	// Resources describe the skipped resources and the errors they failed with.
+	Resources []string
+}
+
+func (e *SkippedResourcesError) Error() string {
+	return fmt.Sprintf("%d resources of the cluster could not be deleted:\n%s", len(e.Resources), strings.Join(e.Resources, "\n"))
+}
+
+// GracePeriodDestroyer is implemented by destroyers which can gracefully stop the
+// machines of the cluster before deleting them.
+type GracePeriodDestroyer interface {
+	Destroyer
+	SetGracePeriod(gracePeriod time.Duration)
+}
+
+// TimeoutDestroyer is implemented by destroyers which can give up deleting the resources of the
+// cluster after a timeout, failing with the resources left to delete.
+type TimeoutDestroyer interface {
+	Destroyer
+	SetTimeout(timeout time.Duration)
+}
+
+// ProtectionMarker is implemented by destroyers which record the deletion protection of the
+// cluster on a marker resource of the platform, so that it holds for every copy of the
+// metadata of the cluster.
+type ProtectionMarker interface {
+	Destroyer
+	SetProtected(protected bool) error
+	Protected() (bool, error)
+}
+
+// Hibernator is implemented by destroyers which can stop the machines of the cluster, keeping
+// them and their disks for the cluster to be resumed later, instead of deleting them.
+type Hibernator interface {
+	Destroyer
+	Stop() error
+	Resume() error
+}
+
+// Relabeler is implemented by destroyers which find the resources of the cluster by labels, and
+// can restore the labels of metadata.json on the resources of the cluster which lost them, for
+// destroy to find them again.
+type Relabeler interface {
+	Destroyer
+	Relabel(dryRun bool) ([]string, error)
+}
+
+// PoolDestroyer is implemented by destroyers which can delete the machines of a single compute
+// machine pool, keeping the rest of the cluster.
+type PoolDestroyer interface {
+	Destroyer
+	DestroyPool(pool string, namePrefix string, dryRun bool) ([]string, error)
+}
+
 // NewFunc is an interface for creating platform-specific destroyers.
 type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error)
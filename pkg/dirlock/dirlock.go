@@ -0,0 +1,99 @@
+// Package dirlock locks the assets directories, for the installers run concurrently on the
+// same machine, e.g. by parallel CI jobs, not to corrupt the state of each other.
+package dirlock
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the lock file of the assets directories. The lock file is left in the
+// directory when released: removing it would let a process lock the new file created by another
+// process while a third one still holds the lock of the removed one.
+const FileName = ".openshift_install.lock"
+
+var (
+	mutex sync.Mutex
+	// locked are the lock files of the directories locked by the process, by absolute path.
+	locked = map[string]*os.File{}
+)
+
+// Acquire locks the directory until Release is called or the process exits, creating the
+// directory if it doesn't exist. It fails at once when another process holds the lock, and
+// returns without error when the process already holds it.
+func Acquire(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, ok := locked[dir]; ok {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create the assets directory")
+	}
+	path := filepath.Join(dir, FileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the lock file of the assets directory")
+	}
+	acquired, err := tryLock(file)
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "failed to lock %s", path)
+	}
+	if !acquired {
+		owner := ""
+		if pid, err := lockOwner(file); err == nil {
+			owner = fmt.Sprintf(" (pid %d)", pid)
+		}
+		file.Close()
+		return errors.Errorf("the assets directory %s is in use by another openshift-install process%s, use a different --dir for concurrent installs", dir, owner)
+	}
+
+	// The pid of the owner is only informative, the lock is held whether it is written or not
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	locked[dir] = file
+	return nil
+}
+
+// Release unlocks the directory locked by Acquire.
+func Release(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	file, ok := locked[dir]
+	if !ok {
+		return nil
+	}
+	delete(locked, dir)
+	err = unlock(file)
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// lockOwner returns the pid written in the lock file by the process holding the lock.
+func lockOwner(file *os.File) (int, error) {
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,60 @@
+package dirlock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dirlock-")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// Another process holding the lock
+	other, err := os.OpenFile(filepath.Join(dir, FileName), os.O_RDWR|os.O_CREATE, 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer other.Close()
+	acquired, err := tryLock(other)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	_, err = other.WriteString("1234\n")
+	assert.NoError(t, err)
+
+	err = Acquire(dir)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is in use by another openshift-install process (pid 1234)")
+	}
+
+	assert.NoError(t, unlock(other))
+	assert.NoError(t, Acquire(dir))
+	assert.NoError(t, Acquire(dir), "the lock is held by the process")
+	acquired, err = tryLock(other)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	assert.NoError(t, Release(dir))
+	acquired, err = tryLock(other)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestAcquireCreatesDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dirlock-")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	assetsDir := filepath.Join(dir, "assets")
+	assert.NoError(t, Acquire(assetsDir))
+	defer Release(assetsDir)
+	assert.FileExists(t, filepath.Join(assetsDir, FileName))
+}
@@ -0,0 +1,60 @@
+// Package diskcheck verifies that directories have enough free space and inodes
+// before the installer starts writing large files into them.
+package diskcheck
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Space is an amount of free space and inodes in a directory.
+type Space struct {
+	// Bytes is the free space, in bytes.
+	Bytes uint64
+	// Inodes is the number of free inodes. It is ignored on
+	// filesystems which don't report inodes.
+	Inodes uint64
+}
+
+// Check verifies that the directory at path, which is created if missing, is
+// writable and has at least the required free space and inodes.
+func Check(path string, required Space) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create %s", path)
+	}
+	probe, err := ioutil.TempFile(path, ".diskcheck")
+	if err != nil {
+		return errors.Wrapf(err, "%s is not writable", path)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	available, err := Available(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the free space of %s", path)
+	}
+	if available.Bytes < required.Bytes {
+		return errors.Errorf("insufficient free space in %s: %s required, %s available", path, humanize(required.Bytes), humanize(available.Bytes))
+	}
+	if available.Inodes < required.Inodes {
+		return errors.Errorf("insufficient free inodes in %s: %d required, %d available", path, required.Inodes, available.Inodes)
+	}
+	return nil
+}
+
+// humanize returns the size in bytes with a binary unit suffix, e.g. 1.5 GiB.
+func humanize(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,69 @@
+package diskcheck
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diskcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		name     string
+		path     string
+		required Space
+		expected string
+	}{
+		{
+			name:     "enough space",
+			path:     dir,
+			required: Space{Bytes: 1, Inodes: 1},
+		},
+		{
+			name:     "missing directory is created",
+			path:     filepath.Join(dir, "assets"),
+			required: Space{Bytes: 1},
+		},
+		{
+			name:     "not enough space",
+			path:     dir,
+			required: Space{Bytes: math.MaxUint64},
+			expected: `^insufficient free space in .*: 16\.0 EiB required, .* available$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(tc.path, tc.required)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				assert.DirExists(t, tc.path)
+				files, err := ioutil.ReadDir(tc.path)
+				assert.NoError(t, err)
+				assert.Empty(t, files, "the write probe must be removed")
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	for size, expected := range map[uint64]string{
+		0:         "0 B",
+		1023:      "1023 B",
+		1024:      "1.0 KiB",
+		512 << 20: "512.0 MiB",
+		3 << 29:   "1.5 GiB",
+	} {
+		assert.Equal(t, expected, humanize(size))
+	}
+}
@@ -0,0 +1,20 @@
+// +build !windows
+
+package diskcheck
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// Available returns the free space and inodes, available to unprivileged
+// users, of the filesystem holding path.
+func Available(path string) (Space, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return Space{}, err
+	}
+	return Space{
+		Bytes:  uint64(stat.Bavail) * uint64(stat.Bsize),
+		Inodes: uint64(stat.Ffree),
+	}, nil
+}
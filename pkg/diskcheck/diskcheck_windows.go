@@ -0,0 +1,23 @@
+// +build windows
+
+package diskcheck
+
+import (
+	"math"
+
+	"golang.org/x/sys/windows"
+)
+
+// Available returns the free space available to the user of the volume holding
+// path. Windows volumes don't have an inode limit, so Inodes is unbounded.
+func Available(path string) (Space, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return Space{}, err
+	}
+	var freeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeBytes, nil, nil); err != nil {
+		return Space{}, err
+	}
+	return Space{Bytes: freeBytes, Inodes: math.MaxUint64}, nil
+}
@@ -0,0 +1,142 @@
+// Package dnscheck verifies, before the cluster is created, the DNS of its base domain from the
+// installer host: the delegation of the base domain and the records of the cluster.
+package dnscheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/libvirt"
+)
+
+// Resolver resolves DNS names, e.g. net.DefaultResolver.
+type Resolver interface {
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// record is a DNS record of the cluster, with the address it must resolve to when the DNS is
+// managed by the user, if known.
+type record struct {
+	name    string
+	display string
+	address string
+}
+
+// Check verifies the DNS of the base domain of the cluster, returning the warnings found and an
+// error for the misconfigurations which would fail the installation:
+//
+// When the installer manages the DNS of the cluster (AWS, Azure and GCP), the base domain of an
+// external cluster must be delegated, having NS records, and the records of the cluster must not
+// exist yet, as they would be left over by another cluster.
+//
+// When the user manages the DNS of the cluster, the records of the cluster which already exist
+// must resolve to the API and ingress VIPs of the platform; the missing records and a base domain
+// which isn't delegated are only reported, as the records may be created later or only resolve
+// from the network of the cluster.
+//
+// Failures to reach the DNS servers are reported as warnings.
+func Check(ctx context.Context, resolver Resolver, config *types.InstallConfig) ([]string, error) {
+	platform := config.Platform.Name()
+	if platform == libvirt.Name {
+		// The records of the cluster are served by the libvirt network
+		return nil, nil
+	}
+	managed := managedDNS(platform)
+	baseDomain := strings.TrimSuffix(config.BaseDomain, ".")
+
+	var warnings, errs []string
+	if !managed || config.Publish != types.InternalPublishingStrategy {
+		nss, err := resolver.LookupNS(ctx, baseDomain)
+		switch {
+		case (err == nil && len(nss) == 0) || isNotFound(err):
+			message := fmt.Sprintf("the base domain %s is not delegated: it has no NS records", baseDomain)
+			if managed {
+				errs = append(errs, message)
+			} else {
+				warnings = append(warnings, message)
+			}
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("failed to look up the NS records of the base domain %s: %v", baseDomain, err))
+		}
+	}
+
+	apiVIP, ingressVIP := vips(config)
+	clusterDomain := config.ClusterDomain()
+	records := []record{
+		{name: "api." + clusterDomain, display: "api." + clusterDomain, address: apiVIP},
+		// Any name of the wildcard record of the ingress
+		{name: "console-openshift-console.apps." + clusterDomain, display: "*.apps." + clusterDomain, address: ingressVIP},
+	}
+	for _, r := range records {
+		addresses, err := resolver.LookupHost(ctx, r.name)
+		switch {
+		case isNotFound(err):
+			if !managed {
+				warnings = append(warnings, fmt.Sprintf("%s does not resolve from the installer host, it must be created before installing", r.display))
+			}
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("failed to resolve %s: %v", r.display, err))
+		case managed:
+			errs = append(errs, fmt.Sprintf("%s already resolves to %s: the records of another cluster may be left over", r.display, strings.Join(addresses, ", ")))
+		case r.address != "" && !containsAddress(addresses, r.address):
+			errs = append(errs, fmt.Sprintf("%s resolves to %s, not to the VIP %s", r.display, strings.Join(addresses, ", "), r.address))
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, errors.Errorf("invalid DNS for base domain %s: %s", baseDomain, strings.Join(errs, "; "))
+	}
+	return warnings, nil
+}
+
+// managedDNS returns whether the installer manages the DNS records of the clusters of the platform.
+func managedDNS(platform string) bool {
+	switch platform {
+	case aws.Name, azure.Name, gcp.Name:
+		return true
+	default:
+		return false
+	}
+}
+
+// vips returns the API and ingress VIPs of the platform, empty when it has none.
+func vips(config *types.InstallConfig) (string, string) {
+	switch {
+	case config.Platform.BareMetal != nil:
+		return config.Platform.BareMetal.APIVIP, config.Platform.BareMetal.IngressVIP
+	case config.Platform.Kubevirt != nil:
+		return config.Platform.Kubevirt.APIVIP, config.Platform.Kubevirt.IngressVIP
+	case config.Platform.OpenStack != nil:
+		return config.Platform.OpenStack.APIVIP, config.Platform.OpenStack.IngressVIP
+	case config.Platform.Ovirt != nil:
+		return config.Platform.Ovirt.APIVIP, config.Platform.Ovirt.IngressVIP
+	case config.Platform.VSphere != nil:
+		return config.Platform.VSphere.APIVIP, config.Platform.VSphere.IngressVIP
+	default:
+		return "", ""
+	}
+}
+
+func isNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.IsNotFound
+}
+
+func containsAddress(addresses []string, address string) bool {
+	ip := net.ParseIP(address)
+	for _, a := range addresses {
+		if a == address || (ip != nil && ip.Equal(net.ParseIP(a))) {
+			return true
+		}
+	}
+	return false
+}
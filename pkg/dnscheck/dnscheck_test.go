@@ -0,0 +1,140 @@
+package dnscheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// fakeResolver resolves the names it holds, failing with a not found error for the others.
+type fakeResolver struct {
+	ns    map[string][]*net.NS
+	hosts map[string][]string
+	err   error
+}
+
+func (r *fakeResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if ns, ok := r.ns[name]; ok {
+		return ns, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if addresses, ok := r.hosts[host]; ok {
+		return addresses, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestCheck(t *testing.T) {
+	delegated := map[string][]*net.NS{"example.com": {{Host: "ns1.example.com."}}}
+	awsConfig := func(publish types.PublishingStrategy) *types.InstallConfig {
+		return &types.InstallConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			BaseDomain: "example.com",
+			Publish:    publish,
+			Platform:   types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+		}
+	}
+	kubevirtConfig := &types.InstallConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		BaseDomain: "example.com",
+		Platform:   types.Platform{Kubevirt: &kubevirt.Platform{APIVIP: "10.0.0.1", IngressVIP: "10.0.0.2"}},
+	}
+
+	cases := []struct {
+		name             string
+		config           *types.InstallConfig
+		resolver         *fakeResolver
+		expectedWarnings []string
+		expectedError    string
+	}{
+		{
+			name:     "managed",
+			config:   awsConfig(types.ExternalPublishingStrategy),
+			resolver: &fakeResolver{ns: delegated},
+		},
+		{
+			name:          "managed not delegated",
+			config:        awsConfig(types.ExternalPublishingStrategy),
+			resolver:      &fakeResolver{},
+			expectedError: `^invalid DNS for base domain example.com: the base domain example.com is not delegated: it has no NS records$`,
+		},
+		{
+			name:     "managed internal not delegated",
+			config:   awsConfig(types.InternalPublishingStrategy),
+			resolver: &fakeResolver{},
+		},
+		{
+			name:   "managed records left over",
+			config: awsConfig(types.ExternalPublishingStrategy),
+			resolver: &fakeResolver{ns: delegated, hosts: map[string][]string{
+				"api.test.example.com": {"192.0.2.1"},
+			}},
+			expectedError: `^invalid DNS for base domain example.com: api.test.example.com already resolves to 192.0.2.1: the records of another cluster may be left over$`,
+		},
+		{
+			name:   "user managed",
+			config: kubevirtConfig,
+			resolver: &fakeResolver{ns: delegated, hosts: map[string][]string{
+				"api.test.example.com":                            {"10.0.0.1"},
+				"console-openshift-console.apps.test.example.com": {"10.0.0.2"},
+			}},
+		},
+		{
+			name:     "user managed missing records",
+			config:   kubevirtConfig,
+			resolver: &fakeResolver{},
+			expectedWarnings: []string{
+				"the base domain example.com is not delegated: it has no NS records",
+				"api.test.example.com does not resolve from the installer host, it must be created before installing",
+				"*.apps.test.example.com does not resolve from the installer host, it must be created before installing",
+			},
+		},
+		{
+			name:   "user managed conflicting record",
+			config: kubevirtConfig,
+			resolver: &fakeResolver{ns: delegated, hosts: map[string][]string{
+				"api.test.example.com":                            {"10.0.0.1"},
+				"console-openshift-console.apps.test.example.com": {"10.0.0.9"},
+			}},
+			expectedError: `^invalid DNS for base domain example.com: \*\.apps\.test\.example\.com resolves to 10\.0\.0\.9, not to the VIP 10\.0\.0\.2$`,
+		},
+		{
+			name:     "resolver failure",
+			config:   awsConfig(types.ExternalPublishingStrategy),
+			resolver: &fakeResolver{err: errors.New("connection refused")},
+			expectedWarnings: []string{
+				"failed to look up the NS records of the base domain example.com: connection refused",
+				"failed to resolve api.test.example.com: connection refused",
+				"failed to resolve *.apps.test.example.com: connection refused",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := Check(context.Background(), tc.resolver, tc.config)
+			assert.Equal(t, tc.expectedWarnings, warnings)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
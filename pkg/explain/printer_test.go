@@ -22,9 +22,16 @@ func Test_PrintFields(t *testing.T) {
     additionalTrustBundle <string>
       AdditionalTrustBundle is a PEM-encoded X.509 certificate bundle that will be added to the nodes' trusted certificate store.
 
+    apiServerCertificate <object>
+      APIServerCertificate is a serving certificate of the API server signed by a CA of the user, served for the external names of the API instead of the certificate signed by the installer, so that the clients of the cluster trust its API from the start.
+
     apiVersion <string>
       APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
 
+    auditProfile <string>
+      Valid Values: "","Default","WriteRequestBodies","AllRequestBodies","None"
+      AuditProfile is the audit policy profile of the API servers of the cluster: "Default" logs the metadata of all the requests and the bodies of none, "WriteRequestBodies" also logs the bodies of the requests writing resources, "AllRequestBodies" also logs the bodies of the requests reading resources, and "None" logs no requests. When unset, the API servers use the Default profile.
+
     baseDomain <string> -required-
       BaseDomain is the base domain to which the cluster should belong.
 
@@ -56,9 +63,16 @@ func Test_PrintFields(t *testing.T) {
     metadata <object> -required-
       <empty>
 
+    mirrorPolicyKind <string>
+      Valid Values: "","ImageContentSourcePolicy","ImageDigestMirrorSet"
+      MirrorPolicyKind is the kind of the objects configuring the imageContentSources in the cluster, "ImageContentSourcePolicy" or "ImageDigestMirrorSet". ImageDigestMirrorSet requires a release serving the config.openshift.io/v1 ImageDigestMirrorSet API. When unset, ImageContentSourcePolicy objects are created.
+
     networking <object>
       Networking is the configuration for the pod network provider in the cluster.
 
+    ntpServers <[]string>
+      NTPServers are the NTP servers, IP addresses or host names, the nodes synchronize their clocks with instead of the default servers of RHCOS, e.g. for nested clusters on KubeVirt whose guest clocks drift.
+
     platform <object> -required-
       Platform is the configuration for the specific platform upon which to perform the installation.
 
@@ -73,8 +87,14 @@ func Test_PrintFields(t *testing.T) {
     pullSecret <string> -required-
       PullSecret is the secret to use when pulling images.
 
+    pullSecretScope <object>
+      PullSecretScope, when set, strips from the pull secret embedded into the cluster the credentials of the registries the cluster doesn't need, keeping only those of the registry of the release image, of the mirrors of imageContentSources and of the additional registries.
+
     sshKey <string>
-      SSHKey is the public Secure Shell (SSH) key to provide access to instances.`,
+      SSHKey is the public Secure Shell (SSH) key to provide access to instances.
+
+    timeouts <object>
+      Timeouts overrides how long the stages of the install and destroy are waited for.`,
 	}, {
 		path: []string{"publish"},
 		desc: ``,
@@ -0,0 +1,121 @@
+// Package featuregates toggles the experimental behaviors of the installer, which are off by
+// default and may change or go away in any release.
+package featuregates
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvVar is the environment variable listing the gates to enable, comma-separated, e.g.
+	// ParallelDestroy,WatchWaits. A gate can be disabled explicitly with Gate=false.
+	EnvVar = "OPENSHIFT_INSTALL_EXPERIMENTAL"
+	// FileEnvVar is the environment variable holding the path of a file listing the gates, one
+	// per line, lines starting with # being comments. The gates of EnvVar override the ones of
+	// the file.
+	FileEnvVar = "OPENSHIFT_INSTALL_EXPERIMENTAL_FILE"
+)
+
+// Gate is an experimental behavior of the installer.
+type Gate string
+
+const (
+	// ParallelDestroy deletes the resources of a kind in parallel on destroy, rather than one
+	// after the other.
+	ParallelDestroy Gate = "ParallelDestroy"
+	// WatchWaits waits for the ClusterOperators by watching them, reacting to their changes
+	// right away rather than on the next poll.
+	WatchWaits Gate = "WatchWaits"
+)
+
+// gates are the known gates with their description.
+var gates = map[Gate]string{
+	ParallelDestroy: "delete the resources of a kind in parallel on destroy",
+	WatchWaits:      "watch the ClusterOperators while waiting for them rather than polling them",
+}
+
+var enabled = map[Gate]bool{}
+
+// Configure enables the gates of the file, if any, then those of the spec, both being lists of
+// gates as read from FileEnvVar and EnvVar.
+func Configure(spec string, file string) error {
+	configured := map[Gate]bool{}
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the feature gates file")
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := parse(line, configured); err != nil {
+				return errors.Wrapf(err, "invalid feature gate in %s", file)
+			}
+		}
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if err := parse(entry, configured); err != nil {
+			return errors.Wrapf(err, "invalid feature gate in %s", EnvVar)
+		}
+	}
+	enabled = configured
+	return nil
+}
+
+// parse sets the gate of the entry, of the form Gate or Gate=bool, in configured.
+func parse(entry string, configured map[Gate]bool) error {
+	name, value := entry, true
+	if i := strings.Index(entry, "="); i >= 0 {
+		var err error
+		name = strings.TrimSpace(entry[:i])
+		if value, err = strconv.ParseBool(strings.TrimSpace(entry[i+1:])); err != nil {
+			return errors.Errorf("%q is not a boolean for %s", entry[i+1:], name)
+		}
+	}
+	if _, ok := gates[Gate(name)]; !ok {
+		return errors.Errorf("unknown gate %q, must be one of %s", name, strings.Join(Known(), ", "))
+	}
+	configured[Gate(name)] = value
+	return nil
+}
+
+// Enabled returns whether the gate is enabled.
+func Enabled(gate Gate) bool {
+	return enabled[gate]
+}
+
+// Active returns the summary of the enabled gates, sorted by name, each with its description.
+func Active() []string {
+	var active []string
+	for gate, on := range enabled {
+		if on {
+			active = append(active, string(gate)+": "+gates[gate])
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+// Known returns the names of the known gates, sorted.
+func Known() []string {
+	known := make([]string, 0, len(gates))
+	for gate := range gates {
+		known = append(known, string(gate))
+	}
+	sort.Strings(known)
+	return known
+}
@@ -0,0 +1,61 @@
+package featuregates
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	defer Configure("", "")
+
+	cases := []struct {
+		spec          string
+		expected      map[Gate]bool
+		expectedError string
+	}{
+		{spec: "", expected: map[Gate]bool{}},
+		{spec: "ParallelDestroy", expected: map[Gate]bool{ParallelDestroy: true}},
+		{spec: " ParallelDestroy , WatchWaits=true ", expected: map[Gate]bool{ParallelDestroy: true, WatchWaits: true}},
+		{spec: "ParallelDestroy,ParallelDestroy=false", expected: map[Gate]bool{ParallelDestroy: false}},
+		{spec: "Parallel", expectedError: `invalid feature gate in OPENSHIFT_INSTALL_EXPERIMENTAL: unknown gate "Parallel", must be one of ParallelDestroy, WatchWaits`},
+		{spec: "WatchWaits=maybe", expectedError: `invalid feature gate in OPENSHIFT_INSTALL_EXPERIMENTAL: "maybe" is not a boolean for WatchWaits`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			err := Configure(tc.spec, "")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, enabled)
+			}
+		})
+	}
+}
+
+func TestConfigureFile(t *testing.T) {
+	defer Configure("", "")
+
+	dir, err := ioutil.TempDir("", "featuregates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "gates")
+	if err := ioutil.WriteFile(file, []byte("# experimental\nParallelDestroy\n\nWatchWaits\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The gates of the spec override those of the file
+	assert.NoError(t, Configure("WatchWaits=false", file))
+	assert.True(t, Enabled(ParallelDestroy))
+	assert.False(t, Enabled(WatchWaits))
+	assert.Equal(t, []string{"ParallelDestroy: delete the resources of a kind in parallel on destroy"}, Active())
+
+	assert.Error(t, Configure("", filepath.Join(dir, "missing")))
+}
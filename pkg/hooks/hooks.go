@@ -0,0 +1,150 @@
+// Package hooks runs user-defined executables before and after the stages of the
+// installation, so that site-specific automation can integrate with the installer.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Phase is when a hook runs relative to its stage.
+type Phase string
+
+const (
+	// PhasePre runs the hook before the stage.
+	PhasePre Phase = "pre"
+	// PhasePost runs the hook once the stage succeeded.
+	PhasePost Phase = "post"
+)
+
+// Stage is a stage of the installation which hooks can run around.
+type Stage string
+
+const (
+	// StageManifests generates the manifests.
+	StageManifests Stage = "manifests"
+	// StageInfrastructure provisions the infrastructure of the cluster.
+	StageInfrastructure Stage = "infrastructure"
+	// StageBootstrap waits for the bootstrap to complete.
+	StageBootstrap Stage = "bootstrap"
+	// StageInstall waits for the installation to complete.
+	StageInstall Stage = "install"
+)
+
+var stages = []Stage{StageManifests, StageInfrastructure, StageBootstrap, StageInstall}
+
+// Context is the context of the stage, written as JSON to the standard input of the hooks.
+type Context struct {
+	// Phase is when the hook runs relative to the stage.
+	Phase Phase `json:"phase"`
+	// Stage is the stage the hook runs around.
+	Stage Stage `json:"stage"`
+	// Directory is the absolute path of the assets directory.
+	Directory string `json:"directory"`
+	// ClusterName is the name of the cluster, once its metadata is written.
+	ClusterName string `json:"clusterName,omitempty"`
+	// InfraID is the infrastructure ID of the cluster, once its metadata is written.
+	InfraID string `json:"infraID,omitempty"`
+	// Platform is the platform of the cluster, once its metadata is written.
+	Platform string `json:"platform,omitempty"`
+	// Kubeconfig is the path of the admin kubeconfig, once it is written.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Time is when the hook is run.
+	Time time.Time `json:"time"`
+}
+
+type hook struct {
+	phase Phase
+	stage Stage
+	path  string
+}
+
+var hooks []hook
+
+// Configure sets the hooks from their specifications, of the form PHASE-STAGE=PATH, e.g.
+// post-manifests=/usr/local/bin/patch-manifests. The hooks of the same phase and stage are
+// run in order.
+func Configure(specs []string) error {
+	configured := make([]hook, 0, len(specs))
+	for _, spec := range specs {
+		h, err := parse(spec)
+		if err != nil {
+			return errors.Wrapf(err, "invalid hook %q", spec)
+		}
+		configured = append(configured, h)
+	}
+	hooks = configured
+	return nil
+}
+
+func parse(spec string) (hook, error) {
+	i := strings.Index(spec, "=")
+	if i < 0 || i == len(spec)-1 {
+		return hook{}, errors.New("must be of the form PHASE-STAGE=PATH")
+	}
+	point, path := spec[:i], spec[i+1:]
+	for _, phase := range []Phase{PhasePre, PhasePost} {
+		if !strings.HasPrefix(point, string(phase)+"-") {
+			continue
+		}
+		stage := Stage(strings.TrimPrefix(point, string(phase)+"-"))
+		for _, s := range stages {
+			if s == stage {
+				return hook{phase: phase, stage: stage, path: path}, nil
+			}
+		}
+		return hook{}, errors.Errorf("unknown stage %q, must be one of %v", stage, stages)
+	}
+	return hook{}, errors.Errorf("unknown phase of %q, must start with %s- or %s-", point, PhasePre, PhasePost)
+}
+
+// Configured returns true if hooks are configured for the stage, in either phase.
+func Configured(stage Stage) bool {
+	for _, h := range hooks {
+		if h.stage == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the hooks of the phase and stage of the context in order, with the context on
+// their standard input. Their output is logged, and the first hook failing stops the run.
+func Run(ctx context.Context, hookContext Context) error {
+	var input []byte
+	for _, h := range hooks {
+		if h.phase != hookContext.Phase || h.stage != hookContext.Stage {
+			continue
+		}
+		if input == nil {
+			hookContext.Time = time.Now().UTC()
+			var err error
+			if input, err = json.Marshal(hookContext); err != nil {
+				return err
+			}
+		}
+		name := fmt.Sprintf("%s-%s", h.phase, h.stage)
+		logrus.Infof("Running the %s hook %s", name, h.path)
+		cmd := exec.CommandContext(ctx, h.path)
+		cmd.Dir = hookContext.Directory
+		cmd.Stdin = bytes.NewReader(input)
+		stdout := logrus.WithField("hook", name).WriterLevel(logrus.InfoLevel)
+		stderr := logrus.WithField("hook", name).WriterLevel(logrus.WarnLevel)
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		err := cmd.Run()
+		stdout.Close()
+		stderr.Close()
+		if err != nil {
+			return errors.Wrapf(err, "the %s hook %s failed", name, h.path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	defer Configure(nil)
+
+	cases := []struct {
+		spec          string
+		expectedError string
+	}{
+		{spec: "post-manifests=/bin/hook"},
+		{spec: "pre-install=hook.sh"},
+		{spec: "post-manifests", expectedError: `invalid hook "post-manifests": must be of the form PHASE-STAGE=PATH`},
+		{spec: "post-manifests=", expectedError: `invalid hook "post-manifests=": must be of the form PHASE-STAGE=PATH`},
+		{spec: "during-manifests=/bin/hook", expectedError: `invalid hook "during-manifests=/bin/hook": unknown phase of "during-manifests", must start with pre- or post-`},
+		{spec: "post-ignition=/bin/hook", expectedError: `invalid hook "post-ignition=/bin/hook": unknown stage "ignition", must be one of [manifests infrastructure bootstrap install]`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			err := Configure([]string{tc.spec})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	defer Configure(nil)
+
+	dir, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The hook saves its standard input in the working directory
+	hook := filepath.Join(dir, "hook.sh")
+	if err := ioutil.WriteFile(hook, []byte("#!/bin/sh\ncat > context.json\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	failing := filepath.Join(dir, "failing.sh")
+	if err := ioutil.WriteFile(failing, []byte("#!/bin/sh\nexit 1\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, Configure([]string{"post-manifests=" + hook, "pre-install=" + failing}))
+	assert.True(t, Configured(StageManifests))
+	assert.False(t, Configured(StageBootstrap))
+
+	assert.NoError(t, Run(context.TODO(), Context{Phase: PhasePre, Stage: StageManifests, Directory: dir}))
+	_, err = os.Stat(filepath.Join(dir, "context.json"))
+	assert.True(t, os.IsNotExist(err), "unexpected run of the post-manifests hook")
+
+	assert.NoError(t, Run(context.TODO(), Context{Phase: PhasePost, Stage: StageManifests, Directory: dir, ClusterName: "test-cluster"}))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "context.json"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	hookContext := Context{}
+	if assert.NoError(t, json.Unmarshal(data, &hookContext)) {
+		assert.Equal(t, PhasePost, hookContext.Phase)
+		assert.Equal(t, StageManifests, hookContext.Stage)
+		assert.Equal(t, dir, hookContext.Directory)
+		assert.Equal(t, "test-cluster", hookContext.ClusterName)
+		assert.False(t, hookContext.Time.IsZero())
+	}
+
+	assert.EqualError(t, Run(context.TODO(), Context{Phase: PhasePre, Stage: StageInstall, Directory: dir}), "the pre-install hook "+failing+" failed: exit status 1")
+}
@@ -0,0 +1,160 @@
+// Package imagecache finds and prunes the RHCOS source DataVolumes created by the
+// installer in the KubeVirt infra cluster, which the VMs of the clusters are cloned from.
+package imagecache
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// sourceSuffix is the suffix of the names of the source DataVolumes, prefixed by the infra ID
+// of the cluster which created them.
+const sourceSuffix = "-source-pvc"
+
+// tenantLabelPattern matches the label set by the installer on the resources of a cluster,
+// capturing its infra ID.
+var tenantLabelPattern = regexp.MustCompile(`^tenantcluster-(.+)-machine\.openshift\.io$`)
+
+// SourceImage is a source DataVolume created by the installer.
+type SourceImage struct {
+	Namespace string
+	Name      string
+	// InfraID is the infra ID of the cluster which created the DataVolume.
+	InfraID string
+	Created time.Time
+	// Clusters are the infra IDs of the clusters whose VMs are cloned from the DataVolume.
+	Clusters []string
+}
+
+// Referenced returns whether VMs are cloned from the DataVolume.
+func (i SourceImage) Referenced() bool {
+	return len(i.Clusters) > 0
+}
+
+// List returns the source DataVolumes in the namespaces, with the clusters whose VMs,
+// in any of the namespaces, are cloned from them.
+func List(ctx context.Context, client ickubevirt.Client, namespaces []string) ([]SourceImage, error) {
+	var images []SourceImage
+	// The clusters referencing each DataVolume, by namespace/name
+	references := map[string]map[string]bool{}
+	for _, namespace := range namespaces {
+		dvs, err := client.ListDataVolumes(ctx, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the DataVolumes of namespace %s", namespace)
+		}
+		for _, dv := range dvs {
+			if image, ok := sourceImage(dv); ok {
+				images = append(images, image)
+			}
+		}
+
+		vms, err := client.ListVirtualMachines(ctx, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the VMs of namespace %s", namespace)
+		}
+		for _, vm := range vms {
+			for _, source := range vmSources(vm) {
+				if references[source] == nil {
+					references[source] = map[string]bool{}
+				}
+				references[source][vmCluster(vm)] = true
+			}
+		}
+	}
+
+	for i := range images {
+		for cluster := range references[images[i].Namespace+"/"+images[i].Name] {
+			images[i].Clusters = append(images[i].Clusters, cluster)
+		}
+		sort.Strings(images[i].Clusters)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Namespace != images[j].Namespace {
+			return images[i].Namespace < images[j].Namespace
+		}
+		return images[i].Name < images[j].Name
+	})
+	return images, nil
+}
+
+// sourceImage returns the source DataVolume, if the DataVolume is one created by the installer.
+func sourceImage(dv unstructured.Unstructured) (SourceImage, bool) {
+	infraID := strings.TrimSuffix(dv.GetName(), sourceSuffix)
+	if infraID == dv.GetName() || infraID == "" {
+		return SourceImage{}, false
+	}
+	if _, ok := dv.GetLabels()[kubevirt.OwnerLabel(infraID)]; !ok {
+		return SourceImage{}, false
+	}
+	return SourceImage{
+		Namespace: dv.GetNamespace(),
+		Name:      dv.GetName(),
+		InfraID:   infraID,
+		Created:   dv.GetCreationTimestamp().Time,
+	}, true
+}
+
+// vmSources returns the namespace/name of the PVCs the DataVolumes of the VM are cloned from.
+func vmSources(vm unstructured.Unstructured) []string {
+	var sources []string
+	templates, _, _ := unstructured.NestedSlice(vm.Object, "spec", "dataVolumeTemplates")
+	for _, t := range templates {
+		template, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(template, "spec", "source", "pvc", "name")
+		if name == "" {
+			continue
+		}
+		namespace, _, _ := unstructured.NestedString(template, "spec", "source", "pvc", "namespace")
+		if namespace == "" {
+			namespace = vm.GetNamespace()
+		}
+		sources = append(sources, namespace+"/"+name)
+	}
+	return sources
+}
+
+// vmCluster returns the infra ID of the cluster of the VM, or the VM itself when it was not
+// created by the installer or the machine-api provider.
+func vmCluster(vm unstructured.Unstructured) string {
+	for label := range vm.GetLabels() {
+		if match := tenantLabelPattern.FindStringSubmatch(label); match != nil {
+			return match[1]
+		}
+	}
+	return fmt.Sprintf("VM %s/%s", vm.GetNamespace(), vm.GetName())
+}
+
+// Prune deletes the source DataVolumes which are not referenced and were created before
+// olderThan ago, returning the deleted ones. In dry-run mode, nothing is deleted.
+func Prune(client ickubevirt.Client, images []SourceImage, olderThan time.Duration, now time.Time, dryRun bool, logger logrus.FieldLogger) ([]SourceImage, error) {
+	var pruned []SourceImage
+	for _, image := range images {
+		if image.Referenced() || now.Sub(image.Created) < olderThan {
+			continue
+		}
+		if dryRun {
+			logger.Infof("Would delete DataVolume %s/%s", image.Namespace, image.Name)
+		} else {
+			logger.Infof("Delete DataVolume %s/%s", image.Namespace, image.Name)
+			if err := client.DeleteDataVolume(image.Namespace, image.Name, true); err != nil {
+				return pruned, errors.Wrapf(err, "failed to delete DataVolume %s/%s", image.Namespace, image.Name)
+			}
+		}
+		pruned = append(pruned, image)
+	}
+	return pruned, nil
+}
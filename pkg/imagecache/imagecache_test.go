@@ -0,0 +1,93 @@
+package imagecache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/kubevirt/mock"
+)
+
+// The creation timestamps of the resources are parsed in the local time zone
+var now = time.Date(2020, 11, 10, 12, 0, 0, 0, time.Local)
+
+func dataVolume(namespace string, name string, labels map[string]string, age time.Duration) unstructured.Unstructured {
+	dv := unstructured.Unstructured{Object: map[string]interface{}{}}
+	dv.SetNamespace(namespace)
+	dv.SetName(name)
+	dv.SetLabels(labels)
+	dv.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+	return dv
+}
+
+func virtualMachine(namespace string, name string, labels map[string]string, sourceNamespace string, sourceName string) unstructured.Unstructured {
+	pvc := map[string]interface{}{"name": sourceName}
+	if sourceNamespace != "" {
+		pvc["namespace"] = sourceNamespace
+	}
+	vm := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"dataVolumeTemplates": []interface{}{
+				map[string]interface{}{
+					"spec": map[string]interface{}{
+						"source": map[string]interface{}{"pvc": pvc},
+					},
+				},
+			},
+		},
+	}}
+	vm.SetNamespace(namespace)
+	vm.SetName(name)
+	vm.SetLabels(labels)
+	return vm
+}
+
+func TestListAndPrune(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	client := mock.NewMockClient(mockCtrl)
+
+	ctx := context.Background()
+	client.EXPECT().ListDataVolumes(ctx, "tenants").Return([]unstructured.Unstructured{
+		dataVolume("tenants", "live-1-source-pvc", map[string]string{"tenantcluster-live-1-machine.openshift.io": "owned"}, 72*time.Hour),
+		dataVolume("tenants", "orphan-1-source-pvc", map[string]string{"tenantcluster-orphan-1-machine.openshift.io": "owned"}, 72*time.Hour),
+		dataVolume("tenants", "recent-1-source-pvc", map[string]string{"tenantcluster-recent-1-machine.openshift.io": "owned"}, time.Hour),
+		// Not created by the installer
+		dataVolume("tenants", "user-source-pvc", nil, 72*time.Hour),
+		dataVolume("tenants", "live-1-master-0-bootvolume", map[string]string{"tenantcluster-live-1-machine.openshift.io": "owned"}, 72*time.Hour),
+	}, nil)
+	client.EXPECT().ListVirtualMachines(ctx, "tenants").Return([]unstructured.Unstructured{
+		virtualMachine("tenants", "live-1-master-0", map[string]string{"tenantcluster-live-1-machine.openshift.io": "owned"}, "tenants", "live-1-source-pvc"),
+	}, nil)
+	client.EXPECT().ListDataVolumes(ctx, "workers").Return(nil, nil)
+	client.EXPECT().ListVirtualMachines(ctx, "workers").Return([]unstructured.Unstructured{
+		virtualMachine("workers", "live-1-worker-abcde", map[string]string{"tenantcluster-live-1-machine.openshift.io": "owned"}, "tenants", "live-1-source-pvc"),
+		virtualMachine("workers", "user-vm", nil, "tenants", "live-1-source-pvc"),
+	}, nil)
+
+	images, err := List(ctx, client, []string{"tenants", "workers"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []SourceImage{
+		{Namespace: "tenants", Name: "live-1-source-pvc", InfraID: "live-1", Created: now.Add(-72 * time.Hour), Clusters: []string{"VM workers/user-vm", "live-1"}},
+		{Namespace: "tenants", Name: "orphan-1-source-pvc", InfraID: "orphan-1", Created: now.Add(-72 * time.Hour)},
+		{Namespace: "tenants", Name: "recent-1-source-pvc", InfraID: "recent-1", Created: now.Add(-time.Hour)},
+	}, images)
+
+	// Dry-run does not delete anything
+	pruned, err := Prune(client, images, 24*time.Hour, now, true, logrus.StandardLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, []SourceImage{images[1]}, pruned)
+
+	client.EXPECT().DeleteDataVolume("tenants", "orphan-1-source-pvc", true).Return(nil)
+	pruned, err = Prune(client, images, 24*time.Hour, now, false, logrus.StandardLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, []SourceImage{images[1]}, pruned)
+}
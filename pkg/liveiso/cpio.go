@@ -0,0 +1,44 @@
+package liveiso
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	cpioMagic   = "070701"
+	cpioTrailer = "TRAILER!!!"
+	// cpioRegularFile is the mode of the regular files, readable by root only.
+	cpioRegularFile = 0100600
+)
+
+// writeCPIO writes a newc cpio archive holding a single regular file.
+func writeCPIO(w io.Writer, name string, data []byte) error {
+	if err := writeCPIOEntry(w, 1, cpioRegularFile, name, data); err != nil {
+		return err
+	}
+	return writeCPIOEntry(w, 0, 0, cpioTrailer, nil)
+}
+
+// writeCPIOEntry writes the header, the name and the data of an entry, each padded to 4 bytes.
+func writeCPIOEntry(w io.Writer, ino int, mode int, name string, data []byte) error {
+	// ino, mode, uid, gid, nlink, mtime, filesize, devmajor, devminor, rdevmajor, rdevminor,
+	// namesize and check
+	nlink := 1
+	if mode == 0 {
+		nlink = 0
+	}
+	header := fmt.Sprintf("%s%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+		cpioMagic, ino, mode, 0, 0, nlink, 0, len(data), 0, 0, 0, 0, len(name)+1, 0)
+	entry := append([]byte(header), name...)
+	entry = append(entry, 0)
+	entry = append(entry, padding(len(entry))...)
+	entry = append(entry, data...)
+	entry = append(entry, padding(len(data))...)
+	_, err := w.Write(entry)
+	return err
+}
+
+func padding(n int) []byte {
+	return make([]byte, (4-n%4)%4)
+}
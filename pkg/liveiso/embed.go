@@ -0,0 +1,106 @@
+// Package liveiso writes RHCOS live ISOs booting with an embedded Ignition config.
+package liveiso
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// headerOffset is the offset of the header locating the embed area of the live ISO, at the
+	// end of its system area, as written by the CoreOS build.
+	headerOffset = 32768 - headerSize
+	headerSize   = 24
+	headerMagic  = "coreiso+"
+
+	// ignitionName is the name of the Ignition config in the initramfs archive of the embed
+	// area, where Ignition looks for it when booted from the live ISO.
+	ignitionName = "config.ign"
+)
+
+// EmbedArea returns the offset and the length of the area of the live ISO reserved for the
+// Ignition config.
+func EmbedArea(iso io.ReaderAt) (int64, int64, error) {
+	header := make([]byte, headerSize)
+	if _, err := iso.ReadAt(header, headerOffset); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read the embed area header")
+	}
+	if string(header[:len(headerMagic)]) != headerMagic {
+		return 0, 0, errors.New("not a live ISO with an embed area")
+	}
+	offset := int64(binary.LittleEndian.Uint64(header[8:16]))
+	length := int64(binary.LittleEndian.Uint64(header[16:24]))
+	if offset < headerOffset+headerSize || length == 0 {
+		return 0, 0, errors.Errorf("invalid embed area at offset %d of length %d", offset, length)
+	}
+	return offset, length, nil
+}
+
+// Embed writes the Ignition config in the embed area of the live ISO, replacing the config
+// already embedded if any.
+func Embed(iso *os.File, ignition []byte) error {
+	offset, length, err := EmbedArea(iso)
+	if err != nil {
+		return err
+	}
+	archive, err := ignitionArchive(ignition)
+	if err != nil {
+		return err
+	}
+	if int64(len(archive)) > length {
+		return errors.Errorf("the compressed Ignition config of %d bytes does not fit the embed area of %d bytes", len(archive), length)
+	}
+	// The rest of the area is zeroed, for the kernel to stop unpacking the archive
+	area := make([]byte, length)
+	copy(area, archive)
+	if _, err := iso.WriteAt(area, offset); err != nil {
+		return errors.Wrap(err, "failed to write the embed area")
+	}
+	return nil
+}
+
+// Write writes the live ISO at base to path, with the Ignition config embedded.
+func Write(base string, path string, ignition []byte) (err error) {
+	in, err := os.Open(base)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// The Ignition configs hold the pull secret
+	out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+	if _, err := io.Copy(out, in); err != nil {
+		return errors.Wrapf(err, "failed to copy %s", base)
+	}
+	return Embed(out, ignition)
+}
+
+// ignitionArchive returns the gzip-compressed newc cpio archive holding the Ignition config,
+// which the kernel unpacks in the initramfs.
+func ignitionArchive(ignition []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	if err := writeCPIO(gz, ignitionName, ignition); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
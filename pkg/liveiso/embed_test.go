@@ -0,0 +1,118 @@
+package liveiso
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAreaOffset = 40960
+	testAreaLength = 8192
+)
+
+// writeTestISO writes a fake live ISO holding only the header of its embed area.
+func writeTestISO(t *testing.T, path string, withHeader bool) {
+	iso := make([]byte, 65536)
+	for i := range iso {
+		iso[i] = 0xff
+	}
+	if withHeader {
+		copy(iso[headerOffset:], headerMagic)
+		binary.LittleEndian.PutUint64(iso[headerOffset+8:], testAreaOffset)
+		binary.LittleEndian.PutUint64(iso[headerOffset+16:], testAreaLength)
+	}
+	if err := ioutil.WriteFile(path, iso, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "liveiso")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	base := filepath.Join(dir, "base.iso")
+	writeTestISO(t, base, true)
+	unsupported := filepath.Join(dir, "unsupported.iso")
+	writeTestISO(t, unsupported, false)
+
+	large := make([]byte, 2*testAreaLength)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		base          string
+		ignition      []byte
+		expectedError string
+	}{
+		{
+			name:     "embedded",
+			base:     base,
+			ignition: []byte(`{"ignition":{"version":"3.1.0"}}`),
+		},
+		{
+			name:          "no embed area",
+			base:          unsupported,
+			ignition:      []byte(`{"ignition":{"version":"3.1.0"}}`),
+			expectedError: "not a live ISO with an embed area",
+		},
+		{
+			name:          "too large",
+			base:          base,
+			ignition:      large,
+			expectedError: "does not fit the embed area of 8192 bytes",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "out.iso")
+			err := Write(tc.base, path, tc.ignition)
+			if tc.expectedError != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedError)
+				}
+				_, err := os.Stat(path)
+				assert.True(t, os.IsNotExist(err), "the ISO is removed on failure")
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			iso, err := ioutil.ReadFile(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, iso, 65536)
+			assert.Equal(t, byte(0xff), iso[testAreaOffset-1], "unexpected write before the embed area")
+			assert.Equal(t, byte(0xff), iso[testAreaOffset+testAreaLength], "unexpected write after the embed area")
+
+			gz, err := gzip.NewReader(bytes.NewReader(iso[testAreaOffset : testAreaOffset+testAreaLength]))
+			if !assert.NoError(t, err) {
+				return
+			}
+			gz.Multistream(false)
+			archive, err := ioutil.ReadAll(gz)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, strings.HasPrefix(string(archive), cpioMagic), "not a newc cpio archive")
+			assert.Contains(t, string(archive), ignitionName+"\x00")
+			assert.Contains(t, string(archive), string(tc.ignition))
+			assert.Contains(t, string(archive), cpioTrailer+"\x00")
+			assert.Equal(t, 0, len(archive)%4, "unaligned cpio archive")
+		})
+	}
+}
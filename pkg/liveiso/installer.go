@@ -0,0 +1,123 @@
+package liveiso
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// installIgnitionPath is where the live system writes the Ignition config of the machine,
+	// which coreos-installer installs with.
+	installIgnitionPath = "/etc/coreos/installer.ign"
+	// registriesPath and trustBundlePath hold the mirrors of the release image and the
+	// additional trust bundle, in the live system.
+	registriesPath  = "/etc/containers/registries.conf"
+	trustBundlePath = "/etc/pki/ca-trust/source/anchors/openshift-install-ca-bundle.crt"
+
+	installerUnitName = "coreos-installer.service"
+	installerUnit     = `[Unit]
+Description=Install RHCOS to %[1]s
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/coreos-installer install --ignition-file %[2]s %[1]s
+ExecStart=/usr/bin/systemctl --no-block reboot
+StandardOutput=kmsg+console
+StandardError=kmsg+console
+
+[Install]
+WantedBy=multi-user.target
+`
+)
+
+// Installer holds the installation of a machine from the live ISO.
+type Installer struct {
+	// Device is the disk RHCOS is installed to, e.g. /dev/vda.
+	Device string
+	// Ignition is the Ignition config of the machine, bootstrap, master or worker.
+	Ignition []byte
+	// ImageContentSources are the mirrors of the release image content, and
+	// AdditionalTrustBundle the certificates trusted for them.
+	ImageContentSources   []types.ImageContentSource
+	AdditionalTrustBundle string
+}
+
+// Config returns the Ignition config of the live system, installing RHCOS with the Ignition
+// config of the machine to the device and rebooting into it.
+func (i Installer) Config() ([]byte, error) {
+	compressed := &bytes.Buffer{}
+	gz := gzip.NewWriter(compressed)
+	if _, err := gz.Write(i.Ignition); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	// The Ignition config of the bootstrap machine is large, it is compressed for the live
+	// Ignition config to fit the embed area
+	machineIgnition := ignition.FileFromBytes(installIgnitionPath, "root", 0600, compressed.Bytes())
+	machineIgnition.Contents.Compression = ignutil.StrToPtr("gzip")
+
+	config := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+	}
+	config.Storage.Files = append(config.Storage.Files, machineIgnition)
+	if registries := registriesConf(i.ImageContentSources); registries != "" {
+		config.Storage.Files = append(config.Storage.Files, ignition.FileFromString(registriesPath, "root", 0644, registries))
+	}
+	if i.AdditionalTrustBundle != "" {
+		config.Storage.Files = append(config.Storage.Files, ignition.FileFromString(trustBundlePath, "root", 0644, i.AdditionalTrustBundle))
+	}
+	config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+		Name:     installerUnitName,
+		Contents: ignutil.StrToPtr(fmt.Sprintf(installerUnit, i.Device, installIgnitionPath)),
+		Enabled:  ignutil.BoolToPtr(true),
+	})
+	return ignition.Marshal(config)
+}
+
+// registriesConf returns the registries.conf of the mirrors, in the format of the one of the
+// bootstrap machine, or an empty string for none. The mirrors of the same source are merged,
+// registries.conf rejecting duplicate registries.
+func registriesConf(sources []types.ImageContentSource) string {
+	var locations []string
+	mirrors := map[string]sets.String{}
+	ordered := map[string][]string{}
+	for _, source := range sources {
+		if _, ok := mirrors[source.Source]; !ok {
+			locations = append(locations, source.Source)
+			mirrors[source.Source] = sets.NewString()
+		}
+		for _, mirror := range source.Mirrors {
+			if !mirrors[source.Source].Has(mirror) {
+				mirrors[source.Source].Insert(mirror)
+				ordered[source.Source] = append(ordered[source.Source], mirror)
+			}
+		}
+	}
+
+	conf := &strings.Builder{}
+	for _, location := range locations {
+		if len(ordered[location]) == 0 {
+			continue
+		}
+		fmt.Fprintf(conf, "[[registry]]\nlocation = %q\ninsecure = false\nmirror-by-digest-only = true\n\n", location)
+		for _, mirror := range ordered[location] {
+			fmt.Fprintf(conf, "[[registry.mirror]]\nlocation = %q\ninsecure = false\n\n", mirror)
+		}
+	}
+	return conf.String()
+}
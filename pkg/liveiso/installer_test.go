@@ -0,0 +1,65 @@
+package liveiso
+
+import (
+	"encoding/json"
+	"testing"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestInstallerConfig(t *testing.T) {
+	data, err := Installer{
+		Device:   "/dev/sda",
+		Ignition: []byte(`{"ignition":{"version":"3.1.0"}}`),
+		ImageContentSources: []types.ImageContentSource{
+			{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp/release"}},
+			{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp/release", "backup.example.com/ocp/release"}},
+			{Source: "quay.io/unmirrored"},
+		},
+		AdditionalTrustBundle: "-----BEGIN CERTIFICATE-----\n",
+	}.Config()
+	if !assert.NoError(t, err) {
+		return
+	}
+	config := &igntypes.Config{}
+	if !assert.NoError(t, json.Unmarshal(data, config)) {
+		return
+	}
+
+	paths := []string{}
+	for _, file := range config.Storage.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{installIgnitionPath, registriesPath, trustBundlePath}, paths)
+	if assert.NotNil(t, config.Storage.Files[0].Contents.Compression) {
+		assert.Equal(t, "gzip", *config.Storage.Files[0].Contents.Compression)
+	}
+	if assert.Len(t, config.Systemd.Units, 1) {
+		unit := config.Systemd.Units[0]
+		assert.Equal(t, installerUnitName, unit.Name)
+		assert.Contains(t, *unit.Contents, "coreos-installer install --ignition-file /etc/coreos/installer.ign /dev/sda")
+	}
+
+	expectedRegistries := `[[registry]]
+location = "quay.io/openshift-release-dev/ocp-release"
+insecure = false
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com/ocp/release"
+insecure = false
+
+[[registry.mirror]]
+location = "backup.example.com/ocp/release"
+insecure = false
+
+`
+	assert.Equal(t, expectedRegistries, registriesConf([]types.ImageContentSource{
+		{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp/release"}},
+		{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp/release", "backup.example.com/ocp/release"}},
+		{Source: "quay.io/unmirrored"},
+	}))
+}
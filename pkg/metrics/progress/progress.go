@@ -0,0 +1,103 @@
+// Package progress exposes the progress of the installer commands as Prometheus metrics on a
+// local HTTP endpoint, for the observability stacks of long-running installs to scrape.
+package progress
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	start = time.Now()
+
+	registry = prometheus.NewRegistry()
+
+	stage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "openshift_install_stage",
+		Help: "Whether the stage of the installer is running (1) or finished (0).",
+	}, []string{"stage"})
+
+	elapsed = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "openshift_install_elapsed_seconds",
+		Help: "Seconds elapsed since the installer started.",
+	}, func() float64 {
+		return time.Since(start).Seconds()
+	})
+
+	retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openshift_install_retries_total",
+		Help: "Number of times the installer retried an operation.",
+	}, []string{"operation"})
+
+	apiErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openshift_install_api_errors_total",
+		Help: "Number of errors returned by the cluster API to the installer.",
+	}, []string{"operation"})
+)
+
+func init() {
+	registry.MustRegister(stage, elapsed, retries, apiErrors)
+}
+
+// StageStarted records that the stage is running.
+func StageStarted(name string) {
+	stage.WithLabelValues(name).Set(1)
+}
+
+// StageFinished records that the stage is finished.
+func StageFinished(name string) {
+	stage.WithLabelValues(name).Set(0)
+}
+
+// Retry records a retry of the operation.
+func Retry(operation string) {
+	retries.WithLabelValues(operation).Inc()
+}
+
+// APIError records an error returned by the cluster API to the operation.
+func APIError(operation string) {
+	apiErrors.WithLabelValues(operation).Inc()
+}
+
+// Handler returns the handler serving the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		families, err := registry.Gather()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", string(expfmt.FmtText))
+		encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+		for _, family := range families {
+			if err := encoder.Encode(family); err != nil {
+				logrus.Debugf("Failed to write the metrics: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// Serve serves the metrics on the /metrics path of the address, e.g. ":9100", in the background.
+// It returns once the address is listened on.
+func Serve(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", address)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			logrus.Warnf("Stopped serving the metrics: %v", err)
+		}
+	}()
+	logrus.Debugf("Serving the metrics on http://%s/metrics", listener.Addr())
+	return nil
+}
@@ -0,0 +1,33 @@
+package progress
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	StageStarted("API")
+	StageStarted("Bootstrap Complete")
+	StageFinished("API")
+	Retry("API")
+	Retry("API")
+	APIError("API")
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `openshift_install_stage{stage="API"} 0`)
+	assert.Contains(t, string(body), `openshift_install_stage{stage="Bootstrap Complete"} 1`)
+	assert.Contains(t, string(body), `openshift_install_retries_total{operation="API"} 2`)
+	assert.Contains(t, string(body), `openshift_install_api_errors_total{operation="API"} 1`)
+	assert.Contains(t, string(body), `openshift_install_elapsed_seconds `)
+}
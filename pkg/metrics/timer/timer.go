@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/metrics/progress"
+	"github.com/openshift/installer/pkg/metrics/tracing"
 )
 
 // Timer is the struct that keeps track of each of the sections.
@@ -23,13 +26,18 @@ const (
 var timer = NewTimer()
 
 // StartTimer initiailzes the timer object with the current timestamp information.
+// The stage is also traced as a span and reported as running in the progress metrics.
 func StartTimer(key string) {
 	timer.StartTimer(key)
+	tracing.StartSpan(key)
+	progress.StageStarted(key)
 }
 
 // StopTimer records the duration for the current stage sent as the key parameter and stores the information.
 func StopTimer(key string) {
 	timer.StopTimer(key)
+	tracing.EndSpan(key, nil)
+	progress.StageFinished(key)
 }
 
 // LogSummary prints the summary of all the times collected so far into the INFO section.
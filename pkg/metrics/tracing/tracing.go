@@ -0,0 +1,349 @@
+// Package tracing records the stages of the installer commands as OpenTelemetry
+// spans, exported with the OTLP/HTTP JSON protocol when an OTLP endpoint is set.
+//
+// The OpenTelemetry SDK environment variables honoured are:
+//   - OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, the URL the spans are sent to as-is
+//   - OTEL_EXPORTER_OTLP_ENDPOINT, the base URL the spans are sent to at its /v1/traces path
+//   - OTEL_EXPORTER_OTLP_TRACES_HEADERS and OTEL_EXPORTER_OTLP_HEADERS, the comma-separated
+//     key=value headers of the requests, the former taking precedence
+//   - OTEL_SERVICE_NAME, the service.name of the resource, openshift-install by default
+//   - OTEL_SDK_DISABLED=true and OTEL_TRACES_EXPORTER=none, which disable the export
+//
+// The other variables, like the protocol, compression, timeout or certificates, are not
+// supported.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/version"
+)
+
+// The OpenTelemetry SDK environment variables configuring the export.
+const (
+	EndpointEnvVar       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	HeadersEnvVar        = "OTEL_EXPORTER_OTLP_HEADERS"
+	TracesHeadersEnvVar  = "OTEL_EXPORTER_OTLP_TRACES_HEADERS"
+	ServiceNameEnvVar    = "OTEL_SERVICE_NAME"
+	SDKDisabledEnvVar    = "OTEL_SDK_DISABLED"
+	TracesExporterEnvVar = "OTEL_TRACES_EXPORTER"
+)
+
+// defaultServiceName is the service.name of the resource when OTEL_SERVICE_NAME is unset.
+const defaultServiceName = "openshift-install"
+
+// Config is the configuration of the export of the spans.
+type Config struct {
+	// Endpoint is the URL the spans are sent to.
+	Endpoint string
+	// Headers are the headers added to the export requests, e.g. to authenticate.
+	Headers map[string]string
+	// ServiceName is the service.name of the resource of the spans.
+	ServiceName string
+}
+
+// ConfigFromEnv returns the configuration of the export from the environment variables
+// read with getenv, nil when tracing is disabled.
+func ConfigFromEnv(getenv func(string) string) (*Config, error) {
+	if strings.EqualFold(strings.TrimSpace(getenv(SDKDisabledEnvVar)), "true") || strings.TrimSpace(getenv(TracesExporterEnvVar)) == "none" {
+		return nil, nil
+	}
+
+	config := &Config{ServiceName: getenv(ServiceNameEnvVar)}
+	if config.ServiceName == "" {
+		config.ServiceName = defaultServiceName
+	}
+	if endpoint := getenv(TracesEndpointEnvVar); endpoint != "" {
+		config.Endpoint = endpoint
+	} else if endpoint := getenv(EndpointEnvVar); endpoint != "" {
+		config.Endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	} else {
+		return nil, nil
+	}
+
+	headersEnvVar := TracesHeadersEnvVar
+	if getenv(headersEnvVar) == "" {
+		headersEnvVar = HeadersEnvVar
+	}
+	headers, err := parseHeaders(getenv(headersEnvVar))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", headersEnvVar)
+	}
+	config.Headers = headers
+	return config, nil
+}
+
+// parseHeaders parses the comma-separated key=value headers, whose values are URL-encoded.
+func parseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, header := range strings.Split(value, ",") {
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+		parts := strings.SplitN(header, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, errors.Errorf("header %q is not of the form key=value", header)
+		}
+		headerValue, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the value of header %q", key)
+		}
+		headers[key] = headerValue
+	}
+	return headers, nil
+}
+
+// Tracer records the spans of a single trace and exports them to an OTLP/HTTP collector.
+type Tracer struct {
+	config  Config
+	client  *http.Client
+	traceID string
+	// open is the stack of started spans, the last one being the parent of the next started span.
+	open     []*span
+	finished []*span
+}
+
+type span struct {
+	name     string
+	spanID   string
+	parentID string
+	start    time.Time
+	end      time.Time
+	err      error
+}
+
+var (
+	tracer     *Tracer
+	tracerLock sync.Mutex
+	tracerOnce sync.Once
+)
+
+// NewTracer returns a tracer exporting to the OTLP/HTTP collector configured.
+func NewTracer(config Config, client *http.Client) *Tracer {
+	return &Tracer{
+		config:  config,
+		client:  client,
+		traceID: randomID(16),
+	}
+}
+
+// defaultTracer returns the tracer configured from the environment, nil when tracing is disabled.
+func defaultTracer() *Tracer {
+	tracerOnce.Do(func() {
+		config, err := ConfigFromEnv(os.Getenv)
+		if err != nil {
+			logrus.Warnf("Tracing is disabled: %v", err)
+			return
+		}
+		if config != nil {
+			tracer = NewTracer(*config, &http.Client{Timeout: 10 * time.Second})
+		}
+	})
+	return tracer
+}
+
+// StartSpan starts a span, child of the latest started span which is still open.
+func StartSpan(name string) {
+	if t := defaultTracer(); t != nil {
+		tracerLock.Lock()
+		defer tracerLock.Unlock()
+		t.StartSpan(name)
+	}
+}
+
+// EndSpan ends the latest started span with the name, recording the error if any.
+func EndSpan(name string, err error) {
+	if t := defaultTracer(); t != nil {
+		tracerLock.Lock()
+		defer tracerLock.Unlock()
+		t.EndSpan(name, err)
+	}
+}
+
+// Flush ends the spans still open and exports all the spans. Errors are only logged,
+// as tracing must not fail the command.
+func Flush() {
+	if t := defaultTracer(); t != nil {
+		tracerLock.Lock()
+		defer tracerLock.Unlock()
+		if err := t.Flush(); err != nil {
+			logrus.Warnf("Failed to export the traces: %v", err)
+		}
+	}
+}
+
+// StartSpan starts a span, child of the latest started span which is still open.
+func (t *Tracer) StartSpan(name string) {
+	s := &span{name: name, spanID: randomID(8), start: time.Now()}
+	if len(t.open) > 0 {
+		s.parentID = t.open[len(t.open)-1].spanID
+	}
+	t.open = append(t.open, s)
+}
+
+// EndSpan ends the latest started span with the name, recording the error if any.
+// The spans started after it and still open are ended with it.
+func (t *Tracer) EndSpan(name string, err error) {
+	for i := len(t.open) - 1; i >= 0; i-- {
+		if t.open[i].name != name {
+			continue
+		}
+		t.open[i].err = err
+		t.endFrom(i)
+		return
+	}
+}
+
+// endFrom ends the open spans from the index, the innermost first.
+func (t *Tracer) endFrom(index int) {
+	now := time.Now()
+	for i := len(t.open) - 1; i >= index; i-- {
+		t.open[i].end = now
+		t.finished = append(t.finished, t.open[i])
+	}
+	t.open = t.open[:index]
+}
+
+// Flush ends the spans still open and exports all the finished spans.
+func (t *Tracer) Flush() error {
+	t.endFrom(0)
+	if len(t.finished) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(t.request())
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the spans")
+	}
+	req, err := http.NewRequest(http.MethodPost, t.config.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the export request")
+	}
+	for key, value := range t.config.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send the spans")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("failed to send the spans to %s: %s", t.config.Endpoint, resp.Status)
+	}
+	t.finished = nil
+	return nil
+}
+
+// The types bellow are the JSON encoding of the OTLP ExportTraceServiceRequest.
+
+type exportTraceServiceRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string `json:"traceId"`
+	SpanID            string `json:"spanId"`
+	ParentSpanID      string `json:"parentSpanId,omitempty"`
+	Name              string `json:"name"`
+	Kind              int    `json:"kind"`
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string `json:"endTimeUnixNano"`
+	Status            status `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeOk     = 1
+	statusCodeError  = 2
+)
+
+func (t *Tracer) request() exportTraceServiceRequest {
+	spans := make([]spanData, 0, len(t.finished))
+	for _, s := range t.finished {
+		data := spanData{
+			TraceID:           t.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Status:            status{Code: statusCodeOk},
+		}
+		if s.err != nil {
+			data.Status = status{Code: statusCodeError, Message: s.err.Error()}
+		}
+		spans = append(spans, data)
+	}
+
+	return exportTraceServiceRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: []keyValue{
+				{Key: "service.name", Value: anyValue{StringValue: t.config.ServiceName}},
+				{Key: "service.version", Value: anyValue{StringValue: version.Raw}},
+			}},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "github.com/openshift/installer"},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+// randomID returns a random hex-encoded ID of size bytes.
+func randomID(size int) string {
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Sprintf("failed to generate a random ID: %v", err))
+	}
+	return hex.EncodeToString(id)
+}
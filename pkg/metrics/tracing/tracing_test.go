@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracerFlush(t *testing.T) {
+	var requests []exportTraceServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var request exportTraceServiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer(Config{Endpoint: server.URL + "/v1/traces", Headers: map[string]string{"Authorization": "Bearer token"}, ServiceName: "installer-ci"}, server.Client())
+	tracer.StartSpan("Total")
+	tracer.StartSpan("Infrastructure")
+	tracer.EndSpan("Infrastructure", errors.New("terraform apply failed"))
+	tracer.StartSpan("Bootstrap Complete")
+	tracer.StartSpan("API")
+	// Ending a span ends the spans started after it
+	tracer.EndSpan("Bootstrap Complete", nil)
+	// Unknown spans are ignored
+	tracer.EndSpan("Console", nil)
+	if !assert.NoError(t, tracer.Flush()) {
+		return
+	}
+
+	if !assert.Len(t, requests, 1) {
+		return
+	}
+	assert.Equal(t, keyValue{Key: "service.name", Value: anyValue{StringValue: "installer-ci"}}, requests[0].ResourceSpans[0].Resource.Attributes[0])
+	spans := map[string]spanData{}
+	for _, s := range requests[0].ResourceSpans[0].ScopeSpans[0].Spans {
+		assert.Equal(t, tracer.traceID, s.TraceID)
+		assert.Len(t, s.SpanID, 16)
+		spans[s.Name] = s
+	}
+	if !assert.Len(t, spans, 4) {
+		return
+	}
+	assert.Empty(t, spans["Total"].ParentSpanID)
+	assert.Equal(t, spans["Total"].SpanID, spans["Infrastructure"].ParentSpanID)
+	assert.Equal(t, spans["Total"].SpanID, spans["Bootstrap Complete"].ParentSpanID)
+	assert.Equal(t, spans["Bootstrap Complete"].SpanID, spans["API"].ParentSpanID)
+	assert.Equal(t, status{Code: statusCodeError, Message: "terraform apply failed"}, spans["Infrastructure"].Status)
+	assert.Equal(t, status{Code: statusCodeOk}, spans["Total"].Status)
+
+	// The exported spans are not sent again
+	assert.NoError(t, tracer.Flush())
+	assert.Len(t, requests, 1)
+}
+
+func TestTracerFlushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer(Config{Endpoint: server.URL + "/v1/traces"}, server.Client())
+	tracer.StartSpan("Total")
+	assert.EqualError(t, tracer.Flush(), "failed to send the spans to "+server.URL+"/v1/traces: 503 Service Unavailable")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	cases := []struct {
+		name     string
+		env      map[string]string
+		expected *Config
+		err      string
+	}{
+		{
+			name: "disabled without endpoint",
+			env:  map[string]string{HeadersEnvVar: "a=b"},
+		},
+		{
+			name:     "base endpoint",
+			env:      map[string]string{EndpointEnvVar: "https://collector:4318/"},
+			expected: &Config{Endpoint: "https://collector:4318/v1/traces", Headers: map[string]string{}, ServiceName: "openshift-install"},
+		},
+		{
+			name: "traces endpoint used as-is",
+			env: map[string]string{
+				EndpointEnvVar:       "https://collector:4318",
+				TracesEndpointEnvVar: "https://traces.example.com/otlp",
+				ServiceNameEnvVar:    "installer-ci",
+			},
+			expected: &Config{Endpoint: "https://traces.example.com/otlp", Headers: map[string]string{}, ServiceName: "installer-ci"},
+		},
+		{
+			name: "headers",
+			env: map[string]string{
+				EndpointEnvVar: "https://collector:4318",
+				HeadersEnvVar:  "Authorization=Bearer%20token, x-tenant = ci",
+			},
+			expected: &Config{Endpoint: "https://collector:4318/v1/traces", Headers: map[string]string{"Authorization": "Bearer token", "x-tenant": "ci"}, ServiceName: "openshift-install"},
+		},
+		{
+			name: "traces headers take precedence",
+			env: map[string]string{
+				EndpointEnvVar:      "https://collector:4318",
+				HeadersEnvVar:       "a=b",
+				TracesHeadersEnvVar: "c=d",
+			},
+			expected: &Config{Endpoint: "https://collector:4318/v1/traces", Headers: map[string]string{"c": "d"}, ServiceName: "openshift-install"},
+		},
+		{
+			name: "invalid headers",
+			env:  map[string]string{EndpointEnvVar: "https://collector:4318", HeadersEnvVar: "Authorization"},
+			err:  `invalid OTEL_EXPORTER_OTLP_HEADERS: header "Authorization" is not of the form key=value`,
+		},
+		{
+			name: "sdk disabled",
+			env:  map[string]string{EndpointEnvVar: "https://collector:4318", SDKDisabledEnvVar: "true"},
+		},
+		{
+			name: "no traces exporter",
+			env:  map[string]string{EndpointEnvVar: "https://collector:4318", TracesExporterEnvVar: "none"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := ConfigFromEnv(func(key string) string { return tc.env[key] })
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
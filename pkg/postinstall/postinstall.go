@@ -0,0 +1,364 @@
+// Package postinstall runs the smoke checks verifying that an installed cluster can run
+// workloads: a deployment, a route, a persistent volume claim and, on KubeVirt, a load balancer
+// service served by the KubeVirt cloud controller manager.
+package postinstall
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+	"github.com/openshift/library-go/pkg/route/routeapihelpers"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The names of the checks.
+const (
+	CheckNamespace    = "namespace"
+	CheckDeployment   = "deployment"
+	CheckRoute        = "route"
+	CheckPVC          = "pvc"
+	CheckLoadBalancer = "load-balancer"
+)
+
+const (
+	// DefaultImage is the image of the smoke workloads, serving HTTP on port 8080.
+	DefaultImage = "registry.access.redhat.com/ubi8/httpd-24"
+	// DefaultTimeout is how long each check is given.
+	DefaultTimeout = 5 * time.Minute
+
+	namespacePrefix = "openshift-install-smoke-"
+	appName         = "smoke"
+	pvcPodName      = appName + "-pvc"
+	appPort         = 8080
+	pollInterval    = 2 * time.Second
+)
+
+// Options are the options of the checks.
+type Options struct {
+	// Image is the image of the smoke workloads, serving HTTP on port 8080. Defaults to
+	// DefaultImage.
+	Image string
+	// LoadBalancer enables the check of the LoadBalancer services.
+	LoadBalancer bool
+	// Timeout is how long each check is given. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// HTTPClient is the client requesting the route, with a 10 seconds timeout by default.
+	HTTPClient *http.Client
+}
+
+// Result is the result of a check.
+type Result struct {
+	Name    string
+	Passed  bool
+	Skipped bool
+	// Message describes the failure or the reason the check was skipped.
+	Message  string
+	Duration time.Duration
+}
+
+// checker runs the checks in a namespace of the cluster.
+type checker struct {
+	client    kubernetes.Interface
+	routes    routev1client.RoutesGetter
+	options   Options
+	namespace string
+}
+
+// Run runs the checks in a temporary namespace of the cluster, deleted once they completed, and
+// returns their results. The checks depending on a failed one are skipped.
+func Run(ctx context.Context, client kubernetes.Interface, routes routev1client.RoutesGetter, options Options) []Result {
+	if options.Image == "" {
+		options.Image = DefaultImage
+	}
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	c := &checker{client: client, routes: routes, options: options}
+
+	var results []Result
+	run := func(name string, check func(context.Context) error, dependencies ...string) {
+		for _, dependency := range dependencies {
+			for _, result := range results {
+				if result.Name == dependency && !result.Passed {
+					results = append(results, Result{Name: name, Skipped: true, Message: fmt.Sprintf("the %s check did not pass", dependency)})
+					return
+				}
+			}
+		}
+		logrus.Infof("Running the %s post-install check", name)
+		checkCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+		start := time.Now()
+		err := check(checkCtx)
+		result := Result{Name: name, Passed: err == nil, Duration: time.Since(start)}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	run(CheckNamespace, c.createNamespace)
+	run(CheckDeployment, c.checkDeployment, CheckNamespace)
+	run(CheckRoute, c.checkRoute, CheckDeployment)
+	run(CheckPVC, c.checkPVC, CheckNamespace)
+	if options.LoadBalancer {
+		run(CheckLoadBalancer, c.checkLoadBalancer, CheckDeployment)
+	}
+
+	if c.namespace != "" {
+		if err := client.CoreV1().Namespaces().Delete(ctx, c.namespace, metav1.DeleteOptions{}); err != nil {
+			logrus.Warnf("Failed to delete the namespace %s of the post-install checks: %v", c.namespace, err)
+		}
+	}
+	return results
+}
+
+// Failed returns the number of checks which failed.
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if !result.Passed && !result.Skipped {
+			failed++
+		}
+	}
+	return failed
+}
+
+// poll calls the condition until it returns true or the context is done. The last error of the
+// condition is returned on timeout.
+func poll(ctx context.Context, condition func() (bool, error)) error {
+	var lastErr error
+	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		done, err := condition()
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		return done, nil
+	}, ctx.Done())
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+func (c *checker) createNamespace(ctx context.Context) error {
+	namespace, err := c.client.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: namespacePrefix,
+			Labels:       map[string]string{"app": appName},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	c.namespace = namespace.Name
+	return nil
+}
+
+func (c *checker) checkDeployment(ctx context.Context) error {
+	deployment, err := c.client.AppsV1().Deployments(c.namespace).Create(ctx, smokeDeployment(c.namespace, c.options.Image), metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	err = poll(ctx, func() (bool, error) {
+		deployment, err = c.client.AppsV1().Deployments(c.namespace).Get(ctx, appName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return deployment.Status.AvailableReplicas > 0, nil
+	})
+	return errors.Wrap(err, "the deployment did not become available")
+}
+
+func (c *checker) checkRoute(ctx context.Context) error {
+	if _, err := c.client.CoreV1().Services(c.namespace).Create(ctx, smokeService(c.namespace, appName, corev1.ServiceTypeClusterIP), metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	route, err := c.routes.Routes(c.namespace).Create(ctx, smokeRoute(c.namespace), metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	var url string
+	err = poll(ctx, func() (bool, error) {
+		route, err = c.routes.Routes(c.namespace).Get(ctx, appName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		uri, _, err := routeapihelpers.IngressURI(route, "")
+		if err != nil {
+			return false, err
+		}
+		url = uri.String()
+		return true, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "the route was not admitted")
+	}
+
+	// The router answers 503 until it has an endpoint of the service
+	err = poll(ctx, func() (bool, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := c.options.HTTPClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return false, errors.Errorf("%s answered %s", url, resp.Status)
+		}
+		return true, nil
+	})
+	return errors.Wrapf(err, "the route %s did not serve the deployment", url)
+}
+
+func (c *checker) checkPVC(ctx context.Context) error {
+	if _, err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).Create(ctx, smokePVC(c.namespace), metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	// The claim is used by a pod, for the storage classes binding on the first consumer
+	if _, err := c.client.CoreV1().Pods(c.namespace).Create(ctx, smokePVCPod(c.namespace, c.options.Image), metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	var phase corev1.PersistentVolumeClaimPhase
+	err := poll(ctx, func() (bool, error) {
+		pvc, err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).Get(ctx, appName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = pvc.Status.Phase
+		if phase != corev1.ClaimBound {
+			return false, nil
+		}
+		pod, err := c.client.CoreV1().Pods(c.namespace).Get(ctx, pvcPodName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if phase != corev1.ClaimBound {
+		return errors.Wrapf(err, "the persistent volume claim was not bound, in phase %q", phase)
+	}
+	return errors.Wrap(err, "the pod of the persistent volume claim did not run")
+}
+
+func (c *checker) checkLoadBalancer(ctx context.Context) error {
+	name := appName + "-lb"
+	if _, err := c.client.CoreV1().Services(c.namespace).Create(ctx, smokeService(c.namespace, name, corev1.ServiceTypeLoadBalancer), metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	err := poll(ctx, func() (bool, error) {
+		service, err := c.client.CoreV1().Services(c.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return len(service.Status.LoadBalancer.Ingress) > 0, nil
+	})
+	return errors.Wrap(err, "the LoadBalancer service was not given an ingress by the cloud controller manager")
+}
+
+func smokeLabels() map[string]string {
+	return map[string]string{"app": appName}
+}
+
+func smokeContainer(image string) corev1.Container {
+	return corev1.Container{
+		Name:  appName,
+		Image: image,
+		Ports: []corev1.ContainerPort{{ContainerPort: appPort, Protocol: corev1.ProtocolTCP}},
+	}
+}
+
+// smokeDeployment returns the deployment serving HTTP with the image.
+func smokeDeployment(namespace string, image string) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: namespace, Labels: smokeLabels()},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: smokeLabels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: smokeLabels()},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{smokeContainer(image)}},
+			},
+		},
+	}
+}
+
+// smokeService returns the service of the deployment with the type.
+func smokeService(namespace string, name string, serviceType corev1.ServiceType) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: smokeLabels()},
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: smokeLabels(),
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       appPort,
+				TargetPort: intstr.FromInt(appPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// smokeRoute returns the unsecured route of the service of the deployment.
+func smokeRoute(namespace string) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: namespace, Labels: smokeLabels()},
+		Spec: routev1.RouteSpec{
+			To:   routev1.RouteTargetReference{Kind: "Service", Name: appName},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString("http")},
+		},
+	}
+}
+
+// smokePVC returns the claim of a volume of the default storage class.
+func smokePVC(namespace string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: namespace, Labels: smokeLabels()},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+}
+
+// smokePVCPod returns the pod mounting the volume of the claim.
+func smokePVCPod(namespace string, image string) *corev1.Pod {
+	container := smokeContainer(image)
+	container.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: pvcPodName, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{container},
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: appName},
+				},
+			}},
+		},
+	}
+}
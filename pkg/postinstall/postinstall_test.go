@@ -0,0 +1,44 @@
+package postinstall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestSmokeObjects(t *testing.T) {
+	deployment := smokeDeployment("ns", "image")
+	assert.Equal(t, "ns", deployment.Namespace)
+	assert.Equal(t, deployment.Spec.Selector.MatchLabels, deployment.Spec.Template.Labels)
+	assert.Equal(t, "image", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	for _, serviceType := range []corev1.ServiceType{corev1.ServiceTypeClusterIP, corev1.ServiceTypeLoadBalancer} {
+		service := smokeService("ns", "name", serviceType)
+		assert.Equal(t, serviceType, service.Spec.Type)
+		assert.True(t, labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(deployment.Spec.Template.Labels)))
+		assert.Equal(t, int32(appPort), service.Spec.Ports[0].TargetPort.IntVal)
+	}
+
+	route := smokeRoute("ns")
+	assert.Equal(t, appName, route.Spec.To.Name)
+	assert.Equal(t, smokeService("ns", appName, corev1.ServiceTypeClusterIP).Spec.Ports[0].Name, route.Spec.Port.TargetPort.StrVal)
+
+	pvc := smokePVC("ns")
+	pod := smokePVCPod("ns", "image")
+	assert.Equal(t, pvc.Name, pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, pod.Spec.Volumes[0].Name, pod.Spec.Containers[0].VolumeMounts[0].Name)
+	assert.False(t, labels.SelectorFromSet(smokeService("ns", appName, corev1.ServiceTypeClusterIP).Spec.Selector).Matches(labels.Set(pod.Labels)), "the PVC pod must not be an endpoint of the service")
+}
+
+func TestFailed(t *testing.T) {
+	results := []Result{
+		{Name: CheckNamespace, Passed: true},
+		{Name: CheckDeployment, Message: "not available"},
+		{Name: CheckRoute, Skipped: true},
+		{Name: CheckPVC, Passed: true},
+	}
+	assert.Equal(t, 1, Failed(results))
+	assert.Equal(t, 0, Failed(nil))
+}
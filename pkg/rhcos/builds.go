@@ -32,6 +32,10 @@ type metadata struct {
 	}
 	BaseURI string `json:"baseURI"`
 	Images  struct {
+		LiveISO struct {
+			Path   string `json:"path"`
+			SHA256 string `json:"sha256"`
+		} `json:"live-iso"`
 		QEMU struct {
 			Path               string `json:"path"`
 			SHA256             string `json:"sha256"`
@@ -51,18 +55,10 @@ type metadata struct {
 }
 
 func fetchRHCOSBuild(ctx context.Context, arch types.Architecture) (*metadata, error) {
-	file, err := data.Assets.Open(fmt.Sprintf("rhcos-%s.json", arch))
+	body, err := readRHCOSBuild(arch)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	body, err := ioutil.ReadAll(file)
-	if os.IsNotExist(err) {
-		return nil, errInvalidArch
-	} else if err != nil {
-		return nil, err
-	}
 
 	var meta *metadata
 	if err := json.Unmarshal(body, &meta); err != nil {
@@ -71,3 +67,16 @@ func fetchRHCOSBuild(ctx context.Context, arch types.Architecture) (*metadata, e
 
 	return meta, nil
 }
+
+// readRHCOSBuild returns the raw build metadata embedded for the architecture.
+func readRHCOSBuild(arch types.Architecture) ([]byte, error) {
+	file, err := data.Assets.Open(fmt.Sprintf("rhcos-%s.json", arch))
+	if os.IsNotExist(err) {
+		return nil, errInvalidArch
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/h2non/filetype/matchers"
@@ -18,11 +19,17 @@ import (
 	"github.com/ulikunitz/xz"
 
 	"golang.org/x/sys/unix"
+
+	"github.com/openshift/installer/pkg/diskcheck"
 )
 
 const (
 	applicationName = "openshift-installer"
 	imageDataType   = "image"
+
+	// compressionRatio is the expected ratio between the uncompressed and the
+	// compressed size of the images, which are cached uncompressed.
+	compressionRatio = 3
 )
 
 // getCacheDir returns a local path of the cache, where the installer should put the data:
@@ -59,18 +66,14 @@ func getCacheDir(dataType string) (string, error) {
 func cacheFile(reader io.Reader, filePath string, sha256Checksum string) (err error) {
 	logrus.Debugf("Unpacking file into %q...", filePath)
 
+	// The lock file is left in the cache: removing it would let a process lock the new file
+	// created by another process while a third one still holds the lock of the removed one.
 	flockPath := fmt.Sprintf("%s.lock", filePath)
-	flock, err := os.Create(flockPath)
+	flock, err := os.OpenFile(flockPath, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return err
 	}
 	defer flock.Close()
-	defer func() {
-		err2 := os.Remove(flockPath)
-		if err == nil {
-			err = err2
-		}
-	}()
 
 	err = unix.Flock(int(flock.Fd()), unix.LOCK_EX)
 	if err != nil {
@@ -84,9 +87,12 @@ func cacheFile(reader io.Reader, filePath string, sha256Checksum string) (err er
 	}()
 
 	_, err = os.Stat(filePath)
-	if err != nil && !os.IsNotExist(err) {
+	if err == nil {
 		return nil // another cacheFile beat us to it
 	}
+	if !os.IsNotExist(err) {
+		return err
+	}
 
 	tempPath := fmt.Sprintf("%s.tmp", filePath)
 
@@ -208,6 +214,17 @@ func DownloadFile(baseURL string, dataType string) (string, error) {
 		return "", errors.Errorf("bad status: %s", resp.Status)
 	}
 
+	// Fail early, before downloading, if the cache can't hold the file
+	if resp.ContentLength > 0 {
+		required := uint64(resp.ContentLength)
+		if ext := path.Ext(resp.Request.URL.Path); ext == ".gz" || ext == ".xz" {
+			required *= compressionRatio
+		}
+		if err := diskcheck.Check(cacheDir, diskcheck.Space{Bytes: required, Inodes: 2}); err != nil {
+			return "", errors.Wrap(err, "the image cache is too small")
+		}
+	}
+
 	// Get sha256 checksum if it was provided as a part of the URL
 	var sha256Checksum string
 	parsedURL, err := url.ParseRequestURI(baseURL)
@@ -0,0 +1,88 @@
+package containerdisk
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLayout builds the layout of a small disk image in a temporary directory.
+func testLayout(t *testing.T) (*Layout, []byte, func()) {
+	dir, err := ioutil.TempDir("", "containerdisk")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	disk := []byte(strings.Repeat("rhcos", 1000))
+	diskPath := filepath.Join(dir, "rhcos.qcow2")
+	if !assert.NoError(t, ioutil.WriteFile(diskPath, disk, 0644)) {
+		t.FailNow()
+	}
+	layout, err := Build(diskPath, filepath.Join(dir, "layout"), "amd64", "48.84")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return layout, disk, func() { os.RemoveAll(dir) }
+}
+
+func TestBuild(t *testing.T) {
+	layout, disk, cleanup := testLayout(t)
+	defer cleanup()
+
+	manifest, data, err := layout.ReadManifest()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, fmt.Sprintf("sha256:%x", sha256.Sum256(data)), layout.Manifest.Digest.String())
+	assert.Equal(t, ocispec.MediaTypeImageConfig, manifest.Config.MediaType)
+	if !assert.Len(t, manifest.Layers, 1) {
+		return
+	}
+	assert.Equal(t, ocispec.MediaTypeImageLayerGzip, manifest.Layers[0].MediaType)
+
+	index, err := ioutil.ReadFile(filepath.Join(layout.Dir, "index.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `"org.opencontainers.image.ref.name":"48.84"`)
+
+	f, err := os.Open(layout.blobPath(manifest.Layers[0].Digest))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.Layers[0].Size, info.Size())
+	gz, err := gzip.NewReader(f)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		names = append(names, hdr.Name)
+		assert.Equal(t, qemuUID, hdr.Uid)
+		assert.Equal(t, qemuUID, hdr.Gid)
+		if hdr.Typeflag == tar.TypeReg {
+			content, err := ioutil.ReadAll(tr)
+			assert.NoError(t, err)
+			assert.Equal(t, disk, content)
+		}
+	}
+	assert.Equal(t, []string{"disk/", "disk/rhcos.qcow2"}, names)
+}
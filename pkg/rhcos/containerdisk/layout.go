@@ -0,0 +1,210 @@
+// Package containerdisk packages RHCOS disk images as KubeVirt containerDisks, OCI images holding
+// the disk in their /disk directory, written as OCI image layouts which skopeo or podman push
+// to registries.
+package containerdisk
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// diskDirectory is the directory of the image KubeVirt boots the disk from.
+	diskDirectory = "disk"
+	// qemuUID is the user and group of the qemu process of the virt-launcher pods, which
+	// must be able to read the disk.
+	qemuUID = 107
+
+	indexFile      = "index.json"
+	blobsDirectory = "blobs"
+)
+
+// Layout is an OCI image layout directory holding a containerDisk.
+type Layout struct {
+	// Dir is the directory of the layout.
+	Dir string
+	// Manifest is the descriptor of the manifest of the image.
+	Manifest ocispec.Descriptor
+}
+
+// blobPath returns the path of the blob with the digest in the layout.
+func (l *Layout) blobPath(d digest.Digest) string {
+	return filepath.Join(l.Dir, blobsDirectory, d.Algorithm().String(), d.Encoded())
+}
+
+// ReadManifest returns the manifest of the image.
+func (l *Layout) ReadManifest() (*ocispec.Manifest, []byte, error) {
+	data, err := ioutil.ReadFile(l.blobPath(l.Manifest.Digest))
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest := &ocispec.Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse the manifest")
+	}
+	return manifest, data, nil
+}
+
+// Build writes to dir an OCI image layout of the containerDisk of the disk image, for the
+// architecture (e.g. amd64) and tagged with tag.
+func Build(diskPath string, dir string, arch string, tag string) (*Layout, error) {
+	layout := &Layout{Dir: dir}
+	if err := os.MkdirAll(filepath.Join(dir, blobsDirectory, digest.Canonical.String()), 0755); err != nil {
+		return nil, err
+	}
+
+	layer, diffID, err := layout.writeLayer(diskPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write the layer of the disk")
+	}
+	configData, err := json.Marshal(ocispec.Image{
+		Architecture: arch,
+		OS:           "linux",
+		RootFS:       ocispec.RootFS{Type: "layers", DiffIDs: []digest.Digest{diffID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	configDescriptor, err := layout.writeBlob(ocispec.MediaTypeImageConfig, configData)
+	if err != nil {
+		return nil, err
+	}
+	manifestData, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    configDescriptor,
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		return nil, err
+	}
+	layout.Manifest, err = layout.writeBlob(ocispec.MediaTypeImageManifest, manifestData)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := layout.Manifest
+	ref.Annotations = map[string]string{ocispec.AnnotationRefName: tag}
+	indexData, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{ref},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, indexFile), indexData, 0644); err != nil {
+		return nil, err
+	}
+	layoutData, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layoutData, 0644); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// writeBlob writes the data as a blob of the layout.
+func (l *Layout) writeBlob(mediaType string, data []byte) (ocispec.Descriptor, error) {
+	descriptor := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	return descriptor, ioutil.WriteFile(l.blobPath(descriptor.Digest), data, 0644)
+}
+
+// writeLayer writes the gzipped tar layer holding the disk in the disk directory, readable by
+// qemu, and returns its descriptor and the digest of the uncompressed tar.
+func (l *Layout) writeLayer(diskPath string) (ocispec.Descriptor, digest.Digest, error) {
+	disk, err := os.Open(diskPath)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer disk.Close()
+	info, err := disk.Stat()
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Join(l.Dir, blobsDirectory), "layer-")
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	compressedDigester := digest.Canonical.Digester()
+	compressed := &countingWriter{writer: io.MultiWriter(tmp, compressedDigester.Hash())}
+	gz := gzip.NewWriter(compressed)
+	uncompressedDigester := digest.Canonical.Digester()
+	tw := tar.NewWriter(io.MultiWriter(gz, uncompressedDigester.Hash()))
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     diskDirectory + "/",
+		Mode:     0555,
+		Uid:      qemuUID,
+		Gid:      qemuUID,
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path.Join(diskDirectory, filepath.Base(diskPath)),
+		Size:     info.Size(),
+		Mode:     0440,
+		Uid:      qemuUID,
+		Gid:      qemuUID,
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if _, err := io.Copy(tw, disk); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := tw.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	descriptor := ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageLayerGzip,
+		Digest:      compressedDigester.Digest(),
+		Size:        compressed.count,
+		Annotations: map[string]string{ocispec.AnnotationTitle: filepath.Base(diskPath)},
+	}
+	if err := os.Rename(tmp.Name(), l.blobPath(descriptor.Digest)); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	return descriptor, uncompressedDigester.Digest(), nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,42 @@
+package rhcos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// LiveISO fetches the URL of the Red Hat Enterprise Linux CoreOS live ISO.
+func LiveISO(ctx context.Context, arch types.Architecture) (string, error) {
+	meta, err := fetchRHCOSBuild(ctx, arch)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch RHCOS metadata")
+	}
+	if meta.Images.LiveISO.Path == "" {
+		return "", errors.Errorf("no live ISO in the RHCOS build for %s", arch)
+	}
+
+	base, err := url.Parse(meta.BaseURI)
+	if err != nil {
+		return "", err
+	}
+
+	relISO, err := url.Parse(meta.Images.LiveISO.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// Attach sha256 checksum to the URL, the ISO is not compressed
+	baseURL := base.ResolveReference(relISO).String() + "?sha256=" + meta.Images.LiveISO.SHA256
+
+	// Check that we have generated a valid URL
+	_, err = url.ParseRequestURI(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	return baseURL, nil
+}
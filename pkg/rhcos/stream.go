@@ -0,0 +1,296 @@
+package rhcos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/none"
+	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/ovirt"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// Stream is the RHCOS metadata embedded in the installer, in the layout of
+// the CoreOS stream metadata format.
+type Stream struct {
+	Stream        string                         `json:"stream"`
+	Architectures map[string]*StreamArchitecture `json:"architectures"`
+}
+
+// StreamArchitecture holds the artifacts and cloud images for an architecture.
+type StreamArchitecture struct {
+	Artifacts map[string]*StreamPlatformArtifacts `json:"artifacts,omitempty"`
+	Images    *StreamImages                       `json:"images,omitempty"`
+}
+
+// StreamPlatformArtifacts holds the downloadable artifacts for a platform,
+// indexed by format (e.g. qcow2.gz) and then by kind (e.g. disk).
+type StreamPlatformArtifacts struct {
+	Release string                                `json:"release"`
+	Formats map[string]map[string]*StreamArtifact `json:"formats"`
+}
+
+// StreamArtifact is a single downloadable file.
+type StreamArtifact struct {
+	Location           string `json:"location"`
+	SHA256             string `json:"sha256"`
+	UncompressedSHA256 string `json:"uncompressed-sha256,omitempty"`
+}
+
+// StreamImages holds the images already uploaded to clouds.
+type StreamImages struct {
+	AWS   *StreamAWSImages  `json:"aws,omitempty"`
+	Azure *StreamAzureImage `json:"azure,omitempty"`
+	GCP   *StreamGCPImage   `json:"gcp,omitempty"`
+}
+
+// StreamAWSImages holds the AMIs per region.
+type StreamAWSImages struct {
+	Regions map[string]*StreamAWSRegionImage `json:"regions"`
+}
+
+// StreamAWSRegionImage is the AMI in a region.
+type StreamAWSRegionImage struct {
+	Release string `json:"release"`
+	Image   string `json:"image"`
+}
+
+// StreamAzureImage is the public storage blob holding the Azure VHD.
+type StreamAzureImage struct {
+	Release string `json:"release"`
+	URL     string `json:"url"`
+}
+
+// StreamGCPImage is the public GCP image.
+type StreamGCPImage struct {
+	Release string `json:"release"`
+	Project string `json:"project"`
+	Name    string `json:"name"`
+}
+
+// streamArchitectures maps the installer architectures to the names used by the stream metadata.
+var streamArchitectures = map[types.Architecture]string{
+	types.ArchitectureAMD64:   "x86_64",
+	types.ArchitecturePPC64LE: "ppc64le",
+	types.ArchitectureS390X:   "s390x",
+}
+
+// platformArtifacts maps the installer platforms to the stream artifacts they consume.
+var platformArtifacts = map[string][]string{
+	aws.Name:       {"aws"},
+	azure.Name:     {"azure"},
+	baremetal.Name: {"openstack", "qemu"},
+	gcp.Name:       {"gcp"},
+	kubevirt.Name:  {"openstack"},
+	libvirt.Name:   {"qemu"},
+	none.Name:      {"metal"},
+	openstack.Name: {"openstack"},
+	ovirt.Name:     {"openstack"},
+	vsphere.Name:   {"vmware"},
+}
+
+// StreamPlatforms returns the platforms accepted by FilteredStream.
+func StreamPlatforms() []string {
+	platforms := make([]string, 0, len(platformArtifacts))
+	for p := range platformArtifacts {
+		platforms = append(platforms, p)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// StreamArchitectures returns the architectures accepted by FilteredStream.
+func StreamArchitectures() []string {
+	archs := make([]string, 0, len(streamArchitectures))
+	for a := range streamArchitectures {
+		archs = append(archs, string(a))
+	}
+	sort.Strings(archs)
+	return archs
+}
+
+// streamMetadata is the subset of the build metadata used to generate the stream.
+type streamMetadata struct {
+	AMIs map[string]struct {
+		HVM string `json:"hvm"`
+	} `json:"amis"`
+	Azure struct {
+		URL string `json:"url"`
+	} `json:"azure"`
+	GCP struct {
+		Image   string `json:"image"`
+		Project string `json:"project"`
+	} `json:"gcp"`
+	BaseURI string `json:"baseURI"`
+	BuildID string `json:"buildid"`
+	Images  map[string]struct {
+		Path               string `json:"path"`
+		SHA256             string `json:"sha256"`
+		UncompressedSHA256 string `json:"uncompressed-sha256"`
+	} `json:"images"`
+}
+
+// FilteredStream returns the embedded RHCOS metadata as a stream. When platform
+// is set, only the artifacts and images used by the installer for that platform
+// are returned; when arch is set, only that architecture is returned.
+func FilteredStream(ctx context.Context, platform string, arch types.Architecture) (*Stream, error) {
+	return filteredStream(platform, arch, readRHCOSBuild)
+}
+
+// filteredStream returns the stream of the build metadata returned by readBuild for each
+// architecture, filtered as FilteredStream does.
+func filteredStream(platform string, arch types.Architecture, readBuild func(types.Architecture) ([]byte, error)) (*Stream, error) {
+	var artifacts []string
+	if platform != "" {
+		var ok bool
+		artifacts, ok = platformArtifacts[platform]
+		if !ok {
+			return nil, errors.Errorf("unsupported platform %q, must be one of %s", platform, strings.Join(StreamPlatforms(), ", "))
+		}
+	}
+
+	archs := []types.Architecture{types.ArchitectureAMD64, types.ArchitecturePPC64LE, types.ArchitectureS390X}
+	if arch != "" {
+		if _, ok := streamArchitectures[arch]; !ok {
+			return nil, errors.Errorf("unsupported architecture %q, must be one of %s", arch, strings.Join(StreamArchitectures(), ", "))
+		}
+		archs = []types.Architecture{arch}
+	}
+
+	stream := &Stream{
+		Stream:        "rhcos",
+		Architectures: map[string]*StreamArchitecture{},
+	}
+	for _, a := range archs {
+		body, err := readBuild(a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch RHCOS metadata for %s", a)
+		}
+		meta := &streamMetadata{}
+		if err := json.Unmarshal(body, meta); err != nil {
+			return nil, errors.Wrap(err, "failed to parse RHCOS build metadata")
+		}
+		streamArch, err := meta.toStream(streamArchitectures[a], platform, artifacts)
+		if err != nil {
+			return nil, err
+		}
+		if len(streamArch.Artifacts) == 0 && streamArch.Images == nil {
+			continue
+		}
+		stream.Architectures[streamArchitectures[a]] = streamArch
+	}
+	return stream, nil
+}
+
+func (m *streamMetadata) toStream(arch string, platform string, artifacts []string) (*StreamArchitecture, error) {
+	base, err := url.Parse(m.BaseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := func(name string) bool {
+		if platform == "" {
+			return true
+		}
+		for _, a := range artifacts {
+			if a == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := &StreamArchitecture{Artifacts: map[string]*StreamPlatformArtifacts{}}
+	for name, image := range m.Images {
+		platformName, format, kind := streamArtifactName(name, image.Path, arch)
+		if platformName == "" || !wanted(platformName) {
+			continue
+		}
+		rel, err := url.Parse(image.Path)
+		if err != nil {
+			return nil, err
+		}
+		artifacts, ok := result.Artifacts[platformName]
+		if !ok {
+			artifacts = &StreamPlatformArtifacts{
+				Release: m.BuildID,
+				Formats: map[string]map[string]*StreamArtifact{},
+			}
+			result.Artifacts[platformName] = artifacts
+		}
+		if artifacts.Formats[format] == nil {
+			artifacts.Formats[format] = map[string]*StreamArtifact{}
+		}
+		artifacts.Formats[format][kind] = &StreamArtifact{
+			Location:           base.ResolveReference(rel).String(),
+			SHA256:             image.SHA256,
+			UncompressedSHA256: image.UncompressedSHA256,
+		}
+	}
+	if len(result.Artifacts) == 0 {
+		result.Artifacts = nil
+	}
+
+	images := &StreamImages{}
+	if len(m.AMIs) > 0 && wanted("aws") {
+		images.AWS = &StreamAWSImages{Regions: map[string]*StreamAWSRegionImage{}}
+		for region, ami := range m.AMIs {
+			images.AWS.Regions[region] = &StreamAWSRegionImage{Release: m.BuildID, Image: ami.HVM}
+		}
+	}
+	if m.Azure.URL != "" && wanted("azure") {
+		images.Azure = &StreamAzureImage{Release: m.BuildID, URL: m.Azure.URL}
+	}
+	if m.GCP.Image != "" && wanted("gcp") {
+		images.GCP = &StreamGCPImage{Release: m.BuildID, Project: m.GCP.Project, Name: m.GCP.Image}
+	}
+	if images.AWS != nil || images.Azure != nil || images.GCP != nil {
+		result.Images = images
+	}
+	return result, nil
+}
+
+// streamArtifactName maps a build metadata image to the platform, format and
+// kind used in the stream metadata. The live images and the 4k metal image are
+// grouped under the metal platform, as in the CoreOS streams.
+func streamArtifactName(name string, path string, arch string) (platform string, format string, kind string) {
+	switch name {
+	case "ostree":
+		return "", "", ""
+	case "live-iso":
+		return "metal", "iso", "disk"
+	case "live-kernel":
+		return "metal", "pxe", "kernel"
+	case "live-initramfs":
+		return "metal", "pxe", "initramfs"
+	case "live-rootfs":
+		return "metal", "pxe", "rootfs"
+	}
+
+	// The format is what follows the architecture in the file name,
+	// e.g. rhcos-<version>-openstack.x86_64.qcow2.gz is qcow2.gz.
+	format = path
+	if i := strings.LastIndex(path, "."+arch+"."); i >= 0 {
+		format = path[i+len(arch)+2:]
+	}
+	switch name {
+	case "metal4k":
+		return "metal", fmt.Sprintf("4k.%s", format), "disk"
+	case "dasd":
+		return "metal", fmt.Sprintf("dasd.%s", format), "disk"
+	}
+	return name, format, "disk"
+}
@@ -0,0 +1,185 @@
+package rhcos
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// readTestBuild returns the build metadata of the testdata, which only has amd64.
+func readTestBuild(arch types.Architecture) ([]byte, error) {
+	if arch != types.ArchitectureAMD64 {
+		return nil, errInvalidArch
+	}
+	return ioutil.ReadFile(filepath.Join("testdata", "rhcos-amd64.json"))
+}
+
+func TestFilteredStream(t *testing.T) {
+	cases := []struct {
+		name      string
+		platform  string
+		arch      types.Architecture
+		artifacts []string
+		formats   map[string][]string
+		aws       []string
+		azure     bool
+		gcp       bool
+		err       string
+	}{
+		{
+			name:      "all platforms",
+			arch:      types.ArchitectureAMD64,
+			artifacts: []string{"aws", "metal", "openstack", "qemu", "vmware"},
+			formats: map[string][]string{
+				"aws":       {"vmdk.gz"},
+				"metal":     {"4k.raw.gz", "iso", "pxe", "raw.gz"},
+				"openstack": {"qcow2.gz"},
+				"qemu":      {"qcow2.gz"},
+				"vmware":    {"ova"},
+			},
+			aws:   []string{"eu-west-1", "us-east-1"},
+			azure: true,
+			gcp:   true,
+		},
+		{
+			name:      "aws",
+			platform:  "aws",
+			arch:      types.ArchitectureAMD64,
+			artifacts: []string{"aws"},
+			formats:   map[string][]string{"aws": {"vmdk.gz"}},
+			aws:       []string{"eu-west-1", "us-east-1"},
+		},
+		{
+			name:     "gcp",
+			platform: "gcp",
+			arch:     types.ArchitectureAMD64,
+			gcp:      true,
+		},
+		{
+			name:      "baremetal",
+			platform:  "baremetal",
+			arch:      types.ArchitectureAMD64,
+			artifacts: []string{"openstack", "qemu"},
+			formats:   map[string][]string{"openstack": {"qcow2.gz"}, "qemu": {"qcow2.gz"}},
+		},
+		{
+			name:      "none",
+			platform:  "none",
+			arch:      types.ArchitectureAMD64,
+			artifacts: []string{"metal"},
+			formats:   map[string][]string{"metal": {"4k.raw.gz", "iso", "pxe", "raw.gz"}},
+		},
+		{
+			name:     "unsupported platform",
+			platform: "unknown",
+			err:      `^unsupported platform "unknown", must be one of .*$`,
+		},
+		{
+			name: "unsupported architecture",
+			arch: types.Architecture("arm64"),
+			err:  `^unsupported architecture "arm64", must be one of amd64, ppc64le, s390x$`,
+		},
+		{
+			name:     "missing architecture",
+			platform: "kubevirt",
+			err:      `^failed to fetch RHCOS metadata for ppc64le: no build metadata for given architecture$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := filteredStream(tc.platform, tc.arch, readTestBuild)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "rhcos", stream.Stream)
+			arch := stream.Architectures["x86_64"]
+			if !assert.NotNil(t, arch) {
+				return
+			}
+
+			var artifacts []string
+			formats := map[string][]string{}
+			for platform, platformArtifacts := range arch.Artifacts {
+				artifacts = append(artifacts, platform)
+				assert.Equal(t, "47.82.202010211043-0", platformArtifacts.Release)
+				for format := range platformArtifacts.Formats {
+					formats[platform] = append(formats[platform], format)
+				}
+				sort.Strings(formats[platform])
+			}
+			sort.Strings(artifacts)
+			assert.Equal(t, tc.artifacts, artifacts, "artifacts")
+			if len(tc.formats) > 0 {
+				assert.Equal(t, tc.formats, formats, "formats")
+			}
+
+			var aws []string
+			var azure, gcp bool
+			if arch.Images != nil {
+				if arch.Images.AWS != nil {
+					for region := range arch.Images.AWS.Regions {
+						aws = append(aws, region)
+					}
+					sort.Strings(aws)
+				}
+				azure = arch.Images.Azure != nil
+				gcp = arch.Images.GCP != nil
+			}
+			assert.Equal(t, tc.aws, aws, "AWS regions")
+			assert.Equal(t, tc.azure, azure, "Azure image")
+			assert.Equal(t, tc.gcp, gcp, "GCP image")
+		})
+	}
+}
+
+func TestFilteredStreamKubevirt(t *testing.T) {
+	stream, err := filteredStream("kubevirt", types.ArchitectureAMD64, readTestBuild)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expected, err := ioutil.ReadFile(filepath.Join("testdata", "stream-kubevirt.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.Marshal(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestStreamArtifactName(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		platform string
+		format   string
+		kind     string
+	}{
+		{name: "ostree", path: "rhcos-ostree.x86_64.tar"},
+		{name: "live-iso", path: "rhcos-live.x86_64.iso", platform: "metal", format: "iso", kind: "disk"},
+		{name: "live-kernel", path: "rhcos-live-kernel-x86_64", platform: "metal", format: "pxe", kind: "kernel"},
+		{name: "live-initramfs", path: "rhcos-live-initramfs.x86_64.img", platform: "metal", format: "pxe", kind: "initramfs"},
+		{name: "live-rootfs", path: "rhcos-live-rootfs.x86_64.img", platform: "metal", format: "pxe", kind: "rootfs"},
+		{name: "metal4k", path: "rhcos-metal4k.x86_64.raw.gz", platform: "metal", format: "4k.raw.gz", kind: "disk"},
+		{name: "dasd", path: "rhcos-dasd.x86_64.raw.gz", platform: "metal", format: "dasd.raw.gz", kind: "disk"},
+		{name: "openstack", path: "rhcos-openstack.x86_64.qcow2.gz", platform: "openstack", format: "qcow2.gz", kind: "disk"},
+		{name: "vmware", path: "rhcos-vmware.x86_64.ova", platform: "vmware", format: "ova", kind: "disk"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			platform, format, kind := streamArtifactName(tc.name, tc.path, "x86_64")
+			assert.Equal(t, tc.platform, platform)
+			assert.Equal(t, tc.format, format)
+			assert.Equal(t, tc.kind, kind)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Package scale applies the changes of the compute machine pools of an install-config to the
+// MachineSets of the running cluster.
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/installer/pkg/asset/machines/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/validation"
+)
+
+const machineSetNamespace = "openshift-machine-api"
+
+var machineSetResource = schema.GroupVersionResource{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machinesets"}
+
+// Change is the change of the MachineSet of a compute machine pool.
+type Change struct {
+	// Pool is the name of the machine pool.
+	Pool string
+	// MachineSet is the MachineSet of the cluster, with the changes applied.
+	MachineSet *machineapi.MachineSet
+	// Diffs describe the changed fields, e.g. "replicas: 2 -> 3".
+	Diffs []string
+
+	// patch is the merge patch of the MachineSet, setting only the changed fields for the
+	// other ones to be kept as the machine-api controllers may have changed them since.
+	patch map[string]interface{}
+}
+
+// ListMachineSets returns the MachineSets of the cluster with the infra ID.
+func ListMachineSets(ctx context.Context, client dynamic.Interface, infraID string) ([]*machineapi.MachineSet, error) {
+	list, err := client.Resource(machineSetResource).Namespace(machineSetNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("machine.openshift.io/cluster-api-cluster=%s", infraID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	machineSets := make([]*machineapi.MachineSet, 0, len(list.Items))
+	for _, item := range list.Items {
+		data, err := item.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		machineSet := &machineapi.MachineSet{}
+		if err := json.Unmarshal(data, machineSet); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal MachineSet %s", item.GetName())
+		}
+		machineSets = append(machineSets, machineSet)
+	}
+	return machineSets, nil
+}
+
+// Plan returns the changes to apply to the MachineSets of the cluster for them to match the
+// compute machine pools of the install-config: their replicas, and the CPU, memory and storage
+// of the machines they create. The pools are validated first. Adding machine pools is not
+// supported, and the MachineSets without a pool are left as they are.
+func Plan(config *types.InstallConfig, infraID string, machineSets []*machineapi.MachineSet) ([]Change, error) {
+	if platform := config.Platform.Name(); platform != kubevirttypes.Name {
+		return nil, errors.Errorf("scaling is not supported for platform %q", platform)
+	}
+
+	var allErrs field.ErrorList
+	for i := range config.Compute {
+		allErrs = append(allErrs, validation.ValidateMachinePool(&config.Platform, &config.Compute[i], field.NewPath("compute").Index(i))...)
+	}
+	if err := allErrs.ToAggregate(); err != nil {
+		return nil, errors.Wrap(err, "invalid compute machine pools")
+	}
+
+	byName := make(map[string]*machineapi.MachineSet, len(machineSets))
+	for _, machineSet := range machineSets {
+		byName[machineSet.Name] = machineSet
+	}
+
+	var changes []Change
+	for _, pool := range config.Compute {
+		mpool := kubevirttypes.MachinePool{}
+		mpool.Set(pool.Platform.Kubevirt)
+		pool.Platform.Kubevirt = &mpool
+
+		desired, err := kubevirt.MachineSets(infraID, config, &pool, "", "worker", "worker-user-data-managed")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the MachineSets of machine pool %s", pool.Name)
+		}
+		for _, want := range desired {
+			have, ok := byName[want.Name]
+			if !ok {
+				return nil, errors.Errorf("machine pool %s has no MachineSet %s in the cluster, adding machine pools is not supported", pool.Name, want.Name)
+			}
+			change, err := diff(have, want)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to compare MachineSet %s", have.Name)
+			}
+			if len(change.Diffs) > 0 {
+				change.Pool = pool.Name
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// diff returns the change of the MachineSet of the cluster to match the desired one.
+func diff(have *machineapi.MachineSet, want *machineapi.MachineSet) (Change, error) {
+	updated := have.DeepCopy()
+	change := Change{MachineSet: updated}
+	spec := map[string]interface{}{}
+	change.patch = map[string]interface{}{"spec": spec}
+
+	haveReplicas, wantReplicas := int32(0), int32(0)
+	if have.Spec.Replicas != nil {
+		haveReplicas = *have.Spec.Replicas
+	}
+	if want.Spec.Replicas != nil {
+		wantReplicas = *want.Spec.Replicas
+	}
+	if haveReplicas != wantReplicas {
+		updated.Spec.Replicas = &wantReplicas
+		spec["replicas"] = wantReplicas
+		change.Diffs = append(change.Diffs, fmt.Sprintf("replicas: %d -> %d", haveReplicas, wantReplicas))
+	}
+
+	wantSpec, ok := want.Spec.Template.Spec.ProviderSpec.Value.Object.(*kubevirtprovider.KubevirtMachineProviderSpec)
+	if !ok {
+		return change, errors.New("unexpected provider spec of the desired MachineSet")
+	}
+	if have.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return change, errors.New("the MachineSet has no provider spec")
+	}
+	haveSpec := &kubevirtprovider.KubevirtMachineProviderSpec{}
+	if err := json.Unmarshal(have.Spec.Template.Spec.ProviderSpec.Value.Raw, haveSpec); err != nil {
+		return change, errors.Wrap(err, "failed to unmarshal the provider spec")
+	}
+
+	// sizeFields are the changed fields of the provider spec, by their JSON name
+	sizeFields := map[string]interface{}{}
+	if haveSpec.RequestedCPU != wantSpec.RequestedCPU {
+		change.Diffs = append(change.Diffs, fmt.Sprintf("cpu: %d -> %d", haveSpec.RequestedCPU, wantSpec.RequestedCPU))
+		sizeFields["requestedCPU"] = wantSpec.RequestedCPU
+	}
+	if haveSpec.RequestedMemory != wantSpec.RequestedMemory {
+		change.Diffs = append(change.Diffs, fmt.Sprintf("memory: %s -> %s", haveSpec.RequestedMemory, wantSpec.RequestedMemory))
+		sizeFields["requestedMemory"] = wantSpec.RequestedMemory
+	}
+	if haveSpec.RequestedStorage != wantSpec.RequestedStorage {
+		change.Diffs = append(change.Diffs, fmt.Sprintf("storage: %s -> %s", haveSpec.RequestedStorage, wantSpec.RequestedStorage))
+		sizeFields["requestedStorage"] = wantSpec.RequestedStorage
+	}
+	if len(sizeFields) > 0 {
+		// Only the changed fields are written back, the other ones are kept as in the cluster
+		raw, err := mergeProviderSpec(have.Spec.Template.Spec.ProviderSpec.Value.Raw, sizeFields)
+		if err != nil {
+			return change, err
+		}
+		updated.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: raw}
+		spec["template"] = map[string]interface{}{
+			"spec": map[string]interface{}{
+				"providerSpec": map[string]interface{}{
+					"value": sizeFields,
+				},
+			},
+		}
+	}
+	return change, nil
+}
+
+// mergeProviderSpec returns the raw provider spec with the fields set.
+func mergeProviderSpec(raw []byte, fields map[string]interface{}) ([]byte, error) {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the provider spec")
+	}
+	for name, value := range fields {
+		object[name] = value
+	}
+	return json.Marshal(object)
+}
+
+// Apply patches the MachineSets of the cluster with the changes. Only the changed fields are
+// patched, not to overwrite the changes made to the MachineSets by the machine-api controllers.
+func Apply(ctx context.Context, client dynamic.Interface, changes []Change) error {
+	for _, change := range changes {
+		patch, err := json.Marshal(change.patch)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Resource(machineSetResource).Namespace(change.MachineSet.Namespace).Patch(ctx, change.MachineSet.Name, apitypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update MachineSet %s", change.MachineSet.Name)
+		}
+	}
+	return nil
+}
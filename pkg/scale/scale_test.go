@@ -0,0 +1,191 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubevirtprovider "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/asset/machines/kubevirt"
+	"github.com/openshift/installer/pkg/types"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/none"
+)
+
+func installConfig() *types.InstallConfig {
+	return &types.InstallConfig{
+		Platform: types.Platform{
+			Kubevirt: &kubevirttypes.Platform{
+				Namespace:    "namespace",
+				StorageClass: "storage-class",
+				NetworkName:  "network",
+			},
+		},
+		Compute: []types.MachinePool{{
+			Name:           "worker",
+			Replicas:       pointer.Int64Ptr(2),
+			Hyperthreading: types.HyperthreadingEnabled,
+			Architecture:   types.ArchitectureAMD64,
+			Platform: types.MachinePoolPlatform{
+				Kubevirt: &kubevirttypes.MachinePool{
+					CPU:         4,
+					Memory:      "10G",
+					StorageSize: "120Gi",
+				},
+			},
+		}},
+	}
+}
+
+// clusterMachineSets returns the MachineSets of the cluster created with the install-config,
+// with their provider specs raw as when listed from the cluster.
+func clusterMachineSets(t *testing.T, config *types.InstallConfig) []*machineapi.MachineSet {
+	var machineSets []*machineapi.MachineSet
+	for i := range config.Compute {
+		sets, err := kubevirt.MachineSets("infra-id", config, &config.Compute[i], "image", "worker", "worker-user-data-managed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, set := range sets {
+			data, err := json.Marshal(set)
+			if err != nil {
+				t.Fatal(err)
+			}
+			machineSet := &machineapi.MachineSet{}
+			if err := json.Unmarshal(data, machineSet); err != nil {
+				t.Fatal(err)
+			}
+			machineSets = append(machineSets, machineSet)
+		}
+	}
+	return machineSets
+}
+
+func TestPlan(t *testing.T) {
+	cases := []struct {
+		name          string
+		edit          func(ic *types.InstallConfig)
+		expectedDiffs []string
+		expectedError string
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name:          "replicas",
+			edit:          func(ic *types.InstallConfig) { ic.Compute[0].Replicas = pointer.Int64Ptr(5) },
+			expectedDiffs: []string{"replicas: 2 -> 5"},
+		},
+		{
+			name: "size",
+			edit: func(ic *types.InstallConfig) {
+				ic.Compute[0].Platform.Kubevirt.CPU = 8
+				ic.Compute[0].Platform.Kubevirt.Memory = "16G"
+			},
+			expectedDiffs: []string{"cpu: 4 -> 8", "memory: 10G -> 16G"},
+		},
+		{
+			name:          "invalid replicas",
+			edit:          func(ic *types.InstallConfig) { ic.Compute[0].Replicas = pointer.Int64Ptr(-1) },
+			expectedError: `invalid compute machine pools: compute[0].replicas: Invalid value: -1: number of replicas must not be negative`,
+		},
+		{
+			name: "new machine pool",
+			edit: func(ic *types.InstallConfig) {
+				pool := ic.Compute[0]
+				pool.Name = "infra"
+				ic.Compute = append(ic.Compute, pool)
+			},
+			expectedError: `machine pool infra has no MachineSet infra-id-infra-0 in the cluster, adding machine pools is not supported`,
+		},
+		{
+			name: "unsupported platform",
+			edit: func(ic *types.InstallConfig) {
+				ic.Platform.Kubevirt = nil
+				ic.Platform.None = &none.Platform{}
+			},
+			expectedError: `scaling is not supported for platform "none"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machineSets := clusterMachineSets(t, installConfig())
+			config := installConfig()
+			if tc.edit != nil {
+				tc.edit(config)
+			}
+
+			changes, err := Plan(config, "infra-id", machineSets)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			if len(tc.expectedDiffs) == 0 {
+				assert.Empty(t, changes)
+				return
+			}
+			if !assert.Len(t, changes, 1) {
+				return
+			}
+			change := changes[0]
+			assert.Equal(t, "worker", change.Pool)
+			assert.Equal(t, tc.expectedDiffs, change.Diffs)
+			assert.Equal(t, int32(*config.Compute[0].Replicas), *change.MachineSet.Spec.Replicas)
+
+			spec := &kubevirtprovider.KubevirtMachineProviderSpec{}
+			if err := json.Unmarshal(change.MachineSet.Spec.Template.Spec.ProviderSpec.Value.Raw, spec); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, config.Compute[0].Platform.Kubevirt.CPU, spec.RequestedCPU)
+			assert.Equal(t, config.Compute[0].Platform.Kubevirt.Memory, spec.RequestedMemory)
+			// The fields of the provider spec which are not scaled are kept as in the cluster
+			assert.Equal(t, "infra-id-source-pvc", spec.SourcePvcName)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	machineSets := clusterMachineSets(t, installConfig())
+	config := installConfig()
+	config.Compute[0].Replicas = pointer.Int64Ptr(3)
+	config.Compute[0].Platform.Kubevirt.Memory = "16G"
+	changes, err := Plan(config, "infra-id", machineSets)
+	if !assert.NoError(t, err) || !assert.Len(t, changes, 1) {
+		return
+	}
+
+	var patches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/apis/machine.openshift.io/v1beta1/namespaces/openshift-machine-api/machinesets/infra-id-worker-0", r.URL.Path)
+		assert.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		patches = append(patches, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion": "machine.openshift.io/v1beta1", "kind": "MachineSet", "metadata": {"name": "infra-id-worker-0"}}`))
+	}))
+	defer server.Close()
+	client, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, Apply(context.Background(), client, changes))
+	// Only the changed fields are patched, the CPU and storage of the provider spec are kept
+	if assert.Len(t, patches, 1) {
+		assert.JSONEq(t, `{"spec": {"replicas": 3, "template": {"spec": {"providerSpec": {"value": {"requestedMemory": "16G"}}}}}}`, patches[0])
+	}
+}
@@ -0,0 +1,35 @@
+package secretstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// fileStore keeps the secrets as files in a directory.
+type fileStore struct {
+	directory string
+	mode      os.FileMode
+}
+
+func (s *fileStore) Write(name string, data []byte) error {
+	path := s.Location(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return errors.Wrap(err, "failed to create dir")
+	}
+	if err := ioutil.WriteFile(path, data, s.mode); err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	// WriteFile does not change the mode of existing files
+	return os.Chmod(path, s.mode)
+}
+
+func (s *fileStore) Read(name string) ([]byte, error) {
+	return ioutil.ReadFile(s.Location(name))
+}
+
+func (s *fileStore) Location(name string) string {
+	return filepath.Join(s.directory, name)
+}
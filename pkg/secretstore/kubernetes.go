@@ -0,0 +1,85 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubernetesSecretStore keeps the secrets as the data of a single Secret, in the
+// cluster of the kubeconfig (e.g. the KubeVirt infra cluster).
+type kubernetesSecretStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+func newKubernetesSecretStore(kubeconfig Kubeconfig, namespace string, name string) (*kubernetesSecretStore, error) {
+	restConfig, err := restConfigFor(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading kubeconfig for the secret store")
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a Kubernetes client for the secret store")
+	}
+	return &kubernetesSecretStore{client: client, namespace: namespace, name: name}, nil
+}
+
+// restConfigFor returns the client configuration of the context of the kubeconfig.
+func restConfigFor(kubeconfig Kubeconfig) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig.Path
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeconfig.Context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+func (s *kubernetesSecretStore) Write(name string, data []byte) error {
+	ctx := context.Background()
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+	secret, err := secrets.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{name: data},
+		}
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return errors.Wrapf(err, "creating secret %s/%s", s.namespace, s.name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "getting secret %s/%s", s.namespace, s.name)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[name] = data
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return errors.Wrapf(err, "updating secret %s/%s", s.namespace, s.name)
+}
+
+func (s *kubernetesSecretStore) Read(name string) ([]byte, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting secret %s/%s", s.namespace, s.name)
+	}
+	data, ok := secret.Data[name]
+	if !ok {
+		return nil, errors.Errorf("secret %s/%s has no %s key", s.namespace, s.name, name)
+	}
+	return data, nil
+}
+
+func (s *kubernetesSecretStore) Location(name string) string {
+	return fmt.Sprintf("key %s of secret %s/%s", name, s.namespace, s.name)
+}
@@ -0,0 +1,79 @@
+// Package secretstore stores the credentials generated by the installer (the
+// kubeadmin password and the admin kubeconfig) and reads them back.
+package secretstore
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// KubeconfigName is the name of the admin kubeconfig secret.
+	KubeconfigName = "kubeconfig"
+	// KubeadminPasswordName is the name of the kubeadmin password secret.
+	KubeadminPasswordName = "kubeadmin-password"
+)
+
+var (
+	// Names are the names of all the secrets which are kept in the store.
+	Names = []string{KubeconfigName, KubeadminPasswordName}
+)
+
+// Store writes and reads back the installer credentials.
+type Store interface {
+	// Write stores the secret data under the given name.
+	Write(name string, data []byte) error
+	// Read returns the secret data stored under the given name.
+	Read(name string) ([]byte, error)
+	// Location describes where the secret with the given name is stored.
+	Location(name string) string
+}
+
+// Kubeconfig selects the kubeconfig of the cluster the kubernetes-secret store keeps the Secret
+// in, e.g. the kubeconfig of the KubeVirt infra cluster. The current KUBECONFIG and its current
+// context are used when they are empty.
+type Kubeconfig struct {
+	Path    string
+	Context string
+}
+
+// New returns the store described by spec, which is one of:
+//   file (the default): plaintext files in the auth directory of the asset directory
+//   secure-file: like file, but readable only by the owner (0600)
+//   kubernetes-secret:<namespace>/<name>: a Secret in the cluster of the kubeconfig
+//   vault:<path>: a Vault KV version 2 secret at the API path (e.g. secret/data/clusters/mycluster),
+//     using VAULT_ADDR and VAULT_TOKEN
+func New(spec string, directory string, kubeconfig Kubeconfig) (Store, error) {
+	kind, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, arg = spec[:i], spec[i+1:]
+	}
+	authDir := filepath.Join(directory, "auth")
+	switch kind {
+	case "", "file":
+		return &fileStore{directory: authDir, mode: 0640}, nil
+	case "secure-file":
+		return &fileStore{directory: authDir, mode: 0600}, nil
+	case "kubernetes-secret":
+		parts := strings.Split(arg, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid kubernetes-secret store %q, expected kubernetes-secret:<namespace>/<name>", spec)
+		}
+		return newKubernetesSecretStore(kubeconfig, parts[0], parts[1])
+	case "vault":
+		if arg == "" {
+			return nil, errors.Errorf("invalid vault store %q, expected vault:<path>", spec)
+		}
+		return newVaultStore(arg)
+	default:
+		return nil, errors.Errorf("unknown secret store %q", spec)
+	}
+}
+
+// IsFile returns true if the store keeps the secrets as files in the asset directory.
+func IsFile(s Store) bool {
+	_, ok := s.(*fileStore)
+	return ok
+}
@@ -0,0 +1,142 @@
+package secretstore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		spec string
+		mode os.FileMode
+	}{
+		{spec: "file", mode: 0640},
+		{spec: "secure-file", mode: 0600},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			store, err := New(tc.spec, dir, Kubeconfig{})
+			assert.NoError(t, err)
+			assert.True(t, IsFile(store))
+			assert.NoError(t, store.Write(KubeadminPasswordName, []byte("password")))
+
+			info, err := os.Stat(filepath.Join(dir, "auth", KubeadminPasswordName))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.mode, info.Mode().Perm())
+
+			data, err := store.Read(KubeadminPasswordName)
+			assert.NoError(t, err)
+			assert.Equal(t, "password", string(data))
+		})
+	}
+}
+
+func TestVaultStore(t *testing.T) {
+	var stored *vaultData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/cluster", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": stored})
+		case http.MethodPost:
+			stored = &vaultData{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(stored))
+		}
+	}))
+	defer server.Close()
+
+	os.Setenv("VAULT_ADDR", server.URL)
+	os.Setenv("VAULT_TOKEN", "token")
+	defer os.Unsetenv("VAULT_ADDR")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	store, err := New("vault:secret/data/cluster", "", Kubeconfig{})
+	assert.NoError(t, err)
+	assert.False(t, IsFile(store))
+	assert.NoError(t, store.Write(KubeconfigName, []byte("kubeconfig")))
+	assert.NoError(t, store.Write(KubeadminPasswordName, []byte("password")))
+
+	data, err := store.Read(KubeconfigName)
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(data))
+	data, err = store.Read(KubeadminPasswordName)
+	assert.NoError(t, err)
+	assert.Equal(t, "password", string(data))
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, spec := range []string{"unknown", "kubernetes-secret:namespace", "vault:"} {
+		_, err := New(spec, "", Kubeconfig{})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestKubernetesSecretStoreConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	err = ioutil.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: tenant
+  cluster:
+    server: https://tenant.example.com:6443
+- name: infra
+  cluster:
+    server: https://infra.example.com:6443
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: tenant
+  context:
+    cluster: tenant
+    user: admin
+- name: infra
+  context:
+    cluster: infra
+    user: admin
+current-context: tenant
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		context string
+		host    string
+	}{
+		{context: "", host: "https://tenant.example.com:6443"},
+		{context: "infra", host: "https://infra.example.com:6443"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.context, func(t *testing.T) {
+			config, err := restConfigFor(Kubeconfig{Path: kubeconfig, Context: tc.context})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.host, config.Host)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package secretstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// vaultStore keeps the secrets in a Vault KV version 2 secret. The values are
+// base64 encoded, since the KV engine only stores strings.
+type vaultStore struct {
+	address string
+	token   string
+	path    string
+	client  *http.Client
+}
+
+type vaultData struct {
+	Data map[string]string `json:"data"`
+}
+
+func newVaultStore(path string) (*vaultStore, error) {
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		return nil, errors.New("VAULT_ADDR must be set to use the vault secret store")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.New("VAULT_TOKEN must be set to use the vault secret store")
+	}
+	return &vaultStore{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		path:    strings.Trim(path, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *vaultStore) Write(name string, data []byte) error {
+	existing, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	existing[name] = base64.StdEncoding.EncodeToString(data)
+	body, err := json.Marshal(vaultData{Data: existing})
+	if err != nil {
+		return err
+	}
+	_, err = s.do(http.MethodPost, body)
+	return err
+}
+
+func (s *vaultStore) Read(name string) ([]byte, error) {
+	existing, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := existing[name]
+	if !ok {
+		return nil, errors.Errorf("vault secret %s has no %s key", s.path, name)
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+func (s *vaultStore) Location(name string) string {
+	return fmt.Sprintf("key %s of vault secret %s", name, s.path)
+}
+
+// readAll returns the current data of the secret, which is empty if it does not exist yet.
+func (s *vaultStore) readAll() (map[string]string, error) {
+	body, err := s.do(http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	if body == nil {
+		return result, nil
+	}
+	var response struct {
+		Data vaultData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Wrapf(err, "parsing vault secret %s", s.path)
+	}
+	for k, v := range response.Data.Data {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// do sends a request for the secret path, returning a nil body if the secret was not found.
+func (s *vaultStore) do(method string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", s.address, s.path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "accessing vault secret %s", s.path)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("accessing vault secret %s: %s: %s", s.path, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
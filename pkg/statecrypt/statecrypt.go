@@ -0,0 +1,156 @@
+// Package statecrypt encrypts the installer state files at rest (the asset state
+// file and the Terraform state), since they embed credentials and certificates.
+package statecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PassphraseEnvVar is the environment variable holding the encryption passphrase.
+	PassphraseEnvVar = "OPENSHIFT_INSTALL_STATE_PASSPHRASE"
+
+	saltSize   = 16
+	keySize    = 32
+	iterations = 100000
+)
+
+var (
+	header = []byte("OPENSHIFT-INSTALL-ENCRYPTED-V1\n")
+
+	passphrase []byte
+	encrypt    bool
+)
+
+// Configure sets the passphrase used to decrypt the state files and whether
+// the state files are encrypted when written.
+func Configure(key []byte, encryptOnWrite bool) error {
+	if encryptOnWrite && len(key) == 0 {
+		return errors.Errorf("encrypting the state requires a passphrase, set %s or provide a key file", PassphraseEnvVar)
+	}
+	passphrase = key
+	encrypt = encryptOnWrite
+	return nil
+}
+
+// Enabled returns true if the state files are encrypted when written.
+func Enabled() bool {
+	return encrypt
+}
+
+// IsEncrypted returns true if the data was encrypted by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Encrypt encrypts the data with AES-256-GCM, using a key derived from the passphrase.
+func Encrypt(data []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, header...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, header), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt. Data which is not encrypted is returned as is.
+func Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.Errorf("the state is encrypted, set %s or provide a key file to decrypt it", PassphraseEnvVar)
+	}
+	data = data[len(header):]
+	if len(data) < saltSize {
+		return nil, errors.New("encrypted state is truncated")
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+	gcm, err := newGCM(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted state is truncated")
+	}
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, data, header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt the state, the passphrase may be wrong")
+	}
+	return plain, nil
+}
+
+// ReadFile reads the file, decrypting it if needed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(data)
+}
+
+// WriteFile writes the data to the file, encrypting it if the encryption is enabled.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if encrypt {
+		var err error
+		if data, err = Encrypt(data); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path, data, perm)
+}
+
+func newGCM(salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2 derives the key from the passphrase with PBKDF2-HMAC-SHA256 (RFC 8018).
+func pbkdf2(password, salt []byte) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keySize; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var counter [4]byte
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keySize]
+}
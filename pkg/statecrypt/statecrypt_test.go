@@ -0,0 +1,43 @@
+package statecrypt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer Configure(nil, false)
+
+	assert.Error(t, Configure(nil, true))
+	assert.NoError(t, Configure([]byte("passphrase"), true))
+
+	data := []byte(`{"some":"state"}`)
+	encrypted, err := Encrypt(data)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "state")
+
+	decrypted, err := Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	plain, err := Decrypt(data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, plain)
+
+	assert.NoError(t, Configure([]byte("wrong"), false))
+	_, err = Decrypt(encrypted)
+	assert.Error(t, err)
+
+	assert.NoError(t, Configure(nil, false))
+	_, err = Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestPBKDF2(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 of "password" and "salt" with 100000 iterations
+	key := pbkdf2([]byte("password"), []byte("salt"))
+	assert.Equal(t, "0394a2ede332c9a13eb82e9b24631604c31df978b4e2f0fbd2c549944f9d79a5", hex.EncodeToString(key))
+}
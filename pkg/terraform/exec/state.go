@@ -2,10 +2,11 @@ package exec
 
 import (
 	"bytes"
-	"os"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/statecrypt"
 )
 
 // ReadState reads the terraform state from file and returns the contents in bytes
@@ -13,13 +14,12 @@ import (
 // ReadState utilizes the terraform's internal wiring to upconvert versions of terraform state to return
 // the state it currently recognizes.
 func ReadState(file string) ([]byte, error) {
-	f, err := os.Open(file)
+	data, err := statecrypt.ReadFile(file)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to open %q", file)
 	}
-	defer f.Close()
 
-	sf, err := statefile.Read(f)
+	sf, err := statefile.Read(bytes.NewReader(data))
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to read statefile from %q", file)
 	}
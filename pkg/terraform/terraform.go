@@ -129,6 +129,10 @@ func unpackAndInit(dir string, platform string) (err error) {
 	return nil
 }
 
+// setupEmbeddedPlugins symlinks the known plugins to the installer executable in the
+// plugins directory of dir. The providers are built into the installer, which serves
+// them when executed under their names, so nothing is extracted and there is nothing
+// to cache across runs.
 func setupEmbeddedPlugins(dir string) error {
 	execPath, err := os.Executable()
 	if err != nil {
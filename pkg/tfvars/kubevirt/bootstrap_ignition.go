@@ -0,0 +1,71 @@
+package kubevirt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// bootstrapUserDataOverhead bounds the size of the bootstrap user data besides the data URL of
+// the embedded Ignition config: its version, merge directive and hostname file.
+const bootstrapUserDataOverhead = 1024
+
+// uploadBootstrapIgnition uploads the bootstrap Ignition config to the URL with a PUT
+// request, so that the bootstrap VM can fetch it instead of having it in its user data.
+func uploadBootstrapIgnition(client *http.Client, url string, bootstrapIgn string) error {
+	logrus.Debugf("Uploading the bootstrap Ignition config to %s", url)
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(bootstrapIgn))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the bootstrap Ignition config upload request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to upload the bootstrap Ignition config")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("failed to upload the bootstrap Ignition config to %s: %s", url, resp.Status)
+	}
+	logrus.Debugf("The bootstrap Ignition config was uploaded.")
+	return nil
+}
+
+// newUploadClient returns the HTTP client uploading the bootstrap Ignition config.
+func newUploadClient() *http.Client {
+	return &http.Client{Timeout: 2 * time.Minute}
+}
+
+// compressBootstrapIgnition returns whether the bootstrap Ignition config embedded in the user
+// data of the bootstrap VM is compressed with gzip, which it is when the user data would not fit
+// the Secret holding it otherwise. It fails when the user data doesn't fit the Secret even with
+// the config compressed.
+func compressBootstrapIgnition(bootstrapIgn string) (bool, error) {
+	size := len("data:text/plain;charset=utf-8;base64,") + base64.StdEncoding.EncodedLen(len(bootstrapIgn)) + bootstrapUserDataOverhead
+	if size <= corev1.MaxSecretSize {
+		return false, nil
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(bootstrapIgn)); err != nil {
+		return false, errors.Wrap(err, "failed to compress the bootstrap Ignition config")
+	}
+	if err := writer.Close(); err != nil {
+		return false, errors.Wrap(err, "failed to compress the bootstrap Ignition config")
+	}
+	compressedSize := len("data:;base64,") + base64.StdEncoding.EncodedLen(compressed.Len()) + bootstrapUserDataOverhead
+	if compressedSize > corev1.MaxSecretSize {
+		return false, errors.Errorf("the user data of the bootstrap VM is %d bytes with its Ignition config compressed, more than the %d bytes a Secret holds; set platform.kubevirt.bootstrapIgnitionURL for the bootstrap VM to fetch the config instead", compressedSize, corev1.MaxSecretSize)
+	}
+	logrus.Debugf("The user data of the bootstrap VM is %d bytes, more than the %d bytes a Secret holds, compressing its Ignition config to %d bytes", size, corev1.MaxSecretSize, compressedSize)
+	return true, nil
+}
@@ -0,0 +1,83 @@
+package kubevirt
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadBootstrapIgnition(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/ignition/bootstrap.ign", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		uploaded = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	ign := `{"ignition":{"version":"3.1.0"}}`
+	assert.NoError(t, uploadBootstrapIgnition(server.Client(), server.URL+"/ignition/bootstrap.ign", ign))
+	assert.Equal(t, ign, uploaded)
+}
+
+func TestUploadBootstrapIgnitionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	url := server.URL + "/ignition/bootstrap.ign"
+	assert.EqualError(t, uploadBootstrapIgnition(server.Client(), url, "{}"), "failed to upload the bootstrap Ignition config to "+url+": 403 Forbidden")
+}
+
+func TestCompressBootstrapIgnition(t *testing.T) {
+	random := make([]byte, 1024*1024)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	cases := []struct {
+		name          string
+		ignition      string
+		expectedGzip  bool
+		expectedError string
+	}{
+		{
+			name:     "small",
+			ignition: `{"ignition":{"version":"3.1.0"}}`,
+		},
+		{
+			name:         "too large uncompressed",
+			ignition:     `{"ignition":{"version":"3.1.0"},"storage":{"files":[` + strings.Repeat(`{"path":"/etc/motd","contents":{"source":"data:,hello"}},`, 20000) + `]}}`,
+			expectedGzip: true,
+		},
+		{
+			name:          "too large compressed",
+			ignition:      base64.StdEncoding.EncodeToString(random),
+			expectedError: `^the user data of the bootstrap VM is \d+ bytes with its Ignition config compressed, more than the 1048576 bytes a Secret holds; set platform.kubevirt.bootstrapIgnitionURL for the bootstrap VM to fetch the config instead$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gzip, err := compressBootstrapIgnition(tc.ignition)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedGzip, gzip)
+			}
+		})
+	}
+}
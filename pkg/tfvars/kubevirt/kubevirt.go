@@ -3,10 +3,16 @@ package kubevirt
 
 import (
 	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	v1 "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+	"github.com/openshift/installer/pkg/types/kubevirt"
 	// "github.com/openshift/installer/pkg/rhcos"
-	// "github.com/openshift/installer/pkg/tfvars/internal/cache"
+	// "github.com/openshift/installer/pkg/rhcos/cache"
 )
 
 type config struct {
@@ -20,20 +26,111 @@ type config struct {
 	NetworkName                string            `json:"kubevirt_network_name"`
 	PersistentVolumeAccessMode string            `json:"kubevirt_pv_access_mode"`
 	ResourcesLabels            map[string]string `json:"kubevirt_labels"`
+	MasterNamePrefix           string            `json:"kubevirt_master_name_prefix"`
+	KubeconfigPath             string            `json:"kubevirt_kubeconfig_path"`
+	KubeconfigContext          string            `json:"kubevirt_kubeconfig_context"`
+	MemoryLimit                string            `json:"kubevirt_master_memory_limit"`
+	OvercommitGuestOverhead    bool              `json:"kubevirt_master_overcommit_guest_overhead"`
+	SpreadPolicy               string            `json:"kubevirt_master_spread_policy"`
+	DiskBus                    string            `json:"kubevirt_master_disk_bus"`
+	EtcdDiskSize               string            `json:"kubevirt_master_etcd_disk_size,omitempty"`
+	EtcdDiskStorageClass       string            `json:"kubevirt_master_etcd_disk_storage_class,omitempty"`
+	CPUModel                   string            `json:"kubevirt_master_cpu_model,omitempty"`
+	CPUFeatures                []cpuFeature      `json:"kubevirt_master_cpu_features,omitempty"`
+	HugepagesPageSize          string            `json:"kubevirt_master_hugepages_page_size,omitempty"`
+	RunStrategy                string            `json:"kubevirt_master_run_strategy"`
+	TerminationGracePeriod     *int64            `json:"kubevirt_master_termination_grace_period_seconds,omitempty"`
+	PriorityClassName          string            `json:"kubevirt_master_priority_class_name,omitempty"`
+	MinAvailable               int               `json:"kubevirt_master_min_available"`
+	BootstrapIgnitionURL       string            `json:"kubevirt_bootstrap_ignition_url"`
+	BootstrapIgnitionGzip      bool              `json:"kubevirt_bootstrap_ignition_gzip"`
+	BootstrapCPU               uint32            `json:"kubevirt_bootstrap_cpu"`
+	BootstrapMemory            string            `json:"kubevirt_bootstrap_memory"`
+	BootstrapStorage           string            `json:"kubevirt_bootstrap_storage"`
+	BootstrapRunStrategy       string            `json:"kubevirt_bootstrap_run_strategy"`
+	ImportAnnotations          map[string]string `json:"kubevirt_import_annotations,omitempty"`
+	DataVolumeAnnotations      map[string]string `json:"kubevirt_data_volume_annotations,omitempty"`
+	ImageImportTimeout         string            `json:"kubevirt_image_import_timeout"`
+}
+
+type cpuFeature struct {
+	Name   string `json:"name"`
+	Policy string `json:"policy"`
 }
 
 // TFVarsSources contains the parameters to be converted into Terraform variables
 type TFVarsSources struct {
-	MasterSpecs     []*v1.KubevirtMachineProviderSpec
-	ImageURL        string
-	Namespace       string
-	ResourcesLabels map[string]string
+	MasterSpecs       []*v1.KubevirtMachineProviderSpec
+	MasterNamePrefix  string
+	ImageURL          string
+	Namespace         string
+	ResourcesLabels   map[string]string
+	KubeconfigPath    string
+	KubeconfigContext string
+	// MasterMemoryOverhead is added to the memory of the masters as their memory limit.
+	MasterMemoryOverhead          string
+	MasterOvercommitGuestOverhead bool
+	MasterSpreadPolicy            kubevirt.SpreadPolicy
+	MasterDiskBus                 kubevirt.DiskBus
+	// MasterEtcdDisk is the dedicated etcd disk of the masters, nil for none.
+	MasterEtcdDisk *kubevirt.EtcdDisk
+	// MasterCPUModel and MasterCPUFeatures are the CPU model and features of the masters,
+	// empty for the defaults of the infra cluster.
+	MasterCPUModel    string
+	MasterCPUFeatures []kubevirt.CPUFeature
+	// MasterHugepagesPageSize is the page size of the hugepages backing the memory of the
+	// masters, empty for regular memory.
+	MasterHugepagesPageSize string
+	// MasterRunStrategy and BootstrapRunStrategy are the run strategies of the masters and
+	// of the bootstrap VM.
+	MasterRunStrategy    kubevirt.RunStrategy
+	BootstrapRunStrategy kubevirt.RunStrategy
+	// MasterGracePeriodSeconds is the termination grace period of the masters, how long their
+	// guests are given to shut down, nil for the default of the infra cluster.
+	MasterGracePeriodSeconds *int64
+	// MasterPriorityClassName is the priority class of the infra cluster the masters are
+	// scheduled with, empty for the default priority.
+	MasterPriorityClassName string
+	// BootstrapIgnitionURL is the URL the bootstrap Ignition config is uploaded to and
+	// fetched from by the bootstrap VM, empty when the config is embedded in its user data.
+	BootstrapIgnitionURL string
+	BootstrapIgnition    string
+	// SkipBootstrapIgnitionUpload leaves the bootstrap Ignition config out of the URL, for the
+	// dry runs which must not write anywhere.
+	SkipBootstrapIgnitionUpload bool
+	// BootstrapVM is the size of the bootstrap VM.
+	BootstrapVM kubevirt.MachinePool
+	// ImportTuning is the resource tuning of the import of the RHCOS image, nil for none.
+	ImportTuning *kubevirt.ImportTuning
+	// ImageImportTimeout is how long the import of the RHCOS image is waited for.
+	ImageImportTimeout time.Duration
+	// DataVolumeAnnotations are the annotations tagging the DataVolumes, nil for none.
+	DataVolumeAnnotations map[string]string
 }
 
 // TFVars generates kubevirt-specific Terraform variables.
 func TFVars(sources TFVarsSources) ([]byte, error) {
 	masterSpec := sources.MasterSpecs[0]
 
+	memoryLimit, err := masterMemoryLimit(masterSpec.RequestedMemory, sources.MasterMemoryOverhead)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrapIgnitionGzip := false
+	if sources.BootstrapIgnitionURL != "" {
+		if sources.SkipBootstrapIgnitionUpload {
+			logrus.Infof("Skipping the upload of the bootstrap Ignition config to %s", sources.BootstrapIgnitionURL)
+		} else if err := uploadBootstrapIgnition(newUploadClient(), sources.BootstrapIgnitionURL, sources.BootstrapIgnition); err != nil {
+			return nil, err
+		}
+	} else {
+		bootstrapIgnitionGzip, err = compressBootstrapIgnition(sources.BootstrapIgnition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// For optional parametes, set only if not nil
 	cfg := config{
 		Namespace:                  sources.Namespace,
@@ -44,16 +141,148 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		Storage:                    masterSpec.RequestedStorage,
 		StorageClass:               masterSpec.StorageClassName,
 		NetworkName:                masterSpec.NetworkName,
-		PersistentVolumeAccessMode: safeAccessMode(masterSpec.PersistentVolumeAccessMode),
+		PersistentVolumeAccessMode: AccessMode(masterSpec.PersistentVolumeAccessMode),
 		ResourcesLabels:            sources.ResourcesLabels,
+		MasterNamePrefix:           sources.MasterNamePrefix,
+		KubeconfigPath:             sources.KubeconfigPath,
+		KubeconfigContext:          sources.KubeconfigContext,
+		MemoryLimit:                memoryLimit,
+		OvercommitGuestOverhead:    sources.MasterOvercommitGuestOverhead,
+		SpreadPolicy:               string(sources.MasterSpreadPolicy),
+		DiskBus:                    safeDiskBus(sources.MasterDiskBus),
+		BootstrapIgnitionURL:       sources.BootstrapIgnitionURL,
+		BootstrapIgnitionGzip:      bootstrapIgnitionGzip,
+		BootstrapCPU:               sources.BootstrapVM.CPU,
+		BootstrapMemory:            sources.BootstrapVM.Memory,
+		BootstrapStorage:           sources.BootstrapVM.StorageSize,
+		CPUModel:                   sources.MasterCPUModel,
+		HugepagesPageSize:          sources.MasterHugepagesPageSize,
+		RunStrategy:                creationRunStrategy(sources.MasterRunStrategy),
+		BootstrapRunStrategy:       creationRunStrategy(sources.BootstrapRunStrategy),
+		TerminationGracePeriod:     sources.MasterGracePeriodSeconds,
+		PriorityClassName:          sources.MasterPriorityClassName,
+		MinAvailable:               mastersMinAvailable(len(sources.MasterSpecs)),
+		ImportAnnotations:          ImportAnnotations(sources.ImportTuning),
+		DataVolumeAnnotations:      sources.DataVolumeAnnotations,
+		ImageImportTimeout:         sources.ImageImportTimeout.String(),
+	}
+	for _, feature := range sources.MasterCPUFeatures {
+		cfg.CPUFeatures = append(cfg.CPUFeatures, cpuFeature{Name: feature.Name, Policy: safeCPUFeaturePolicy(feature.Policy)})
+	}
+	if etcdDisk := sources.MasterEtcdDisk; etcdDisk != nil {
+		cfg.EtcdDiskSize = etcdDisk.Size
+		cfg.EtcdDiskStorageClass = etcdDisk.StorageClass
+		if cfg.EtcdDiskStorageClass == "" {
+			cfg.EtcdDiskStorageClass = masterSpec.StorageClassName
+		}
 	}
 
 	return json.MarshalIndent(cfg, "", "  ")
 }
 
-func safeAccessMode(accessMode string) string {
+// The annotations of a DataVolume tuning the resources of its CDI importer pod. CDI versions
+// without per-DataVolume tuning ignore them and use the pod resource requirements of the CDI
+// config of the infra cluster.
+const (
+	importRequestsCPUAnnotation    = "cdi.kubevirt.io/storage.import.requests.cpu"
+	importRequestsMemoryAnnotation = "cdi.kubevirt.io/storage.import.requests.memory"
+	importLimitsCPUAnnotation      = "cdi.kubevirt.io/storage.import.limits.cpu"
+	importLimitsMemoryAnnotation   = "cdi.kubevirt.io/storage.import.limits.memory"
+)
+
+// ImportAnnotations returns the annotations of the DataVolume importing the RHCOS image,
+// nil when its import is not tuned.
+func ImportAnnotations(tuning *kubevirt.ImportTuning) map[string]string {
+	if tuning == nil {
+		return nil
+	}
+	annotations := map[string]string{}
+	if requests := tuning.Requests; requests != nil {
+		setIfNotEmpty(annotations, importRequestsCPUAnnotation, requests.CPU)
+		setIfNotEmpty(annotations, importRequestsMemoryAnnotation, requests.Memory)
+	}
+	if limits := tuning.Limits; limits != nil {
+		setIfNotEmpty(annotations, importLimitsCPUAnnotation, limits.CPU)
+		setIfNotEmpty(annotations, importLimitsMemoryAnnotation, limits.Memory)
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+func setIfNotEmpty(m map[string]string, key string, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
+
+// mastersMinAvailable returns how many masters the disruption budget of the masters keeps
+// available, for the drains of the infra cluster nodes not to evict more masters than etcd can
+// lose and keep its quorum, e.g. 2 of 3 masters. It returns 0, for no disruption budget, with
+// less than 3 masters, as the budget would block the drains of their nodes for good.
+func mastersMinAvailable(masters int) int {
+	if masters < 3 {
+		return 0
+	}
+	return masters/2 + 1
+}
+
+// masterMemoryLimit returns the sum of the memory and the memory overhead, or an empty
+// string when there is no overhead and the VMs have no memory limit.
+func masterMemoryLimit(memory string, overhead string) (string, error) {
+	if overhead == "" {
+		return "", nil
+	}
+	memoryQuantity, err := resource.ParseQuantity(memory)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the master memory %q", memory)
+	}
+	overheadQuantity, err := resource.ParseQuantity(overhead)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the master memory overhead %q", overhead)
+	}
+	memoryQuantity.Add(overheadQuantity)
+	return memoryQuantity.String(), nil
+}
+
+func safeDiskBus(diskBus kubevirt.DiskBus) string {
+	if diskBus != "" {
+		return string(diskBus)
+	}
+	return string(kubevirt.DiskBusVirtio)
+}
+
+// creationRunStrategy returns the run strategy the VMs are created with. The VMs with the Manual
+// run strategy are created with Always, as KubeVirt doesn't start them otherwise while terraform
+// waits for them to be ready, and switched to Manual once created.
+func creationRunStrategy(runStrategy kubevirt.RunStrategy) string {
+	if runStrategy == "" || runStrategy == kubevirt.RunStrategyManual {
+		return string(kubevirt.RunStrategyAlways)
+	}
+	return string(runStrategy)
+}
+
+func safeCPUFeaturePolicy(policy kubevirt.CPUFeaturePolicy) string {
+	if policy != "" {
+		return string(policy)
+	}
+	return string(kubevirt.CPUFeaturePolicyRequire)
+}
+
+// AccessMode returns the access mode of the persistent volumes of the VMs, ReadWriteMany
+// when unset.
+func AccessMode(accessMode string) string {
 	if accessMode != "" {
 		return accessMode
 	}
 	return "ReadWriteMany"
 }
+
+// RunTFVars generates the Terraform variables labeling the resources created by a single run
+// of the infrastructure stage, so that they can be rolled back when the run fails.
+func RunTFVars(runLabels map[string]string) ([]byte, error) {
+	return json.MarshalIndent(struct {
+		RunLabels map[string]string `json:"kubevirt_run_labels"`
+	}{RunLabels: runLabels}, "", "  ")
+}
@@ -0,0 +1,38 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/openshift/cluster-api-provider-kubevirt/pkg/apis/kubevirtprovider/v1alpha1"
+)
+
+func TestTFVarsMinAvailable(t *testing.T) {
+	cases := []struct {
+		masters      int
+		minAvailable int
+	}{
+		{masters: 1, minAvailable: 0},
+		{masters: 2, minAvailable: 0},
+		{masters: 3, minAvailable: 2},
+		{masters: 4, minAvailable: 3},
+		{masters: 5, minAvailable: 3},
+	}
+	for _, tc := range cases {
+		specs := make([]*v1.KubevirtMachineProviderSpec, tc.masters)
+		for i := range specs {
+			specs[i] = &v1.KubevirtMachineProviderSpec{RequestedMemory: "16Gi", RequestedCPU: 4, RequestedStorage: "120Gi"}
+		}
+		data, err := TFVars(TFVarsSources{MasterSpecs: specs, Namespace: "tenant", MasterNamePrefix: "infra-id"})
+		if !assert.NoError(t, err, "%d masters", tc.masters) {
+			continue
+		}
+		var variables map[string]interface{}
+		if !assert.NoError(t, json.Unmarshal(data, &variables)) {
+			continue
+		}
+		assert.Equal(t, float64(tc.minAvailable), variables["kubevirt_master_min_available"], "%d masters", tc.masters)
+	}
+}
@@ -0,0 +1,130 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift/installer/pkg/tfvars"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+// Plan returns the resources which the Terraform configuration of the kubevirt platform
+// creates in the infra cluster with the variables, in their creation order. It mirrors
+// data/data/kubevirt, the run labels set at apply time are left out.
+func Plan(common *tfvars.Common, data []byte) ([]tfvars.PlannedResource, error) {
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	var resources []tfvars.PlannedResource
+	add := func(kind string, namespace string, name string, annotations map[string]string, spec map[string]interface{}) {
+		resources = append(resources, tfvars.PlannedResource{
+			Kind:        kind,
+			Namespace:   namespace,
+			Name:        name,
+			Labels:      cfg.ResourcesLabels,
+			Annotations: annotations,
+			Spec:        spec,
+		})
+	}
+	volume := func(name string, storage string, storageClass string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":         name,
+			"storage":      storage,
+			"storageClass": storageClass,
+			"accessMode":   cfg.PersistentVolumeAccessMode,
+		}
+	}
+
+	add("DataVolume", cfg.Namespace, cfg.SourcePvcName, mergeAnnotations(cfg.ImportAnnotations, cfg.DataVolumeAnnotations), map[string]interface{}{
+		"source":        cfg.ImageURL,
+		"storage":       "20Gi",
+		"storageClass":  cfg.StorageClass,
+		"accessMode":    cfg.PersistentVolumeAccessMode,
+		"importTimeout": cfg.ImageImportTimeout,
+	})
+
+	etcdBlank := fmt.Sprintf("%s-master-etcd-blank", cfg.MasterNamePrefix)
+	if cfg.EtcdDiskSize != "" {
+		add("PersistentVolumeClaim", cfg.Namespace, etcdBlank, nil, map[string]interface{}{
+			"storage":      cfg.EtcdDiskSize,
+			"storageClass": cfg.EtcdDiskStorageClass,
+			"accessMode":   cfg.PersistentVolumeAccessMode,
+		})
+	}
+	if cfg.MinAvailable > 0 {
+		add("PodDisruptionBudget", cfg.Namespace, fmt.Sprintf("%s-masters", cfg.MasterNamePrefix), nil, map[string]interface{}{
+			"minAvailable": cfg.MinAvailable,
+		})
+	}
+	for i := 0; i < common.Masters; i++ {
+		name := kubevirt.ResourceName(cfg.MasterNamePrefix, "master", i)
+		add("Secret", cfg.Namespace, name+"-ignition", nil, map[string]interface{}{
+			"keys": []string{"userdata"},
+		})
+
+		dataVolumes := []interface{}{volume(name+"-bootvolume", cfg.Storage, cfg.StorageClass)}
+		if cfg.EtcdDiskSize != "" {
+			dataVolumes = append(dataVolumes, volume(name+"-etcdvolume", cfg.EtcdDiskSize, cfg.EtcdDiskStorageClass))
+		}
+		spec := map[string]interface{}{
+			"cpu":          cfg.CPU,
+			"memory":       cfg.Memory,
+			"network":      cfg.NetworkName,
+			"diskBus":      cfg.DiskBus,
+			"runStrategy":  cfg.RunStrategy,
+			"spreadPolicy": cfg.SpreadPolicy,
+			"source":       fmt.Sprintf("%s/%s", cfg.Namespace, cfg.SourcePvcName),
+			"dataVolumes":  dataVolumes,
+		}
+		setSpecIfNotEmpty(spec, "memoryLimit", cfg.MemoryLimit)
+		setSpecIfNotEmpty(spec, "cpuModel", cfg.CPUModel)
+		setSpecIfNotEmpty(spec, "hugepagesPageSize", cfg.HugepagesPageSize)
+		setSpecIfNotEmpty(spec, "priorityClassName", cfg.PriorityClassName)
+		if len(cfg.CPUFeatures) > 0 {
+			spec["cpuFeatures"] = cfg.CPUFeatures
+		}
+		if cfg.OvercommitGuestOverhead {
+			spec["overcommitGuestOverhead"] = true
+		}
+		if cfg.TerminationGracePeriod != nil {
+			spec["terminationGracePeriodSeconds"] = *cfg.TerminationGracePeriod
+		}
+		add("VirtualMachine", cfg.Namespace, name, cfg.DataVolumeAnnotations, spec)
+	}
+
+	bootstrap := fmt.Sprintf("%s-bootstrap", common.ClusterID)
+	ignition := map[string]interface{}{"keys": []string{"userdata"}, "gzip": cfg.BootstrapIgnitionGzip}
+	setSpecIfNotEmpty(ignition, "ignitionURL", cfg.BootstrapIgnitionURL)
+	add("Secret", cfg.Namespace, bootstrap+"-ignition", nil, ignition)
+	add("VirtualMachine", cfg.Namespace, bootstrap, cfg.DataVolumeAnnotations, map[string]interface{}{
+		"cpu":         cfg.BootstrapCPU,
+		"memory":      cfg.BootstrapMemory,
+		"network":     cfg.NetworkName,
+		"runStrategy": cfg.BootstrapRunStrategy,
+		"source":      fmt.Sprintf("%s/%s", cfg.Namespace, cfg.SourcePvcName),
+		"dataVolumes": []interface{}{volume(bootstrap+"-bootvolume", cfg.BootstrapStorage, cfg.StorageClass)},
+	})
+	return resources, nil
+}
+
+// mergeAnnotations merges the annotations as the merge function of Terraform, nil for none.
+func mergeAnnotations(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func setSpecIfNotEmpty(spec map[string]interface{}, key string, value string) {
+	if value != "" {
+		spec[key] = value
+	}
+}
@@ -0,0 +1,88 @@
+package kubevirt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/tfvars"
+)
+
+func TestPlan(t *testing.T) {
+	labels := map[string]string{"tenantcluster-infra-id-machine.openshift.io": "owned"}
+	data, err := json.Marshal(config{
+		Namespace:                  "tenant",
+		ImageURL:                   "https://example.com/rhcos.qcow2.gz",
+		SourcePvcName:              "infra-id-source-pvc",
+		Memory:                     "16Gi",
+		CPU:                        8,
+		Storage:                    "120Gi",
+		StorageClass:               "fast-ssd",
+		NetworkName:                "tenant-network",
+		PersistentVolumeAccessMode: "ReadWriteMany",
+		ResourcesLabels:            labels,
+		MasterNamePrefix:           "infra-id",
+		DiskBus:                    "virtio",
+		EtcdDiskSize:               "20Gi",
+		EtcdDiskStorageClass:       "fast-ssd",
+		RunStrategy:                "Always",
+		MinAvailable:               2,
+		BootstrapCPU:               4,
+		BootstrapMemory:            "8Gi",
+		BootstrapStorage:           "120Gi",
+		BootstrapRunStrategy:       "Always",
+		BootstrapIgnitionGzip:      true,
+		ImageImportTimeout:         "20m0s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := Plan(&tfvars.Common{ClusterID: "infra-id", Masters: 3}, data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var names []string
+	for _, r := range resources {
+		assert.Equal(t, labels, r.Labels)
+		names = append(names, r.Kind+" "+r.Namespace+"/"+r.Name)
+	}
+	assert.Equal(t, []string{
+		"DataVolume tenant/infra-id-source-pvc",
+		"PersistentVolumeClaim tenant/infra-id-master-etcd-blank",
+		"PodDisruptionBudget tenant/infra-id-masters",
+		"Secret tenant/infra-id-master-0-ignition",
+		"VirtualMachine tenant/infra-id-master-0",
+		"Secret tenant/infra-id-master-1-ignition",
+		"VirtualMachine tenant/infra-id-master-1",
+		"Secret tenant/infra-id-master-2-ignition",
+		"VirtualMachine tenant/infra-id-master-2",
+		"Secret tenant/infra-id-bootstrap-ignition",
+		"VirtualMachine tenant/infra-id-bootstrap",
+	}, names)
+
+	assert.Equal(t, 2, resources[2].Spec["minAvailable"])
+	master := resources[4].Spec
+	assert.Equal(t, uint32(8), master["cpu"])
+	assert.Equal(t, "16Gi", master["memory"])
+	assert.Equal(t, "tenant/infra-id-source-pvc", master["source"])
+	assert.Len(t, master["dataVolumes"], 2)
+	assert.NotContains(t, master, "terminationGracePeriodSeconds")
+}
+
+func TestPlanSingleMaster(t *testing.T) {
+	data, err := json.Marshal(config{Namespace: "tenant", MasterNamePrefix: "infra-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, err := Plan(&tfvars.Common{ClusterID: "infra-id", Masters: 1}, data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// Neither the disruption budget nor the etcd disks
+	for _, r := range resources {
+		assert.Contains(t, []string{"DataVolume", "Secret", "VirtualMachine"}, r.Kind)
+	}
+	assert.Len(t, resources, 5)
+}
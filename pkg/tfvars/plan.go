@@ -0,0 +1,31 @@
+package tfvars
+
+import (
+	"encoding/json"
+)
+
+// PlannedResource is an infrastructure resource which the Terraform configuration of the
+// platform creates with the variables, as listed by the dry runs.
+type PlannedResource struct {
+	Kind        string                 `json:"kind"`
+	Namespace   string                 `json:"namespace,omitempty"`
+	Name        string                 `json:"name"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	Spec        map[string]interface{} `json:"spec,omitempty"`
+}
+
+// Common are the variables shared by the platforms, parsed from the terraform.tfvars.
+type Common struct {
+	ClusterID string
+	Masters   int
+}
+
+// ParseCommon parses the variables shared by the platforms from the terraform.tfvars.
+func ParseCommon(data []byte) (*Common, error) {
+	cfg := config{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &Common{ClusterID: cfg.ClusterID, Masters: cfg.Masters}, nil
+}
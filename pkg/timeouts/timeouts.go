@@ -0,0 +1,77 @@
+// Package timeouts resolves the timeouts of the stages of the lifecycle of the cluster, from
+// the --*-timeout flags of the installer, the timeouts of the install-config and the defaults.
+package timeouts
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Stage is a stage of the lifecycle of the cluster with a timeout.
+type Stage string
+
+const (
+	// Bootstrap is the wait for the bootstrapping to complete.
+	Bootstrap Stage = "bootstrap"
+	// InstallComplete is the wait for the cluster operators to initialize the cluster.
+	InstallComplete Stage = "install-complete"
+	// Destroy is the deletion of the resources of the cluster.
+	Destroy Stage = "destroy"
+	// ImageImport is the import of the RHCOS image into the KubeVirt infra cluster.
+	ImageImport Stage = "image-import"
+)
+
+// Stages are all the stages with a timeout.
+var Stages = []Stage{Bootstrap, InstallComplete, Destroy, ImageImport}
+
+var (
+	mutex     sync.Mutex
+	overrides = map[Stage]time.Duration{}
+)
+
+// Override sets the timeout of the stage, overriding the install-config, e.g. from the flags of
+// the installer. A zero timeout removes the override.
+func Override(stage Stage, timeout time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if timeout == 0 {
+		delete(overrides, stage)
+		return
+	}
+	overrides[stage] = timeout
+}
+
+// Get returns the timeout of the stage: the override, else the timeout of the install-config,
+// which may be nil, else the default timeout.
+func Get(stage Stage, config *types.InstallConfig, defaultTimeout time.Duration) time.Duration {
+	mutex.Lock()
+	timeout, ok := overrides[stage]
+	mutex.Unlock()
+	if ok {
+		return timeout
+	}
+	if config != nil && config.Timeouts != nil {
+		if d := configTimeout(stage, config.Timeouts); d != nil {
+			return d.Duration
+		}
+	}
+	return defaultTimeout
+}
+
+func configTimeout(stage Stage, t *types.Timeouts) *metav1.Duration {
+	switch stage {
+	case Bootstrap:
+		return t.Bootstrap
+	case InstallComplete:
+		return t.InstallComplete
+	case Destroy:
+		return t.Destroy
+	case ImageImport:
+		return t.ImageImport
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package timeouts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGet(t *testing.T) {
+	config := &types.InstallConfig{Timeouts: &types.Timeouts{
+		Bootstrap: &metav1.Duration{Duration: 45 * time.Minute},
+	}}
+
+	assert.Equal(t, 30*time.Minute, Get(Bootstrap, nil, 30*time.Minute), "default")
+	assert.Equal(t, 40*time.Minute, Get(InstallComplete, config, 40*time.Minute), "default when unset in the install-config")
+	assert.Equal(t, 45*time.Minute, Get(Bootstrap, config, 30*time.Minute), "install-config")
+
+	Override(Bootstrap, time.Hour)
+	defer Override(Bootstrap, 0)
+	assert.Equal(t, time.Hour, Get(Bootstrap, config, 30*time.Minute), "override")
+	assert.Equal(t, 40*time.Minute, Get(InstallComplete, config, 40*time.Minute), "override of another stage")
+
+	Override(Bootstrap, 0)
+	assert.Equal(t, 45*time.Minute, Get(Bootstrap, config, 30*time.Minute), "override removed")
+}
@@ -22,6 +22,11 @@ type ClusterMetadata struct {
 	// infraID is an ID that is used to identify cloud resources created by the installer.
 	InfraID                 string `json:"infraID"`
 	ClusterPlatformMetadata `json:",inline"`
+	// protected makes destroy refuse to delete the cluster unless the protection is overridden.
+	Protected bool `json:"protected,omitempty"`
+	// releaseImageOverride is the release image the cluster was installed with, when it was
+	// overridden instead of being the default one of the installer.
+	ReleaseImageOverride string `json:"releaseImageOverride,omitempty"`
 }
 
 // ClusterPlatformMetadata contains metadata for platfrom.
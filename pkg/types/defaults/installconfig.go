@@ -87,7 +87,7 @@ func SetInstallConfigDefaults(c *types.InstallConfig) {
 	case c.Platform.Ovirt != nil:
 		ovirtdefaults.SetPlatformDefaults(c.Platform.Ovirt)
 	case c.Platform.Kubevirt != nil:
-		kubevirtdefaults.SetPlatformDefaults(c.Platform.Kubevirt, c.ControlPlane, c.Compute)
+		kubevirtdefaults.SetPlatformDefaults(c.Platform.Kubevirt, c)
 	case c.Platform.None != nil:
 		nonedefaults.SetPlatformDefaults(c.Platform.None)
 	}
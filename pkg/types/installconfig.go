@@ -101,6 +101,13 @@ type InstallConfig struct {
 	// PullSecret is the secret to use when pulling images.
 	PullSecret string `json:"pullSecret"`
 
+	// PullSecretScope, when set, strips from the pull secret embedded into the cluster the
+	// credentials of the registries the cluster doesn't need, keeping only those of the
+	// registry of the release image, of the mirrors of imageContentSources and of the
+	// additional registries.
+	// +optional
+	PullSecretScope *PullSecretScope `json:"pullSecretScope,omitempty"`
+
 	// Proxy defines the proxy settings for the cluster.
 	// If unset, the cluster will not be configured to use a proxy.
 	// +optional
@@ -110,6 +117,13 @@ type InstallConfig struct {
 	// +optional
 	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
 
+	// MirrorPolicyKind is the kind of the objects configuring the imageContentSources in the
+	// cluster, "ImageContentSourcePolicy" or "ImageDigestMirrorSet". ImageDigestMirrorSet
+	// requires a release serving the config.openshift.io/v1 ImageDigestMirrorSet API.
+	// When unset, ImageContentSourcePolicy objects are created.
+	// +optional
+	MirrorPolicyKind MirrorPolicyKind `json:"mirrorPolicyKind,omitempty"`
+
 	// Publish controls how the user facing endpoints of the cluster like the Kubernetes API, OpenShift routes etc. are exposed.
 	// When no strategy is specified, the strategy is "External".
 	//
@@ -141,6 +155,88 @@ type InstallConfig struct {
 	// GCP: "Mint", "Passthrough", "Manual"
 	// +optional
 	CredentialsMode CredentialsMode `json:"credentialsMode,omitempty"`
+
+	// AuditProfile is the audit policy profile of the API servers of the cluster:
+	// "Default" logs the metadata of all the requests and the bodies of none,
+	// "WriteRequestBodies" also logs the bodies of the requests writing resources,
+	// "AllRequestBodies" also logs the bodies of the requests reading resources, and
+	// "None" logs no requests.
+	// When unset, the API servers use the Default profile.
+	// +optional
+	AuditProfile AuditProfile `json:"auditProfile,omitempty"`
+
+	// APIServerCertificate is a serving certificate of the API server signed by a CA of the
+	// user, served for the external names of the API instead of the certificate signed by the
+	// installer, so that the clients of the cluster trust its API from the start.
+	// +optional
+	APIServerCertificate *APIServerCertificate `json:"apiServerCertificate,omitempty"`
+
+	// NTPServers are the NTP servers, IP addresses or host names, the nodes synchronize their
+	// clocks with instead of the default servers of RHCOS, e.g. for nested clusters on KubeVirt
+	// whose guest clocks drift.
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
+
+	// Timeouts override the timeouts of the stages of the lifecycle of the cluster, e.g. for
+	// slow infra clusters. The --*-timeout flags of the installer override them in turn.
+	// +optional
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+}
+
+// APIServerCertificate is a named serving certificate of the API server, read from files of the
+// installer host.
+type APIServerCertificate struct {
+	// CertFile is the path of the file holding the PEM-encoded certificate, followed by its
+	// intermediate CA certificates, if any.
+	CertFile string `json:"certFile"`
+
+	// KeyFile is the path of the file holding the PEM-encoded private key of the certificate.
+	KeyFile string `json:"keyFile"`
+
+	// CAFile is the path of the file holding the PEM-encoded certificate of the root CA of the
+	// certificate, added to the certificate authorities of the admin kubeconfig for it to keep
+	// trusting the API once the certificate is served.
+	CAFile string `json:"caFile"`
+
+	// Names are the DNS names, leading wildcards allowed, the certificate is served for and
+	// must be valid for. Defaults to the api.<cluster domain> name of the API.
+	// +optional
+	Names []string `json:"names,omitempty"`
+}
+
+// Timeouts are the timeouts of the stages of the lifecycle of the cluster.
+type Timeouts struct {
+	// Bootstrap is how long the installer waits for the bootstrapping to complete once the
+	// Kubernetes API is up. Defaults to 30m.
+	// +optional
+	Bootstrap *metav1.Duration `json:"bootstrap,omitempty"`
+
+	// InstallComplete is how long the installer waits for the cluster operators to initialize
+	// the cluster once the bootstrapping is complete. Defaults to 40m, 60m on bare metal.
+	// +optional
+	InstallComplete *metav1.Duration `json:"installComplete,omitempty"`
+
+	// Destroy is how long the installer waits for the resources of the cluster to be deleted.
+	// Defaults to no timeout.
+	// +optional
+	Destroy *metav1.Duration `json:"destroy,omitempty"`
+
+	// ImageImport is how long the installer waits for the RHCOS image to be imported into the
+	// infra cluster, on KubeVirt. Defaults to 20m.
+	// +optional
+	ImageImport *metav1.Duration `json:"imageImport,omitempty"`
+}
+
+// APIServerCertificateNames returns the names the named serving certificate of the API server
+// is served for, nil when there is no such certificate.
+func (c *InstallConfig) APIServerCertificateNames() []string {
+	if c.APIServerCertificate == nil {
+		return nil
+	}
+	if len(c.APIServerCertificate.Names) > 0 {
+		return c.APIServerCertificate.Names
+	}
+	return []string{fmt.Sprintf("api.%s", c.ClusterDomain())}
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
@@ -315,6 +411,15 @@ type Proxy struct {
 	NoProxy string `json:"noProxy,omitempty"`
 }
 
+// PullSecretScope selects the credentials of the pull secret embedded into the cluster.
+type PullSecretScope struct {
+	// AdditionalRegistries are the registries, besides those of the release image and of its
+	// mirrors, whose credentials are kept, e.g. cloud.openshift.com for the telemetry or
+	// registry.redhat.io for the operators. A registry may be followed by a namespace.
+	// +optional
+	AdditionalRegistries []string `json:"additionalRegistries,omitempty"`
+}
+
 // ImageContentSource defines a list of sources/repositories that can be used to pull content.
 type ImageContentSource struct {
 	// Source is the repository that users refer to, e.g. in image pull specifications.
@@ -325,6 +430,34 @@ type ImageContentSource struct {
 	Mirrors []string `json:"mirrors,omitempty"`
 }
 
+// MirrorPolicyKind is the kind of the objects configuring the mirrors of the image content sources.
+// +kubebuilder:validation:Enum="";ImageContentSourcePolicy;ImageDigestMirrorSet
+type MirrorPolicyKind string
+
+const (
+	// ImageContentSourcePolicyMirrorPolicyKind configures the mirrors with
+	// operator.openshift.io/v1alpha1 ImageContentSourcePolicy objects.
+	ImageContentSourcePolicyMirrorPolicyKind MirrorPolicyKind = "ImageContentSourcePolicy"
+	// ImageDigestMirrorSetMirrorPolicyKind configures the mirrors with config.openshift.io/v1
+	// ImageDigestMirrorSet objects.
+	ImageDigestMirrorSetMirrorPolicyKind MirrorPolicyKind = "ImageDigestMirrorSet"
+)
+
+// AuditProfile is the audit policy profile of the API servers.
+// +kubebuilder:validation:Enum="";Default;WriteRequestBodies;AllRequestBodies;None
+type AuditProfile string
+
+const (
+	// DefaultAuditProfile logs the metadata of all the requests.
+	DefaultAuditProfile AuditProfile = "Default"
+	// WriteRequestBodiesAuditProfile also logs the bodies of the requests writing resources.
+	WriteRequestBodiesAuditProfile AuditProfile = "WriteRequestBodies"
+	// AllRequestBodiesAuditProfile also logs the bodies of the requests reading resources.
+	AllRequestBodiesAuditProfile AuditProfile = "AllRequestBodies"
+	// NoneAuditProfile logs no requests.
+	NoneAuditProfile AuditProfile = "None"
+)
+
 // CredentialsMode is the mode by which CredentialsRequests will be satisfied.
 // +kubebuilder:validation:Enum="";Mint;Passthrough;Manual
 type CredentialsMode string
@@ -6,7 +6,24 @@ import (
 )
 
 // SetPlatformDefaults sets the defaults for the platform.
-func SetPlatformDefaults(p *kubevirt.Platform, controlPlane *types.MachinePool, compute []types.MachinePool) {
+func SetPlatformDefaults(p *kubevirt.Platform, c *types.InstallConfig) {
+	controlPlane, compute := c.ControlPlane, c.Compute
+	if p != nil && p.CreateNetwork != nil && p.CreateNetwork.Type == "" {
+		p.CreateNetwork.Type = kubevirt.NetworkTypeBridge
+	}
+	if p != nil && p.NetworkProbe != nil && p.NetworkProbe.Image == "" {
+		p.NetworkProbe.Image = kubevirt.DefaultNetworkProbeImage
+	}
+	if p != nil && p.WorkerIgnitionServer != nil && p.WorkerIgnitionServer.Image == "" {
+		p.WorkerIgnitionServer.Image = kubevirt.DefaultWorkerIgnitionServerImage
+	}
+	// Disconnected installs get the nodes configured for their mirror registries
+	if p != nil && len(c.ImageContentSources) > 0 && p.MirrorRegistry == nil {
+		p.MirrorRegistry = &kubevirt.MirrorRegistry{}
+	}
+	if p != nil && p.MirrorRegistry != nil && p.MirrorRegistry.CABundle == "" {
+		p.MirrorRegistry.CABundle = c.AdditionalTrustBundle
+	}
 	if controlPlane.Platform.Kubevirt == nil {
 		controlPlane.Platform.Kubevirt = &kubevirt.MachinePool{
 			CPU:         8,
@@ -51,10 +51,55 @@ func TestSetPlatformDefaults(t *testing.T) {
 			ic:       defaultInstallConfig(),
 			expected: expectedInstallConfig(),
 		},
+		{
+			name: "create network type",
+			ic: func() *types.InstallConfig {
+				ic := defaultInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{CreateNetwork: &kubevirt.NetworkTemplate{Bridge: "br1"}}
+				return ic
+			}(),
+			expected: func() *types.InstallConfig {
+				ic := expectedInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{CreateNetwork: &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1"}}
+				return ic
+			}(),
+		},
+		{
+			name: "mirror registry",
+			ic: func() *types.InstallConfig {
+				ic := defaultInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{}
+				ic.ImageContentSources = []types.ImageContentSource{{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com:5000/ocp/release"}}}
+				ic.AdditionalTrustBundle = "mirror-ca"
+				return ic
+			}(),
+			expected: func() *types.InstallConfig {
+				ic := expectedInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{MirrorRegistry: &kubevirt.MirrorRegistry{CABundle: "mirror-ca"}}
+				ic.ImageContentSources = []types.ImageContentSource{{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com:5000/ocp/release"}}}
+				ic.AdditionalTrustBundle = "mirror-ca"
+				return ic
+			}(),
+		},
+		{
+			name: "mirror registry CA bundle",
+			ic: func() *types.InstallConfig {
+				ic := defaultInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{MirrorRegistry: &kubevirt.MirrorRegistry{CABundle: "mirror-ca"}}
+				ic.AdditionalTrustBundle = "proxy-ca"
+				return ic
+			}(),
+			expected: func() *types.InstallConfig {
+				ic := expectedInstallConfig()
+				ic.Platform.Kubevirt = &kubevirt.Platform{MirrorRegistry: &kubevirt.MirrorRegistry{CABundle: "mirror-ca"}}
+				ic.AdditionalTrustBundle = "proxy-ca"
+				return ic
+			}(),
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			SetPlatformDefaults(tc.ic.Platform.Kubevirt, tc.ic.ControlPlane, tc.ic.Compute)
+			SetPlatformDefaults(tc.ic.Platform.Kubevirt, tc.ic)
 			assert.Equal(t, tc.expected, tc.ic, "unexpected InstallConfig")
 		})
 	}
@@ -1,5 +1,113 @@
 package kubevirt
 
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SpreadPolicy is the scheduling policy of the VMs of a machine pool across the infra cluster nodes.
+// +kubebuilder:validation:Enum="";Spread;Pack;None
+type SpreadPolicy string
+
+const (
+	// SpreadPolicySpread requires the VMs of the pool to run on different infra cluster nodes.
+	SpreadPolicySpread SpreadPolicy = "Spread"
+	// SpreadPolicyPack prefers running the VMs of the pool on the same infra cluster nodes.
+	SpreadPolicyPack SpreadPolicy = "Pack"
+	// SpreadPolicyNone leaves the placement of the VMs of the pool to the infra cluster scheduler.
+	SpreadPolicyNone SpreadPolicy = "None"
+)
+
+// DiskBus is the type of disk device emulated for the boot disk of the VMs.
+// +kubebuilder:validation:Enum="";virtio;sata;scsi
+type DiskBus string
+
+const (
+	// DiskBusVirtio emulates a paravirtualized virtio disk.
+	DiskBusVirtio DiskBus = "virtio"
+	// DiskBusSATA emulates a SATA disk.
+	DiskBusSATA DiskBus = "sata"
+	// DiskBusSCSI emulates a SCSI disk.
+	DiskBusSCSI DiskBus = "scsi"
+)
+
+// RunStrategy is how the infra cluster runs the VMs, e.g. when their guest crashes.
+// +kubebuilder:validation:Enum="";Always;RerunOnFailure;Manual
+type RunStrategy string
+
+const (
+	// RunStrategyAlways keeps the VMs running, restarting them whenever they stop.
+	RunStrategyAlways RunStrategy = "Always"
+	// RunStrategyRerunOnFailure restarts the VMs when their guest fails, but not when it is
+	// shut down from within.
+	RunStrategyRerunOnFailure RunStrategy = "RerunOnFailure"
+	// RunStrategyManual never restarts the VMs, which are only started and stopped by the
+	// users of the infra cluster once created.
+	RunStrategyManual RunStrategy = "Manual"
+)
+
+// Well-known CPU models of the VMs, besides the named models of the infra cluster nodes, e.g.
+// Haswell-noTSX or EPYC.
+const (
+	// CPUModelHostPassthrough passes the CPU of the infra cluster node through to the VMs,
+	// e.g. for nested virtualization, at the cost of live migrating them only to nodes
+	// with the same CPU.
+	CPUModelHostPassthrough = "host-passthrough"
+	// CPUModelHostModel emulates the model closest to the CPU of the infra cluster node.
+	CPUModelHostModel = "host-model"
+)
+
+// CPUFeaturePolicy is how a CPU feature is enabled in the VMs.
+// +kubebuilder:validation:Enum="";force;require;optional;disable;forbid
+type CPUFeaturePolicy string
+
+const (
+	// CPUFeaturePolicyForce enables the feature even if the CPU of the node lacks it.
+	CPUFeaturePolicyForce CPUFeaturePolicy = "force"
+	// CPUFeaturePolicyRequire enables the feature, the VMs failing to start on the nodes
+	// whose CPU lacks it.
+	CPUFeaturePolicyRequire CPUFeaturePolicy = "require"
+	// CPUFeaturePolicyOptional enables the feature if the CPU of the node has it.
+	CPUFeaturePolicyOptional CPUFeaturePolicy = "optional"
+	// CPUFeaturePolicyDisable disables the feature.
+	CPUFeaturePolicyDisable CPUFeaturePolicy = "disable"
+	// CPUFeaturePolicyForbid disables the feature, the VMs failing to start on the nodes
+	// whose CPU has it.
+	CPUFeaturePolicyForbid CPUFeaturePolicy = "forbid"
+)
+
+// CPUFeature is a CPU feature enabled or disabled in the VMs.
+type CPUFeature struct {
+	// Name is the name of the feature, e.g. vmx or svm for nested virtualization.
+	Name string `json:"name"`
+
+	// Policy is how the feature is enabled, one of force, require, optional, disable or
+	// forbid. Defaults to require.
+	// +optional
+	Policy CPUFeaturePolicy `json:"policy,omitempty"`
+}
+
+// Hugepages page sizes of the VMs, those of the x86_64 architecture.
+const (
+	// HugepagesPageSize2Mi backs the memory of the VMs with 2Mi pages.
+	HugepagesPageSize2Mi = "2Mi"
+	// HugepagesPageSize1Gi backs the memory of the VMs with 1Gi pages.
+	HugepagesPageSize1Gi = "1Gi"
+)
+
+// Hugepages backs the memory of the VMs with hugepages of the infra cluster nodes, e.g. for
+// DPDK workloads.
+type Hugepages struct {
+	// PageSize is the size of the hugepages, 2Mi or 1Gi.
+	PageSize string `json:"pageSize"`
+
+	// Count is the number of hugepages of each VM, which sets the memory of the VMs when the
+	// memory of the pool is unset, and must match it otherwise.
+	// +optional
+	Count uint32 `json:"count,omitempty"`
+}
+
 // MachinePool stores the configuration for a machine pool installed
 // on kubevirt.
 type MachinePool struct {
@@ -16,6 +124,132 @@ type MachinePool struct {
 	// Format: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/apimachinery/pkg/api/resource/quantity.go
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
+
+	// NamePrefix replaces the cluster infrastructure ID as the prefix of the names of
+	// the VMs in the pool, e.g. to follow site naming conventions.
+	// The VMs are named <namePrefix>-<pool name>-<index or random suffix>.
+	// +optional
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// InfraKubeconfigPath is the kubeconfig file used to access the infra cluster the VMs of
+	// the pool are placed in, when it is another infra cluster than the platform one. The
+	// VMs are then created in the platform namespace of that infra cluster.
+	// Only supported for compute pools.
+	// +optional
+	InfraKubeconfigPath string `json:"infraKubeconfigPath,omitempty"`
+
+	// InfraContext is the context of the kubeconfig used to access the infra cluster of the
+	// pool. Defaults to the current-context of the kubeconfig.
+	// Only supported for compute pools.
+	// +optional
+	InfraContext string `json:"infraContext,omitempty"`
+
+	// InfraCABundle is an additional bundle of PEM-encoded CA certificates trusted when the
+	// installer connects to the infra cluster of the pool, either the inline PEM or the path
+	// of a file holding it. Only used with InfraKubeconfigPath or InfraContext.
+	// Only supported for compute pools.
+	// +optional
+	InfraCABundle string `json:"infraCABundle,omitempty"`
+
+	// MemoryOverhead is the memory the virt-launcher pods of the VMs may use on top of
+	// the memory of the VMs, set as the memory limit of the VMs to cap their footprint
+	// in the infra cluster.
+	// Format: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/apimachinery/pkg/api/resource/quantity.go
+	// Only supported for the control plane pool.
+	// +optional
+	MemoryOverhead string `json:"memoryOverhead,omitempty"`
+
+	// OvercommitGuestOverhead makes the virt-launcher pods not request the memory
+	// overhead of the guest management from the scheduler, only adding it to their
+	// memory limit, so that more VMs fit the infra cluster nodes.
+	// Only supported for the control plane pool.
+	// +optional
+	OvercommitGuestOverhead bool `json:"overcommitGuestOverhead,omitempty"`
+
+	// SpreadPolicy is the scheduling policy of the VMs of the pool across the infra cluster
+	// nodes: Spread requires them to run on different nodes, Pack prefers running them on
+	// the same nodes and None sets no preference. When unset, running them on different
+	// nodes is preferred but not required.
+	// Only supported for the control plane pool.
+	// +optional
+	SpreadPolicy SpreadPolicy `json:"spreadPolicy,omitempty"`
+
+	// DiskBus is the type of disk device emulated for the boot disk of the VMs, one of
+	// virtio, sata or scsi. Defaults to virtio.
+	// Only supported for the control plane pool.
+	// +optional
+	DiskBus DiskBus `json:"diskBus,omitempty"`
+
+	// EtcdDisk adds a dedicated disk to the VMs, mounted at /var/lib/etcd, so that etcd
+	// does not share the I/O of the boot disk.
+	// Only supported for the control plane pool.
+	// +optional
+	EtcdDisk *EtcdDisk `json:"etcdDisk,omitempty"`
+
+	// CPUModel is the CPU model of the VMs: host-passthrough, host-model or a named model
+	// such as Haswell-noTSX. A named model keeps the VMs live migratable across the infra
+	// cluster nodes supporting it. Defaults to the CPU model of the infra cluster.
+	// Only supported for the control plane pool.
+	// +optional
+	CPUModel string `json:"cpuModel,omitempty"`
+
+	// CPUFeatures are the CPU features enabled or disabled in the VMs on top of the CPU model,
+	// e.g. vmx for nested virtualization.
+	// Only supported for the control plane pool.
+	// +optional
+	CPUFeatures []CPUFeature `json:"cpuFeatures,omitempty"`
+
+	// Hugepages backs the whole memory of the VMs with hugepages of the infra cluster nodes,
+	// which must advertise the hugepages of the page size as allocatable resources.
+	// Only supported for the control plane pool.
+	// +optional
+	Hugepages *Hugepages `json:"hugepages,omitempty"`
+
+	// RunStrategy is how the infra cluster runs the VMs of the pool: Always restarts them
+	// whenever they stop, RerunOnFailure only when their guest fails and Manual never.
+	// Defaults to the run strategy of the platform.
+	// Only supported for the control plane pool.
+	// +optional
+	RunStrategy RunStrategy `json:"runStrategy,omitempty"`
+
+	// TerminationGracePeriodSeconds is how long the guests of the VMs are given to shut down
+	// cleanly when the VMs are stopped or deleted, e.g. for the etcd members of the control
+	// plane to leave their cluster, before they are forcibly terminated. Defaults to the
+	// grace period of the infra cluster.
+	// Only supported for the control plane pool.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the infra cluster the virt-launcher pods of
+	// the VMs are scheduled with, e.g. for the control plane to preempt less important
+	// workloads when the infra cluster is short of resources. Defaults to the default
+	// priority of the infra cluster.
+	// Only supported for the control plane pool.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ProviderSpecOverrides is deep-merged into the KubevirtMachineProviderSpec generated for
+	// the machines of the pool, for the fields of the machine-api provider the installer does
+	// not model yet. Objects are merged key by key, any other value replaces the generated
+	// one and null removes it. The apiVersion and kind of the provider spec can't be
+	// overridden. Use with care, the fields unknown to the installer are passed to the
+	// provider unchecked.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ProviderSpecOverrides map[string]interface{} `json:"providerSpecOverrides,omitempty"`
+}
+
+// EtcdDisk is the dedicated etcd disk of the VMs of a machine pool.
+type EtcdDisk struct {
+	// Size is the size of the disk, at least 10Gi.
+	// Format: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/apimachinery/pkg/api/resource/quantity.go
+	Size string `json:"size"`
+
+	// StorageClass is the storage class of the DataVolume of the disk in the infra cluster.
+	// Defaults to the platform storage class.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
 }
 
 // Set sets the values from `required` to `p`.
@@ -35,4 +269,103 @@ func (p *MachinePool) Set(required *MachinePool) {
 	if required.StorageSize != "" {
 		p.StorageSize = required.StorageSize
 	}
+
+	if required.NamePrefix != "" {
+		p.NamePrefix = required.NamePrefix
+	}
+
+	if required.InfraKubeconfigPath != "" {
+		p.InfraKubeconfigPath = required.InfraKubeconfigPath
+	}
+
+	if required.InfraContext != "" {
+		p.InfraContext = required.InfraContext
+	}
+
+	if required.InfraCABundle != "" {
+		p.InfraCABundle = required.InfraCABundle
+	}
+
+	if required.MemoryOverhead != "" {
+		p.MemoryOverhead = required.MemoryOverhead
+	}
+
+	if required.OvercommitGuestOverhead {
+		p.OvercommitGuestOverhead = required.OvercommitGuestOverhead
+	}
+
+	if required.SpreadPolicy != "" {
+		p.SpreadPolicy = required.SpreadPolicy
+	}
+
+	if required.DiskBus != "" {
+		p.DiskBus = required.DiskBus
+	}
+
+	if required.EtcdDisk != nil {
+		p.EtcdDisk = required.EtcdDisk
+	}
+
+	if required.CPUModel != "" {
+		p.CPUModel = required.CPUModel
+	}
+
+	if len(required.CPUFeatures) > 0 {
+		p.CPUFeatures = required.CPUFeatures
+	}
+
+	if required.Hugepages != nil {
+		p.Hugepages = required.Hugepages
+	}
+
+	if required.RunStrategy != "" {
+		p.RunStrategy = required.RunStrategy
+	}
+
+	if required.TerminationGracePeriodSeconds != nil {
+		p.TerminationGracePeriodSeconds = required.TerminationGracePeriodSeconds
+	}
+
+	if required.PriorityClassName != "" {
+		p.PriorityClassName = required.PriorityClassName
+	}
+
+	if required.ProviderSpecOverrides != nil {
+		p.ProviderSpecOverrides = required.ProviderSpecOverrides
+	}
+}
+
+// HasInfraCluster returns whether the VMs of the pool are placed in another infra cluster than
+// the platform one.
+func (p *MachinePool) HasInfraCluster() bool {
+	return p != nil && (p.InfraKubeconfigPath != "" || p.InfraContext != "")
+}
+
+// ResourceNamePrefix returns the prefix of the names of the VMs of the pool, and of their
+// DataVolumes and secrets: its name prefix or, when unset, the infra ID of the cluster.
+func (p *MachinePool) ResourceNamePrefix(infraID string) string {
+	if p != nil && p.NamePrefix != "" {
+		return p.NamePrefix
+	}
+	return infraID
+}
+
+// ResourceName returns the name of the VM with the index in the machine pool of the role (e.g.
+// master), named after the name prefix of the pool. The names of its DataVolumes and secrets
+// start with it, and so does the name of the machine and MachineSet of the pool.
+func ResourceName(prefix string, role string, index int) string {
+	return fmt.Sprintf("%s-%s-%d", prefix, role, index)
+}
+
+// VMMemory returns the memory of the VMs of the pool: its memory or, when unset, the memory of
+// its hugepages.
+func (p *MachinePool) VMMemory() string {
+	if p.Memory != "" || p.Hugepages == nil || p.Hugepages.Count == 0 {
+		return p.Memory
+	}
+	pageSize, err := resource.ParseQuantity(p.Hugepages.PageSize)
+	if err != nil {
+		return p.Memory
+	}
+	return resource.NewQuantity(pageSize.Value()*int64(p.Hugepages.Count), resource.BinarySI).String()
 }
@@ -0,0 +1,35 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceName(t *testing.T) {
+	cases := []struct {
+		name     string
+		pool     *MachinePool
+		expected string
+	}{
+		{
+			name:     "no pool",
+			expected: "infra-id-master-1",
+		},
+		{
+			name:     "no name prefix",
+			pool:     &MachinePool{CPU: 4},
+			expected: "infra-id-master-1",
+		},
+		{
+			name:     "name prefix",
+			pool:     &MachinePool{NamePrefix: "site-a"},
+			expected: "site-a-master-1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ResourceName(tc.pool.ResourceNamePrefix("infra-id"), "master", 1))
+		})
+	}
+}
@@ -1,7 +1,78 @@
 package kubevirt
 
-// Metadata contains kubevirt metadata (e.g. for uninstalling the cluster).
+import "fmt"
+
+// OwnerLabelValue is the value of the owner label of the infra cluster resources of a cluster.
+const OwnerLabelValue = "owned"
+
+// OwnerLabel returns the key of the label set, by the installer and the machine-api, on the
+// infra cluster resources of the cluster with the given infra ID.
+func OwnerLabel(infraID string) string {
+	return fmt.Sprintf("tenantcluster-%s-machine.openshift.io", infraID)
+}
+
+// OwnerLabels returns the labels of the infra cluster resources of the cluster with the given
+// infra ID.
+func OwnerLabels(infraID string) map[string]string {
+	return map[string]string{OwnerLabel(infraID): OwnerLabelValue}
+}
+
+// Metadata contains kubevirt metadata (e.g. for uninstalling the cluster). It is the kubevirt
+// section of metadata.json.
 type Metadata struct {
-	Namespace string            `json:"namespace"`
-	Labels    map[string]string `json:"labels"`
+	// Namespace is the namespace of the infra cluster the cluster is installed in.
+	Namespace string `json:"namespace"`
+	// Labels are the labels of the infra cluster resources of the cluster, which carry any
+	// of them.
+	Labels map[string]string `json:"labels"`
+	// InfraKubeconfigPath is the kubeconfig file used to access the infra cluster.
+	InfraKubeconfigPath string `json:"infraKubeconfigPath,omitempty"`
+	// InfraContext is the context of the kubeconfig used to access the infra cluster.
+	InfraContext string `json:"infraContext,omitempty"`
+	// InfraCABundle is the additional CA bundle trusted when connecting to the infra cluster,
+	// either the inline PEM or the path of a file holding it.
+	InfraCABundle string `json:"infraCABundle,omitempty"`
+	// InfraClusters are the infra clusters, besides the one above, which the VMs of machine
+	// pools with their own infra cluster were placed in.
+	InfraClusters []InfraCluster `json:"infraClusters,omitempty"`
+	// DataVolumeTags are the tags of the DataVolumes of the cluster, which are deleted with it
+	// when they carry its infra ID and the tags, even without its labels.
+	DataVolumeTags *DataVolumeTags `json:"dataVolumeTags,omitempty"`
+	// Machines are the infra cluster nodes and zones the VMs of the cluster were scheduled to,
+	// looked up once they were created.
+	Machines []MachinePlacement `json:"machines,omitempty"`
+}
+
+// Owns returns whether a resource with the given labels belongs to the cluster, carrying any
+// of the labels of the metadata.
+func (m *Metadata) Owns(labels map[string]string) bool {
+	for key, value := range m.Labels {
+		if existing, ok := labels[key]; ok && existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLabelKey returns whether the given labels hold any of the label keys of the metadata,
+// whatever their values.
+func (m *Metadata) HasLabelKey(labels map[string]string) bool {
+	for key := range m.Labels {
+		if _, ok := labels[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MachinePlacement is the infra cluster node and zone a VM of the cluster was scheduled to.
+type MachinePlacement struct {
+	// Name is the name of the VM.
+	Name string `json:"name"`
+	// Namespace is the namespace of the VM.
+	Namespace string `json:"namespace"`
+	// Node is the infra cluster node the VM was scheduled to.
+	Node string `json:"node,omitempty"`
+	// Zone is the zone of the node, from its topology.kubernetes.io/zone label.
+	Zone string `json:"zone,omitempty"`
 }
@@ -0,0 +1,48 @@
+package kubevirt
+
+import (
+	"testing"
+
+	kubevirtutils "github.com/openshift/cluster-api-provider-kubevirt/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerLabels(t *testing.T) {
+	// The labels must match the ones set by the machine-api on the VMs of the compute pools
+	assert.Equal(t, kubevirtutils.BuildLabels("test-infra-id"), OwnerLabels("test-infra-id"))
+}
+
+func TestMetadataOwns(t *testing.T) {
+	m := &Metadata{Labels: OwnerLabels("test-infra-id")}
+	cases := []struct {
+		name     string
+		labels   map[string]string
+		owns     bool
+		hasLabel bool
+	}{
+		{
+			name:     "owner label",
+			labels:   map[string]string{OwnerLabel("test-infra-id"): OwnerLabelValue, "other": "label"},
+			owns:     true,
+			hasLabel: true,
+		},
+		{
+			name:     "owner label with another value",
+			labels:   map[string]string{OwnerLabel("test-infra-id"): "shared"},
+			hasLabel: true,
+		},
+		{
+			name:   "other cluster",
+			labels: OwnerLabels("other-infra-id"),
+		},
+		{
+			name: "no labels",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.owns, m.Owns(tc.labels))
+			assert.Equal(t, tc.hasLabel, m.HasLabelKey(tc.labels))
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package kubevirt
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Platform stores all the global configuration that all
 // machinesets use.
 type Platform struct {
@@ -13,6 +18,44 @@ type Platform struct {
 	// NetworkName is the target network of all the network interfaces of the nodes.
 	NetworkName string `json:"networkName"`
 
+	// CreateNetwork is the network-attachment-definition created by the installer in the
+	// namespace when the network named NetworkName doesn't exist in it. The created network is
+	// labeled with the cluster and deleted with it on destroy; an existing network is used as
+	// is and never modified.
+	// +optional
+	CreateNetwork *NetworkTemplate `json:"createNetwork,omitempty"`
+
+	// NetworkProbe, when set, runs a short-lived pod attached to the network before the VMs of
+	// the cluster are created, checking that the network hands out addresses and reaches its
+	// gateway and DNS server. The pod requires the NET_ADMIN and NET_RAW capabilities.
+	// +optional
+	NetworkProbe *NetworkProbe `json:"networkProbe,omitempty"`
+
+	// ImportTuning limits the resources used to import the RHCOS image into the infra cluster,
+	// so that installs on shared infra clusters don't saturate their storage.
+	// +optional
+	ImportTuning *ImportTuning `json:"importTuning,omitempty"`
+
+	// DataVolumeTags, when set, tags the DataVolumes created by the installer with annotations,
+	// so that the storage operators of the infra cluster can tell the disks of the tenant
+	// clusters apart, e.g. to prioritize or reclaim them.
+	// +optional
+	DataVolumeTags *DataVolumeTags `json:"dataVolumeTags,omitempty"`
+
+	// WorkerIgnitionServer, when set, serves the worker pointer ignition config from a Service
+	// of the platform namespace, so that compute VMs created outside the machine-api, e.g. by
+	// day-2 scale-out tooling, can fetch it without reaching the machine config server of the
+	// cluster. The Service and its Deployment and Secret are deleted with the cluster.
+	// +optional
+	WorkerIgnitionServer *WorkerIgnitionServer `json:"workerIgnitionServer,omitempty"`
+
+	// MirrorRegistry configures the nodes of the cluster for the mirror registries of the
+	// imageContentSources of disconnected installs, with MachineConfigs installing the
+	// containers registries.conf drop-in of the mirrors and the CA of the mirror registries.
+	// It is set by default whenever imageContentSources is set.
+	// +optional
+	MirrorRegistry *MirrorRegistry `json:"mirrorRegistry,omitempty"`
+
 	// APIVIP is an IP which will be served by bootstrap and then pivoted masters, using keepalived
 	APIVIP string `json:"apiVIP"`
 
@@ -21,4 +64,399 @@ type Platform struct {
 
 	// PersistentVolumeAccessMode is the access mode should be use with the persistent volumes
 	PersistentVolumeAccessMode string `json:"persistentVolumeAccessMode,omitempty"`
+
+	// VIPsInUseCheck enables probing the APIVIP and IngressVIP on the infra network before
+	// the installation, from a short-lived pod attached to the network-attachment-definition,
+	// failing the validation if a host of the network already answers ARP for any of them.
+	// The pod uses the image of the network probe, when set.
+	// +optional
+	VIPsInUseCheck bool `json:"vipsInUseCheck,omitempty"`
+
+	// NetworkIsolation creates a MultiNetworkPolicy for the network of the cluster, only allowing
+	// ingress traffic to the bootstrap and control plane VMs from the machine networks of the
+	// cluster, besides the API and ingress ports, isolating them from the other tenants of the
+	// network. The infra cluster must serve and enforce the MultiNetworkPolicies.
+	// +optional
+	NetworkIsolation bool `json:"networkIsolation,omitempty"`
+
+	// InfraKubeconfigPath is the kubeconfig file used to access the infra cluster.
+	// Defaults to the first file of KUBECONFIG, or to ~/.kube/config.
+	// +optional
+	InfraKubeconfigPath string `json:"infraKubeconfigPath,omitempty"`
+
+	// InfraContext is the context of the kubeconfig used to access the infra cluster.
+	// Defaults to the current-context of the kubeconfig.
+	// +optional
+	InfraContext string `json:"infraContext,omitempty"`
+
+	// InfraCABundle is an additional bundle of PEM-encoded CA certificates trusted, besides
+	// the certificate authority of the kubeconfig, when the installer connects to the infra
+	// cluster, e.g. the CA of a re-encrypting proxy in front of it. It is either the inline
+	// PEM or the path of a file holding it.
+	// +optional
+	InfraCABundle string `json:"infraCABundle,omitempty"`
+
+	// NamespaceScopedCredentials declares that the infra cluster credentials are only granted
+	// access to the namespaces of the cluster. The validations of cluster-scoped resources,
+	// such as the storage class, the namespaces and the KubeVirt version, are then skipped
+	// unless a SelfSubjectAccessReview shows that the credentials are allowed to run them.
+	// +optional
+	NamespaceScopedCredentials bool `json:"namespaceScopedCredentials,omitempty"`
+
+	// BootstrapIgnitionURL is the HTTP(S) URL the bootstrap Ignition config is uploaded to,
+	// with a PUT request, before the bootstrap VM is created. The bootstrap VM then fetches
+	// its config from the URL instead of having it embedded in its user data, working around
+	// the size limits of the infra cluster secrets for the configs too large to be embedded
+	// even compressed with gzip, as they are when they don't fit uncompressed. The URL must be
+	// reachable from the infra network and accept both the PUT and the GET requests, e.g. an
+	// object storage bucket or a WebDAV server. The uploaded config holds the cluster secrets
+	// and is not deleted by the installer.
+	// +optional
+	BootstrapIgnitionURL string `json:"bootstrapIgnitionURL,omitempty"`
+
+	// Bootstrap is the size of the bootstrap VM, e.g. smaller than the masters on resource
+	// constrained infra clusters. The unset fields default to 4 CPUs, 8G of memory and 35Gi
+	// of storage.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+
+	// ServiceAccount is the service account of the infra cluster whose bound, short-lived
+	// tokens the cluster uses to access the infra cluster, instead of the credentials of the
+	// infra kubeconfig. The installer requests the first token, which a CronJob of the
+	// cluster then renews before it expires. The service account must be allowed to create
+	// tokens for itself, i.e. to create its serviceaccounts/token subresource.
+	// +optional
+	ServiceAccount *ServiceAccountReference `json:"serviceAccount,omitempty"`
+
+	// NodeLabels map labels of the infra cluster nodes to labels of the tenant cluster nodes
+	// running on them. The machine-api provider sets the tenant labels of the nodes to the
+	// values of the infra labels of the nodes running their VMs, and the zone and region of the
+	// nodes are read from the infra labels mapped to topology.kubernetes.io/zone and
+	// topology.kubernetes.io/region, which default to the same labels. At least one infra
+	// cluster node must have each infra label.
+	// +optional
+	NodeLabels []NodeLabelMapping `json:"nodeLabels,omitempty"`
+
+	// RunStrategy is how the infra cluster runs the bootstrap VM and the control plane VMs
+	// which don't set their own: Always restarts them whenever they stop, RerunOnFailure
+	// only when their guest fails and Manual never. Defaults to Always. It does not apply
+	// to the compute VMs, which are created by the machine-api provider.
+	// +optional
+	RunStrategy RunStrategy `json:"runStrategy,omitempty"`
+}
+
+// NetworkType is the CNI plugin of a network created by the installer.
+// +kubebuilder:validation:Enum="";bridge;ovn
+type NetworkType string
+
+const (
+	// NetworkTypeBridge connects the VMs to a Linux bridge of the infra cluster nodes.
+	NetworkTypeBridge NetworkType = "bridge"
+	// NetworkTypeOVN connects the VMs to an OVN-Kubernetes secondary network, a layer 2
+	// overlay or, with a VLAN, a localnet mapped to the physical network of the same name as
+	// the network.
+	NetworkTypeOVN NetworkType = "ovn"
+)
+
+// NetworkTemplate is the network-attachment-definition created by the installer.
+type NetworkTemplate struct {
+	// Type is the CNI plugin of the network.
+	// Defaults to bridge.
+	// +optional
+	Type NetworkType `json:"type,omitempty"`
+
+	// Bridge is the Linux bridge of the infra cluster nodes the VMs are connected to, required
+	// with the bridge type.
+	// +optional
+	Bridge string `json:"bridge,omitempty"`
+
+	// CIDR is the subnet of the network, which the addresses of the VMs are allocated from, and
+	// which must hold the APIVIP and the IngressVIP. Without it, the VMs get their addresses
+	// from a DHCP server of the network.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// VLAN is the VLAN ID of the network, from 1 to 4094.
+	// +optional
+	VLAN int `json:"vlan,omitempty"`
+}
+
+// DefaultNetworkProbeImage is the default image of the network probe pod.
+const DefaultNetworkProbeImage = "docker.io/library/busybox:1.33"
+
+// NetworkProbe is the probe of the network of the platform.
+type NetworkProbe struct {
+	// Image is the image of the probe pod, which must provide the busybox udhcpc, ip, arping
+	// and nslookup commands.
+	// Defaults to docker.io/library/busybox:1.33.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// DHCP checks that a DHCP server of the network offers an address to the probe.
+	// +optional
+	DHCP bool `json:"dhcp,omitempty"`
+
+	// Gateway is the IP address of the gateway of the network, checked to answer ARP requests.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// DNSServer is the IP address of a DNS server, checked to resolve DNSName from the network.
+	// +optional
+	DNSServer string `json:"dnsServer,omitempty"`
+
+	// DNSName is the name resolved by the DNS server, required with DNSServer.
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+}
+
+// DefaultWorkerIgnitionServerImage is the default image of the worker ignition server.
+const DefaultWorkerIgnitionServerImage = "docker.io/library/busybox:1.33"
+
+// WorkerIgnitionServer is the server of the worker pointer ignition config in the infra cluster.
+type WorkerIgnitionServer struct {
+	// Image is the image of the server pod, which must provide the busybox httpd command.
+	// Defaults to docker.io/library/busybox:1.33.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// MirrorRegistry is the configuration of the nodes for the mirror registries of the cluster.
+type MirrorRegistry struct {
+	// CABundle is the bundle of PEM-encoded CA certificates of the mirror registries, trusted by
+	// the container runtime of the nodes when pulling from the mirrors. Defaults to the
+	// additionalTrustBundle of the install-config.
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+}
+
+// The annotations of the DataVolumes tagged with DataVolumeTags.
+const (
+	// DataVolumeInfraIDAnnotation holds the infra ID of the cluster of the tagged DataVolumes.
+	DataVolumeInfraIDAnnotation = "installer.openshift.io/infra-id"
+	// DataVolumePriorityClassAnnotation holds the priority class of the DataVolume.
+	DataVolumePriorityClassAnnotation = "installer.openshift.io/priority-class"
+	// DataVolumeStorageOverheadAnnotation holds the storage overhead of the DataVolume.
+	DataVolumeStorageOverheadAnnotation = "installer.openshift.io/storage-overhead"
+	// DataVolumeExpectedLifetimeAnnotation holds the expected lifetime of the DataVolume.
+	DataVolumeExpectedLifetimeAnnotation = "installer.openshift.io/expected-lifetime"
+)
+
+// DataVolumeTags are the tags of the DataVolumes created by the installer.
+type DataVolumeTags struct {
+	// PriorityClass is the priority class of the DataVolumes, e.g. the one of the CDI pods
+	// importing and cloning them.
+	// +optional
+	PriorityClass string `json:"priorityClass,omitempty"`
+
+	// StorageOverhead is the fraction of the requested storage the storage of the DataVolumes
+	// needs on top of it, between 0 and 1, e.g. 0.055, as the filesystem overhead of CDI.
+	// +optional
+	StorageOverhead string `json:"storageOverhead,omitempty"`
+
+	// ExpectedLifetime is how long the DataVolumes are expected to live, e.g. 72h for the
+	// clusters of a CI job.
+	// +optional
+	ExpectedLifetime string `json:"expectedLifetime,omitempty"`
+}
+
+// Annotations returns the annotations of the DataVolumes of the cluster with the given infra
+// ID, nil when the DataVolumes are not tagged.
+func (t *DataVolumeTags) Annotations(infraID string) map[string]string {
+	if t == nil {
+		return nil
+	}
+	annotations := map[string]string{DataVolumeInfraIDAnnotation: infraID}
+	for key, value := range map[string]string{
+		DataVolumePriorityClassAnnotation:    t.PriorityClass,
+		DataVolumeStorageOverheadAnnotation:  t.StorageOverhead,
+		DataVolumeExpectedLifetimeAnnotation: t.ExpectedLifetime,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// ImportTuning is the resource tuning of the CDI imports of the installer.
+type ImportTuning struct {
+	// Requests are the resources requested by the CDI importer pod.
+	// +optional
+	Requests *ImportResources `json:"requests,omitempty"`
+
+	// Limits are the resource limits of the CDI importer pod.
+	// +optional
+	Limits *ImportResources `json:"limits,omitempty"`
+
+	// MaxConcurrentImports caps the number of infra cluster resources, and so of DataVolume
+	// imports and clones, the installer creates concurrently.
+	// Defaults to no cap.
+	// +optional
+	MaxConcurrentImports int `json:"maxConcurrentImports,omitempty"`
+}
+
+// ImportResources are the CPU and memory of a CDI importer pod, of type Quantity.
+type ImportResources struct {
+	// CPU is the CPU of the importer pod, e.g. 500m.
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the memory of the importer pod, e.g. 1Gi.
+	// +optional
+	Memory string `json:"memory,omitempty"`
+}
+
+// ServiceAccountReference references a service account of the infra cluster.
+type ServiceAccountReference struct {
+	// Namespace is the namespace of the service account.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the service account.
+	Name string `json:"name"`
+}
+
+// The default size of the bootstrap VM.
+const (
+	DefaultBootstrapCPU     = 4
+	DefaultBootstrapMemory  = "8G"
+	DefaultBootstrapStorage = "35Gi"
+)
+
+// Bootstrap is the size of the bootstrap VM.
+type Bootstrap struct {
+	// CPU is the number of CPUs of the bootstrap VM.
+	// +optional
+	CPU uint32 `json:"cpu,omitempty"`
+
+	// Memory is the size of the memory of the bootstrap VM.
+	// Format: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/apimachinery/pkg/api/resource/quantity.go
+	// +optional
+	Memory string `json:"memory,omitempty"`
+
+	// Storage is the size of the boot volume of the bootstrap VM.
+	// Format: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/apimachinery/pkg/api/resource/quantity.go
+	// +optional
+	Storage string `json:"storage,omitempty"`
+}
+
+// BootstrapVM returns the size of the bootstrap VM as a machine pool, with the defaults of
+// the unset fields.
+func (p *Platform) BootstrapVM() MachinePool {
+	vm := MachinePool{CPU: DefaultBootstrapCPU, Memory: DefaultBootstrapMemory, StorageSize: DefaultBootstrapStorage}
+	if b := p.Bootstrap; b != nil {
+		if b.CPU != 0 {
+			vm.CPU = b.CPU
+		}
+		if b.Memory != "" {
+			vm.Memory = b.Memory
+		}
+		if b.Storage != "" {
+			vm.StorageSize = b.Storage
+		}
+	}
+	return vm
+}
+
+// NodeLabelMapping maps a label of the infra cluster nodes to a label of the tenant cluster nodes.
+type NodeLabelMapping struct {
+	// InfraLabel is the label of the infra cluster nodes, e.g. topology.kubernetes.io/zone.
+	InfraLabel string `json:"infraLabel"`
+
+	// TenantLabel is the label of the tenant cluster nodes set to the value of InfraLabel.
+	// Defaults to InfraLabel.
+	// +optional
+	TenantLabel string `json:"tenantLabel,omitempty"`
+}
+
+// TenantNodeLabel returns the label of the tenant cluster nodes of the mapping.
+func (m NodeLabelMapping) TenantNodeLabel() string {
+	if m.TenantLabel != "" {
+		return m.TenantLabel
+	}
+	return m.InfraLabel
+}
+
+// InfraNodeLabel returns the label of the infra cluster nodes mapped to the label of the tenant
+// cluster nodes, the same label when none is.
+func (p *Platform) InfraNodeLabel(tenantLabel string) string {
+	for _, mapping := range p.NodeLabels {
+		if mapping.TenantNodeLabel() == tenantLabel {
+			return mapping.InfraLabel
+		}
+	}
+	return tenantLabel
+}
+
+// IsInlinePEM returns whether the CA bundle is an inline PEM rather than the path of a file.
+func IsInlinePEM(caBundle string) bool {
+	return strings.HasPrefix(strings.TrimSpace(caBundle), "-----BEGIN")
+}
+
+// MachinePoolRunStrategy returns the run strategy of the VMs of the machine pool, which is the
+// platform one unless overridden by the pool, Always when neither sets it. A nil pool returns
+// the run strategy of the bootstrap VM.
+func (p *Platform) MachinePoolRunStrategy(pool *MachinePool) RunStrategy {
+	if pool != nil && pool.RunStrategy != "" {
+		return pool.RunStrategy
+	}
+	if p.RunStrategy != "" {
+		return p.RunStrategy
+	}
+	return RunStrategyAlways
+}
+
+// InfraCluster is an infra cluster the VMs of the cluster are placed in, with the namespace of
+// the cluster in it.
+type InfraCluster struct {
+	// KubeconfigPath is the kubeconfig file used to access the infra cluster.
+	// +optional
+	KubeconfigPath string `json:"kubeconfigPath,omitempty"`
+
+	// Context is the context of the kubeconfig used to access the infra cluster.
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// CABundle is the additional CA bundle trusted when connecting to the infra cluster,
+	// either the inline PEM or the path of a file holding it.
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// Namespace is the namespace of the cluster in the infra cluster.
+	Namespace string `json:"namespace"`
+}
+
+// Endpoint returns the infra cluster without its namespace, identifying the connection to it.
+func (c InfraCluster) Endpoint() InfraCluster {
+	c.Namespace = ""
+	return c
+}
+
+// InfraCluster returns the infra cluster of the platform.
+func (p *Platform) InfraCluster() InfraCluster {
+	return InfraCluster{
+		KubeconfigPath: p.InfraKubeconfigPath,
+		Context:        p.InfraContext,
+		CABundle:       p.InfraCABundle,
+		Namespace:      p.Namespace,
+	}
+}
+
+// MachinePoolInfraCluster returns the infra cluster the VMs of the machine pool are placed in,
+// which is the platform one unless the pool has its own.
+func (p *Platform) MachinePoolInfraCluster(pool *MachinePool) InfraCluster {
+	if !pool.HasInfraCluster() {
+		return p.InfraCluster()
+	}
+	return InfraCluster{
+		KubeconfigPath: pool.InfraKubeconfigPath,
+		Context:        pool.InfraContext,
+		CABundle:       pool.InfraCABundle,
+		Namespace:      p.Namespace,
+	}
+}
+
+// CredentialsSecretName returns the name of the secret, in the openshift-machine-api namespace,
+// holding the kubeconfig of the infra cluster of the machine pool with its own infra cluster.
+func CredentialsSecretName(poolName string) string {
+	return fmt.Sprintf("kubevirt-credentials-%s", poolName)
 }
@@ -0,0 +1,90 @@
+package kubevirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachinePoolInfraCluster(t *testing.T) {
+	platform := &Platform{
+		Namespace:           "ns",
+		InfraKubeconfigPath: "/kubeconfig-a",
+		InfraContext:        "infra-a",
+		InfraCABundle:       "/ca-a.pem",
+	}
+	platformInfraCluster := InfraCluster{KubeconfigPath: "/kubeconfig-a", Context: "infra-a", CABundle: "/ca-a.pem", Namespace: "ns"}
+	cases := []struct {
+		name     string
+		pool     *MachinePool
+		expected InfraCluster
+	}{
+		{
+			name:     "no pool",
+			expected: platformInfraCluster,
+		},
+		{
+			name:     "platform infra cluster",
+			pool:     &MachinePool{CPU: 4},
+			expected: platformInfraCluster,
+		},
+		{
+			name:     "pool infra cluster",
+			pool:     &MachinePool{InfraKubeconfigPath: "/kubeconfig-b"},
+			expected: InfraCluster{KubeconfigPath: "/kubeconfig-b", Namespace: "ns"},
+		},
+		{
+			name:     "pool infra context",
+			pool:     &MachinePool{InfraContext: "infra-b", InfraCABundle: "/ca-b.pem"},
+			expected: InfraCluster{Context: "infra-b", CABundle: "/ca-b.pem", Namespace: "ns"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, platform.MachinePoolInfraCluster(tc.pool))
+		})
+	}
+}
+
+func TestInfraClusterEndpoint(t *testing.T) {
+	a := InfraCluster{KubeconfigPath: "/kubeconfig", Namespace: "ns-a"}
+	b := InfraCluster{KubeconfigPath: "/kubeconfig", Namespace: "ns-b"}
+	assert.Equal(t, a.Endpoint(), b.Endpoint())
+	assert.Empty(t, a.Endpoint().Namespace)
+}
+
+func TestInfraNodeLabel(t *testing.T) {
+	platform := &Platform{NodeLabels: []NodeLabelMapping{
+		{InfraLabel: "example.com/rack", TenantLabel: "topology.kubernetes.io/zone"},
+		{InfraLabel: "example.com/room"},
+	}}
+	assert.Equal(t, "example.com/rack", platform.InfraNodeLabel("topology.kubernetes.io/zone"))
+	assert.Equal(t, "example.com/room", platform.InfraNodeLabel("example.com/room"))
+	assert.Equal(t, "topology.kubernetes.io/region", platform.InfraNodeLabel("topology.kubernetes.io/region"))
+}
+
+func TestBootstrapVM(t *testing.T) {
+	platform := &Platform{}
+	assert.Equal(t, MachinePool{CPU: 4, Memory: "8G", StorageSize: "35Gi"}, platform.BootstrapVM())
+	platform.Bootstrap = &Bootstrap{CPU: 2, Memory: "6Gi"}
+	assert.Equal(t, MachinePool{CPU: 2, Memory: "6Gi", StorageSize: "35Gi"}, platform.BootstrapVM())
+}
+
+func TestMachinePoolRunStrategy(t *testing.T) {
+	platform := &Platform{}
+	assert.Equal(t, RunStrategyAlways, platform.MachinePoolRunStrategy(nil))
+	platform.RunStrategy = RunStrategyRerunOnFailure
+	assert.Equal(t, RunStrategyRerunOnFailure, platform.MachinePoolRunStrategy(nil))
+	assert.Equal(t, RunStrategyRerunOnFailure, platform.MachinePoolRunStrategy(&MachinePool{}))
+	assert.Equal(t, RunStrategyManual, platform.MachinePoolRunStrategy(&MachinePool{RunStrategy: RunStrategyManual}))
+}
+
+func TestDataVolumeTagsAnnotations(t *testing.T) {
+	var none *DataVolumeTags
+	assert.Nil(t, none.Annotations("infra-id"))
+	assert.Equal(t, map[string]string{
+		DataVolumeInfraIDAnnotation:          "infra-id",
+		DataVolumePriorityClassAnnotation:    "tenant-low",
+		DataVolumeExpectedLifetimeAnnotation: "72h",
+	}, (&DataVolumeTags{PriorityClass: "tenant-low", ExpectedLifetime: "72h"}).Annotations("infra-id"))
+}
@@ -1,6 +1,12 @@
 package validation
 
 import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/types/kubevirt"
@@ -23,12 +29,225 @@ func ValidateMachinePool(p *kubevirt.MachinePool, fldPath *field.Path) field.Err
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("storage"), p.StorageSize, "Storage size must be positive value"))
 	}
 
-	memoryQuantity, err := resource.ParseQuantity(p.Memory)
+	// The memory of the VMs is set by the count of their hugepages when unset
+	memoryQuantity, err := resource.ParseQuantity(p.VMMemory())
 	if err != nil {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), p.Memory, "Memory must be of Quantity type format"))
+		if p.Memory != "" || p.Hugepages == nil || p.Hugepages.Count == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), p.Memory, "Memory must be of Quantity type format"))
+		}
 	} else if memoryQuantity.Sign() != 1 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), p.Memory, "Memory must be positive value"))
 	}
 
+	if p.MemoryOverhead != "" {
+		overheadQuantity, err := resource.ParseQuantity(p.MemoryOverhead)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryOverhead"), p.MemoryOverhead, "Memory overhead must be of Quantity type format"))
+		case overheadQuantity.Sign() == -1:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryOverhead"), p.MemoryOverhead, "Memory overhead must not be negative"))
+		case memoryQuantity.Sign() == 1 && overheadQuantity.Cmp(memoryQuantity) > 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryOverhead"), p.MemoryOverhead, "Memory overhead must not exceed the memory of the VMs"))
+		}
+	}
+
+	switch p.SpreadPolicy {
+	case "", kubevirt.SpreadPolicySpread, kubevirt.SpreadPolicyPack, kubevirt.SpreadPolicyNone:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("spreadPolicy"), p.SpreadPolicy, []string{string(kubevirt.SpreadPolicySpread), string(kubevirt.SpreadPolicyPack), string(kubevirt.SpreadPolicyNone)}))
+	}
+
+	switch p.DiskBus {
+	case "", kubevirt.DiskBusVirtio, kubevirt.DiskBusSATA, kubevirt.DiskBusSCSI:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("diskBus"), p.DiskBus, []string{string(kubevirt.DiskBusVirtio), string(kubevirt.DiskBusSATA), string(kubevirt.DiskBusSCSI)}))
+	}
+
+	allErrs = append(allErrs, validateRunStrategy(p.RunStrategy, fldPath.Child("runStrategy"))...)
+
+	if p.TerminationGracePeriodSeconds != nil && *p.TerminationGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("terminationGracePeriodSeconds"), *p.TerminationGracePeriodSeconds, "Termination grace period must not be negative"))
+	}
+
+	if p.EtcdDisk != nil {
+		allErrs = append(allErrs, validateEtcdDisk(p.EtcdDisk, fldPath.Child("etcdDisk"))...)
+	}
+
+	if p.CPUModel != "" && !cpuModels.Has(p.CPUModel) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cpuModel"), p.CPUModel, cpuModels.List()))
+	}
+
+	allErrs = append(allErrs, validateCPUFeatures(p.CPUFeatures, fldPath.Child("cpuFeatures"))...)
+
+	if p.Hugepages != nil {
+		allErrs = append(allErrs, validateHugepages(p.Hugepages, p.Memory, memoryQuantity, fldPath)...)
+	}
+
+	if p.NamePrefix != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(p.NamePrefix) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namePrefix"), p.NamePrefix, msg))
+		}
+	}
+
+	if p.PriorityClassName != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(p.PriorityClassName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("priorityClassName"), p.PriorityClassName, msg))
+		}
+	}
+
+	for _, key := range []string{"apiVersion", "kind"} {
+		if _, ok := p.ProviderSpecOverrides[key]; ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("providerSpecOverrides", key), "the type of the provider spec can't be overridden"))
+		}
+	}
+
+	if p.InfraCABundle != "" {
+		if !p.HasInfraCluster() {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("infraCABundle"), p.InfraCABundle, "is only supported with infraKubeconfigPath or infraContext"))
+		} else if kubevirt.IsInlinePEM(p.InfraCABundle) && !x509.NewCertPool().AppendCertsFromPEM([]byte(p.InfraCABundle)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("infraCABundle"), p.InfraCABundle, "must hold PEM-encoded certificates"))
+		}
+	}
+
+	return allErrs
+}
+
+// controlPlaneOnlyFields are the fields of the machine pools which only configure the VMs the installer
+// creates, the VMs of the compute machine pools are created by the machine-api provider which ignores them.
+var controlPlaneOnlyFields = []struct {
+	path   []string
+	isSet  func(p *kubevirt.MachinePool) bool
+	value  func(p *kubevirt.MachinePool) interface{}
+	reason string
+}{
+	{[]string{"memoryOverhead"}, func(p *kubevirt.MachinePool) bool { return p.MemoryOverhead != "" }, func(p *kubevirt.MachinePool) interface{} { return p.MemoryOverhead }, ""},
+	{[]string{"overcommitGuestOverhead"}, func(p *kubevirt.MachinePool) bool { return p.OvercommitGuestOverhead }, func(p *kubevirt.MachinePool) interface{} { return p.OvercommitGuestOverhead }, ""},
+	{[]string{"spreadPolicy"}, func(p *kubevirt.MachinePool) bool { return p.SpreadPolicy != "" }, func(p *kubevirt.MachinePool) interface{} { return p.SpreadPolicy }, ""},
+	{[]string{"diskBus"}, func(p *kubevirt.MachinePool) bool { return p.DiskBus != "" }, func(p *kubevirt.MachinePool) interface{} { return p.DiskBus }, ""},
+	{[]string{"etcdDisk"}, func(p *kubevirt.MachinePool) bool { return p.EtcdDisk != nil }, func(p *kubevirt.MachinePool) interface{} { return p.EtcdDisk.Size }, "etcd only runs on the control plane"},
+	{[]string{"cpuModel"}, func(p *kubevirt.MachinePool) bool { return p.CPUModel != "" }, func(p *kubevirt.MachinePool) interface{} { return p.CPUModel }, ""},
+	{[]string{"cpuFeatures"}, func(p *kubevirt.MachinePool) bool { return len(p.CPUFeatures) > 0 }, func(p *kubevirt.MachinePool) interface{} { return p.CPUFeatures[0].Name }, ""},
+	{[]string{"hugepages", "pageSize"}, func(p *kubevirt.MachinePool) bool { return p.Hugepages != nil }, func(p *kubevirt.MachinePool) interface{} { return p.Hugepages.PageSize }, ""},
+	{[]string{"runStrategy"}, func(p *kubevirt.MachinePool) bool { return p.RunStrategy != "" }, func(p *kubevirt.MachinePool) interface{} { return p.RunStrategy }, ""},
+	{[]string{"terminationGracePeriodSeconds"}, func(p *kubevirt.MachinePool) bool { return p.TerminationGracePeriodSeconds != nil }, func(p *kubevirt.MachinePool) interface{} { return *p.TerminationGracePeriodSeconds }, ""},
+	{[]string{"priorityClassName"}, func(p *kubevirt.MachinePool) bool { return p.PriorityClassName != "" }, func(p *kubevirt.MachinePool) interface{} { return p.PriorityClassName }, ""},
+}
+
+// ValidateComputeMachinePool checks that the specified compute machine pool doesn't set the fields
+// only supported by the control plane machine pool.
+func ValidateComputeMachinePool(p *kubevirt.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, f := range controlPlaneOnlyFields {
+		if !f.isSet(p) {
+			continue
+		}
+		reason := f.reason
+		if reason == "" {
+			reason = "their VMs are created by the machine-api provider"
+		}
+		allErrs = append(allErrs, field.Invalid(fldPath.Child(f.path[0], f.path[1:]...), f.value(p), fmt.Sprintf("compute machine pools do not support %s, %s", f.path[0], reason)))
+	}
+
+	return allErrs
+}
+
+// validateRunStrategy checks that the run strategy of the VMs is one the installer supports.
+func validateRunStrategy(runStrategy kubevirt.RunStrategy, fldPath *field.Path) field.ErrorList {
+	switch runStrategy {
+	case "", kubevirt.RunStrategyAlways, kubevirt.RunStrategyRerunOnFailure, kubevirt.RunStrategyManual:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fldPath, runStrategy, []string{string(kubevirt.RunStrategyAlways), string(kubevirt.RunStrategyRerunOnFailure), string(kubevirt.RunStrategyManual)})}
+	}
+}
+
+// cpuModels are the CPU models of the VMs supported, the host ones and the x86 models named by
+// libvirt which KubeVirt schedules the VMs on the infra cluster nodes supporting.
+var cpuModels = sets.NewString(
+	kubevirt.CPUModelHostPassthrough,
+	kubevirt.CPUModelHostModel,
+	"486", "pentium", "pentium2", "pentium3", "pentiumpro", "coreduo", "n270", "core2duo",
+	"qemu32", "kvm32", "cpu64-rhel5", "cpu64-rhel6", "qemu64", "kvm64",
+	"Conroe", "Penryn", "Nehalem", "Nehalem-IBRS", "Westmere", "Westmere-IBRS",
+	"SandyBridge", "SandyBridge-IBRS", "IvyBridge", "IvyBridge-IBRS",
+	"Haswell", "Haswell-noTSX", "Haswell-noTSX-IBRS", "Haswell-IBRS",
+	"Broadwell", "Broadwell-noTSX", "Broadwell-noTSX-IBRS", "Broadwell-IBRS",
+	"Skylake-Client", "Skylake-Client-IBRS", "Skylake-Client-noTSX-IBRS",
+	"Skylake-Server", "Skylake-Server-IBRS", "Skylake-Server-noTSX-IBRS",
+	"Cascadelake-Server", "Cascadelake-Server-noTSX", "Cooperlake",
+	"Icelake-Client", "Icelake-Client-noTSX", "Icelake-Server", "Icelake-Server-noTSX",
+	"Snowridge", "athlon", "phenom", "Opteron_G1", "Opteron_G2", "Opteron_G3", "Opteron_G4", "Opteron_G5",
+	"EPYC", "EPYC-IBPB", "EPYC-Rome", "Dhyana",
+)
+
+// cpuFeatureName matches the names of the CPU features, e.g. vmx or avx512f.
+var cpuFeatureName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+func validateCPUFeatures(features []kubevirt.CPUFeature, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := sets.NewString()
+	for i, feature := range features {
+		if !cpuFeatureName.MatchString(feature.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), feature.Name, "CPU feature name must consist of lower case alphanumeric characters, '_', '.' or '-'"))
+		} else if seen.Has(feature.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), feature.Name))
+		}
+		seen.Insert(feature.Name)
+
+		switch feature.Policy {
+		case "", kubevirt.CPUFeaturePolicyForce, kubevirt.CPUFeaturePolicyRequire, kubevirt.CPUFeaturePolicyOptional, kubevirt.CPUFeaturePolicyDisable, kubevirt.CPUFeaturePolicyForbid:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("policy"), feature.Policy, []string{string(kubevirt.CPUFeaturePolicyForce), string(kubevirt.CPUFeaturePolicyRequire), string(kubevirt.CPUFeaturePolicyOptional), string(kubevirt.CPUFeaturePolicyDisable), string(kubevirt.CPUFeaturePolicyForbid)}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateHugepages checks the page size of the hugepages and that they hold the memory of the
+// VMs exactly.
+func validateHugepages(h *kubevirt.Hugepages, memory string, memoryQuantity resource.Quantity, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if h.PageSize != kubevirt.HugepagesPageSize2Mi && h.PageSize != kubevirt.HugepagesPageSize1Gi {
+		return append(allErrs, field.NotSupported(fldPath.Child("hugepages", "pageSize"), h.PageSize, []string{kubevirt.HugepagesPageSize2Mi, kubevirt.HugepagesPageSize1Gi}))
+	}
+	if memoryQuantity.Sign() != 1 {
+		// The memory is validated separately
+		return allErrs
+	}
+
+	pageSize := resource.MustParse(h.PageSize)
+	switch {
+	case memory != "" && h.Count > 0 && memoryQuantity.Value() != pageSize.Value()*int64(h.Count):
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hugepages", "count"), h.Count, fmt.Sprintf("%d hugepages of %s do not match the memory %s, leave the memory unset for the hugepages to set it", h.Count, h.PageSize, memory)))
+	case memoryQuantity.Value()%pageSize.Value() != 0:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), memory, fmt.Sprintf("Memory must be a multiple of the hugepages page size %s", h.PageSize)))
+	}
+
+	return allErrs
+}
+
+// minEtcdDiskSize is the minimum size of the dedicated etcd disk.
+var minEtcdDiskSize = resource.MustParse("10Gi")
+
+func validateEtcdDisk(d *kubevirt.EtcdDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	sizeQuantity, err := resource.ParseQuantity(d.Size)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), d.Size, "Etcd disk size must be of Quantity type format"))
+	} else if sizeQuantity.Cmp(minEtcdDiskSize) < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), d.Size, "Etcd disk size must be at least 10Gi"))
+	}
+
+	if d.StorageClass != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(d.StorageClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storageClass"), d.StorageClass, msg))
+		}
+	}
+
 	return allErrs
 }
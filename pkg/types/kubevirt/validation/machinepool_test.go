@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 
 	"github.com/openshift/installer/pkg/types/kubevirt"
 )
@@ -105,6 +106,406 @@ func TestValidateMachinePool(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "valid name prefix",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				NamePrefix:  "cc1234-ocp",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid name prefix",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				NamePrefix:  "CC_1234",
+			},
+			valid: false,
+		},
+		{
+			name: "name prefix ending with dash",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				NamePrefix:  "ocp-",
+			},
+			valid: false,
+		},
+		{
+			name: "valid infra cluster",
+			pool: &kubevirt.MachinePool{
+				CPU:                 4,
+				Memory:              "5G",
+				StorageSize:         "100Gi",
+				InfraKubeconfigPath: "/kubeconfig-b",
+				InfraContext:        "infra-b",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid infra CA bundle without infra cluster",
+			pool: &kubevirt.MachinePool{
+				CPU:           4,
+				Memory:        "5G",
+				StorageSize:   "100Gi",
+				InfraCABundle: "/ca-b.pem",
+			},
+			valid: false,
+		},
+		{
+			name: "invalid inline infra CA bundle",
+			pool: &kubevirt.MachinePool{
+				CPU:           4,
+				Memory:        "5G",
+				StorageSize:   "100Gi",
+				InfraContext:  "infra-b",
+				InfraCABundle: "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----\n",
+			},
+			valid: false,
+		},
+		{
+			name: "valid memory overhead",
+			pool: &kubevirt.MachinePool{
+				CPU:            4,
+				Memory:         "5G",
+				StorageSize:    "100Gi",
+				MemoryOverhead: "512Mi",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid memory overhead",
+			pool: &kubevirt.MachinePool{
+				CPU:            4,
+				Memory:         "5G",
+				StorageSize:    "100Gi",
+				MemoryOverhead: "invalid string",
+			},
+			valid: false,
+		},
+		{
+			name: "negative memory overhead",
+			pool: &kubevirt.MachinePool{
+				CPU:            4,
+				Memory:         "5G",
+				StorageSize:    "100Gi",
+				MemoryOverhead: "-512Mi",
+			},
+			valid: false,
+		},
+		{
+			name: "memory overhead exceeding memory",
+			pool: &kubevirt.MachinePool{
+				CPU:            4,
+				Memory:         "5G",
+				StorageSize:    "100Gi",
+				MemoryOverhead: "6G",
+			},
+			valid: false,
+		},
+		{
+			name: "valid spread policy",
+			pool: &kubevirt.MachinePool{
+				CPU:          4,
+				Memory:       "5G",
+				StorageSize:  "100Gi",
+				SpreadPolicy: kubevirt.SpreadPolicySpread,
+			},
+			valid: true,
+		},
+		{
+			name: "invalid spread policy",
+			pool: &kubevirt.MachinePool{
+				CPU:          4,
+				Memory:       "5G",
+				StorageSize:  "100Gi",
+				SpreadPolicy: "Scatter",
+			},
+			valid: false,
+		},
+		{
+			name: "valid disk bus",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				DiskBus:     kubevirt.DiskBusSCSI,
+			},
+			valid: true,
+		},
+		{
+			name: "invalid disk bus",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				DiskBus:     "ide",
+			},
+			valid: false,
+		},
+		{
+			name: "valid run strategy",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				RunStrategy: kubevirt.RunStrategyManual,
+			},
+			valid: true,
+		},
+		{
+			name: "invalid run strategy",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				RunStrategy: "Once",
+			},
+			valid: false,
+		},
+		{
+			name: "valid termination grace period",
+			pool: &kubevirt.MachinePool{
+				CPU:                           4,
+				Memory:                        "5G",
+				StorageSize:                   "100Gi",
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(0),
+			},
+			valid: true,
+		},
+		{
+			name: "negative termination grace period",
+			pool: &kubevirt.MachinePool{
+				CPU:                           4,
+				Memory:                        "5G",
+				StorageSize:                   "100Gi",
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(-1),
+			},
+			valid: false,
+		},
+		{
+			name: "valid priority class name",
+			pool: &kubevirt.MachinePool{
+				CPU:               4,
+				Memory:            "5G",
+				StorageSize:       "100Gi",
+				PriorityClassName: "tenant-control-plane",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid priority class name",
+			pool: &kubevirt.MachinePool{
+				CPU:               4,
+				Memory:            "5G",
+				StorageSize:       "100Gi",
+				PriorityClassName: "Tenant_Control_Plane",
+			},
+			valid: false,
+		},
+		{
+			name: "valid provider spec overrides",
+			pool: &kubevirt.MachinePool{
+				CPU:                   4,
+				Memory:                "5G",
+				StorageSize:           "100Gi",
+				ProviderSpecOverrides: map[string]interface{}{"requestedStorage": "200Gi", "dedicatedCPUs": true},
+			},
+			valid: true,
+		},
+		{
+			name: "provider spec overrides of the kind",
+			pool: &kubevirt.MachinePool{
+				CPU:                   4,
+				Memory:                "5G",
+				StorageSize:           "100Gi",
+				ProviderSpecOverrides: map[string]interface{}{"kind": "OtherProviderSpec"},
+			},
+			valid: false,
+		},
+		{
+			name: "valid etcd disk",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				EtcdDisk:    &kubevirt.EtcdDisk{Size: "10Gi", StorageClass: "fast-ssd"},
+			},
+			valid: true,
+		},
+		{
+			name: "etcd disk too small",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				EtcdDisk:    &kubevirt.EtcdDisk{Size: "8Gi"},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid etcd disk size",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				EtcdDisk:    &kubevirt.EtcdDisk{},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid etcd disk storage class",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				EtcdDisk:    &kubevirt.EtcdDisk{Size: "10Gi", StorageClass: "Fast_SSD"},
+			},
+			valid: false,
+		},
+		{
+			name: "host-passthrough cpu model",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUModel:    "host-passthrough",
+			},
+			valid: true,
+		},
+		{
+			name: "named cpu model",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUModel:    "Haswell-noTSX",
+			},
+			valid: true,
+		},
+		{
+			name: "unknown cpu model",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUModel:    "haswell",
+			},
+			valid: false,
+		},
+		{
+			name: "valid cpu features",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUFeatures: []kubevirt.CPUFeature{{Name: "vmx"}, {Name: "pcid", Policy: kubevirt.CPUFeaturePolicyDisable}},
+			},
+			valid: true,
+		},
+		{
+			name: "invalid cpu feature name",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUFeatures: []kubevirt.CPUFeature{{Name: "VMX"}},
+			},
+			valid: false,
+		},
+		{
+			name: "duplicate cpu feature",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUFeatures: []kubevirt.CPUFeature{{Name: "vmx"}, {Name: "vmx", Policy: kubevirt.CPUFeaturePolicyForbid}},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid cpu feature policy",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "5G",
+				StorageSize: "100Gi",
+				CPUFeatures: []kubevirt.CPUFeature{{Name: "vmx", Policy: "enable"}},
+			},
+			valid: false,
+		},
+		{
+			name: "valid hugepages",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "16Gi",
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize1Gi},
+			},
+			valid: true,
+		},
+		{
+			name: "valid hugepages count without memory",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize2Mi, Count: 8192},
+			},
+			valid: true,
+		},
+		{
+			name: "valid hugepages count matching memory",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "16Gi",
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize1Gi, Count: 16},
+			},
+			valid: true,
+		},
+		{
+			name: "invalid hugepages page size",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "16Gi",
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: "4Ki"},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid hugepages count not matching memory",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "16Gi",
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize1Gi, Count: 8},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid memory not a multiple of the hugepages page size",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				Memory:      "16G",
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize2Mi},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid hugepages without memory nor count",
+			pool: &kubevirt.MachinePool{
+				CPU:         4,
+				StorageSize: "100Gi",
+				Hugepages:   &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize1Gi},
+			},
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -117,3 +518,71 @@ func TestValidateMachinePool(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateComputeMachinePool(t *testing.T) {
+	cases := []struct {
+		name          string
+		pool          *kubevirt.MachinePool
+		expectedError string
+	}{
+		{
+			name: "valid",
+			pool: &kubevirt.MachinePool{CPU: 4, Memory: "8Gi", StorageSize: "120Gi", NamePrefix: "site"},
+		},
+		{
+			name:          "memory overhead",
+			pool:          &kubevirt.MachinePool{MemoryOverhead: "512Mi"},
+			expectedError: `^test-path\.memoryOverhead: Invalid value: "512Mi": compute machine pools do not support memoryOverhead, their VMs are created by the machine-api provider$`,
+		},
+		{
+			name:          "cpu model",
+			pool:          &kubevirt.MachinePool{CPUModel: kubevirt.CPUModelHostPassthrough},
+			expectedError: `^test-path\.cpuModel: Invalid value: "host-passthrough": compute machine pools do not support cpuModel`,
+		},
+		{
+			name:          "cpu features",
+			pool:          &kubevirt.MachinePool{CPUFeatures: []kubevirt.CPUFeature{{Name: "vmx"}}},
+			expectedError: `^test-path\.cpuFeatures: Invalid value: "vmx": compute machine pools do not support cpuFeatures`,
+		},
+		{
+			name:          "run strategy",
+			pool:          &kubevirt.MachinePool{RunStrategy: kubevirt.RunStrategyManual},
+			expectedError: `^test-path\.runStrategy: Invalid value: "Manual": compute machine pools do not support runStrategy`,
+		},
+		{
+			name:          "termination grace period",
+			pool:          &kubevirt.MachinePool{TerminationGracePeriodSeconds: pointer.Int64Ptr(600)},
+			expectedError: `^test-path\.terminationGracePeriodSeconds: Invalid value: 600: compute machine pools do not support terminationGracePeriodSeconds`,
+		},
+		{
+			name:          "priority class",
+			pool:          &kubevirt.MachinePool{PriorityClassName: "tenant-control-plane"},
+			expectedError: `^test-path\.priorityClassName: Invalid value: "tenant-control-plane": compute machine pools do not support priorityClassName`,
+		},
+		{
+			name:          "etcd disk",
+			pool:          &kubevirt.MachinePool{EtcdDisk: &kubevirt.EtcdDisk{Size: "10Gi"}},
+			expectedError: `^test-path\.etcdDisk: Invalid value: "10Gi": compute machine pools do not support etcdDisk, etcd only runs on the control plane$`,
+		},
+		{
+			name:          "hugepages",
+			pool:          &kubevirt.MachinePool{Hugepages: &kubevirt.Hugepages{PageSize: kubevirt.HugepagesPageSize1Gi}},
+			expectedError: `^test-path\.hugepages\.pageSize: Invalid value: "1Gi": compute machine pools do not support hugepages`,
+		},
+		{
+			name:          "several fields",
+			pool:          &kubevirt.MachinePool{SpreadPolicy: kubevirt.SpreadPolicyPack, DiskBus: kubevirt.DiskBusSATA, OvercommitGuestOverhead: true},
+			expectedError: `^\[test-path\.overcommitGuestOverhead: .*, test-path\.spreadPolicy: .*, test-path\.diskBus: .*\]$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateComputeMachinePool(tc.pool, field.NewPath("test-path")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+const (
+	// infraIDMaxLen is the length of the longest infra IDs, the cluster name truncated to fit
+	// with the random suffix.
+	infraIDMaxLen = 27
+	// randomSuffix stands for the random suffixes of the infra ID and of the compute machine
+	// names in the reported names.
+	randomSuffix = "-xxxxx"
+)
+
+// infraID returns the longest infra ID which can be generated from the cluster name, with the
+// random suffix as x.
+func infraID(clusterName string) string {
+	base := strings.ReplaceAll(clusterName, ".", "-")
+	if maxBaseLen := infraIDMaxLen - len(randomSuffix); len(base) > maxBaseLen {
+		base = base[:maxBaseLen]
+	}
+	return strings.TrimRight(base, "-") + randomSuffix
+}
+
+// longestNames returns the longest names of the VMs and DataVolumes created in the infra
+// cluster for the VMs of the machine pool, whose names start with the prefix. The control plane
+// VMs are created by the installer and the compute VMs by the machine-api provider, which names
+// them after their machine set.
+func longestNames(prefix string, pool *types.MachinePool, controlPlane bool) []string {
+	replicas := int64(1)
+	if pool.Replicas != nil && *pool.Replicas > 1 {
+		replicas = *pool.Replicas
+	}
+	vm := kubevirt.ResourceName(prefix, pool.Name, int(replicas-1))
+	if !controlPlane {
+		vm = kubevirt.ResourceName(prefix, pool.Name, 0) + randomSuffix
+	}
+	return []string{vm, vm + "-bootvolume"}
+}
+
+// ValidateNames checks that the names of the VMs and DataVolumes created in the infra cluster,
+// generated from the cluster name or the name prefixes of the machine pools, are valid DNS-1123
+// labels, as the names of the VM pods and the labels of the DataVolumes require.
+func ValidateNames(ic *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	id := infraID(ic.ObjectMeta.Name)
+	validate := func(fldPath *field.Path, value string, names []string) {
+		longest := ""
+		for _, name := range names {
+			if len(name) > len(longest) {
+				longest = name
+			}
+		}
+		for _, msg := range utilvalidation.IsDNS1123Label(longest) {
+			detailedErr := fmt.Errorf("the generated name %s is invalid: %s", longest, msg)
+			allErrs = append(allErrs, field.Invalid(fldPath, value, detailedErr.Error()))
+		}
+	}
+	validate(field.NewPath("metadata", "name"), ic.ObjectMeta.Name, []string{
+		id + "-source-pvc",
+		id + "-bootstrap",
+		id + "-bootstrap-bootvolume",
+		id + "-bootstrap-datavolumedisk1",
+	})
+
+	pools := []*types.MachinePool{}
+	paths := []*field.Path{}
+	if ic.ControlPlane != nil {
+		pools = append(pools, ic.ControlPlane)
+		paths = append(paths, field.NewPath("controlPlane"))
+	}
+	for i := range ic.Compute {
+		pools = append(pools, &ic.Compute[i])
+		paths = append(paths, field.NewPath("compute").Index(i))
+	}
+	for i, pool := range pools {
+		controlPlane := pool == ic.ControlPlane
+		names := longestNames(pool.Platform.Kubevirt.ResourceNamePrefix(id), pool, controlPlane)
+		if pool.Platform.Kubevirt != nil && pool.Platform.Kubevirt.NamePrefix != "" {
+			validate(paths[i].Child("platform", "kubevirt", "namePrefix"), pool.Platform.Kubevirt.NamePrefix, names)
+			continue
+		}
+		validate(field.NewPath("metadata", "name"), ic.ObjectMeta.Name, names)
+	}
+
+	return allErrs
+}
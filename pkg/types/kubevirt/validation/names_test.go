@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func TestValidateNames(t *testing.T) {
+	installConfig := func(name string, masterPrefix string, workerPrefix string) *types.InstallConfig {
+		return &types.InstallConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			ControlPlane: &types.MachinePool{
+				Name:     "master",
+				Replicas: pointer.Int64Ptr(3),
+				Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{NamePrefix: masterPrefix}},
+			},
+			Compute: []types.MachinePool{{
+				Name:     "worker",
+				Replicas: pointer.Int64Ptr(2),
+				Platform: types.MachinePoolPlatform{Kubevirt: &kubevirt.MachinePool{NamePrefix: workerPrefix}},
+			}},
+		}
+	}
+
+	cases := []struct {
+		name          string
+		installConfig *types.InstallConfig
+		expectedErr   string
+	}{
+		{
+			name:          "valid",
+			installConfig: installConfig("tenant", "", ""),
+		},
+		{
+			name:          "longest cluster name",
+			installConfig: installConfig(strings.Repeat("a", 54), "", ""),
+		},
+		{
+			name:          "cluster name with dots",
+			installConfig: installConfig("tenant.cluster", "", ""),
+		},
+		{
+			name:          "valid name prefixes",
+			installConfig: installConfig("tenant", strings.Repeat("m", 43), strings.Repeat("w", 37)),
+		},
+		{
+			name:          "control plane name prefix too long",
+			installConfig: installConfig("tenant", strings.Repeat("m", 44), ""),
+			expectedErr:   `^controlPlane\.platform\.kubevirt\.namePrefix: Invalid value: "m{44}": the generated name m{44}-master-2-bootvolume is invalid: must be no more than 63 characters$`,
+		},
+		{
+			name:          "compute name prefix too long",
+			installConfig: installConfig("tenant", "", strings.Repeat("w", 38)),
+			expectedErr:   `^compute\[0\]\.platform\.kubevirt\.namePrefix: Invalid value: "w{38}": the generated name w{38}-worker-0-xxxxx-bootvolume is invalid: must be no more than 63 characters$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNames(tc.installConfig).ToAggregate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedErr, err)
+			}
+		})
+	}
+}
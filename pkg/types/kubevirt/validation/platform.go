@@ -1,6 +1,16 @@
 package validation
 
 import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/types/kubevirt"
@@ -13,6 +23,10 @@ func ValidatePlatform(p *kubevirt.Platform, fldPath *field.Path) field.ErrorList
 
 	if p.Namespace == "" {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("Infra Cluster Namespace"), p.Namespace, "Infra Cluster Namespace can't be empty"))
+	} else {
+		for _, msg := range utilvalidation.IsDNS1123Label(p.Namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), p.Namespace, msg))
+		}
 	}
 
 	if p.NetworkName == "" {
@@ -27,5 +41,256 @@ func ValidatePlatform(p *kubevirt.Platform, fldPath *field.Path) field.ErrorList
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("IngressVIP"), p.IngressVIP, err.Error()))
 	}
 
+	if p.BootstrapIgnitionURL != "" {
+		if u, err := url.Parse(p.BootstrapIgnitionURL); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bootstrapIgnitionURL"), p.BootstrapIgnitionURL, err.Error()))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bootstrapIgnitionURL"), p.BootstrapIgnitionURL, "must use http or https protocol"))
+		} else if u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bootstrapIgnitionURL"), p.BootstrapIgnitionURL, "must have a host"))
+		}
+	}
+
+	if kubevirt.IsInlinePEM(p.InfraCABundle) {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(p.InfraCABundle)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("infraCABundle"), p.InfraCABundle, "must hold PEM-encoded certificates"))
+		}
+	}
+
+	if p.MirrorRegistry != nil && p.MirrorRegistry.CABundle != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(p.MirrorRegistry.CABundle)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("mirrorRegistry", "caBundle"), p.MirrorRegistry.CABundle, "must hold PEM-encoded certificates"))
+		}
+	}
+
+	if p.CreateNetwork != nil {
+		allErrs = append(allErrs, validateNetworkTemplate(p, fldPath.Child("createNetwork"))...)
+	}
+
+	if p.NetworkProbe != nil {
+		allErrs = append(allErrs, validateNetworkProbe(p.NetworkProbe, fldPath.Child("networkProbe"))...)
+	}
+
+	if p.ImportTuning != nil {
+		allErrs = append(allErrs, validateImportTuning(p.ImportTuning, fldPath.Child("importTuning"))...)
+	}
+
+	if p.DataVolumeTags != nil {
+		allErrs = append(allErrs, validateDataVolumeTags(p.DataVolumeTags, fldPath.Child("dataVolumeTags"))...)
+	}
+
+	if p.ServiceAccount != nil {
+		for _, msg := range utilvalidation.IsDNS1123Label(p.ServiceAccount.Namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceAccount", "namespace"), p.ServiceAccount.Namespace, msg))
+		}
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(p.ServiceAccount.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceAccount", "name"), p.ServiceAccount.Name, msg))
+		}
+	}
+
+	if p.Bootstrap != nil {
+		allErrs = append(allErrs, validateBootstrap(p.Bootstrap, fldPath.Child("bootstrap"))...)
+	}
+
+	allErrs = append(allErrs, validateNodeLabels(p.NodeLabels, fldPath.Child("nodeLabels"))...)
+
+	allErrs = append(allErrs, validateRunStrategy(p.RunStrategy, fldPath.Child("runStrategy"))...)
+
+	return allErrs
+}
+
+// validateBootstrap checks the size of the bootstrap VM, with the quantity rules of the machine
+// pools for the fields set.
+func validateBootstrap(b *kubevirt.Bootstrap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if b.Memory != "" {
+		memoryQuantity, err := resource.ParseQuantity(b.Memory)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), b.Memory, "Memory must be of Quantity type format"))
+		} else if memoryQuantity.Sign() != 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), b.Memory, "Memory must be positive value"))
+		}
+	}
+	if b.Storage != "" {
+		storageQuantity, err := resource.ParseQuantity(b.Storage)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storage"), b.Storage, "Storage size must be of Quantity type format"))
+		} else if storageQuantity.Sign() != 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storage"), b.Storage, "Storage size must be positive value"))
+		}
+	}
 	return allErrs
 }
+
+// validateNodeLabels checks that the labels of the node label mappings are label keys, mapped to
+// different tenant labels.
+func validateNodeLabels(mappings []kubevirt.NodeLabelMapping, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	tenantLabels := map[string]bool{}
+	for i, mapping := range mappings {
+		for _, msg := range utilvalidation.IsQualifiedName(mapping.InfraLabel) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("infraLabel"), mapping.InfraLabel, msg))
+		}
+		if mapping.TenantLabel != "" {
+			for _, msg := range utilvalidation.IsQualifiedName(mapping.TenantLabel) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("tenantLabel"), mapping.TenantLabel, msg))
+			}
+		}
+		tenantLabel := mapping.TenantNodeLabel()
+		if tenantLabels[tenantLabel] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("tenantLabel"), tenantLabel))
+		}
+		tenantLabels[tenantLabel] = true
+	}
+	return allErrs
+}
+
+// bridgeName matches the names of the Linux interfaces.
+var bridgeName = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,15}$`)
+
+// validateNetworkTemplate checks the network-attachment-definition created when the network of
+// the platform doesn't exist.
+func validateNetworkTemplate(p *kubevirt.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	template := p.CreateNetwork
+
+	if p.NetworkName != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(p.NetworkName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Root().Child("platform", "kubevirt", "networkName"), p.NetworkName, fmt.Sprintf("the network created must have a valid name: %s", msg)))
+		}
+	}
+
+	switch template.Type {
+	case "", kubevirt.NetworkTypeBridge:
+		if template.Bridge == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("bridge"), "the bridge is required with the bridge type"))
+		} else if !bridgeName.MatchString(template.Bridge) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bridge"), template.Bridge, "must be a network interface name of at most 15 letters, digits, '_', '.' or '-'"))
+		}
+	case kubevirt.NetworkTypeOVN:
+		if template.Bridge != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bridge"), template.Bridge, "is only supported with the bridge type"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), template.Type, []string{string(kubevirt.NetworkTypeBridge), string(kubevirt.NetworkTypeOVN)}))
+	}
+
+	if template.CIDR != "" {
+		if _, subnet, err := net.ParseCIDR(template.CIDR); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cidr"), template.CIDR, err.Error()))
+		} else {
+			for _, vip := range []struct {
+				name string
+				ip   string
+			}{{"apiVIP", p.APIVIP}, {"ingressVIP", p.IngressVIP}} {
+				if ip := net.ParseIP(vip.ip); ip != nil && !subnet.Contains(ip) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("cidr"), template.CIDR, fmt.Sprintf("must contain the %s %s", vip.name, vip.ip)))
+				}
+			}
+		}
+	}
+
+	if template.VLAN < 0 || template.VLAN > 4094 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vlan"), template.VLAN, "must be between 1 and 4094"))
+	}
+
+	return allErrs
+}
+
+// validateNetworkProbe checks the addresses probed and that the probe checks something.
+func validateNetworkProbe(probe *kubevirt.NetworkProbe, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !probe.DHCP && probe.Gateway == "" && probe.DNSServer == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of dhcp, gateway and dnsServer must be set"))
+	}
+	if probe.Gateway != "" {
+		if err := validate.IP(probe.Gateway); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("gateway"), probe.Gateway, err.Error()))
+		}
+	}
+	if probe.DNSServer != "" {
+		if err := validate.IP(probe.DNSServer); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsServer"), probe.DNSServer, err.Error()))
+		}
+		if probe.DNSName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("dnsName"), "the name resolved is required with dnsServer"))
+		} else if err := validate.DomainName(probe.DNSName, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsName"), probe.DNSName, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateImportTuning checks the resources of the CDI importer pod and the cap of the
+// concurrent imports.
+func validateImportTuning(tuning *kubevirt.ImportTuning, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	requests := parseImportResources(tuning.Requests, fldPath.Child("requests"), &allErrs)
+	limits := parseImportResources(tuning.Limits, fldPath.Child("limits"), &allErrs)
+	for _, name := range []string{"cpu", "memory"} {
+		limit, hasLimit := limits[name]
+		if request, ok := requests[name]; ok && hasLimit && limit.Cmp(request) < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("limits", name), limit.String(), fmt.Sprintf("must not be less than the %s request %s", name, request.String())))
+		}
+	}
+
+	if tuning.MaxConcurrentImports < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxConcurrentImports"), tuning.MaxConcurrentImports, "must not be negative"))
+	}
+
+	return allErrs
+}
+
+// validateDataVolumeTags checks the values of the tags of the DataVolumes.
+func validateDataVolumeTags(tags *kubevirt.DataVolumeTags, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if tags.PriorityClass != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(tags.PriorityClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("priorityClass"), tags.PriorityClass, msg))
+		}
+	}
+	if tags.StorageOverhead != "" {
+		if overhead, err := strconv.ParseFloat(tags.StorageOverhead, 64); err != nil || overhead < 0 || overhead >= 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storageOverhead"), tags.StorageOverhead, "must be a number between 0 and 1"))
+		}
+	}
+	if tags.ExpectedLifetime != "" {
+		if lifetime, err := time.ParseDuration(tags.ExpectedLifetime); err != nil || lifetime <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("expectedLifetime"), tags.ExpectedLifetime, "must be a positive duration, e.g. 72h"))
+		}
+	}
+
+	return allErrs
+}
+
+// parseImportResources returns the quantities of the resources which are set, by name,
+// appending the errors of the invalid ones.
+func parseImportResources(resources *kubevirt.ImportResources, fldPath *field.Path, allErrs *field.ErrorList) map[string]resource.Quantity {
+	quantities := map[string]resource.Quantity{}
+	if resources == nil {
+		return quantities
+	}
+	for _, r := range []struct {
+		name  string
+		value string
+	}{{"cpu", resources.CPU}, {"memory", resources.Memory}} {
+		name, value := r.name, r.value
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		switch {
+		case err != nil:
+			*allErrs = append(*allErrs, field.Invalid(fldPath.Child(name), value, "must be of Quantity type format"))
+		case quantity.Sign() != 1:
+			*allErrs = append(*allErrs, field.Invalid(fldPath.Child(name), value, "must be positive value"))
+		default:
+			quantities[name] = quantity
+		}
+	}
+	return quantities
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,6 +95,415 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			valid: true,
 		},
+		{
+			name: "valid bootstrap ignition URL",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.BootstrapIgnitionURL = "https://storage.example.com/ignition/bootstrap.ign"
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid bootstrap ignition URL scheme",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.BootstrapIgnitionURL = "s3://bucket/bootstrap.ign"
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "invalid bootstrap ignition URL without host",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.BootstrapIgnitionURL = "https:///bootstrap.ign"
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "invalid namespace",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.Namespace = "Tenant_Namespace"
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "namespace too long",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.Namespace = strings.Repeat("a", 64)
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid inline infra CA bundle",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.InfraCABundle = testCACert
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "valid infra CA bundle file",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.InfraCABundle = "/etc/pki/infra-ca.pem"
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid inline infra CA bundle",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.InfraCABundle = "-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----\n"
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid mirror registry CA bundle",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.MirrorRegistry = &kubevirt.MirrorRegistry{CABundle: testCACert}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid mirror registry CA bundle",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.MirrorRegistry = &kubevirt.MirrorRegistry{CABundle: "not a certificate"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid service account",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ServiceAccount = &kubevirt.ServiceAccountReference{Namespace: "test-namespace", Name: "tenant-cluster"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "service account without name",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ServiceAccount = &kubevirt.ServiceAccountReference{Namespace: "test-namespace"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "service account with invalid namespace",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ServiceAccount = &kubevirt.ServiceAccountReference{Namespace: "Test_Namespace", Name: "tenant-cluster"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid create network",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: "10.0.0.0/24", VLAN: 100}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "valid ovn create network",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeOVN, CIDR: "10.0.0.0/24"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "valid network probe",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkProbe = &kubevirt.NetworkProbe{DHCP: true, Gateway: "10.0.0.1", DNSServer: "10.0.0.2", DNSName: "example.com"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "network probe without checks",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkProbe = &kubevirt.NetworkProbe{}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "network probe with invalid gateway",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkProbe = &kubevirt.NetworkProbe{Gateway: "gateway"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "network probe dns server without name",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkProbe = &kubevirt.NetworkProbe{DNSServer: "10.0.0.2"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid import tuning",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ImportTuning = &kubevirt.ImportTuning{
+					Requests:             &kubevirt.ImportResources{CPU: "500m", Memory: "1Gi"},
+					Limits:               &kubevirt.ImportResources{CPU: "1", Memory: "2Gi"},
+					MaxConcurrentImports: 2,
+				}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "import tuning with invalid request",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ImportTuning = &kubevirt.ImportTuning{Requests: &kubevirt.ImportResources{Memory: "a lot"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "import tuning with limit less than request",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ImportTuning = &kubevirt.ImportTuning{
+					Requests: &kubevirt.ImportResources{CPU: "2"},
+					Limits:   &kubevirt.ImportResources{CPU: "500m"},
+				}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "import tuning with negative concurrent imports",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.ImportTuning = &kubevirt.ImportTuning{MaxConcurrentImports: -1}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid data volume tags",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.DataVolumeTags = &kubevirt.DataVolumeTags{PriorityClass: "tenant-low", StorageOverhead: "0.055", ExpectedLifetime: "72h"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "data volume tags with invalid priority class",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.DataVolumeTags = &kubevirt.DataVolumeTags{PriorityClass: "Tenant_Low"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "data volume tags with invalid storage overhead",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.DataVolumeTags = &kubevirt.DataVolumeTags{StorageOverhead: "1.5"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "data volume tags with invalid expected lifetime",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.DataVolumeTags = &kubevirt.DataVolumeTags{ExpectedLifetime: "3 days"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network with invalid name",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network without bridge",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network with invalid bridge",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "a-bridge-name-too-long"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create ovn network with bridge",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeOVN, Bridge: "br1"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network with unsupported type",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: "macvlan"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network with invalid CIDR",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: "10.0.0.0"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network without the VIPs",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", CIDR: "192.168.0.0/24"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "create network with invalid VLAN",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NetworkName = "test-network"
+				p.CreateNetwork = &kubevirt.NetworkTemplate{Type: kubevirt.NetworkTypeBridge, Bridge: "br1", VLAN: 4095}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid bootstrap",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.Bootstrap = &kubevirt.Bootstrap{CPU: 2, Memory: "6Gi", Storage: "30Gi"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid bootstrap memory",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.Bootstrap = &kubevirt.Bootstrap{Memory: "6 GB"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "invalid bootstrap storage",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.Bootstrap = &kubevirt.Bootstrap{Storage: "0"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid node labels",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NodeLabels = []kubevirt.NodeLabelMapping{
+					{InfraLabel: "example.com/rack", TenantLabel: "topology.kubernetes.io/zone"},
+					{InfraLabel: "topology.kubernetes.io/region"},
+				}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid node label",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NodeLabels = []kubevirt.NodeLabelMapping{{InfraLabel: "example.com/rack", TenantLabel: "not a label"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "duplicate tenant node label",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.NodeLabels = []kubevirt.NodeLabelMapping{
+					{InfraLabel: "example.com/rack", TenantLabel: "topology.kubernetes.io/zone"},
+					{InfraLabel: "topology.kubernetes.io/zone"},
+				}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid run strategy",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.RunStrategy = kubevirt.RunStrategyRerunOnFailure
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid run strategy",
+			platform: func() *kubevirt.Platform {
+				p := validPlatform()
+				p.RunStrategy = "Halted"
+				return p
+			}(),
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -106,3 +516,20 @@ func TestValidatePlatform(t *testing.T) {
 		})
 	}
 }
+
+const testCACert = `-----BEGIN CERTIFICATE-----
+MIICYTCCAcqgAwIBAgIJAI2kA+uXAbhOMA0GCSqGSIb3DQEBCwUAMEgxCzAJBgNV
+BAYTAlVTMQswCQYDVQQIDAJDQTEWMBQGA1UEBwwNU2FuIEZyYW5jaXNjbzEUMBIG
+A1UECgwLUmVkIEhhdCBJbmMwHhcNMTkwMjEyMTkzMjUzWhcNMTkwMjEzMTkzMjUz
+WjBIMQswCQYDVQQGEwJVUzELMAkGA1UECAwCQ0ExFjAUBgNVBAcMDVNhbiBGcmFu
+Y2lzY28xFDASBgNVBAoMC1JlZCBIYXQgSW5jMIGfMA0GCSqGSIb3DQEBAQUAA4GN
+ADCBiQKBgQC+HOC0mKig/oINAKPo88LqxDJ4l7lozdLtp5oGeqWrLUXSfkvXAkQY
+2QYdvPAjpRfH7Ii7G0Asx+HTKdvula7B5fXDjc6NYKuEpTJZRV1ugntI97bozF/E
+C2BBmxxEnJN3+Xe8RYXMjz5Q4aqPw9vZhlWN+0hrREl1Ea/zHuWFIQIDAQABo1Mw
+UTAdBgNVHQ4EFgQUvTS1XjlvOdsufSyWxukyQu3LriEwHwYDVR0jBBgwFoAUvTS1
+XjlvOdsufSyWxukyQu3LriEwDwYDVR0TAQH/BAUwAwEB/zANBgkqhkiG9w0BAQsF
+AAOBgQB9gFcOXnzJrM65QqxeCB9Z5l5JMjp45UFC9Bj2cgwDHP80Zvi4omlaacC6
+aavmnLd67zm9PbYDWRaOIWAMeB916Iwaw/v6I0jwhAk/VxX5Fl6cGlZu9jZ3zbFE
+2sDqkwzIuSjCG2A23s6d4M1S3IXCCydoCSLMu+WhLkbboK6jEg==
+-----END CERTIFICATE-----
+`
@@ -63,6 +63,67 @@ type MachinePool struct {
 	// +kubebuilder:default=amd64
 	// +optional
 	Architecture Architecture `json:"architecture,omitempty"`
+
+	// IgnitionFragments are Ignition configs, in JSON, whose files, directories, links,
+	// systemd units and users are appended to the pointer Ignition config of the machines
+	// of the pool. Their spec version must be 3.0.0 or 3.1.0.
+	// The fragments of the compute pools are applied to all the compute machines.
+	//
+	// +optional
+	IgnitionFragments []string `json:"ignitionFragments,omitempty"`
+
+	// KernelArguments are the kernel arguments added to the machines of the pool, e.g.
+	// hugepagesz=1G or intel_iommu=on, with a MachineConfig.
+	// The arguments of the compute pools are added to all the compute machines.
+	//
+	// +optional
+	KernelArguments []string `json:"kernelArguments,omitempty"`
+
+	// KubeletConfig is the configuration of the kubelet of the machines of the pool, set
+	// with a KubeletConfig of the machine config pool of their role.
+	// The configuration of the compute pools applies to all the compute machines.
+	//
+	// +optional
+	KubeletConfig *KubeletConfig `json:"kubeletConfig,omitempty"`
+
+	// ContainerRuntimeConfig is the configuration of the container runtime of the machines of
+	// the pool, set with a ContainerRuntimeConfig of the machine config pool of their role.
+	// The configuration of the compute pools applies to all the compute machines.
+	//
+	// +optional
+	ContainerRuntimeConfig *ContainerRuntimeConfig `json:"containerRuntimeConfig,omitempty"`
+}
+
+// KubeletConfig is the configuration of the kubelet of the machines of a pool.
+type KubeletConfig struct {
+	// MaxPods is the maximum number of pods run by each machine.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+
+	// SystemReserved are the resources reserved for the system daemons of the machines, as
+	// quantities per resource name, e.g. cpu: 500m and memory: 1Gi. The resource names are
+	// cpu, memory, ephemeral-storage and pid.
+	//
+	// +optional
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+}
+
+// ContainerRuntimeConfig is the configuration of the container runtime of the machines of a
+// pool.
+type ContainerRuntimeConfig struct {
+	// PidsLimit is the maximum number of processes of a container, at least 20.
+	//
+	// +kubebuilder:validation:Minimum=20
+	// +optional
+	PidsLimit *int64 `json:"pidsLimit,omitempty"`
+
+	// LogSizeMax is the maximum size of the log file of a container, e.g. 50Mi, at least
+	// 8Ki. A negative size removes the limit.
+	//
+	// +optional
+	LogSizeMax string `json:"logSizeMax,omitempty"`
 }
 
 // MachinePoolPlatform is the platform-specific configuration for a machine
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/none"
+)
+
+// The error codes are stable identifiers of the validation errors, for the tools driving the
+// installer to map them to their own messages. A code is made of the area of the install-config
+// the error is about, the section of the area and the type of the error, as in KUBEVIRT_MP_002
+// for an invalid value of a KubeVirt machine pool. The areas are the upper-cased platform names
+// for the platform fields, and INSTALLCONFIG for the others.
+const (
+	// CodeAreaInstallConfig is the area of the fields not specific to a platform.
+	CodeAreaInstallConfig = "INSTALLCONFIG"
+
+	// CodeSectionPlatform is the section of the platform fields.
+	CodeSectionPlatform = "PF"
+	// CodeSectionMachinePool is the section of the machine pools.
+	CodeSectionMachinePool = "MP"
+	// CodeSectionNetworking is the section of the networking fields.
+	CodeSectionNetworking = "NET"
+	// CodeSectionMetadata is the section of the metadata of the install-config.
+	CodeSectionMetadata = "META"
+	// CodeSectionGeneral is the section of the other fields.
+	CodeSectionGeneral = "GEN"
+
+	// CodeUnknown is the code of the errors which are not about a field of the install-config,
+	// such as failing to read it.
+	CodeUnknown = "INSTALLCONFIG_GEN_000"
+)
+
+// errorTypeCodes are the numbers of the error codes by type of field error.
+var errorTypeCodes = map[field.ErrorType]int{
+	field.ErrorTypeRequired:     1,
+	field.ErrorTypeInvalid:      2,
+	field.ErrorTypeNotSupported: 3,
+	field.ErrorTypeDuplicate:    4,
+	field.ErrorTypeForbidden:    5,
+	field.ErrorTypeTooLong:      6,
+	field.ErrorTypeTooMany:      7,
+	field.ErrorTypeNotFound:     8,
+	field.ErrorTypeInternal:     9,
+}
+
+// fieldIndex matches the indices and keys of a field path.
+var fieldIndex = regexp.MustCompile(`\[[^]]*\]`)
+
+// ErrorCode returns the error code of the field error.
+func ErrorCode(err *field.Error) string {
+	path := strings.Split(fieldIndex.ReplaceAllString(err.Field, ""), ".")
+	area, section := CodeAreaInstallConfig, CodeSectionGeneral
+	for i, segment := range path {
+		if segment == "platform" && i+1 < len(path) && isPlatformName(path[i+1]) {
+			area = strings.ToUpper(path[i+1])
+			if i > 0 || (i+2 < len(path) && path[i+2] == "defaultMachinePlatform") {
+				section = CodeSectionMachinePool
+			} else {
+				section = CodeSectionPlatform
+			}
+			break
+		}
+	}
+	if area == CodeAreaInstallConfig {
+		switch path[0] {
+		case "controlPlane", "compute":
+			section = CodeSectionMachinePool
+		case "networking":
+			section = CodeSectionNetworking
+		case "metadata":
+			section = CodeSectionMetadata
+		}
+	}
+	return fmt.Sprintf("%s_%s_%03d", area, section, errorTypeCodes[err.Type])
+}
+
+func isPlatformName(name string) bool {
+	if name == none.Name {
+		return true
+	}
+	for _, names := range [][]string{types.PlatformNames, types.HiddenPlatformNames} {
+		for _, platformName := range names {
+			if name == platformName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CodedError is the machine-readable envelope of a validation error.
+type CodedError struct {
+	// Code is the error code, see ErrorCode.
+	Code string `json:"code"`
+	// Field is the path of the field of the install-config the error is about, if any.
+	Field string `json:"field,omitempty"`
+	// Type is the type of the field error, if any, e.g. FieldValueInvalid.
+	Type string `json:"type,omitempty"`
+	// Message is the message of the error, as logged.
+	Message string `json:"message"`
+}
+
+// CodedErrors returns the envelopes of the errors of err, flattening the aggregated errors.
+// The field errors get their error code, and the other errors CodeUnknown.
+func CodedErrors(err error) []CodedError {
+	if err == nil {
+		return nil
+	}
+	switch cause := errors.Cause(err).(type) {
+	case utilerrors.Aggregate:
+		var coded []CodedError
+		for _, e := range cause.Errors() {
+			coded = append(coded, CodedErrors(e)...)
+		}
+		return coded
+	case *field.Error:
+		return []CodedError{{
+			Code:    ErrorCode(cause),
+			Field:   cause.Field,
+			Type:    string(cause.Type),
+			Message: cause.Error(),
+		}}
+	default:
+		return []CodedError{{
+			Code:    CodeUnknown,
+			Message: err.Error(),
+		}}
+	}
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestErrorCode(t *testing.T) {
+	cases := []struct {
+		err      *field.Error
+		expected string
+	}{
+		{
+			err:      field.Invalid(field.NewPath("controlPlane", "platform", "kubevirt", "cpuModel"), "", ""),
+			expected: "KUBEVIRT_MP_002",
+		},
+		{
+			err:      field.Required(field.NewPath("compute").Index(1).Child("platform", "kubevirt", "storageClass"), ""),
+			expected: "KUBEVIRT_MP_001",
+		},
+		{
+			err:      field.Invalid(field.NewPath("platform", "kubevirt", "defaultMachinePlatform", "cpu"), "", ""),
+			expected: "KUBEVIRT_MP_002",
+		},
+		{
+			err:      field.NotSupported(field.NewPath("platform", "kubevirt", "createNetwork", "type"), "", nil),
+			expected: "KUBEVIRT_PF_003",
+		},
+		{
+			err:      field.Invalid(field.NewPath("platform", "aws", "region"), "", ""),
+			expected: "AWS_PF_002",
+		},
+		{
+			err:      field.Invalid(field.NewPath("compute").Index(0).Child("replicas"), "", ""),
+			expected: "INSTALLCONFIG_MP_002",
+		},
+		{
+			err:      field.Invalid(field.NewPath("networking", "machineNetwork").Index(0).Child("cidr"), "", ""),
+			expected: "INSTALLCONFIG_NET_002",
+		},
+		{
+			err:      field.Required(field.NewPath("metadata", "name"), ""),
+			expected: "INSTALLCONFIG_META_001",
+		},
+		{
+			err:      field.Forbidden(field.NewPath("pullSecret"), ""),
+			expected: "INSTALLCONFIG_GEN_005",
+		},
+		{
+			err:      field.Invalid(field.NewPath("platform"), "", ""),
+			expected: "INSTALLCONFIG_GEN_002",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.err.Field, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ErrorCode(tc.err))
+		})
+	}
+}
+
+func TestCodedErrors(t *testing.T) {
+	err := errors.Wrap(field.ErrorList{
+		field.Invalid(field.NewPath("platform", "kubevirt", "apiVIP"), "bad", "not an IP"),
+		field.Required(field.NewPath("baseDomain"), ""),
+	}.ToAggregate(), "invalid install config")
+	assert.Equal(t, []CodedError{
+		{
+			Code:    "KUBEVIRT_PF_002",
+			Field:   "platform.kubevirt.apiVIP",
+			Type:    "FieldValueInvalid",
+			Message: `platform.kubevirt.apiVIP: Invalid value: "bad": not an IP`,
+		},
+		{
+			Code:    "INSTALLCONFIG_GEN_001",
+			Field:   "baseDomain",
+			Type:    "FieldValueRequired",
+			Message: "baseDomain: Required value",
+		},
+	}, CodedErrors(err))
+
+	assert.Equal(t, []CodedError{{Code: CodeUnknown, Message: "failed to read"}}, CodedErrors(fmt.Errorf("failed to read")))
+	assert.Nil(t, CodedErrors(nil))
+}
@@ -10,6 +10,7 @@ import (
 	dockerref "github.com/containers/image/docker/reference"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -97,21 +98,106 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 		allErrs = append(allErrs, field.Required(field.NewPath("controlPlane"), "controlPlane is required"))
 	}
 	allErrs = append(allErrs, validateCompute(&c.Platform, c.ControlPlane, c.Compute, field.NewPath("compute"))...)
+	if c.Platform.Kubevirt != nil && nameErr == nil {
+		allErrs = append(allErrs, kubevirtvalidation.ValidateNames(c)...)
+	}
 	if err := validate.ImagePullSecret(c.PullSecret); err != nil {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("pullSecret"), c.PullSecret, err.Error()))
 	}
+	if c.PullSecretScope != nil {
+		allErrs = append(allErrs, validatePullSecretScope(c.PullSecretScope, field.NewPath("pullSecretScope"))...)
+	}
 	if c.Proxy != nil {
 		allErrs = append(allErrs, validateProxy(c.Proxy, field.NewPath("proxy"))...)
 	}
 	allErrs = append(allErrs, validateImageContentSources(c.ImageContentSources, field.NewPath("imageContentSources"))...)
+	if _, ok := validMirrorPolicyKinds[c.MirrorPolicyKind]; c.MirrorPolicyKind != "" && !ok {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("mirrorPolicyKind"), c.MirrorPolicyKind, validMirrorPolicyKindValues))
+	}
 	if _, ok := validPublishingStrategies[c.Publish]; !ok {
 		allErrs = append(allErrs, field.NotSupported(field.NewPath("publish"), c.Publish, validPublishingStrategyValues))
 	}
 	allErrs = append(allErrs, validateCloudCredentialsMode(c.CredentialsMode, field.NewPath("credentialsMode"), c.Platform.Name())...)
+	if _, ok := validAuditProfiles[c.AuditProfile]; c.AuditProfile != "" && !ok {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("auditProfile"), c.AuditProfile, validAuditProfileValues))
+	}
+	if c.APIServerCertificate != nil {
+		allErrs = append(allErrs, validateAPIServerCertificate(c.APIServerCertificate, field.NewPath("apiServerCertificate"))...)
+	}
+	allErrs = append(allErrs, validateNTPServers(c.NTPServers, field.NewPath("ntpServers"))...)
+	if c.Timeouts != nil {
+		allErrs = append(allErrs, validateTimeouts(c.Timeouts, field.NewPath("timeouts"))...)
+	}
 
 	return allErrs
 }
 
+// validateTimeouts checks that the timeouts set are positive.
+func validateTimeouts(t *types.Timeouts, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, timeout := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{name: "bootstrap", duration: t.Bootstrap},
+		{name: "installComplete", duration: t.InstallComplete},
+		{name: "destroy", duration: t.Destroy},
+		{name: "imageImport", duration: t.ImageImport},
+	} {
+		if timeout.duration != nil && timeout.duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(timeout.name), timeout.duration.Duration.String(), "must be positive"))
+		}
+	}
+	return allErrs
+}
+
+// validateAPIServerCertificate checks that the files of the named serving certificate of the API
+// server are set and that its names are DNS names, with leading wildcards allowed. The content
+// of the files is checked once they are read.
+func validateAPIServerCertificate(c *types.APIServerCertificate, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.CertFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("certFile"), "the certificate file must be set"))
+	}
+	if c.KeyFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("keyFile"), "the key file must be set"))
+	}
+	if c.CAFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("caFile"), "the CA file must be set"))
+	}
+	seen := map[string]bool{}
+	for i, name := range c.Names {
+		if err := validate.DomainName(strings.TrimPrefix(name, "*."), false); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("names").Index(i), name, err.Error()))
+			continue
+		}
+		if seen[name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("names").Index(i), name))
+		}
+		seen[name] = true
+	}
+	return allErrs
+}
+
+// validateNTPServers checks that the NTP servers are IP addresses or host names, listed once.
+func validateNTPServers(servers []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+	for i, server := range servers {
+		if net.ParseIP(server) == nil {
+			if err := validate.DomainName(server, false); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), server, fmt.Sprintf("must be an IP address or a host name: %v", err)))
+				continue
+			}
+		}
+		if seen[server] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), server))
+		}
+		seen[server] = true
+	}
+	return allErrs
+}
+
 // ipAddressTypeByField is a map of field path to whether they request IPv4 or IPv6.
 type ipAddressTypeByField map[string]struct{ IPv4, IPv6 bool }
 
@@ -390,6 +476,9 @@ func validateCompute(platform *types.Platform, control *types.MachinePool, pools
 			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("architecture"), p.Architecture, "heteregeneous multi-arch is not supported; compute pool architecture must match control plane"))
 		}
 		allErrs = append(allErrs, ValidateMachinePool(platform, &p, poolFldPath)...)
+		if p.Platform.Kubevirt != nil {
+			allErrs = append(allErrs, kubevirtvalidation.ValidateComputeMachinePool(p.Platform.Kubevirt, poolFldPath.Child("platform", "kubevirt"))...)
+		}
 	}
 	return allErrs
 }
@@ -489,12 +578,87 @@ func validateImageContentSources(groups []types.ImageContentSource, fldPath *fie
 			allErrs = append(allErrs, field.Invalid(groupf.Child("source"), group.Source, err.Error()))
 		}
 
+		mirrors := sets.NewString()
 		for midx, mirror := range group.Mirrors {
 			if err := validateNamedRepository(mirror); err != nil {
 				allErrs = append(allErrs, field.Invalid(groupf.Child("mirrors").Index(midx), mirror, err.Error()))
 				continue
 			}
+			if mirror == group.Source {
+				allErrs = append(allErrs, field.Invalid(groupf.Child("mirrors").Index(midx), mirror, "must differ from the source"))
+			}
+			if mirrors.Has(mirror) {
+				allErrs = append(allErrs, field.Duplicate(groupf.Child("mirrors").Index(midx), mirror))
+			}
+			mirrors.Insert(mirror)
+		}
+	}
+	return allErrs
+}
+
+// ValidateReleaseImageMirrors checks that the release image is referenced by digest when its
+// repository is mirrored by the image content sources, the mirrors only being used to pull
+// images by digest.
+func ValidateReleaseImageMirrors(pullSpec string, groups []types.ImageContentSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ref, err := dockerref.ParseNamed(pullSpec)
+	if err != nil {
+		return allErrs
+	}
+	if _, ok := ref.(dockerref.Digested); ok {
+		return allErrs
+	}
+	for gidx, group := range groups {
+		if len(group.Mirrors) == 0 {
+			continue
 		}
+		if ref.Name() == group.Source || strings.HasPrefix(ref.Name(), group.Source+"/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(gidx).Child("source"), group.Source, fmt.Sprintf("the release image %s is referenced by tag, the mirrors are only used for images referenced by digest", pullSpec)))
+		}
+	}
+	return allErrs
+}
+
+// ReleaseImageRepositories returns the repositories the cluster pulls the release image from: the
+// repository of the release image and the mirrors of the image content sources.
+func ReleaseImageRepositories(pullSpec string, groups []types.ImageContentSource) []string {
+	var repositories []string
+	if ref, err := dockerref.ParseNamed(pullSpec); err == nil {
+		repositories = append(repositories, ref.Name())
+	}
+	for _, group := range groups {
+		repositories = append(repositories, group.Mirrors...)
+	}
+	return repositories
+}
+
+// ValidateReleaseImagePullSecret checks that the pull secret has credentials for the repository of
+// the release image and for the mirrors of the image content sources.
+func ValidateReleaseImagePullSecret(pullSecret string, pullSpec string, groups []types.ImageContentSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := validate.ImagePullSecretRegistries(pullSecret, ReleaseImageRepositories(pullSpec, groups)); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", err.Error()))
+	}
+	return allErrs
+}
+
+func validatePullSecretScope(scope *types.PullSecretScope, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	registries := sets.NewString()
+	for i, registry := range scope.AdditionalRegistries {
+		registryPath := fldPath.Child("additionalRegistries").Index(i)
+		host := strings.SplitN(registry, "/", 2)[0]
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if err := validate.DomainName(host, false); err != nil || strings.Contains(registry, "://") {
+			allErrs = append(allErrs, field.Invalid(registryPath, registry, "must be a registry host, optionally followed by a namespace"))
+			continue
+		}
+		if registries.Has(registry) {
+			allErrs = append(allErrs, field.Duplicate(registryPath, registry))
+		}
+		registries.Insert(registry)
 	}
 	return allErrs
 }
@@ -526,6 +690,38 @@ var (
 	}()
 )
 
+var (
+	validMirrorPolicyKinds = map[types.MirrorPolicyKind]struct{}{
+		types.ImageContentSourcePolicyMirrorPolicyKind: {},
+		types.ImageDigestMirrorSetMirrorPolicyKind:     {},
+	}
+
+	validMirrorPolicyKindValues = func() []string {
+		v := make([]string, 0, len(validMirrorPolicyKinds))
+		for k := range validMirrorPolicyKinds {
+			v = append(v, string(k))
+		}
+		sort.Strings(v)
+		return v
+	}()
+
+	validAuditProfiles = map[types.AuditProfile]struct{}{
+		types.DefaultAuditProfile:            {},
+		types.WriteRequestBodiesAuditProfile: {},
+		types.AllRequestBodiesAuditProfile:   {},
+		types.NoneAuditProfile:               {},
+	}
+
+	validAuditProfileValues = func() []string {
+		v := make([]string, 0, len(validAuditProfiles))
+		for p := range validAuditProfiles {
+			v = append(v, string(p))
+		}
+		sort.Strings(v)
+		return v
+	}()
+)
+
 func validateCloudCredentialsMode(mode types.CredentialsMode, fldPath *field.Path, platform string) field.ErrorList {
 	if mode == "" {
 		return nil
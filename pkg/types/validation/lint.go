@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+// minimumKubevirtWorkerMemory is the least memory of the KubeVirt compute VMs running the
+// default workloads of the cluster, e.g. the router, registry and monitoring.
+var minimumKubevirtWorkerMemory = resource.MustParse("8Gi")
+
+// hddStorageClassHints are the words of the names of the storage classes usually backed by
+// hard disks, which are too slow for etcd.
+var hddStorageClassHints = []string{"hdd", "slow", "magnetic", "cold"}
+
+// hddAWSVolumeTypes are the AWS volume types backed by hard disks.
+var hddAWSVolumeTypes = map[string]bool{"standard": true, "st1": true, "sc1": true}
+
+// Warning is a non-fatal issue of a valid install-config, which deviates from the best
+// practices.
+type Warning struct {
+	// Field is the path of the field of the install-config the warning is about.
+	Field string `json:"field"`
+	// Message is the message of the warning, as logged.
+	Message string `json:"message"`
+}
+
+// String returns the warning as logged.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Field, w.Message)
+}
+
+// Lint returns the warnings of the defaulted install-config, which should be valid.
+func Lint(c *types.InstallConfig) []Warning {
+	var warnings []Warning
+	warnings = append(warnings, lintControlPlane(c)...)
+	warnings = append(warnings, lintNetworkType(c)...)
+	warnings = append(warnings, lintKubevirtCompute(c)...)
+	warnings = append(warnings, lintProxy(c)...)
+	return warnings
+}
+
+func lintControlPlane(c *types.InstallConfig) []Warning {
+	var warnings []Warning
+	pool := c.ControlPlane
+	if pool == nil {
+		return nil
+	}
+	if pool.Replicas != nil && *pool.Replicas == 1 {
+		warnings = append(warnings, Warning{
+			Field:   field.NewPath("controlPlane", "replicas").String(),
+			Message: "a single control plane replica leaves etcd without a quorum when its machine fails, use 3 replicas for highly available clusters",
+		})
+	}
+
+	if pool.Platform.AWS != nil && hddAWSVolumeTypes[pool.Platform.AWS.EC2RootVolume.Type] {
+		warnings = append(warnings, Warning{
+			Field:   field.NewPath("controlPlane", "platform", "aws", "rootVolume", "type").String(),
+			Message: fmt.Sprintf("the %s volumes are backed by hard disks, too slow for etcd, use gp2 or io1 volumes", pool.Platform.AWS.EC2RootVolume.Type),
+		})
+	}
+	if mpool := pool.Platform.Kubevirt; mpool != nil && c.Platform.Kubevirt != nil {
+		fldPath := field.NewPath("platform", "kubevirt", "storageClass")
+		storageClass := c.Platform.Kubevirt.StorageClass
+		if mpool.EtcdDisk != nil && mpool.EtcdDisk.StorageClass != "" {
+			fldPath = field.NewPath("controlPlane", "platform", "kubevirt", "etcdDisk", "storageClass")
+			storageClass = mpool.EtcdDisk.StorageClass
+		}
+		if hddStorageClass(storageClass) {
+			warnings = append(warnings, Warning{
+				Field:   fldPath.String(),
+				Message: fmt.Sprintf("the storage class %s of the etcd data of the control plane looks backed by hard disks, too slow for etcd, use a storage class backed by SSDs", storageClass),
+			})
+		}
+	}
+	return warnings
+}
+
+// hddStorageClass returns whether the name of the storage class hints that it is backed by hard
+// disks.
+func hddStorageClass(name string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	}) {
+		for _, hint := range hddStorageClassHints {
+			if word == hint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lintNetworkType(c *types.InstallConfig) []Warning {
+	if c.Networking == nil {
+		return nil
+	}
+	fldPath := field.NewPath("networking", "networkType")
+	switch c.Networking.NetworkType {
+	case string(operv1.NetworkTypeOpenShiftSDN), string(operv1.NetworkTypeOVNKubernetes):
+	case string(operv1.NetworkTypeKuryr):
+		if c.Platform.Name() != openstack.Name {
+			return []Warning{{
+				Field:   fldPath.String(),
+				Message: fmt.Sprintf("Kuryr runs on the OpenStack Neutron networks, not on the %s platform, use OpenShiftSDN or OVNKubernetes", c.Platform.Name()),
+			}}
+		}
+	default:
+		return []Warning{{
+			Field:   fldPath.String(),
+			Message: fmt.Sprintf("%s is not deployed by the cluster network operator, its manifests must be added to the manifests of the cluster", c.Networking.NetworkType),
+		}}
+	}
+	return nil
+}
+
+func lintKubevirtCompute(c *types.InstallConfig) []Warning {
+	var warnings []Warning
+	for i, pool := range c.Compute {
+		mpool := pool.Platform.Kubevirt
+		if mpool == nil || (pool.Replicas != nil && *pool.Replicas == 0) {
+			continue
+		}
+		memory, err := resource.ParseQuantity(mpool.VMMemory())
+		if err != nil || memory.Cmp(minimumKubevirtWorkerMemory) >= 0 {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Field:   field.NewPath("compute").Index(i).Child("platform", kubevirt.Name, "memory").String(),
+			Message: fmt.Sprintf("the %s of memory of the VMs is less than the %s the workloads of the cluster need on the compute nodes", memory.String(), minimumKubevirtWorkerMemory.String()),
+		})
+	}
+	return warnings
+}
+
+func lintProxy(c *types.InstallConfig) []Warning {
+	proxy := c.Proxy
+	if proxy == nil || (proxy.HTTPProxy == "" && proxy.HTTPSProxy == "") {
+		return nil
+	}
+	var warnings []Warning
+	if proxy.HTTPProxy != "" && proxy.HTTPSProxy == "" {
+		warnings = append(warnings, Warning{
+			Field:   field.NewPath("proxy", "httpsProxy").String(),
+			Message: "the HTTPS requests of the cluster, most of its requests, are not proxied without an httpsProxy",
+		})
+	}
+
+	var noProxy []string
+	for _, entry := range strings.Split(proxy.NoProxy, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			noProxy = append(noProxy, entry)
+		}
+	}
+	if !noProxyMatches(noProxy, c.ClusterDomain()) {
+		warnings = append(warnings, Warning{
+			Field:   field.NewPath("proxy", "noProxy").String(),
+			Message: fmt.Sprintf("the routes of the cluster are reached through the proxy, add .%s to the noProxy entries", c.ClusterDomain()),
+		})
+	}
+	return warnings
+}
+
+// noProxyMatches returns whether one of the noProxy entries matches the domain.
+func noProxyMatches(noProxy []string, domain string) bool {
+	for _, entry := range noProxy {
+		if entry == "*" {
+			return true
+		}
+		suffix := strings.TrimPrefix(entry, ".")
+		if domain == suffix || strings.HasSuffix(domain, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/kubevirt"
+)
+
+func lintInstallConfig() *types.InstallConfig {
+	c := validInstallConfig()
+	c.ControlPlane.Replicas = pointer.Int64Ptr(3)
+	c.Proxy.NoProxy = ".test-domain,172.30.0.0/16"
+	return c
+}
+
+func lintKubevirtPlatform(c *types.InstallConfig) {
+	c.Platform = types.Platform{Kubevirt: &kubevirt.Platform{StorageClass: "ocs-ssd"}}
+	c.ControlPlane.Platform.Kubevirt = &kubevirt.MachinePool{CPU: 8, Memory: "16Gi", StorageSize: "120Gi"}
+	c.Compute[0].Platform.Kubevirt = &kubevirt.MachinePool{CPU: 4, Memory: "8Gi", StorageSize: "120Gi"}
+}
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		name     string
+		edit     func(c *types.InstallConfig)
+		expected []string
+	}{
+		{
+			name: "no warnings",
+		},
+		{
+			name: "no warnings on kubevirt",
+			edit: lintKubevirtPlatform,
+		},
+		{
+			name: "single master",
+			edit: func(c *types.InstallConfig) {
+				c.ControlPlane.Replicas = pointer.Int64Ptr(1)
+			},
+			expected: []string{"controlPlane.replicas: a single control plane replica leaves etcd without a quorum when its machine fails, use 3 replicas for highly available clusters"},
+		},
+		{
+			name: "hdd aws root volume",
+			edit: func(c *types.InstallConfig) {
+				c.ControlPlane.Platform.AWS = &aws.MachinePool{EC2RootVolume: aws.EC2RootVolume{Type: "st1"}}
+			},
+			expected: []string{"controlPlane.platform.aws.rootVolume.type: the st1 volumes are backed by hard disks, too slow for etcd, use gp2 or io1 volumes"},
+		},
+		{
+			name: "hdd kubevirt storage class",
+			edit: func(c *types.InstallConfig) {
+				lintKubevirtPlatform(c)
+				c.Platform.Kubevirt.StorageClass = "ceph-hdd"
+			},
+			expected: []string{"platform.kubevirt.storageClass: the storage class ceph-hdd of the etcd data of the control plane looks backed by hard disks, too slow for etcd, use a storage class backed by SSDs"},
+		},
+		{
+			name: "ssd kubevirt etcd disk",
+			edit: func(c *types.InstallConfig) {
+				lintKubevirtPlatform(c)
+				c.Platform.Kubevirt.StorageClass = "slow"
+				c.ControlPlane.Platform.Kubevirt.EtcdDisk = &kubevirt.EtcdDisk{Size: "10Gi", StorageClass: "fast"}
+			},
+		},
+		{
+			name: "hdd kubevirt etcd disk",
+			edit: func(c *types.InstallConfig) {
+				lintKubevirtPlatform(c)
+				c.ControlPlane.Platform.Kubevirt.EtcdDisk = &kubevirt.EtcdDisk{Size: "10Gi", StorageClass: "standard.hdd"}
+			},
+			expected: []string{"controlPlane.platform.kubevirt.etcdDisk.storageClass: the storage class standard.hdd of the etcd data of the control plane looks backed by hard disks, too slow for etcd, use a storage class backed by SSDs"},
+		},
+		{
+			name: "kuryr off openstack",
+			edit: func(c *types.InstallConfig) {
+				c.Networking.NetworkType = "Kuryr"
+			},
+			expected: []string{"networking.networkType: Kuryr runs on the OpenStack Neutron networks, not on the aws platform, use OpenShiftSDN or OVNKubernetes"},
+		},
+		{
+			name: "third-party network type",
+			edit: func(c *types.InstallConfig) {
+				c.Networking.NetworkType = "Calico"
+			},
+			expected: []string{"networking.networkType: Calico is not deployed by the cluster network operator, its manifests must be added to the manifests of the cluster"},
+		},
+		{
+			name: "tiny kubevirt worker memory",
+			edit: func(c *types.InstallConfig) {
+				lintKubevirtPlatform(c)
+				c.Compute[0].Platform.Kubevirt.Memory = "4G"
+			},
+			expected: []string{"compute[0].platform.kubevirt.memory: the 4G of memory of the VMs is less than the 8Gi the workloads of the cluster need on the compute nodes"},
+		},
+		{
+			name: "tiny kubevirt worker memory without replicas",
+			edit: func(c *types.InstallConfig) {
+				lintKubevirtPlatform(c)
+				c.Compute[0].Platform.Kubevirt.Memory = "4G"
+				c.Compute[0].Replicas = pointer.Int64Ptr(0)
+			},
+		},
+		{
+			name: "http proxy only",
+			edit: func(c *types.InstallConfig) {
+				c.Proxy.HTTPSProxy = ""
+			},
+			expected: []string{"proxy.httpsProxy: the HTTPS requests of the cluster, most of its requests, are not proxied without an httpsProxy"},
+		},
+		{
+			name: "noProxy missing the cluster domain",
+			edit: func(c *types.InstallConfig) {
+				c.Proxy.NoProxy = "example.com, 172.30.0.0/16"
+			},
+			expected: []string{"proxy.noProxy: the routes of the cluster are reached through the proxy, add .test-cluster.test-domain to the noProxy entries"},
+		},
+		{
+			name: "noProxy wildcard",
+			edit: func(c *types.InstallConfig) {
+				c.Proxy.NoProxy = "*"
+			},
+		},
+		{
+			name: "noProxy cluster domain",
+			edit: func(c *types.InstallConfig) {
+				c.Proxy.NoProxy = "test-cluster.test-domain"
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := lintInstallConfig()
+			if tc.edit != nil {
+				tc.edit(c)
+			}
+			var warnings []string
+			for _, warning := range Lint(c) {
+				warnings = append(warnings, warning.String())
+			}
+			assert.Equal(t, tc.expected, warnings)
+		})
+	}
+}
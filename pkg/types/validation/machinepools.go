@@ -1,8 +1,16 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
 
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	ignvalidate "github.com/coreos/ignition/v2/config/validate"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/types"
@@ -69,10 +77,151 @@ func ValidateMachinePool(platform *types.Platform, p *types.MachinePool, fldPath
 	if !validArchitectures[p.Architecture] {
 		allErrs = append(allErrs, field.NotSupported(fldPath.Child("architecture"), p.Architecture, validArchitectureValues))
 	}
+	for i, fragment := range p.IgnitionFragments {
+		if err := validateIgnitionFragment(fragment); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ignitionFragments").Index(i), fragment, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, validateKernelArguments(p, fldPath.Child("kernelArguments"))...)
+	if p.KubeletConfig != nil {
+		allErrs = append(allErrs, validateKubeletConfig(p.KubeletConfig, fldPath.Child("kubeletConfig"))...)
+	}
+	if p.ContainerRuntimeConfig != nil {
+		allErrs = append(allErrs, validateContainerRuntimeConfig(p.ContainerRuntimeConfig, fldPath.Child("containerRuntimeConfig"))...)
+	}
 	allErrs = append(allErrs, validateMachinePoolPlatform(platform, &p.Platform, p, fldPath.Child("platform"))...)
 	return allErrs
 }
 
+// repeatableKernelArguments are the kernel arguments which may be set several times with
+// different values, e.g. to reserve hugepages of several sizes.
+var repeatableKernelArguments = map[string]bool{
+	"console":    true,
+	"hugepages":  true,
+	"hugepagesz": true,
+}
+
+// validateKernelArguments checks that the kernel arguments are single arguments, and that
+// they don't conflict with each other nor with the other fields of the pool.
+func validateKernelArguments(p *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	values := map[string]string{}
+	for i, arg := range p.KernelArguments {
+		switch {
+		case arg == "":
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg, "kernel argument must not be empty"))
+			continue
+		case strings.IndexFunc(arg, unicode.IsSpace) >= 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg, "kernel argument must not contain whitespace, set each argument separately"))
+			continue
+		case arg == "nosmt":
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg, "set hyperthreading to Disabled instead"))
+			continue
+		}
+		key := strings.SplitN(arg, "=", 2)[0]
+		value, seen := values[key]
+		switch {
+		case !seen:
+			values[key] = arg
+		case value == arg:
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), arg))
+		case !repeatableKernelArguments[key]:
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg, fmt.Sprintf("conflicts with kernel argument %s", value)))
+		}
+	}
+	return allErrs
+}
+
+// systemReservedResources are the resources the kubelet may reserve for the system daemons.
+var systemReservedResources = map[string]bool{
+	"cpu":               true,
+	"memory":            true,
+	"ephemeral-storage": true,
+	"pid":               true,
+}
+
+// minLogSizeMax is the minimum size of the container logs, matching the read buffer of conmon.
+const minLogSizeMax = 8192
+
+// validateKubeletConfig checks the configuration of the kubelet of the pool.
+func validateKubeletConfig(c *types.KubeletConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.MaxPods != nil && *c.MaxPods < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), *c.MaxPods, "must be positive"))
+	}
+	names := make([]string, 0, len(systemReservedResources))
+	for name := range systemReservedResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for name, value := range c.SystemReserved {
+		reservedPath := fldPath.Child("systemReserved").Key(name)
+		if !systemReservedResources[name] {
+			allErrs = append(allErrs, field.NotSupported(reservedPath, name, names))
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(reservedPath, value, err.Error()))
+		case quantity.Sign() < 0:
+			allErrs = append(allErrs, field.Invalid(reservedPath, value, "must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+// validateContainerRuntimeConfig checks the configuration of the container runtime of the pool.
+func validateContainerRuntimeConfig(c *types.ContainerRuntimeConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.PidsLimit != nil && *c.PidsLimit < 20 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pidsLimit"), *c.PidsLimit, "must be at least 20"))
+	}
+	if c.LogSizeMax != "" {
+		quantity, err := resource.ParseQuantity(c.LogSizeMax)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("logSizeMax"), c.LogSizeMax, err.Error()))
+		case quantity.Sign() > 0 && quantity.Value() < minLogSizeMax:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("logSizeMax"), c.LogSizeMax, "must be at least 8Ki, or negative for no limit"))
+		}
+	}
+	return allErrs
+}
+
+// validateIgnitionFragment checks that the fragment is an Ignition config of a spec version
+// compatible with the pointer Ignition configs, which only sets their appended sections.
+func validateIgnitionFragment(fragment string) error {
+	config := &igntypes.Config{}
+	if err := json.Unmarshal([]byte(fragment), config); err != nil {
+		return fmt.Errorf("invalid Ignition config: %v", err)
+	}
+	version, err := config.Ignition.Semver()
+	if err != nil {
+		return fmt.Errorf("invalid Ignition spec version %q: %v", config.Ignition.Version, err)
+	}
+	if version.Major != igntypes.MaxVersion.Major || version.Minor > igntypes.MaxVersion.Minor {
+		return fmt.Errorf("unsupported Ignition spec version %s, must be between %d.0.0 and %s", version, igntypes.MaxVersion.Major, igntypes.MaxVersion.String())
+	}
+	if !reflect.DeepEqual(config.Ignition, igntypes.Ignition{Version: config.Ignition.Version}) {
+		return fmt.Errorf("the ignition section can only set the version, it is set by the pointer Ignition config")
+	}
+	if len(config.Storage.Disks) > 0 || len(config.Storage.Filesystems) > 0 || len(config.Storage.Raid) > 0 {
+		return fmt.Errorf("disks, filesystems and raid arrays are not supported, only files, directories, links, systemd units and users")
+	}
+
+	// The 3.0 configs are valid 3.1 configs
+	config.Ignition.Version = igntypes.MaxVersion.String()
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if report := ignvalidate.ValidateWithContext(config, data); report.IsFatal() {
+		return fmt.Errorf("invalid Ignition config: %s", report.String())
+	}
+	return nil
+}
+
 func validateMachinePoolPlatform(platform *types.Platform, p *types.MachinePoolPlatform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	platformName := platform.Name()
@@ -57,6 +57,220 @@ func TestValidateMachinePool(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name:     "valid kernel arguments",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{"intel_iommu=on", "hugepagesz=1G", "hugepages=16", "hugepagesz=2M", "hugepages=512"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "kernel argument with whitespace",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{"intel_iommu=on iommu=pt"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "empty kernel argument",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{""}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "duplicate kernel arguments",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{"iommu=pt", "iommu=pt"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "conflicting kernel arguments",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{"intel_iommu=on", "intel_iommu=off"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "valid kubelet and container runtime configs",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KubeletConfig = &types.KubeletConfig{
+					MaxPods:        pointer.Int32Ptr(500),
+					SystemReserved: map[string]string{"cpu": "500m", "memory": "1Gi"},
+				}
+				p.ContainerRuntimeConfig = &types.ContainerRuntimeConfig{PidsLimit: pointer.Int64Ptr(4096), LogSizeMax: "50Mi"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "unlimited container log size",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.ContainerRuntimeConfig = &types.ContainerRuntimeConfig{LogSizeMax: "-1"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "zero max pods",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KubeletConfig = &types.KubeletConfig{MaxPods: pointer.Int32Ptr(0)}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "unsupported system reserved resource",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KubeletConfig = &types.KubeletConfig{SystemReserved: map[string]string{"gpu": "1"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid system reserved quantity",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KubeletConfig = &types.KubeletConfig{SystemReserved: map[string]string{"memory": "1 gigabyte"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "low pids limit",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.ContainerRuntimeConfig = &types.ContainerRuntimeConfig{PidsLimit: pointer.Int64Ptr(10)}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "small container log size",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.ContainerRuntimeConfig = &types.ContainerRuntimeConfig{LogSizeMax: "4Ki"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "nosmt kernel argument",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.KernelArguments = []string{"nosmt"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "valid ignition fragment 3.1.0",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.1.0"},"systemd":{"units":[{"name":"custom.service","enabled":true,"contents":"[Service]\nExecStart=/bin/true\n"}]}}`}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "valid ignition fragment 3.0.0",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.0.0"},"storage":{"files":[{"path":"/etc/custom.conf","contents":{"source":"data:,custom"}}]}}`}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "invalid ignition fragment json",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid ignition fragment 2.2.0",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"2.2.0"}}`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid ignition fragment 3.2.0",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.2.0"}}`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid ignition fragment config merge",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.1.0","config":{"merge":[{"source":"https://example.com/config"}]}}}`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid ignition fragment disks",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.1.0"},"storage":{"disks":[{"device":"/dev/sdb"}]}}`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "invalid ignition fragment relative path",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.IgnitionFragments = []string{`{"ignition":{"version":"3.1.0"},"storage":{"files":[{"path":"etc/custom.conf"}]}}`}
+				return p
+			}(),
+			valid: false,
+		},
 		{
 			name:     "valid aws",
 			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
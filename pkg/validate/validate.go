@@ -3,6 +3,7 @@ package validate
 
 import (
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -91,15 +92,71 @@ func ImagePullSecret(secret string) error {
 	}
 	errs := []error{}
 	for d, a := range s.Auths {
-		_, authPresent := a["auth"]
+		auth, authPresent := a["auth"]
 		_, credsStorePresnet := a["credsStore"]
 		if !authPresent && !credsStorePresnet {
 			errs = append(errs, fmt.Errorf("%q requires either auth or credsStore", d))
 		}
+		if authPresent {
+			if err := imagePullSecretAuth(auth); err != nil {
+				errs = append(errs, fmt.Errorf("invalid auth for %q: %v", d, err))
+			}
+		}
 	}
 	return k8serrors.NewAggregate(errs)
 }
 
+// imagePullSecretAuth checks that the auth of an image pull secret entry is the base64 encoding
+// of "user:password".
+func imagePullSecretAuth(auth interface{}) error {
+	encoded, ok := auth.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("must be base64-encoded: %v", err)
+	}
+	if !strings.Contains(string(decoded), ":") {
+		return fmt.Errorf("must be the base64 encoding of user:password")
+	}
+	return nil
+}
+
+// ImagePullSecretRegistries checks that the given image pull secret has credentials for each of
+// the repositories (e.g. quay.io/openshift-release-dev/ocp-release), and returns an error listing
+// the ones without credentials.
+func ImagePullSecretRegistries(secret string, repositories []string) error {
+	var s imagePullSecret
+	if err := json.Unmarshal([]byte(secret), &s); err != nil {
+		return err
+	}
+	var uncovered []string
+	for _, repository := range repositories {
+		covered := false
+		for key := range s.Auths {
+			if ImagePullSecretCovers(key, repository) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, repository)
+		}
+	}
+	if len(uncovered) > 0 {
+		return fmt.Errorf("no credentials for %s", strings.Join(uncovered, ", "))
+	}
+	return nil
+}
+
+// ImagePullSecretCovers returns whether the credentials of the image pull secret entry with the
+// given key, a registry host optionally followed by a namespace, apply to the repository.
+func ImagePullSecretCovers(key string, repository string) bool {
+	key = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://"), "/")
+	return repository == key || strings.HasPrefix(repository, key+"/")
+}
+
 // ClusterName1035 checks the provided cluster name matches RFC1035 naming requirements.
 // Some platform resource names must comply with RFC1035 "[a-z]([-a-z0-9]*[a-z0-9])?". They
 // are based on the InfraID, which is a truncated version of the cluster name where all non-
@@ -253,7 +253,7 @@ func TestImagePullSecret(t *testing.T) {
 	}{
 		{
 			name:   "single entry with auth",
-			secret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+			secret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
 			valid:  true,
 		},
 		{
@@ -268,7 +268,7 @@ func TestImagePullSecret(t *testing.T) {
 		},
 		{
 			name:   "no auths",
-			secret: `{"not-auths":{"example.com":{"auth":"authorization value"}}}`,
+			secret: `{"not-auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
 			valid:  false,
 		},
 		{
@@ -278,7 +278,7 @@ func TestImagePullSecret(t *testing.T) {
 		},
 		{
 			name:   "additional fields",
-			secret: `{"auths":{"example.com":{"auth":"authorization value","other-field":"other field value"}}}`,
+			secret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA==","other-field":"other field value"}}}`,
 			valid:  true,
 		},
 		{
@@ -288,12 +288,27 @@ func TestImagePullSecret(t *testing.T) {
 		},
 		{
 			name:   "multiple valid entries",
-			secret: `{"auths":{"example.com":{"auth":"authorization value"},"other-example.com":{"auth":"other auth value"}}}`,
+			secret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="},"other-example.com":{"auth":"b3RoZXI6cGFzc3dvcmQ="}}}`,
 			valid:  true,
 		},
+		{
+			name:   "auth not base64",
+			secret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+			valid:  false,
+		},
+		{
+			name:   "auth without user",
+			secret: `{"auths":{"example.com":{"auth":"cGFzc3dvcmQ="}}}`,
+			valid:  false,
+		},
+		{
+			name:   "auth not a string",
+			secret: `{"auths":{"example.com":{"auth":12}}}`,
+			valid:  false,
+		},
 		{
 			name:   "mix of valid and invalid entries",
-			secret: `{"auths":{"example.com":{"auth":"authorization value"},"other-example.com":{"unrequired-field":"value"}}}`,
+			secret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNzd29yZA=="},"other-example.com":{"unrequired-field":"value"}}}`,
 			valid:  false,
 		},
 	}
@@ -309,6 +324,44 @@ func TestImagePullSecret(t *testing.T) {
 	}
 }
 
+func TestImagePullSecretRegistries(t *testing.T) {
+	secret := `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNzd29yZA=="},"https://mirror.example.com:5000/ocp/":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`
+	cases := []struct {
+		name          string
+		repositories  []string
+		expectedError string
+	}{
+		{
+			name:         "registry",
+			repositories: []string{"quay.io/openshift-release-dev/ocp-release"},
+		},
+		{
+			name:         "namespace",
+			repositories: []string{"mirror.example.com:5000/ocp/release"},
+		},
+		{
+			name:          "other namespace",
+			repositories:  []string{"mirror.example.com:5000/other/release"},
+			expectedError: "no credentials for mirror.example.com:5000/other/release",
+		},
+		{
+			name:          "other registries",
+			repositories:  []string{"quay.io/openshift-release-dev/ocp-release", "registry.example.com/release", "quay.io.example.com/release"},
+			expectedError: "no credentials for registry.example.com/release, quay.io.example.com/release",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ImagePullSecretRegistries(secret, tc.repositories)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
 const invalidFormatCertificate = `-----INVALID FORMAT-----
 MIIF2zCCA8OgAwIBAgICEAAwDQYJKoZIhvcNAQELBQAwgYExCzAJBgNVBAYTAlVT
 MRcwFQYDVQQIDA5Ob3J0aCBDYXJvbGluYTEQMA4GA1UEBwwHUmFsZWlnaDEUMBIG